@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+)
+
+// expandAlias replaces the subcommand in the given command line arguments
+// with the command and arguments of the alias of the same name from the CLI
+// configuration, if there is one, keeping any global options before it and
+// any other arguments after it.
+//
+// An alias can't replace a built-in command, so if the subcommand is both
+// then expandAlias leaves the arguments unchanged and returns a warning
+// message to show.
+func expandAlias(args []string, aliases map[string]*cliconfig.ConfigAlias, commands map[string]cli.CommandFactory) ([]string, string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			// Global options come before the subcommand.
+			continue
+		}
+		alias, ok := aliases[arg]
+		if !ok {
+			return args, ""
+		}
+		if _, builtIn := commands[arg]; builtIn {
+			return args, fmt.Sprintf("The alias %q in the CLI configuration has no effect, because %q is a built-in command.", arg, arg)
+		}
+
+		command := strings.Fields(alias.Command)
+		log.Printf("[INFO] Expanding alias %q to %q with arguments %q", arg, strings.Join(command, " "), alias.Args)
+		ret := make([]string, 0, len(args)+len(command)+len(alias.Args))
+		ret = append(ret, args[:i]...)
+		ret = append(ret, command...)
+		ret = append(ret, alias.Args...)
+		ret = append(ret, args[i+1:]...)
+		return ret, ""
+	}
+	return args, ""
+}
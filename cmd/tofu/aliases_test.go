@@ -0,0 +1,85 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+)
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]*cliconfig.ConfigAlias{
+		"preview": {
+			Command: "plan",
+			Args:    []string{"-refresh=false", "-var-file=dev.tfvars"},
+		},
+		"resources": {
+			Command: "state list",
+		},
+		"plan": {
+			Command: "apply",
+		},
+	}
+	commands := map[string]cli.CommandFactory{
+		"plan":       nil,
+		"state list": nil,
+	}
+
+	tests := []struct {
+		args        []string
+		want        []string
+		wantWarning bool
+	}{
+		{
+			nil,
+			nil,
+			false,
+		},
+		{
+			[]string{"preview"},
+			[]string{"plan", "-refresh=false", "-var-file=dev.tfvars"},
+			false,
+		},
+		{
+			[]string{"-chdir=prod", "preview", "-target=foo.bar"},
+			[]string{"-chdir=prod", "plan", "-refresh=false", "-var-file=dev.tfvars", "-target=foo.bar"},
+			false,
+		},
+		{
+			[]string{"resources", "-state=foo.tfstate"},
+			[]string{"state", "list", "-state=foo.tfstate"},
+			false,
+		},
+		{
+			// Only the subcommand can be an alias.
+			[]string{"show", "preview"},
+			[]string{"show", "preview"},
+			false,
+		},
+		{
+			// Built-in commands take precedence over aliases.
+			[]string{"plan"},
+			[]string{"plan"},
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q", test.args), func(t *testing.T) {
+			got, warning := expandAlias(test.args, aliases, commands)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, test.want)
+			}
+			if gotWarning := warning != ""; gotWarning != test.wantWarning {
+				t.Errorf("wrong warning %q", warning)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/go-retryablehttp"
@@ -20,10 +22,15 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command"
 	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/costestimate"
+	"github.com/opentofu/opentofu/internal/command/regopolicy"
+	"github.com/opentofu/opentofu/internal/command/runhook"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/command/webbrowser"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	pluginDiscovery "github.com/opentofu/opentofu/internal/plugin/discovery"
 	"github.com/opentofu/opentofu/internal/terminal"
 )
@@ -56,6 +63,14 @@ var hiddenCommands map[string]struct{}
 // Ui is the cli.Ui used for communicating to the outside world.
 var Ui cli.Ui
 
+// commandView is the view shared by all of the commands, which records the
+// error code of any failure they report.
+var commandView *views.View
+
+// lockNotifier is the notifier shared by all of the commands for state lock
+// events, or nil if there are no lock webhooks.
+var lockNotifier clistate.LockNotifier
+
 func initCommands(
 	ctx context.Context,
 	originalWorkingDir string,
@@ -89,10 +104,13 @@ func initCommands(
 
 	wd := workingDir(originalWorkingDir, os.Getenv("TF_DATA_DIR"))
 
+	commandView = views.NewView(streams).SetRunningInAutomation(inAutomation)
+	lockNotifier = lockNotifierFromConfig(config)
+
 	meta := command.Meta{
 		WorkingDir: wd,
 		Streams:    streams,
-		View:       views.NewView(streams).SetRunningInAutomation(inAutomation),
+		View:       commandView,
 
 		Color:            true,
 		GlobalPluginDirs: globalPluginDirs(),
@@ -106,6 +124,18 @@ func initCommands(
 		PluginCacheDir:      config.PluginCacheDir,
 
 		PluginCacheMayBreakDependencyLockFile: config.PluginCacheMayBreakDependencyLockFile,
+		ProviderSchemaCache:                   config.ProviderSchemaCache,
+
+		LockQueueTimeout: lockQueueTimeoutFromConfig(config),
+		LockNotifier:     lockNotifier,
+		DriftWebhooks:    driftWebhooksFromConfig(config),
+
+		PlanSigning:        planSigningFromConfig(config),
+		RequireSignedPlans: len(config.PlanSigning) != 0 && config.PlanSigning[0].RequireSignature,
+
+		CostEstimator: costEstimatorFromConfig(config),
+		RunHooks:      runHooksFromConfig(config),
+		RegoPolicies:  regoPoliciesFromConfig(config),
 
 		ShutdownCh:    makeShutdownCh(),
 		CallerContext: ctx,
@@ -150,6 +180,12 @@ func initCommands(
 			}, nil
 		},
 
+		"drift": func() (cli.Command, error) {
+			return &command.DriftCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"env": func() (cli.Command, error) {
 			return &command.WorkspaceCommand{
 				Meta:       meta,
@@ -245,12 +281,30 @@ func initCommands(
 			}, nil
 		},
 
+		"perf": func() (cli.Command, error) {
+			return &command.PerfCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"perf report": func() (cli.Command, error) {
+			return &command.PerfReportCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"plan": func() (cli.Command, error) {
 			return &command.PlanCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"plan compare": func() (cli.Command, error) {
+			return &command.PlanCompareCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers": func() (cli.Command, error) {
 			return &command.ProvidersCommand{
 				Meta: meta,
@@ -372,6 +426,32 @@ func initCommands(
 			}, nil
 		},
 
+		"locks": func() (cli.Command, error) {
+			return &command.LocksCommand{}, nil
+		},
+
+		"locks list": func() (cli.Command, error) {
+			return &command.LocksListCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"stacks": func() (cli.Command, error) {
+			return &command.StacksCommand{}, nil
+		},
+
+		"stacks apply": func() (cli.Command, error) {
+			return &command.StacksApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"stacks plan": func() (cli.Command, error) {
+			return &command.StacksPlanCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state": func() (cli.Command, error) {
 			return &command.StateCommand{}, nil
 		},
@@ -502,3 +582,142 @@ func getAliasCommandKeys() []string {
 	}
 	return keys
 }
+
+// lockQueueTimeoutFromConfig returns the lock queue timeout from the given
+// CLI configuration, or zero if it isn't set or isn't valid. Invalid values
+// have already been reported by the configuration's validation.
+func lockQueueTimeoutFromConfig(config *cliconfig.Config) time.Duration {
+	d, err := config.LockQueueDuration()
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// lockNotifierFromConfig returns a notifier for the lock webhooks in the
+// given CLI configuration, or nil if there are none.
+func lockNotifierFromConfig(config *cliconfig.Config) clistate.LockNotifier {
+	names := make([]string, 0, len(config.LockWebhooks))
+	for name := range config.LockWebhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hooks := make([]clistate.LockWebhook, 0, len(names))
+	for _, name := range names {
+		hook := config.LockWebhooks[name]
+		events := make([]clistate.LockEventType, len(hook.Events))
+		for i, event := range hook.Events {
+			events[i] = clistate.LockEventType(event)
+		}
+		hooks = append(hooks, clistate.LockWebhook{
+			URL:     hook.URL,
+			Events:  events,
+			Headers: hook.Headers,
+		})
+	}
+	return clistate.NewWebhookNotifier(hooks)
+}
+
+// driftWebhooksFromConfig returns the drift webhooks in the given CLI
+// configuration, in order of their names.
+func driftWebhooksFromConfig(config *cliconfig.Config) []command.DriftWebhook {
+	names := make([]string, 0, len(config.DriftWebhooks))
+	for name := range config.DriftWebhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hooks := make([]command.DriftWebhook, 0, len(names))
+	for _, name := range names {
+		hook := config.DriftWebhooks[name]
+		hooks = append(hooks, command.DriftWebhook{
+			URL:     hook.URL,
+			Headers: hook.Headers,
+		})
+	}
+	return hooks
+}
+
+// regoPoliciesFromConfig returns the Rego policies in the given CLI
+// configuration, in order of their names.
+func regoPoliciesFromConfig(config *cliconfig.Config) []*regopolicy.Policy {
+	names := make([]string, 0, len(config.RegoPolicies))
+	for name := range config.RegoPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policies := make([]*regopolicy.Policy, 0, len(names))
+	for _, name := range names {
+		policy := config.RegoPolicies[name]
+		policies = append(policies, &regopolicy.Policy{
+			Name:              name,
+			Paths:             policy.Paths,
+			Query:             policy.Query,
+			Program:           policy.Program,
+			OverrideTokenHash: policy.OverrideTokenHash,
+		})
+	}
+	return policies
+}
+
+// runHooksFromConfig returns the run hooks in the given CLI configuration,
+// in order of their names, which is the order they run in.
+func runHooksFromConfig(config *cliconfig.Config) runhook.Hooks {
+	names := make([]string, 0, len(config.RunHooks))
+	for name := range config.RunHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hooks := make(runhook.Hooks, 0, len(names))
+	for _, name := range names {
+		hook := config.RunHooks[name]
+		events := make([]runhook.Event, len(hook.Events))
+		for i, event := range hook.Events {
+			events[i] = runhook.Event(event)
+		}
+		hooks = append(hooks, runhook.Hook{
+			Name:    name,
+			Program: hook.Program,
+			Args:    hook.Args,
+			Events:  events,
+		})
+	}
+	return hooks
+}
+
+// costEstimatorFromConfig returns the cost estimator from the cost_estimator
+// block in the given CLI configuration, or nil if there is none.
+func costEstimatorFromConfig(config *cliconfig.Config) *costestimate.Estimator {
+	for name, estimator := range config.CostEstimators {
+		// The configuration is validated to have at most one of these, with
+		// either a program or a price sheet.
+		ret := &costestimate.Estimator{Name: name}
+		if estimator.PriceSheet != "" {
+			ret.Provider = &costestimate.PriceSheet{Path: estimator.PriceSheet}
+		} else {
+			ret.Provider = &costestimate.Program{
+				Program: estimator.Program,
+				Args:    estimator.Args,
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
+// planSigningFromConfig returns the plan signing keys from the plan_signing
+// block in the given CLI configuration, if any.
+func planSigningFromConfig(config *cliconfig.Config) planfile.SigningConfig {
+	if len(config.PlanSigning) == 0 {
+		return planfile.SigningConfig{}
+	}
+	signing := config.PlanSigning[0]
+	return planfile.SigningConfig{
+		SigningKeyFile:       signing.SigningKeyFile,
+		SigningKeyPassphrase: signing.SigningKeyPassphrase,
+		TrustedKeyFiles:      signing.TrustedKeyFiles,
+	}
+}
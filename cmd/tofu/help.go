@@ -63,6 +63,8 @@ Global options (use these before the subcommand, if any):
   -chdir=DIR    Switch to a different working directory before executing the
                 given subcommand.
   -help         Show this help output, or the help for a specified subcommand.
+  -offline      Fail instead of accessing registries, remote backends, or other
+                network services. Also enabled by setting TF_OFFLINE=1.
   -version      An alias for the "version" subcommand.
 `, listCommands(commands, primaryCommands, maxKeyLen), listCommands(commands, otherCommands, maxKeyLen))
 
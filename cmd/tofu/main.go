@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,11 +25,14 @@ import (
 	"github.com/mitchellh/colorstring"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command"
 	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/logging"
+	"github.com/opentofu/opentofu/internal/offline"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tracing"
 	"github.com/opentofu/opentofu/version"
 
@@ -256,6 +260,21 @@ func realMain() int {
 		}
 	}
 
+	// The arguments can also include an -offline option, which has the same
+	// effect as the TF_OFFLINE environment variable. Enabling offline mode
+	// here is early enough even though we've already created some HTTP
+	// clients above, because they check it for each request.
+	offlineOpt, args := extractOfflineOption(args)
+	offlineEnv, err := offlineFromEnv()
+	if err != nil {
+		Ui.Error(err.Error())
+		return 1
+	}
+	if offlineOpt || offlineEnv {
+		log.Printf("[INFO] OpenTofu is running in offline mode")
+		offline.Enable(true)
+	}
+
 	// In tests, Commands may already be set to provide mock commands
 	if commands == nil {
 		// Commands get to hold on to the original working directory here,
@@ -276,6 +295,13 @@ func realMain() int {
 	// Make sure we clean up any managed plugins at the end of this
 	defer plugin.CleanupClients()
 
+	// The subcommand can be an alias from the CLI configuration, which
+	// stands for a built-in command with some arguments.
+	args, aliasWarning := expandAlias(args, config.Aliases, commands)
+	if aliasWarning != "" {
+		Ui.Warn(aliasWarning)
+	}
+
 	// Build the CLI so far, we do this so we can query the subcommand.
 	cliRunner := &cli.CLI{
 		Args:       args,
@@ -359,6 +385,13 @@ func realMain() int {
 	}
 
 	exitCode, err := cliRunner.Run()
+
+	// Lock events are delivered in the background, so we give any that are
+	// still on their way a chance to arrive before exiting.
+	if lockNotifier != nil {
+		lockNotifier.Flush(5 * time.Second)
+	}
+
 	if err != nil {
 		Ui.Error(fmt.Sprintf("Error executing CLI: %s", err.Error()))
 		return 1
@@ -367,9 +400,20 @@ func realMain() int {
 	// if we are exiting with a non-zero code, check if it was caused by any
 	// plugins crashing
 	if exitCode != 0 {
-		for _, panicLog := range logging.PluginPanics() {
+		panics := logging.PluginPanics()
+		for _, panicLog := range panics {
 			Ui.Error(panicLog)
 		}
+
+		// A general failure gets a more specific exit code if the command
+		// reported an error with a stable error code, or if a plugin crashed.
+		if exitCode == 1 {
+			code := commandView.ErrorCode()
+			if code == "" && len(panics) != 0 {
+				code = tfdiags.ErrorCodeProviderCrashed
+			}
+			exitCode = command.ExitCodeForError(code)
+		}
 	}
 
 	return exitCode
@@ -535,3 +579,36 @@ func mkConfigDir(configDir string) error {
 
 	return err
 }
+
+// extractOfflineOption returns whether the given arguments include the
+// -offline global option, along with the arguments without it.
+//
+// Like -chdir, the -offline option must appear before the subcommand.
+func extractOfflineOption(args []string) (bool, []string) {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if arg == "-offline" {
+			newArgs := make([]string, 0, len(args)-1)
+			newArgs = append(newArgs, args[:i]...)
+			newArgs = append(newArgs, args[i+1:]...)
+			return true, newArgs
+		}
+	}
+	return false, args
+}
+
+// offlineFromEnv returns whether the TF_OFFLINE environment variable enables
+// offline mode.
+func offlineFromEnv() (bool, error) {
+	v := os.Getenv(offline.EnvVar)
+	if v == "" {
+		return false, nil
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("Invalid value for %s: must be 1 to enable offline mode, or 0 to disable it", offline.EnvVar)
+	}
+	return on, nil
+}
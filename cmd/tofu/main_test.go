@@ -367,3 +367,47 @@ func TestMkConfigDir_noparent(t *testing.T) {
 		t.Fatalf("Expected error: %s, but got: %v", expectedError, err)
 	}
 }
+
+func TestExtractOfflineOption(t *testing.T) {
+	tests := []struct {
+		args        []string
+		wantOffline bool
+		wantArgs    []string
+	}{
+		{nil, false, nil},
+		{[]string{"plan"}, false, []string{"plan"}},
+		{[]string{"-offline", "plan"}, true, []string{"plan"}},
+		{[]string{"-chdir=foo", "-offline", "init", "-upgrade"}, true, []string{"-chdir=foo", "init", "-upgrade"}},
+		// The option only counts if it comes before the subcommand.
+		{[]string{"plan", "-offline"}, false, []string{"plan", "-offline"}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q", test.args), func(t *testing.T) {
+			gotOffline, gotArgs := extractOfflineOption(test.args)
+			if gotOffline != test.wantOffline {
+				t.Errorf("wrong offline %t; want %t", gotOffline, test.wantOffline)
+			}
+			if !reflect.DeepEqual(gotArgs, test.wantArgs) {
+				t.Errorf("wrong args %q; want %q", gotArgs, test.wantArgs)
+			}
+		})
+	}
+}
+
+func TestOfflineFromEnv(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "1": true, "true": true, "0": false} {
+		t.Setenv("TF_OFFLINE", value)
+		got, err := offlineFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", value, err)
+		}
+		if got != want {
+			t.Errorf("wrong result %t for %q; want %t", got, value, want)
+		}
+	}
+
+	t.Setenv("TF_OFFLINE", "yes please")
+	if _, err := offlineFromEnv(); err == nil {
+		t.Errorf("unexpected success for invalid value")
+	}
+}
@@ -108,7 +108,13 @@ func parseModuleInstancePrefix(traversal hcl.Traversal) (ModuleInstance, hcl.Tra
 
 		// Check for optional module instance key
 		if len(remain) > 0 {
-			if idx, ok := remain[0].(hcl.TraverseIndex); ok {
+			if _, ok := remain[0].(hcl.TraverseSplat); ok {
+				// A splat operator can appear only in the traversals that
+				// the configs package builds for the endpoints of "moved"
+				// blocks, where it's a wildcard for any instance key.
+				remain = remain[1:]
+				step.InstanceKey = anyKey
+			} else if idx, ok := remain[0].(hcl.TraverseIndex); ok {
 				remain = remain[1:]
 
 				switch idx.Key.Type() {
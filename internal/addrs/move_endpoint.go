@@ -71,6 +71,16 @@ func (e *MoveEndpoint) Equal(other *MoveEndpoint) bool {
 	}
 }
 
+// WildcardCount returns the number of [*] wildcards that the endpoint uses in
+// place of instance keys.
+//
+// The "from" and "to" endpoints of a moved block must have the same number of
+// wildcards, because each wildcard in "to" takes the instance key matched by
+// the corresponding wildcard in "from".
+func (e *MoveEndpoint) WildcardCount() int {
+	return moveableWildcardCount(e.relSubject)
+}
+
 // MightUnifyWith returns true if it is possible that a later call to
 // UnifyMoveEndpoints might succeed if given the receiver and the other
 // given endpoint.
@@ -35,6 +35,35 @@ func (k anyKeyImpl) Value() cty.Value {
 // anyKey is the only valid value of anyKeyImpl
 var anyKey = anyKeyImpl('*')
 
+// moveableWildcardCount returns the number of steps in the given address that
+// have anyKey as their instance key, which in the endpoints of a "moved" block
+// means that the configuration used the [*] wildcard in place of a key.
+func moveableWildcardCount(addr AbsMoveable) int {
+	var mod ModuleInstance
+	count := 0
+	switch addr := addr.(type) {
+	case ModuleInstance:
+		mod = addr
+	case AbsModuleCall:
+		mod = addr.Module
+	case AbsResource:
+		mod = addr.Module
+	case AbsResourceInstance:
+		mod = addr.Module
+		if addr.Resource.Key == anyKey {
+			count++
+		}
+	default:
+		panic(fmt.Sprintf("unsupported address type %T", addr))
+	}
+	for _, step := range mod {
+		if step.InstanceKey == anyKey {
+			count++
+		}
+	}
+	return count
+}
+
 // MoveEndpointInModule annotates a MoveEndpoint with the address of the
 // module where it was declared, which is the form we use for resolving
 // whether move statements chain from or are nested within other move
@@ -180,6 +209,152 @@ func (e *MoveEndpointInModule) InModuleInstance(modInst ModuleInstance) AbsMovea
 	}
 }
 
+// HasWildcards returns true if the receiver uses the [*] wildcard in place of
+// any instance keys. An endpoint with wildcards is a pattern that must be
+// made specific using WithWildcardKeys before it can be used to move objects.
+func (e *MoveEndpointInModule) HasWildcards() bool {
+	return moveableWildcardCount(e.relSubject) > 0
+}
+
+// MatchWildcards decides whether the given object matches the receiver,
+// treating each of its wildcards as matching any instance key, and if so
+// returns the instance keys that each of the wildcards matched, in order.
+//
+// For a module endpoint the given address must be a module instance, which
+// matches if the endpoint selects either that module instance or one of its
+// ancestors. For a resource endpoint the given address must be a resource
+// instance.
+func (e *MoveEndpointInModule) MatchWildcards(addr AbsMoveable) ([]InstanceKey, bool) {
+	var mod ModuleInstance
+	switch addr := addr.(type) {
+	case ModuleInstance:
+		mod = addr
+	case AbsResourceInstance:
+		mod = addr.Module
+	default:
+		return nil, false
+	}
+	_, mRel, match := e.matchModuleInstancePrefix(mod)
+	if !match {
+		return nil, false
+	}
+
+	var keys []InstanceKey
+	matchSteps := func(steps ModuleInstance) bool {
+		if len(steps) > len(mRel) {
+			return false
+		}
+		for i, step := range steps {
+			switch {
+			case step.Name != mRel[i].Name:
+				return false
+			case step.InstanceKey == anyKey:
+				keys = append(keys, mRel[i].InstanceKey)
+			case step.InstanceKey != mRel[i].InstanceKey:
+				return false
+			}
+		}
+		return true
+	}
+
+	switch relSubject := e.relSubject.(type) {
+	case ModuleInstance:
+		if _, ok := addr.(ModuleInstance); !ok || !matchSteps(relSubject) {
+			return nil, false
+		}
+	case AbsModuleCall:
+		if _, ok := addr.(ModuleInstance); !ok || !matchSteps(relSubject.Module) {
+			return nil, false
+		}
+		if len(mRel) <= len(relSubject.Module) || mRel[len(relSubject.Module)].Name != relSubject.Call.Name {
+			return nil, false
+		}
+	case AbsResource:
+		riAddr, ok := addr.(AbsResourceInstance)
+		if !ok || len(mRel) != len(relSubject.Module) || !matchSteps(relSubject.Module) {
+			return nil, false
+		}
+		if !riAddr.Resource.Resource.Equal(relSubject.Resource) {
+			return nil, false
+		}
+	case AbsResourceInstance:
+		riAddr, ok := addr.(AbsResourceInstance)
+		if !ok || len(mRel) != len(relSubject.Module) || !matchSteps(relSubject.Module) {
+			return nil, false
+		}
+		if !riAddr.Resource.Resource.Equal(relSubject.Resource.Resource) {
+			return nil, false
+		}
+		switch relSubject.Resource.Key {
+		case anyKey:
+			keys = append(keys, riAddr.Resource.Key)
+		case riAddr.Resource.Key:
+			// The key matches exactly.
+		default:
+			return nil, false
+		}
+	default:
+		panic(fmt.Sprintf("unexpected move subject type %T", relSubject))
+	}
+	return keys, true
+}
+
+// WithWildcardKeys returns a copy of the receiver with each of its wildcards
+// replaced by the corresponding instance key from the given slice, which must
+// have one key for each wildcard.
+//
+// Keys returned by MatchWildcards for the "from" endpoint of a move statement
+// are suitable for making both of its endpoints specific.
+func (e *MoveEndpointInModule) WithWildcardKeys(keys []InstanceKey) *MoveEndpointInModule {
+	if len(keys) != moveableWildcardCount(e.relSubject) {
+		panic(fmt.Sprintf("%s needs %d wildcard keys, but got %d", e, moveableWildcardCount(e.relSubject), len(keys)))
+	}
+	replaceKey := func(key InstanceKey) InstanceKey {
+		if key != anyKey {
+			return key
+		}
+		key, keys = keys[0], keys[1:]
+		return key
+	}
+	replaceSteps := func(steps ModuleInstance) ModuleInstance {
+		if steps == nil {
+			return nil
+		}
+		ret := make(ModuleInstance, len(steps))
+		for i, step := range steps {
+			ret[i] = ModuleInstanceStep{Name: step.Name, InstanceKey: replaceKey(step.InstanceKey)}
+		}
+		return ret
+	}
+
+	var relSubject AbsMoveable
+	switch rs := e.relSubject.(type) {
+	case ModuleInstance:
+		relSubject = replaceSteps(rs)
+	case AbsModuleCall:
+		relSubject = AbsModuleCall{Module: replaceSteps(rs.Module), Call: rs.Call}
+	case AbsResource:
+		relSubject = AbsResource{Module: replaceSteps(rs.Module), Resource: rs.Resource}
+	case AbsResourceInstance:
+		module := replaceSteps(rs.Module)
+		relSubject = AbsResourceInstance{
+			Module: module,
+			Resource: ResourceInstance{
+				Resource: rs.Resource.Resource,
+				Key:      replaceKey(rs.Resource.Key),
+			},
+		}
+	default:
+		panic(fmt.Sprintf("unexpected move subject type %T", rs))
+	}
+
+	return &MoveEndpointInModule{
+		SourceRange: e.SourceRange,
+		module:      e.module,
+		relSubject:  relSubject,
+	}
+}
+
 // ModuleCallTraversals returns both the address of the module where the
 // receiver was declared and any other module calls it traverses through
 // while selecting a particular object to move.
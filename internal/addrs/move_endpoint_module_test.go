@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -1741,6 +1742,111 @@ func TestIsModuleMoveReIndex(t *testing.T) {
 	}
 }
 
+func TestMoveEndpointInModuleWildcards(t *testing.T) {
+	fooResource := Resource{Mode: ManagedResourceMode, Type: "test", Name: "foo"}
+
+	tests := []struct {
+		Endpoint   *MoveEndpointInModule
+		Addr       AbsMoveable
+		WantKeys   []InstanceKey
+		WantMatch  bool
+		WantString string
+	}{
+		{
+			Endpoint: &MoveEndpointInModule{
+				relSubject: AbsResource{
+					Module:   ModuleInstance{{Name: "old", InstanceKey: anyKey}},
+					Resource: fooResource,
+				},
+			},
+			Addr:       mustParseAbsResourceInstanceStr(`module.old["a"].test.foo[0]`),
+			WantKeys:   []InstanceKey{StringKey("a")},
+			WantMatch:  true,
+			WantString: `module.old["a"].test.foo[*]`,
+		},
+		{
+			Endpoint: &MoveEndpointInModule{
+				relSubject: AbsResource{
+					Module:   ModuleInstance{{Name: "old", InstanceKey: anyKey}},
+					Resource: fooResource,
+				},
+			},
+			Addr:      mustParseAbsResourceInstanceStr(`module.new["a"].test.foo`),
+			WantMatch: false,
+		},
+		{
+			Endpoint: &MoveEndpointInModule{
+				relSubject: AbsResource{
+					Module:   ModuleInstance{{Name: "old", InstanceKey: anyKey}},
+					Resource: fooResource,
+				},
+			},
+			Addr:      mustParseModuleInstanceStr(`module.old["a"]`),
+			WantMatch: false,
+		},
+		{
+			Endpoint: &MoveEndpointInModule{
+				module: mustParseModuleStr("module.parent"),
+				relSubject: AbsResourceInstance{
+					Resource: fooResource.Instance(anyKey),
+				},
+			},
+			Addr:       mustParseAbsResourceInstanceStr(`module.parent[0].test.foo["k"]`),
+			WantKeys:   []InstanceKey{StringKey("k")},
+			WantMatch:  true,
+			WantString: `module.parent[*].test.foo["k"]`,
+		},
+		{
+			Endpoint: &MoveEndpointInModule{
+				relSubject: ModuleInstance{
+					{Name: "a", InstanceKey: anyKey},
+					{Name: "b", InstanceKey: anyKey},
+				},
+			},
+			Addr:       mustParseModuleInstanceStr(`module.a[1].module.b["x"].module.c`),
+			WantKeys:   []InstanceKey{IntKey(1), StringKey("x")},
+			WantMatch:  true,
+			WantString: `module.a[1].module.b["x"]`,
+		},
+		{
+			Endpoint: &MoveEndpointInModule{
+				relSubject: ModuleInstance{
+					{Name: "a", InstanceKey: anyKey},
+					{Name: "b", InstanceKey: IntKey(0)},
+				},
+			},
+			Addr:      mustParseModuleInstanceStr(`module.a[1].module.b[1]`),
+			WantMatch: false,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%02d]%s.MatchWildcards(%s)", i, test.Endpoint, test.Addr), func(t *testing.T) {
+			if !test.Endpoint.HasWildcards() {
+				t.Fatal("endpoint has no wildcards")
+			}
+			gotKeys, gotMatch := test.Endpoint.MatchWildcards(test.Addr)
+			if gotMatch != test.WantMatch {
+				t.Fatalf("wrong match result\ngot:  %t\nwant: %t", gotMatch, test.WantMatch)
+			}
+			if !gotMatch {
+				return
+			}
+			if diff := cmp.Diff(test.WantKeys, gotKeys); diff != "" {
+				t.Errorf("wrong keys\n%s", diff)
+			}
+
+			got := test.Endpoint.WithWildcardKeys(gotKeys)
+			if got.HasWildcards() {
+				t.Errorf("result still has wildcards: %s", got)
+			}
+			if got, want := got.String(), test.WantString; got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
 func mustParseAbsResourceInstanceStr(s string) AbsResourceInstance {
 	r, diags := ParseAbsResourceInstanceStr(s)
 	if diags.HasErrors() {
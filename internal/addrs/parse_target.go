@@ -89,6 +89,11 @@ func parseResourceInstanceUnderModule(moduleAddr ModuleInstance, remain hcl.Trav
 	case 0:
 		return moduleAddr.ResourceInstance(mode, typeName, name, NoKey), diags
 	case 1:
+		if _, ok := remain[0].(hcl.TraverseSplat); ok {
+			// As in parseModuleInstancePrefix, this is a wildcard in the
+			// endpoint of a "moved" block.
+			return moduleAddr.ResourceInstance(mode, typeName, name, anyKey), diags
+		}
 		if tt, ok := remain[0].(hcl.TraverseIndex); ok {
 			key, err := ParseInstanceKey(tt.Key)
 			if err != nil {
@@ -39,11 +39,15 @@ type Function struct {
 const (
 	FunctionNamespaceProvider = "provider"
 	FunctionNamespaceCore     = "core"
+	FunctionNamespaceLocal    = "local"
+	FunctionNamespaceModule   = "module"
 )
 
 var FunctionNamespaces = []string{
 	FunctionNamespaceProvider,
 	FunctionNamespaceCore,
+	FunctionNamespaceLocal,
+	FunctionNamespaceModule,
 }
 
 func ParseFunction(input string) Function {
@@ -0,0 +1,130 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// ApplyCheckpointFilename is the name of the file, within the working
+// directory's data directory, where the progress of applying a saved plan is
+// recorded.
+const ApplyCheckpointFilename = "apply-checkpoint.json"
+
+// ApplyCheckpoint records the progress of applying a saved plan, so that an
+// apply that crashed or was cancelled part way through can be resumed later
+// without retrying the changes that had already been completed.
+//
+// A resource instance change is only ever recorded as completed once a state
+// snapshot reflecting its result has been persisted, so a checkpoint never
+// claims more progress than the persisted state does.
+type ApplyCheckpoint struct {
+	// Path is the file where the checkpoint is saved. It is not itself
+	// saved as part of the checkpoint.
+	Path string `json:"-"`
+
+	// PlanFile is the path of the saved plan file being applied.
+	PlanFile string `json:"plan_file"`
+
+	// Workspace is the workspace the plan was being applied to.
+	Workspace string `json:"workspace"`
+
+	// Completed and Pending are the addresses of the resource instance
+	// changes from the plan that have and have not yet been completed,
+	// respectively, as returned by ApplyCheckpointKey.
+	Completed []string `json:"completed"`
+	Pending   []string `json:"pending"`
+
+	// Lineage and Serial identify the last state snapshot that was persisted
+	// while applying the plan, or the state snapshot that the apply started
+	// from if it hasn't persisted any yet. An apply can only be resumed if
+	// the current state is still that snapshot, since otherwise another
+	// operation has changed the state in the meantime.
+	Lineage string `json:"lineage"`
+	Serial  uint64 `json:"serial"`
+
+	// Attempts is how many times OpenTofu has started applying the plan,
+	// including the current attempt. The pending changes have been tried
+	// by every earlier attempt without being completed.
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// Retries returns how many earlier attempts to apply the plan didn't
+// complete the changes that are still pending.
+func (c *ApplyCheckpoint) Retries() int {
+	if c == nil || c.Attempts <= 1 {
+		return 0
+	}
+	return c.Attempts - 1
+}
+
+// ReadApplyCheckpoint reads a checkpoint previously saved at the given path.
+//
+// If there is no checkpoint at that path then the returned error wraps
+// os.ErrNotExist.
+func ReadApplyCheckpoint(path string) (*ApplyCheckpoint, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret := &ApplyCheckpoint{}
+	if err := json.Unmarshal(src, ret); err != nil {
+		return nil, fmt.Errorf("invalid apply checkpoint %s: %w", path, err)
+	}
+	ret.Path = path
+	return ret, nil
+}
+
+// ApplyCheckpointKey returns the string used to identify the given planned
+// change in an ApplyCheckpoint.
+func ApplyCheckpointKey(change *plans.ResourceInstanceChangeSrc) string {
+	if change.DeposedKey != states.NotDeposed {
+		return fmt.Sprintf("%s (deposed object %s)", change.Addr, change.DeposedKey)
+	}
+	return change.Addr.String()
+}
+
+// IsCompleted returns true if the change with the given key has already been
+// recorded as completed.
+func (c *ApplyCheckpoint) IsCompleted(key string) bool {
+	return slices.Contains(c.Completed, key)
+}
+
+// Save writes the checkpoint to its Path, replacing any existing file there.
+func (c *ApplyCheckpoint) Save() error {
+	src, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+
+	// We write to a temporary file and then rename it, so that a crash
+	// while saving can't leave behind a truncated checkpoint.
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, src, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Path)
+}
+
+// Remove deletes the saved checkpoint, if any, once it's no longer needed.
+func (c *ApplyCheckpoint) Remove() error {
+	err := os.Remove(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// ApplyReportFormatVersion is the version of the JSON format of an
+// ApplyReport.
+const ApplyReportFormatVersion = "1.0"
+
+// ApplyReport describes the outcome of each of the changes that an apply
+// operation set out to make.
+//
+// When a change fails, OpenTofu still applies the changes that don't depend
+// on it, and skips only those that do. The report records which changes
+// ended up in each of those groups, so that they can be followed up on.
+type ApplyReport struct {
+	FormatVersion string `json:"format_version"`
+
+	Succeeded []ApplyReportEntry `json:"succeeded"`
+	Failed    []ApplyReportEntry `json:"failed"`
+
+	// Skipped are the changes that were never attempted, typically because
+	// they depend on a change that failed.
+	Skipped []ApplyReportEntry `json:"skipped"`
+}
+
+// ApplyReportEntry describes a single resource instance change in an
+// ApplyReport.
+type ApplyReportEntry struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
+
+	// Error is the error message for a failed change.
+	Error string `json:"error,omitempty"`
+}
+
+// NewApplyReportEntry returns the entry for the given planned change, with
+// the given error message if it failed.
+func NewApplyReportEntry(change *plans.ResourceInstanceChangeSrc, err string) ApplyReportEntry {
+	return ApplyReportEntry{
+		Address: change.Addr.String(),
+		Action:  ReportActionName(change.Action),
+		Error:   err,
+	}
+}
+
+// ReportActionName returns the name used for the given action in the JSON
+// reports produced by operations.
+func ReportActionName(action plans.Action) string {
+	switch action {
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	case plans.NoOp:
+		return "no-op"
+	default:
+		return strings.ToLower(action.String())
+	}
+}
+
+// Incomplete returns true if any of the changes failed or were skipped.
+func (r *ApplyReport) Incomplete() bool {
+	return len(r.Failed) != 0 || len(r.Skipped) != 0
+}
+
+// SuggestedCommand returns a plan command that targets the failed and
+// skipped changes, to review what's needed to complete them, or an empty
+// string if there are none.
+func (r *ApplyReport) SuggestedCommand() string {
+	if !r.Incomplete() {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString("tofu plan")
+	seen := make(map[string]bool)
+	for _, entries := range [][]ApplyReportEntry{r.Failed, r.Skipped} {
+		for _, entry := range entries {
+			if seen[entry.Address] {
+				continue
+			}
+			seen[entry.Address] = true
+			buf.WriteString(" -target=")
+			buf.WriteString(shellQuote(entry.Address))
+		}
+	}
+	return buf.String()
+}
+
+// shellQuote returns s quoted for a POSIX shell, so that the addresses in a
+// suggested command can contain quotes, such as in instance keys.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Save writes the report as JSON to the given path.
+func (r *ApplyReport) Save(path string) error {
+	src, err := json.MarshalIndent(struct {
+		*ApplyReport
+		SuggestedCommand string `json:"suggested_command,omitempty"`
+	}{r, r.SuggestedCommand()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(src, '\n'), 0644)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"testing"
+)
+
+func TestApplyReportSuggestedCommand(t *testing.T) {
+	tests := map[string]struct {
+		report *ApplyReport
+		want   string
+	}{
+		"complete": {
+			&ApplyReport{
+				Succeeded: []ApplyReportEntry{{Address: "test_instance.a", Action: "create"}},
+			},
+			"",
+		},
+		"failed and skipped": {
+			&ApplyReport{
+				Failed:  []ApplyReportEntry{{Address: "test_instance.a", Action: "create", Error: "boom"}},
+				Skipped: []ApplyReportEntry{{Address: "test_instance.b", Action: "update"}},
+			},
+			"tofu plan -target='test_instance.a' -target='test_instance.b'",
+		},
+		"quotes in instance keys": {
+			&ApplyReport{
+				Failed: []ApplyReportEntry{{Address: `test_instance.a["it's"]`, Action: "create", Error: "boom"}},
+			},
+			`tofu plan -target='test_instance.a["it'\''s"]'`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.report.SuggestedCommand(); got != test.want {
+				t.Errorf("wrong command\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/opentofu/svchost"
@@ -21,12 +22,16 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/costestimate"
+	"github.com/opentofu/opentofu/internal/command/regopolicy"
+	"github.com/opentofu/opentofu/internal/command/runhook"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states"
@@ -252,6 +257,10 @@ type Operation struct {
 	PlanOutPath    string // PlanOutPath is the path to save the plan
 	PlanOutBackend *plans.Backend
 
+	// PlanMaxAge, if nonzero, limits how long after it is created the plan
+	// saved to PlanOutPath can be applied.
+	PlanMaxAge time.Duration
+
 	// ConfigDir is the path to the directory containing the configuration's
 	// root module.
 	ConfigDir string
@@ -278,6 +287,45 @@ type Operation struct {
 	// plan and apply arguments but may not work for all backends.
 	PlanFile *planfile.WrappedPlanFile
 
+	// Checkpoint, if set, is where an apply operation for a saved plan
+	// records its progress so that it can be resumed if interrupted.
+	//
+	// If ResumeApply is also set then the changes that the checkpoint already
+	// records as completed are skipped, and the plan is applied against the
+	// latest state rather than the state it was created from.
+	Checkpoint  *ApplyCheckpoint
+	ResumeApply bool
+
+	// PreviewDestroyOrder, if set, asks an apply operation to show the
+	// order in which the planned destroy actions will be applied before
+	// asking for approval.
+	PreviewDestroyOrder bool
+
+	// AutoApprovePolicy, if set, asks an apply operation to skip asking for
+	// approval when the policy approves the new plan. Backends that don't
+	// support it must return an error if it's set.
+	AutoApprovePolicy plans.AutoApprovePolicy
+
+	// InteractiveReview, if set, asks an apply operation to let the user
+	// review the planned changes one by one and deselect some of them, which
+	// are then excluded from a new plan, before approving. Backends that
+	// don't support it must return an error if it's set.
+	InteractiveReview bool
+
+	// StatePersistMode and StatePersistInterval control how often an apply
+	// operation persists intermediate state snapshots. If
+	// StatePersistInterval is zero then the backend chooses its default
+	// interval. Backends that don't persist intermediate snapshots
+	// themselves must return an error if either is set.
+	StatePersistMode     StatePersistMode
+	StatePersistInterval time.Duration
+
+	// ApplyReport, if set, asks an apply operation to report which of the
+	// planned changes succeeded, failed, or were skipped, in the
+	// RunningOperation's ApplyReport field. Backends that don't support it
+	// must return an error if it's set.
+	ApplyReport bool
+
 	// The options below are more self-explanatory and affect the runtime
 	// behavior of the operation.
 	PlanMode     plans.Mode
@@ -285,6 +333,73 @@ type Operation struct {
 	Targets      []addrs.Targetable
 	Excludes     []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
+
+	// TargetSelectors select additional resource instances to target, by
+	// the attribute values recorded in the prior state. Backends that don't
+	// support them must return an error if any are set.
+	TargetSelectors []tofu.TargetSelector
+
+	// CascadeReplace, if set, asks for the resource instances that depend on
+	// the ones in ForceReplace to be replaced too. Backends that don't
+	// support it must return an error if it's set.
+	CascadeReplace bool
+
+	// AllowDeferral, if set, asks for the resources whose count or for_each
+	// arguments aren't known yet to be deferred to a later plan instead of
+	// causing an error. Backends that don't support it must return an error
+	// if it's set.
+	AllowDeferral bool
+
+	// ShowProvisioners, if set, asks for the plan to show what the
+	// provisioners of each planned change will run when it's applied.
+	// Backends that don't support it must return an error if it's set.
+	ShowProvisioners bool
+
+	// PlanCache, if set, makes a plan operation incremental: resource
+	// instances whose inputs haven't changed since the earlier plan that
+	// populated the cache aren't planned again. Backends that don't support
+	// it must return an error if it's set.
+	PlanCache *tofu.PlanCache
+
+	// ReportOrphans, if set, asks a plan operation to report the resource
+	// instances in the prior state that have no configuration, in the
+	// RunningOperation's OrphanReport field. Backends that don't support it
+	// must return an error if it's set.
+	ReportOrphans bool
+
+	// ExportGraph, if set, asks a plan operation to describe the order in
+	// which applying the plan would change its resource instances, in the
+	// RunningOperation's ExecutionGraph field. Backends that don't support
+	// it must return an error if it's set.
+	ExportGraph bool
+
+	// StateVersion, if set, makes a plan operation plan against the given
+	// earlier version of the state instead of the latest one, as accepted by
+	// statemgr.StateVersionReader. Backends that don't support it must
+	// return an error if it's set.
+	StateVersion string
+
+	// ProviderParallelism optionally limits the concurrent operations for
+	// the resources of specific providers, named either by a local name from
+	// the root module's required_providers or by a source address. Backends
+	// that don't run operations locally ignore it.
+	ProviderParallelism map[string]int
+
+	// CostEstimator, if set, estimates the cost of a new plan before it's
+	// rendered, so that the estimate is included in the plan rendering.
+	CostEstimator *costestimate.Estimator
+
+	// RunHooks are external programs to run at points in the lifecycle of
+	// plan and apply operations. Backends that don't run operations locally
+	// ignore them.
+	RunHooks runhook.Hooks
+
+	// RegoPolicies are checked against the plan of an apply operation before
+	// it's applied, and PolicyOverrideToken can override their denials.
+	// Backends that don't run operations locally ignore them.
+	RegoPolicies        []*regopolicy.Policy
+	PolicyOverrideToken string
+
 	// Injected by the command creating the operation (plan/apply/refresh/etc...)
 	Variables map[string]UnparsedVariableValue
 	RootCall  configs.StaticModuleCall
@@ -321,6 +436,10 @@ type Operation struct {
 	// for unmatched import targets and where any generated config should be
 	// written to.
 	GenerateConfigOut string
+
+	// GenerateConfigTemplates, if not nil, reshape the config generated for
+	// unmatched import targets before it's written to GenerateConfigOut.
+	GenerateConfigTemplates *genconfig.Templates
 }
 
 // HasConfig returns true if and only if the operation has a ConfigDir value
@@ -391,6 +510,18 @@ type RunningOperation struct {
 	// the exit status because the plan value is not available at that point.
 	PlanEmpty bool
 
+	// ApplyReport is populated after an Apply operation that was asked for
+	// a report has applied its plan, even if some of the changes failed.
+	ApplyReport *ApplyReport
+
+	// OrphanReport is populated after a Plan operation that was asked for a
+	// report of orphaned resource instances has created a plan.
+	OrphanReport *OrphanReport
+
+	// ExecutionGraph is populated after a Plan operation that was asked to
+	// export its execution graph has created a plan without errors.
+	ExecutionGraph *ExecutionGraph
+
 	// State is the final state after the operation completed. Persisting
 	// this state is managed by the backend. This should only be read
 	// after the operation completes to avoid read/write races.
@@ -414,6 +545,25 @@ func (r OperationResult) ExitStatus() int {
 	return int(r)
 }
 
+// StatePersistMode describes how often an apply operation persists
+// intermediate state snapshots while it's applying changes.
+type StatePersistMode int
+
+const (
+	// StatePersistPeriodically persists a snapshot when a state update
+	// arrives after the operation's StatePersistInterval has elapsed since
+	// the previous one. This is the default.
+	StatePersistPeriodically StatePersistMode = iota
+
+	// StatePersistEachChange persists a snapshot after every state update,
+	// which usually means after each resource instance change.
+	StatePersistEachChange
+
+	// StatePersistOnCompletion persists a snapshot only once the apply has
+	// completed, or if it's interrupted.
+	StatePersistOnCompletion
+)
+
 // If the argument is a path, ReadPathOrContents loads it and returns the contents,
 // otherwise the argument is assumed to be the desired contents and is simply
 // returned.
@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// ExecutionGraphFormatVersion is the version of the JSON format of an
+// ExecutionGraph.
+const ExecutionGraphFormatVersion = "1.0"
+
+// ExecutionGraph describes the order in which applying a plan would change
+// its resource instances, for external tools that schedule the changes or
+// work out which other changes each of them can affect.
+type ExecutionGraph struct {
+	FormatVersion string               `json:"format_version"`
+	Nodes         []ExecutionGraphNode `json:"nodes"`
+}
+
+// ExecutionGraphNode is a single change to a resource instance object in an
+// ExecutionGraph.
+type ExecutionGraphNode struct {
+	// ID identifies the node within the graph. It's the address of the
+	// resource instance, followed by the deposed key for a deposed object,
+	// and by "(destroy)" or "(forget)" for those operations.
+	ID string `json:"id"`
+
+	Address string `json:"address"`
+	Deposed string `json:"deposed,omitempty"`
+
+	// Operation is "apply" for the node that creates, updates, or reads the
+	// object, "destroy" for the node that destroys it, or "forget" for the
+	// node that removes it from the state. A replacement has both an apply
+	// node and a destroy node.
+	Operation string `json:"operation"`
+
+	// Action is the action that the plan proposes for the object, such as
+	// "create" or "replace".
+	Action string `json:"action"`
+
+	Provider string `json:"provider"`
+
+	// DependsOn are the IDs of the nodes that must be complete before this
+	// one can start. Dependencies that are implied by the other dependencies
+	// might be left out.
+	DependsOn []string `json:"depends_on"`
+}
+
+// Save writes the graph as JSON to the given path.
+func (g *ExecutionGraph) Save(path string) error {
+	src, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(src, '\n'), 0644)
+}
@@ -8,6 +8,8 @@
 package init
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/opentofu/svchost/disco"
@@ -28,6 +30,7 @@ import (
 	backendS3 "github.com/opentofu/opentofu/internal/backend/remote-state/s3"
 	backendCloud "github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/offline"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -91,10 +94,29 @@ func Init(services *disco.Disco) {
 
 // Backend returns the initialization factory for the given backend, or
 // nil if none exists.
+//
+// While OpenTofu is running in offline mode, the backends that store state
+// in a remote system fail to configure.
 func Backend(name string) backend.InitFn {
 	backendsLock.Lock()
 	defer backendsLock.Unlock()
-	return backends[name]
+	f := backends[name]
+	if f == nil || !offline.Enabled() || localBackends[name] {
+		return f
+	}
+	return func(enc encryption.StateEncryption) backend.Backend {
+		return offlineBackendShim{
+			Backend: f(enc),
+			Type:    name,
+		}
+	}
+}
+
+// localBackends are the backends that don't need network access, and so
+// remain available in offline mode.
+var localBackends = map[string]bool{
+	"local": true,
+	"inmem": true,
 }
 
 // Set sets a new backend in the list of backends. If f is nil then the
@@ -155,3 +177,23 @@ func deprecateBackend(b backend.Backend, message string) backend.Backend {
 		Message: message,
 	}
 }
+
+// offlineBackendShim is used to wrap a backend that needs network access
+// while OpenTofu is running in offline mode, so that it fails to configure.
+//
+// Like deprecatedBackendShim, it hides any optional interfaces of the wrapped
+// backend, but that doesn't matter because the backend can't be used.
+type offlineBackendShim struct {
+	backend.Backend
+	Type string
+}
+
+// Configure returns an error without configuring the wrapped backend.
+func (b offlineBackendShim) Configure(context.Context, cty.Value) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	return diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Backend not available in offline mode",
+		fmt.Sprintf("OpenTofu is running in offline mode, so it can't use the %q backend, which needs network access. To use this backend, run OpenTofu without the -offline option and without the %s environment variable.", b.Type, offline.EnvVar),
+	))
+}
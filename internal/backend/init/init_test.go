@@ -6,10 +6,15 @@
 package init
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/offline"
 )
 
 func TestInit_backend(t *testing.T) {
@@ -45,3 +50,23 @@ func TestInit_backend(t *testing.T) {
 		})
 	}
 }
+
+func TestBackend_offline(t *testing.T) {
+	Init(nil)
+	offline.Enable(true)
+	t.Cleanup(func() { offline.Enable(false) })
+
+	local := Backend("local")(encryption.StateEncryptionDisabled())
+	if got, want := reflect.TypeOf(local).String(), "*local.Local"; got != want {
+		t.Errorf("expected the local backend to be %q in offline mode, got: %q", want, got)
+	}
+
+	s3 := Backend("s3")(encryption.StateEncryptionDisabled())
+	diags := s3.Configure(context.Background(), cty.EmptyObjectVal)
+	if !diags.HasErrors() {
+		t.Fatalf("unexpected success configuring the s3 backend in offline mode")
+	}
+	if got, want := diags.Err().Error(), `can't use the "s3" backend`; !strings.Contains(got, want) {
+		t.Errorf("error doesn't contain %q\n%s", want, got)
+	}
+}
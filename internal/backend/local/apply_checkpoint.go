@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"bytes"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// applyCheckpointer keeps a backend.ApplyCheckpoint up to date with the
+// changes from a plan that are reflected in each persisted state snapshot.
+type applyCheckpointer struct {
+	checkpoint *backend.ApplyCheckpoint
+	prior      *states.State
+	changes    []*plans.ResourceInstanceChangeSrc
+
+	// meta reports the lineage and serial of the latest persisted state
+	// snapshot, or is nil if the state manager doesn't track them.
+	meta statemgr.PersistentMeta
+}
+
+// newApplyCheckpointer prepares to track progress applying the given plan to
+// the state in the given state manager, recording all of its not-yet-completed
+// changes as pending.
+//
+// Any changes that the checkpoint already records as completed are expected
+// to have been removed from the plan already, using resumeFromCheckpoint.
+func newApplyCheckpointer(checkpoint *backend.ApplyCheckpoint, plan *plans.Plan, stateMgr statemgr.Transient) *applyCheckpointer {
+	ret := &applyCheckpointer{
+		checkpoint: checkpoint,
+		prior:      plan.PriorState,
+	}
+	ret.meta, _ = stateMgr.(statemgr.PersistentMeta)
+	ret.recordSnapshot()
+	checkpoint.Pending = nil
+	for _, change := range plan.Changes.Resources {
+		if !checkpointTracksChange(change) {
+			continue
+		}
+		ret.changes = append(ret.changes, change)
+		checkpoint.Pending = append(checkpoint.Pending, backend.ApplyCheckpointKey(change))
+	}
+	return ret
+}
+
+// update records as completed any pending changes whose result is reflected
+// in the given state snapshot, which must already have been persisted, and
+// then saves the checkpoint.
+func (c *applyCheckpointer) update(current *states.State) error {
+	if current == nil {
+		return nil
+	}
+	var pending []string
+	for _, change := range c.changes {
+		key := backend.ApplyCheckpointKey(change)
+		if c.checkpoint.IsCompleted(key) {
+			continue
+		}
+		if changeCompleted(change, c.prior, current) {
+			c.checkpoint.Completed = append(c.checkpoint.Completed, key)
+		} else {
+			pending = append(pending, key)
+		}
+	}
+	c.checkpoint.Pending = pending
+	c.recordSnapshot()
+	return c.checkpoint.Save()
+}
+
+// recordSnapshot records the lineage and serial of the latest persisted state
+// snapshot in the checkpoint.
+func (c *applyCheckpointer) recordSnapshot() {
+	if c.meta == nil {
+		return
+	}
+	meta := c.meta.StateSnapshotMeta()
+	c.checkpoint.Lineage = meta.Lineage
+	c.checkpoint.Serial = meta.Serial
+}
+
+// resumeFromCheckpoint adjusts the given run so that it will apply only the
+// changes that the checkpoint doesn't record as completed, starting from the
+// current state rather than from the prior state recorded in the plan.
+func resumeFromCheckpoint(run *backend.LocalRun, checkpoint *backend.ApplyCheckpoint, current *states.State) {
+	if current == nil {
+		current = states.NewState()
+	}
+	plan := run.Plan
+	remaining := make([]*plans.ResourceInstanceChangeSrc, 0, len(plan.Changes.Resources))
+	for _, change := range plan.Changes.Resources {
+		if checkpoint.IsCompleted(backend.ApplyCheckpointKey(change)) {
+			continue
+		}
+		remaining = append(remaining, change)
+	}
+	plan.Changes.Resources = remaining
+	plan.PriorState = current.DeepCopy()
+	run.InputState = plan.PriorState
+}
+
+// checkpointTracksChange returns true for the changes whose progress is
+// recorded in a checkpoint. Reads and no-op changes have no lasting effect
+// on their own, so there's nothing to skip if an apply is resumed.
+func checkpointTracksChange(change *plans.ResourceInstanceChangeSrc) bool {
+	switch change.Action {
+	case plans.Read:
+		return false
+	case plans.NoOp:
+		return change.Importing != nil
+	default:
+		return true
+	}
+}
+
+// changeCompleted decides whether the given change has been completed by
+// comparing the state it was planned against with the given current state.
+func changeCompleted(change *plans.ResourceInstanceChangeSrc, prior, current *states.State) bool {
+	before := prior.ResourceInstance(change.Addr)
+	after := current.ResourceInstance(change.Addr)
+
+	if change.DeposedKey != states.NotDeposed {
+		// The only change that can be planned for a deposed object is to
+		// destroy or forget it.
+		return after == nil || after.Deposed[change.DeposedKey] == nil
+	}
+
+	switch change.Action {
+	case plans.Delete, plans.Forget:
+		return after == nil || after.Current == nil
+	case plans.Create, plans.NoOp:
+		return after != nil && after.Current != nil
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		// With create_before_destroy the previous object is deposed until
+		// it's been destroyed, so we wait for that too.
+		if after == nil || after.Current == nil || len(after.Deposed) > countDeposed(before) {
+			return false
+		}
+		return objectChanged(before, after)
+	default:
+		return after != nil && after.Current != nil && objectChanged(before, after)
+	}
+}
+
+func objectChanged(before, after *states.ResourceInstance) bool {
+	if before == nil || before.Current == nil {
+		return true
+	}
+	return before.Current.Status != after.Current.Status ||
+		!bytes.Equal(before.Current.AttrsJSON, after.Current.AttrsJSON)
+}
+
+func countDeposed(is *states.ResourceInstance) int {
+	if is == nil {
+		return 0
+	}
+	return len(is.Deposed)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestApplyCheckpointer(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	instance := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	object := func(attrs string) *states.ResourceInstanceObjectSrc {
+		return &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(attrs),
+		}
+	}
+	change := func(name string, action plans.Action) *plans.ResourceInstanceChangeSrc {
+		return &plans.ResourceInstanceChangeSrc{
+			Addr:         instance(name),
+			PrevRunAddr:  instance(name),
+			ProviderAddr: provider,
+			ChangeSrc:    plans.ChangeSrc{Action: action},
+		}
+	}
+
+	prior := states.NewState()
+	prior.SyncWrapper().SetResourceInstanceCurrent(instance("updated"), object(`{"id":"a","v":1}`), provider, addrs.NoKey)
+	prior.SyncWrapper().SetResourceInstanceCurrent(instance("deleted"), object(`{"id":"b"}`), provider, addrs.NoKey)
+	prior.SyncWrapper().SetResourceInstanceCurrent(instance("unchanged"), object(`{"id":"c"}`), provider, addrs.NoKey)
+
+	plan := &plans.Plan{
+		PriorState: prior,
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				change("created", plans.Create),
+				change("updated", plans.Update),
+				change("deleted", plans.Delete),
+				change("unchanged", plans.NoOp),
+				change("later", plans.Create),
+			},
+		},
+	}
+
+	checkpoint := &backend.ApplyCheckpoint{
+		Path:      filepath.Join(t.TempDir(), backend.ApplyCheckpointFilename),
+		PlanFile:  "saved.tfplan",
+		Workspace: backend.DefaultStateName,
+	}
+	stateMgr := statemgr.NewFilesystem(filepath.Join(t.TempDir(), "terraform.tfstate"), encryption.StateEncryptionDisabled())
+	if err := statemgr.WriteAndPersist(t.Context(), stateMgr, prior, nil); err != nil {
+		t.Fatal(err)
+	}
+	c := newApplyCheckpointer(checkpoint, plan, stateMgr)
+	if diff := cmp.Diff([]string{"test_instance.created", "test_instance.updated", "test_instance.deleted", "test_instance.later"}, checkpoint.Pending); diff != "" {
+		t.Fatalf("wrong initial pending changes\n%s", diff)
+	}
+
+	// The persisted snapshot reflects everything except the last create.
+	current := prior.DeepCopy()
+	current.SyncWrapper().SetResourceInstanceCurrent(instance("created"), object(`{"id":"d"}`), provider, addrs.NoKey)
+	current.SyncWrapper().SetResourceInstanceCurrent(instance("updated"), object(`{"id":"a","v":2}`), provider, addrs.NoKey)
+	current.SyncWrapper().SetResourceInstanceCurrent(instance("deleted"), nil, provider, addrs.NoKey)
+	if err := statemgr.WriteAndPersist(t.Context(), stateMgr, current, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.update(current); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := backend.ReadApplyCheckpoint(checkpoint.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(checkpoint, saved); diff != "" {
+		t.Fatalf("saved checkpoint doesn't match\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"test_instance.created", "test_instance.updated", "test_instance.deleted"}, saved.Completed); diff != "" {
+		t.Errorf("wrong completed changes\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"test_instance.later"}, saved.Pending); diff != "" {
+		t.Errorf("wrong pending changes\n%s", diff)
+	}
+	if meta := stateMgr.StateSnapshotMeta(); saved.Lineage != meta.Lineage || saved.Serial != meta.Serial {
+		t.Errorf("checkpoint has lineage %q serial %d; want the persisted lineage %q serial %d", saved.Lineage, saved.Serial, meta.Lineage, meta.Serial)
+	}
+
+	// Resuming should leave only the incomplete changes, applied on top of
+	// the persisted state.
+	run := &backend.LocalRun{Plan: plan}
+	resumeFromCheckpoint(run, saved, current)
+	var remaining []string
+	for _, change := range plan.Changes.Resources {
+		remaining = append(remaining, change.Addr.String())
+	}
+	if diff := cmp.Diff([]string{"test_instance.unchanged", "test_instance.later"}, remaining); diff != "" {
+		t.Errorf("wrong remaining changes\n%s", diff)
+	}
+	if run.InputState.ResourceInstance(instance("created")) == nil {
+		t.Errorf("resumed run doesn't start from the current state")
+	}
+
+	if err := saved.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if err := saved.Remove(); err != nil {
+		t.Fatalf("removing a missing checkpoint should succeed, got: %s", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// applyReportHook records the outcome of each resource instance change that
+// OpenTofu Core applies, to build a backend.ApplyReport.
+type applyReportHook struct {
+	tofu.NilHook
+
+	// changes are the changes that the report is about, which must be
+	// recorded before applying them because OpenTofu Core removes each change
+	// from the plan once it's applied.
+	changes []*plans.ResourceInstanceChangeSrc
+
+	mu sync.Mutex
+	// results has an entry for each resource instance that OpenTofu Core
+	// finished applying a change for, which is the error message if any of
+	// its changes failed.
+	results map[string]string
+}
+
+var _ tofu.Hook = (*applyReportHook)(nil)
+
+func newApplyReportHook() *applyReportHook {
+	return &applyReportHook{
+		results: make(map[string]string),
+	}
+}
+
+// track records the changes from the given plan that the report will be
+// about. Reads and no-op changes are left out, as for apply checkpoints.
+func (h *applyReportHook) track(plan *plans.Plan) {
+	h.changes = nil
+	for _, change := range plan.Changes.Resources {
+		if checkpointTracksChange(change) {
+			h.changes = append(h.changes, change)
+		}
+	}
+}
+
+func (h *applyReportHook) PostApply(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value, err error) (tofu.HookAction, error) {
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	h.record(addr, msg)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *applyReportHook) PostApplyImport(addr addrs.AbsResourceInstance, _ plans.ImportingSrc) (tofu.HookAction, error) {
+	h.record(addr, "")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *applyReportHook) PostApplyForget(addr addrs.AbsResourceInstance) (tofu.HookAction, error) {
+	h.record(addr, "")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *applyReportHook) record(addr addrs.AbsResourceInstance, msg string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A replacement has a result for each of its steps, and if any of them
+	// failed then so did the change.
+	if prev, ok := h.results[addr.String()]; ok && prev != "" {
+		return
+	}
+	h.results[addr.String()] = msg
+}
+
+// report sorts the tracked changes by the results recorded for them. Changes
+// that OpenTofu Core never finished applying are skipped.
+func (h *applyReportHook) report() *backend.ApplyReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ret := &backend.ApplyReport{
+		FormatVersion: backend.ApplyReportFormatVersion,
+		Succeeded:     []backend.ApplyReportEntry{},
+		Failed:        []backend.ApplyReportEntry{},
+		Skipped:       []backend.ApplyReportEntry{},
+	}
+	for _, change := range h.changes {
+		msg, ok := h.results[change.Addr.String()]
+		switch {
+		case !ok:
+			ret.Skipped = append(ret.Skipped, backend.NewApplyReportEntry(change, ""))
+		case msg != "":
+			ret.Failed = append(ret.Failed, backend.NewApplyReportEntry(change, msg))
+		default:
+			ret.Succeeded = append(ret.Succeeded, backend.NewApplyReportEntry(change, ""))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,214 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+const reviewDescription = `Enter the number of a change to deselect it, or to select it again.
+Enter "show" followed by a number to show the details of that change,
+"all" or "none" to select or deselect all changes, "yes" to continue,
+or "no" to cancel. Deselected changes are excluded from a new plan,
+along with any changes that depend on them.`
+
+// reviewPlan implements "tofu apply -interactive-review", which lets the user
+// go through the resource instance changes in the given plan and deselect the
+// ones they don't want to apply.
+//
+// The deselected changes are added to the plan's exclusions and OpenTofu
+// creates a new plan, which the user then reviews in the same way, until they
+// accept a plan with all of its changes selected. reviewPlan returns that
+// plan, or a nil plan if the user cancelled the review.
+func (b *Local) reviewPlan(ctx, stopCtx context.Context, op *backend.Operation, lr *backend.LocalRun, plan *plans.Plan, schemas *tofu.Schemas) (*plans.Plan, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	planOpts := *lr.PlanOpts
+	for {
+		changes := reviewableChanges(plan)
+		if len(changes) == 0 {
+			// Excluding changes can leave nothing else to do, in which case
+			// there's nothing left to review either.
+			return plan, diags
+		}
+		deselected := make([]bool, len(changes))
+
+		for {
+			op.UIOut.Output(reviewList(changes, deselected))
+
+			v, err := op.UIIn.Input(stopCtx, &tofu.InputOpts{
+				Id:          "review",
+				Query:       "\nWhich changes do you want to apply?",
+				Description: reviewDescription,
+			})
+			if err != nil {
+				diags = diags.Append(fmt.Errorf("error asking for review: %w", err))
+				return nil, diags
+			}
+			if stopCtx.Err() != nil {
+				diags = diags.Append(errors.New("execution halted"))
+				return nil, diags
+			}
+
+			answer := strings.ToLower(strings.TrimSpace(v))
+			if n, ok := strings.CutPrefix(answer, "show"); ok {
+				i, ok := reviewChangeIndex(strings.TrimSpace(n), len(changes))
+				if !ok {
+					op.UIOut.Output(fmt.Sprintf("\nEnter \"show\" followed by a number from 1 to %d.", len(changes)))
+					continue
+				}
+				op.View.Plan(singleChangePlan(plan, changes[i]), schemas)
+				continue
+			}
+			if i, ok := reviewChangeIndex(answer, len(changes)); ok {
+				deselected[i] = !deselected[i]
+				continue
+			}
+
+			switch answer {
+			case "all", "none":
+				for i := range deselected {
+					deselected[i] = answer == "none"
+				}
+				continue
+			case "no":
+				return nil, diags
+			case "yes":
+			default:
+				op.UIOut.Output(fmt.Sprintf("\nUnrecognized answer %q.", v))
+				continue
+			}
+
+			if !slices.Contains(deselected, true) {
+				return plan, diags
+			}
+			if !slices.Contains(deselected, false) {
+				op.UIOut.Output("\nNo changes are selected. Select at least one change, or answer \"no\" to cancel.")
+				continue
+			}
+			break
+		}
+
+		var excludes []addrs.Targetable
+		for i, change := range changes {
+			if deselected[i] {
+				excludes = append(excludes, change.Addr)
+			}
+		}
+		planOpts.Excludes = append(slices.Clip(planOpts.Excludes), excludes...)
+
+		log.Printf("[INFO] backend/local: apply calling Plan to exclude %d deselected changes", len(excludes))
+		newPlan, moreDiags := lr.Core.Plan(ctx, lr.Config, lr.InputState, &planOpts)
+		if moreDiags.HasErrors() {
+			diags = diags.Append(moreDiags)
+			return nil, diags
+		}
+		plan = newPlan
+		moreDiags = moreDiags.Append(b.estimateCost(ctx, op, lr, plan, schemas))
+
+		op.UIOut.Output("\nOpenTofu has created a new plan without the deselected changes:")
+		op.View.Plan(plan, schemas)
+		// We show the warnings from the new plan right away, so that the
+		// user can consider them during the next review.
+		if len(moreDiags) > 0 {
+			op.View.Diagnostics(moreDiags)
+		}
+	}
+}
+
+// reviewableChanges returns the changes in the given plan that the user can
+// deselect during an interactive review, in the order they're listed.
+func reviewableChanges(plan *plans.Plan) []*plans.ResourceInstanceChangeSrc {
+	var ret []*plans.ResourceInstanceChangeSrc
+	for _, change := range plan.Changes.Resources {
+		if change.Action == plans.NoOp {
+			continue
+		}
+		ret = append(ret, change)
+	}
+	slices.SortFunc(ret, func(a, b *plans.ResourceInstanceChangeSrc) int {
+		if a.Addr.Less(b.Addr) {
+			return -1
+		}
+		if b.Addr.Less(a.Addr) {
+			return 1
+		}
+		return 0
+	})
+	return ret
+}
+
+// reviewList describes the given changes and whether each of them is
+// selected, numbered from 1 for the user to refer to them.
+func reviewList(changes []*plans.ResourceInstanceChangeSrc, deselected []bool) string {
+	var buf strings.Builder
+	buf.WriteString("\nPlanned changes:\n")
+	for i, change := range changes {
+		mark := "x"
+		if deselected[i] {
+			mark = " "
+		}
+		addr := change.Addr.String()
+		if change.DeposedKey != states.NotDeposed {
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, change.DeposedKey)
+		}
+		fmt.Fprintf(&buf, "  %d. [%s] %s will be %s\n", i+1, mark, addr, reviewActionDescription(change.Action))
+	}
+	return buf.String()
+}
+
+func reviewActionDescription(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "created"
+	case plans.Read:
+		return "read"
+	case plans.Update:
+		return "updated in-place"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replaced"
+	case plans.Delete:
+		return "destroyed"
+	case plans.Forget:
+		return "removed from the state"
+	default:
+		return strings.ToLower(action.String())
+	}
+}
+
+// reviewChangeIndex returns the index of the change with the given number in
+// a list of n changes, if the answer is such a number.
+func reviewChangeIndex(answer string, n int) (int, bool) {
+	num, err := strconv.Atoi(answer)
+	if err != nil || num < 1 || num > n {
+		return 0, false
+	}
+	return num - 1, true
+}
+
+// singleChangePlan returns a copy of the given plan that has only the given
+// change, for showing the details of that change alone.
+func singleChangePlan(plan *plans.Plan, change *plans.ResourceInstanceChangeSrc) *plans.Plan {
+	ret := *plan
+	ret.Changes = &plans.Changes{
+		Resources: []*plans.ResourceInstanceChangeSrc{change},
+	}
+	ret.DriftedResources = nil
+	return &ret
+}
@@ -16,8 +16,11 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/regopolicy"
+	"github.com/opentofu/opentofu/internal/command/runhook"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/logging"
+	"github.com/opentofu/opentofu/internal/perf"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
@@ -80,6 +83,12 @@ func (b *Local) opApply(
 	stateHook := new(StateHook)
 	op.Hooks = append(op.Hooks, stateHook)
 
+	var reportHook *applyReportHook
+	if op.ApplyReport {
+		reportHook = newApplyReportHook()
+		op.Hooks = append(op.Hooks, reportHook)
+	}
+
 	// Get our context
 	lr, _, opState, contextDiags := b.localRun(ctx, op)
 	diags = diags.Append(contextDiags)
@@ -111,16 +120,35 @@ func (b *Local) opApply(
 	// stateHook uses schemas for when it periodically persists state to the
 	// persistent storage backend.
 	stateHook.Schemas = schemas
-	persistInterval := getEnvAsInt(persistIntervalEnvironmentVariableName, defaultPersistInterval)
-	if persistInterval < defaultPersistInterval {
-		panic(fmt.Sprintf("Can't use value lower than %d for env variable %s, got %d",
-			defaultPersistInterval, persistIntervalEnvironmentVariableName, persistInterval))
+	switch op.StatePersistMode {
+	case backend.StatePersistEachChange:
+		stateHook.PersistEachUpdate = true
+	case backend.StatePersistOnCompletion:
+		// A zero interval disables intermediate snapshots, though the
+		// hook still persists one if the operation is interrupted.
+		stateHook.PersistInterval = 0
+	case backend.StatePersistPeriodically:
+		if op.StatePersistInterval > 0 {
+			stateHook.PersistInterval = op.StatePersistInterval
+			break
+		}
+		persistInterval := getEnvAsInt(persistIntervalEnvironmentVariableName, defaultPersistInterval)
+		if persistInterval < defaultPersistInterval {
+			panic(fmt.Sprintf("Can't use value lower than %d for env variable %s, got %d",
+				defaultPersistInterval, persistIntervalEnvironmentVariableName, persistInterval))
+		}
+		stateHook.PersistInterval = time.Duration(persistInterval) * time.Second
 	}
-	stateHook.PersistInterval = time.Duration(persistInterval) * time.Second
 
 	var plan *plans.Plan
 	// If we weren't given a plan, then we refresh/plan
 	if op.PlanFile == nil {
+		diags = diags.Append(b.runHooks(ctx, op, runhook.EventPrePlan, nil, nil, nil, false, nil))
+		if diags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
 		// Perform the plan
 		log.Printf("[INFO] backend/local: apply calling Plan")
 		plan, moreDiags = lr.Core.Plan(ctx, lr.Config, lr.InputState, lr.PlanOpts)
@@ -140,11 +168,53 @@ func (b *Local) opApply(
 			return
 		}
 
+		moreDiags = b.runHooks(ctx, op, runhook.EventPostPlan, lr.Config, plan, nil, false, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
 		trivialPlan := !plan.CanApply()
 		hasUI := op.UIOut != nil && op.UIIn != nil
 		mustConfirm := hasUI && !op.AutoApprove && !trivialPlan
+		diags = diags.Append(b.estimateCost(ctx, op, lr, plan, schemas))
 		op.View.Plan(plan, schemas)
 
+		// The policies are checked before asking for approval, so that
+		// nobody approves changes that can't be applied anyway.
+		moreDiags = b.checkPolicies(ctx, op, lr, plan, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
+		if op.AutoApprovePolicy != plans.NoAutoApprovePolicy && !trivialPlan {
+			if blocking := op.AutoApprovePolicy.BlockingChanges(plan.Changes); len(blocking) != 0 {
+				// We must not fall back to applying without approval when
+				// there's no UI to ask with, because then the policy would
+				// have no effect at all.
+				diags = diags.Append(autoApprovePolicyDiagnostic(op.AutoApprovePolicy, blocking, hasUI))
+				if !hasUI {
+					op.ReportResult(runningOp, diags)
+					return
+				}
+			} else {
+				mustConfirm = false
+			}
+		}
+
+		if op.PreviewDestroyOrder {
+			waves, moreDiags := lr.Core.DestroyOrder(plan, lr.Config)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+			op.View.DestroyOrder(waves)
+		}
+
 		if testHookStopPlanApply != nil {
 			testHookStopPlanApply()
 		}
@@ -161,7 +231,26 @@ func (b *Local) opApply(
 			return
 		}
 
-		if mustConfirm {
+		if mustConfirm && op.InteractiveReview {
+			// We'll show any accumulated warnings before the review, so the
+			// user can consider them when deciding which changes to apply.
+			if len(diags) > 0 {
+				op.View.Diagnostics(diags)
+				diags = nil // reset so we won't show the same diagnostics again later
+			}
+
+			plan, moreDiags = b.reviewPlan(ctx, stopCtx, op, lr, plan, schemas)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+			if plan == nil {
+				op.View.Cancelled(op.PlanMode)
+				runningOp.Result = backend.OperationFailure
+				return
+			}
+		} else if mustConfirm {
 			var desc, query string
 			switch op.PlanMode {
 			case plans.DestroyMode:
@@ -256,10 +345,37 @@ func (b *Local) opApply(
 				op.View.PlannedChange(change)
 			}
 		}
+
+		moreDiags = b.checkPolicies(ctx, op, lr, plan, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
+	moreDiags = b.runHooks(ctx, op, runhook.EventPreApply, lr.Config, plan, nil, false, schemas)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		op.ReportResult(runningOp, diags)
+		return
 	}
 
 	// Set up our hook for continuous state updates
 	stateHook.StateMgr = opState
+	var retries int
+	if op.PlanFile != nil && op.Checkpoint != nil {
+		stateHook.checkpoint = newApplyCheckpointer(op.Checkpoint, plan, opState)
+		op.Checkpoint.Attempts++
+		retries = op.Checkpoint.Retries()
+		if err := op.Checkpoint.Save(); err != nil {
+			log.Printf("[WARN] backend/local: failed to save apply checkpoint: %s", err)
+		}
+	}
+	if reportHook != nil {
+		reportHook.track(plan)
+	}
+	op.View.ApplyStarting(plan, retries)
 
 	// Start to apply in a goroutine so that we can be interrupted.
 	var applyState *states.State
@@ -277,6 +393,9 @@ func (b *Local) opApply(
 		return
 	}
 	diags = diags.Append(applyDiags)
+	if reportHook != nil {
+		runningOp.ApplyReport = reportHook.report()
+	}
 
 	// Even on error with an empty state, the state value should not be nil.
 	// Return early here to prevent corrupting any existing state.
@@ -288,7 +407,9 @@ func (b *Local) opApply(
 
 	// Store the final state
 	runningOp.State = applyState
+	endStateWrite := perf.StartPhase(ctx, "state write")
 	err := statemgr.WriteAndPersist(context.TODO(), opState, applyState, schemas)
+	endStateWrite()
 	if err != nil {
 		// Export the state file from the state manager and assign the new
 		// state. This is needed to preserve the existing serial and lineage.
@@ -303,10 +424,18 @@ func (b *Local) opApply(
 		return
 	}
 
+	diags = diags.Append(b.runHooks(ctx, op, runhook.EventPostApply, lr.Config, nil, applyState, applyDiags.HasErrors(), schemas))
+
 	if applyDiags.HasErrors() {
+		stateHook.updateCheckpoint(applyState)
 		op.ReportResult(runningOp, diags)
 		return
 	}
+	if op.Checkpoint != nil {
+		if err := op.Checkpoint.Remove(); err != nil {
+			log.Printf("[WARN] backend/local: failed to remove apply checkpoint: %s", err)
+		}
+	}
 
 	// If we've accumulated any warnings along the way then we'll show them
 	// here just before we show the summary and next steps. If we encountered
@@ -314,6 +443,16 @@ func (b *Local) opApply(
 	op.View.Diagnostics(diags)
 }
 
+// checkPolicies checks the given plan against the operation's Rego policies,
+// if it has any changes to apply.
+func (b *Local) checkPolicies(ctx context.Context, op *backend.Operation, lr *backend.LocalRun, plan *plans.Plan, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	if len(op.RegoPolicies) == 0 || !plan.CanApply() {
+		return nil
+	}
+	log.Printf("[INFO] backend/local: checking the plan against %d Rego policies", len(op.RegoPolicies))
+	return regopolicy.CheckPlan(ctx, op.RegoPolicies, op.PolicyOverrideToken, lr.Config, plan, &statefile.File{State: plan.PriorState}, schemas)
+}
+
 // backupStateForError is called in a scenario where we're unable to persist the
 // state for some reason, and will attempt to save a backup copy of the state
 // to local disk to help the user recover. This is a "last ditch effort" sort
@@ -393,3 +532,36 @@ For resources that support import, it is possible to recover by manually importi
 
 This is a serious bug in OpenTofu and should be reported.
 `
+
+// autoApprovePolicyDiagnostic explains why the given policy didn't approve
+// the plan, as a warning if we're about to ask for approval instead, or as
+// an error if we can't.
+func autoApprovePolicyDiagnostic(policy plans.AutoApprovePolicy, blocking []*plans.ResourceInstanceChangeSrc, hasUI bool) tfdiags.Diagnostic {
+	var what string
+	switch policy {
+	case plans.AutoApproveNoDestroy:
+		what = "destroy"
+	default:
+		what = "replace"
+	}
+	objects := "objects"
+	if len(blocking) == 1 {
+		objects = "object"
+	}
+	detail := fmt.Sprintf(
+		"The -auto-approve-policy=%s option only approves plans that don't %s any existing objects, but this plan will %s %d %s, including %s.",
+		policy, what, what, len(blocking), objects, blocking[0].Addr,
+	)
+	if !hasUI {
+		return tfdiags.Sourceless(
+			tfdiags.Error,
+			"Manual approval required",
+			detail+" OpenTofu can't ask for approval in this context, so the plan will not be applied.",
+		)
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Manual approval required",
+		detail,
+	)
+}
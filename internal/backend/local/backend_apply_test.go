@@ -10,6 +10,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -20,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/regopolicy"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
@@ -397,6 +399,79 @@ func applyFixtureSchema() providers.ProviderSchema {
 	}
 }
 
+func TestLocal_applyRegoPolicies(t *testing.T) {
+	// The OPA stand-in used in this test assumes a Unix-like environment
+	// where scripts are directly executable based on their #! line.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+
+	program := filepath.Join(t.TempDir(), "opa")
+	script := "#!/bin/sh\ncat > /dev/null\necho '{\"result\":[{\"expressions\":[{\"value\":{\"deny\":[\"no tags\"]}}]}]}'\n"
+	if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	policies := []*regopolicy.Policy{
+		{
+			Name:    "org",
+			Paths:   []string{"policies"},
+			Program: program,
+			// This is the SHA-256 hash of "let-me-through".
+			OverrideTokenHash: "952c46e05e2bc3af57296712457295dae617faf086ff88ee759cb188f086cac0",
+		},
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		b := TestLocal(t)
+		p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+		op, done := testOperationApply(t, "./testdata/apply")
+		op.RegoPolicies = policies
+
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		<-run.Done()
+		if run.Result == backend.OperationSuccess {
+			t.Fatal("operation succeeded; want failure")
+		}
+		if p.ApplyResourceChangeCalled {
+			t.Error("apply should not be called")
+		}
+		if got, want := done(t).Stderr(), `The policy "org" denied the changes: no tags`; !strings.Contains(got, want) {
+			t.Errorf("wrong error output\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		b := TestLocal(t)
+		p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+		p.ApplyResourceChangeResponse = &providers.ApplyResourceChangeResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+			"id":  cty.StringVal("yes"),
+			"ami": cty.StringVal("bar"),
+		})}
+		op, done := testOperationApply(t, "./testdata/apply")
+		op.RegoPolicies = policies
+		op.PolicyOverrideToken = "let-me-through"
+
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		<-run.Done()
+		output := done(t)
+		if run.Result != backend.OperationSuccess {
+			t.Fatalf("operation failed\n%s", output.Stderr())
+		}
+		if !p.ApplyResourceChangeCalled {
+			t.Error("apply should be called")
+		}
+		if got, want := output.Stdout(), "Policy denial overridden"; !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
 func TestApply_applyCanceledAutoApprove(t *testing.T) {
 	b := TestLocal(t)
 
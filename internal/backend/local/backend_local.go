@@ -7,18 +7,27 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/perf"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -55,7 +64,15 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 		return nil, nil, nil, diags
 	}
 	log.Printf("[TRACE] backend/local: requesting state lock for workspace %q", op.Workspace)
-	if diags := op.StateLocker.Lock(s, op.Type.String()); diags.HasErrors() {
+	lock := op.StateLocker.Lock
+	if op.Type == backend.OperationTypePlan {
+		// Planning never persists a new state snapshot, so it only needs a
+		// shared lock and can therefore run concurrently with other plans.
+		// Refresh-only runs still need an exclusive lock because they
+		// persist the refreshed state.
+		lock = op.StateLocker.LockShared
+	}
+	if diags := lock(s, op.Type.String()); diags.HasErrors() {
 		return nil, nil, nil, diags
 	}
 
@@ -68,7 +85,10 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 	}()
 
 	log.Printf("[TRACE] backend/local: reading remote state for workspace %q", op.Workspace)
-	if err := s.RefreshState(context.TODO()); err != nil {
+	endStateRead := perf.StartPhase(ctx, "state read")
+	err = s.RefreshState(context.TODO())
+	endStateRead()
+	if err != nil {
 		diags = diags.Append(fmt.Errorf("error loading state: %w", err))
 		return nil, nil, nil, diags
 	}
@@ -82,6 +102,9 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 	}
 	coreOpts.UIInput = op.UIIn
 	coreOpts.Hooks = op.Hooks
+	if profile := perf.FromContext(ctx); profile != nil {
+		coreOpts.Hooks = append(slices.Clone(op.Hooks), newPerfHook(profile))
+	}
 	coreOpts.Encryption = op.Encryption
 
 	var ctxDiags tfdiags.Diagnostics
@@ -110,6 +133,11 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 		// Write sources into the cache of the main loader so that they are
 		// available if we need to generate diagnostic message snippets.
 		op.ConfigLoader.ImportSourcesFromSnapshot(configSnap)
+
+		if op.ResumeApply && op.Checkpoint != nil {
+			log.Printf("[TRACE] backend/local: resuming apply from checkpoint %s", op.Checkpoint.Path)
+			resumeFromCheckpoint(ret, op.Checkpoint, s.State())
+		}
 	} else {
 		log.Printf("[TRACE] backend/local: populating backend.LocalRun for current working directory")
 		ret, configSnap, ctxDiags = b.localRunDirect(ctx, op, ret, &coreOpts, s)
@@ -149,7 +177,9 @@ func (b *Local) localRunDirect(ctx context.Context, op *backend.Operation, run *
 	var diags tfdiags.Diagnostics
 
 	// Load the configuration using the caller-provided configuration loader.
+	endConfigLoad := perf.StartPhase(ctx, "config load")
 	config, configSnap, configDiags := op.ConfigLoader.LoadConfigWithSnapshot(ctx, op.ConfigDir, op.RootCall)
+	endConfigLoad()
 	diags = diags.Append(configDiags)
 	if configDiags.HasErrors() {
 		return nil, nil, diags
@@ -204,19 +234,32 @@ func (b *Local) localRunDirect(ctx context.Context, op *backend.Operation, run *
 	}
 
 	planOpts := &tofu.PlanOpts{
-		Mode:               op.PlanMode,
-		Targets:            op.Targets,
-		Excludes:           op.Excludes,
-		ForceReplace:       op.ForceReplace,
-		SetVariables:       variables,
-		SkipRefresh:        op.Type != backend.OperationTypeRefresh && !op.PlanRefresh,
-		GenerateConfigPath: op.GenerateConfigOut,
+		Mode:                op.PlanMode,
+		Targets:             op.Targets,
+		Excludes:            op.Excludes,
+		ForceReplace:        op.ForceReplace,
+		CascadeReplace:      op.CascadeReplace,
+		AllowDeferral:       op.AllowDeferral,
+		PreviewProvisioners: op.ShowProvisioners,
+		PlanCache:           op.PlanCache,
+		SetVariables:        variables,
+		SkipRefresh:         op.Type != backend.OperationTypeRefresh && !op.PlanRefresh,
+		GenerateConfigPath:  op.GenerateConfigOut,
 	}
 	run.PlanOpts = planOpts
 
 	// For a "direct" local run, the input state is the most recently stored
-	// snapshot, from the previous run.
+	// snapshot, from the previous run, unless the operation asks for an
+	// earlier one.
 	state := s.State()
+	if op.StateVersion != "" {
+		var versionDiags tfdiags.Diagnostics
+		state, versionDiags = readStateVersion(ctx, s, op.StateVersion)
+		diags = diags.Append(versionDiags)
+		if versionDiags.HasErrors() {
+			return nil, nil, diags
+		}
+	}
 	if state != nil {
 		migratedState, migrateDiags := tofumigrate.MigrateStateProviderAddresses(config, state)
 		diags = diags.Append(migrateDiags)
@@ -227,6 +270,22 @@ func (b *Local) localRunDirect(ctx context.Context, op *backend.Operation, run *
 	}
 	run.InputState = state
 
+	if len(op.TargetSelectors) != 0 {
+		selected, selectDiags := tofu.SelectTargets(state, op.TargetSelectors)
+		diags = diags.Append(selectDiags)
+		if selectDiags.HasErrors() {
+			return nil, nil, diags
+		}
+		for _, addr := range selected {
+			log.Printf("[TRACE] backend/local: target selector selected %s", addr)
+		}
+		planOpts.Targets = append(slices.Clone(op.Targets), selected...)
+	}
+
+	var parallelismDiags tfdiags.Diagnostics
+	coreOpts.ProviderParallelism, parallelismDiags = providerParallelism(config, op.ProviderParallelism)
+	diags = diags.Append(parallelismDiags)
+
 	tfCtx, moreDiags := tofu.NewContext(coreOpts)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
@@ -236,6 +295,102 @@ func (b *Local) localRunDirect(ctx context.Context, op *backend.Operation, run *
 	return run, configSnap, diags
 }
 
+// readStateVersion returns the given earlier version of the state from the
+// state manager, for an operation's StateVersion.
+func readStateVersion(ctx context.Context, s statemgr.Full, version string) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var file *statefile.File
+	err := statemgr.ErrStateVersionsUnsupported
+	if reader, ok := s.(statemgr.StateVersionReader); ok {
+		file, err = reader.ReadStateVersion(ctx, version)
+	}
+	switch {
+	case errors.Is(err, statemgr.ErrStateVersionsUnsupported):
+		return nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State versions not supported",
+			"The state storage for this workspace doesn't keep earlier versions of the state, so the -state-version option can't be used with it.",
+		))
+	case err != nil:
+		return nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state version",
+			fmt.Sprintf("Error reading version %q of the state: %s.", version, err),
+		))
+	}
+	log.Printf("[INFO] backend/local: planning against state version %q, with serial %d", version, file.Serial)
+	return file.State, diags
+}
+
+// savedPlanEphemeralVariableValue returns the value of the given ephemeral
+// root module variable for applying a saved plan, taken from the variable
+// values set for the apply operation, and records it in the plan.
+func savedPlanEphemeralVariableValue(vv map[string]backend.UnparsedVariableValue, variable *configs.Variable, plan *plans.Plan) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	raw, ok := vv[variable.Name]
+	if !ok {
+		if variable.Required() {
+			return cty.DynamicVal, diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "No value for required variable",
+				Detail:   fmt.Sprintf("The root module input variable %q is ephemeral, so its value isn't saved in the plan file and must be set again when applying the plan.", variable.Name),
+				Subject:  variable.DeclRange.Ptr(),
+			})
+		}
+		return variable.Default, diags
+	}
+
+	iv, valDiags := raw.ParseVariableValue(variable.ParsingMode)
+	diags = append(diags, valDiags.ToHCL()...)
+	if valDiags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	dv, err := plans.NewDynamicValue(iv.Value, cty.DynamicPseudoType)
+	if err != nil {
+		return cty.DynamicVal, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value for input variable",
+			Detail:   fmt.Sprintf("The value for variable %q could not be serialized: %s.", variable.Name, err),
+			Subject:  variable.DeclRange.Ptr(),
+		})
+	}
+	plan.EphemeralVariableValues[variable.Name] = dv
+	return iv.Value, diags
+}
+
+// checkSavedPlanVariables returns an error for each variable that was
+// explicitly set on the command line or in a named variables file when
+// applying a saved plan, unless it's an ephemeral variable, because the
+// values of all other variables were fixed when the plan was created.
+//
+// Values from automatically-loaded variables files and environment variables
+// are ignored, since they are present regardless of whether the user intended
+// to change anything.
+func checkSavedPlanVariables(vv map[string]backend.UnparsedVariableValue, decls map[string]*configs.Variable) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, name := range slices.Sorted(maps.Keys(vv)) {
+		mode := configs.VariableParseLiteral
+		if decl, ok := decls[name]; ok {
+			if decl.Ephemeral {
+				continue
+			}
+			mode = decl.ParsingMode
+		}
+		iv, _ := vv[name].ParseVariableValue(mode)
+		if iv == nil || (iv.SourceType != tofu.ValueFromCLIArg && iv.SourceType != tofu.ValueFromNamedFile) {
+			continue
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't set variables when applying a saved plan",
+			fmt.Sprintf("The -var and -var-file options cannot be used to set variable %q when applying a saved plan file, because a saved plan includes the variable values that were set when it was created. Only the values of ephemeral variables can be set when applying a saved plan.", name),
+		))
+	}
+	return diags
+}
+
 func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation, pf *planfile.Reader, run *backend.LocalRun, coreOpts *tofu.ContextOpts, currentStateMeta *statemgr.SnapshotMeta) (*backend.LocalRun, *configload.Snapshot, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -263,15 +418,36 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 		))
 		return nil, snap, diags
 	}
+
+	// When resuming an interrupted apply the plan may have expired since the
+	// apply started, but refusing to finish it then would only leave the
+	// changes half-applied. Resuming is only allowed while the state is
+	// exactly as the interrupted apply left it, which is checked below.
+	if plan.Expired(time.Now()) && !op.ResumeApply {
+		diags = diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Saved plan has expired",
+			fmt.Sprintf("The given plan file can no longer be applied because it expired at %s. Create a new plan to apply the changes.", plan.ExpiresAt.Format(time.RFC3339)),
+		), tfdiags.ErrorCodePlanStale))
+		return nil, snap, diags
+	}
+
 	// When we're applying a saved plan, we populate Plan instead of PlanOpts,
 	// because a plan object incorporates the subset of data from PlanOps that
 	// we need to apply the plan.
 	run.Plan = plan
 
+	// Ephemeral variables are never saved in a plan file, so their values
+	// must be set again when applying it.
+	plan.EphemeralVariableValues = make(map[string]plans.DynamicValue)
+
 	subCall := op.RootCall.WithVariables(func(variable *configs.Variable) (cty.Value, hcl.Diagnostics) {
 		var diags hcl.Diagnostics
 
 		name := variable.Name
+		if variable.Ephemeral {
+			return savedPlanEphemeralVariableValue(op.Variables, variable, plan)
+		}
 		v, ok := plan.VariableValues[name]
 		if !ok {
 			if variable.Required() {
@@ -302,6 +478,11 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 	}
 	run.Config = config
 
+	diags = diags.Append(checkSavedPlanVariables(op.Variables, config.Module.Variables))
+	if diags.HasErrors() {
+		return nil, snap, diags
+	}
+
 	// NOTE: We're intentionally comparing the current locks with the
 	// configuration snapshot, rather than the lock snapshot in the plan file,
 	// because it's the current locks which dictate our plugin selections
@@ -375,12 +556,29 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 				"The given plan file can not be applied because it was created from a different state lineage.",
 			))
 
+		case op.ResumeApply && op.Checkpoint != nil:
+			// When resuming an interrupted apply the state is expected to
+			// have changed since the plan was created, because it records
+			// the changes that were already completed, but nothing else
+			// may have changed it since the last snapshot that the
+			// interrupted apply persisted.
+			if currentStateMeta.Lineage != op.Checkpoint.Lineage || currentStateMeta.Serial != op.Checkpoint.Serial {
+				diags = diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
+					tfdiags.Error,
+					"State changed since the interrupted apply",
+					fmt.Sprintf(
+						"The interrupted apply can't be resumed because the state was changed by another operation after the apply last saved it: the apply saved serial %d of lineage %q, but the current state is serial %d of lineage %q. Create a new plan to apply the remaining changes.",
+						op.Checkpoint.Serial, op.Checkpoint.Lineage, currentStateMeta.Serial, currentStateMeta.Lineage,
+					),
+				), tfdiags.ErrorCodePlanStale))
+			}
+
 		case priorStateFile.Serial != currentStateMeta.Serial:
-			diags = diags.Append(tfdiags.Sourceless(
+			diags = diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Saved plan is stale",
 				"The given plan file can no longer be applied because the state was changed by another operation after the plan was created.",
-			))
+			), tfdiags.ErrorCodePlanStale))
 		}
 	}
 	// When we're applying a saved plan, the input state is the "prior state"
@@ -388,6 +586,10 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 	// refreshing we did while building the plan.
 	run.InputState = priorStateFile.State
 
+	var parallelismDiags tfdiags.Diagnostics
+	coreOpts.ProviderParallelism, parallelismDiags = providerParallelism(config, op.ProviderParallelism)
+	diags = diags.Append(parallelismDiags)
+
 	tfCtx, moreDiags := tofu.NewContext(coreOpts)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
@@ -397,6 +599,84 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 	return run, snap, diags
 }
 
+// providerParallelism resolves the providers named in the given per-provider
+// parallelism limits against the configuration. A name without a namespace
+// is first looked up as a local name in the root module's required_providers,
+// and otherwise matches the one provider of that type that the configuration
+// uses, so that "cloudflare" means cloudflare/cloudflare rather than the
+// hashicorp/cloudflare that the name would imply on its own. Limits for
+// providers that the configuration doesn't use are ignored with a warning.
+func providerParallelism(config *configs.Config, limits map[string]int) (map[addrs.Provider]int, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if len(limits) == 0 {
+		return nil, diags
+	}
+
+	used := config.ProviderTypes()
+	names := slices.Sorted(maps.Keys(limits))
+	ret := make(map[addrs.Provider]int, len(limits))
+	for _, name := range names {
+		provider, moreDiags := providerForParallelismName(config, used, name)
+		diags = diags.Append(moreDiags)
+		if provider.IsZero() {
+			continue
+		}
+		ret[provider] = limits[name]
+	}
+	return ret, diags
+}
+
+func providerForParallelismName(config *configs.Config, used []addrs.Provider, name string) (addrs.Provider, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var provider addrs.Provider
+	if strings.Contains(name, "/") {
+		// The name was already validated when the arguments were parsed.
+		provider, _ = addrs.ParseProviderSourceString(name)
+	} else {
+		provider = config.Module.ImpliedProviderForUnqualifiedType(name)
+		if _, declared := config.Module.ProviderRequirements.RequiredProviders[name]; !declared && !slices.Contains(used, provider) {
+			var matches []addrs.Provider
+			for _, candidate := range used {
+				if candidate.Type == name {
+					matches = append(matches, candidate)
+				}
+			}
+			switch len(matches) {
+			case 1:
+				provider = matches[0]
+			case 0:
+				// Not used at all; warned about below.
+			default:
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					fmt.Sprintf("Ambiguous provider %q in -parallelism", name),
+					fmt.Sprintf("The configuration uses more than one provider named %q: %s. Give the source address of the one to limit, or declare it in the root module's required_providers block.", name, joinProviders(matches)),
+				))
+				return addrs.Provider{}, diags
+			}
+		}
+	}
+
+	if !slices.Contains(used, provider) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			fmt.Sprintf("Unused provider %q in -parallelism", name),
+			fmt.Sprintf("The configuration doesn't use the provider %s, so its parallelism limit has no effect. Use a local name from the root module's required_providers block, or the provider's source address.", provider.ForDisplay()),
+		))
+		return addrs.Provider{}, diags
+	}
+	return provider, diags
+}
+
+func joinProviders(providers []addrs.Provider) string {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.ForDisplay()
+	}
+	return strings.Join(names, ", ")
+}
+
 // interactiveCollectVariables attempts to complete the given existing
 // map of variables by interactively prompting for any variables that are
 // declared as required but not yet present.
@@ -10,14 +10,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -277,3 +281,102 @@ func (s *stateStorageThatFailsRefresh) RefreshState(_ context.Context) error {
 func (s *stateStorageThatFailsRefresh) PersistState(_ context.Context, schemas *tofu.Schemas) error {
 	return fmt.Errorf("unimplemented")
 }
+
+func TestCheckSavedPlanVariables(t *testing.T) {
+	decls := map[string]*configs.Variable{
+		"password": {
+			Name:      "password",
+			Ephemeral: true,
+		},
+		"region": {
+			Name: "region",
+		},
+	}
+
+	tests := map[string]struct {
+		vars    map[string]backend.UnparsedVariableValue
+		wantErr string
+	}{
+		"ephemeral variable on the command line": {
+			vars: map[string]backend.UnparsedVariableValue{
+				"password": testSourcedVariableValue{tofu.ValueFromCLIArg},
+			},
+		},
+		"other variable from an automatically-loaded file": {
+			vars: map[string]backend.UnparsedVariableValue{
+				"region": testSourcedVariableValue{tofu.ValueFromAutoFile},
+			},
+		},
+		"other variable on the command line": {
+			vars: map[string]backend.UnparsedVariableValue{
+				"region": testSourcedVariableValue{tofu.ValueFromCLIArg},
+			},
+			wantErr: `cannot be used to set variable "region"`,
+		},
+		"undeclared variable from a named file": {
+			vars: map[string]backend.UnparsedVariableValue{
+				"undeclared": testSourcedVariableValue{tofu.ValueFromNamedFile},
+			},
+			wantErr: `cannot be used to set variable "undeclared"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := checkSavedPlanVariables(test.vars, decls)
+			if test.wantErr == "" {
+				if diags.HasErrors() {
+					t.Fatalf("unexpected errors: %s", diags.Err())
+				}
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want an error")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, test.wantErr)
+			}
+		})
+	}
+}
+
+// testSourcedVariableValue is a backend.UnparsedVariableValue whose value
+// came from the given source.
+type testSourcedVariableValue struct {
+	source tofu.ValueSourceType
+}
+
+func (v testSourcedVariableValue) ParseVariableValue(mode configs.VariableParsingMode) (*tofu.InputValue, tfdiags.Diagnostics) {
+	return &tofu.InputValue{
+		Value:      cty.StringVal("example"),
+		SourceType: v.source,
+	}, nil
+}
+
+func TestProviderParallelism(t *testing.T) {
+	config, _ := initwd.MustLoadConfigForTests(t, "./testdata/provider-parallelism", "tests")
+
+	got, diags := providerParallelism(config, map[string]int{
+		// A local name from the root module's required_providers.
+		"cf": 2,
+		// An implied provider.
+		"aws": 10,
+		// A provider used only by a child module, by its type.
+		"fastly": 3,
+		// A provider the configuration doesn't use.
+		"cloudflare/other": 4,
+	})
+	want := map[addrs.Provider]int{
+		addrs.MustParseProviderSourceString("cloudflare/cloudflare"): 2,
+		addrs.NewDefaultProvider("aws"):                              10,
+		addrs.MustParseProviderSourceString("fastly/fastly"):         3,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong limits\n%s", diff)
+	}
+	if len(diags) != 1 || diags[0].Severity() != tfdiags.Warning {
+		t.Fatalf("want one warning; got %s", diags.ErrWithWarnings())
+	}
+	if got, want := diags[0].Description().Summary, `Unused provider "cloudflare/other" in -parallelism`; got != want {
+		t.Errorf("wrong warning %q; want %q", got, want)
+	}
+}
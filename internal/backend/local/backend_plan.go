@@ -12,6 +12,7 @@ import (
 	"log"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/runhook"
 	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -108,6 +109,12 @@ func (b *Local) opPlan(
 	// resulting state is always just the input state.
 	runningOp.State = lr.InputState
 
+	diags = diags.Append(b.runHooks(ctx, op, runhook.EventPrePlan, nil, nil, nil, false, nil))
+	if diags.HasErrors() {
+		op.ReportResult(runningOp, diags)
+		return
+	}
+
 	// Perform the plan in a goroutine so we can be interrupted
 	var plan *plans.Plan
 	var planDiags tfdiags.Diagnostics
@@ -144,6 +151,35 @@ func (b *Local) opPlan(
 
 	// Record whether this plan includes any side-effects that could be applied.
 	runningOp.PlanEmpty = !plan.CanApply()
+	if op.ReportOrphans {
+		runningOp.OrphanReport = orphanReport(lr.Config, plan)
+	}
+	if op.ExportGraph && !plan.Errored {
+		graph, moreDiags := lr.Core.ApplyGraphForUI(plan, lr.Config)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+		runningOp.ExecutionGraph = executionGraph(plan, tofu.ApplySteps(graph))
+	}
+
+	schemas, moreDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		op.ReportResult(runningOp, diags)
+		return
+	}
+
+	// Run hooks can reject a complete plan before it's saved or rendered.
+	if !plan.Errored {
+		moreDiags = b.runHooks(ctx, op, runhook.EventPostPlan, lr.Config, plan, nil, false, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
 
 	// Save the plan to disk
 	if path := op.PlanOutPath; path != "" {
@@ -157,6 +193,9 @@ func (b *Local) opPlan(
 			return
 		}
 		plan.Backend = *op.PlanOutBackend
+		if op.PlanMaxAge > 0 {
+			plan.ExpiresAt = plan.Timestamp.Add(op.PlanMaxAge)
+		}
 
 		// We may have updated the state in the refresh step above, but we
 		// will freeze that updated state in the plan file for now and
@@ -194,23 +233,18 @@ func (b *Local) opPlan(
 		}
 	}
 
-	// Render the plan, if we produced one.
-	// (This might potentially be a partial plan with Errored set to true)
-	schemas, moreDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
-	diags = diags.Append(moreDiags)
-	if moreDiags.HasErrors() {
-		op.ReportResult(runningOp, diags)
-		return
-	}
-
 	// Write out any generated config, before we render the plan.
-	wroteConfig, moreDiags := maybeWriteGeneratedConfig(plan, op.GenerateConfigOut)
+	wroteConfig, moreDiags := maybeWriteGeneratedConfig(plan, op.GenerateConfigOut, op.GenerateConfigTemplates)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
 		op.ReportResult(runningOp, diags)
 		return
 	}
 
+	diags = diags.Append(b.estimateCost(ctx, op, lr, plan, schemas))
+
+	// Render the plan, if we produced one.
+	// (This might potentially be a partial plan with Errored set to true)
 	op.View.Plan(plan, schemas)
 
 	// If we've accumulated any diagnostics along the way then we'll show them
@@ -229,7 +263,7 @@ func (b *Local) opPlan(
 	}
 }
 
-func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool, diags tfdiags.Diagnostics) {
+func maybeWriteGeneratedConfig(plan *plans.Plan, out string, templates *genconfig.Templates) (wroteConfig bool, diags tfdiags.Diagnostics) {
 	if genconfig.ShouldWriteConfig(out) {
 		diags := genconfig.ValidateTargetFile(out)
 		if diags.HasErrors() {
@@ -245,6 +279,9 @@ func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool,
 			if c.Importing != nil {
 				change.ImportID = c.Importing.ID
 			}
+			if moreDiags := templates.Apply(&change); moreDiags.HasErrors() {
+				return false, diags.Append(moreDiags)
+			}
 
 			var moreDiags tfdiags.Diagnostics
 			writer, wroteConfig, moreDiags = change.MaybeWriteConfig(writer, out)
@@ -263,3 +300,13 @@ func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool,
 
 	return wroteConfig, diags
 }
+
+// estimateCost records the cost estimate of a new plan in the plan, if the
+// operation has a cost estimator and the plan has changes to estimate.
+func (b *Local) estimateCost(ctx context.Context, op *backend.Operation, lr *backend.LocalRun, plan *plans.Plan, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	if op.CostEstimator == nil || plan.Errored || !plan.CanApply() {
+		return nil
+	}
+	log.Printf("[INFO] backend/local: estimating the cost of the plan with %q", op.CostEstimator.Name)
+	return op.CostEstimator.AttachToPlan(ctx, lr.Config, plan, &statefile.File{State: plan.PriorState}, schemas)
+}
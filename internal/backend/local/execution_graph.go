@@ -0,0 +1,75 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// executionGraph describes the given steps of the apply graph for the given
+// plan.
+func executionGraph(plan *plans.Plan, steps []*tofu.ApplyStep) *backend.ExecutionGraph {
+	ret := &backend.ExecutionGraph{
+		FormatVersion: backend.ExecutionGraphFormatVersion,
+		Nodes:         make([]backend.ExecutionGraphNode, 0, len(steps)),
+	}
+
+	ids := make(map[*tofu.ApplyStep]string, len(steps))
+	for _, step := range steps {
+		change := plan.Changes.ResourceInstanceDeposed(step.Addr, step.DeposedKey)
+		deposed := step.DeposedKey
+		if change == nil && deposed != states.NotDeposed {
+			// The destroy step of a create_before_destroy replacement
+			// destroys the current object once it's been deposed, using a
+			// deposed key chosen while building the graph, so it's reported
+			// as a change to the current object.
+			change = plan.Changes.ResourceInstance(step.Addr)
+			deposed = states.NotDeposed
+		}
+
+		id := step.Addr.String()
+		if deposed != states.NotDeposed {
+			id = fmt.Sprintf("%s (deposed %s)", id, deposed)
+		}
+		if step.Operation != tofu.ApplyStepApply {
+			id = fmt.Sprintf("%s (%s)", id, step.Operation)
+		}
+		ids[step] = id
+
+		node := backend.ExecutionGraphNode{
+			ID:        id,
+			Address:   step.Addr.String(),
+			Operation: string(step.Operation),
+			Provider:  step.Provider.String(),
+		}
+		if deposed != states.NotDeposed {
+			node.Deposed = string(deposed)
+		}
+		if change != nil {
+			node.Action = backend.ReportActionName(change.Action)
+		}
+		ret.Nodes = append(ret.Nodes, node)
+	}
+
+	for i, step := range steps {
+		deps := make([]string, 0, len(step.DependsOn))
+		for _, dep := range step.DependsOn {
+			deps = append(deps, ids[dep])
+		}
+		sort.Strings(deps)
+		ret.Nodes[i].DependsOn = deps
+	}
+	sort.Slice(ret.Nodes, func(i, j int) bool {
+		return ret.Nodes[i].ID < ret.Nodes[j].ID
+	})
+	return ret
+}
@@ -31,11 +31,21 @@ type StateHook struct {
 	// StateMgr.PersistState function for some backends needs schemas.
 	PersistInterval time.Duration
 
+	// If PersistEachUpdate is set then we'll try to persist a state snapshot
+	// after every state update instead, as if PersistInterval had always
+	// elapsed. Like PersistInterval, this requires field Schemas to be valid.
+	PersistEachUpdate bool
+
 	// Schemas are the schemas to use when persisting state due to
-	// PersistInterval. This is ignored if PersistInterval is zero,
-	// and PersistInterval is ignored if this is nil.
+	// PersistInterval or PersistEachUpdate. Neither has any effect if this
+	// is nil, and this is ignored if neither is set.
 	Schemas *tofu.Schemas
 
+	// checkpoint, if set, is updated each time a state snapshot is
+	// successfully persisted, so that an interrupted apply can be resumed.
+	checkpoint *applyCheckpointer
+	latest     *states.State
+
 	intermediatePersist IntermediateStatePersistInfo
 }
 
@@ -80,13 +90,15 @@ func (h *StateHook) PostStateUpdate(new *states.State) (tofu.HookAction, error)
 		if err := h.StateMgr.WriteState(new); err != nil {
 			return tofu.HookActionHalt, err
 		}
-		if mgrPersist, ok := h.StateMgr.(statemgr.Persister); ok && h.PersistInterval != 0 && h.Schemas != nil {
+		h.latest = new
+		if mgrPersist, ok := h.StateMgr.(statemgr.Persister); ok && (h.PersistInterval != 0 || h.PersistEachUpdate) && h.Schemas != nil {
 			if h.shouldPersist() {
 				err := mgrPersist.PersistState(context.TODO(), h.Schemas)
 				if err != nil {
 					return tofu.HookActionHalt, err
 				}
 				h.intermediatePersist.LastPersist = time.Now()
+				h.updateCheckpoint(new)
 			} else {
 				log.Printf("[DEBUG] State storage %T declined to persist a state snapshot", h.StateMgr)
 			}
@@ -122,6 +134,8 @@ func (h *StateHook) Stopping() {
 				// but it's a best effort thing anyway, so we'll just emit a
 				// log to aid with debugging.
 				log.Printf("[ERROR] Failed to persist state after interruption: %s", err)
+			} else {
+				h.updateCheckpoint(h.latest)
 			}
 		} else {
 			log.Printf("[DEBUG] State storage %T declined to persist a state snapshot", h.StateMgr)
@@ -130,6 +144,18 @@ func (h *StateHook) Stopping() {
 
 }
 
+// updateCheckpoint records the progress reflected in a state snapshot that
+// has just been persisted. Failing to save the checkpoint doesn't affect the
+// apply itself; it only means that fewer changes can be skipped if resumed.
+func (h *StateHook) updateCheckpoint(persisted *states.State) {
+	if h.checkpoint == nil {
+		return
+	}
+	if err := h.checkpoint.update(persisted); err != nil {
+		log.Printf("[WARN] Failed to save apply checkpoint: %s", err)
+	}
+}
+
 func (h *StateHook) shouldPersist() bool {
 	if m, ok := h.StateMgr.(IntermediateStateConditionalPersister); ok {
 		return m.ShouldPersistIntermediateState(&h.intermediatePersist)
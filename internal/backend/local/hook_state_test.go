@@ -149,6 +149,56 @@ func TestStateHookStopping(t *testing.T) {
 	}
 }
 
+func TestStateHookPersistEachUpdate(t *testing.T) {
+	is := &testPersistentState{}
+	hook := &StateHook{
+		StateMgr:          is,
+		Schemas:           &tofu.Schemas{},
+		PersistEachUpdate: true,
+	}
+
+	s := statemgr.TestFullInitialState()
+	for i := 0; i < 2; i++ {
+		if _, err := hook.PostStateUpdate(s); err != nil {
+			t.Fatalf("unexpected error from PostStateUpdate: %s", err)
+		}
+	}
+
+	gotLog := is.CallLog
+	wantLog := []string{
+		"WriteState",
+		"PersistState",
+		"WriteState",
+		"PersistState",
+	}
+	if diff := cmp.Diff(wantLog, gotLog); diff != "" {
+		t.Fatalf("wrong call log\n%s", diff)
+	}
+}
+
+func TestStateHookPersistOnCompletion(t *testing.T) {
+	// A zero PersistInterval disables intermediate snapshots, until
+	// OpenTofu is asked to stop.
+	is := &testPersistentState{}
+	hook := &StateHook{
+		StateMgr: is,
+		Schemas:  &tofu.Schemas{},
+	}
+
+	s := statemgr.TestFullInitialState()
+	if _, err := hook.PostStateUpdate(s); err != nil {
+		t.Fatalf("unexpected error from PostStateUpdate: %s", err)
+	}
+	if is.Persisted != nil {
+		t.Fatalf("persisted an intermediate snapshot")
+	}
+
+	hook.Stopping()
+	if is.Persisted == nil || !is.Persisted.Equal(s) {
+		t.Fatalf("mismatching state persisted")
+	}
+}
+
 func TestStateHookCustomPersistRule(t *testing.T) {
 	is := &testPersistentStateThatRefusesToPersist{}
 	hook := &StateHook{
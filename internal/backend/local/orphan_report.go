@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// orphanReport lists the managed resource instances in the plan's prior
+// state whose resource isn't in the given configuration.
+//
+// The prior state already has any moved blocks applied, so resources that
+// were moved are reported only if their new address has no configuration
+// either.
+func orphanReport(config *configs.Config, plan *plans.Plan) *backend.OrphanReport {
+	ret := &backend.OrphanReport{
+		FormatVersion: backend.OrphanReportFormatVersion,
+		Orphans:       []backend.OrphanReportEntry{},
+	}
+	if plan.PriorState == nil {
+		return ret
+	}
+
+	for _, ms := range plan.PriorState.Modules {
+		modConfig := config.DescendentForInstance(ms.Addr)
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+				continue
+			}
+			if modConfig != nil && modConfig.Module.ResourceByAddr(rs.Addr.Resource) != nil {
+				continue
+			}
+			for key, is := range rs.Instances {
+				addr := rs.Addr.Instance(key)
+				entry := backend.OrphanReportEntry{
+					Address:               addr.String(),
+					ModuleAddress:         ms.Addr.String(),
+					ModuleInConfiguration: modConfig != nil,
+					Type:                  rs.Addr.Resource.Type,
+					Name:                  rs.Addr.Resource.Name,
+					Provider:              rs.ProviderConfig.String(),
+					Tainted:               is.Current != nil && is.Current.Status == states.ObjectTainted,
+					DeposedObjects:        len(is.Deposed),
+				}
+				if change := plan.Changes.ResourceInstance(addr); change != nil {
+					entry.PlannedAction = backend.ReportActionName(change.Action)
+				}
+				ret.Orphans = append(ret.Orphans, entry)
+			}
+		}
+	}
+
+	sort.Slice(ret.Orphans, func(i, j int) bool {
+		return ret.Orphans[i].Address < ret.Orphans[j].Address
+	})
+	return ret
+}
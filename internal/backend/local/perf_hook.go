@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/perf"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// perfHook records how long each resource instance's provider calls take in
+// a performance profile, from the hook calls that OpenTofu Core makes just
+// before and after them.
+type perfHook struct {
+	tofu.NilHook
+
+	profile *perf.Profile
+
+	mu      sync.Mutex
+	pending map[perfHookKey]perfHookCall
+}
+
+var _ tofu.Hook = (*perfHook)(nil)
+
+// perfHookKey identifies a provider call that has started, by the hook
+// method that started it, since OpenTofu Core might refresh and plan the
+// same object concurrently with other work on it.
+type perfHookKey struct {
+	addr   string
+	gen    states.Generation
+	method string
+}
+
+type perfHookCall struct {
+	call  string
+	start time.Time
+}
+
+func newPerfHook(profile *perf.Profile) *perfHook {
+	return &perfHook{
+		profile: profile,
+		pending: make(map[perfHookKey]perfHookCall),
+	}
+}
+
+func (h *perfHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, _ cty.Value) (tofu.HookAction, error) {
+	h.start(addr, gen, "refresh", "refresh")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, _ cty.Value, _ cty.Value) (tofu.HookAction, error) {
+	h.end(addr, gen, "refresh")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, _, _ cty.Value) (tofu.HookAction, error) {
+	h.start(addr, gen, "diff", "plan")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) PostDiff(addr addrs.AbsResourceInstance, gen states.Generation, _ plans.Action, _, _ cty.Value) (tofu.HookAction, error) {
+	h.end(addr, gen, "diff")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, _, _ cty.Value) (tofu.HookAction, error) {
+	// OpenTofu Core also reports reading data resources as applying them.
+	call := "apply"
+	if action == plans.Read {
+		call = "read"
+	}
+	h.start(addr, gen, "apply", call)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, _ cty.Value, _ error) (tofu.HookAction, error) {
+	h.end(addr, gen, "apply")
+	return tofu.HookActionContinue, nil
+}
+
+func (h *perfHook) start(addr addrs.AbsResourceInstance, gen states.Generation, method, call string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := perfHookKey{addr: addr.String(), gen: gen, method: method}
+	h.pending[key] = perfHookCall{call: call, start: time.Now()}
+}
+
+func (h *perfHook) end(addr addrs.AbsResourceInstance, gen states.Generation, method string) {
+	h.mu.Lock()
+	key := perfHookKey{addr: addr.String(), gen: gen, method: method}
+	started, ok := h.pending[key]
+	delete(h.pending, key)
+	h.mu.Unlock()
+
+	if ok {
+		h.profile.AddResourceCall(key.addr, started.call, time.Since(started.start))
+	}
+}
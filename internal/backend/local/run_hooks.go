@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/command/runhook"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// runHooks runs the operation's run hooks for the given event, with the
+// JSON representations of the given plan and new state, if non-nil, as part
+// of their input.
+func (b *Local) runHooks(ctx context.Context, op *backend.Operation, event runhook.Event, config *configs.Config, plan *plans.Plan, state *states.State, errored bool, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !op.RunHooks.Subscribed(event) {
+		return diags
+	}
+
+	input := &runhook.Input{
+		Workspace: op.Workspace,
+		Errored:   errored,
+	}
+	if plan != nil {
+		src, err := jsonplan.Marshal(config, plan, &statefile.File{State: plan.PriorState}, schemas)
+		if err != nil {
+			return diags.Append(fmt.Errorf("failed to encode the plan for run hooks: %w", err))
+		}
+		input.Plan = src
+	}
+	if state != nil {
+		src, err := jsonstate.Marshal(&statefile.File{State: state}, schemas)
+		if err != nil {
+			return diags.Append(fmt.Errorf("failed to encode the state for run hooks: %w", err))
+		}
+		input.State = src
+	}
+	return op.RunHooks.Run(ctx, event, input)
+}
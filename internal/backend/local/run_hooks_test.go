@@ -0,0 +1,127 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/runhook"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestLocal_applyRunHooks(t *testing.T) {
+	// The hook script used in this test assumes a Unix-like environment
+	// where scripts are directly executable based on their #! line.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+
+	dir := t.TempDir()
+	program := filepath.Join(dir, "hook")
+	script := "#!/bin/sh\ncat >> \"$1\"\necho >> \"$1\"\nif [ \"$2\" = reject ]; then echo 'rejected' >&2; exit 1; fi\n"
+	if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	newProvider := func(b *Local) *tofu.MockProvider {
+		p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+		p.ApplyResourceChangeResponse = &providers.ApplyResourceChangeResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+			"id":  cty.StringVal("yes"),
+			"ami": cty.StringVal("bar"),
+		})}
+		return p
+	}
+
+	t.Run("all events", func(t *testing.T) {
+		b := TestLocal(t)
+		newProvider(b)
+		inputPath := filepath.Join(t.TempDir(), "input")
+		op, done := testOperationApply(t, "./testdata/apply")
+		op.RunHooks = runhook.Hooks{
+			{Name: "audit", Program: program, Args: []string{inputPath}},
+		}
+
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		<-run.Done()
+		if run.Result != backend.OperationSuccess {
+			t.Fatalf("operation failed\n%s", done(t).Stderr())
+		}
+
+		f, err := os.Open(inputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var got []runhook.Event
+		var last runhook.Input
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var input runhook.Input
+			if err := json.Unmarshal(scanner.Bytes(), &input); err != nil {
+				t.Fatalf("invalid hook input: %s\n%s", err, scanner.Text())
+			}
+			if (input.Plan != nil) != (input.Event == runhook.EventPostPlan || input.Event == runhook.EventPreApply) {
+				t.Errorf("wrong plan for %s: %s", input.Event, input.Plan)
+			}
+			got = append(got, input.Event)
+			last = input
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatal(err)
+		}
+		want := []runhook.Event{runhook.EventPrePlan, runhook.EventPostPlan, runhook.EventPreApply, runhook.EventPostApply}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong events\n%s", diff)
+		}
+		if last.Errored || !strings.Contains(string(last.State), `"address":"test_instance.foo"`) {
+			t.Errorf("wrong post_apply input\nerrored: %t\nstate: %s", last.Errored, last.State)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		b := TestLocal(t)
+		p := newProvider(b)
+		inputPath := filepath.Join(t.TempDir(), "input")
+		op, done := testOperationApply(t, "./testdata/apply")
+		op.RunHooks = runhook.Hooks{
+			{Name: "tickets", Program: program, Args: []string{inputPath, "reject"}, Events: []runhook.Event{runhook.EventPreApply}},
+		}
+
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		<-run.Done()
+		if run.Result == backend.OperationSuccess {
+			t.Fatal("operation succeeded; want failure")
+		}
+		if !p.PlanResourceChangeCalled {
+			t.Error("plan should be called")
+		}
+		if p.ApplyResourceChangeCalled {
+			t.Error("apply should not be called")
+		}
+		if got, want := done(t).Stderr(), `The run hook "tickets" failed at pre_apply`; !strings.Contains(got, want) {
+			t.Errorf("wrong error output\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
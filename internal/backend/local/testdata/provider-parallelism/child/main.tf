@@ -0,0 +1,9 @@
+terraform {
+  required_providers {
+    fastly = {
+      source = "fastly/fastly"
+    }
+  }
+}
+
+resource "fastly_service_vcl" "foo" {}
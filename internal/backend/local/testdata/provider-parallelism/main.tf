@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    cf = {
+      source = "cloudflare/cloudflare"
+    }
+  }
+}
+
+resource "aws_instance" "foo" {}
+
+resource "cloudflare_record" "foo" {
+  provider = cf
+}
+
+module "child" {
+  source = "./child"
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// OrphanReportFormatVersion is the version of the JSON format of an
+// OrphanReport.
+const OrphanReportFormatVersion = "1.0"
+
+// OrphanReport lists the managed resource instances that are tracked in the
+// state but whose resource, or whole module, is no longer in the
+// configuration.
+type OrphanReport struct {
+	FormatVersion string              `json:"format_version"`
+	Orphans       []OrphanReportEntry `json:"orphans"`
+}
+
+// OrphanReportEntry describes a single resource instance in an OrphanReport.
+type OrphanReportEntry struct {
+	Address string `json:"address"`
+
+	// ModuleAddress is the address of the module instance that the resource
+	// instance belongs to, or an empty string for the root module.
+	// ModuleInConfiguration is false if that module itself was removed from
+	// the configuration, rather than just the resource.
+	ModuleAddress         string `json:"module_address,omitempty"`
+	ModuleInConfiguration bool   `json:"module_in_configuration"`
+
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+
+	// Tainted is true if the current object is tainted, and DeposedObjects
+	// is the number of deposed objects that are also still tracked.
+	Tainted        bool `json:"tainted,omitempty"`
+	DeposedObjects int  `json:"deposed_objects,omitempty"`
+
+	// PlannedAction is the action that the plan proposes for the current
+	// object, such as "delete" or "forget", or an empty string if the plan
+	// doesn't include the resource instance, for example because of
+	// targeting.
+	PlannedAction string `json:"planned_action,omitempty"`
+}
+
+// Save writes the report as JSON to the given path.
+func (r *OrphanReport) Save(path string) error {
+	src, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(src, '\n'), 0644)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 // httpClient is a remote client that stores data in Consul or HTTP REST.
@@ -110,10 +111,10 @@ func (c *httpClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string,
 		return info.ID, nil
 	case http.StatusUnauthorized:
 		log.Printf("[DEBUG] LOCK, Unauthorized: %s", parseResponseBodyForLog(resp))
-		return "", fmt.Errorf("HTTP remote state endpoint requires auth")
+		return "", tfdiags.ErrorWithCode(fmt.Errorf("HTTP remote state endpoint requires auth"), tfdiags.ErrorCodeAuthFailed)
 	case http.StatusForbidden:
 		log.Printf("[DEBUG] LOCK, Forbidden: %s", parseResponseBodyForLog(resp))
-		return "", fmt.Errorf("HTTP remote state endpoint invalid auth")
+		return "", tfdiags.ErrorWithCode(fmt.Errorf("HTTP remote state endpoint invalid auth"), tfdiags.ErrorCodeAuthFailed)
 	case http.StatusConflict, http.StatusLocked:
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
@@ -195,10 +196,10 @@ func (c *httpClient) Get(ctx context.Context) (*remote.Payload, error) {
 		return nil, nil
 	case http.StatusUnauthorized:
 		log.Printf("[DEBUG] GET STATE, Unauthorized: %s", parseResponseBodyForLog(resp))
-		return nil, fmt.Errorf("HTTP remote state endpoint requires auth")
+		return nil, tfdiags.ErrorWithCode(fmt.Errorf("HTTP remote state endpoint requires auth"), tfdiags.ErrorCodeAuthFailed)
 	case http.StatusForbidden:
 		log.Printf("[DEBUG] GET STATE, Forbidden: %s", parseResponseBodyForLog(resp))
-		return nil, fmt.Errorf("HTTP remote state endpoint invalid auth")
+		return nil, tfdiags.ErrorWithCode(fmt.Errorf("HTTP remote state endpoint invalid auth"), tfdiags.ErrorCodeAuthFailed)
 	case http.StatusInternalServerError:
 		log.Printf("[DEBUG] GET STATE, Internal Server Error: %s", parseResponseBodyForLog(resp))
 		return nil, fmt.Errorf("HTTP remote state internal server error")
@@ -42,7 +42,9 @@ func Reset() {
 	}
 
 	locks = lockMap{
-		m: map[string]*statemgr.LockInfo{},
+		m:       map[string]*statemgr.LockInfo{},
+		shared:  map[string]map[string]*statemgr.LockInfo{},
+		waiters: map[string]map[string]*statemgr.LockInfo{},
 	}
 }
 
@@ -185,6 +187,14 @@ type stateMap struct {
 type lockMap struct {
 	sync.Mutex
 	m map[string]*statemgr.LockInfo
+
+	// shared records, per state name, the holders of shared locks on that
+	// state, keyed by lock ID.
+	shared map[string]map[string]*statemgr.LockInfo
+
+	// waiters records, per state name, the callers currently waiting for
+	// the lock on that state, keyed by lock ID.
+	waiters map[string]map[string]*statemgr.LockInfo
 }
 
 func (l *lockMap) lock(name string, info *statemgr.LockInfo) (string, error) {
@@ -192,6 +202,10 @@ func (l *lockMap) lock(name string, info *statemgr.LockInfo) (string, error) {
 	defer l.Unlock()
 
 	lockInfo := l.m[name]
+	if lockInfo == nil && !info.Shared {
+		// An exclusive lock also conflicts with any shared lock holder.
+		lockInfo = l.firstShared(name)
+	}
 	if lockInfo != nil {
 		lockErr := &statemgr.LockError{
 			Info: lockInfo,
@@ -204,15 +218,42 @@ func (l *lockMap) lock(name string, info *statemgr.LockInfo) (string, error) {
 	}
 
 	info.Created = time.Now().UTC()
+	if info.Shared {
+		if l.shared[name] == nil {
+			l.shared[name] = map[string]*statemgr.LockInfo{}
+		}
+		l.shared[name][info.ID] = info
+		return info.ID, nil
+	}
 	l.m[name] = info
 
 	return info.ID, nil
 }
 
+// firstShared returns the shared lock holder with the lowest ID for the
+// named state, or nil if there are none. The caller must hold l's mutex.
+func (l *lockMap) firstShared(name string) *statemgr.LockInfo {
+	var first *statemgr.LockInfo
+	for _, info := range l.shared[name] {
+		if first == nil || info.ID < first.ID {
+			first = info
+		}
+	}
+	return first
+}
+
 func (l *lockMap) unlock(name, id string) error {
 	l.Lock()
 	defer l.Unlock()
 
+	if _, ok := l.shared[name][id]; ok {
+		delete(l.shared[name], id)
+		if len(l.shared[name]) == 0 {
+			delete(l.shared, name)
+		}
+		return nil
+	}
+
 	lockInfo := l.m[name]
 
 	if lockInfo == nil {
@@ -232,3 +273,46 @@ func (l *lockMap) unlock(name, id string) error {
 	delete(l.m, name)
 	return nil
 }
+
+// current returns a copy of the lock information for the holder of the named
+// lock, or nil if it is not locked.
+func (l *lockMap) current(name string) *statemgr.LockInfo {
+	l.Lock()
+	defer l.Unlock()
+
+	info := l.m[name]
+	if info == nil {
+		info = l.firstShared(name)
+	}
+	if info == nil {
+		return nil
+	}
+	ret := *info
+	return &ret
+}
+
+// addWaiter records info as waiting for the named lock and returns the number
+// of other callers waiting for it.
+func (l *lockMap) addWaiter(name string, info *statemgr.LockInfo) int {
+	l.Lock()
+	defer l.Unlock()
+
+	w := l.waiters[name]
+	if w == nil {
+		w = map[string]*statemgr.LockInfo{}
+		l.waiters[name] = w
+	}
+	w[info.ID] = info
+
+	return len(w) - 1
+}
+
+func (l *lockMap) removeWaiter(name, id string) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.waiters[name], id)
+	if len(l.waiters[name]) == 0 {
+		delete(l.waiters, name)
+	}
+}
@@ -8,6 +8,9 @@ package inmem
 import (
 	"context"
 	"crypto/md5"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -18,8 +21,14 @@ type RemoteClient struct {
 	Data []byte
 	MD5  []byte
 	Name string
+
+	// history is every state stored by Put, oldest first, so that earlier
+	// versions can be read back like they can from versioned storage.
+	history [][]byte
 }
 
+var _ remote.ClientVersions = (*RemoteClient)(nil)
+
 func (c *RemoteClient) Get(_ context.Context) (*remote.Payload, error) {
 	if c.Data == nil {
 		return nil, nil
@@ -36,18 +45,56 @@ func (c *RemoteClient) Put(_ context.Context, data []byte) error {
 
 	c.Data = data
 	c.MD5 = md5[:]
+	c.history = append(c.history, data)
 	return nil
 }
 
 func (c *RemoteClient) Delete(_ context.Context) error {
 	c.Data = nil
 	c.MD5 = nil
+	c.history = nil
 	return nil
 }
 
+// StateVersions returns identifiers of the form "v1", "v2", and so on for
+// each state stored by Put, newest first.
+func (c *RemoteClient) StateVersions(_ context.Context) ([]string, error) {
+	ids := make([]string, 0, len(c.history))
+	for i := len(c.history); i > 0; i-- {
+		ids = append(ids, fmt.Sprintf("v%d", i))
+	}
+	return ids, nil
+}
+
+func (c *RemoteClient) GetVersion(_ context.Context, id string) (*remote.Payload, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "v"))
+	if err != nil || !strings.HasPrefix(id, "v") || n < 1 || n > len(c.history) {
+		return nil, nil
+	}
+	data := c.history[n-1]
+	md5 := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  md5[:],
+	}, nil
+}
+
 func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string, error) {
 	return locks.lock(c.Name, info)
 }
 func (c *RemoteClient) Unlock(_ context.Context, id string) error {
 	return locks.unlock(c.Name, id)
 }
+
+func (c *RemoteClient) CurrentLock(_ context.Context) (*statemgr.LockInfo, error) {
+	return locks.current(c.Name), nil
+}
+
+func (c *RemoteClient) AddLockWaiter(_ context.Context, info *statemgr.LockInfo) (int, error) {
+	return locks.addWaiter(c.Name, info), nil
+}
+
+func (c *RemoteClient) RemoveLockWaiter(_ context.Context, id string) error {
+	locks.removeWaiter(c.Name, id)
+	return nil
+}
@@ -6,17 +6,24 @@
 package inmem
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/encryption"
+	statespkg "github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientLockWaiter = new(RemoteClient)
+	var _ remote.ClientVersions = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -40,3 +47,121 @@ func TestInmemLocks(t *testing.T) {
 
 	remote.TestRemoteLocks(t, s.(*remote.State).Client, s.(*remote.State).Client)
 }
+
+func TestInmemLockWaiters(t *testing.T) {
+	defer Reset()
+	s, err := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody()).StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter := s.(statemgr.LockWaiter)
+
+	first := statemgr.NewLockInfo()
+	second := statemgr.NewLockInfo()
+
+	if n, err := waiter.AddLockWaiter(t.Context(), first); err != nil || n != 0 {
+		t.Fatalf("first waiter: got %d, %v; want 0, nil", n, err)
+	}
+	if n, err := waiter.AddLockWaiter(t.Context(), second); err != nil || n != 1 {
+		t.Fatalf("second waiter: got %d, %v; want 1, nil", n, err)
+	}
+	// Re-registering must not count the same waiter twice.
+	if n, err := waiter.AddLockWaiter(t.Context(), first); err != nil || n != 1 {
+		t.Fatalf("refreshed first waiter: got %d, %v; want 1, nil", n, err)
+	}
+
+	if err := waiter.RemoveLockWaiter(t.Context(), first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := waiter.AddLockWaiter(t.Context(), second); err != nil || n != 0 {
+		t.Fatalf("after removal: got %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestInmemStateVersions(t *testing.T) {
+	defer Reset()
+	s, err := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody()).StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"first", "second"} {
+		state := statespkg.NewState()
+		state.RootModule().SetOutputValue("v", cty.StringVal(v), false, "")
+		if err := s.WriteState(state); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PersistState(t.Context(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reader := s.(statemgr.StateVersionReader)
+
+	for version, want := range map[string]string{"v1": "first", "v2": "second"} {
+		file, err := reader.ReadStateVersion(t.Context(), version)
+		if err != nil {
+			t.Fatalf("version %s: %s", version, err)
+		}
+		if got := file.State.RootModule().OutputValues["v"].Value; !got.RawEquals(cty.StringVal(want)) {
+			t.Errorf("version %s: got %#v, want %q", version, got, want)
+		}
+		// The same snapshot must also be found by its serial.
+		bySerial, err := reader.ReadStateVersion(t.Context(), fmt.Sprint(file.Serial))
+		if err != nil {
+			t.Fatalf("serial %d: %s", file.Serial, err)
+		}
+		if got := bySerial.State.RootModule().OutputValues["v"].Value; !got.RawEquals(cty.StringVal(want)) {
+			t.Errorf("serial %d: got %#v, want %q", file.Serial, got, want)
+		}
+	}
+
+	if _, err := reader.ReadStateVersion(t.Context(), "v3"); err == nil {
+		t.Error("expected error for a version that doesn't exist")
+	}
+	if _, err := reader.ReadStateVersion(t.Context(), "99"); err == nil {
+		t.Error("expected error for a serial that doesn't exist")
+	}
+}
+
+func TestInmemSharedLocks(t *testing.T) {
+	defer Reset()
+	s, err := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody()).StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared1 := statemgr.NewLockInfo()
+	shared1.Shared = true
+	shared2 := statemgr.NewLockInfo()
+	shared2.Shared = true
+
+	id1, err := s.Lock(t.Context(), shared1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := s.Lock(t.Context(), shared2)
+	if err != nil {
+		t.Fatal("second shared lock should succeed:", err)
+	}
+
+	if _, err := s.Lock(t.Context(), statemgr.NewLockInfo()); err == nil {
+		t.Fatal("exclusive lock should fail while shared locks are held")
+	}
+
+	if err := s.Unlock(t.Context(), id1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unlock(t.Context(), id2); err != nil {
+		t.Fatal(err)
+	}
+
+	exclusiveID, err := s.Lock(t.Context(), statemgr.NewLockInfo())
+	if err != nil {
+		t.Fatal("exclusive lock should succeed once shared locks are released:", err)
+	}
+	if _, err := s.Lock(t.Context(), shared1); err == nil {
+		t.Fatal("shared lock should fail while an exclusive lock is held")
+	}
+	if err := s.Unlock(t.Context(), exclusiveID); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -82,10 +82,15 @@ func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string,
 		info.ID = lockID
 	}
 
+	// A shared lock, requested by operations that only read the state, uses
+	// the shared variants of the advisory lock functions, so that several of
+	// them can hold the lock at once but none while an exclusive lock is held.
+	tryLockFunc, unlockFunc := advisoryLockFuncs(info.Shared)
+
 	// Local helper function so we can call it multiple places
 	//
 	lockUnlock := func(pgLockId string) error {
-		query := `SELECT pg_advisory_unlock($1)`
+		query := fmt.Sprintf(`SELECT %s($1)`, unlockFunc)
 		row := c.Client.QueryRow(query, pgLockId)
 		var didUnlock []byte
 		err := row.Scan(&didUnlock)
@@ -98,15 +103,17 @@ func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string,
 	creationLockID := c.composeCreationLockID()
 
 	// Try to acquire locks for the existing row `id` and the creation lock.
-	query := fmt.Sprintf(`SELECT %s.id, pg_try_advisory_lock(%s.id), pg_try_advisory_lock($1) FROM %s.%s WHERE %s.name = $2`,
-		pq.QuoteIdentifier(c.TableName), pq.QuoteIdentifier(c.TableName), pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName), pq.QuoteIdentifier(c.TableName))
+	query := fmt.Sprintf(`SELECT %s.id, %s(%s.id), %s($1) FROM %s.%s WHERE %s.name = $2`,
+		pq.QuoteIdentifier(c.TableName), tryLockFunc, pq.QuoteIdentifier(c.TableName), tryLockFunc, pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName), pq.QuoteIdentifier(c.TableName))
 
 	row := c.Client.QueryRow(query, creationLockID, c.Name)
 	var pgLockId, didLock, didLockForCreate []byte
 	err = row.Scan(&pgLockId, &didLock, &didLockForCreate)
 	switch {
 	case err == sql.ErrNoRows:
-		// No rows means we're creating the workspace. Take the creation lock.
+		// No rows means we're creating the workspace. Take the creation lock,
+		// which is always exclusive, even for a shared lock request.
+		info.Shared = false
 		query = `SELECT pg_try_advisory_lock($1)`
 		innerRow := c.Client.QueryRow(query, creationLockID)
 		var innerDidLock []byte
@@ -140,7 +147,8 @@ func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string,
 
 func (c *RemoteClient) Unlock(_ context.Context, id string) error {
 	if c.info != nil && c.info.Path != "" {
-		query := `SELECT pg_advisory_unlock($1)`
+		_, unlockFunc := advisoryLockFuncs(c.info.Shared)
+		query := fmt.Sprintf(`SELECT %s($1)`, unlockFunc)
 		row := c.Client.QueryRow(query, c.info.Path)
 		var didUnlock []byte
 		err := row.Scan(&didUnlock)
@@ -157,3 +165,12 @@ func (c *RemoteClient) composeCreationLockID() string {
 	hash.Write([]byte(c.SchemaName + "\x00" + c.TableName))
 	return fmt.Sprintf("%d", int64(hash.Sum32())*-1)
 }
+
+// advisoryLockFuncs returns the names of the Postgres functions that try to
+// take and release an advisory lock, either shared or exclusive.
+func advisoryLockFuncs(shared bool) (tryLock, unlock string) {
+	if shared {
+		return "pg_try_advisory_lock_shared", "pg_advisory_unlock_shared"
+	}
+	return "pg_try_advisory_lock", "pg_advisory_unlock"
+}
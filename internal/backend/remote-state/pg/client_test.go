@@ -16,6 +16,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -91,6 +92,78 @@ func TestRemoteLocks(t *testing.T) {
 	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 }
 
+func TestRemoteSharedLocks(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropSchema(t, dbCleaner, schemaName)
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":    connStr,
+		"schema_name": schemaName,
+	})
+
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	s1, err := b1.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The workspace must exist, or the first lock is the creation lock.
+	if err := s1.WriteState(states.NewState()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.PersistState(t.Context(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	s2, err := b2.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b3 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	s3, err := b3.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared1 := statemgr.NewLockInfo()
+	shared1.Shared = true
+	shared2 := statemgr.NewLockInfo()
+	shared2.Shared = true
+
+	id1, err := s1.Lock(t.Context(), shared1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := s2.Lock(t.Context(), shared2)
+	if err != nil {
+		t.Fatal("second shared lock should succeed:", err)
+	}
+	if _, err := s3.Lock(t.Context(), statemgr.NewLockInfo()); err == nil {
+		t.Fatal("exclusive lock should fail while shared locks are held")
+	}
+
+	if err := s1.Unlock(t.Context(), id1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.Unlock(t.Context(), id2); err != nil {
+		t.Fatal(err)
+	}
+
+	exclusiveID, err := s3.Lock(t.Context(), statemgr.NewLockInfo())
+	if err != nil {
+		t.Fatal("exclusive lock should succeed once shared locks are released:", err)
+	}
+	if err := s3.Unlock(t.Context(), exclusiveID); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestConcurrentCreationLocksInDifferentSchemas tests whether backends with different schemas
 // affect each other while taking global workspace creation locks.
 func TestConcurrentCreationLocksInDifferentSchemas(t *testing.T) {
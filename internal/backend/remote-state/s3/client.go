@@ -193,6 +193,77 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 	return payload, nil
 }
 
+// StateVersions implements remote.ClientVersions by listing the versions of
+// the state object, which S3 keeps when versioning is enabled for the bucket.
+func (c *RemoteClient) StateVersions(ctx context.Context) ([]string, error) {
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: &c.bucketName,
+		Prefix: &c.path,
+	}
+	var ids []string
+	for {
+		output, err := c.s3Client.ListObjectVersions(ctx, input)
+		if err != nil {
+			var nb *types.NoSuchBucket
+			if errors.As(err, &nb) {
+				return nil, fmt.Errorf(errS3NoSuchBucket, err)
+			}
+			return nil, err
+		}
+		// S3 lists the versions of each key newest first. The prefix also
+		// matches other keys, such as the lock file.
+		for _, v := range output.Versions {
+			if aws.ToString(v.Key) == c.path {
+				ids = append(ids, aws.ToString(v.VersionId))
+			}
+		}
+		if !aws.ToBool(output.IsTruncated) {
+			return ids, nil
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
+	}
+}
+
+// GetVersion implements remote.ClientVersions.
+func (c *RemoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.GetObjectInput{
+		Bucket:    &c.bucketName,
+		Key:       &c.path,
+		VersionId: aws.String(id),
+	}
+
+	if c.serverSideEncryption && c.customerEncryptionKey != nil {
+		input.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+		input.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
+		input.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+	}
+
+	output, err := c.s3Client.GetObject(ctx, input, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	if err != nil {
+		var nk *types.NoSuchKey
+		if errors.As(err, &nk) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buf, output.Body); err != nil {
+		return nil, fmt.Errorf("Failed to read remote state: %w", err)
+	}
+	sum := md5.Sum(buf.Bytes())
+	return &remote.Payload{
+		Data: buf.Bytes(),
+		MD5:  sum[:],
+	}, nil
+}
+
 func (c *RemoteClient) Put(ctx context.Context, data []byte) error {
 	contentLength := int64(len(data))
 
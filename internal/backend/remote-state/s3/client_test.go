@@ -32,6 +32,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientVersions = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -170,6 +170,46 @@ func (b *Remote) opApply(ctx context.Context, stopCtx, cancelCtx context.Context
 		))
 	}
 
+	if len(op.TargetSelectors) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-target-selector option is not supported",
+			"The -target-selector option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.AutoApprovePolicy != plans.NoAutoApprovePolicy {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-auto-approve-policy option is not supported",
+			"The -auto-approve-policy option is not currently supported for remote applies.",
+		))
+	}
+
+	if op.InteractiveReview {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-interactive-review option is not supported",
+			"The -interactive-review option is not currently supported for remote applies.",
+		))
+	}
+
+	if op.ApplyReport {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-continue-on-error option is not supported",
+			"The -continue-on-error and -failure-report options are not currently supported for remote applies.",
+		))
+	}
+
+	if op.StatePersistMode != backend.StatePersistPeriodically || op.StatePersistInterval != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-state-persist option is not supported",
+			"The -state-persist option is not currently supported for remote applies.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
@@ -137,6 +137,70 @@ func (b *Remote) opPlan(ctx, stopCtx, cancelCtx context.Context, op *backend.Ope
 		))
 	}
 
+	if len(op.TargetSelectors) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-target-selector option is not supported",
+			"The -target-selector option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.CascadeReplace {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-cascade option is not supported",
+			"The -cascade option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.AllowDeferral {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-allow-deferral option is not supported",
+			"The -allow-deferral option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ShowProvisioners {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-show-provisioners option is not supported",
+			"The -show-provisioners option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.PlanCache != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-incremental option is not supported",
+			"The -incremental option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ExportGraph {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-export-graph option is not supported",
+			"The -export-graph option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.StateVersion != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-state-version option is not supported",
+			"The -state-version option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ReportOrphans {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-report-orphans option is not supported",
+			"The -report-orphans option is not currently supported for remote plans.",
+		))
+	}
+
 	if !op.PlanRefresh {
 		desiredAPIVersion, _ := version.NewVersion("2.4")
 
@@ -87,6 +87,70 @@ func (b *Cloud) opPlan(ctx, stopCtx, cancelCtx context.Context, op *backend.Oper
 		))
 	}
 
+	if len(op.TargetSelectors) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-target-selector option is not supported",
+			"The -target-selector option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.CascadeReplace {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-cascade option is not supported",
+			"The -cascade option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.AllowDeferral {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-allow-deferral option is not supported",
+			"The -allow-deferral option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ShowProvisioners {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-show-provisioners option is not supported",
+			"The -show-provisioners option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.PlanCache != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-incremental option is not supported",
+			"The -incremental option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ExportGraph {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-export-graph option is not supported",
+			"The -export-graph option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.StateVersion != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-state-version option is not supported",
+			"The -state-version option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.ReportOrphans {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-report-orphans option is not supported",
+			"The -report-orphans option is not currently supported for remote plans.",
+		))
+	}
+
 	if len(op.GenerateConfigOut) > 0 {
 		diags = diags.Append(genconfig.ValidateTargetFile(op.GenerateConfigOut))
 	}
@@ -534,7 +598,7 @@ func (b *Cloud) renderPlanLogs(ctx context.Context, op *backend.Operation, run *
 
 	// Write any generated config before rendering the plan, so we can stop in case of errors
 	if shouldGenerateConfig {
-		diags := maybeWriteGeneratedConfig(redactedPlan, op.GenerateConfigOut)
+		diags := maybeWriteGeneratedConfig(redactedPlan, op.GenerateConfigOut, op.GenerateConfigTemplates)
 		if diags.HasErrors() {
 			return diags.Err()
 		}
@@ -553,7 +617,7 @@ func (b *Cloud) renderPlanLogs(ctx context.Context, op *backend.Operation, run *
 // maybeWriteGeneratedConfig attempts to write any generated configuration from the JSON plan
 // to the specified output file, if generated configuration exists and the correct flag was
 // passed to the plan command.
-func maybeWriteGeneratedConfig(plan *jsonformat.Plan, out string) (diags tfdiags.Diagnostics) {
+func maybeWriteGeneratedConfig(plan *jsonformat.Plan, out string, templates *genconfig.Templates) (diags tfdiags.Diagnostics) {
 	if genconfig.ShouldWriteConfig(out) {
 		diags := genconfig.ValidateTargetFile(out)
 		if diags.HasErrors() {
@@ -569,6 +633,9 @@ func maybeWriteGeneratedConfig(plan *jsonformat.Plan, out string) (diags tfdiags
 			if c.Change.Importing != nil {
 				change.ImportID = c.Change.Importing.ID
 			}
+			if moreDiags := templates.Apply(&change); moreDiags.HasErrors() {
+				return diags.Append(moreDiags)
+			}
 
 			var moreDiags tfdiags.Diagnostics
 			writer, _, moreDiags = change.MaybeWriteConfig(writer, out)
@@ -43,8 +43,15 @@ func LoadSavedPlanBookmark(filepath string) (SavedPlanBookmark, error) {
 	if err != nil {
 		return bookmark, err
 	}
+	return ParseSavedPlanBookmark(data)
+}
+
+// ParseSavedPlanBookmark is like LoadSavedPlanBookmark, but reads the bookmark
+// from the given bytes that have already been read from its file.
+func ParseSavedPlanBookmark(data []byte) (SavedPlanBookmark, error) {
+	bookmark := SavedPlanBookmark{}
 
-	err = json.Unmarshal(data, &bookmark)
+	err := json.Unmarshal(data, &bookmark)
 	if err != nil {
 		return bookmark, err
 	}
@@ -7,9 +7,14 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
@@ -63,6 +68,17 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	profileCommand := "apply"
+	if c.Destroy {
+		profileCommand = "destroy"
+	}
+	ctx, stopProfile, profileDiags := c.startProfile(ctx, profileCommand, args.ProfilePath, args.ProfileCPUPath)
+	if profileDiags.HasErrors() {
+		view.Diagnostics(profileDiags)
+		return 1
+	}
+	defer func() { view.Diagnostics(stopProfile()) }()
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -82,23 +98,28 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// If we're resuming an interrupted apply then the checkpoint it left
+	// behind tells us which saved plan to continue applying.
+	var checkpoint *backend.ApplyCheckpoint
+	if args.Resume {
+		checkpoint, diags = c.loadApplyCheckpoint(ctx)
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		args.PlanPath = checkpoint.PlanFile
+	}
+
 	// Attempt to load the plan file, if specified
-	planFile, diags := c.LoadPlanFile(args.PlanPath, enc)
+	planFile, diags := c.LoadPlanFile(args.PlanPath, args.RequireSignedPlan, enc)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
 		return 1
 	}
 
-	// Check for invalid combination of plan file and variable overrides
-	if planFile != nil && !args.Vars.Empty() {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Can't set variables when applying a saved plan",
-			"The -var and -var-file options cannot be used when applying a saved plan file, because a saved plan includes the variable values that were set when it was created.",
-		))
-		view.Diagnostics(diags)
-		return 1
-	}
+	// Variables can be set when applying a saved plan only to provide values
+	// for ephemeral variables, which the backend checks once it has loaded
+	// the configuration from the plan.
 
 	// FIXME: the -input flag value is needed to initialize the backend and the
 	// operation, but there is no clear path to pass this value down, so we
@@ -112,6 +133,13 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.providerParallelism = args.Operation.ProviderParallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
+
+	// The -apply-timeout is enforced while waiting for the operation, so
+	// likewise it must go through the Meta object.
+	c.Meta.applyTimeout = args.ApplyTimeout
+	c.Meta.applyTimeoutGrace = args.ApplyTimeoutGrace
 
 	// Prepare the backend, passing the plan file if present, and the
 	// backend-specific arguments
@@ -125,6 +153,32 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// Build the operation request
 	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewType, planFile, args.Operation, args.AutoApprove, enc)
 	diags = diags.Append(opDiags)
+	if opReq != nil {
+		opReq.PreviewDestroyOrder = args.PreviewOrder
+		opReq.AutoApprovePolicy = args.AutoApprovePolicy
+		opReq.InteractiveReview = args.InteractiveReview
+		opReq.ApplyReport = args.ContinueOnError
+		opReq.PolicyOverrideToken = args.PolicyOverrideToken
+		switch {
+		case args.StatePersistEachChange:
+			opReq.StatePersistMode = backend.StatePersistEachChange
+		case args.StatePersistOnCompletion:
+			opReq.StatePersistMode = backend.StatePersistOnCompletion
+		default:
+			opReq.StatePersistInterval = args.StatePersistInterval
+		}
+	}
+	if _, ok := planFile.Local(); ok && opReq != nil {
+		if checkpoint == nil {
+			checkpoint = &backend.ApplyCheckpoint{
+				Path:      filepath.Join(c.DataDir(), backend.ApplyCheckpointFilename),
+				PlanFile:  args.PlanPath,
+				Workspace: opReq.Workspace,
+			}
+		}
+		opReq.Checkpoint = checkpoint
+		opReq.ResumeApply = args.Resume
+	}
 
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
@@ -142,6 +196,10 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	if op.ApplyReport != nil {
+		view.Diagnostics(c.saveApplyReport(args.FailureReport, op.ApplyReport))
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
@@ -164,14 +222,25 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	return 0
 }
 
-func (c *ApplyCommand) LoadPlanFile(path string, enc encryption.Encryption) (*planfile.WrappedPlanFile, tfdiags.Diagnostics) {
+// LoadPlanFile loads the saved plan file at the given path, if any, after
+// making sure it hasn't been tampered with since it was created.
+//
+// The signature is verified against the same bytes that the plan is loaded
+// from, so that the file can't be replaced in between.
+func (c *ApplyCommand) LoadPlanFile(path string, requireSigned bool, enc encryption.Encryption) (*planfile.WrappedPlanFile, tfdiags.Diagnostics) {
 	var planFile *planfile.WrappedPlanFile
 	var diags tfdiags.Diagnostics
 
 	// Try to load plan if path is specified
 	if path != "" {
-		var err error
-		planFile, err = c.PlanFile(path, enc.Plan())
+		raw, err := c.readPlanFile(path)
+		if err == nil && raw != nil {
+			diags = diags.Append(c.verifyPlanFileSignature(path, raw, requireSigned))
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			planFile, err = planfile.OpenWrappedBytes(raw, enc.Plan())
+		}
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -208,6 +277,78 @@ func (c *ApplyCommand) LoadPlanFile(path string, enc encryption.Encryption) (*pl
 	return planFile, diags
 }
 
+// saveApplyReport writes the report requested with -failure-report, if any,
+// and summarizes it if any of the planned changes weren't applied.
+func (c *ApplyCommand) saveApplyReport(path string, report *backend.ApplyReport) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var saved string
+	if path != "" {
+		if err := report.Save(path); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write failure report",
+				fmt.Sprintf("Could not write the report of the applied changes to %s: %s.", path, err),
+			))
+		} else {
+			saved = fmt.Sprintf(" The report of each change was written to %s.", path)
+		}
+	}
+
+	if report.Incomplete() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Some planned changes were not applied",
+			fmt.Sprintf(
+				"%d of the planned changes succeeded, %d failed, and %d were never attempted, typically because they depend on a change that failed.%s\n\nTo review what's still needed to complete them, run:\n  %s",
+				len(report.Succeeded), len(report.Failed), len(report.Skipped), saved, report.SuggestedCommand(),
+			),
+		))
+	}
+	return diags
+}
+
+// loadApplyCheckpoint reads the checkpoint left behind by an interrupted
+// apply of a saved plan in the current working directory.
+func (c *ApplyCommand) loadApplyCheckpoint(ctx context.Context) (*backend.ApplyCheckpoint, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	path := filepath.Join(c.DataDir(), backend.ApplyCheckpointFilename)
+	checkpoint, err := backend.ReadApplyCheckpoint(path)
+	if errors.Is(err, os.ErrNotExist) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No apply to resume",
+			"There is no record of an interrupted apply in this working directory. Only the apply of a saved plan file can be resumed.",
+		))
+		return nil, diags
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read apply checkpoint",
+			fmt.Sprintf("Error: %s", err),
+		))
+		return nil, diags
+	}
+
+	workspace, err := c.Workspace(ctx)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Error selecting workspace: %w", err))
+		return nil, diags
+	}
+	if checkpoint.Workspace != workspace {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Apply checkpoint is for a different workspace",
+			fmt.Sprintf("The interrupted apply was for workspace %q, but the current workspace is %q. Select workspace %q to resume it.", checkpoint.Workspace, workspace, checkpoint.Workspace),
+		))
+		return nil, diags
+	}
+
+	return checkpoint, diags
+}
+
 func (c *ApplyCommand) PrepareBackend(ctx context.Context, planFile *planfile.WrappedPlanFile, args *arguments.State, viewType arguments.ViewType, enc encryption.StateEncryption) (backend.Enhanced, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -258,6 +399,13 @@ func (c *ApplyCommand) PrepareBackend(ctx context.Context, planFile *planfile.Wr
 	if beDiags.HasErrors() {
 		return nil, diags
 	}
+
+	// Check the workspace given with -workspace, if any, now that we know
+	// which backend it belongs to.
+	diags = diags.Append(c.prepareWorkspaceOverride(ctx, be))
+	if diags.HasErrors() {
+		return nil, diags
+	}
 	return be, diags
 }
 
@@ -288,7 +436,10 @@ func (c *ApplyCommand) OperationRequest(
 	opReq.PlanRefresh = args.Refresh
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
+	opReq.TargetSelectors = args.TargetSelectors
 	opReq.ForceReplace = args.ForceReplace
+	opReq.CascadeReplace = args.CascadeReplace
+	opReq.AllowDeferral = args.AllowDeferral
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
 
@@ -319,6 +470,53 @@ func (c *ApplyCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *ApplyCommand) AutocompleteArgs() complete.Predictor {
+	if c.Destroy {
+		return complete.PredictNothing
+	}
+	// The optional argument is a saved plan file.
+	return completePredictSequence{
+		complete.PredictFiles("*"),
+	}
+}
+
+func (c *ApplyCommand) AutocompleteFlags() complete.Flags {
+	ctx := c.CommandContext()
+	flags := complete.Flags{
+		"-apply-timeout":       complete.PredictAnything,
+		"-apply-timeout-grace": complete.PredictAnything,
+		"-auto-approve":        complete.PredictNothing,
+		"-backup":              complete.PredictFiles("*.tfstate"),
+		"-compact-warnings":    complete.PredictNothing,
+		"-continue-on-error":   complete.PredictNothing,
+		"-exclude":             c.completePredictResourceAddress(ctx),
+		"-failure-report":      complete.PredictFiles("*.json"),
+		"-input":               completePredictBoolean,
+		"-json":                complete.PredictNothing,
+		"-lock":                completePredictBoolean,
+		"-lock-timeout":        complete.PredictAnything,
+		"-no-color":            complete.PredictNothing,
+		"-parallelism":         complete.PredictAnything,
+		"-policy-override":     complete.PredictAnything,
+		"-profile":             complete.PredictFiles("*.json"),
+		"-profile-cpu":         complete.PredictFiles("*"),
+		"-refresh":             completePredictBoolean,
+		"-refresh-parallelism": complete.PredictAnything,
+		"-state":               complete.PredictFiles("*.tfstate"),
+		"-state-out":           complete.PredictFiles("*.tfstate"),
+		"-state-persist":       complete.PredictSet("resource", "completion"),
+		"-target":              c.completePredictResourceAddress(ctx),
+		"-var":                 c.completePredictVariableAssignment(ctx),
+		"-var-file":            complete.PredictFiles("*.tfvars"),
+	}
+	if !c.Destroy {
+		flags["-destroy"] = complete.PredictNothing
+		flags["-refresh-only"] = complete.PredictNothing
+		flags["-replace"] = c.completePredictResourceAddress(ctx)
+	}
+	return flags
+}
+
 func (c *ApplyCommand) Help() string {
 	if c.Destroy {
 		return c.helpDestroy()
@@ -348,10 +546,30 @@ Usage: tofu [global options] apply [options] [PLAN]
   OpenTofu will take the actions described in that plan without any
   confirmation prompt.
 
+  If applying a saved plan is interrupted, run "tofu apply -resume" to
+  continue from where it stopped instead of applying the whole plan again.
+
 Options:
 
+  -apply-timeout=0s      Stop the operation if it runs for longer than the
+                         given duration: OpenTofu starts no more changes,
+                         waits for the changes in progress, saves the state,
+                         and exits with status 15. Disabled by default.
+
+  -apply-timeout-grace=2m
+                         How long to wait for the changes in progress after
+                         the -apply-timeout is reached, before canceling
+                         them.
+
   -auto-approve          Skip interactive approval of plan before applying.
 
+  -auto-approve-policy=policy
+                         Skip interactive approval only if the plan is
+                         allowed by the policy: "no-destroy" for plans that
+                         don't delete or replace any objects, or
+                         "no-replace" for plans that don't replace any
+                         objects. Otherwise, ask for approval as usual.
+
   -backup=path           Path to backup the existing state file before
                          modifying. Defaults to the "-state-out" path with
                          ".backup" extension. Set to "-" to disable backup.
@@ -368,10 +586,29 @@ Options:
                          will be performed. All locations, for all errors
                          will be listed. Disabled by default
 
+  -continue-on-error     After applying, summarize which planned changes
+                         failed or were never attempted because they depend
+                         on a change that failed, with a plan command
+                         targeting them. OpenTofu always continues with the
+                         changes that don't depend on a failed one; this
+                         option only enables the summary.
+
   -destroy               Destroy OpenTofu-managed infrastructure.
                          The command "tofu destroy" is a convenience alias
                          for this option.
 
+  -failure-report=path   Write a JSON report of which planned changes
+                         succeeded, failed, or were never attempted because
+                         they depend on a change that failed, along with a
+                         plan command targeting the ones that weren't
+                         applied. Implies -continue-on-error.
+
+  -interactive-review    Before asking for approval, review the planned
+                         changes one by one, show the details of any of
+                         them, and deselect the ones that shouldn't be
+                         applied. OpenTofu then creates a new plan that
+                         excludes the deselected changes.
+
   -lock=false            Don't hold a state lock during the operation. This is
                          dangerous if others might concurrently run commands
                          against the same workspace.
@@ -385,7 +622,37 @@ Options:
   -concise               Disables progress-related messages in the output.
 
   -parallelism=n         Limit the number of parallel resource operations.
-                         Defaults to 10.
+                         Defaults to 10. Use provider=n, for example
+                         -parallelism=aws=10,cloudflare=2, to set a lower
+                         limit for a specific provider.
+
+  -policy-override=token Override the denials of the Rego policies from the
+                         CLI configuration that accept this override token.
+                         The denials are shown as warnings instead.
+
+  -profile=path          Write a JSON performance profile of the operation,
+                         with the time spent in each of its phases and on
+                         the provider calls of each resource instance. Use
+                         "tofu perf report" to show it.
+
+  -profile-cpu=path      Write a pprof CPU profile of the operation.
+
+  -refresh-parallelism=n Give the resources of each provider configuration
+                         their own limit of n concurrent operations while
+                         planning, instead of sharing the overall
+                         -parallelism limit, so that a slow provider doesn't
+                         hold up the others.
+
+  -preview-order         Before asking for approval, show the order in which
+                         the planned destroy actions will be applied, as
+                         waves derived from the dependency graph.
+
+  -resume                Continue an interrupted apply of a saved plan,
+                         skipping the changes that were already completed.
+
+  -require-signed-plan   Refuse to apply the saved plan unless it has a
+                         valid signature from a key in the CLI
+                         configuration.
 
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
@@ -394,8 +661,21 @@ Options:
                          "-state". This can be used to preserve the old
                          state.
 
+  -state-persist=when    How often to persist the state while applying:
+                         "resource" after each resource change,
+                         "completion" only when the apply completes, or a
+                         duration such as "5m" to persist at that interval.
+                         Defaults to the TF_STATE_PERSIST_INTERVAL
+                         environment variable, or 20 seconds.
+
   -show-sensitive        If specified, sensitive values will be displayed.
 
+  -workspace=name[,create]
+                         Use the given workspace for this command only,
+                         instead of the currently selected workspace. Add
+                         ",create" to create the workspace if it doesn't
+                         already exist.
+
   -json                  Produce output in a machine-readable JSON format,
                          suitable for use in text editor integrations and
                          other automated systems. Always disables color.
@@ -426,6 +706,9 @@ Usage: tofu [global options] destroy [options]
   This command is a convenience alias for:
       tofu apply -destroy
 
+  Use -preview-order to show the order in which the resources will be
+  destroyed, as waves derived from the dependency graph, before approving.
+
   This command also accepts many of the plan-customization options accepted by
   the tofu plan command. For more information on those options, run:
       tofu plan -help
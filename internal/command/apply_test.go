@@ -8,12 +8,14 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -23,9 +25,12 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -70,6 +75,59 @@ func TestApply(t *testing.T) {
 		t.Fatal("state should not be nil")
 	}
 }
+
+func TestApply_workspace(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	p := applyFixtureProvider()
+
+	// Without the "create" suffix, the workspace must already exist.
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-auto-approve", "-workspace=staging"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), `The workspace "staging" given with -workspace doesn't exist`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\n got: %s\nwant: %s", got, want)
+	}
+
+	view, done = testView(t)
+	c = &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code = c.Run([]string{"-auto-approve", "-workspace=staging,create"})
+	output = done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	state := testStateRead(t, filepath.Join(local.DefaultWorkspaceDir, "staging", DefaultStateFilename))
+	if state.Empty() {
+		t.Fatal("state of the staging workspace should not be empty")
+	}
+	if _, err := os.Stat(DefaultStateFilename); !os.IsNotExist(err) {
+		t.Fatalf("the default workspace's state was written: %v", err)
+	}
+
+	// The workspace is used only for this command, so it must not be
+	// recorded as the selected workspace.
+	if _, err := os.Stat(filepath.Join(DefaultDataDir, local.DefaultWorkspaceFile)); !os.IsNotExist(err) {
+		t.Fatalf("the selected workspace was changed: %v", err)
+	}
+}
+
 func TestApply_conditionalSensitive(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -173,6 +231,83 @@ func TestApply_approveNo(t *testing.T) {
 	}
 }
 
+func TestApply_autoApprovePolicy(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	defer testInputMap(t, map[string]string{
+		"approve": "no",
+	})()
+
+	run := func(t *testing.T, statePath string) (int, string) {
+		// Do not use the NewMockUi initializer here, as we want to delay
+		// the call to init until after setting up the input mocks
+		ui := new(cli.MockUi)
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		}
+		code := c.Run([]string{
+			"-auto-approve-policy=no-destroy",
+			"-no-color",
+			"-state", statePath,
+		})
+		return code, done(t).All()
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		statePath := testTempFile(t)
+		code, output := run(t, statePath)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output)
+		}
+		if _, err := os.Stat(statePath); err != nil {
+			t.Fatalf("state file should exist: %s", err)
+		}
+	})
+
+	t.Run("destroys objects", func(t *testing.T) {
+		statePath := testStateFile(t, states.BuildState(func(s *states.SyncState) {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: "gone",
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"gone"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+				addrs.NoKey,
+			)
+		}))
+		code, output := run(t, statePath)
+		if code != 1 {
+			t.Fatalf("bad: %d\n\n%s", code, output)
+		}
+		for _, want := range []string{
+			"Manual approval required",
+			"test_instance.gone.",
+			"Apply cancelled",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to include %q, but was:\n%s", want, output)
+			}
+		}
+	})
+}
+
 func TestApply_approveYes(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -543,6 +678,122 @@ func TestApply_error(t *testing.T) {
 	}
 }
 
+func TestApply_failureReport(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-failure-report"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+	reportPath := filepath.Join(td, "report.json")
+
+	p := applyFixtureProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		if req.PlannedState.GetAttr("ami").RawEquals(cty.StringVal("bad")) {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("bad ami"))
+			return
+		}
+		resp.NewState = cty.UnknownAsNull(req.PlannedState)
+		return
+	}
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-failure-report", reportPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, output.All())
+	}
+	if got, want := output.Stdout(), "Some planned changes were not applied"; !strings.Contains(got, want) {
+		t.Errorf("missing summary %q in output:\n%s", want, got)
+	}
+
+	src, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(src, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"succeeded": []interface{}{
+			map[string]interface{}{"address": "test_instance.good", "action": "create"},
+		},
+		"failed": []interface{}{
+			map[string]interface{}{"address": "test_instance.bad", "action": "create", "error": "bad ami"},
+		},
+		"skipped": []interface{}{
+			map[string]interface{}{"address": "test_instance.dependent", "action": "create"},
+		},
+		"suggested_command": "tofu plan -target='test_instance.bad' -target='test_instance.dependent'",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong report\n%s", diff)
+	}
+}
+
+func TestApply_continueOnError(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-failure-report"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		if req.PlannedState.GetAttr("ami").RawEquals(cty.StringVal("bad")) {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("bad ami"))
+			return
+		}
+		resp.NewState = cty.UnknownAsNull(req.PlannedState)
+		return
+	}
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-continue-on-error",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, output.All())
+	}
+	got := output.Stdout()
+	for _, want := range []string{
+		"Some planned changes were not applied",
+		"1 of the planned changes succeeded, 1 failed, and 1 were never attempted",
+		"tofu plan -target='test_instance.bad' -target='test_instance.dependent'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "The report of each change was written") {
+		t.Errorf("output mentions a report file without -failure-report:\n%s", got)
+	}
+}
+
 func TestApply_input(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -717,6 +968,304 @@ func TestApply_plan(t *testing.T) {
 	}
 }
 
+func TestApply_planSigned(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+	signing := planfile.SigningConfig{SigningKeyFile: testPlanSigningKey(t)}
+	if err := planfile.Sign(planPath, signing); err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			PlanSigning:      signing,
+		},
+	}
+
+	args := []string{
+		"-state-out", statePath,
+		"-require-signed-plan",
+		planPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestApply_planSignatureInvalid(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+	signing := planfile.SigningConfig{SigningKeyFile: testPlanSigningKey(t)}
+	if err := planfile.Sign(planPath, signing); err != nil {
+		t.Fatal(err)
+	}
+
+	// A signature from a key that isn't trusted is rejected even if signed
+	// plans aren't required.
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			PlanSigning:      planfile.SigningConfig{SigningKeyFile: testPlanSigningKey(t)},
+		},
+	}
+
+	code := c.Run([]string{"-state-out", statePath, planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Invalid plan file signature"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Fatal("state was written, but the plan should not have been applied")
+	}
+}
+
+func TestApply_planUnsignedRequired(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides:   metaOverridesForProvider(p),
+			View:               view,
+			PlanSigning:        planfile.SigningConfig{SigningKeyFile: testPlanSigningKey(t)},
+			RequireSignedPlans: true,
+		},
+	}
+
+	code := c.Run([]string{"-state-out", statePath, planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Plan file is not signed"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
+
+func TestApply_planExpired(t *testing.T) {
+	_, snap := testModuleWithSnapshot(t, "apply")
+	plan := testPlan(t)
+	plan.Timestamp = time.Now().Add(-2 * time.Hour)
+	plan.ExpiresAt = plan.Timestamp.Add(time.Hour)
+	planPath := testPlanFile(t, snap, states.NewState(), plan)
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state-out", statePath, planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Saved plan has expired"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if got, want := view.ErrorCode(), tfdiags.ErrorCodePlanStale; got != want {
+		t.Fatalf("wrong error code %q; want %q", got, want)
+	}
+}
+
+func TestApply_planResume(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	checkpoint := &backend.ApplyCheckpoint{
+		Path:      filepath.Join(td, DefaultDataDir, backend.ApplyCheckpointFilename),
+		PlanFile:  planPath,
+		Workspace: backend.DefaultStateName,
+		Pending:   []string{"test_instance.foo"},
+	}
+	if err := checkpoint.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state-out", statePath,
+		"-resume",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	state := testStateRead(t, statePath)
+	if state.ResourceInstance(addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "foo"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)) == nil {
+		t.Fatalf("resumed apply didn't create test_instance.foo")
+	}
+	if _, err := os.Stat(checkpoint.Path); !os.IsNotExist(err) {
+		t.Fatalf("checkpoint should be removed after a successful apply, got: %v", err)
+	}
+}
+
+func TestApply_planResumeJSON(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	checkpoint := &backend.ApplyCheckpoint{
+		Path:      filepath.Join(td, DefaultDataDir, backend.ApplyCheckpointFilename),
+		PlanFile:  planPath,
+		Workspace: backend.DefaultStateName,
+		Pending:   []string{"test_instance.foo"},
+		Attempts:  1,
+	}
+	if err := checkpoint.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state-out", statePath,
+		"-resume",
+		"-json",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.All())
+	}
+
+	// The resumed change is the second attempt at applying it, and the
+	// progress of the apply as a whole is reported as it finishes.
+	var sawStart, sawProgress bool
+	for _, line := range strings.Split(output.Stdout(), "\n") {
+		if line == "" {
+			continue
+		}
+		var msg struct {
+			Type string                 `json:"type"`
+			Hook map[string]interface{} `json:"hook"`
+		}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid JSON output line %q: %s", line, err)
+		}
+		switch msg.Type {
+		case "apply_start":
+			sawStart = true
+			if got, want := msg.Hook["retries"], float64(1); got != want {
+				t.Errorf("wrong retries %v in apply_start; want %v", got, want)
+			}
+		case "apply_overall_progress":
+			if msg.Hook["completed"] == float64(1) {
+				sawProgress = true
+			}
+		}
+	}
+	if !sawStart || !sawProgress {
+		t.Fatalf("missing apply_start or apply_overall_progress messages\n%s", output.Stdout())
+	}
+}
+
+func TestApply_planResumeStateChanged(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	planPath := applyFixturePlanFile(t)
+
+	// The interrupted apply last persisted a later snapshot than the
+	// current state, so something else has replaced the state since.
+	checkpoint := &backend.ApplyCheckpoint{
+		Path:      filepath.Join(td, DefaultDataDir, backend.ApplyCheckpointFilename),
+		PlanFile:  planPath,
+		Workspace: backend.DefaultStateName,
+		Pending:   []string{"test_instance.foo"},
+		Lineage:   "interrupted-apply",
+		Serial:    3,
+	}
+	if err := checkpoint.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-resume"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "State changed since the interrupted apply"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("provider was asked to apply a change")
+	}
+}
+
+func TestApply_planResumeWithoutCheckpoint(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-resume"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "No apply to resume"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestApply_plan_backup(t *testing.T) {
 	statePath := testTempFile(t)
 	backupPath := testTempFile(t)
@@ -1169,6 +1718,78 @@ func TestApply_shutdown(t *testing.T) {
 	}
 }
 
+func TestApply_applyTimeout(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-shutdown"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+	p := testProvider()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			ShutdownCh:       make(chan struct{}),
+		},
+	}
+
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return
+	}
+	var applied atomic.Int32
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		// The first change is still in progress when the timeout is
+		// reached, so it completes but the second one never starts.
+		applied.Add(1)
+		time.Sleep(500 * time.Millisecond)
+		resp.NewState = req.PlannedState
+		return
+	}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"ami": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-apply-timeout=100ms",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stderr(), "Apply timed out"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if got, want := view.ErrorCode(), tfdiags.ErrorCodeApplyTimeout; got != want {
+		t.Fatalf("wrong error code %q; want %q", got, want)
+	}
+	if got, want := int(applied.Load()), 1; got != want {
+		t.Fatalf("wrong number of applied changes %d; want %d", got, want)
+	}
+
+	// The change that completed is saved in the state.
+	state := testStateRead(t, statePath)
+	if state.ResourceInstance(mustResourceInstanceAddr("test_instance.foo")) == nil {
+		t.Fatalf("completed change is missing from the state:\n%s", state)
+	}
+	if state.ResourceInstance(mustResourceInstanceAddr("test_instance.bar")) != nil {
+		t.Fatalf("unexpected change in the state:\n%s", state)
+	}
+}
+
 func TestApply_state(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -2055,6 +2676,69 @@ func TestApply_excluded(t *testing.T) {
 	}
 }
 
+func TestApply_interactiveReview(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-excluded"), td)
+	t.Chdir(td)
+
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		return providers.PlanResourceChangeResponse{
+			PlannedState: req.ProposedNewState,
+		}
+	}
+
+	// The changes are listed in address order, so test_instance.bar is the
+	// first one. After it's deselected, OpenTofu asks us to review the new
+	// plan without it.
+	defer testInteractiveInput(t, []string{"show 1", "1", "yes", "yes"})()
+
+	// Do not use the NewMockUi initializer here, as we want to delay
+	// the call to init until after setting up the input mocks
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-interactive-review", "-no-color"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.All())
+	}
+
+	uiOutput := ui.OutputWriter.String()
+	for _, want := range []string{
+		"1. [x] test_instance.bar will be created",
+		"1. [ ] test_instance.bar will be created",
+		"OpenTofu has created a new plan without the deselected changes",
+		"1. [x] test_instance.baz will be created",
+	} {
+		if !strings.Contains(uiOutput, want) {
+			t.Errorf("expected output to include %q, but was:\n%s", want, uiOutput)
+		}
+	}
+	if got, want := output.Stdout(), "3 added, 0 changed, 0 destroyed"; !strings.Contains(got, want) {
+		t.Fatalf("bad change summary, want %q, got:\n%s", want, got)
+	}
+}
+
 // Diagnostics for invalid -exclude flags
 func TestApply_excludeFlagsDiags(t *testing.T) {
 	testCases := map[string]string{
@@ -7,6 +7,7 @@ package arguments
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -22,6 +23,10 @@ type Apply struct {
 	// AutoApprove skips the manual verification step for the apply operation.
 	AutoApprove bool
 
+	// AutoApprovePolicy skips the manual verification step only for plans
+	// that the policy approves, such as plans that don't destroy anything.
+	AutoApprovePolicy plans.AutoApprovePolicy
+
 	// InputEnabled is used to disable interactive input for unspecified
 	// variable and backend config values. Default is true.
 	InputEnabled bool
@@ -29,6 +34,67 @@ type Apply struct {
 	// PlanPath contains an optional path to a stored plan file
 	PlanPath string
 
+	// Resume continues applying the saved plan recorded in the working
+	// directory's apply checkpoint, skipping the changes that were already
+	// completed by the interrupted apply.
+	Resume bool
+
+	// RequireSignedPlan refuses to apply the saved plan unless it has a
+	// valid signature from one of the keys in the CLI configuration.
+	RequireSignedPlan bool
+
+	// PreviewOrder shows the order in which the planned destroy actions
+	// will be applied, as waves of objects that can be destroyed together,
+	// before asking for approval.
+	PreviewOrder bool
+
+	// InteractiveReview lets the user review the planned changes one by one
+	// and deselect some of them before approving.
+	InteractiveReview bool
+
+	// ContinueOnError asks for a summary of the planned changes that
+	// failed or were skipped after applying them. Applying already
+	// continues with the changes that don't depend on a failed one, so this
+	// only enables the report.
+	ContinueOnError bool
+
+	// FailureReport, if set, is the path where a report of the planned
+	// changes that succeeded, failed, or were skipped is written after
+	// applying them. It implies ContinueOnError.
+	FailureReport string
+
+	// PolicyOverrideToken, if set, overrides the denials of the Rego
+	// policies from the CLI configuration whose override token it is.
+	PolicyOverrideToken string
+
+	// StatePersistEachChange, StatePersistOnCompletion, and
+	// StatePersistInterval set how often intermediate state snapshots are
+	// persisted while applying: after each resource instance change, only
+	// once the apply completes, or at the given interval. If none is set,
+	// the backend's default interval is used.
+	StatePersistEachChange   bool
+	StatePersistOnCompletion bool
+	StatePersistInterval     time.Duration
+
+	// ApplyTimeout, if nonzero, is how long the operation can run before
+	// OpenTofu stops starting new changes and waits for the changes in
+	// progress to complete, for at most ApplyTimeoutGrace.
+	ApplyTimeout time.Duration
+
+	// ApplyTimeoutGrace is how long to wait for the changes in progress
+	// after ApplyTimeout is reached, before canceling them. If it's zero,
+	// DefaultApplyTimeoutGrace is used.
+	ApplyTimeoutGrace time.Duration
+
+	// ProfilePath, if set, is the path where a performance profile of the
+	// operation is written, with the time spent in each of its phases and on
+	// the provider calls of each resource instance.
+	ProfilePath string
+
+	// ProfileCPUPath, if set, is the path where a pprof CPU profile of the
+	// operation is written.
+	ProfileCPUPath string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 
@@ -39,6 +105,11 @@ type Apply struct {
 	ModuleDeprecationWarnings string
 }
 
+// DefaultApplyTimeoutGrace is how long OpenTofu waits for the changes in
+// progress after the -apply-timeout is reached, unless -apply-timeout-grace
+// says otherwise.
+const DefaultApplyTimeoutGrace = 2 * time.Minute
+
 // ParseApply processes CLI arguments, returning an Apply value and errors.
 // If errors are encountered, an Apply value is still returned representing
 // the best effort interpretation of the arguments.
@@ -52,8 +123,23 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 
 	cmdFlags := extendedFlagSet("apply", apply.State, apply.Operation, apply.Vars)
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
+	var autoApprovePolicy string
+	cmdFlags.StringVar(&autoApprovePolicy, "auto-approve-policy", "", "auto-approve-policy")
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
 	cmdFlags.BoolVar(&apply.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.BoolVar(&apply.Resume, "resume", false, "resume")
+	cmdFlags.BoolVar(&apply.RequireSignedPlan, "require-signed-plan", false, "require-signed-plan")
+	cmdFlags.BoolVar(&apply.PreviewOrder, "preview-order", false, "preview-order")
+	cmdFlags.BoolVar(&apply.InteractiveReview, "interactive-review", false, "interactive-review")
+	cmdFlags.BoolVar(&apply.ContinueOnError, "continue-on-error", false, "continue-on-error")
+	cmdFlags.StringVar(&apply.FailureReport, "failure-report", "", "failure-report")
+	cmdFlags.StringVar(&apply.PolicyOverrideToken, "policy-override", "", "policy-override")
+	var statePersist string
+	cmdFlags.StringVar(&statePersist, "state-persist", "", "state-persist")
+	cmdFlags.DurationVar(&apply.ApplyTimeout, "apply-timeout", 0, "apply-timeout")
+	cmdFlags.DurationVar(&apply.ApplyTimeoutGrace, "apply-timeout-grace", 0, "apply-timeout-grace")
+	cmdFlags.StringVar(&apply.ProfilePath, "profile", "", "profile")
+	cmdFlags.StringVar(&apply.ProfileCPUPath, "profile-cpu", "", "profile-cpu")
 	cmdFlags.StringVar(&apply.ModuleDeprecationWarnings, "deprecation", "", "control the level of deprecation warnings")
 
 	var json bool
@@ -81,6 +167,124 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	if apply.FailureReport != "" {
+		apply.ContinueOnError = true
+	}
+
+	if apply.Resume && apply.PlanPath != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file not allowed with -resume",
+			"The -resume option continues applying the saved plan recorded by the interrupted apply, so a plan file can't also be given.",
+		))
+	}
+
+	if apply.RequireSignedPlan && apply.PlanPath == "" && !apply.Resume {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required with -require-signed-plan",
+			"The -require-signed-plan option verifies the signature of a saved plan file, so it can only be used when applying a saved plan.",
+		))
+	}
+
+	switch {
+	case apply.ApplyTimeout < 0:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid apply timeout",
+			"The -apply-timeout option must be a positive duration, such as \"30m\" or \"2h\".",
+		))
+	case apply.ApplyTimeoutGrace < 0:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid apply timeout grace period",
+			"The -apply-timeout-grace option must be a positive duration, such as \"5m\".",
+		))
+	case apply.ApplyTimeoutGrace > 0 && apply.ApplyTimeout == 0:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Apply timeout required with -apply-timeout-grace",
+			"The -apply-timeout-grace option sets how long to wait for the changes in progress after the -apply-timeout is reached, so it can only be used with -apply-timeout.",
+		))
+	}
+
+	switch statePersist {
+	case "":
+	case "resource":
+		apply.StatePersistEachChange = true
+	case "completion":
+		apply.StatePersistOnCompletion = true
+	default:
+		interval, err := time.ParseDuration(statePersist)
+		if err != nil || interval <= 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid -state-persist option",
+				"The -state-persist option must be \"resource\" to persist the state after each resource change, \"completion\" to persist it only when the apply completes, or a positive duration, such as \"5m\", to persist it at that interval.",
+			))
+			break
+		}
+		apply.StatePersistInterval = interval
+	}
+
+	if policy, err := plans.ParseAutoApprovePolicy(autoApprovePolicy); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid -auto-approve-policy option",
+			fmt.Sprintf("The -auto-approve-policy option must be either %q or %q.", plans.AutoApproveNoDestroy, plans.AutoApproveNoReplace),
+		))
+	} else {
+		apply.AutoApprovePolicy = policy
+	}
+
+	if apply.AutoApprovePolicy != plans.NoAutoApprovePolicy {
+		switch {
+		case apply.AutoApprove:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible approval options",
+				"The -auto-approve option skips approval for every plan, so it can't be combined with -auto-approve-policy.",
+			))
+		case apply.PlanPath != "" || apply.Resume:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Plan file not allowed with -auto-approve-policy",
+				"A saved plan is applied without asking for approval, so the -auto-approve-policy option can only be used when creating a new plan.",
+			))
+		}
+	}
+
+	if apply.PreviewOrder && (apply.PlanPath != "" || apply.Resume) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file not allowed with -preview-order",
+			"The -preview-order option shows the destroy order of a new plan before asking for approval, so it can't be used when applying a saved plan.",
+		))
+	}
+
+	if apply.InteractiveReview {
+		switch {
+		case apply.AutoApprove || apply.AutoApprovePolicy != plans.NoAutoApprovePolicy:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible approval options",
+				"The -interactive-review option asks for approval after reviewing the plan, so it can't be combined with -auto-approve or -auto-approve-policy.",
+			))
+		case apply.PlanPath != "" || apply.Resume:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Plan file not allowed with -interactive-review",
+				"A saved plan is applied without asking for approval, so the -interactive-review option can only be used when creating a new plan.",
+			))
+		case !apply.InputEnabled && !json:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Input required for -interactive-review",
+				"The -interactive-review option asks questions interactively, so it can't be combined with -input=false.",
+			))
+		}
+	}
+
 	// JSON view currently does not support input, so we disable it here.
 	if json {
 		apply.InputEnabled = false
@@ -89,7 +293,7 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	// JSON view cannot confirm apply, so we require either a plan file or
 	// auto-approve to be specified. We intentionally fail here rather than
 	// override auto-approve, which would be dangerous.
-	if json && apply.PlanPath == "" && !apply.AutoApprove {
+	if json && apply.PlanPath == "" && !apply.Resume && !apply.AutoApprove {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Plan file or auto-approve required",
@@ -99,6 +303,25 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 
 	diags = diags.Append(apply.Operation.Parse())
 
+	if apply.InteractiveReview {
+		switch {
+		case len(apply.Operation.Targets) > 0 || len(apply.Operation.TargetSelectors) > 0:
+			// Deselected changes are excluded from the new plan, and the
+			// target and exclude options can't be used together.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Targeting not allowed with -interactive-review",
+				"The -interactive-review option excludes the changes that you deselect from a new plan, so it can't be combined with the -target, -target-file, or -target-selector options.",
+			))
+		case apply.Operation.PlanMode == plans.RefreshOnlyMode:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Refresh-only mode not allowed with -interactive-review",
+				"The -interactive-review option reviews the planned changes to resources, which a refresh-only plan doesn't have.",
+			))
+		}
+	}
+
 	switch {
 	case json:
 		apply.ViewType = ViewJSON
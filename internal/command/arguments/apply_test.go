@@ -8,6 +8,7 @@ package arguments
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -71,6 +72,192 @@ func TestParseApply_basicValid(t *testing.T) {
 				},
 			},
 		},
+		"resume": {
+			[]string{"-resume"},
+			&Apply{
+				AutoApprove:  false,
+				InputEnabled: true,
+				Resume:       true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"require signed plan": {
+			[]string{"-require-signed-plan", "saved.tfplan"},
+			&Apply{
+				AutoApprove:       false,
+				InputEnabled:      true,
+				PlanPath:          "saved.tfplan",
+				RequireSignedPlan: true,
+				ViewType:          ViewHuman,
+				State:             &State{Lock: true},
+				Vars:              &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"preview order": {
+			[]string{"-preview-order"},
+			&Apply{
+				AutoApprove:  false,
+				InputEnabled: true,
+				PreviewOrder: true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"interactive review": {
+			[]string{"-interactive-review"},
+			&Apply{
+				InputEnabled:      true,
+				InteractiveReview: true,
+				ViewType:          ViewHuman,
+				State:             &State{Lock: true},
+				Vars:              &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"continue on error": {
+			[]string{"-continue-on-error"},
+			&Apply{
+				ContinueOnError: true,
+				InputEnabled:    true,
+				ViewType:        ViewHuman,
+				State:           &State{Lock: true},
+				Vars:            &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"failure report": {
+			[]string{"-failure-report=report.json"},
+			&Apply{
+				ContinueOnError: true,
+				FailureReport:   "report.json",
+				InputEnabled:    true,
+				ViewType:        ViewHuman,
+				State:           &State{Lock: true},
+				Vars:            &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"policy override": {
+			[]string{"-policy-override=let-me-through"},
+			&Apply{
+				PolicyOverrideToken: "let-me-through",
+				InputEnabled:        true,
+				ViewType:            ViewHuman,
+				State:               &State{Lock: true},
+				Vars:                &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"state persist each change": {
+			[]string{"-state-persist=resource"},
+			&Apply{
+				StatePersistEachChange: true,
+				InputEnabled:           true,
+				ViewType:               ViewHuman,
+				State:                  &State{Lock: true},
+				Vars:                   &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"state persist on completion": {
+			[]string{"-state-persist=completion"},
+			&Apply{
+				StatePersistOnCompletion: true,
+				InputEnabled:             true,
+				ViewType:                 ViewHuman,
+				State:                    &State{Lock: true},
+				Vars:                     &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"state persist interval": {
+			[]string{"-state-persist=5m"},
+			&Apply{
+				StatePersistInterval: 5 * time.Minute,
+				InputEnabled:         true,
+				ViewType:             ViewHuman,
+				State:                &State{Lock: true},
+				Vars:                 &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"auto-approve policy": {
+			[]string{"-auto-approve-policy=no-destroy"},
+			&Apply{
+				AutoApprovePolicy: plans.AutoApproveNoDestroy,
+				InputEnabled:      true,
+				ViewType:          ViewHuman,
+				State:             &State{Lock: true},
+				Vars:              &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"apply timeout": {
+			[]string{"-apply-timeout=45m", "-apply-timeout-grace=5m"},
+			&Apply{
+				InputEnabled:      true,
+				ApplyTimeout:      45 * time.Minute,
+				ApplyTimeoutGrace: 5 * time.Minute,
+				ViewType:          ViewHuman,
+				State:             &State{Lock: true},
+				Vars:              &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
 		"JSON view disables input": {
 			[]string{"-json", "-auto-approve"},
 			&Apply{
@@ -121,6 +308,10 @@ func TestParseApply_json(t *testing.T) {
 			[]string{"-json", "saved.tfplan"},
 			true,
 		},
+		"-json -resume": {
+			[]string{"-json", "-resume"},
+			true,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -157,6 +348,158 @@ func TestParseApply_invalid(t *testing.T) {
 	}
 }
 
+func TestParseApply_resumeWithPlanFile(t *testing.T) {
+	_, diags := ParseApply([]string{"-resume", "saved.tfplan"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Plan file not allowed with -resume"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParseApply_requireSignedPlanWithoutPlanFile(t *testing.T) {
+	_, diags := ParseApply([]string{"-require-signed-plan"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Plan file required with -require-signed-plan"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParseApply_previewOrderWithPlanFile(t *testing.T) {
+	_, diags := ParseApply([]string{"-preview-order", "saved.tfplan"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Plan file not allowed with -preview-order"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParseApply_applyTimeoutInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"negative timeout": {
+			[]string{"-apply-timeout=-1m"},
+			"Invalid apply timeout",
+		},
+		"negative grace period": {
+			[]string{"-apply-timeout=1h", "-apply-timeout-grace=-1m"},
+			"Invalid apply timeout grace period",
+		},
+		"grace period without timeout": {
+			[]string{"-apply-timeout-grace=1m"},
+			"Apply timeout required with -apply-timeout-grace",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseApply(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseApply_autoApprovePolicyInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want string
+	}{
+		"unsupported policy": {
+			[]string{"-auto-approve-policy=always"},
+			"Invalid -auto-approve-policy option",
+		},
+		"with auto-approve": {
+			[]string{"-auto-approve-policy=no-replace", "-auto-approve"},
+			"Incompatible approval options",
+		},
+		"with plan file": {
+			[]string{"-auto-approve-policy=no-destroy", "saved.tfplan"},
+			"Plan file not allowed with -auto-approve-policy",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseApply(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, tc.want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseApply_interactiveReviewInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want string
+	}{
+		"with auto-approve": {
+			[]string{"-interactive-review", "-auto-approve"},
+			"Incompatible approval options",
+		},
+		"with auto-approve policy": {
+			[]string{"-interactive-review", "-auto-approve-policy=no-destroy"},
+			"Incompatible approval options",
+		},
+		"with plan file": {
+			[]string{"-interactive-review", "saved.tfplan"},
+			"Plan file not allowed with -interactive-review",
+		},
+		"without input": {
+			[]string{"-interactive-review", "-input=false"},
+			"Input required for -interactive-review",
+		},
+		"with target": {
+			[]string{"-interactive-review", "-target=foo_bar.baz"},
+			"Targeting not allowed with -interactive-review",
+		},
+		"refresh-only": {
+			[]string{"-interactive-review", "-refresh-only"},
+			"Refresh-only mode not allowed with -interactive-review",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseApply(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, tc.want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseApply_statePersistInvalid(t *testing.T) {
+	for _, value := range []string{"sometimes", "0s", "-5m"} {
+		t.Run(value, func(t *testing.T) {
+			_, diags := ParseApply([]string{"-state-persist=" + value})
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got, want := diags.Err().Error(), "Invalid -state-persist option"; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
 func TestParseApply_tooManyArguments(t *testing.T) {
 	got, diags := ParseApply([]string{"saved.tfplan", "please"})
 	if len(diags) == 0 {
@@ -701,6 +1044,24 @@ func TestParseApply_replace(t *testing.T) {
 	}
 }
 
+func TestParseApply_replaceCascade(t *testing.T) {
+	got, diags := ParseApply([]string{"-replace=foo_bar.baz", "-cascade"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if !got.Operation.CascadeReplace {
+		t.Fatal("expected CascadeReplace to be set")
+	}
+
+	_, diags = ParseApply([]string{"-cascade"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Replace address required with -cascade"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParseApply_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -19,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 // DefaultParallelism is the limit OpenTofu places on total parallel
@@ -36,6 +37,10 @@ type State struct {
 	// The default is 0, meaning no limit.
 	LockTimeout time.Duration
 
+	// LockTimeoutSet is true if LockTimeout was given explicitly, rather
+	// than being left at its default.
+	LockTimeoutSet bool
+
 	// StatePath specifies a non-default location for the state file. The
 	// default value is blank, which is interpreted as "terraform.tfstate".
 	StatePath string
@@ -50,6 +55,16 @@ type State struct {
 	// which is interpreted as StateOutPath +
 	// ".backup".
 	BackupPath string
+
+	// Workspace selects the workspace to use for this command only, without
+	// changing the workspace selected by "tofu workspace select". The
+	// default value is blank, which means to use the selected workspace.
+	//
+	// CreateWorkspace is set if the workspace should be created if it
+	// doesn't exist yet, which is requested with the "create" suffix as in
+	// -workspace=name,create.
+	Workspace       string
+	CreateWorkspace bool
 }
 
 // Operation describes arguments which are used to configure how a OpenTofu
@@ -64,6 +79,18 @@ type Operation struct {
 	// as it walks the dependency graph.
 	Parallelism int
 
+	// ProviderParallelism optionally limits the parallel operations for
+	// the resources of specific providers, within the overall Parallelism.
+	// The providers are named as given on the command line, either by a
+	// local name from required_providers or by a source address, and are
+	// only resolved once the configuration is loaded.
+	ProviderParallelism map[string]int
+
+	// RefreshParallelism, if nonzero, gives the resources of each provider
+	// configuration their own pool of this many concurrent operations while
+	// refreshing and planning, instead of sharing the overall Parallelism.
+	RefreshParallelism int
+
 	// Refresh controls whether or not the operation should refresh existing
 	// state before proceeding. Default is true.
 	Refresh bool
@@ -76,6 +103,10 @@ type Operation struct {
 	// than a set of excluded resource addresses and resources dependent on them.
 	Excludes []addrs.Targetable
 
+	// TargetSelectors select additional resource instances to target based
+	// on the attribute values recorded for them in the prior state.
+	TargetSelectors []tofu.TargetSelector
+
 	// ForceReplace addresses cause OpenTofu to force a particular set of
 	// resource instances to generate "replace" actions in any plan where they
 	// would normally have generated "no-op" or "update" actions.
@@ -88,6 +119,15 @@ type Operation struct {
 	// learn a use-case for broader matching.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// CascadeReplace also forces replacement of the resource instances that
+	// depend on the ones in ForceReplace, directly or indirectly.
+	CascadeReplace bool
+
+	// AllowDeferral defers the planning of resources whose count or for_each
+	// arguments aren't known yet to a later plan, instead of returning an
+	// error.
+	AllowDeferral bool
+
 	// These private fields are used only temporarily during decoding. Use
 	// method Parse to populate the exported fields from these, validating
 	// the raw values in the process.
@@ -95,7 +135,9 @@ type Operation struct {
 	targetsFilesRaw  []string
 	excludesRaw      []string
 	excludesFilesRaw []string
+	selectorsRaw     []string
 	forceReplaceRaw  []string
+	parallelismRaw   []rawProviderParallelism
 	destroyRaw       bool
 	refreshOnlyRaw   bool
 }
@@ -157,7 +199,7 @@ func parseFileTargetables(filePaths []string, flag string) ([]addrs.Targetable,
 		for sc.Scan() {
 			lineBytes := sc.Bytes()
 			lineRange := sc.Range()
-			if isComment(lineBytes) {
+			if isComment(lineBytes) || isBlank(lineBytes) {
 				continue
 			}
 			traversal, syntaxDiags := hclsyntax.ParseTraversalAbs(lineBytes, lineRange.Filename, lineRange.Start)
@@ -181,6 +223,12 @@ func isComment(b []byte) bool {
 	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("#"))
 }
 
+// isBlank returns true for lines in a targeting file that contain only
+// whitespace, which lists generated by other tools often include.
+func isBlank(b []byte) bool {
+	return len(bytes.TrimSpace(b)) == 0
+}
+
 func parseRawTargetsAndExcludes(targetsDirect, excludesDirect []string, targetFiles, excludeFiles []string) ([]addrs.Targetable, []addrs.Targetable, tfdiags.Diagnostics) {
 	var allParsedTargets, allParsedExcludes, parsedTargets []addrs.Targetable
 	var parseDiags, diags tfdiags.Diagnostics
@@ -222,6 +270,46 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 	o.Targets, o.Excludes, parseDiags = parseRawTargetsAndExcludes(o.targetsRaw, o.excludesRaw, o.targetsFilesRaw, o.excludesFilesRaw)
 	diags = diags.Append(parseDiags)
 
+	for _, raw := range o.selectorsRaw {
+		selector, selectorDiags := tofu.ParseTargetSelector(raw)
+		diags = diags.Append(selectorDiags)
+		if selectorDiags.HasErrors() {
+			continue
+		}
+		o.TargetSelectors = append(o.TargetSelectors, selector)
+	}
+	if len(o.TargetSelectors) > 0 && len(o.Excludes) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid combination of arguments",
+			"The target and exclude planning options are mutually-exclusive. Each plan must use either only the target options or only the exclude options.",
+		))
+	}
+
+	if o.RefreshParallelism < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid refresh parallelism",
+			"The -refresh-parallelism option must be a positive whole number.",
+		))
+	}
+
+	for _, raw := range o.parallelismRaw {
+		_, providerDiags := addrs.ParseProviderSourceString(raw.provider)
+		if providerDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid provider %q in -parallelism", raw.provider),
+				providerDiags[0].Description().Detail,
+			))
+			continue
+		}
+		if o.ProviderParallelism == nil {
+			o.ProviderParallelism = make(map[string]int)
+		}
+		o.ProviderParallelism[raw.provider] = raw.limit
+	}
+
 	for _, raw := range o.forceReplaceRaw {
 		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
 		if syntaxDiags.HasErrors() {
@@ -255,6 +343,14 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		o.ForceReplace = append(o.ForceReplace, addr)
 	}
 
+	if o.CascadeReplace && len(o.forceReplaceRaw) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Replace address required with -cascade",
+			"The -cascade option also replaces the resource instances that depend on the ones given with -replace=..., so it can only be used together with at least one -replace option.",
+		))
+	}
+
 	// If you add a new possible value for o.PlanMode here, consider also
 	// adding a specialized error message for it in ParseApplyDestroy.
 	switch {
@@ -318,14 +414,17 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 
 	if state != nil {
 		f.BoolVar(&state.Lock, "lock", true, "lock")
-		f.DurationVar(&state.LockTimeout, "lock-timeout", 0, "lock-timeout")
+		f.Var(flagDuration{value: &state.LockTimeout, set: &state.LockTimeoutSet}, "lock-timeout", "lock-timeout")
 		f.StringVar(&state.StatePath, "state", "", "state-path")
 		f.StringVar(&state.StateOutPath, "state-out", "", "state-path")
 		f.StringVar(&state.BackupPath, "backup", "", "backup-path")
+		f.Var(flagWorkspace{name: &state.Workspace, create: &state.CreateWorkspace}, "workspace", "workspace")
 	}
 
 	if operation != nil {
-		f.IntVar(&operation.Parallelism, "parallelism", DefaultParallelism, "parallelism")
+		operation.Parallelism = DefaultParallelism
+		f.Var(flagParallelism{total: &operation.Parallelism, providers: &operation.parallelismRaw}, "parallelism", "parallelism")
+		f.IntVar(&operation.RefreshParallelism, "refresh-parallelism", 0, "refresh-parallelism")
 		f.BoolVar(&operation.Refresh, "refresh", true, "refresh")
 		f.BoolVar(&operation.destroyRaw, "destroy", false, "destroy")
 		f.BoolVar(&operation.refreshOnlyRaw, "refresh-only", false, "refresh-only")
@@ -333,7 +432,10 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 		f.Var((*flagStringSlice)(&operation.targetsFilesRaw), "target-file", "target-file")
 		f.Var((*flagStringSlice)(&operation.excludesRaw), "exclude", "exclude")
 		f.Var((*flagStringSlice)(&operation.excludesFilesRaw), "exclude-file", "exclude-file")
+		f.Var((*flagStringSlice)(&operation.selectorsRaw), "target-selector", "target-selector")
 		f.Var((*flagStringSlice)(&operation.forceReplaceRaw), "replace", "replace")
+		f.BoolVar(&operation.CascadeReplace, "cascade", false, "cascade")
+		f.BoolVar(&operation.AllowDeferral, "allow-deferral", false, "allow-deferral")
 	}
 
 	// Gather all -var and -var-file arguments into one heterogeneous structure
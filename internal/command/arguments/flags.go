@@ -8,6 +8,9 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // flagStringSlice is a flag.Value implementation which allows collecting
@@ -26,6 +29,118 @@ func (v *flagStringSlice) Set(raw string) error {
 	return nil
 }
 
+// flagParallelism is a flag.Value implementation for -parallelism, which
+// accepts either an overall limit such as -parallelism=20, per-provider limits
+// such as -parallelism=aws=10,cloudflare=2, or a mixture of both. The provider
+// names are collected as raw strings to be resolved by Operation.Parse.
+type flagParallelism struct {
+	total     *int
+	providers *[]rawProviderParallelism
+}
+
+// rawProviderParallelism is a per-provider limit from -parallelism whose
+// provider name hasn't yet been parsed.
+type rawProviderParallelism struct {
+	provider string
+	limit    int
+}
+
+var _ flag.Value = flagParallelism{}
+
+func (f flagParallelism) String() string {
+	if f.total == nil {
+		return ""
+	}
+	return strconv.Itoa(*f.total)
+}
+
+func (f flagParallelism) Set(raw string) error {
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		name, value, perProvider := strings.Cut(item, "=")
+		if !perProvider {
+			n, err := strconv.Atoi(item)
+			if err != nil {
+				return fmt.Errorf("%q is not a number or a provider=number pair", item)
+			}
+			*f.total = n
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("the limit for provider %q must be a positive whole number", name)
+		}
+		*f.providers = append(*f.providers, rawProviderParallelism{provider: name, limit: n})
+	}
+	return nil
+}
+
+// flagDuration is a flag.Value implementation for duration options that
+// records whether the option was given at all, so that an explicit zero can
+// be told apart from the default.
+type flagDuration struct {
+	value *time.Duration
+	set   *bool
+}
+
+var _ flag.Value = flagDuration{}
+
+func (f flagDuration) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return f.value.String()
+}
+
+func (f flagDuration) Set(raw string) error {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+	*f.value = d
+	*f.set = true
+	return nil
+}
+
+// flagWorkspace is a flag.Value implementation for the -workspace option,
+// which takes a workspace name optionally followed by ",create".
+type flagWorkspace struct {
+	name   *string
+	create *bool
+}
+
+var _ flag.Value = flagWorkspace{}
+
+func (f flagWorkspace) String() string {
+	if f.name == nil || *f.name == "" {
+		return ""
+	}
+	if *f.create {
+		return *f.name + ",create"
+	}
+	return *f.name
+}
+
+func (f flagWorkspace) Set(raw string) error {
+	name, option, hasOption := strings.Cut(raw, ",")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("a workspace name is required")
+	}
+	create := false
+	if hasOption {
+		if strings.TrimSpace(option) != "create" {
+			return fmt.Errorf("unsupported option %q after the workspace name; the only supported option is \"create\"", option)
+		}
+		create = true
+	}
+	*f.name = name
+	*f.create = create
+	return nil
+}
+
 // flagNameValueSlice is a flag.Value implementation that appends raw flag
 // names and values to a slice. This is used to collect a sequence of flags
 // with possibly different names, preserving the overall order.
@@ -6,6 +6,8 @@
 package arguments
 
 import (
+	"fmt"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -38,10 +40,11 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput, rawOutput bool
-	var statePath string
+	var statePath, format string
 	cmdFlags := extendedFlagSet("output", nil, nil, output.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	cmdFlags.StringVar(&statePath, "state", "", "path")
 	cmdFlags.BoolVar(&output.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 
@@ -74,6 +77,33 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		rawOutput = false
 	}
 
+	var formatViewType ViewType
+	switch format {
+	case "":
+	case "dotenv":
+		formatViewType = ViewDotenv
+	case "sh":
+		formatViewType = ViewShell
+	case "yaml":
+		formatViewType = ViewYAML
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The -format option must be \"dotenv\", \"sh\", or \"yaml\", not %q.", format),
+		))
+	}
+	if formatViewType != ViewNone && (jsonOutput || rawOutput) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -format option can't be combined with -raw or -json.",
+		))
+		formatViewType = ViewNone
+		jsonOutput = false
+		rawOutput = false
+	}
+
 	output.StatePath = statePath
 
 	if len(args) > 0 {
@@ -89,6 +119,8 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 	}
 
 	switch {
+	case formatViewType != ViewNone:
+		output.ViewType = formatViewType
 	case jsonOutput:
 		output.ViewType = ViewJSON
 	case rawOutput:
@@ -50,6 +50,27 @@ func TestParseOutput_valid(t *testing.T) {
 				StatePath: "foobar.tfstate",
 			},
 		},
+		"dotenv": {
+			[]string{"-format=dotenv"},
+			&Output{
+				Name:     "",
+				ViewType: ViewDotenv,
+			},
+		},
+		"sh": {
+			[]string{"-format=sh", "foo"},
+			&Output{
+				Name:     "foo",
+				ViewType: ViewShell,
+			},
+		},
+		"yaml": {
+			[]string{"-format", "yaml"},
+			&Output{
+				Name:     "",
+				ViewType: ViewYAML,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -102,6 +123,34 @@ func TestParseOutput_invalid(t *testing.T) {
 				),
 			},
 		},
+		"unknown format": {
+			[]string{"-format=toml"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The -format option must be "dotenv", "sh", or "yaml", not "toml".`,
+				),
+			},
+		},
+		"format and json specified": {
+			[]string{"-format=yaml", "-json"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -format option can't be combined with -raw or -json.",
+				),
+			},
+		},
 		"raw with no name": {
 			[]string{"-raw"},
 			&Output{
@@ -6,6 +6,9 @@
 package arguments
 
 import (
+	"time"
+
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -27,17 +30,56 @@ type Plan struct {
 	// OutPath contains an optional path to store the plan file
 	OutPath string
 
+	// MaxAge, if nonzero, is how long after it is created the plan file saved
+	// to OutPath can be applied.
+	MaxAge time.Duration
+
 	// GenerateConfigPath tells OpenTofu that config should be generated for
 	// unmatched import target paths and which path the generated file should
 	// be written to.
 	GenerateConfigPath string
 
+	// GenerateConfigTemplatePath is an optional path to a file of templates
+	// that reshape the generated config for each resource type.
+	GenerateConfigTemplatePath string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
 	ShowSensitive bool
 
+	// ShowProvisioners makes the plan show what the provisioners of each
+	// planned change will run when it's applied.
+	ShowProvisioners bool
+
+	// Incremental makes the plan skip planning the resource instances whose
+	// inputs haven't changed since the previous incremental plan in the same
+	// working directory and workspace.
+	Incremental bool
+
+	// ReportOrphansPath, if set, is the path where a report of the resource
+	// instances in the state that have no configuration is written.
+	ReportOrphansPath string
+
+	// ExportGraphPath, if set, is the path where the graph of the changes
+	// that applying the plan would make is written, in the order they'd
+	// be made.
+	ExportGraphPath string
+
+	// StateVersion, if set, identifies an earlier version of the state,
+	// kept by the backend, to plan against instead of the latest one.
+	StateVersion string
+
+	// ProfilePath, if set, is the path where a performance profile of the
+	// operation is written, with the time spent in each of its phases and on
+	// the provider calls of each resource instance.
+	ProfilePath string
+
+	// ProfileCPUPath, if set, is the path where a pprof CPU profile of the
+	// operation is written.
+	ProfileCPUPath string
+
 	// ModuleDeprecationWarnLevel stores the level that will be used for selecting what deprecation warnings to show.
 	ModuleDeprecationWarnLevel string
 }
@@ -57,8 +99,17 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&plan.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
 	cmdFlags.BoolVar(&plan.InputEnabled, "input", true, "input")
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
+	cmdFlags.DurationVar(&plan.MaxAge, "max-age", 0, "max-age")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
+	cmdFlags.StringVar(&plan.GenerateConfigTemplatePath, "generate-config-template", "", "generate-config-template")
 	cmdFlags.BoolVar(&plan.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.BoolVar(&plan.ShowProvisioners, "show-provisioners", false, "show-provisioners")
+	cmdFlags.BoolVar(&plan.Incremental, "incremental", false, "incremental")
+	cmdFlags.StringVar(&plan.ReportOrphansPath, "report-orphans", "", "report-orphans")
+	cmdFlags.StringVar(&plan.ExportGraphPath, "export-graph", "", "export-graph")
+	cmdFlags.StringVar(&plan.StateVersion, "state-version", "", "state-version")
+	cmdFlags.StringVar(&plan.ProfilePath, "profile", "", "profile")
+	cmdFlags.StringVar(&plan.ProfileCPUPath, "profile-cpu", "", "profile-cpu")
 	cmdFlags.StringVar(&plan.ModuleDeprecationWarnLevel, "deprecation", "", "control the level of deprecation warnings")
 
 	var json bool
@@ -82,11 +133,67 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 		))
 	}
 
+	switch {
+	case plan.MaxAge < 0:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid plan maximum age",
+			"The -max-age option must be a positive duration, such as \"30m\" or \"24h\".",
+		))
+	case plan.MaxAge > 0 && plan.OutPath == "":
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required with -max-age",
+			"The -max-age option limits how long a saved plan file can be applied, so it can only be used with -out.",
+		))
+	}
+
+	if plan.GenerateConfigTemplatePath != "" && plan.GenerateConfigPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Generated config output required with -generate-config-template",
+			"The -generate-config-template option reshapes the config generated for import targets, so it can only be used with -generate-config-out.",
+		))
+	}
+
 	diags = diags.Append(plan.Operation.Parse())
 
+	if plan.Incremental && plan.Operation.PlanMode != plans.NormalMode {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command-line options",
+			"The -incremental option can only be used in the normal planning mode, so it can't be used with -destroy or -refresh-only.",
+		))
+	}
+
+	if plan.StateVersion != "" {
+		if plan.OutPath != "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				"A plan made with -state-version is based on an earlier version of the state, so it can't be applied and can't be saved with -out.",
+			))
+		}
+		if plan.Incremental {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				"The -incremental option caches the results of planning against the latest state, so it can't be used with -state-version.",
+			))
+		}
+	}
+
 	// JSON view currently does not support input, so we disable it here
 	if json {
 		plan.InputEnabled = false
+
+		if plan.ShowProvisioners {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				"The -show-provisioners option adds provisioner previews to the human-readable plan output, so it can't be used with -json.",
+			))
+		}
 	}
 
 	switch {
@@ -8,6 +8,7 @@ package arguments
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/tfdiags"
 
@@ -55,6 +56,82 @@ func TestParsePlan_basicValid(t *testing.T) {
 				},
 			},
 		},
+		"maximum age": {
+			[]string{"-out=saved.tfplan", "-max-age=24h"},
+			&Plan{
+				InputEnabled: true,
+				OutPath:      "saved.tfplan",
+				MaxAge:       24 * time.Hour,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"generated config template": {
+			[]string{"-generate-config-out=generated.tf", "-generate-config-template=templates.hcl"},
+			&Plan{
+				InputEnabled:               true,
+				GenerateConfigPath:         "generated.tf",
+				GenerateConfigTemplatePath: "templates.hcl",
+				ViewType:                   ViewHuman,
+				State:                      &State{Lock: true},
+				Vars:                       &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"show provisioners": {
+			[]string{"-show-provisioners"},
+			&Plan{
+				InputEnabled:     true,
+				ShowProvisioners: true,
+				ViewType:         ViewHuman,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"allow deferral": {
+			[]string{"-allow-deferral"},
+			&Plan{
+				InputEnabled: true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:      plans.NormalMode,
+					Parallelism:   10,
+					Refresh:       true,
+					AllowDeferral: true,
+				},
+			},
+		},
+		"explicit lock timeout": {
+			[]string{"-lock-timeout=0s"},
+			&Plan{
+				InputEnabled: true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true, LockTimeoutSet: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
 		"JSON view disables input": {
 			[]string{"-json"},
 			&Plan{
@@ -114,6 +191,95 @@ func TestParsePlan_tooManyArguments(t *testing.T) {
 	}
 }
 
+func TestParsePlan_maxAgeInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want string
+	}{
+		"without -out": {
+			[]string{"-max-age=1h"},
+			"Plan file required with -max-age",
+		},
+		"negative": {
+			[]string{"-out=saved.tfplan", "-max-age=-1h"},
+			"Invalid plan maximum age",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParsePlan(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, tc.want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePlan_showProvisionersJSON(t *testing.T) {
+	_, diags := ParsePlan([]string{"-show-provisioners", "-json"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Incompatible command-line options"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParsePlan_incrementalMode(t *testing.T) {
+	plan, diags := ParsePlan([]string{"-incremental"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if !plan.Incremental {
+		t.Fatal("-incremental not set")
+	}
+
+	_, diags = ParsePlan([]string{"-incremental", "-destroy"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Incompatible command-line options"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParsePlan_reportOrphans(t *testing.T) {
+	plan, diags := ParsePlan([]string{"-report-orphans=orphans.json"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got, want := plan.ReportOrphansPath, "orphans.json"; got != want {
+		t.Fatalf("wrong report path %q; want %q", got, want)
+	}
+}
+
+func TestParsePlan_stateVersion(t *testing.T) {
+	plan, diags := ParsePlan([]string{"-state-version=12"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got, want := plan.StateVersion, "12"; got != want {
+		t.Fatalf("wrong state version %q; want %q", got, want)
+	}
+
+	for _, args := range [][]string{
+		{"-state-version=12", "-out=saved.tfplan"},
+		{"-state-version=12", "-incremental"},
+	} {
+		_, diags = ParsePlan(args)
+		if len(diags) == 0 {
+			t.Fatalf("%v: expected diags but got none", args)
+		}
+		if got, want := diags.Err().Error(), "Incompatible command-line options"; !strings.Contains(got, want) {
+			t.Fatalf("%v: wrong diags\n got: %s\nwant: %s", args, got, want)
+		}
+	}
+}
+
 func TestParsePlan_targets(t *testing.T) {
 	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
 	boop, _ := addrs.ParseTargetStr("module.boop")
@@ -194,6 +360,12 @@ func TestParsePlan_targetFile(t *testing.T) {
 			},
 			want: []addrs.Targetable{foobarbaz.Subject, boop.Subject},
 		},
+		"target file with comments and blank lines": {
+			files: []mockFile{
+				{fileContent: "# generated\nfoo_bar.baz\n\n  \r\n# more\nmodule.boop\n\n"},
+			},
+			want: []addrs.Targetable{foobarbaz.Subject, boop.Subject},
+		},
 		"target file invalid target": {
 			files: []mockFile{
 				{
@@ -526,6 +698,9 @@ func TestParsePlan_excludeFile(t *testing.T) {
 		"exclude file valid comment": {
 			{fileContent: "#foo_bar.baz"},
 		},
+		"exclude file valid blank lines": {
+			{fileContent: "\nfoo_bar.baz\n   \n\nmodule.boop\n"},
+		},
 	}
 	for name, tc := range testCasesTest {
 		t.Run(name, func(t *testing.T) {
@@ -571,6 +746,56 @@ func TestParsePlan_excludeFile(t *testing.T) {
 	}
 }
 
+func TestParsePlan_targetSelectors(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    int
+		wantErr string
+	}{
+		"no selectors by default": {
+			args: nil,
+			want: 0,
+		},
+		"one selector": {
+			args: []string{`-target-selector=tags["team"] == "payments"`},
+			want: 1,
+		},
+		"two selectors": {
+			args: []string{`-target-selector=size > 2`, `-target-selector`, `startswith(name, "pay")`},
+			want: 2,
+		},
+		"invalid syntax": {
+			args:    []string{`-target-selector=tags[`},
+			want:    0,
+			wantErr: `Invalid target selector "tags["`,
+		},
+		"with exclude": {
+			args:    []string{`-target-selector=size > 2`, `-exclude=foo_bar.baz`},
+			want:    1,
+			wantErr: "Invalid combination of arguments",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			} else if tc.wantErr != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diags but got none")
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+
+			if got := len(got.Operation.TargetSelectors); got != tc.want {
+				t.Fatalf("wrong number of selectors %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestParsePlan_excludeAndTarget(t *testing.T) {
 	testCases := [][]string{
 		[]string{"-target-file=foo_file", "-exclude=foo_bar.baz"},
@@ -602,6 +827,136 @@ func TestParsePlan_excludeAndTarget(t *testing.T) {
 	}
 }
 
+func TestParsePlan_parallelism(t *testing.T) {
+	testCases := map[string]struct {
+		args           []string
+		want           int
+		wantByProvider map[string]int
+		wantErr        string
+	}{
+		"overall": {
+			args: []string{"-parallelism=3"},
+			want: 3,
+		},
+		"per provider": {
+			args: []string{"-parallelism=aws=10,cloudflare=2,fastly/fastly=3"},
+			want: DefaultParallelism,
+			wantByProvider: map[string]int{
+				"aws":           10,
+				"cloudflare":    2,
+				"fastly/fastly": 3,
+			},
+		},
+		"overall and per provider": {
+			args: []string{"-parallelism=20", "-parallelism=aws=5"},
+			want: 20,
+			wantByProvider: map[string]int{
+				"aws": 5,
+			},
+		},
+		"invalid provider limit": {
+			args:    []string{"-parallelism=aws=0"},
+			want:    DefaultParallelism,
+			wantErr: `the limit for provider "aws" must be a positive whole number`,
+		},
+		"invalid provider": {
+			args:    []string{"-parallelism=not/a/valid/provider=2"},
+			want:    DefaultParallelism,
+			wantErr: `Invalid provider "not/a/valid/provider" in -parallelism`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+			} else if len(diags) == 0 || !strings.Contains(diags.Err().Error(), tc.wantErr) {
+				t.Fatalf("wrong diags\n got: %v\nwant: %s", diags, tc.wantErr)
+			}
+			if got.Operation.Parallelism != tc.want {
+				t.Errorf("wrong parallelism %d; want %d", got.Operation.Parallelism, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantByProvider, got.Operation.ProviderParallelism); diff != "" {
+				t.Errorf("wrong provider parallelism\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlan_workspace(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		want       string
+		wantCreate bool
+		wantErr    string
+	}{
+		"not set": {
+			args: nil,
+		},
+		"select": {
+			args: []string{"-workspace=staging"},
+			want: "staging",
+		},
+		"select or create": {
+			args:       []string{"-workspace=staging,create"},
+			want:       "staging",
+			wantCreate: true,
+		},
+		"last one wins": {
+			args: []string{"-workspace=staging,create", "-workspace=prod"},
+			want: "prod",
+		},
+		"unsupported option": {
+			args:    []string{"-workspace=staging,delete"},
+			wantErr: `unsupported option "delete" after the workspace name`,
+		},
+		"no name": {
+			args:    []string{"-workspace=,create"},
+			wantErr: "a workspace name is required",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+			} else if len(diags) == 0 || !strings.Contains(diags.Err().Error(), tc.wantErr) {
+				t.Fatalf("wrong diags\n got: %v\nwant: %s", diags, tc.wantErr)
+			}
+			if got.State.Workspace != tc.want {
+				t.Errorf("wrong workspace %q; want %q", got.State.Workspace, tc.want)
+			}
+			if got.State.CreateWorkspace != tc.wantCreate {
+				t.Errorf("wrong create %t; want %t", got.State.CreateWorkspace, tc.wantCreate)
+			}
+		})
+	}
+}
+
+func TestParsePlan_refreshParallelism(t *testing.T) {
+	got, diags := ParsePlan([]string{"-refresh-parallelism=4"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got.Operation.RefreshParallelism != 4 {
+		t.Errorf("wrong refresh parallelism %d; want 4", got.Operation.RefreshParallelism)
+	}
+
+	_, diags = ParsePlan([]string{"-refresh-parallelism=-1"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Invalid refresh parallelism"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParsePlan_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -652,3 +1007,13 @@ func TestParsePlan_vars(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlan_generateConfigTemplateWithoutOut(t *testing.T) {
+	_, diags := ParsePlan([]string{"-generate-config-template=templates.hcl"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Generated config output required with -generate-config-template"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
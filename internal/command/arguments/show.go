@@ -6,6 +6,10 @@
 package arguments
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -19,9 +23,33 @@ type Show struct {
 	TargetType ShowTargetType
 	TargetArg  string
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// ViewType specifies which output format to use: human, JSON, SARIF,
+	// Markdown, or HTML. SARIF, Markdown, and HTML are only valid when
+	// showing a plan.
 	ViewType ViewType
 
+	// MarkdownMaxDiffLines limits the lines of diff shown for each resource
+	// instance, and MarkdownMaxLength limits the total size in bytes, when
+	// ViewType is ViewMarkdown. Zero means no limit.
+	MarkdownMaxDiffLines int
+	MarkdownMaxLength    int
+
+	// Metadata shows only where a saved plan came from and whether it can
+	// still be applied, instead of the changes it proposes.
+	Metadata bool
+
+	// Filters are address patterns selecting which resource instances to
+	// show from a state snapshot or plan, where "*" matches any sequence of
+	// characters and "?" matches any single character. An instance is shown
+	// if it matches at least one of them. Empty means all instances.
+	Filters []string
+
+	// FilterModule, if not nil, limits the resource instances shown from a
+	// state snapshot or plan to those belonging to the given module instance
+	// or any of its descendants. A module call without an instance key
+	// matches all of its instances.
+	FilterModule addrs.ModuleInstance
+
 	Vars *Vars
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
@@ -73,17 +101,29 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput bool
+	var sarifOutput bool
+	var markdownOutput bool
+	var htmlOutput bool
 	var stateTarget bool
 	var planTarget string
 	var configTarget bool
 	var moduleTarget string
+	var filterModule string
 	cmdFlags := extendedFlagSet("show", nil, nil, show.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&sarifOutput, "sarif", false, "sarif")
+	cmdFlags.BoolVar(&markdownOutput, "markdown", false, "markdown")
+	cmdFlags.BoolVar(&htmlOutput, "html", false, "html")
+	cmdFlags.BoolVar(&show.Metadata, "meta", false, "show saved plan metadata")
+	cmdFlags.IntVar(&show.MarkdownMaxDiffLines, "markdown-max-diff-lines", 0, "maximum lines of diff per resource in markdown output")
+	cmdFlags.IntVar(&show.MarkdownMaxLength, "markdown-max-length", 0, "maximum length of markdown output")
 	cmdFlags.BoolVar(&show.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 	cmdFlags.BoolVar(&stateTarget, "state", false, "show the latest state snapshot")
 	cmdFlags.StringVar(&planTarget, "plan", "", "show the plan from a saved plan file")
 	cmdFlags.BoolVar(&configTarget, "config", false, "show the current configuration")
 	cmdFlags.StringVar(&moduleTarget, "module", "", "show metadata about one module")
+	cmdFlags.Var((*flagStringSlice)(&show.Filters), "filter", "show only matching resource instances")
+	cmdFlags.StringVar(&filterModule, "filter-module", "", "show only resource instances in a module")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -111,12 +151,102 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		return show, diags
 	}
 
+	for _, filter := range show.Filters {
+		if filter == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter",
+				"The -filter option requires a resource address pattern, such as aws_instance.* or module.app.*.",
+			))
+			return show, diags
+		}
+	}
+	if filterModule != "" {
+		addr, addrDiags := addrs.ParseModuleInstanceStr(filterModule)
+		if addrDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module address",
+				fmt.Sprintf("The -filter-module option requires a module address, such as module.app, not %q.", filterModule),
+			))
+			return show, diags
+		}
+		show.FilterModule = addr
+	}
+	filtered := len(show.Filters) != 0 || show.FilterModule != nil
+	if filtered && (configTarget || moduleTarget != "" || show.Metadata) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Filters require a state snapshot or plan",
+			"The -filter and -filter-module options can't be combined with -config, -module=DIR, or -meta.",
+		))
+		return show, diags
+	}
+
+	formats := 0
+	for _, selected := range []bool{jsonOutput, sarifOutput, markdownOutput, htmlOutput} {
+		if selected {
+			formats++
+		}
+	}
+	if formats > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Conflicting output formats",
+			"The -json, -sarif, -markdown, and -html options are mutually-exclusive.",
+		))
+		return show, diags
+	}
+
+	if !markdownOutput && (show.MarkdownMaxDiffLines != 0 || show.MarkdownMaxLength != 0) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Markdown limits require -markdown",
+			"The -markdown-max-diff-lines and -markdown-max-length options can only be used with -markdown.",
+		))
+	}
+	if show.MarkdownMaxDiffLines < 0 || show.MarkdownMaxLength < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid Markdown limit",
+			"The -markdown-max-diff-lines and -markdown-max-length options must not be negative. Use zero for no limit.",
+		))
+	}
+
+	if show.Metadata && (sarifOutput || markdownOutput || htmlOutput) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Conflicting output formats",
+			"The -meta option can only be combined with -json.",
+		))
+		return show, diags
+	}
+
+	// Some formats can only represent a saved plan, which we report as an
+	// error once we know what's being shown.
+	var planOnlyErr tfdiags.Diagnostic
 	switch {
 	case jsonOutput:
 		show.ViewType = ViewJSON
+	case sarifOutput:
+		show.ViewType = ViewSARIF
+		planOnlyErr = requiresPlanError("SARIF")
+	case markdownOutput:
+		show.ViewType = ViewMarkdown
+		planOnlyErr = requiresPlanError("Markdown")
+	case htmlOutput:
+		show.ViewType = ViewHTML
+		planOnlyErr = requiresPlanError("HTML")
 	default:
 		show.ViewType = ViewHuman
 	}
+	if show.Metadata {
+		planOnlyErr = tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan metadata requires a saved plan",
+			"The -meta option can only be used to show a saved plan file.",
+		)
+	}
 
 	if planTarget == "" && moduleTarget == "" && !stateTarget && !configTarget {
 		// If none of the target type options was provided then we're
@@ -125,6 +255,9 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		args = cmdFlags.Args()
 		switch len(args) {
 		case 0:
+			if planOnlyErr != nil {
+				diags = diags.Append(planOnlyErr)
+			}
 			show.TargetType = ShowState
 			show.TargetArg = ""
 		case 1:
@@ -181,6 +314,16 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 			"Conflicting object types to show",
 			"The -state, -plan=FILENAME, -config, and -module=DIR options are mutually-exclusive, to specify which kind of object to show.",
 		))
+	} else if planOnlyErr != nil && show.TargetType != ShowPlan {
+		diags = diags.Append(planOnlyErr)
 	}
 	return show, diags
 }
+
+func requiresPlanError(format string) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		fmt.Sprintf("%s output requires a saved plan", format),
+		fmt.Sprintf("The -%s option can only be used to show a saved plan file.", strings.ToLower(format)),
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -50,6 +51,40 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType:   ViewJSON,
 			},
 		},
+		"saved plan file, SARIF": {
+			[]string{"-plan=tfplan", "-sarif"},
+			&Show{
+				TargetType: ShowPlan,
+				TargetArg:  "tfplan",
+				ViewType:   ViewSARIF,
+			},
+		},
+		"legacy positional plan file, SARIF": {
+			[]string{"-sarif", "tfplan"},
+			&Show{
+				TargetType: ShowUnknownType,
+				TargetArg:  "tfplan",
+				ViewType:   ViewSARIF,
+			},
+		},
+		"saved plan file, Markdown with limits": {
+			[]string{"-plan=tfplan", "-markdown", "-markdown-max-diff-lines=50", "-markdown-max-length=65000"},
+			&Show{
+				TargetType:           ShowPlan,
+				TargetArg:            "tfplan",
+				ViewType:             ViewMarkdown,
+				MarkdownMaxDiffLines: 50,
+				MarkdownMaxLength:    65000,
+			},
+		},
+		"saved plan file, HTML": {
+			[]string{"-plan=tfplan", "-html"},
+			&Show{
+				TargetType: ShowPlan,
+				TargetArg:  "tfplan",
+				ViewType:   ViewHTML,
+			},
+		},
 		"saved plan file": {
 			[]string{"-plan=tfplan"},
 			&Show{
@@ -66,6 +101,24 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType:   ViewJSON,
 			},
 		},
+		"saved plan file metadata": {
+			[]string{"-plan=tfplan", "-meta"},
+			&Show{
+				TargetType: ShowPlan,
+				TargetArg:  "tfplan",
+				ViewType:   ViewHuman,
+				Metadata:   true,
+			},
+		},
+		"legacy positional plan file metadata, JSON": {
+			[]string{"-meta", "-json", "tfplan"},
+			&Show{
+				TargetType: ShowUnknownType,
+				TargetArg:  "tfplan",
+				ViewType:   ViewJSON,
+				Metadata:   true,
+			},
+		},
 		"legacy positional argument": {
 			[]string{"foo"},
 			&Show{
@@ -98,6 +151,27 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType:   ViewJSON,
 			},
 		},
+		"saved plan file, filtered": {
+			[]string{"-plan=tfplan", "-filter=aws_instance.*", "-filter=module.db.*", "-filter-module=module.app[\"blue\"]"},
+			&Show{
+				TargetType: ShowPlan,
+				TargetArg:  "tfplan",
+				ViewType:   ViewHuman,
+				Filters:    []string{"aws_instance.*", "module.db.*"},
+				FilterModule: addrs.ModuleInstance{
+					{Name: "app", InstanceKey: addrs.StringKey("blue")},
+				},
+			},
+		},
+		"latest state snapshot, filtered, JSON": {
+			[]string{"-json", "-filter=aws_instance.web"},
+			&Show{
+				TargetType: ShowState,
+				TargetArg:  "",
+				ViewType:   ViewJSON,
+				Filters:    []string{"aws_instance.web"},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -107,7 +181,7 @@ func TestParseShow_valid(t *testing.T) {
 			if len(diags) > 0 {
 				t.Fatalf("unexpected diags: %v", diags)
 			}
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 		})
@@ -201,6 +275,134 @@ func TestParseShow_invalid(t *testing.T) {
 				),
 			},
 		},
+		"SARIF and JSON": {
+			[]string{"-plan=tfplan", "-sarif", "-json"},
+			&Show{
+				ViewType: ViewNone,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Conflicting output formats",
+					"The -json, -sarif, -markdown, and -html options are mutually-exclusive.",
+				),
+			},
+		},
+		"SARIF for state": {
+			[]string{"-state", "-sarif"},
+			&Show{
+				TargetType: ShowState,
+				ViewType:   ViewSARIF,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"SARIF output requires a saved plan",
+					"The -sarif option can only be used to show a saved plan file.",
+				),
+			},
+		},
+		"Markdown for the latest state snapshot": {
+			[]string{"-markdown"},
+			&Show{
+				TargetType: ShowState,
+				ViewType:   ViewMarkdown,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Markdown output requires a saved plan",
+					"The -markdown option can only be used to show a saved plan file.",
+				),
+			},
+		},
+		"metadata for the latest state snapshot": {
+			[]string{"-meta"},
+			&Show{
+				TargetType: ShowState,
+				ViewType:   ViewHuman,
+				Metadata:   true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Plan metadata requires a saved plan",
+					"The -meta option can only be used to show a saved plan file.",
+				),
+			},
+		},
+		"metadata as HTML": {
+			[]string{"-plan=tfplan", "-meta", "-html"},
+			&Show{
+				ViewType: ViewNone,
+				Metadata: true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Conflicting output formats",
+					"The -meta option can only be combined with -json.",
+				),
+			},
+		},
+		"HTML and Markdown": {
+			[]string{"-plan=tfplan", "-html", "-markdown"},
+			&Show{
+				ViewType: ViewNone,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Conflicting output formats",
+					"The -json, -sarif, -markdown, and -html options are mutually-exclusive.",
+				),
+			},
+		},
+		"HTML for configuration": {
+			[]string{"-config", "-html"},
+			&Show{
+				ViewType: ViewNone,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"JSON output required for configuration",
+					"The -config option requires -json to be specified.",
+				),
+			},
+		},
+		"Markdown limits without Markdown": {
+			[]string{"-plan=tfplan", "-markdown-max-length=100"},
+			&Show{
+				TargetType:        ShowPlan,
+				TargetArg:         "tfplan",
+				ViewType:          ViewHuman,
+				MarkdownMaxLength: 100,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Markdown limits require -markdown",
+					"The -markdown-max-diff-lines and -markdown-max-length options can only be used with -markdown.",
+				),
+			},
+		},
+		"negative Markdown limit": {
+			[]string{"-plan=tfplan", "-markdown", "-markdown-max-diff-lines=-1"},
+			&Show{
+				TargetType:           ShowPlan,
+				TargetArg:            "tfplan",
+				ViewType:             ViewMarkdown,
+				MarkdownMaxDiffLines: -1,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid Markdown limit",
+					"The -markdown-max-diff-lines and -markdown-max-length options must not be negative. Use zero for no limit.",
+				),
+			},
+		},
 		"configuration without json": {
 			[]string{"-config"},
 			&Show{
@@ -287,6 +489,43 @@ func TestParseShow_invalid(t *testing.T) {
 				),
 			},
 		},
+		"empty filter": {
+			[]string{"-filter="},
+			&Show{
+				Filters: []string{""},
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid filter",
+					"The -filter option requires a resource address pattern, such as aws_instance.* or module.app.*.",
+				),
+			},
+		},
+		"invalid filter module": {
+			[]string{"-filter-module=aws_instance.web"},
+			&Show{},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid module address",
+					`The -filter-module option requires a module address, such as module.app, not "aws_instance.web".`,
+				),
+			},
+		},
+		"filter with config": {
+			[]string{"-config", "-json", "-filter=aws_instance.*"},
+			&Show{
+				Filters: []string{"aws_instance.*"},
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Filters require a state snapshot or plan",
+					"The -filter and -filter-module options can't be combined with -config, -module=DIR, or -meta.",
+				),
+			},
+		},
 		"module with config": {
 			[]string{"-module=foo", "-config", "-json"},
 			&Show{
@@ -308,7 +547,7 @@ func TestParseShow_invalid(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			got, gotDiags := ParseShow(tc.args)
 			got.Vars = nil
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 			if !reflect.DeepEqual(gotDiags, tc.wantDiags) {
@@ -11,10 +11,16 @@ package arguments
 type ViewType rune
 
 const (
-	ViewNone  ViewType = 0
-	ViewHuman ViewType = 'H'
-	ViewJSON  ViewType = 'J'
-	ViewRaw   ViewType = 'R'
+	ViewNone     ViewType = 0
+	ViewHuman    ViewType = 'H'
+	ViewJSON     ViewType = 'J'
+	ViewRaw      ViewType = 'R'
+	ViewSARIF    ViewType = 'S'
+	ViewMarkdown ViewType = 'M'
+	ViewHTML     ViewType = 'T'
+	ViewDotenv   ViewType = 'D'
+	ViewShell    ViewType = 'E'
+	ViewYAML     ViewType = 'Y'
 )
 
 func (vt ViewType) String() string {
@@ -27,6 +33,18 @@ func (vt ViewType) String() string {
 		return "json"
 	case ViewRaw:
 		return "raw"
+	case ViewSARIF:
+		return "sarif"
+	case ViewMarkdown:
+		return "markdown"
+	case ViewHTML:
+		return "html"
+	case ViewDotenv:
+		return "dotenv"
+	case ViewShell:
+		return "sh"
+	case ViewYAML:
+		return "yaml"
 	default:
 		return "unknown"
 	}
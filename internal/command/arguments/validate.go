@@ -24,6 +24,11 @@ type Validate struct {
 	// included with the module.
 	NoTests bool
 
+	// CachedSchemas indicates that OpenTofu should validate against the
+	// provider schemas saved by "tofu init -cache-schemas" instead of the
+	// installed providers.
+	CachedSchemas bool
+
 	// ViewType specifies which output format to use: human, JSON, or "raw".
 	ViewType ViewType
 
@@ -45,6 +50,7 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.StringVar(&validate.TestDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&validate.NoTests, "no-tests", false, "no-tests")
+	cmdFlags.BoolVar(&validate.CachedSchemas, "cached-schemas", false, "cached-schemas")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -60,6 +60,15 @@ func TestParseValidate_valid(t *testing.T) {
 				NoTests:       true,
 			},
 		},
+		"cached-schemas": {
+			[]string{"-cached-schemas"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				CachedSchemas: true,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -7,8 +7,12 @@ package command
 
 import (
 	"context"
+	"sort"
 
 	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 )
 
 // This file contains some re-usable predictors for auto-complete. The
@@ -37,40 +41,116 @@ func (s completePredictSequence) Predict(a complete.Args) []string {
 
 func (m *Meta) completePredictWorkspaceName(ctx context.Context) complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		// There are lot of things that can fail in here, so if we encounter
-		// any error then we'll just return nothing and not support autocomplete
-		// until whatever error is fixed. (The user can't actually see the error
-		// here, but other commands should produce a user-visible error before
-		// too long.)
-
-		// We assume here that we want to autocomplete for the current working
-		// directory, since we don't have enough context to know where to
-		// find any config path argument, and it might be _after_ the argument
-		// we're trying to complete here anyway.
-		configPath, err := modulePath(nil)
-		if err != nil {
+		b := m.completeBackend(ctx)
+		if b == nil {
+			return nil
+		}
+
+		names, _ := b.Workspaces(ctx)
+		return names
+	})
+}
+
+// completePredictResourceAddress predicts the addresses of the resources and
+// resource instances in the current workspace's latest state snapshot.
+func (m *Meta) completePredictResourceAddress(ctx context.Context) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		b := m.completeBackend(ctx)
+		if b == nil {
 			return nil
 		}
 
-		backendConfig, diags := m.loadBackendConfig(ctx, configPath)
-		if diags.HasErrors() {
+		workspace, err := m.Workspace(ctx)
+		if err != nil {
+			return nil
+		}
+		stateMgr, err := b.StateMgr(ctx, workspace)
+		if err != nil {
 			return nil
 		}
+		if err := stateMgr.RefreshState(context.WithoutCancel(ctx)); err != nil {
+			return nil
+		}
+		state := stateMgr.State()
+		if state == nil {
+			return nil
+		}
+
+		var ret []string
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				ret = append(ret, rs.Addr.String())
+				for key := range rs.Instances {
+					if key != nil {
+						ret = append(ret, rs.Addr.Instance(key).String())
+					}
+				}
+			}
+		}
+		sort.Strings(ret)
+		return ret
+	})
+}
 
-		// Load the encryption configuration
-		enc, encDiags := m.Encryption(ctx)
-		if encDiags.HasErrors() {
+// completePredictVariableAssignment predicts the "NAME=" prefix of -var
+// option values for the input variables declared in the root module.
+func (m *Meta) completePredictVariableAssignment(ctx context.Context) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		configPath, err := modulePath(nil)
+		if err != nil {
 			return nil
 		}
 
-		b, diags := m.Backend(ctx, &BackendOpts{
-			Config: backendConfig,
-		}, enc.State())
-		if diags.HasErrors() {
+		// The module might be only partially valid while it's being edited,
+		// so we use whatever we could load even if there were errors.
+		mod, _ := m.loadSingleModule(ctx, configPath, configs.SelectiveLoadAll)
+		if mod == nil {
 			return nil
 		}
 
-		names, _ := b.Workspaces(ctx)
-		return names
+		ret := make([]string, 0, len(mod.Variables))
+		for name := range mod.Variables {
+			ret = append(ret, name+"=")
+		}
+		sort.Strings(ret)
+		return ret
 	})
 }
+
+// completeBackend returns the backend for the current working directory, or
+// nil if it can't be initialized.
+func (m *Meta) completeBackend(ctx context.Context) backend.Backend {
+	// There are lot of things that can fail in here, so if we encounter
+	// any error then we'll just return nothing and not support autocomplete
+	// until whatever error is fixed. (The user can't actually see the error
+	// here, but other commands should produce a user-visible error before
+	// too long.)
+
+	// We assume here that we want to autocomplete for the current working
+	// directory, since we don't have enough context to know where to
+	// find any config path argument, and it might be _after_ the argument
+	// we're trying to complete here anyway.
+	configPath, err := modulePath(nil)
+	if err != nil {
+		return nil
+	}
+
+	backendConfig, diags := m.loadBackendConfig(ctx, configPath)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := m.Encryption(ctx)
+	if encDiags.HasErrors() {
+		return nil
+	}
+
+	b, diags := m.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	if diags.HasErrors() {
+		return nil
+	}
+	return b
+}
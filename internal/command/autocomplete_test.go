@@ -12,6 +12,9 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
 )
 
 func TestMetaCompletePredictWorkspaceName(t *testing.T) {
@@ -38,3 +41,64 @@ func TestMetaCompletePredictWorkspaceName(t *testing.T) {
 		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
 	}
 }
+
+func TestMetaCompletePredictResourceAddress(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	state := testState()
+	state.SyncWrapper().SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_instance.bar[0]"),
+		&states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"baz"}`),
+			Status:    states.ObjectReady,
+		},
+		addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		addrs.NoKey,
+	)
+	testStateFileDefault(t, state)
+
+	ui := new(cli.MockUi)
+	meta := &Meta{Ui: ui}
+
+	predictor := meta.completePredictResourceAddress(t.Context())
+
+	got := predictor.Predict(complete.Args{
+		Last: "",
+	})
+	want := []string{"test_instance.bar", "test_instance.bar[0]", "test_instance.foo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestMetaCompletePredictVariableAssignment(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	err := os.WriteFile("main.tf", []byte(`
+variable "region" {}
+variable "instance_count" {
+  default = 1
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	meta := &Meta{Ui: ui}
+
+	predictor := meta.completePredictVariableAssignment(t.Context())
+
+	got := predictor.Predict(complete.Args{
+		Last: "",
+	})
+	want := []string{"instance_count=", "region="}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
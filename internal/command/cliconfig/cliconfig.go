@@ -15,13 +15,19 @@ package cliconfig
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/hashicorp/hcl"
 	"github.com/opentofu/svchost"
@@ -31,6 +37,7 @@ import (
 
 const pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
 const pluginCacheMayBreakLockFileEnvVar = "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE"
+const providerSchemaCacheEnvVar = "TF_PROVIDER_SCHEMA_CACHE"
 
 // Config is the structure of the configuration for the OpenTofu CLI.
 //
@@ -55,11 +62,53 @@ type Config struct {
 	// over the requirements of the dependency lock file.
 	PluginCacheMayBreakDependencyLockFile bool `hcl:"plugin_cache_may_break_dependency_lock_file"`
 
+	// ProviderSchemaCache, if set, makes OpenTofu save the schemas it fetches
+	// from providers on disk and load them from there in later commands,
+	// instead of starting every provider to ask for its schema each time.
+	ProviderSchemaCache bool `hcl:"provider_schema_cache"`
+
+	// LockQueueTimeout, if set, is a duration such as "10m" that plan,
+	// apply, and refresh wait in the queue for a state lock that's held by
+	// another process when they aren't given the -lock-timeout option,
+	// instead of failing immediately.
+	LockQueueTimeout string `hcl:"lock_queue_timeout"`
+
 	Hosts map[string]*ConfigHost `hcl:"host"`
 
 	Credentials        map[string]map[string]interface{}   `hcl:"credentials"`
 	CredentialsHelpers map[string]*ConfigCredentialsHelper `hcl:"credentials_helper"`
 
+	// LockWebhooks are HTTP endpoints to notify of state lock lifecycle
+	// events, keyed by the label of their "lock_webhook" block.
+	LockWebhooks map[string]*ConfigLockWebhook `hcl:"lock_webhook"`
+
+	// DriftWebhooks are HTTP endpoints to send the reports of "tofu drift"
+	// to when it detects drift, keyed by the label of their "drift_webhook"
+	// block.
+	DriftWebhooks map[string]*ConfigDriftWebhook `hcl:"drift_webhook"`
+
+	// CostEstimators are external programs that estimate the cost of plans,
+	// keyed by the label of their "cost_estimator" block. Only one of these
+	// is allowed across the whole configuration.
+	CostEstimators map[string]*ConfigCostEstimator `hcl:"cost_estimator"`
+
+	// RegoPolicies are sets of Rego policies that plans must pass before
+	// they're applied, keyed by the label of their "rego_policy" block.
+	RegoPolicies map[string]*ConfigRegoPolicy `hcl:"rego_policy"`
+
+	// RunHooks are external programs to run at points in the lifecycle of
+	// plan and apply operations, keyed by the label of their "run_hook"
+	// block.
+	RunHooks map[string]*ConfigRunHook `hcl:"run_hook"`
+
+	// Aliases are custom subcommands that run a built-in command with a
+	// fixed set of arguments, keyed by the label of their "alias" block.
+	Aliases map[string]*ConfigAlias `hcl:"alias"`
+
+	// PlanSigning represents any plan_signing blocks in the configuration.
+	// Only one of these is allowed across the whole configuration.
+	PlanSigning []*ConfigPlanSigning
+
 	// ProviderInstallation represents any provider_installation blocks
 	// in the configuration. Only one of these is allowed across the whole
 	// configuration, but we decode into a slice here so that we can handle
@@ -90,6 +139,66 @@ type ConfigCredentialsHelper struct {
 	Args []string `hcl:"args"`
 }
 
+// ConfigLockWebhook is the structure of the "lock_webhook" nested block
+// within the CLI configuration.
+type ConfigLockWebhook struct {
+	URL     string            `hcl:"url"`
+	Events  []string          `hcl:"events"`
+	Headers map[string]string `hcl:"headers"`
+}
+
+// ConfigDriftWebhook is the structure of the "drift_webhook" nested block
+// within the CLI configuration.
+type ConfigDriftWebhook struct {
+	URL     string            `hcl:"url"`
+	Headers map[string]string `hcl:"headers"`
+}
+
+// ConfigCostEstimator is the structure of the "cost_estimator" nested block
+// within the CLI configuration.
+type ConfigCostEstimator struct {
+	Program    string   `hcl:"program"`
+	Args       []string `hcl:"args"`
+	PriceSheet string   `hcl:"price_sheet"`
+}
+
+// ConfigRegoPolicy is the structure of the "rego_policy" nested block within
+// the CLI configuration.
+type ConfigRegoPolicy struct {
+	Paths             []string `hcl:"paths"`
+	Query             string   `hcl:"query"`
+	Program           string   `hcl:"program"`
+	OverrideTokenHash string   `hcl:"override_token_hash"`
+}
+
+// ConfigRunHook is the structure of the "run_hook" nested block within the
+// CLI configuration.
+type ConfigRunHook struct {
+	Program string   `hcl:"program"`
+	Args    []string `hcl:"args"`
+	Events  []string `hcl:"events"`
+}
+
+// ConfigAlias is the structure of the "alias" nested block within the CLI
+// configuration.
+type ConfigAlias struct {
+	// Command is the built-in command that the alias runs, such as "plan"
+	// or "state list".
+	Command string `hcl:"command"`
+
+	// Args are the arguments that the alias passes to the command, before
+	// any arguments given on the command line.
+	Args []string `hcl:"args"`
+}
+
+// lockWebhookEvents are the valid values for the "events" argument of a
+// "lock_webhook" block.
+var lockWebhookEvents = []string{"acquired", "released", "force_unlocked"}
+
+// runHookEvents are the valid values for the "events" argument of a
+// "run_hook" block.
+var runHookEvents = []string{"pre_plan", "post_plan", "pre_apply", "post_apply"}
+
 // BuiltinConfig is the built-in defaults for the configuration. These
 // can be overridden by user configurations.
 var BuiltinConfig Config
@@ -200,6 +309,9 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	ociCredsBlocks, ociCredsDiags := decodeOCIRepositoryCredentialsFromConfig(obj)
 	diags = diags.Append(ociCredsDiags)
 	result.OCIRepositoryCredentials = ociCredsBlocks
+	planSigningBlocks, planSigningDiags := decodePlanSigningFromConfig(obj, path)
+	diags = diags.Append(planSigningDiags)
+	result.PlanSigning = planSigningBlocks
 
 	// Replace all env vars
 	for k, v := range result.Providers {
@@ -213,6 +325,21 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 		result.PluginCacheDir = os.ExpandEnv(result.PluginCacheDir)
 	}
 
+	// Webhook URLs and headers commonly carry secrets, which are better
+	// kept in the environment than in the configuration file.
+	for _, hook := range result.LockWebhooks {
+		hook.URL = os.ExpandEnv(hook.URL)
+		for k, v := range hook.Headers {
+			hook.Headers[k] = os.ExpandEnv(v)
+		}
+	}
+	for _, hook := range result.DriftWebhooks {
+		hook.URL = os.ExpandEnv(hook.URL)
+		for k, v := range hook.Headers {
+			hook.Headers[k] = os.ExpandEnv(v)
+		}
+	}
+
 	return result, diags
 }
 
@@ -274,6 +401,10 @@ func envConfig(env map[string]string) *Config {
 		config.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	if envSchemaCache := env[providerSchemaCacheEnvVar]; envSchemaCache != "" && envSchemaCache != "0" {
+		config.ProviderSchemaCache = true
+	}
+
 	return config
 }
 
@@ -293,6 +424,22 @@ func makeEnvMap(environ []string) map[string]string {
 	return ret
 }
 
+// LockQueueDuration returns the parsed value of the lock_queue_timeout
+// setting, which is zero if it isn't set.
+func (c *Config) LockQueueDuration() (time.Duration, error) {
+	if c.LockQueueTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.LockQueueTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration %s", c.LockQueueTimeout)
+	}
+	return d, nil
+}
+
 // Validate checks for errors in the configuration that cannot be detected
 // just by HCL decoding, returning any problems as diagnostics.
 //
@@ -337,6 +484,116 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		)
 	}
 
+	if c.LockQueueTimeout != "" {
+		if _, err := c.LockQueueDuration(); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The lock_queue_timeout setting must be a non-negative duration, such as \"10m\""),
+			)
+		}
+	}
+
+	// Check that all "lock_webhook" blocks have a valid URL and events.
+	for name, hook := range c.LockWebhooks {
+		if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			diags = diags.Append(
+				fmt.Errorf("The lock_webhook %q block must have a url argument with an absolute http or https URL", name),
+			)
+		}
+		for _, event := range hook.Events {
+			if !slices.Contains(lockWebhookEvents, event) {
+				diags = diags.Append(
+					fmt.Errorf("The lock_webhook %q block has invalid event %q: must be one of %s", name, event, strings.Join(lockWebhookEvents, ", ")),
+				)
+			}
+		}
+	}
+
+	// Check that all "drift_webhook" blocks have a valid URL.
+	for name, hook := range c.DriftWebhooks {
+		if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			diags = diags.Append(
+				fmt.Errorf("The drift_webhook %q block must have a url argument with an absolute http or https URL", name),
+			)
+		}
+	}
+
+	// Should have zero or one "cost_estimator" blocks, which must name
+	// either the program to run or the price sheet to use.
+	if len(c.CostEstimators) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one cost_estimator block may be specified"),
+		)
+	}
+	for name, estimator := range c.CostEstimators {
+		if (estimator.Program == "") == (estimator.PriceSheet == "") {
+			diags = diags.Append(
+				fmt.Errorf("The cost_estimator %q block must have either a program or a price_sheet argument", name),
+			)
+		}
+	}
+
+	// Check that all "rego_policy" blocks name some policies, and that any
+	// override token hash is a hex-encoded SHA-256 hash.
+	for name, policy := range c.RegoPolicies {
+		if len(policy.Paths) == 0 {
+			diags = diags.Append(
+				fmt.Errorf("The rego_policy %q block must have a paths argument", name),
+			)
+		}
+		if h := policy.OverrideTokenHash; h != "" {
+			if b, err := hex.DecodeString(h); err != nil || len(b) != sha256.Size {
+				diags = diags.Append(
+					fmt.Errorf("The rego_policy %q block has an invalid override_token_hash: must be a hex-encoded SHA-256 hash", name),
+				)
+			}
+		}
+	}
+
+	// Check that all "run_hook" blocks name a program and have valid events.
+	for name, hook := range c.RunHooks {
+		if hook.Program == "" {
+			diags = diags.Append(
+				fmt.Errorf("The run_hook %q block must have a program argument", name),
+			)
+		}
+		for _, event := range hook.Events {
+			if !slices.Contains(runHookEvents, event) {
+				diags = diags.Append(
+					fmt.Errorf("The run_hook %q block has invalid event %q: must be one of %s", name, event, strings.Join(runHookEvents, ", ")),
+				)
+			}
+		}
+	}
+
+	// Check that all "alias" blocks have a usable name and name a command.
+	for name, alias := range c.Aliases {
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsFunc(name, unicode.IsSpace) {
+			diags = diags.Append(
+				fmt.Errorf("The alias %q block has an invalid name: must not be empty, start with a dash, or contain spaces", name),
+			)
+		}
+		if strings.TrimSpace(alias.Command) == "" {
+			diags = diags.Append(
+				fmt.Errorf("The alias %q block must have a command argument", name),
+			)
+		}
+	}
+
+	// Should have zero or one "plan_signing" blocks, and a signature can't
+	// be required without any keys to verify it against.
+	if len(c.PlanSigning) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one plan_signing block may be specified"),
+		)
+	}
+	for _, signing := range c.PlanSigning {
+		if signing.RequireSignature && signing.SigningKeyFile == "" && len(signing.TrustedKeyFiles) == 0 {
+			diags = diags.Append(
+				fmt.Errorf("The plan_signing block must have a signing_key_file or trusted_key_files argument when require_signature is set"),
+			)
+		}
+	}
+
 	// Should have zero or one "provider_installation" blocks
 	if len(c.ProviderInstallation) > 1 {
 		diags = diags.Append(
@@ -413,6 +670,13 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	result.ProviderSchemaCache = c.ProviderSchemaCache || c2.ProviderSchemaCache
+
+	result.LockQueueTimeout = c.LockQueueTimeout
+	if result.LockQueueTimeout == "" {
+		result.LockQueueTimeout = c2.LockQueueTimeout
+	}
+
 	if (len(c.Hosts) + len(c2.Hosts)) > 0 {
 		result.Hosts = make(map[string]*ConfigHost)
 		for name, host := range c.Hosts {
@@ -446,6 +710,71 @@ func (c *Config) Merge(c2 *Config) *Config {
 		}
 	}
 
+	if (len(c.LockWebhooks) + len(c2.LockWebhooks)) > 0 {
+		result.LockWebhooks = make(map[string]*ConfigLockWebhook)
+		for name, hook := range c.LockWebhooks {
+			result.LockWebhooks[name] = hook
+		}
+		for name, hook := range c2.LockWebhooks {
+			result.LockWebhooks[name] = hook
+		}
+	}
+
+	if (len(c.DriftWebhooks) + len(c2.DriftWebhooks)) > 0 {
+		result.DriftWebhooks = make(map[string]*ConfigDriftWebhook)
+		for name, hook := range c.DriftWebhooks {
+			result.DriftWebhooks[name] = hook
+		}
+		for name, hook := range c2.DriftWebhooks {
+			result.DriftWebhooks[name] = hook
+		}
+	}
+
+	if (len(c.RegoPolicies) + len(c2.RegoPolicies)) > 0 {
+		result.RegoPolicies = make(map[string]*ConfigRegoPolicy)
+		for name, policy := range c.RegoPolicies {
+			result.RegoPolicies[name] = policy
+		}
+		for name, policy := range c2.RegoPolicies {
+			result.RegoPolicies[name] = policy
+		}
+	}
+
+	if (len(c.RunHooks) + len(c2.RunHooks)) > 0 {
+		result.RunHooks = make(map[string]*ConfigRunHook)
+		for name, hook := range c.RunHooks {
+			result.RunHooks[name] = hook
+		}
+		for name, hook := range c2.RunHooks {
+			result.RunHooks[name] = hook
+		}
+	}
+
+	if (len(c.CostEstimators) + len(c2.CostEstimators)) > 0 {
+		result.CostEstimators = make(map[string]*ConfigCostEstimator)
+		for name, estimator := range c.CostEstimators {
+			result.CostEstimators[name] = estimator
+		}
+		for name, estimator := range c2.CostEstimators {
+			result.CostEstimators[name] = estimator
+		}
+	}
+
+	if (len(c.Aliases) + len(c2.Aliases)) > 0 {
+		result.Aliases = make(map[string]*ConfigAlias)
+		for name, alias := range c.Aliases {
+			result.Aliases[name] = alias
+		}
+		for name, alias := range c2.Aliases {
+			result.Aliases[name] = alias
+		}
+	}
+
+	if (len(c.PlanSigning) + len(c2.PlanSigning)) > 0 {
+		result.PlanSigning = append(result.PlanSigning, c.PlanSigning...)
+		result.PlanSigning = append(result.PlanSigning, c2.PlanSigning...)
+	}
+
 	if (len(c.ProviderInstallation) + len(c2.ProviderInstallation)) > 0 {
 		result.ProviderInstallation = append(result.ProviderInstallation, c.ProviderInstallation...)
 		result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
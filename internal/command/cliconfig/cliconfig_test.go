@@ -117,6 +117,20 @@ func TestEnvConfig(t *testing.T) {
 			},
 			&Config{},
 		},
+		"TF_PROVIDER_SCHEMA_CACHE=1": {
+			map[string]string{
+				"TF_PROVIDER_SCHEMA_CACHE": "1",
+			},
+			&Config{
+				ProviderSchemaCache: true,
+			},
+		},
+		"TF_PROVIDER_SCHEMA_CACHE=0": {
+			map[string]string{
+				"TF_PROVIDER_SCHEMA_CACHE": "0",
+			},
+			&Config{},
+		},
 		"TF_PLUGIN_CACHE_DIR and TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE": {
 			map[string]string{
 				"TF_PLUGIN_CACHE_DIR":                            "beep",
@@ -270,6 +284,162 @@ func TestLoadConfig_credentials(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_lockWebhooks(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "lock-webhooks"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		LockWebhooks: map[string]*ConfigLockWebhook{
+			"alerts": {
+				URL:    "https://hooks.example.com/tofu",
+				Events: []string{"acquired", "force_unlocked"},
+				Headers: map[string]string{
+					"Authorization": "Bearer abc123",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_driftWebhooks(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "drift-webhooks"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		DriftWebhooks: map[string]*ConfigDriftWebhook{
+			"alerts": {
+				URL: "https://hooks.example.com/drift",
+				Headers: map[string]string{
+					"Authorization": "Bearer abc123",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_runHooks(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "run-hooks"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		RunHooks: map[string]*ConfigRunHook{
+			"tickets": {
+				Program: "/usr/local/bin/check-ticket",
+				Args:    []string{"--project", "INFRA"},
+				Events:  []string{"pre_plan", "pre_apply"},
+			},
+			"notify": {
+				Program: "notify-chat",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_regoPolicies(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "rego-policies"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		RegoPolicies: map[string]*ConfigRegoPolicy{
+			"org": {
+				Paths:             []string{"/etc/tofu/policies"},
+				Query:             "data.org.tofu",
+				Program:           "/usr/local/bin/opa",
+				OverrideTokenHash: "952c46e05e2bc3af57296712457295dae617faf086ff88ee759cb188f086cac0",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_costEstimator(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "cost-estimator"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		CostEstimators: map[string]*ConfigCostEstimator{
+			"infracost": {
+				Program: "/usr/local/bin/infracost-tofu",
+				Args:    []string{"--currency", "EUR"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_aliases(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "aliases"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		Aliases: map[string]*ConfigAlias{
+			"preview": {
+				Command: "plan",
+				Args:    []string{"-refresh=false", "-compact-warnings", "-var-file=dev.tfvars"},
+			},
+			"resources": {
+				Command: "state list",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
+func TestLoadConfig_planSigning(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "plan-signing"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+
+	want := &Config{
+		PlanSigning: []*ConfigPlanSigning{
+			{
+				SigningKeyFile:       "/etc/tofu/plan-signing.asc",
+				SigningKeyPassphrase: "hunter2",
+				TrustedKeyFiles:      []string{"/etc/tofu/release.pub.asc"},
+				RequireSignature:     true,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+}
+
 func TestConfigValidate(t *testing.T) {
 	tests := map[string]struct {
 		Config    *Config
@@ -359,6 +529,139 @@ func TestConfigValidate(t *testing.T) {
 			},
 			1, // no more than one provider_installation block allowed
 		},
+		"lock_webhook good": {
+			&Config{
+				LockWebhooks: map[string]*ConfigLockWebhook{
+					"foo": {URL: "https://example.com/hook", Events: []string{"released"}},
+				},
+			},
+			0,
+		},
+		"plan_signing good": {
+			&Config{
+				PlanSigning: []*ConfigPlanSigning{
+					{TrustedKeyFiles: []string{"release.asc"}, RequireSignature: true},
+				},
+			},
+			0,
+		},
+		"plan_signing requiring a signature without keys": {
+			&Config{
+				PlanSigning: []*ConfigPlanSigning{
+					{RequireSignature: true},
+				},
+			},
+			1, // can't verify signatures without any keys
+		},
+		"plan_signing multiple": {
+			&Config{
+				PlanSigning: []*ConfigPlanSigning{
+					{SigningKeyFile: "a.asc"},
+					{SigningKeyFile: "b.asc"},
+				},
+			},
+			1, // no more than one plan_signing block allowed
+		},
+		"cost_estimator without program": {
+			&Config{
+				CostEstimators: map[string]*ConfigCostEstimator{
+					"foo": {Args: []string{"--json"}},
+				},
+			},
+			1, // the program is required
+		},
+		"cost_estimator with program and price sheet": {
+			&Config{
+				CostEstimators: map[string]*ConfigCostEstimator{
+					"foo": {Program: "foo", PriceSheet: "prices.json"},
+				},
+			},
+			1, // only one of program and price_sheet is allowed
+		},
+		"cost_estimator with price sheet": {
+			&Config{
+				CostEstimators: map[string]*ConfigCostEstimator{
+					"foo": {PriceSheet: "prices.json"},
+				},
+			},
+			0,
+		},
+		"alias without command": {
+			&Config{
+				Aliases: map[string]*ConfigAlias{
+					"preview": {Args: []string{"-refresh=false"}},
+				},
+			},
+			1, // the command is required
+		},
+		"alias with invalid name": {
+			&Config{
+				Aliases: map[string]*ConfigAlias{
+					"-preview": {Command: "plan"},
+					"my plan":  {Command: "plan"},
+				},
+			},
+			2, // names can't start with a dash or contain spaces
+		},
+		"cost_estimator multiple": {
+			&Config{
+				CostEstimators: map[string]*ConfigCostEstimator{
+					"foo": {Program: "foo"},
+					"bar": {Program: "bar"},
+				},
+			},
+			1, // no more than one cost_estimator block allowed
+		},
+		"lock_webhook with bad url and event": {
+			&Config{
+				LockWebhooks: map[string]*ConfigLockWebhook{
+					"foo": {URL: "example.com/hook", Events: []string{"stolen"}},
+				},
+			},
+			2, // url must be absolute, and the event is not valid
+		},
+		"drift_webhook with bad url": {
+			&Config{
+				DriftWebhooks: map[string]*ConfigDriftWebhook{
+					"foo": {URL: "ftp://example.com/hook"},
+				},
+			},
+			1, // url must be http or https
+		},
+		"lock_queue_timeout good": {
+			&Config{
+				LockQueueTimeout: "15m",
+			},
+			0,
+		},
+		"lock_queue_timeout not a duration": {
+			&Config{
+				LockQueueTimeout: "forever",
+			},
+			1, // must be a duration
+		},
+		"lock_queue_timeout negative": {
+			&Config{
+				LockQueueTimeout: "-1m",
+			},
+			1, // must not be negative
+		},
+		"run_hook without program and with bad event": {
+			&Config{
+				RunHooks: map[string]*ConfigRunHook{
+					"foo": {Events: []string{"pre_destroy"}},
+				},
+			},
+			2, // the program is required, and the event is not valid
+		},
+		"rego_policy without paths and with bad override hash": {
+			&Config{
+				RegoPolicies: map[string]*ConfigRegoPolicy{
+					"foo": {OverrideTokenHash: "not-a-hash"},
+				},
+			},
+			2, // paths are required, and the hash must be a SHA-256 hash
+		},
 		"plugin_cache_dir does not exist": {
 			&Config{
 				PluginCacheDir: "fake",
@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigPlanSigning is the structure of the "plan_signing" nested block
+// within the CLI configuration, which configures the OpenPGP keys used to sign
+// saved plan files and to verify them before they are applied.
+type ConfigPlanSigning struct {
+	SigningKeyFile       string   `hcl:"signing_key_file"`
+	SigningKeyPassphrase string   `hcl:"signing_key_passphrase"`
+	TrustedKeyFiles      []string `hcl:"trusted_key_files"`
+	RequireSignature     bool     `hcl:"require_signature"`
+}
+
+func decodePlanSigningFromConfig(hclFile *hclast.File, filename string) ([]*ConfigPlanSigning, tfdiags.Diagnostics) {
+	const errInvalidSummary = "Invalid plan_signing block"
+	var ret []*ConfigPlanSigning
+	var diags tfdiags.Diagnostics
+
+	root, ok := hclFile.Node.(*hclast.ObjectList)
+	if !ok {
+		return ret, diags
+	}
+	for _, block := range root.Items {
+		if block.Keys[0].Token.Value() != "plan_signing" {
+			continue
+		}
+
+		isJSON := block.Keys[0].Token.JSON
+		if block.Assign.Line != 0 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The plan_signing block at %s must not be introduced with an equals sign.", block.Pos()),
+			))
+			continue
+		}
+		if len(block.Keys) > 1 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The plan_signing block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+		body, ok := block.Val.(*hclast.ObjectType)
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The plan_signing block at %s must be represented by a JSON object.", block.Pos()),
+			))
+			continue
+		}
+
+		signing := &ConfigPlanSigning{}
+		if err := hcl.DecodeObject(signing, body); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("Invalid plan_signing block at %s: %s.", body.Pos(), err),
+			))
+			continue
+		}
+
+		// Key files are resolved relative to the directory containing the
+		// file where this block came from, and the passphrase can be taken
+		// from the environment so that it need not be written in the file.
+		baseDir := filepath.Dir(filename)
+		if signing.SigningKeyFile != "" {
+			signing.SigningKeyFile = resolveKeyFilePath(baseDir, os.ExpandEnv(signing.SigningKeyFile))
+		}
+		signing.SigningKeyPassphrase = os.ExpandEnv(signing.SigningKeyPassphrase)
+		for i, keyFile := range signing.TrustedKeyFiles {
+			signing.TrustedKeyFiles[i] = resolveKeyFilePath(baseDir, os.ExpandEnv(keyFile))
+		}
+		ret = append(ret, signing)
+	}
+
+	return ret, diags
+}
+
+func resolveKeyFilePath(baseDir, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	// As with the OCI credentials configuration, we make a best effort to
+	// make the path absolute so that it isn't reinterpreted if the process
+	// changes working directory after loading the CLI configuration.
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	return path
+}
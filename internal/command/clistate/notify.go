@@ -0,0 +1,203 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clistate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// LockEventType identifies a kind of state lock lifecycle event.
+type LockEventType string
+
+const (
+	LockEventAcquired      LockEventType = "acquired"
+	LockEventReleased      LockEventType = "released"
+	LockEventForceUnlocked LockEventType = "force_unlocked"
+)
+
+// LockEvent describes a single state lock lifecycle event, as delivered to
+// a LockNotifier.
+type LockEvent struct {
+	Type LockEventType `json:"event"`
+
+	// Workspace is the name of the workspace whose state was locked. It is
+	// empty for locks that aren't for the state of a workspace, such as the
+	// lock on the backend settings saved in the working directory.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Lock is the lock information for the lock the event relates to.
+	Lock *statemgr.LockInfo `json:"lock"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LockNotifier is implemented by types that want to be told about state lock
+// lifecycle events, such as to alert on long-held or force-broken locks.
+//
+// Notifications are best-effort: NotifyLock must not block and has no way to
+// report failure, since locking must not be affected by problems delivering
+// notifications.
+type LockNotifier interface {
+	NotifyLock(ctx context.Context, event LockEvent)
+
+	// Flush waits for up to the given timeout for the delivery of any
+	// events that are still in progress. It is called once, just before
+	// OpenTofu exits, and NotifyLock must not be called after it.
+	Flush(timeout time.Duration)
+}
+
+// LockWebhook describes a single HTTP endpoint to notify of lock events.
+type LockWebhook struct {
+	URL string
+
+	// Events is the set of events to deliver to this webhook. If empty,
+	// all events are delivered.
+	Events []LockEventType
+
+	// Headers are extra HTTP request headers to send with each request,
+	// such as for authentication.
+	Headers map[string]string
+}
+
+// lockWebhookTimeout is the maximum time we'll wait for each webhook request.
+const lockWebhookTimeout = 10 * time.Second
+
+// lockWebhookQueueSize is the number of events that can wait to be delivered
+// before further events are dropped.
+const lockWebhookQueueSize = 64
+
+type webhookNotifier struct {
+	hooks  []LockWebhook
+	client *http.Client
+
+	// Events are delivered in the order they happened by a single
+	// goroutine, which reads them from queue and closes done once queue is
+	// closed by Flush.
+	mu      sync.Mutex
+	queue   chan webhookEvent
+	done    chan struct{}
+	flushed bool
+}
+
+type webhookEvent struct {
+	ctx   context.Context
+	event LockEvent
+}
+
+var _ LockNotifier = (*webhookNotifier)(nil)
+
+// NewWebhookNotifier returns a LockNotifier that delivers each event as a
+// JSON-encoded HTTP POST request to each of the given webhooks that are
+// subscribed to that event type.
+//
+// The requests are sent in the background, so that a slow webhook doesn't
+// delay the operation that took or released the lock.
+//
+// Returns nil if hooks is empty, which callers can use to skip notification
+// entirely.
+func NewWebhookNotifier(hooks []LockWebhook) LockNotifier {
+	if len(hooks) == 0 {
+		return nil
+	}
+	client := httpclient.New(context.Background())
+	client.Timeout = lockWebhookTimeout
+	n := &webhookNotifier{
+		hooks:  hooks,
+		client: client,
+		queue:  make(chan webhookEvent, lockWebhookQueueSize),
+		done:   make(chan struct{}),
+	}
+	go n.deliver()
+	return n
+}
+
+func (n *webhookNotifier) NotifyLock(ctx context.Context, event LockEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.flushed {
+		log.Printf("[WARN] clistate: dropping lock %s event reported after notifications were flushed", event.Type)
+		return
+	}
+	select {
+	case n.queue <- webhookEvent{ctx: ctx, event: event}:
+	default:
+		log.Printf("[WARN] clistate: dropping lock %s event because too many events are waiting to be delivered", event.Type)
+	}
+}
+
+func (n *webhookNotifier) Flush(timeout time.Duration) {
+	n.mu.Lock()
+	if !n.flushed {
+		n.flushed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	select {
+	case <-n.done:
+	case <-time.After(timeout):
+		log.Printf("[WARN] clistate: gave up waiting for lock event notifications after %s", timeout)
+	}
+}
+
+// deliver sends the queued events to the webhooks until the queue is closed.
+func (n *webhookNotifier) deliver() {
+	defer close(n.done)
+
+	for queued := range n.queue {
+		event := queued.event
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("[WARN] clistate: failed to encode lock %s event: %s", event.Type, err)
+			continue
+		}
+
+		for _, hook := range n.hooks {
+			if len(hook.Events) != 0 && !slices.Contains(hook.Events, event.Type) {
+				continue
+			}
+			if err := n.send(queued.ctx, hook, body); err != nil {
+				log.Printf("[WARN] clistate: failed to deliver lock %s event to %s: %s", event.Type, hook.URL, err)
+			}
+		}
+	}
+}
+
+func (n *webhookNotifier) send(ctx context.Context, hook LockWebhook, body []byte) error {
+	// Notifications must still be delivered when the operation that
+	// released the lock was itself cancelled.
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
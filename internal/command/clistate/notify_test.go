@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clistate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var received []LockEvent
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event LockEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid request body: %s", err)
+		}
+		mu.Lock()
+		received = append(received, event)
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]LockWebhook{
+		{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			// Only subscribed to force-unlock, so should receive nothing here.
+			URL:    server.URL + "/force",
+			Events: []LockEventType{LockEventForceUnlocked},
+		},
+	})
+
+	streams, _ := terminal.StreamsForTesting(t)
+	view := views.NewStateLocker(arguments.ViewHuman, views.NewView(streams))
+	l := NewNotifyingLocker(0, view, notifier, "staging")
+
+	s := statemgr.NewFullFake(nil, nil)
+	if diags := l.Lock(s, "test-lock"); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if diags := l.Unlock(); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	// The events are delivered in the background until they're flushed.
+	notifier.Flush(5 * time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("expected 2 events, got %d: %#v", len(received), received)
+	}
+	if received[0].Type != LockEventAcquired || received[1].Type != LockEventReleased {
+		t.Errorf("wrong event types %q, %q", received[0].Type, received[1].Type)
+	}
+	for i, event := range received {
+		if event.Lock == nil || event.Lock.Operation != "test-lock" || event.Lock.ID != "placeholder" {
+			t.Errorf("wrong lock info in event %d: %#v", i, event.Lock)
+		}
+		if event.Workspace != "staging" {
+			t.Errorf("wrong workspace in event %d: %q", i, event.Workspace)
+		}
+		if authHeaders[i] != "Bearer secret" {
+			t.Errorf("wrong Authorization header in event %d: %q", i, authHeaders[i])
+		}
+	}
+}
+
+func TestNewWebhookNotifier_empty(t *testing.T) {
+	if got := NewWebhookNotifier(nil); got != nil {
+		t.Errorf("expected nil notifier for no webhooks, got %#v", got)
+	}
+}
+
+func TestWebhookNotifier_flushTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	notifier := NewWebhookNotifier([]LockWebhook{{URL: server.URL}})
+
+	// A webhook that doesn't respond must delay neither the notification
+	// nor, for longer than the given timeout, the exit.
+	start := time.Now()
+	notifier.NotifyLock(t.Context(), LockEvent{Type: LockEventAcquired, Lock: statemgr.NewLockInfo()})
+	notifier.Flush(50 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("notification and flush took %s", elapsed)
+	}
+
+	// Events reported after the flush are dropped rather than panicking.
+	notifier.NotifyLock(t.Context(), LockEvent{Type: LockEventReleased, Lock: statemgr.NewLockInfo()})
+}
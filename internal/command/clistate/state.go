@@ -11,6 +11,7 @@ package clistate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -62,6 +63,12 @@ type Locker interface {
 	// Lock the provided state manager, storing the reason string in the LockInfo.
 	Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics
 
+	// LockShared is like Lock, but requests a shared lock that may be held
+	// concurrently with other shared locks, for callers that only read the
+	// state. State managers that don't support shared locks will take an
+	// exclusive lock instead.
+	LockShared(s statemgr.Locker, reason string) tfdiags.Diagnostics
+
 	// Unlock the previously locked state.
 	Unlock() tfdiags.Diagnostics
 
@@ -70,25 +77,38 @@ type Locker interface {
 }
 
 type locker struct {
-	ctx     context.Context
-	timeout time.Duration
-	mu      sync.Mutex
-	state   statemgr.Locker
-	view    views.StateLocker
-	lockID  string
+	ctx       context.Context
+	timeout   time.Duration
+	mu        sync.Mutex
+	state     statemgr.Locker
+	view      views.StateLocker
+	notifier  LockNotifier
+	workspace string
+	lockID    string
+	lockInfo  *statemgr.LockInfo
 }
 
 var _ Locker = (*locker)(nil)
 
 // Create a new Locker.
-// This Locker uses state.LockWithContext to retry the lock until the provided
-// timeout is reached, or the context is canceled. Lock progress will be be
-// reported to the user through the provided UI.
+// This Locker uses statemgr.LockWithProgress to retry the lock until the
+// provided timeout is reached, or the context is canceled. Lock progress,
+// including the current lock holder, will be reported to the user through the
+// provided UI.
 func NewLocker(timeout time.Duration, view views.StateLocker) Locker {
+	return NewNotifyingLocker(timeout, view, nil, "")
+}
+
+// NewNotifyingLocker is like NewLocker, but additionally reports each
+// successful lock and unlock to the given notifier, if it is not nil, as an
+// event for the state of the given workspace.
+func NewNotifyingLocker(timeout time.Duration, view views.StateLocker, notifier LockNotifier, workspace string) Locker {
 	return &locker{
-		ctx:     context.Background(),
-		timeout: timeout,
-		view:    view,
+		ctx:       context.Background(),
+		timeout:   timeout,
+		view:      view,
+		notifier:  notifier,
+		workspace: workspace,
 	}
 }
 
@@ -99,9 +119,11 @@ func (l *locker) WithContext(ctx context.Context) Locker {
 		panic("nil context")
 	}
 	return &locker{
-		ctx:     ctx,
-		timeout: l.timeout,
-		view:    l.view,
+		ctx:       ctx,
+		timeout:   l.timeout,
+		view:      l.view,
+		notifier:  l.notifier,
+		workspace: l.workspace,
 	}
 }
 
@@ -109,6 +131,15 @@ func (l *locker) WithContext(ctx context.Context) Locker {
 // longer than the threshold. The lock is retried until the context is
 // cancelled.
 func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
+	return l.lock(s, reason, false)
+}
+
+// LockShared is like Lock, but requests a shared lock.
+func (l *locker) LockShared(s statemgr.Locker, reason string) tfdiags.Diagnostics {
+	return l.lock(s, reason, true)
+}
+
+func (l *locker) lock(s statemgr.Locker, reason string, shared bool) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
 	l.mu.Lock()
@@ -121,21 +152,36 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 
 	lockInfo := statemgr.NewLockInfo()
 	lockInfo.Operation = reason
+	lockInfo.Shared = shared
 
 	err := slowmessage.Do(LockThreshold, func() error {
-		id, err := statemgr.LockWithContext(ctx, s, lockInfo)
+		id, err := statemgr.LockWithProgress(ctx, s, lockInfo, l.view.LockWaiting)
 		l.lockID = id
 		return err
 	}, l.view.Locking)
 
 	if err != nil {
-		diags = diags.Append(tfdiags.Sourceless(
+		diag := tfdiags.Sourceless(
 			tfdiags.Error,
 			"Error acquiring the state lock",
 			fmt.Sprintf(LockErrorMessage, err),
-		))
+		)
+		// A lock error that describes the existing lock means that another
+		// operation holds it. Other failures might have a more specific
+		// code of their own, such as when the backend rejected our
+		// credentials.
+		var lockErr *statemgr.LockError
+		if errors.As(err, &lockErr) && lockErr.Info != nil {
+			diag = tfdiags.WithErrorCode(diag, tfdiags.ErrorCodeStateLocked)
+		} else if code := tfdiags.ErrorCodeOf(err); code != "" {
+			diag = tfdiags.WithErrorCode(diag, code)
+		}
+		diags = diags.Append(diag)
+		return diags
 	}
 
+	l.lockInfo = lockInfo
+	l.notify(LockEventAcquired)
 	return diags
 }
 
@@ -159,10 +205,29 @@ func (l *locker) Unlock() tfdiags.Diagnostics {
 			"Error releasing the state lock",
 			fmt.Sprintf(UnlockErrorMessage, err),
 		))
+		return diags
 	}
 
+	l.notify(LockEventReleased)
 	return diags
+}
 
+// notify reports an event for the currently-held lock to the notifier, if
+// any. The caller must hold l.mu.
+func (l *locker) notify(typ LockEventType) {
+	if l.notifier == nil || l.lockInfo == nil || l.lockID == "" {
+		return
+	}
+	info := *l.lockInfo
+	// The lock implementation is permitted to override the ID on the way
+	// through, so we report the one it actually returned.
+	info.ID = l.lockID
+	l.notifier.NotifyLock(l.ctx, LockEvent{
+		Type:      typ,
+		Workspace: l.workspace,
+		Lock:      &info,
+		Timestamp: time.Now().UTC(),
+	})
 }
 
 func (l *locker) Timeout() time.Duration {
@@ -186,6 +251,10 @@ func (l noopLocker) Lock(statemgr.Locker, string) tfdiags.Diagnostics {
 	return nil
 }
 
+func (l noopLocker) LockShared(statemgr.Locker, string) tfdiags.Diagnostics {
+	return nil
+}
+
 func (l noopLocker) Unlock() tfdiags.Diagnostics {
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 func TestUnlock(t *testing.T) {
@@ -28,3 +29,25 @@ func TestUnlock(t *testing.T) {
 		t.Error("expected error")
 	}
 }
+
+func TestLock_errorCode(t *testing.T) {
+	streams, _ := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+	s := statemgr.NewFullFake(nil, nil)
+
+	l := NewLocker(0, views.NewStateLocker(arguments.ViewHuman, view))
+	if diags := l.Lock(s, "test-lock"); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	defer l.Unlock()
+
+	// A second lock of the same state conflicts with the first one.
+	other := NewLocker(0, views.NewStateLocker(arguments.ViewHuman, view))
+	diags := other.Lock(s, "test-lock")
+	if !diags.HasErrors() {
+		t.Fatal("expected error")
+	}
+	if got, want := diags.ErrorCode(), tfdiags.ErrorCodeStateLocked; got != want {
+		t.Errorf("wrong error code %q; want %q", got, want)
+	}
+}
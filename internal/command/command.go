@@ -38,6 +38,11 @@ const DefaultVarsExtension = ".tfvars"
 // DefaultVarsFilename is the default filename used for vars
 const DefaultVarsFilename = "terraform" + DefaultVarsExtension
 
+// WorkspaceVarsDir is the directory that contains a subdirectory of variables
+// files for each workspace, which are loaded automatically when that
+// workspace is selected.
+const WorkspaceVarsDir = "workspaces"
+
 // DefaultBackupExtension is added to the state file to form the path
 const DefaultBackupExtension = ".backup"
 
@@ -45,6 +50,10 @@ const DefaultBackupExtension = ".backup"
 // operations as it walks the dependency graph.
 const DefaultParallelism = 10
 
+// DefaultInstallJobs is the limit "tofu init" places on the number of modules
+// and provider packages it downloads at the same time.
+const DefaultInstallJobs = 10
+
 // ErrUnsupportedLocalOp is the common error message shown for operations
 // that require a backend.Local.
 const ErrUnsupportedLocalOp = `The configured backend doesn't support this operation.
@@ -24,6 +24,8 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/opentofu/svchost"
@@ -164,6 +166,12 @@ func testModuleWithSnapshot(t *testing.T, name string) (*configs.Config, *config
 		t.Fatal(instDiags.Err())
 	}
 
+	// Since module installer has modified the module manifest on disk, we need
+	// to refresh the cache of it in the loader.
+	if err := loader.RefreshModules(); err != nil {
+		t.Fatalf("failed to refresh modules after installation: %s", err)
+	}
+
 	config, snap, diags := loader.LoadConfigWithSnapshot(t.Context(), dir, configs.RootModuleCallForTesting())
 	if diags.HasErrors() {
 		t.Fatal(diags.Error())
@@ -544,6 +552,33 @@ func testTempFile(t *testing.T) string {
 	return filepath.Join(testTempDirRealpath(t), "state.tfstate")
 }
 
+// testPlanSigningKey generates a new OpenPGP private key for signing plan
+// files and writes it to a temporary file, returning its path.
+func testPlanSigningKey(t *testing.T) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("OpenTofu Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.asc")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
 // testTempDirRealpath is like [testing.T.TempDir] but takes the
 // extra step of ensuring that the result is a path that does not
 // include any symlinks.
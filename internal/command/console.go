@@ -14,6 +14,8 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/repl"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -33,6 +35,8 @@ func (c *ConsoleCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.extendedFlagSet("console")
 	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	var planPath string
+	cmdFlags.StringVar(&planPath, "plan", "", "path")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
@@ -62,6 +66,32 @@ func (c *ConsoleCommand) Run(args []string) int {
 		return 1
 	}
 
+	var planFile *planfile.WrappedPlanFile
+	if planPath != "" {
+		if !c.variableArgs.Empty() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Can't set variables when evaluating a saved plan",
+				"The -var and -var-file options cannot be used with -plan, because a saved plan includes the variable values that were set when it was created.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		planFile, err = c.PlanFile(planPath, enc.Plan())
+		if err == nil && planFile == nil {
+			err = fmt.Errorf("the path is a directory, not a plan file")
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Failed to load %q as a plan file", planPath),
+				fmt.Sprintf("Error: %s", err),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
 	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
 	diags = diags.Append(backendDiags)
 	if diags.HasErrors() {
@@ -93,6 +123,7 @@ func (c *ConsoleCommand) Run(args []string) int {
 	// Build the operation
 	opReq := c.Operation(ctx, b, arguments.ViewHuman, enc)
 	opReq.ConfigDir = configPath
+	opReq.PlanFile = planFile
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	opReq.AllowUnsetVariables = true // we'll just evaluate them as unknown
 	if err != nil {
@@ -142,6 +173,9 @@ func (c *ConsoleCommand) Run(args []string) int {
 		// not actually making a plan.
 		evalOpts.SetVariables = lr.PlanOpts.SetVariables
 	}
+	// When given a saved plan, we evaluate against its planned values
+	// instead, using the prior state and variable values recorded in it.
+	evalOpts.Plan = lr.Plan
 
 	// Before we can evaluate expressions, we must compute and populate any
 	// derived values (input variables, local values, output values)
@@ -177,7 +211,39 @@ func (c *ConsoleCommand) Run(args []string) int {
 		return c.modePiped(session, ui)
 	}
 
-	return c.modeInteractive(session, ui)
+	return c.modeInteractive(session, ui, newConsoleAddressCompleter(consoleAddresses(lr)))
+}
+
+// consoleAddresses returns the addresses of the root module resources and
+// resource instances that can be referred to in the console, for completion.
+func consoleAddresses(lr *backend.LocalRun) []string {
+	var ret []string
+	add := func(addr addrs.AbsResourceInstance) {
+		if !addr.Module.IsRoot() {
+			return
+		}
+		ret = append(ret, addr.Resource.Resource.String())
+		if addr.Resource.Key != addrs.NoKey {
+			ret = append(ret, addr.Resource.String())
+		}
+	}
+	if lr.InputState != nil {
+		if ms := lr.InputState.RootModule(); ms != nil {
+			for _, rs := range ms.Resources {
+				for key := range rs.Instances {
+					add(rs.Addr.Instance(key))
+				}
+			}
+		}
+	}
+	if lr.Plan != nil {
+		for _, rc := range lr.Plan.Changes.Resources {
+			if rc.Action != plans.Delete {
+				add(rc.Addr)
+			}
+		}
+	}
+	return ret
 }
 
 func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui) int {
@@ -223,6 +289,10 @@ Usage: tofu [global options] console [options]
 
   This command will never modify your state.
 
+  The interactive console keeps a history of the expressions you enter
+  across sessions, and can complete the addresses of resources in the
+  state when you press Tab.
+
 Options:
 
   -compact-warnings      If OpenTofu produces any warnings that are not
@@ -237,6 +307,10 @@ Options:
                          will be performed. All locations, for all errors
                          will be listed. Disabled by default
 
+  -plan=path             Evaluate expressions against the planned values in
+                         the given saved plan file, instead of the values
+                         in the current state.
+
   -state=path            Legacy option for the local backend only. See the local
                          backend's documentation for more information.
 
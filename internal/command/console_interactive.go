@@ -9,22 +9,41 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"unicode"
 
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/repl"
 
 	"github.com/chzyer/readline"
 	"github.com/mitchellh/cli"
 )
 
-func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
+// consoleHistoryFilename is the name of the file in the CLI configuration
+// directory where the interactive console keeps its history across sessions.
+const consoleHistoryFilename = "console_history"
+
+func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui, completer readline.AutoCompleter) int {
+	// We only keep history for real terminal sessions, and not for unit
+	// tests that drive the console through a pipe without setting up
+	// Meta.Streams.
+	var historyFile string
+	if c.Streams != nil {
+		historyFile = consoleHistoryFile()
+	}
+
 	// Configure input
 	l, err := readline.NewEx(&readline.Config{
 		Prompt:            "> ",
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
+		HistoryFile:       historyFile,
 		HistorySearchFold: true,
+		AutoComplete:      completer,
 		Stdin:             os.Stdin,
 		Stdout:            os.Stdout,
 		Stderr:            os.Stderr,
@@ -43,6 +62,13 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 		// Read a line
 		line, err := l.Readline()
 		if errors.Is(err, readline.ErrInterrupt) {
+			if consoleState.commandInOpenState() > 0 {
+				// An interrupt while entering a multi-line expression
+				// discards it, rather than leaving the console.
+				consoleState = consoleBracketState{}
+				l.SetPrompt("> ")
+				continue
+			}
 			if len(line) == 0 {
 				break
 			} else {
@@ -80,3 +106,76 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 
 	return 0
 }
+
+// consoleHistoryFile returns the path of the file to keep the console history
+// in, or an empty string to keep no history if there's no CLI configuration
+// directory.
+func consoleHistoryFile() string {
+	dir, err := cliconfig.ConfigDir()
+	if err != nil {
+		log.Printf("[WARN] Not keeping console history: %s", err)
+		return ""
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("[WARN] Not keeping console history: %s", err)
+		return ""
+	}
+	return filepath.Join(dir, consoleHistoryFilename)
+}
+
+// consoleAddressCompleter completes the addresses of resources and resource
+// instances at the cursor in the interactive console.
+type consoleAddressCompleter struct {
+	// addrs is the sorted list of addresses to offer as completions.
+	addrs []string
+}
+
+var _ readline.AutoCompleter = (*consoleAddressCompleter)(nil)
+
+// Do implements readline.AutoCompleter by returning the remainder of each
+// address that starts with the partial address before the cursor.
+func (c *consoleAddressCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	start := pos
+	for start > 0 && isConsoleAddressRune(line[start-1]) {
+		start--
+	}
+	prefix := string(line[start:pos])
+	if prefix == "" {
+		// There's nothing to complete, so Tab just indents as usual, which
+		// keeps pasted multi-line expressions intact.
+		return [][]rune{{'\t'}}, 0
+	}
+
+	var ret [][]rune
+	for _, addr := range c.addrs {
+		if strings.HasPrefix(addr, prefix) && addr != prefix {
+			ret = append(ret, []rune(addr[len(prefix):]))
+		}
+	}
+	return ret, len([]rune(prefix))
+}
+
+func isConsoleAddressRune(r rune) bool {
+	switch r {
+	case '_', '-', '.', '[', ']', '"':
+		return true
+	default:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+}
+
+// newConsoleAddressCompleter returns a completer for the given addresses,
+// ignoring duplicates.
+func newConsoleAddressCompleter(addrs []string) *consoleAddressCompleter {
+	seen := make(map[string]struct{}, len(addrs))
+	var unique []string
+	for _, addr := range addrs {
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		unique = append(unique, addr)
+	}
+	sort.Strings(unique)
+	return &consoleAddressCompleter{addrs: unique}
+}
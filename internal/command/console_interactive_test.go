@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -119,3 +120,65 @@ func TestConsole_multiline_interactive(t *testing.T) {
 		})
 	}
 }
+
+func TestConsoleAddressCompleter(t *testing.T) {
+	completer := newConsoleAddressCompleter([]string{
+		"test_instance.foo",
+		`test_instance.foo["a"]`,
+		"test_instance.bar",
+		"data.test_data_source.foo",
+		"test_instance.foo",
+	})
+
+	testCases := map[string]struct {
+		line       string
+		want       []string
+		wantLength int
+	}{
+		"resource type": {
+			line:       "test_in",
+			want:       []string{"stance.bar", "stance.foo", `stance.foo["a"]`},
+			wantLength: 7,
+		},
+		"inside an expression": {
+			line:       "length(test_instance.f",
+			want:       []string{"oo", `oo["a"]`},
+			wantLength: 15,
+		},
+		"instance key": {
+			line:       "test_instance.foo[",
+			want:       []string{`"a"]`},
+			wantLength: 18,
+		},
+		"data resource": {
+			line:       "data.",
+			want:       []string{"test_data_source.foo"},
+			wantLength: 5,
+		},
+		"no match": {
+			line:       "var.",
+			wantLength: 4,
+		},
+		"nothing to complete": {
+			line: "1 + ",
+			want: []string{"\t"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			line := []rune(tc.line)
+			candidates, length := completer.Do(line, len(line))
+			var got []string
+			for _, c := range candidates {
+				got = append(got, string(c))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong candidates\n%s", diff)
+			}
+			if length != tc.wantLength {
+				t.Errorf("wrong length %d; want %d", length, tc.wantLength)
+			}
+		})
+	}
+}
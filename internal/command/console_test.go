@@ -350,3 +350,68 @@ func TestConsole_multiline_pipe(t *testing.T) {
 		})
 	}
 }
+
+func TestConsole_plan(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	planCmd := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	if code := planCmd.Run([]string{"-out=saved.tfplan"}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+
+	ui := cli.NewMockUi()
+	view, _ = testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	var output bytes.Buffer
+	defer testStdinPipe(t, strings.NewReader("test_instance.foo.ami\n"))()
+	outCloser := testStdoutCapture(t, &output)
+
+	code := c.Run([]string{"-plan=saved.tfplan"})
+	outCloser()
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if got, want := output.String(), "\"bar\"\n"; got != want {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConsole_planWithVars(t *testing.T) {
+	testCwdTemp(t)
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-plan=saved.tfplan", "-var", "foo=bar"})
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Can't set variables when evaluating a saved plan"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+}
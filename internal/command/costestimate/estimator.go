@@ -0,0 +1,65 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package costestimate produces cost estimates for plans with the cost
+// provider configured in the CLI configuration, such as an external program
+// or a price sheet, so that the cost of infrastructure changes can be
+// included in OpenTofu's plan rendering.
+package costestimate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// Provider is implemented by the sources of cost estimates, such as an
+// external program or a static price sheet.
+type Provider interface {
+	// Estimate returns the estimate for the plan with the given JSON
+	// representation, in the same format as "tofu show -json" produces for
+	// a saved plan.
+	Estimate(ctx context.Context, planJSON []byte) (*plans.CostEstimate, error)
+}
+
+// Estimator is the cost provider configured by the cost_estimator block.
+type Estimator struct {
+	// Name is the label of the cost_estimator block that configured this
+	// estimator, used in messages about it.
+	Name string
+
+	Provider Provider
+}
+
+// AttachToPlan asks the provider for an estimate for the JSON representation
+// of the given plan and records it in the plan's CostEstimate field.
+//
+// A failure to estimate the cost doesn't prevent the plan from being used, so
+// it's returned as a warning, and the plan is then left without an estimate.
+func (e *Estimator) AttachToPlan(ctx context.Context, config *configs.Config, plan *plans.Plan, priorStateFile *statefile.File, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// An estimate from an earlier run mustn't be passed to the program as if
+	// it were part of the plan.
+	plan.CostEstimate = nil
+	planJSON, err := jsonplan.Marshal(config, plan, priorStateFile, schemas)
+	if err == nil {
+		plan.CostEstimate, err = e.Provider.Estimate(ctx, planJSON)
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to estimate cost",
+			fmt.Sprintf("The cost estimator %q couldn't estimate the cost of this plan: %s.", e.Name, err),
+		))
+	}
+	return diags
+}
@@ -0,0 +1,195 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package costestimate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// PriceSheet is a Provider that estimates the cost of a plan from a JSON file
+// of fixed monthly costs for each resource type, such as an organization's
+// internal price list.
+//
+// The file has a "currency" and a "resource_types" object whose properties
+// are resource type names. Each resource type has either a flat
+// "monthly_cost", or an "attribute" and a "monthly_costs" object of the cost
+// for each value of that attribute, such as an instance type. When both are
+// given, "monthly_cost" is used for the values that aren't listed. Resource
+// types that aren't in the file aren't included in the estimate.
+type PriceSheet struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+var _ Provider = (*PriceSheet)(nil)
+
+// priceSheetFile is the JSON representation of a price sheet.
+type priceSheetFile struct {
+	Currency      string                        `json:"currency"`
+	ResourceTypes map[string]*priceSheetPricing `json:"resource_types"`
+}
+
+type priceSheetPricing struct {
+	MonthlyCost  string            `json:"monthly_cost"`
+	Attribute    string            `json:"attribute"`
+	MonthlyCosts map[string]string `json:"monthly_costs"`
+}
+
+// priceSheetPlan is the subset of the JSON representation of a plan that the
+// price sheet needs.
+type priceSheetPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Change  struct {
+			Before map[string]json.RawMessage `json:"before"`
+			After  map[string]json.RawMessage `json:"after"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// Estimate reads the price sheet and prices each managed resource instance in
+// the given JSON plan before and after the change.
+func (s *PriceSheet) Estimate(_ context.Context, planJSON []byte) (*plans.CostEstimate, error) {
+	sheet, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var plan priceSheetPlan
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan: %w", err)
+	}
+
+	ret := &plans.CostEstimate{Currency: sheet.Currency}
+	total, pastTotal := new(big.Rat), new(big.Rat)
+	scale := 2
+	for _, rc := range plan.ResourceChanges {
+		pricing := sheet.ResourceTypes[rc.Type]
+		if rc.Mode != "managed" || pricing == nil {
+			continue
+		}
+		// Objects that don't exist before or after the change cost nothing
+		// then. If an object's cost depends on an attribute that isn't known
+		// yet, and there's no default cost, the object is left out.
+		cost, ok := pricing.cost(rc.Change.After)
+		if !ok {
+			continue
+		}
+		pastCost, ok := pricing.cost(rc.Change.Before)
+		if !ok {
+			continue
+		}
+		total.Add(total, cost.value)
+		pastTotal.Add(pastTotal, pastCost.value)
+		scale = max(scale, cost.scale, pastCost.scale)
+		ret.Resources = append(ret.Resources, plans.ResourceCost{
+			Address:         rc.Address,
+			MonthlyCost:     cost.text,
+			PastMonthlyCost: pastCost.text,
+		})
+	}
+	sort.Slice(ret.Resources, func(i, j int) bool {
+		return ret.Resources[i].Address < ret.Resources[j].Address
+	})
+	ret.TotalMonthlyCost = total.FloatString(scale)
+	ret.PastTotalMonthlyCost = pastTotal.FloatString(scale)
+	return ret, nil
+}
+
+// load reads and validates the price sheet.
+func (s *PriceSheet) load() (*priceSheetFile, error) {
+	src, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price sheet: %w", err)
+	}
+	var sheet priceSheetFile
+	if err := json.Unmarshal(src, &sheet); err != nil {
+		return nil, fmt.Errorf("invalid price sheet %s: %w", s.Path, err)
+	}
+	if sheet.Currency == "" {
+		return nil, fmt.Errorf("invalid price sheet %s: missing currency", s.Path)
+	}
+	for typeName, pricing := range sheet.ResourceTypes {
+		if pricing == nil || (pricing.MonthlyCost == "" && pricing.Attribute == "") {
+			return nil, fmt.Errorf("invalid price sheet %s: %s must have a monthly_cost or an attribute", s.Path, typeName)
+		}
+		if pricing.Attribute != "" && len(pricing.MonthlyCosts) == 0 {
+			return nil, fmt.Errorf("invalid price sheet %s: %s must have monthly_costs for its attribute", s.Path, typeName)
+		}
+		costs := []string{pricing.MonthlyCost}
+		for _, cost := range pricing.MonthlyCosts {
+			costs = append(costs, cost)
+		}
+		for _, cost := range costs {
+			if _, err := parsePrice(cost); cost != "" && err != nil {
+				return nil, fmt.Errorf("invalid price sheet %s: %s: %w", s.Path, typeName, err)
+			}
+		}
+	}
+	return &sheet, nil
+}
+
+// price is a cost from a price sheet, with the number of decimal places it
+// was written with.
+type price struct {
+	text  string
+	value *big.Rat
+	scale int
+}
+
+var zeroPrice = price{text: "0", value: new(big.Rat)}
+
+func parsePrice(text string) (price, error) {
+	value, ok := new(big.Rat).SetString(text)
+	if !ok || value.Sign() < 0 || strings.ContainsAny(text, "eE/") {
+		return price{}, fmt.Errorf("invalid cost %q: must be a non-negative decimal number", text)
+	}
+	scale := 0
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		scale = len(text) - i - 1
+	}
+	return price{text: text, value: value, scale: scale}, nil
+}
+
+// cost returns the monthly cost of the object with the given attributes, or
+// false if it can't be priced. A null object costs nothing.
+func (p *priceSheetPricing) cost(obj map[string]json.RawMessage) (price, bool) {
+	if obj == nil {
+		return zeroPrice, true
+	}
+	text := p.MonthlyCost
+	if p.Attribute != "" {
+		if cost, ok := p.MonthlyCosts[attributeKey(obj[p.Attribute])]; ok {
+			text = cost
+		}
+	}
+	if text == "" {
+		return price{}, false
+	}
+	// The price sheet has already been validated.
+	ret, _ := parsePrice(text)
+	return ret, true
+}
+
+// attributeKey returns the key to look up the given JSON attribute value in
+// monthly_costs: the string itself for strings, and the JSON text of other
+// values, such as numbers.
+func attributeKey(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
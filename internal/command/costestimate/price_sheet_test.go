@@ -0,0 +1,92 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package costestimate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestPriceSheet(t *testing.T) {
+	planJSON := []byte(`{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "test_instance.resized", "mode": "managed", "type": "test_instance", "change": {"before": {"size": "small"}, "after": {"size": "large"}}},
+    {"address": "test_instance.new", "mode": "managed", "type": "test_instance", "change": {"before": null, "after": {"size": "small"}}},
+    {"address": "test_instance.unknown", "mode": "managed", "type": "test_instance", "change": {"before": null, "after": {}}},
+    {"address": "test_ip.gone", "mode": "managed", "type": "test_ip", "change": {"before": {"id": "a"}, "after": null}},
+    {"address": "test_disk.sized", "mode": "managed", "type": "test_disk", "change": {"before": {"gigabytes": 100}, "after": {"gigabytes": 100}}},
+    {"address": "test_disk.other", "mode": "managed", "type": "test_disk", "change": {"before": null, "after": {"gigabytes": 5}}},
+    {"address": "data.test_ip.lookup", "mode": "data", "type": "test_ip", "change": {"before": null, "after": {}}},
+    {"address": "test_unpriced.foo", "mode": "managed", "type": "test_unpriced", "change": {"before": null, "after": {}}}
+  ]
+}`)
+
+	s := &PriceSheet{Path: filepath.Join("testdata", "prices.json")}
+	got, err := s.Estimate(t.Context(), planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &plans.CostEstimate{
+		Currency:             "EUR",
+		TotalMonthlyCost:     "81.515",
+		PastTotalMonthlyCost: "15.215",
+		Resources: []plans.ResourceCost{
+			{Address: "test_disk.other", MonthlyCost: "10", PastMonthlyCost: "0"},
+			{Address: "test_disk.sized", MonthlyCost: "4.125", PastMonthlyCost: "4.125"},
+			{Address: "test_instance.new", MonthlyCost: "7.49", PastMonthlyCost: "0"},
+			{Address: "test_instance.resized", MonthlyCost: "59.90", PastMonthlyCost: "7.49"},
+			{Address: "test_ip.gone", MonthlyCost: "0", PastMonthlyCost: "3.6"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong estimate\n%s", diff)
+	}
+}
+
+func TestPriceSheet_invalid(t *testing.T) {
+	tests := map[string]struct {
+		sheet   string
+		wantErr string
+	}{
+		"no currency": {
+			`{"resource_types": {}}`,
+			"missing currency",
+		},
+		"no cost": {
+			`{"currency": "USD", "resource_types": {"test_ip": {}}}`,
+			"test_ip must have a monthly_cost or an attribute",
+		},
+		"attribute without costs": {
+			`{"currency": "USD", "resource_types": {"test_ip": {"attribute": "size"}}}`,
+			"test_ip must have monthly_costs for its attribute",
+		},
+		"negative cost": {
+			`{"currency": "USD", "resource_types": {"test_ip": {"monthly_cost": "-1"}}}`,
+			`test_ip: invalid cost "-1"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prices.json")
+			if err := os.WriteFile(path, []byte(test.sheet), 0644); err != nil {
+				t.Fatal(err)
+			}
+			s := &PriceSheet{Path: path}
+			_, err := s.Estimate(t.Context(), []byte(`{"format_version": "1.2"}`))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("wrong error\ngot:  %v\nwant: %s", err, test.wantErr)
+			}
+		})
+	}
+}
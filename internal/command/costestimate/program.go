@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package costestimate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// Program is a Provider that runs an external program to estimate the cost of
+// a plan.
+//
+// OpenTofu runs the program with the JSON representation of the plan on its
+// standard input. The program must write its estimate to its standard output
+// in the format of jsonplan.CostEstimate, and exit with a non-zero status and
+// an error message on its standard error if it can't estimate the cost.
+type Program struct {
+	// Program is the path to the program to run. If it doesn't contain a
+	// path separator, it's looked up in the directories named by the PATH
+	// environment variable.
+	Program string
+
+	// Args are the arguments to pass to the program.
+	Args []string
+}
+
+var _ Provider = (*Program)(nil)
+
+// Estimate runs the program with the given JSON plan and returns its
+// estimate.
+func (p *Program) Estimate(ctx context.Context, planJSON []byte) (*plans.CostEstimate, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Program, p.Args...)
+	cmd.Stdin = bytes.NewReader(planJSON)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if _, isExitErr := err.(*exec.ExitError); isExitErr {
+		errText := strings.TrimSpace(stderr.String())
+		if errText == "" {
+			return nil, fmt.Errorf("%s failed, but it produced no error message", p.Program)
+		}
+		return nil, fmt.Errorf("%s failed: %s", p.Program, errText)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", p.Program, err)
+	}
+
+	estimate, err := jsonplan.UnmarshalCostEstimate(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("malformed output from %s: %w", p.Program, err)
+	}
+	return estimate, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package costestimate
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestProgram(t *testing.T) {
+	// The estimator script used in this test assumes a Unix-like environment
+	// where scripts are directly executable based on their #! line and where
+	// bash is available, like the credentials helper tests do.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := filepath.Join(wd, "testdata", "test-estimator")
+	planJSON := []byte(`{"format_version":"1.2"}`)
+
+	t.Run("happy path", func(t *testing.T) {
+		p := &Program{Program: program, Args: []string{"good"}}
+		got, err := p.Estimate(t.Context(), planJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &plans.CostEstimate{
+			Currency:             "USD",
+			TotalMonthlyCost:     "12.50",
+			PastTotalMonthlyCost: "10",
+			Resources: []plans.ResourceCost{
+				{Address: "test_instance.foo", MonthlyCost: "2.50"},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong estimate\n%s", diff)
+		}
+	})
+	t.Run("malformed output", func(t *testing.T) {
+		p := &Program{Program: program, Args: []string{"malformed"}}
+		_, err := p.Estimate(t.Context(), planJSON)
+		if err == nil || !strings.Contains(err.Error(), `invalid total_monthly_cost "a lot"`) {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+	t.Run("program failure", func(t *testing.T) {
+		p := &Program{Program: program, Args: []string{"fail"}}
+		_, err := p.Estimate(t.Context(), planJSON)
+		if err == nil || !strings.Contains(err.Error(), "failed: unsupported plan") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+	t.Run("no plan on stdin", func(t *testing.T) {
+		p := &Program{Program: program, Args: []string{"good"}}
+		_, err := p.Estimate(t.Context(), nil)
+		if err == nil || !strings.Contains(err.Error(), "failed: no plan on stdin") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+	t.Run("missing program", func(t *testing.T) {
+		p := &Program{Program: filepath.Join(wd, "testdata", "nonexistent")}
+		_, err := p.Estimate(t.Context(), planJSON)
+		if err == nil || !strings.Contains(err.Error(), "failed to run") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,565 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// DriftCommand is a Command implementation that compares the remote objects
+// with the latest state, like a refresh-only plan, and reports any drift
+// without saving a plan or updating the state.
+type DriftCommand struct {
+	Meta
+}
+
+// driftReportFormatVersion is the version of the JSON drift report produced
+// with the -json option. It follows the same rules as the versions of the
+// other JSON output formats.
+const driftReportFormatVersion = "1.1"
+
+// driftReport is the JSON representation of the result of DriftCommand, as
+// produced with the -json option.
+type driftReport struct {
+	FormatVersion string          `json:"format_version"`
+	Timestamp     string          `json:"timestamp"`
+	DriftDetected bool            `json:"drift_detected"`
+	Resources     []driftResource `json:"resources"`
+}
+
+type driftResource struct {
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address,omitempty"`
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+
+	// Action is "update" if the remote object has changed, "delete" if it
+	// no longer exists, or "move" if only its address changed.
+	Action string `json:"action"`
+
+	// Attributes are the names of the top-level attributes and blocks that
+	// have changed.
+	Attributes []string `json:"attributes,omitempty"`
+
+	// Changes has a record for each individual value that changed within
+	// the attributes above.
+	Changes []driftChange `json:"changes,omitempty"`
+
+	Severity driftSeverity `json:"severity"`
+}
+
+// driftChange describes a single value that changed within a drifted
+// resource instance.
+type driftChange struct {
+	// Path is the path to the value within the resource instance object, as
+	// a sequence of attribute names, map keys and list indices.
+	Path []interface{} `json:"path"`
+
+	// Before and After are the JSON representations of the value in the
+	// state and in the remote object, which are null if the value was added
+	// or removed. Both are omitted if the value is sensitive.
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Sensitive bool            `json:"sensitive,omitempty"`
+}
+
+// driftSeverity describes how much a drifted resource instance is likely to
+// matter, so that scheduled checks can decide whether to alert on it.
+type driftSeverity string
+
+const (
+	// driftSeverityHigh is for remote objects that were deleted outside of
+	// OpenTofu.
+	driftSeverityHigh driftSeverity = "high"
+
+	// driftSeverityMedium is for remote objects that were changed outside of
+	// OpenTofu.
+	driftSeverityMedium driftSeverity = "medium"
+
+	// driftSeverityLow is for objects that only moved to a new address in
+	// the configuration.
+	driftSeverityLow driftSeverity = "low"
+)
+
+func (c *DriftCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+
+	var jsonOutput bool
+	var outPath string
+	cmdFlags := c.Meta.defaultFlagSet("drift")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&outPath, "out", "", "path")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.IntVar(&c.Meta.parallelism, "parallelism", arguments.DefaultParallelism, "parallelism")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	configPath, err := modulePath(cmdFlags.Args())
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.showDiagnostics(diags) // in case of any warnings in here
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(ctx, b, arguments.ViewHuman, enc)
+	opReq.Type = backend.OperationTypePlan
+	opReq.PlanMode = plans.RefreshOnlyMode
+	opReq.PlanRefresh = true
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(ctx, opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	lr, _, ctxDiags := local.LocalRun(ctx, opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+		}
+	}()
+
+	plan, planDiags := lr.Core.Plan(ctx, lr.Config, lr.InputState, lr.PlanOpts)
+	diags = diags.Append(planDiags)
+	if planDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	schemas, schemaDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	c.showDiagnostics(diags)
+
+	report, err := newDriftReport(plan, schemas)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to build drift report: %s", err))
+		return 1
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to marshal drift report to json: %s", err))
+		return 1
+	}
+	if jsonOutput {
+		c.Ui.Output(string(out))
+	} else {
+		c.Ui.Output(c.renderDriftReport(report))
+	}
+
+	if outPath != "" {
+		if err := os.WriteFile(outPath, append(out, '\n'), 0644); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write drift report to %s: %s", outPath, err))
+			return 1
+		}
+	}
+
+	if report.DriftDetected {
+		// A failure to deliver the report doesn't change the result of the
+		// comparison, so it's reported only as a warning.
+		for _, err := range c.notifyDriftWebhooks(ctx, out) {
+			c.Ui.Warn(fmt.Sprintf("Failed to send drift report: %s", err))
+		}
+		return 2
+	}
+	return 0
+}
+
+// newDriftReport summarizes the resource instances that the given
+// refresh-only plan found to have drifted.
+func newDriftReport(plan *plans.Plan, schemas *tofu.Schemas) (*driftReport, error) {
+	report := &driftReport{
+		FormatVersion: driftReportFormatVersion,
+		Timestamp:     plan.Timestamp.Format(time.RFC3339),
+		Resources:     []driftResource{},
+	}
+
+	for _, dr := range plan.DriftedResources {
+		res := driftResource{
+			Address: dr.Addr.String(),
+			Type:    dr.Addr.Resource.Resource.Type,
+			Name:    dr.Addr.Resource.Resource.Name,
+		}
+		if !dr.PrevRunAddr.Equal(dr.Addr) {
+			res.PreviousAddress = dr.PrevRunAddr.String()
+		}
+
+		switch dr.Action {
+		case plans.Delete:
+			res.Action = "delete"
+			res.Severity = driftSeverityHigh
+		case plans.Update:
+			schema, _ := schemas.ResourceTypeConfig(dr.ProviderAddr.Provider, dr.Addr.Resource.Resource.Mode, dr.Addr.Resource.Resource.Type)
+			if schema == nil {
+				return nil, fmt.Errorf("no schema found for %s", dr.Addr)
+			}
+			change, err := dr.Decode(schema.ImpliedType())
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode drift for %s: %w", dr.Addr, err)
+			}
+			res.Action = "update"
+			res.Attributes = driftedAttributes(schema, change)
+			res.Changes, err = driftedValues(schema, change)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare drift for %s: %w", dr.Addr, err)
+			}
+			res.Severity = driftSeverityMedium
+		case plans.NoOp:
+			if res.PreviousAddress == "" {
+				continue
+			}
+			res.Action = "move"
+			res.Severity = driftSeverityLow
+		default:
+			// Refresh-only plans don't detect any other kinds of drift.
+			continue
+		}
+		report.Resources = append(report.Resources, res)
+	}
+
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Address < report.Resources[j].Address
+	})
+	report.DriftDetected = len(report.Resources) != 0
+	return report, nil
+}
+
+// driftedAttributes returns the names of the top-level attributes and nested
+// blocks whose values differ between the before and after values of the
+// given change.
+func driftedAttributes(schema *configschema.Block, change *plans.ResourceInstanceChange) []string {
+	before, _ := change.Before.UnmarkDeep()
+	after, _ := change.After.UnmarkDeep()
+	if before.IsNull() || after.IsNull() || !before.IsKnown() || !after.IsKnown() {
+		return nil
+	}
+
+	var names []string
+	for name := range schema.Attributes {
+		names = append(names, name)
+	}
+	for name := range schema.BlockTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ret []string
+	for _, name := range names {
+		if !before.GetAttr(name).RawEquals(after.GetAttr(name)) {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// driftedValues returns a record for each of the individual values that
+// differ between the before and after values of the given change.
+//
+// Values that are marked as sensitive, either in the state or by the schema,
+// are reported without their before and after values.
+func driftedValues(schema *configschema.Block, change *plans.ResourceInstanceChange) ([]driftChange, error) {
+	before := change.Before.MarkWithPaths(schema.ValueMarks(change.Before, nil))
+	after := change.After.MarkWithPaths(schema.ValueMarks(change.After, nil))
+	if before.IsNull() || after.IsNull() {
+		return nil, nil
+	}
+
+	var ret []driftChange
+	err := compareDriftedValues(nil, before, after, &ret)
+	return ret, err
+}
+
+func compareDriftedValues(path cty.Path, before, after cty.Value, changes *[]driftChange) error {
+	unmarkedBefore, _ := before.UnmarkDeep()
+	unmarkedAfter, _ := after.UnmarkDeep()
+	if unmarkedBefore.RawEquals(unmarkedAfter) {
+		return nil
+	}
+
+	// Values that are sensitive as a whole are reported as a single change,
+	// so that nothing about their contents is revealed.
+	if before.IsMarked() || after.IsMarked() {
+		return addDriftChange(path, before, after, changes)
+	}
+
+	// Collections and structural values of the same shape are compared
+	// element by element, so that each change is as specific as possible.
+	// Anything else, including sets, which have no stable way to address
+	// their elements, is reported as a whole.
+	ty := before.Type()
+	if before.IsNull() || after.IsNull() || !before.IsKnown() || !after.IsKnown() || !ty.Equals(after.Type()) {
+		return addDriftChange(path, before, after, changes)
+	}
+	switch {
+	case ty.IsObjectType():
+		names := make([]string, 0, len(ty.AttributeTypes()))
+		for name := range ty.AttributeTypes() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := compareDriftedValues(path.GetAttr(name), before.GetAttr(name), after.GetAttr(name), changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ty.IsMapType():
+		keys := make(map[string]bool)
+		for k := range before.AsValueMap() {
+			keys[k] = true
+		}
+		for k := range after.AsValueMap() {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		null := cty.NullVal(ty.ElementType())
+		for _, k := range sorted {
+			key := cty.StringVal(k)
+			b, a := null, null
+			if before.HasIndex(key).True() {
+				b = before.Index(key)
+			}
+			if after.HasIndex(key).True() {
+				a = after.Index(key)
+			}
+			if err := compareDriftedValues(path.Index(key), b, a, changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	case (ty.IsListType() || ty.IsTupleType()) && before.LengthInt() == after.LengthInt():
+		for i := 0; i < before.LengthInt(); i++ {
+			idx := cty.NumberIntVal(int64(i))
+			if err := compareDriftedValues(path.Index(idx), before.Index(idx), after.Index(idx), changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return addDriftChange(path, before, after, changes)
+	}
+}
+
+func addDriftChange(path cty.Path, before, after cty.Value, changes *[]driftChange) error {
+	change := driftChange{
+		Path: make([]interface{}, 0, len(path)),
+	}
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			change.Path = append(change.Path, step.Name)
+		case cty.IndexStep:
+			if step.Key.Type() == cty.Number {
+				i, _ := step.Key.AsBigFloat().Int64()
+				change.Path = append(change.Path, i)
+			} else {
+				change.Path = append(change.Path, step.Key.AsString())
+			}
+		}
+	}
+
+	if before.ContainsMarked() || after.ContainsMarked() {
+		change.Sensitive = true
+	} else {
+		var err error
+		if change.Before, err = ctyjson.Marshal(before, before.Type()); err != nil {
+			return err
+		}
+		if change.After, err = ctyjson.Marshal(after, after.Type()); err != nil {
+			return err
+		}
+	}
+	*changes = append(*changes, change)
+	return nil
+}
+
+func (c *DriftCommand) renderDriftReport(report *driftReport) string {
+	if !report.DriftDetected {
+		return c.Colorize().Color("[bold][green]No drift detected.[reset] The remote objects match the latest state.")
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "[bold][yellow]Drift detected[reset] in %d resource instances:\n\n", len(report.Resources))
+	for _, res := range report.Resources {
+		switch res.Action {
+		case "delete":
+			fmt.Fprintf(&buf, "  [red]-[reset] [bold]%s[reset]: deleted outside of OpenTofu (%s)\n", res.Address, res.Severity)
+		case "update":
+			changed := "changed"
+			if len(res.Attributes) != 0 {
+				changed = strings.Join(res.Attributes, ", ") + " changed"
+			}
+			fmt.Fprintf(&buf, "  [yellow]~[reset] [bold]%s[reset]: %s outside of OpenTofu (%s)\n", res.Address, changed, res.Severity)
+		case "move":
+			fmt.Fprintf(&buf, "  [cyan]>[reset] [bold]%s[reset]: moved from %s (%s)\n", res.Address, res.PreviousAddress, res.Severity)
+		}
+	}
+	buf.WriteString("\nRun \"tofu apply -refresh-only\" to update the state to match, or \"tofu apply\" to restore the remote objects to match the configuration.")
+	return c.Colorize().Color(buf.String())
+}
+
+func (c *DriftCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("")
+}
+
+func (c *DriftCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json":         complete.PredictNothing,
+		"-lock":         completePredictBoolean,
+		"-lock-timeout": complete.PredictAnything,
+		"-out":          complete.PredictAnything,
+		"-parallelism":  complete.PredictAnything,
+		"-var":          complete.PredictAnything,
+		"-var-file":     complete.PredictFiles("*.tfvars"),
+	}
+}
+
+func (c *DriftCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] drift [options]
+
+  Compare the remote objects with the latest state and report any that have
+  changed outside of OpenTofu, without creating a plan file or updating the
+  state. This is intended for drift detection that runs on a schedule.
+
+  Each drifted resource instance is reported with the attributes that
+  changed and a severity: "high" for objects that were deleted, "medium" for
+  objects that were changed, and "low" for objects that only moved to a new
+  address.
+
+  The exit code is 0 if there is no drift, 2 if drift was detected, and 1 if
+  the comparison failed. When drift is detected, the report is also sent to
+  any drift_webhook endpoints in the CLI configuration.
+
+Options:
+
+  -json               Produce the drift report in a machine-readable JSON
+                      format.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+  -no-color           If specified, output won't contain any color.
+
+  -out=path           Write the drift report in the JSON format to the given
+                      path, in addition to the normal output.
+
+  -parallelism=n      Limit the number of concurrent operations. Defaults
+                      to 10.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DriftCommand) Synopsis() string {
+	return "Detect changes made outside of OpenTofu"
+}
@@ -0,0 +1,233 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestDrift(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refresh"), td)
+	t.Chdir(td)
+
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	testStateFileDefault(t, states.BuildState(func(s *states.SyncState) {
+		for name, attrs := range map[string]string{
+			"foo":  `{"id":"foo","ami":"bar"}`,
+			"gone": `{"id":"gone","ami":"bar"}`,
+		} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: name,
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(attrs),
+					Status:    states.ObjectReady,
+				},
+				provider,
+				addrs.NoKey,
+			)
+		}
+	}))
+
+	newCommand := func(t *testing.T, drifted bool) (*DriftCommand, *cli.MockUi) {
+		p := testProvider()
+		p.GetProviderSchemaResponse = refreshFixtureSchema()
+		p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+			if !drifted {
+				return providers.ReadResourceResponse{NewState: req.PriorState}
+			}
+			if req.PriorState.GetAttr("id").AsString() == "gone" {
+				return providers.ReadResourceResponse{NewState: cty.NullVal(req.PriorState.Type())}
+			}
+			return providers.ReadResourceResponse{
+				NewState: cty.ObjectVal(map[string]cty.Value{
+					"id":  req.PriorState.GetAttr("id"),
+					"ami": cty.StringVal("changed"),
+				}),
+			}
+		}
+		ui := cli.NewMockUi()
+		view, _ := testView(t)
+		return &DriftCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		}, ui
+	}
+
+	t.Run("no drift", func(t *testing.T) {
+		c, ui := newCommand(t, false)
+		if code := c.Run([]string{"-no-color"}); code != 0 {
+			t.Fatalf("wrong exit status %d; want 0\n%s", code, ui.ErrorWriter.String())
+		}
+		if got, want := ui.OutputWriter.String(), "No drift detected."; !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		c, ui := newCommand(t, true)
+		if code := c.Run([]string{"-no-color"}); code != 2 {
+			t.Fatalf("wrong exit status %d; want 2\n%s", code, ui.ErrorWriter.String())
+		}
+		got := ui.OutputWriter.String()
+		for _, want := range []string{
+			"Drift detected in 2 resource instances",
+			"~ test_instance.foo: ami changed outside of OpenTofu (medium)",
+			"- test_instance.gone: deleted outside of OpenTofu (high)",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output is missing %q\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		c, ui := newCommand(t, true)
+		if code := c.Run([]string{"-json"}); code != 2 {
+			t.Fatalf("wrong exit status %d; want 2\n%s", code, ui.ErrorWriter.String())
+		}
+		var got driftReport
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+		}
+		got.Timestamp = ""
+		want := driftReport{
+			FormatVersion: driftReportFormatVersion,
+			DriftDetected: true,
+			Resources: []driftResource{
+				{
+					Address:    "test_instance.foo",
+					Type:       "test_instance",
+					Name:       "foo",
+					Action:     "update",
+					Attributes: []string{"ami"},
+					Changes: []driftChange{
+						{
+							Path:   []interface{}{"ami"},
+							Before: json.RawMessage(`"bar"`),
+							After:  json.RawMessage(`"changed"`),
+						},
+					},
+					Severity: driftSeverityMedium,
+				},
+				{
+					Address:  "test_instance.gone",
+					Type:     "test_instance",
+					Name:     "gone",
+					Action:   "delete",
+					Severity: driftSeverityHigh,
+				},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong report\n%s", diff)
+		}
+	})
+
+	t.Run("out and webhooks", func(t *testing.T) {
+		var received []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.Header.Get("Authorization"), "Bearer abc123"; got != want {
+				t.Errorf("wrong Authorization header %q; want %q", got, want)
+			}
+			received, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		c, ui := newCommand(t, true)
+		c.DriftWebhooks = []DriftWebhook{
+			{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer abc123"}},
+		}
+		outPath := filepath.Join(t.TempDir(), "drift.json")
+		if code := c.Run([]string{"-no-color", "-out", outPath}); code != 2 {
+			t.Fatalf("wrong exit status %d; want 2\n%s", code, ui.ErrorWriter.String())
+		}
+
+		written, err := os.ReadFile(outPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report driftReport
+		if err := json.Unmarshal(written, &report); err != nil {
+			t.Fatalf("invalid report file: %s\n%s", err, written)
+		}
+		if !report.DriftDetected || len(report.Resources) != 2 {
+			t.Errorf("wrong report file\n%s", written)
+		}
+		if got, want := string(received), strings.TrimSpace(string(written)); got != want {
+			t.Errorf("wrong webhook payload\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
+func TestDriftedValues(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":       {Type: cty.String, Computed: true},
+			"password": {Type: cty.String, Optional: true, Sensitive: true},
+			"tags":     {Type: cty.Map(cty.String), Optional: true},
+			"ports":    {Type: cty.List(cty.Number), Optional: true},
+		},
+	}
+	change := &plans.ResourceInstanceChange{
+		Change: plans.Change{
+			Before: cty.ObjectVal(map[string]cty.Value{
+				"id":       cty.StringVal("a"),
+				"password": cty.StringVal("old"),
+				"tags":     cty.MapVal(map[string]cty.Value{"env": cty.StringVal("prod"), "team": cty.StringVal("web")}),
+				"ports":    cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+			}),
+			After: cty.ObjectVal(map[string]cty.Value{
+				"id":       cty.StringVal("a"),
+				"password": cty.StringVal("new"),
+				"tags":     cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev"), "owner": cty.StringVal("ops")}),
+				"ports":    cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(8443)}),
+			}),
+		},
+	}
+
+	got, err := driftedValues(schema, change)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []driftChange{
+		{Path: []interface{}{"password"}, Sensitive: true},
+		{Path: []interface{}{"ports", int64(1)}, Before: json.RawMessage(`443`), After: json.RawMessage(`8443`)},
+		{Path: []interface{}{"tags", "env"}, Before: json.RawMessage(`"prod"`), After: json.RawMessage(`"dev"`)},
+		{Path: []interface{}{"tags", "owner"}, Before: json.RawMessage(`null`), After: json.RawMessage(`"ops"`)},
+		{Path: []interface{}{"tags", "team"}, Before: json.RawMessage(`"web"`), After: json.RawMessage(`null`)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+}
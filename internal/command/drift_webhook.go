@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+)
+
+// DriftWebhook describes a single HTTP endpoint to send drift reports to.
+type DriftWebhook struct {
+	URL string
+
+	// Headers are extra HTTP request headers to send with each request,
+	// such as for authentication.
+	Headers map[string]string
+}
+
+// driftWebhookTimeout is the maximum time we'll wait for each webhook
+// request.
+const driftWebhookTimeout = 10 * time.Second
+
+// notifyDriftWebhooks sends the given JSON drift report as an HTTP POST
+// request to each of the drift webhooks, and returns an error for each one
+// that it couldn't be delivered to.
+func (m *Meta) notifyDriftWebhooks(ctx context.Context, report []byte) []error {
+	if len(m.DriftWebhooks) == 0 {
+		return nil
+	}
+	client := httpclient.New(ctx)
+	client.Timeout = driftWebhookTimeout
+
+	var errs []error
+	for _, hook := range m.DriftWebhooks {
+		if err := sendDriftReport(ctx, client, hook, report); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hook.URL, err))
+		}
+	}
+	return errs
+}
+
+func sendDriftReport(ctx context.Context, client *http.Client, hook DriftWebhook, report []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(report))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
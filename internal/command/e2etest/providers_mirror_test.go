@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -83,6 +84,16 @@ func testOpenTofuProvidersMirror(t *testing.T, fixture string) {
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("unexpected files in result\n%s", diff)
 	}
+
+	// Mirroring again into the same directory must not download any of the
+	// packages again, because they're all already present.
+	stdout, stderr, err = tf.Run("providers", "mirror", "-platform=linux_amd64", "-platform=windows_386", outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Mirror summary: 0 downloaded, 4 already present") {
+		t.Errorf("packages were downloaded again\nstdout:\n%s", stdout)
+	}
 }
 
 // this test is based on testOpenTofuProvidersMirror above.
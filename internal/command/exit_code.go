@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// These are the exit codes for the failures that have a stable error code,
+// which replace the general exit code 1 so that automation can tell them
+// apart. They start at 10 to stay clear of the other exit codes that some
+// commands use, such as 2 for "tofu plan -detailed-exitcode" and 3 for
+// "tofu fmt -check".
+//
+// Like the error codes themselves, these must never be changed or reused.
+const (
+	ExitCodeStateLocked     = 10
+	ExitCodeAuthFailed      = 11
+	ExitCodeStateCorrupt    = 12
+	ExitCodeProviderCrashed = 13
+	ExitCodePlanStale       = 14
+	ExitCodeApplyTimeout    = 15
+)
+
+// ExitCodeForError returns the exit code for a command that failed with the
+// given error code, or 1 if the error code has no exit code of its own.
+func ExitCodeForError(code tfdiags.ErrorCode) int {
+	switch code {
+	case tfdiags.ErrorCodeStateLocked:
+		return ExitCodeStateLocked
+	case tfdiags.ErrorCodeAuthFailed:
+		return ExitCodeAuthFailed
+	case tfdiags.ErrorCodeStateCorrupt:
+		return ExitCodeStateCorrupt
+	case tfdiags.ErrorCodeProviderCrashed:
+		return ExitCodeProviderCrashed
+	case tfdiags.ErrorCodePlanStale:
+		return ExitCodePlanStale
+	case tfdiags.ErrorCodeApplyTimeout:
+		return ExitCodeApplyTimeout
+	default:
+		return 1
+	}
+}
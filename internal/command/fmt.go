@@ -48,6 +48,7 @@ type FmtCommand struct {
 	diff      bool
 	check     bool
 	recursive bool
+	rules     []fmtRule
 	input     io.Reader // STDIN if nil
 }
 
@@ -63,12 +64,20 @@ func (c *FmtCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.diff, "diff", false, "diff")
 	cmdFlags.BoolVar(&c.check, "check", false, "check")
 	cmdFlags.BoolVar(&c.recursive, "recursive", false, "recursive")
+	var rules string
+	cmdFlags.StringVar(&rules, "rules", "", "rules")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	var err error
+	if c.rules, err = parseFmtRules(rules); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -rules option: %s", err))
+		return 1
+	}
+
 	args = cmdFlags.Args()
 
 	var paths []string
@@ -296,6 +305,8 @@ func (c *FmtCommand) processDir(path string, stdout io.Writer) tfdiags.Diagnosti
 // formatSourceCode is the formatting logic itself, applied to each file that
 // is selected (directly or indirectly) on the command line.
 func (c *FmtCommand) formatSourceCode(src []byte, filename string) []byte {
+	src = applyFmtRules(src, filename, c.rules)
+
 	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
 	if diags.HasErrors() {
 		// It would be weird to get here because the caller should already have
@@ -588,6 +599,20 @@ Options:
 
   -recursive     Also process files in subdirectories. By default, only the
                  given directory (or current directory) is processed.
+
+  -rules=list    Also apply the given comma-separated rewrite rules, or
+                 "all" of them:
+
+                   interpolation       Unwrap interpolation-only strings
+                                       like "${var.name}" anywhere in an
+                                       expression.
+                   meta-arguments      Move meta-arguments like count and
+                                       for_each to the top of blocks.
+                   required-providers  Sort the entries in required_providers
+                                       blocks.
+                   align-maps          Use "=" instead of ":" in map and
+                                       object literals, so that their values
+                                       are aligned.
 `
 	return strings.TrimSpace(helpText)
 }
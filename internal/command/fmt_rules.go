@@ -0,0 +1,457 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// fmtRule is an opt-in rewrite rule for "tofu fmt", for changes beyond the
+// canonical formatting that fmt always applies.
+type fmtRule struct {
+	Name string
+
+	// Edits returns the changes to make to the given source code, which has
+	// already been parsed into the given body. The edits must not overlap.
+	// The result of all the rules is formatted afterwards, so the edits
+	// don't need to produce canonical whitespace.
+	Edits func(src []byte, body *hclsyntax.Body) []fmtEdit
+}
+
+// fmtRules are all of the available rules, in the order they are applied.
+var fmtRules = []fmtRule{
+	{Name: "interpolation", Edits: fmtInterpolationEdits},
+	{Name: "meta-arguments", Edits: fmtMetaArgumentsEdits},
+	{Name: "required-providers", Edits: fmtRequiredProvidersEdits},
+	{Name: "align-maps", Edits: fmtAlignMapsEdits},
+}
+
+// parseFmtRules returns the rules selected by the given comma-separated list
+// of rule names, or all of them for "all".
+func parseFmtRules(s string) ([]fmtRule, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s == "all" {
+		return fmtRules, nil
+	}
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, rule := range fmtRules {
+			if rule.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown rule %q; the available rules are %s, or \"all\"", name, fmtRuleNames())
+		}
+		selected[name] = true
+	}
+	var ret []fmtRule
+	for _, rule := range fmtRules {
+		if selected[rule.Name] {
+			ret = append(ret, rule)
+		}
+	}
+	return ret, nil
+}
+
+func fmtRuleNames() string {
+	names := make([]string, len(fmtRules))
+	for i, rule := range fmtRules {
+		names[i] = fmt.Sprintf("%q", rule.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// applyFmtRules returns the given source code with the given rules applied.
+//
+// Each rule is applied repeatedly until it makes no further changes, so that
+// rules can leave nested constructs to a later pass rather than producing
+// overlapping edits. If a rule would produce invalid syntax, which would be
+// a bug in the rule, its changes are discarded.
+func applyFmtRules(src []byte, filename string, rules []fmtRule) []byte {
+	for _, rule := range rules {
+		// The limit is just a safeguard against a rule that never settles.
+		for range 10 {
+			file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+			if diags.HasErrors() {
+				return src
+			}
+			edits := rule.Edits(src, file.Body.(*hclsyntax.Body))
+			if len(edits) == 0 {
+				break
+			}
+			result := applyFmtEdits(src, edits)
+			if _, diags := hclsyntax.ParseConfig(result, filename, hcl.InitialPos); diags.HasErrors() {
+				break
+			}
+			src = result
+		}
+	}
+	return src
+}
+
+// fmtEdit replaces the bytes from Start up to End with Text.
+type fmtEdit struct {
+	Start, End int
+	Text       []byte
+}
+
+func applyFmtEdits(src []byte, edits []fmtEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Start < edits[j].Start
+	})
+	var buf bytes.Buffer
+	pos := 0
+	for _, edit := range edits {
+		buf.Write(src[pos:edit.Start])
+		buf.Write(edit.Text)
+		pos = edit.End
+	}
+	buf.Write(src[pos:])
+	return buf.Bytes()
+}
+
+// fmtInterpolationEdits unwraps interpolation-only templates like "${foo}"
+// anywhere in an expression, not only those that are the entire value of an
+// argument, which fmt always unwraps.
+func fmtInterpolationEdits(src []byte, body *hclsyntax.Body) []fmtEdit {
+	// An unwrapped expression used as an object key would be taken as a
+	// literal name rather than evaluated, so we leave keys alone.
+	var keys []hcl.Range
+	var wraps []*hclsyntax.TemplateWrapExpr
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch node := node.(type) {
+		case *hclsyntax.ObjectConsKeyExpr:
+			keys = append(keys, node.Range())
+		case *hclsyntax.TemplateWrapExpr:
+			wraps = append(wraps, node)
+		}
+		return nil
+	})
+
+	var edits []fmtEdit
+	end := -1
+	for _, wrap := range wraps {
+		rng := wrap.Range()
+		if rng.Start.Byte < end || fmtRangeWithin(rng, keys) {
+			// Nested wraps are handled by a later pass, once their
+			// container has been unwrapped.
+			continue
+		}
+		inner := wrap.Wrapped.Range()
+		text := bytes.TrimSpace(src[inner.Start.Byte:inner.End.Byte])
+		if bytes.ContainsRune(text, '\n') {
+			text = append(append([]byte("("), text...), ')')
+		}
+		edits = append(edits, fmtEdit{Start: rng.Start.Byte, End: rng.End.Byte, Text: text})
+		end = rng.End.Byte
+	}
+	return edits
+}
+
+func fmtRangeWithin(rng hcl.Range, within []hcl.Range) bool {
+	for _, other := range within {
+		if rng.Start.Byte >= other.Start.Byte && rng.End.Byte <= other.End.Byte {
+			return true
+		}
+	}
+	return false
+}
+
+// fmtMetaArguments are the arguments that the meta-arguments rule moves to
+// the top of each kind of block, in order.
+var fmtMetaArguments = map[string][]string{
+	"resource":  {"count", "for_each", "provider"},
+	"data":      {"count", "for_each", "provider"},
+	"ephemeral": {"count", "for_each", "provider"},
+	"module":    {"source", "version", "count", "for_each", "providers"},
+}
+
+// fmtMetaArgumentsEdits moves meta-arguments like count and for_each to the
+// top of resource, data, and module blocks, separated from the other
+// arguments by a blank line.
+func fmtMetaArgumentsEdits(src []byte, body *hclsyntax.Body) []fmtEdit {
+	var edits []fmtEdit
+	for _, block := range body.Blocks {
+		order, ok := fmtMetaArguments[block.Type]
+		if !ok {
+			continue
+		}
+		items := fmtBodyItems(block.Body)
+		var metas []fmtBodyItem
+		for _, name := range order {
+			if attr, ok := block.Body.Attributes[name]; ok {
+				metas = append(metas, fmtBodyItem{Name: name, IsAttr: true, Range: attr.SrcRange})
+			}
+		}
+		if len(metas) == 0 || fmtMetaArgumentsInPlace(src, items, metas) {
+			continue
+		}
+
+		// We can only move whole lines, so we leave blocks alone when any
+		// of the meta-arguments share a line with something else.
+		insertAt, ok := fmtBodyContentStart(src, block)
+		if !ok {
+			continue
+		}
+		spans := make([]fmtLineSpan, len(metas))
+		for i, meta := range metas {
+			if spans[i], ok = fmtItemLines(src, meta.Range); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		var text bytes.Buffer
+		for _, span := range spans {
+			text.Write(src[span.Start:span.End])
+			start, end := span.Start, span.End
+			switch {
+			case start == insertAt:
+				// Don't leave a blank line at the top of the body.
+				end = fmtSkipBlankLine(src, end)
+			case fmtBlankLineBefore(src, start):
+				// The blank line separated the moved lines from the items
+				// above, so it goes with them.
+				start = bytes.LastIndexByte(src[:start-1], '\n') + 1
+			}
+			edits = append(edits, fmtEdit{Start: start, End: end})
+		}
+		if len(metas) < len(items) {
+			text.WriteString("\n")
+		}
+		edits = append(edits, fmtEdit{Start: insertAt, End: fmtSkipBlankLines(src, insertAt), Text: text.Bytes()})
+	}
+	return fmtMergeEdits(edits)
+}
+
+// fmtMetaArgumentsInPlace returns true if the given meta-arguments are
+// already the first items of a body, in order, followed by a blank line.
+func fmtMetaArgumentsInPlace(src []byte, items, metas []fmtBodyItem) bool {
+	for i, meta := range metas {
+		if items[i].Range != meta.Range {
+			return false
+		}
+	}
+	if len(items) == len(metas) {
+		return true
+	}
+	last, next := items[len(metas)-1], items[len(metas)]
+	return fmtHasBlankLine(src[last.Range.End.Byte:next.Range.Start.Byte])
+}
+
+// fmtRequiredProvidersEdits sorts the entries of required_providers blocks by
+// their local names.
+func fmtRequiredProvidersEdits(src []byte, body *hclsyntax.Body) []fmtEdit {
+	var edits []fmtEdit
+	for _, block := range body.Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "required_providers" {
+				continue
+			}
+			items := fmtBodyItems(inner.Body)
+			if len(items) < 2 || sort.SliceIsSorted(items, func(i, j int) bool { return items[i].Name < items[j].Name }) {
+				continue
+			}
+			spans := make([]fmtLineSpan, len(items))
+			ok := true
+			for i, item := range items {
+				if spans[i], ok = fmtItemLines(src, item.Range); !ok {
+					break
+				}
+			}
+			if !ok {
+				continue
+			}
+			sorted := make([]int, len(items))
+			for i := range sorted {
+				sorted[i] = i
+			}
+			sort.SliceStable(sorted, func(i, j int) bool {
+				return items[sorted[i]].Name < items[sorted[j]].Name
+			})
+			var text bytes.Buffer
+			for _, i := range sorted {
+				text.Write(src[spans[i].Start:spans[i].End])
+			}
+			edits = append(edits, fmtEdit{Start: spans[0].Start, End: spans[len(spans)-1].End, Text: text.Bytes()})
+		}
+	}
+	return edits
+}
+
+// fmtAlignMapsEdits replaces colons between the keys and values of object
+// and map literals with equals signs, which the standard formatting then
+// aligns like any other sequence of arguments.
+func fmtAlignMapsEdits(src []byte, body *hclsyntax.Body) []fmtEdit {
+	var edits []fmtEdit
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		obj, ok := node.(*hclsyntax.ObjectConsExpr)
+		if !ok {
+			return nil
+		}
+		for _, item := range obj.Items {
+			from := item.KeyExpr.Range().End.Byte
+			to := item.ValueExpr.Range().Start.Byte
+			if from >= to {
+				continue
+			}
+			between := src[from:to]
+			if i := bytes.IndexByte(between, ':'); i >= 0 && len(bytes.TrimSpace(between)) == 1 {
+				edits = append(edits, fmtEdit{Start: from + i, End: from + i + 1, Text: []byte("=")})
+			}
+		}
+		return nil
+	})
+	return edits
+}
+
+// fmtBodyItem is an argument or nested block within a body.
+type fmtBodyItem struct {
+	Name   string
+	IsAttr bool
+	Range  hcl.Range
+}
+
+// fmtBodyItems returns the arguments and nested blocks of the given body, in
+// the order they appear in the source code.
+func fmtBodyItems(body *hclsyntax.Body) []fmtBodyItem {
+	var items []fmtBodyItem
+	for name, attr := range body.Attributes {
+		items = append(items, fmtBodyItem{Name: name, IsAttr: true, Range: attr.SrcRange})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, fmtBodyItem{Name: block.Type, Range: block.Range()})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Range.Start.Byte < items[j].Range.Start.Byte
+	})
+	return items
+}
+
+// fmtLineSpan is a range of whole lines of source code.
+type fmtLineSpan struct {
+	Start, End int
+}
+
+// fmtItemLines returns the lines of the body item with the given range,
+// including any comments on the lines directly above it, or false if the
+// item shares a line with anything other than a comment.
+func fmtItemLines(src []byte, rng hcl.Range) (fmtLineSpan, bool) {
+	start := bytes.LastIndexByte(src[:rng.Start.Byte], '\n') + 1
+	if len(bytes.TrimSpace(src[start:rng.Start.Byte])) != 0 {
+		return fmtLineSpan{}, false
+	}
+	end := len(src)
+	if i := bytes.IndexByte(src[rng.End.Byte:], '\n'); i >= 0 {
+		end = rng.End.Byte + i + 1
+	}
+	rest := bytes.TrimSpace(src[rng.End.Byte:end])
+	if len(rest) != 0 && !bytes.HasPrefix(rest, []byte("#")) && !bytes.HasPrefix(rest, []byte("//")) {
+		return fmtLineSpan{}, false
+	}
+	for start > 0 {
+		prev := bytes.LastIndexByte(src[:start-1], '\n') + 1
+		line := bytes.TrimSpace(src[prev : start-1])
+		if !bytes.HasPrefix(line, []byte("#")) && !bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		start = prev
+	}
+	return fmtLineSpan{Start: start, End: end}, true
+}
+
+// fmtBodyContentStart returns the start of the line after the opening brace
+// of the given block, or false if the brace isn't the last thing on its line.
+func fmtBodyContentStart(src []byte, block *hclsyntax.Block) (int, bool) {
+	pos := block.OpenBraceRange.End.Byte
+	i := bytes.IndexByte(src[pos:], '\n')
+	if i < 0 || len(bytes.TrimSpace(src[pos:pos+i])) != 0 {
+		return 0, false
+	}
+	return pos + i + 1, true
+}
+
+// fmtHasBlankLine returns true if there's a blank line in the given source
+// code between two body items, not counting the remainder of the first
+// item's line or the indentation of the second.
+func fmtHasBlankLine(b []byte) bool {
+	lines := bytes.Split(b, []byte("\n"))
+	for _, line := range lines[1 : len(lines)-1] {
+		if len(bytes.TrimSpace(line)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fmtBlankLineBefore returns true if the line before the one starting at pos
+// is blank.
+func fmtBlankLineBefore(src []byte, pos int) bool {
+	if pos == 0 {
+		return false
+	}
+	prev := bytes.LastIndexByte(src[:pos-1], '\n') + 1
+	return len(bytes.TrimSpace(src[prev:pos])) == 0
+}
+
+// fmtSkipBlankLine returns the position after the blank line at pos, if
+// there is one, so that removing lines doesn't leave two blank lines behind.
+func fmtSkipBlankLine(src []byte, pos int) int {
+	i := bytes.IndexByte(src[pos:], '\n')
+	if i >= 0 && len(bytes.TrimSpace(src[pos:pos+i])) == 0 {
+		return pos + i + 1
+	}
+	return pos
+}
+
+// fmtSkipBlankLines returns the position after any blank lines at pos.
+func fmtSkipBlankLines(src []byte, pos int) int {
+	for {
+		next := fmtSkipBlankLine(src, pos)
+		if next == pos {
+			return pos
+		}
+		pos = next
+	}
+}
+
+// fmtMergeEdits combines any overlapping edits, which can happen when a
+// removed line directly follows an insertion point.
+func fmtMergeEdits(edits []fmtEdit) []fmtEdit {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Start < edits[j].Start
+	})
+	var ret []fmtEdit
+	for _, edit := range edits {
+		if n := len(ret); n > 0 && edit.Start < ret[n-1].End {
+			last := &ret[n-1]
+			last.Text = append(last.Text, edit.Text...)
+			if edit.End > last.End {
+				last.End = edit.End
+			}
+			continue
+		}
+		ret = append(ret, edit)
+	}
+	return ret
+}
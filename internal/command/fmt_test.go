@@ -146,6 +146,85 @@ func TestFmt(t *testing.T) {
 	}
 }
 
+// TestFmt_rules runs each of the opt-in rules separately over the files in
+// testdata/fmt/rules, named after the rule they exercise.
+func TestFmt_rules(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range fmtRules {
+		t.Run(rule.Name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "fmt", "rules", rule.Name+"_in.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", "fmt", "rules", rule.Name+"_out.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotFile := filepath.Join(tmpDir, rule.Name+"_got.tf")
+			if err := os.WriteFile(gotFile, input, 0700); err != nil {
+				t.Fatal(err)
+			}
+
+			ui := cli.NewMockUi()
+			c := &FmtCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					Ui:               ui,
+				},
+			}
+			args := []string{"-rules=" + rule.Name, gotFile}
+			if code := c.Run(args); code != 0 {
+				t.Fatalf("fmt command was unsuccessful:\n%s", ui.ErrorWriter.String())
+			}
+
+			got, err := os.ReadFile(gotFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+
+			// The rules are opt-in, so the default formatting must leave
+			// the input in a state that the rule would still change.
+			if err := os.WriteFile(gotFile, input, 0700); err != nil {
+				t.Fatal(err)
+			}
+			if code := c.Run([]string{gotFile}); code != 0 {
+				t.Fatalf("fmt command was unsuccessful:\n%s", ui.ErrorWriter.String())
+			}
+			got, err = os.ReadFile(gotFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) == string(want) {
+				t.Errorf("default formatting applied the %s rule", rule.Name)
+			}
+		})
+	}
+}
+
+func TestFmt_rulesInvalid(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+	if code := c.Run([]string{"-rules=interpolation,sort-everything", "-"}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1", code)
+	}
+	want := `unknown rule "sort-everything"`
+	if got := ui.ErrorWriter.String(); !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+}
+
 func TestFmt_nonexist(t *testing.T) {
 	tempDir := fmtFixtureWriteDir(t)
 
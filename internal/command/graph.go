@@ -32,6 +32,7 @@ func (c *GraphCommand) Run(args []string) int {
 	var moduleDepth int
 	var verbose bool
 	var planPath string
+	var format string
 
 	ctx := c.CommandContext()
 
@@ -43,12 +44,25 @@ func (c *GraphCommand) Run(args []string) int {
 	cmdFlags.IntVar(&moduleDepth, "module-depth", -1, "module-depth")
 	cmdFlags.BoolVar(&verbose, "verbose", false, "verbose")
 	cmdFlags.StringVar(&planPath, "plan", "", "plan")
+	cmdFlags.StringVar(&format, "format", "dot", "format")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	switch format {
+	case "dot":
+	case "mermaid", "d2":
+		if drawCycles {
+			c.Ui.Error("The -draw-cycles option can only be used with -format=dot.")
+			return 1
+		}
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported graph format %q. The -format option must be \"dot\", \"mermaid\", or \"d2\".", format))
+		return 1
+	}
+
 	configPath, err := modulePath(cmdFlags.Args())
 	if err != nil {
 		c.Ui.Error(err.Error())
@@ -225,14 +239,28 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
-	graphStr, err := tofu.GraphDot(g, &dag.DotOpts{
-		DrawCycles: drawCycles,
-		MaxDepth:   moduleDepth,
-		Verbose:    verbose,
-	})
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
-		return 1
+	var graphStr string
+	switch format {
+	case "mermaid", "d2":
+		opts := &tofu.GraphDiagramOpts{Verbose: verbose}
+		if lr.Plan != nil {
+			opts.Changes = lr.Plan.Changes
+		}
+		if format == "mermaid" {
+			graphStr = tofu.GraphMermaid(g, opts)
+		} else {
+			graphStr = tofu.GraphD2(g, opts)
+		}
+	default:
+		graphStr, err = tofu.GraphDot(g, &dag.DotOpts{
+			DrawCycles: drawCycles,
+			MaxDepth:   moduleDepth,
+			Verbose:    verbose,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
+			return 1
+		}
 	}
 
 	if diags.HasErrors() {
@@ -255,17 +283,25 @@ Usage: tofu [global options] graph [options]
   Produces a representation of the dependency graph between different
   objects in the current configuration and state.
 
-  The graph is presented in the DOT language. The typical program that can
-  read this format is GraphViz, but many web services are also available
-  to read this format.
+  By default the graph is presented in the DOT language. The typical program
+  that can read this format is GraphViz, but many web services are also
+  available to read this format. The graph can also be presented as a
+  Mermaid flowchart or a D2 diagram, for including in documentation and
+  pull requests.
 
 Options:
 
   -plan=tfplan     Render graph using the specified plan file instead of the
                    configuration in the current directory.
 
+  -format=dot      Format of the graph. Can be: dot, mermaid, or d2. The
+                   mermaid and d2 formats group the objects of each module
+                   together and, if you also set the -plan=... option, color
+                   each resource by the action planned for it.
+
   -draw-cycles     Highlight any cycles in the graph with colored edges.
-                   This helps when diagnosing cycle errors.
+                   This helps when diagnosing cycle errors. Only supported
+                   with -format=dot.
 
   -type=plan       Type of graph to output. Can be: plan, plan-refresh-only,
                    plan-destroy, or apply. By default OpenTofu chooses
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 
@@ -161,3 +162,118 @@ func TestGraph_plan(t *testing.T) {
 		t.Fatalf("doesn't look like digraph: %s", output)
 	}
 }
+
+func TestGraph_diagramFormats(t *testing.T) {
+	testCwdTemp(t)
+
+	plannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.UnknownVal(cty.String),
+		"ami": cty.StringVal("bar"),
+	})
+	priorValRaw, err := plans.NewDynamicValue(cty.NullVal(plannedVal.Type()), plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plannedValRaw, err := plans.NewDynamicValue(plannedVal, plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := testPlan(t)
+	for _, change := range []struct {
+		module addrs.ModuleInstance
+		name   string
+		action plans.Action
+	}{
+		{addrs.RootModuleInstance, "foo", plans.Create},
+		{addrs.RootModuleInstance.Child("child", addrs.NoKey), "baz", plans.Update},
+	} {
+		plan.Changes.Resources = append(plan.Changes.Resources, &plans.ResourceInstanceChangeSrc{
+			Addr: addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: change.name,
+			}.Instance(addrs.NoKey).Absolute(change.module),
+			ChangeSrc: plans.ChangeSrc{
+				Action: change.action,
+				Before: priorValRaw,
+				After:  plannedValRaw,
+			},
+			ProviderAddr: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		})
+	}
+	_, configSnap := testModuleWithSnapshot(t, "graph-modules")
+	planPath := testPlanFile(t, configSnap, states.NewState(), plan)
+
+	tests := map[string]string{
+		"mermaid": `flowchart LR
+  n1["provider[#quot;registry.opentofu.org/hashicorp/test#quot;]"]
+  n2["test_instance.foo"]
+  subgraph m0 ["module.child"]
+    n0["module.child.test_instance.baz"]
+  end
+  n0 --> n2
+  n2 --> n1
+  classDef update fill:#fff8c5,stroke:#bf8700
+  class n0 update
+  classDef create fill:#dafbe1,stroke:#1a7f37
+  class n2 create
+`,
+		"d2": `direction: right
+classes: {
+  update: {style: {fill: "#fff8c5"; stroke: "#bf8700"}}
+  create: {style: {fill: "#dafbe1"; stroke: "#1a7f37"}}
+}
+n1: "provider[\"registry.opentofu.org/hashicorp/test\"]"
+n2: "test_instance.foo" {class: create}
+m0: "module.child" {
+  n0: "module.child.test_instance.baz" {class: update}
+}
+m0.n0 -> n2
+n2 -> n1
+`,
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			c := &GraphCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+					Ui:               ui,
+				},
+			}
+
+			args := []string{
+				"-format=" + format,
+				"-plan=" + planPath,
+			}
+			if code := c.Run(args); code != 0 {
+				t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+			}
+
+			got := strings.TrimSpace(ui.OutputWriter.String())
+			if diff := cmp.Diff(strings.TrimSpace(want), got); diff != "" {
+				t.Errorf("wrong output\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGraph_invalidFormat(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-format=svg"}); code != 1 {
+		t.Fatalf("unexpected success: %s", ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), `Unsupported graph format "svg"`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+}
@@ -7,6 +7,7 @@ package command
 
 import (
 	"fmt"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/mitchellh/cli"
@@ -29,6 +30,10 @@ func (h uiModuleInstallHooks) Download(modulePath, packageAddr string, v *versio
 	}
 }
 
+func (h uiModuleInstallHooks) Downloaded(modulePath, packageAddr string, size int64, elapsed time.Duration) {
+	h.Ui.Info(fmt.Sprintf("Downloaded %s for %s (%s)", packageAddr, modulePath, formatTransferStats(size, elapsed)))
+}
+
 func (h uiModuleInstallHooks) Install(modulePath string, v *version.Version, localDir string) {
 	if h.ShowLocalPaths {
 		h.Ui.Info(fmt.Sprintf("- %s in %s", modulePath, localDir))
@@ -36,3 +41,25 @@ func (h uiModuleInstallHooks) Install(modulePath string, v *version.Version, loc
 		h.Ui.Info(fmt.Sprintf("- %s", modulePath))
 	}
 }
+
+// formatTransferStats describes the retrieval of a module or provider package
+// of the given size in bytes, such as "12.5 MiB in 1.2s, 10.4 MiB/s".
+func formatTransferStats(size int64, elapsed time.Duration) string {
+	// We round to a tenth of a second, but avoid reporting a zero duration.
+	elapsed = max(elapsed.Round(100*time.Millisecond), 100*time.Millisecond)
+	speed := int64(float64(size) / elapsed.Seconds())
+	return fmt.Sprintf("%s in %s, %s/s", formatByteSize(size), elapsed, formatByteSize(speed))
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
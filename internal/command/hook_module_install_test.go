@@ -0,0 +1,28 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTransferStats(t *testing.T) {
+	tests := []struct {
+		size    int64
+		elapsed time.Duration
+		want    string
+	}{
+		{512, 10 * time.Millisecond, "512 B in 100ms, 5.0 KiB/s"},
+		{3 * 1024 * 1024, 1500 * time.Millisecond, "3.0 MiB in 1.5s, 2.0 MiB/s"},
+		{126141235, 4200 * time.Millisecond, "120.3 MiB in 4.2s, 28.6 MiB/s"},
+	}
+	for _, test := range tests {
+		if got := formatTransferStats(test.size, test.elapsed); got != test.want {
+			t.Errorf("wrong result for %d bytes in %s\ngot:  %s\nwant: %s", test.size, test.elapsed, got, test.want)
+		}
+	}
+}
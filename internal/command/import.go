@@ -42,6 +42,7 @@ func (c *ImportCommand) Run(args []string) int {
 	}
 
 	var configPath string
+	var fromFile string
 	args = c.Meta.process(args)
 
 	cmdFlags := c.Meta.extendedFlagSet("import")
@@ -51,6 +52,7 @@ func (c *ImportCommand) Run(args []string) int {
 	cmdFlags.StringVar(&c.Meta.stateOutPath, "state-out", "", "path")
 	cmdFlags.StringVar(&c.Meta.backupPath, "backup", "", "path")
 	cmdFlags.StringVar(&configPath, "config", pwd, "path")
+	cmdFlags.StringVar(&fromFile, "from-file", "", "path")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
@@ -58,38 +60,61 @@ func (c *ImportCommand) Run(args []string) int {
 		return 1
 	}
 
-	args = cmdFlags.Args()
-	if len(args) != 2 {
-		c.Ui.Error("The import command expects two arguments.")
-		cmdFlags.Usage()
-		return 1
-	}
-
 	var diags tfdiags.Diagnostics
 
-	// Parse the provided resource address.
-	traversalSrc := []byte(args[0])
-	traversal, travDiags := hclsyntax.ParseTraversalAbs(traversalSrc, "<import-address>", hcl.Pos{Line: 1, Column: 1})
-	diags = diags.Append(travDiags)
-	if travDiags.HasErrors() {
-		c.registerSynthConfigSource("<import-address>", traversalSrc) // so we can include a source snippet
-		c.showDiagnostics(diags)
-		c.Ui.Info(importCommandInvalidAddressReference)
-		return 1
-	}
-	addr, addrDiags := addrs.ParseAbsResourceInstance(traversal)
-	diags = diags.Append(addrDiags)
-	if addrDiags.HasErrors() {
-		c.registerSynthConfigSource("<import-address>", traversalSrc) // so we can include a source snippet
-		c.showDiagnostics(diags)
-		c.Ui.Info(importCommandInvalidAddressReference)
-		return 1
+	var entries []importManifestEntry
+	args = cmdFlags.Args()
+	if fromFile != "" {
+		if len(args) != 0 {
+			c.Ui.Error("The import command doesn't expect any arguments when using -from-file.")
+			cmdFlags.Usage()
+			return 1
+		}
+		var manifestDiags tfdiags.Diagnostics
+		entries, manifestDiags = readImportManifest(fromFile)
+		diags = diags.Append(manifestDiags)
+		if manifestDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	} else {
+		if len(args) != 2 {
+			c.Ui.Error("The import command expects two arguments.")
+			cmdFlags.Usage()
+			return 1
+		}
+		entries = []importManifestEntry{
+			{Address: args[0], ID: args[1], source: "<import-address>"},
+		}
 	}
 
-	if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
-		diags = diags.Append(errors.New("A managed resource address is required. Importing into a data resource is not allowed."))
-		c.showDiagnostics(diags)
-		return 1
+	// Parse the provided resource addresses.
+	targetAddrs := make([]addrs.AbsResourceInstance, len(entries))
+	sources := make(map[string]string, len(entries))
+	for i, entry := range entries {
+		addr, addrDiags := c.parseImportAddress(entry)
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			c.Ui.Info(importCommandInvalidAddressReference)
+			return 1
+		}
+		if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			diags = diags.Append(errors.New("A managed resource address is required. Importing into a data resource is not allowed."))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if prev, exists := sources[addr.String()]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Duplicate import address",
+				fmt.Sprintf("%s is imported by both %s and %s. Each resource instance can only be imported once.", addr, prev, entry.source),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		sources[addr.String()] = entry.source
+		targetAddrs[i] = addr
 	}
 
 	if !c.dirIsConfigPath(configPath) {
@@ -122,52 +147,17 @@ func (c *ImportCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Verify that the given address points to something that exists in config.
-	// This is to reduce the risk that a typo in the resource address will
-	// import something that OpenTofu will want to immediately destroy on
-	// the next plan, and generally acts as a reassurance of user intent.
-	targetConfig := config.DescendentForInstance(addr.Module)
-	if targetConfig == nil {
-		modulePath := addr.Module.String()
-		diags = diags.Append(&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Import to non-existent module",
-			Detail: fmt.Sprintf(
-				"%s is not defined in the configuration. Please add configuration for this module before importing into it.",
-				modulePath,
-			),
-		})
-		c.showDiagnostics(diags)
-		return 1
-	}
-	targetMod := targetConfig.Module
-	rcs := targetMod.ManagedResources
-	var rc *configs.Resource
-	resourceRelAddr := addr.Resource.Resource
-	for _, thisRc := range rcs {
-		if resourceRelAddr.Type == thisRc.Type && resourceRelAddr.Name == thisRc.Name {
-			rc = thisRc
-			break
+	targets := make([]*tofu.ImportTarget, len(entries))
+	for i, entry := range entries {
+		if !c.checkImportTargetConfig(config, targetAddrs[i], entry, diags) {
+			return 1
 		}
-	}
-	if rc == nil {
-		modulePath := addr.Module.String()
-		if modulePath == "" {
-			modulePath = "the root module"
+		targets[i] = &tofu.ImportTarget{
+			CommandLineImportTarget: &tofu.CommandLineImportTarget{
+				Addr: targetAddrs[i],
+				ID:   entry.ID,
+			},
 		}
-
-		c.showDiagnostics(diags)
-
-		// This is not a diagnostic because currently our diagnostics printer
-		// doesn't support having a code example in the detail, and there's
-		// a code example in this message.
-		// TODO: Improve the diagnostics printer so we can use it for this
-		// message.
-		c.Ui.Error(fmt.Sprintf(
-			importCommandMissingResourceFmt,
-			addr, modulePath, resourceRelAddr.Type, resourceRelAddr.Name,
-		))
-		return 1
 	}
 
 	// Check for user-supplied plugin path
@@ -244,18 +234,11 @@ func (c *ImportCommand) Run(args []string) int {
 		}
 	}()
 
-	// Perform the import. Note that as you can see it is possible for this
-	// API to import more than one resource at once. For now, we only allow
-	// one while we stabilize this feature.
+	// Perform the import. All of the targets are imported in a single walk
+	// of the import graph, which orders them by their dependencies, and the
+	// resulting state is only persisted if all of them succeed.
 	newState, importDiags := lr.Core.Import(ctx, lr.Config, lr.InputState, &tofu.ImportOpts{
-		Targets: []*tofu.ImportTarget{
-			{
-				CommandLineImportTarget: &tofu.CommandLineImportTarget{
-					Addr: addr,
-					ID:   args[1],
-				},
-			},
-		},
+		Targets: targets,
 
 		// The LocalRun idea is designed around our primary operations, so
 		// the input variables end up represented as plan options even though
@@ -297,9 +280,104 @@ func (c *ImportCommand) Run(args []string) int {
 	return 0
 }
 
+// parseImportAddress parses the address of the resource instance to import
+// for the given entry.
+func (c *ImportCommand) parseImportAddress(entry importManifestEntry) (addrs.AbsResourceInstance, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	traversalSrc := []byte(entry.Address)
+	traversal, travDiags := hclsyntax.ParseTraversalAbs(traversalSrc, entry.source, hcl.Pos{Line: 1, Column: 1})
+	diags = diags.Append(travDiags)
+	if travDiags.HasErrors() {
+		c.registerSynthConfigSource(entry.source, traversalSrc) // so we can include a source snippet
+		return addrs.AbsResourceInstance{}, diags
+	}
+	addr, addrDiags := addrs.ParseAbsResourceInstance(traversal)
+	diags = diags.Append(addrDiags)
+	if addrDiags.HasErrors() {
+		c.registerSynthConfigSource(entry.source, traversalSrc) // so we can include a source snippet
+	}
+	return addr, diags
+}
+
+// checkImportTargetConfig verifies that the given address points to
+// something that exists in config, and that it uses the provider
+// configuration requested by the entry, if any. This is to reduce the risk
+// that a typo in the resource address will import something that OpenTofu
+// will want to immediately destroy on the next plan, and generally acts as a
+// reassurance of user intent.
+//
+// If the target isn't valid, checkImportTargetConfig shows the given
+// diagnostics along with the problem and returns false.
+func (c *ImportCommand) checkImportTargetConfig(config *configs.Config, addr addrs.AbsResourceInstance, entry importManifestEntry, diags tfdiags.Diagnostics) bool {
+	targetConfig := config.DescendentForInstance(addr.Module)
+	if targetConfig == nil {
+		modulePath := addr.Module.String()
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Import to non-existent module",
+			Detail: fmt.Sprintf(
+				"%s is not defined in the configuration. Please add configuration for this module before importing into it.",
+				modulePath,
+			),
+		})
+		c.showDiagnostics(diags)
+		return false
+	}
+	targetMod := targetConfig.Module
+	rcs := targetMod.ManagedResources
+	var rc *configs.Resource
+	resourceRelAddr := addr.Resource.Resource
+	for _, thisRc := range rcs {
+		if resourceRelAddr.Type == thisRc.Type && resourceRelAddr.Name == thisRc.Name {
+			rc = thisRc
+			break
+		}
+	}
+	if rc == nil {
+		modulePath := addr.Module.String()
+		if modulePath == "" {
+			modulePath = "the root module"
+		}
+
+		c.showDiagnostics(diags)
+
+		// This is not a diagnostic because currently our diagnostics printer
+		// doesn't support having a code example in the detail, and there's
+		// a code example in this message.
+		// TODO: Improve the diagnostics printer so we can use it for this
+		// message.
+		c.Ui.Error(fmt.Sprintf(
+			importCommandMissingResourceFmt,
+			addr, modulePath, resourceRelAddr.Type, resourceRelAddr.Name,
+		))
+		return false
+	}
+
+	// The provider configuration for the import is always the one selected
+	// by the resource block, so a manifest can only confirm it.
+	if entry.Provider != "" {
+		if configured := rc.ProviderConfigAddr().StringCompact(); entry.Provider != configured {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Provider configuration mismatch",
+				Detail: fmt.Sprintf(
+					"The import manifest entry at %s expects %s to use the provider configuration %s, but its resource block uses %s. To import it with a different provider configuration, set the provider argument in the resource block.",
+					entry.source, addr, entry.Provider, configured,
+				),
+				Subject: rc.DeclRange.Ptr(),
+			})
+			c.showDiagnostics(diags)
+			return false
+		}
+	}
+	return true
+}
+
 func (c *ImportCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] import [options] ADDR ID
+       tofu [global options] import [options] -from-file=FILE
 
   Import existing infrastructure into your OpenTofu state.
 
@@ -314,6 +392,9 @@ Usage: tofu [global options] import [options] ADDR ID
   determine the ID syntax to use. It typically matches directly to the ID
   that the provider uses.
 
+  To import many resources at once, list them in a manifest file given
+  with -from-file instead of giving ADDR and ID.
+
   This command will not modify your infrastructure, but it will make
   network requests to inspect parts of your infrastructure relevant to
   the resource being imported.
@@ -332,6 +413,12 @@ Options:
                           will be performed. All locations, for all errors
                           will be listed. Disabled by default
 
+  -from-file=FILE         Import each resource listed in the given manifest
+                          file, which is either a JSON array of objects with
+                          "address", "id", and optional "provider" properties,
+                          or a CSV file with those columns. The format is
+                          chosen by the file extension, .json or .csv.
+
   -config=path            Path to a directory of OpenTofu configuration files
                           to use to configure the provider. Defaults to pwd.
                           If no config files are present, they must be provided
@@ -0,0 +1,140 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// importManifestEntry is one resource instance for "tofu import" to import,
+// given either as command line arguments or as an entry in a manifest file
+// given with the -from-file option.
+type importManifestEntry struct {
+	Address string `json:"address"`
+	ID      string `json:"id"`
+
+	// Provider optionally names the provider configuration that the
+	// resource instance is expected to be imported with, such as "aws.west".
+	Provider string `json:"provider,omitempty"`
+
+	// source describes where the entry came from, for use as the filename
+	// in diagnostics.
+	source string
+}
+
+// readImportManifest reads the entries of an import manifest file, which is
+// either a JSON array of objects with "address", "id", and optional
+// "provider" properties, or a CSV file with those columns in that order. The
+// format is chosen by the file extension.
+func readImportManifest(path string) ([]importManifestEntry, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read import manifest",
+			fmt.Sprintf("Could not read %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	var entries []importManifestEntry
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		entries, err = parseImportManifestJSON(path, src)
+	case ".csv":
+		entries, err = parseImportManifestCSV(path, src)
+	default:
+		err = fmt.Errorf("the file name must end with .json or .csv, to select the manifest format")
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid import manifest",
+			fmt.Sprintf("Could not read %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	if len(entries) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Empty import manifest",
+			fmt.Sprintf("The import manifest %s doesn't include any resources to import.", path),
+		))
+		return nil, diags
+	}
+	for _, entry := range entries {
+		if entry.Address == "" || entry.ID == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid import manifest",
+				fmt.Sprintf("The import manifest entry at %s must have both an address and an ID.", entry.source),
+			))
+		}
+	}
+	return entries, diags
+}
+
+func parseImportManifestJSON(path string, src []byte) ([]importManifestEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.DisallowUnknownFields()
+	var entries []importManifestEntry
+	if err := dec.Decode(&entries); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].source = fmt.Sprintf("%s entry %d", path, i+1)
+	}
+	return entries, nil
+}
+
+func parseImportManifestCSV(path string, src []byte) ([]importManifestEntry, error) {
+	r := csv.NewReader(bytes.NewReader(src))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var entries []importManifestEntry
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		// The first row can optionally be a header naming the columns.
+		if len(entries) == 0 && strings.EqualFold(record[0], "address") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d must have an address, an ID, and optionally a provider configuration, but it has %d columns", line, len(record))
+		}
+		entry := importManifestEntry{
+			Address: record[0],
+			ID:      record[1],
+			source:  fmt.Sprintf("%s line %d", path, line),
+		}
+		if len(record) == 3 {
+			entry.Provider = record[2]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -10,9 +10,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 
@@ -984,3 +987,131 @@ test_instance.foo:
   ID = yay
   provider = provider["registry.opentofu.org/hashicorp/test"]
 `
+
+func TestImport_fromFile(t *testing.T) {
+	for _, manifest := range []string{"imports.json", "imports.csv"} {
+		t.Run(manifest, func(t *testing.T) {
+			t.Chdir(testFixturePath("import-manifest"))
+
+			statePath := testTempFile(t)
+
+			p := testProvider()
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			c := &ImportCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					Ui:               ui,
+					View:             view,
+				},
+			}
+
+			var mu sync.Mutex
+			var imported []string
+			p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+				mu.Lock()
+				imported = append(imported, req.ID)
+				mu.Unlock()
+				return providers.ImportResourceStateResponse{
+					ImportedResources: []providers.ImportedResource{
+						{
+							TypeName: "test_instance",
+							State: cty.ObjectVal(map[string]cty.Value{
+								"id": cty.StringVal(req.ID),
+							}),
+						},
+					},
+				}
+			}
+			p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"id": {Type: cty.String, Optional: true, Computed: true},
+							},
+						},
+					},
+				},
+			}
+
+			args := []string{
+				"-state", statePath,
+				"-from-file", manifest,
+			}
+			if code := c.Run(args); code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+			}
+
+			sort.Strings(imported)
+			if diff := cmp.Diff([]string{"bar-0-id", "bar-1-id", "foo-id"}, imported); diff != "" {
+				t.Errorf("wrong imported IDs\n%s", diff)
+			}
+			testStateOutput(t, statePath, testImportFromFileStr)
+		})
+	}
+}
+
+func TestImport_fromFileInvalid(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"provider mismatch": {
+			[]string{"-from-file=wrong-provider.csv"},
+			"expects test_instance.foo to use the provider configuration test.west, but its resource block uses test",
+		},
+		"duplicate address": {
+			[]string{"-from-file=duplicate.json"},
+			"test_instance.foo is imported by both duplicate.json entry 1 and duplicate.json entry 2",
+		},
+		"unsupported format": {
+			[]string{"-from-file=main.tf"},
+			"the file name must end with .json or .csv",
+		},
+		"positional arguments": {
+			[]string{"-from-file=imports.json", "test_instance.foo", "foo-id"},
+			"doesn't expect any arguments when using -from-file",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(testFixturePath("import-manifest"))
+
+			statePath := testTempFile(t)
+
+			p := testProvider()
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			c := &ImportCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					Ui:               ui,
+					View:             view,
+				},
+			}
+
+			if code := c.Run(append([]string{"-state", statePath}, test.args...)); code != 1 {
+				t.Fatalf("import succeeded; expected failure")
+			}
+			if p.ImportResourceStateCalled {
+				t.Error("ImportResourceState should not be called")
+			}
+			if got := strings.Join(strings.Fields(ui.ErrorWriter.String()), " "); !strings.Contains(got, test.want) {
+				t.Errorf("incorrect message\nwant substring: %s\ngot:\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+const testImportFromFileStr = `
+test_instance.bar.0:
+  ID = bar-0-id
+  provider = provider["registry.opentofu.org/hashicorp/test"].west
+test_instance.bar.1:
+  ID = bar-1-id
+  provider = provider["registry.opentofu.org/hashicorp/test"].west
+test_instance.foo:
+  ID = foo-id
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+`
@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/svchost"
@@ -52,7 +53,7 @@ func (c *InitCommand) Run(args []string) int {
 	defer span.End()
 
 	var flagFromModule, flagLockfile, testsDirectory string
-	var flagBackend, flagCloud, flagGet, flagUpgrade bool
+	var flagBackend, flagCloud, flagGet, flagUpgrade, flagDryRun, flagCacheSchemas bool
 	var flagPluginPath FlagStringSlice
 	flagConfigExtra := newRawFlags("-backend-config")
 
@@ -69,11 +70,14 @@ func (c *InitCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.reconfigure, "reconfigure", false, "reconfigure")
 	cmdFlags.BoolVar(&c.migrateState, "migrate-state", false, "migrate state")
 	cmdFlags.BoolVar(&flagUpgrade, "upgrade", false, "")
+	cmdFlags.BoolVar(&flagDryRun, "dry-run", false, "")
 	cmdFlags.Var(&flagPluginPath, "plugin-dir", "plugin directory")
 	cmdFlags.StringVar(&flagLockfile, "lockfile", "", "Set a dependency lockfile mode")
 	cmdFlags.BoolVar(&c.Meta.ignoreRemoteVersion, "ignore-remote-version", false, "continue even if remote and local OpenTofu versions are incompatible")
 	cmdFlags.StringVar(&testsDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&c.outputInJSON, "json", false, "json")
+	cmdFlags.BoolVar(&flagCacheSchemas, "cache-schemas", false, "cache provider schemas for validation")
+	cmdFlags.IntVar(&c.Meta.installJobs, "jobs", DefaultInstallJobs, "jobs")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -108,6 +112,20 @@ func (c *InitCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.installJobs < 1 {
+		c.Ui.Error("The -jobs option must be at least 1")
+		return 1
+	}
+
+	if flagDryRun && !flagUpgrade {
+		c.Ui.Error("The -dry-run option can only be used with -upgrade")
+		return 1
+	}
+	if flagDryRun && flagFromModule != "" {
+		c.Ui.Error("The -dry-run and -from-module options are mutually-exclusive")
+		return 1
+	}
+
 	// Copying the state only happens during backend migration, so setting
 	// -force-copy implies -migrate-state
 	if c.forceInitCopy {
@@ -128,6 +146,12 @@ func (c *InitCommand) Run(args []string) int {
 		return 1
 	}
 
+	if flagDryRun {
+		ctx, done := c.InterruptibleContext(ctx)
+		defer done()
+		return c.upgradeDryRun(ctx, path, testsDirectory, flagGet, flagPluginPath)
+	}
+
 	if err := c.storePluginPath(c.pluginPath); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error saving -plugin-path values: %s", err))
 		return 1
@@ -361,6 +385,16 @@ func (c *InitCommand) Run(args []string) int {
 		header = true
 	}
 
+	if flagCacheSchemas {
+		c.Ui.Output(c.Colorize().Color("\n[reset][bold]Caching provider schemas..."))
+		header = true
+		diags = diags.Append(c.cacheProviderSchemas(ctx))
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
 	// If we outputted information, then we need to output a newline
 	// so that our success message is nicely spaced out from prior text.
 	if header {
@@ -580,6 +614,11 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 		stateReqs := state.ProviderRequirements()
 		reqs = reqs.Merge(stateReqs)
 	}
+	origins, hclDiags := config.ProviderOrigins()
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return false, true, diags
+	}
 
 	potentialProviderConflicts := make(map[string][]string)
 
@@ -621,24 +660,30 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 		return false, true, diags
 	}
 
-	var inst *providercache.Installer
+	var source getproviders.Source
 	if len(pluginDirs) == 0 {
 		// By default we use a source that looks for providers in all of the
 		// standard locations, possibly customized by the user in CLI config.
-		inst = c.providerInstaller()
+		source = c.providerInstallSource()
 	} else {
 		// If the user passes at least one -plugin-dir then that circumvents
 		// the usual sources and forces OpenTofu to consult only the given
 		// directories. Anything not available in one of those directories
 		// is not available for installation.
-		source := c.providerCustomLocalDirectorySource(ctx, pluginDirs)
-		inst = c.providerInstallerCustomSource(source)
+		source = c.providerCustomLocalDirectorySource(ctx, pluginDirs)
 
 		// The default (or configured) search paths are logged earlier, in provider_source.go
 		// Log that those are being overridden by the `-plugin-dir` command line options
 		log.Println("[DEBUG] init: overriding provider plugin search paths")
 		log.Printf("[DEBUG] will search for provider plugins in %s", pluginDirs)
 	}
+	if len(origins) != 0 {
+		// Providers whose required_providers entries declare an origin are
+		// installed from there, but keep their own addresses everywhere
+		// else, including in the dependency lock file.
+		source = getproviders.NewRedirectSource(source, origins)
+	}
+	inst := c.providerInstallerCustomSource(source)
 
 	// We want to print out a nice warning if we don't manage to pull
 	// checksums for all our providers. This is tracked via callbacks
@@ -773,6 +818,15 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 					))
 				}
 
+			case getproviders.ErrUnauthorized:
+				diags = diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to query available provider packages",
+					fmt.Sprintf("Could not retrieve the list of available versions for provider %s: %s.",
+						provider.ForDisplay(), err,
+					),
+				), tfdiags.ErrorCodeAuthFailed))
+
 			case getproviders.ErrRequestCanceled:
 				// We don't attribute cancellation to any particular operation,
 				// but rather just emit a single general message about it at
@@ -890,6 +944,9 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 				))
 			}
 		},
+		FetchPackageDownloaded: func(provider addrs.Provider, version getproviders.Version, size int64, elapsed time.Duration) {
+			c.Ui.Info(fmt.Sprintf("- Downloaded %s v%s (%s)", provider.ForDisplay(), version, formatTransferStats(size, elapsed)))
+		},
 		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult) {
 			var keyID string
 			if authResult != nil && authResult.Signed() {
@@ -1206,10 +1263,13 @@ func (c *InitCommand) AutocompleteFlags() complete.Flags {
 		"-backend":        completePredictBoolean,
 		"-cloud":          completePredictBoolean,
 		"-backend-config": complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
+		"-cache-schemas":  complete.PredictNothing,
+		"-dry-run":        complete.PredictNothing,
 		"-force-copy":     complete.PredictNothing,
 		"-from-module":    completePredictModuleSource,
 		"-get":            completePredictBoolean,
 		"-input":          completePredictBoolean,
+		"-jobs":           complete.PredictAnything,
 		"-lock":           completePredictBoolean,
 		"-lock-timeout":   complete.PredictAnything,
 		"-no-color":       complete.PredictNothing,
@@ -1253,6 +1313,12 @@ Options:
                           times. The backend type must be in the configuration
                           itself.
 
+  -cache-schemas          Also save the schemas of the selected providers in
+                          a cache shared by all working directories, so that
+                          "tofu validate -cached-schemas" can validate any
+                          configuration using the same provider versions
+                          without installing them.
+
   -compact-warnings       If OpenTofu produces any warnings that are not
                           accompanied by errors, show them in a more compact
                           form that includes only the summary messages.
@@ -1303,9 +1369,17 @@ Options:
                           default behavior of selecting exactly the version
                           recorded in the dependency lockfile.
 
+  -dry-run                With -upgrade, only report which module and provider
+                          versions would be selected, without installing
+                          anything, initializing the backend, or changing the
+                          dependency lockfile.
+
   -lockfile=MODE          Set a dependency lockfile mode.
                           Currently only "readonly" is valid.
 
+  -jobs=n                 Limit the number of modules and provider packages
+                          to download concurrently. Defaults to 10.
+
   -ignore-remote-version  A rare option used for cloud backend and the remote backend
                           only. Set this to ignore checking that the local and remote
                           OpenTofu versions use compatible state representations, making
@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/providercache"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// upgradeDryRun implements "tofu init -upgrade -dry-run", which reports the
+// module and provider versions that "tofu init -upgrade" would select without
+// installing anything, initializing the backend, or changing the dependency
+// lock file.
+func (c *InitCommand) upgradeDryRun(ctx context.Context, path, testsDir string, getModules bool, pluginDirs []string) int {
+	var diags tfdiags.Diagnostics
+
+	if getModules {
+		modsDiags := c.moduleUpgradesDryRun(ctx, path, testsDir)
+		diags = diags.Append(modsDiags)
+		if modsDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	providersDiags := c.providerUpgradesDryRun(ctx, path, testsDir, pluginDirs)
+	diags = diags.Append(providersDiags)
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color(strings.TrimSpace(outputInitUpgradeDryRun)))
+	return 0
+}
+
+func (c *InitCommand) moduleUpgradesDryRun(ctx context.Context, path, testsDir string) tfdiags.Diagnostics {
+	upgrades, diags := c.moduleUpgrades(ctx, path, testsDir)
+	if diags.HasErrors() || len(upgrades) == 0 {
+		return diags
+	}
+
+	c.Ui.Output(c.Colorize().Color("[reset][bold]Upgrading modules (dry run)..."))
+	for _, upgrade := range upgrades {
+		name := fmt.Sprintf("%s (%s)", upgrade.Key, upgrade.SourceAddr.ForDisplay())
+		if _, ok := upgrade.SourceAddr.(addrs.ModuleSourceRegistry); !ok {
+			c.Ui.Info(fmt.Sprintf("- %s: not versioned, so it would be downloaded again", name))
+			continue
+		}
+		if upgrade.Selected == nil {
+			// The diagnostics explain why we couldn't select a version.
+			continue
+		}
+		var newest string
+		if upgrade.Newest != nil && upgrade.Newest.GreaterThan(upgrade.Selected) {
+			newest = upgrade.Newest.String()
+		}
+		c.Ui.Info(upgradeDryRunLine(name, moduleDryRunVersion(upgrade.Current), upgrade.Selected.String(), upgrade.Constraint.String(), newest))
+	}
+	c.Ui.Output("")
+	return diags
+}
+
+func (c *InitCommand) providerUpgradesDryRun(ctx context.Context, path, testsDir string, pluginDirs []string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// Modules that aren't installed yet, or whose installed version
+	// doesn't load, would prevent us from loading the full configuration,
+	// so in that case we look only at the root module's requirements.
+	config, confDiags := c.loadConfigWithTests(ctx, path, testsDir)
+	if confDiags.HasErrors() {
+		rootMod, moreDiags := c.loadSingleModuleWithTests(ctx, path, testsDir)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return diags
+		}
+		config, _ = configs.BuildConfig(ctx, rootMod, configs.ModuleWalkerFunc(
+			func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+				return nil, nil, nil
+			},
+		))
+		c.Ui.Warn("Some modules aren't installed or couldn't be loaded, so only the provider requirements of the root module are included.\n")
+	}
+
+	reqs, _, hclDiags := config.ProviderRequirements()
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return diags
+	}
+	origins, hclDiags := config.ProviderOrigins()
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return diags
+	}
+
+	previousLocks, moreDiags := c.lockedDependenciesWithPredecessorRegistryShimmed()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	var source getproviders.Source
+	if len(pluginDirs) == 0 {
+		source = c.providerInstallSource()
+	} else {
+		source = c.providerCustomLocalDirectorySource(ctx, pluginDirs)
+	}
+	if len(origins) != 0 {
+		source = getproviders.NewRedirectSource(source, origins)
+	}
+	inst := c.providerInstallerCustomSource(source)
+
+	selections, err := inst.SelectProviderVersions(ctx, previousLocks, reqs, providercache.InstallUpgrades)
+	if installErr, ok := err.(providercache.InstallerError); ok {
+		failed := make([]addrs.Provider, 0, len(installErr.ProviderErrors))
+		for provider := range installErr.ProviderErrors {
+			failed = append(failed, provider)
+		}
+		sort.Slice(failed, func(i, j int) bool {
+			return failed[i].LessThan(failed[j])
+		})
+		for _, provider := range failed {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to query available provider packages",
+				fmt.Sprintf("Could not retrieve the list of available versions for provider %s: %s.", provider.ForDisplay(), installErr.ProviderErrors[provider]),
+			))
+		}
+	} else if err != nil {
+		diags = diags.Append(err)
+	}
+	if len(selections) == 0 {
+		return diags
+	}
+
+	providers := make([]addrs.Provider, 0, len(selections))
+	for provider := range selections {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].LessThan(providers[j])
+	})
+
+	c.Ui.Output(c.Colorize().Color("[reset][bold]Upgrading provider plugins (dry run)..."))
+	for _, provider := range providers {
+		selection := selections[provider]
+		var current string
+		if lock := previousLocks.Provider(provider); lock != nil {
+			current = lock.Version().String()
+		}
+		var newest string
+		if selection.Newest.GreaterThan(selection.Selected) {
+			newest = selection.Newest.String()
+		}
+		c.Ui.Info(upgradeDryRunLine(provider.ForDisplay(), current, selection.Selected.String(), getproviders.VersionConstraintsString(reqs[provider]), newest))
+	}
+	c.Ui.Output("")
+	return diags
+}
+
+func moduleDryRunVersion(v *version.Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// upgradeDryRunLine describes the upgrade of a single dependency for
+// "tofu init -upgrade -dry-run", where current is empty if no version is
+// currently selected and newest is empty unless a newer version than the
+// selected one is available but excluded by the constraint.
+func upgradeDryRunLine(name, current, selected, constraint, newest string) string {
+	var reason string
+	switch {
+	case newest != "":
+		reason = fmt.Sprintf("%s is excluded by the constraint %q", newest, constraint)
+	case constraint != "":
+		reason = fmt.Sprintf("newest available, allowed by the constraint %q", constraint)
+	default:
+		reason = "newest available, no version constraint"
+	}
+
+	switch current {
+	case "":
+		return fmt.Sprintf("- %s: not yet selected -> %s (%s)", name, selected, reason)
+	case selected:
+		return fmt.Sprintf("- %s: %s, unchanged (%s)", name, selected, reason)
+	default:
+		return fmt.Sprintf("- %s: %s -> %s (%s)", name, current, selected, reason)
+	}
+}
+
+const outputInitUpgradeDryRun = `
+[reset][bold]This was a dry run, so no modules or providers were installed and the dependency lock file was not changed.[reset]
+Run "tofu init -upgrade" to perform these upgrades.
+`
@@ -182,6 +182,59 @@ func TestInit_get(t *testing.T) {
 	}
 }
 
+func TestInit_getJobs(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-jobs"), td)
+	t.Chdir(td)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	args := []string{"-jobs=2"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	// The modules are installed concurrently, so they can appear in any order.
+	output := ui.OutputWriter.String()
+	for _, name := range []string{"bar", "baz", "foo"} {
+		if want := fmt.Sprintf("%s in %s", name, name); !strings.Contains(output, want) {
+			t.Errorf("doesn't look like we installed module %q: %s", name, output)
+		}
+	}
+}
+
+func TestInit_jobsInvalid(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get"), td)
+	t.Chdir(td)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-jobs=0"}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "The -jobs option must be at least 1"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestInit_getUpgradeModules(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -1462,6 +1515,55 @@ func TestInit_getProviderSource(t *testing.T) {
 	}
 }
 
+func TestInit_getProviderOrigin(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-provider-origin"), td)
+	t.Chdir(td)
+
+	overrides := metaOverridesForProvider(testProvider())
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	// The provider is only available from its origin.
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"registry.example.com/mirror/alpha": {"1.2.3"},
+	})
+	defer close()
+	m := Meta{
+		testingOverrides: overrides,
+		Ui:               ui,
+		View:             view,
+		ProviderSource:   providerSource,
+	}
+
+	c := &InitCommand{
+		Meta: m,
+	}
+
+	if code := c.Run([]string{"-backend=false"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	// The provider is installed and locked under its own address.
+	installedPath := fmt.Sprintf(".terraform/providers/registry.opentofu.org/acme/alpha/1.2.3/%s", getproviders.CurrentPlatform)
+	if _, err := os.Stat(installedPath); os.IsNotExist(err) {
+		t.Error("provider 'alpha' not installed under its own address")
+	}
+
+	locks, err := m.lockedDependencies()
+	if err != nil {
+		t.Fatalf("failed to get locked dependencies: %s", err)
+	}
+	alpha := addrs.MustParseProviderSourceString("acme/alpha")
+	if got := locks.Provider(alpha); got == nil {
+		t.Errorf("no lock for %s", alpha)
+	} else if got, want := got.Version(), getproviders.MustParseVersion("1.2.3"); got != want {
+		t.Errorf("wrong locked version %s; want %s", got, want)
+	}
+	if got := locks.Provider(addrs.MustParseProviderSourceString("registry.example.com/mirror/alpha")); got != nil {
+		t.Errorf("unexpected lock for the origin")
+	}
+}
+
 func TestInit_getProviderLegacyFromState(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -2058,6 +2160,98 @@ func TestInit_getUpgradePlugins(t *testing.T) {
 	}
 }
 
+func TestInit_upgradeDryRun(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-providers"), td)
+	t.Chdir(td)
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		// looking for an exact version
+		"exact": {"1.2.3"},
+		// config requires >= 2.3.3
+		"greater-than": {"2.3.4", "2.3.3", "2.3.0"},
+		// config specifies > 1.0.0 , < 3.0.0
+		"between": {"3.4.5", "2.3.4", "1.2.3"},
+	})
+	defer close()
+
+	lockFile := `
+provider "registry.opentofu.org/hashicorp/exact" {
+  version     = "1.2.3"
+  constraints = "1.2.3"
+}
+
+provider "registry.opentofu.org/hashicorp/greater-than" {
+  version     = "2.3.3"
+  constraints = ">= 2.3.3"
+}
+`
+	if err := os.WriteFile(".terraform.lock.hcl", []byte(lockFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+			ProviderSource:   providerSource,
+		},
+	}
+
+	if code := c.Run([]string{"-upgrade", "-dry-run"}); code != 0 {
+		t.Fatalf("command did not complete successfully:\n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		`- hashicorp/between: not yet selected -> 2.3.4 (3.4.5 is excluded by the constraint "> 1.0.0, < 3.0.0")`,
+		`- hashicorp/exact: 1.2.3, unchanged (newest available, allowed by the constraint "1.2.3")`,
+		`- hashicorp/greater-than: 2.3.3 -> 2.3.4 (newest available, allowed by the constraint ">= 2.3.3")`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output doesn't include %q\n%s", want, output)
+		}
+	}
+
+	// A dry run must leave the working directory exactly as it was.
+	gotLockFile, err := os.ReadFile(".terraform.lock.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lockFile, string(gotLockFile)); diff != "" {
+		t.Errorf("lock file was changed\n%s", diff)
+	}
+	if _, err := os.Stat(".terraform"); !os.IsNotExist(err) {
+		t.Errorf(".terraform directory was created")
+	}
+}
+
+func TestInit_dryRunWithoutUpgrade(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-providers"), td)
+	t.Chdir(td)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-dry-run"}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "The -dry-run option can only be used with -upgrade"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestInit_getProviderMissing(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -10,13 +10,18 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/states"
 )
 
 // MarshalCheckStates is the main entry-point for this package, which takes
 // the top-level model object for checks in state and plan, and returns a
 // JSON representation of it suitable for use in public integration points.
-func MarshalCheckStates(results *states.CheckResults) []byte {
+//
+// If config is not nil then the results for check blocks also include the
+// severity and metadata declared in the configuration.
+func MarshalCheckStates(results *states.CheckResults, config *configs.Config) []byte {
 	jsonResults := make([]checkResultStatic, 0, results.ConfigResults.Len())
 
 	for _, elem := range results.ConfigResults.Elems {
@@ -49,11 +54,16 @@ func MarshalCheckStates(results *states.CheckResults) []byte {
 			return objects[i].Address["to_display"].(string) < objects[j].Address["to_display"].(string)
 		})
 
-		jsonResults = append(jsonResults, checkResultStatic{
+		jsonResult := checkResultStatic{
 			Address:   makeStaticObjectAddr(staticAddr),
 			Status:    checkStatusForJSON(aggrResult.Status),
 			Instances: objects,
-		})
+		}
+		if check := configCheck(config, staticAddr); check != nil {
+			jsonResult.Severity = string(check.Severity)
+			jsonResult.Metadata = check.Metadata
+		}
+		jsonResults = append(jsonResults, jsonResult)
 	}
 
 	sort.Slice(jsonResults, func(i, j int) bool {
@@ -69,6 +79,21 @@ func MarshalCheckStates(results *states.CheckResults) []byte {
 	return ret
 }
 
+// configCheck returns the check block declared at the given address in the
+// given configuration, or nil if the address isn't for a check block or the
+// configuration isn't available.
+func configCheck(config *configs.Config, addr addrs.ConfigCheckable) *configs.Check {
+	checkAddr, ok := addr.(addrs.ConfigCheck)
+	if !ok || config == nil {
+		return nil
+	}
+	modCfg := config.Descendent(checkAddr.Module)
+	if modCfg == nil {
+		return nil
+	}
+	return modCfg.Module.Checks[checkAddr.Check.Name]
+}
+
 // checkResultStatic is the container for the static, configuration-driven
 // idea of "checkable object" -- a resource block with conditions, for example --
 // which ensures that we can always say _something_ about each checkable
@@ -82,6 +107,11 @@ type checkResultStatic struct {
 	// to this static object.
 	Status checkStatus `json:"status"`
 
+	// Severity and Metadata are the "severity" and "metadata" arguments of
+	// a check block, and are set only for checkable objects of kind "check".
+	Severity string            `json:"severity,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Instances contains the results for each individual dynamic object that
 	// belongs to this static object.
 	Instances []checkResultDynamic `json:"instances,omitempty"`
@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/states"
 )
 
@@ -229,7 +230,7 @@ func TestMarshalCheckStates(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			gotBytes := MarshalCheckStates(test.Input)
+			gotBytes := MarshalCheckStates(test.Input, nil)
 
 			var got any
 			err := json.Unmarshal(gotBytes, &got)
@@ -243,3 +244,68 @@ func TestMarshalCheckStates(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalCheckStates_checkBlockConfig(t *testing.T) {
+	checkBlockAddr := addrs.ConfigCheckable(addrs.Check{Name: "a"}.InModule(addrs.RootModule))
+	checkBlockInstAddr := addrs.Checkable(addrs.Check{Name: "a"}.Absolute(addrs.RootModuleInstance))
+
+	results := &states.CheckResults{
+		ConfigResults: addrs.MakeMap(
+			addrs.MakeMapElem(checkBlockAddr, &states.CheckResultAggregate{
+				Status: checks.StatusFail,
+				ObjectResults: addrs.MakeMap(
+					addrs.MakeMapElem(checkBlockInstAddr, &states.CheckResultObject{
+						Status:          checks.StatusFail,
+						FailureMessages: []string{"Certificate expires soon."},
+					}),
+				),
+			}),
+		),
+	}
+	config := &configs.Config{
+		Module: &configs.Module{
+			Checks: map[string]*configs.Check{
+				"a": {
+					Name:     "a",
+					Severity: configs.CheckSeverityError,
+					Metadata: map[string]string{"owner": "platform"},
+				},
+			},
+		},
+	}
+
+	var got any
+	if err := json.Unmarshal(MarshalCheckStates(results, config), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []any{
+		map[string]any{
+			"address": map[string]any{
+				"kind":       "check",
+				"name":       "a",
+				"to_display": "check.a",
+			},
+			"instances": []any{
+				map[string]any{
+					"address": map[string]any{
+						"to_display": "check.a",
+					},
+					"problems": []any{
+						map[string]any{
+							"message": "Certificate expires soon.",
+						},
+					},
+					"status": "fail",
+				},
+			},
+			"metadata": map[string]any{
+				"owner": "platform",
+			},
+			"severity": "error",
+			"status":   "fail",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
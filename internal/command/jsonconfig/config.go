@@ -69,6 +69,7 @@ type variable struct {
 	Description string          `json:"description,omitempty"`
 	Required    bool            `json:"required,omitempty"`
 	Sensitive   bool            `json:"sensitive,omitempty"`
+	Ephemeral   bool            `json:"ephemeral,omitempty"`
 	Deprecated  string          `json:"deprecated,omitempty"`
 }
 
@@ -113,11 +114,13 @@ type resource struct {
 }
 
 type output struct {
-	Sensitive   bool        `json:"sensitive,omitempty"`
-	Deprecated  string      `json:"deprecated,omitempty"`
-	Expression  *expression `json:"expression,omitempty"`
-	DependsOn   []string    `json:"depends_on,omitempty"`
-	Description string      `json:"description,omitempty"`
+	Sensitive   bool            `json:"sensitive,omitempty"`
+	Ephemeral   bool            `json:"ephemeral,omitempty"`
+	Deprecated  string          `json:"deprecated,omitempty"`
+	Type        json.RawMessage `json:"type,omitempty"`
+	Expression  *expression     `json:"expression,omitempty"`
+	DependsOn   []string        `json:"depends_on,omitempty"`
+	Description string          `json:"description,omitempty"`
 }
 
 type provisioner struct {
@@ -349,6 +352,7 @@ func marshalModule(c *configs.Config, schemas *tofu.Schemas, addr string) (modul
 	for _, v := range c.Module.Outputs {
 		o := output{
 			Sensitive:  v.Sensitive,
+			Ephemeral:  v.Ephemeral,
 			Deprecated: v.Deprecated,
 		}
 		if !inSingleModuleMode(schemas) {
@@ -358,6 +362,15 @@ func marshalModule(c *configs.Config, schemas *tofu.Schemas, addr string) (modul
 		if v.Description != "" {
 			o.Description = v.Description
 		}
+		// As with variables below, we leave the "type" property unset when
+		// the output doesn't declare a type or declares it as "any".
+		if v.ConstraintType != cty.NilType && !v.ConstraintType.Equals(cty.DynamicPseudoType) {
+			typeJSON, err := v.ConstraintType.MarshalJSON()
+			if err != nil {
+				return module, fmt.Errorf("failed to marshal %#v as JSON: %w", v.ConstraintType, err)
+			}
+			o.Type = typeJSON
+		}
 		if len(v.DependsOn) > 0 {
 			dependencies := make([]string, len(v.DependsOn))
 			for i, d := range v.DependsOn {
@@ -424,6 +437,7 @@ func marshalModule(c *configs.Config, schemas *tofu.Schemas, addr string) (modul
 				Required:    required,
 				Description: v.Description,
 				Sensitive:   v.Sensitive,
+				Ephemeral:   v.Ephemeral,
 				Deprecated:  v.Deprecated,
 			}
 		}
@@ -249,7 +249,59 @@ func TestMarshalModule(t *testing.T) {
 				},
 			},
 		},
-		// TODO: More test cases covering things other than input variables.
+		"output, minimal": {
+			Input: &configs.Config{
+				Module: &configs.Module{
+					Outputs: map[string]*configs.Output{
+						"example": {
+							Name: "example",
+						},
+					},
+				},
+			},
+			Schemas: emptySchemas,
+			Want: module{
+				Outputs: map[string]output{
+					"example": {
+						Expression: &expression{},
+					},
+				},
+				ModuleCalls: map[string]moduleCall{},
+			},
+		},
+		"output, object type": {
+			Input: &configs.Config{
+				Module: &configs.Module{
+					Outputs: map[string]*configs.Output{
+						"example": {
+							Name: "example",
+							ConstraintType: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+								"foo": cty.String,
+								"bar": cty.String,
+							}, []string{"bar"}),
+							Type: cty.Object(map[string]cty.Type{
+								"foo": cty.String,
+								"bar": cty.String,
+							}),
+						},
+					},
+				},
+			},
+			Schemas: emptySchemas,
+			Want: module{
+				Outputs: map[string]output{
+					"example": {
+						// Output types use the same representation as
+						// input variable types.
+						Type:       json.RawMessage(`["object",{"bar":"string","foo":"string"},["bar"]]`),
+						Expression: &expression{},
+					},
+				},
+				ModuleCalls: map[string]moduleCall{},
+			},
+		},
+		// TODO: More test cases covering things other than input variables
+		// and outputs.
 		// (For now the other details are mainly tested in package command,
 		// as part of the tests for "tofu show".)
 	}
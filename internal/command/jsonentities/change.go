@@ -115,6 +115,7 @@ const (
 	ReasonReadBecauseConfigUnknown      ChangeReason = "read_because_config_unknown"
 	ReasonReadBecauseDependencyPending  ChangeReason = "read_because_dependency_pending"
 	ReasonReadBecauseCheckNested        ChangeReason = "read_because_check_nested"
+	ReasonReadBecauseDeferredByConfig   ChangeReason = "read_because_deferred_by_config"
 )
 
 func changeReason(reason plans.ResourceInstanceChangeActionReason) ChangeReason {
@@ -147,6 +148,8 @@ func changeReason(reason plans.ResourceInstanceChangeActionReason) ChangeReason
 		return ReasonReadBecauseDependencyPending
 	case plans.ResourceInstanceReadBecauseCheckNested:
 		return ReasonReadBecauseCheckNested
+	case plans.ResourceInstanceReadBecauseDeferredByConfig:
+		return ReasonReadBecauseDeferredByConfig
 	default:
 		// This should never happen, but there's no good way to guarantee
 		// exhaustive handling of the enum, so a generic fall back is better
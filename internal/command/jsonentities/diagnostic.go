@@ -40,6 +40,7 @@ type Diagnostic struct {
 	Severity   string             `json:"severity"`
 	Summary    string             `json:"summary"`
 	Detail     string             `json:"detail"`
+	Code       string             `json:"code,omitempty"`
 	Address    string             `json:"address,omitempty"`
 	Range      *DiagnosticRange   `json:"range,omitempty"`
 	Snippet    *DiagnosticSnippet `json:"snippet,omitempty"`
@@ -171,6 +172,7 @@ func NewDiagnostic(diag tfdiags.Diagnostic, sources map[string]*hcl.File) *Diagn
 		Severity:   sev,
 		Summary:    desc.Summary,
 		Detail:     desc.Detail,
+		Code:       string(tfdiags.DiagnosticErrorCode(diag)),
 		Address:    desc.Address,
 		Range:      newDiagnosticRange(highlightRange),
 		Snippet:    snippet,
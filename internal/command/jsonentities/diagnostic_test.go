@@ -88,6 +88,19 @@ func TestNewDiagnostic(t *testing.T) {
 				Detail:   "Something is broken",
 			},
 		},
+		"sourceless error with code": {
+			tfdiags.WithErrorCode(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error acquiring the state lock",
+				"Another operation holds the lock.",
+			), tfdiags.ErrorCodeStateLocked),
+			&Diagnostic{
+				Severity: "error",
+				Summary:  "Error acquiring the state lock",
+				Detail:   "Another operation holds the lock.",
+				Code:     "state_locked",
+			},
+		},
 		"error with source code unavailable": {
 			&hcl.Diagnostic{
 				Severity: hcl.DiagError,
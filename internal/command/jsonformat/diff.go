@@ -59,8 +59,9 @@ func precomputeDiffs(plan Plan, mode plans.Mode) diffs {
 		schema := plan.getSchema(change)
 		structuredChange := structured.FromJsonChange(change.Change, attribute_path.AlwaysMatcher())
 		diffs.changes = append(diffs.changes, diff{
-			change: change,
-			diff:   differ.ComputeDiffForBlock(structuredChange, schema.Block),
+			change:       change,
+			diff:         differ.ComputeDiffForBlock(structuredChange, schema.Block),
+			provisioners: precomputeProvisionerDiffs(plan, change),
 		})
 	}
 
@@ -95,8 +96,36 @@ func (d diffs) Empty() bool {
 }
 
 type diff struct {
-	change jsonplan.ResourceChange
-	diff   computed.Diff
+	change       jsonplan.ResourceChange
+	diff         computed.Diff
+	provisioners []provisionerDiff
+}
+
+// provisionerDiff is a provisioner preview of a resource instance change,
+// rendered as the creation of the provisioner configuration.
+type provisionerDiff struct {
+	preview jsonplan.ProvisionerPreview
+	diff    computed.Diff
+}
+
+func precomputeProvisionerDiffs(plan Plan, change jsonplan.ResourceChange) []provisionerDiff {
+	if len(change.Deposed) != 0 {
+		// Provisioners only run for current objects.
+		return nil
+	}
+
+	var ret []provisionerDiff
+	for _, preview := range plan.ProvisionerPreviews {
+		if preview.Address != change.Address {
+			continue
+		}
+		structuredChange := structured.FromJsonChange(preview.Config, attribute_path.AlwaysMatcher())
+		ret = append(ret, provisionerDiff{
+			preview: preview,
+			diff:    differ.ComputeDiffForOutput(structuredChange),
+		})
+	}
+	return ret
 }
 
 func (d diff) Moved() bool {
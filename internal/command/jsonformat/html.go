@@ -0,0 +1,465 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/colorstring"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// HTMLOpts provides the information an HTML plan report needs beyond the plan
+// itself.
+type HTMLOpts struct {
+	// Dependencies maps the address of each resource in the configuration to
+	// the addresses of the resources it depends on, as returned by
+	// [jsonplan.ResourceDependencies]. It's used to draw the dependency
+	// graph, which is omitted if this is nil.
+	Dependencies map[string][]string
+}
+
+// RenderHTMLPlan writes the given plan as a self-contained HTML document,
+// suitable for attaching to a change ticket.
+//
+// The report includes the same diff for each resource instance that
+// "tofu show" would produce, controls for searching and filtering those
+// diffs, and a graph of the dependencies between the changed resources.
+// Sensitive values are masked unless the renderer's ShowSensitive is set.
+func (renderer Renderer) RenderHTMLPlan(plan Plan, mode plans.Mode, opts HTMLOpts, qualities ...plans.Quality) error {
+	report := plan.htmlReport(renderer, mode, opts, qualities...)
+	return htmlReportTemplate.Execute(renderer.Streams.Stdout.File, report)
+}
+
+type htmlReport struct {
+	Summary string
+	Errored bool
+	Masked  bool
+	Actions []htmlAction
+	Changes []htmlChange
+	Drift   []htmlChange
+	Outputs []htmlLine
+	Graph   *htmlGraph
+}
+
+type htmlAction struct {
+	Name  string
+	Label string
+	Count int
+}
+
+type htmlChange struct {
+	Address string
+
+	// Resource is the address of the resource in the configuration, which
+	// identifies the node for this change in the dependency graph.
+	Resource string
+	Action   string
+	Summary  string
+	Lines    []htmlLine
+}
+
+// htmlLine is a line of a rendered diff, with Class describing how the line
+// changes so that it can be highlighted.
+type htmlLine struct {
+	Class string
+	Text  string
+}
+
+type htmlGraph struct {
+	Width      int
+	Height     int
+	NodeWidth  int
+	NodeHeight int
+	Nodes      []htmlNode
+	Edges      []htmlEdge
+}
+
+type htmlNode struct {
+	Address string
+	Label   string
+	Action  string
+	X, Y    int
+}
+
+type htmlEdge struct {
+	X1, Y1, X2, Y2 int
+}
+
+// htmlActions lists the filterable actions in the order they're presented.
+// Replace actions share a single filter, as do the moves and imports that
+// don't otherwise change their resource instance.
+var htmlActions = []htmlAction{
+	{Name: "create", Label: "Create"},
+	{Name: "update", Label: "Update"},
+	{Name: "replace", Label: "Replace"},
+	{Name: "delete", Label: "Destroy"},
+	{Name: "read", Label: "Read"},
+	{Name: "forget", Label: "Forget"},
+	{Name: "import", Label: "Import"},
+	{Name: "move", Label: "Move"},
+}
+
+func (plan Plan) htmlReport(renderer Renderer, mode plans.Mode, opts HTMLOpts, qualities ...plans.Quality) htmlReport {
+	// The diffs are shown as preformatted text, with their own highlighting.
+	renderer.Colorize = &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	diffs := precomputeDiffs(plan, mode)
+	summary := summarizeChanges(diffs)
+
+	report := htmlReport{
+		Masked: !renderer.ShowSensitive,
+	}
+	for _, quality := range qualities {
+		if quality == plans.Errored {
+			report.Errored = true
+		}
+	}
+	if len(summary.changes) > 0 {
+		report.Summary = summary.String()
+	}
+
+	counts := make(map[string]int)
+	for _, change := range summary.changes {
+		if rendered, ok := htmlReportChange(renderer, change, proposedChange); ok {
+			report.Changes = append(report.Changes, rendered)
+			counts[rendered.Action]++
+		}
+	}
+	for _, action := range htmlActions {
+		if counts[action.Name] > 0 {
+			action.Count = counts[action.Name]
+			report.Actions = append(report.Actions, action)
+		}
+	}
+	for _, dr := range reportedDrift(diffs, mode) {
+		if rendered, ok := htmlReportChange(renderer, dr, detectedDrift); ok {
+			report.Drift = append(report.Drift, rendered)
+		}
+	}
+	if outputs := renderHumanDiffOutputs(renderer, diffs.outputs); len(outputs) > 0 {
+		report.Outputs = htmlDiffLines(outputs)
+	}
+	if opts.Dependencies != nil && len(report.Changes) > 0 {
+		report.Graph = htmlDependencyGraph(report.Changes, opts.Dependencies)
+	}
+	return report
+}
+
+func htmlReportChange(renderer Renderer, diff diff, cause string) (htmlChange, bool) {
+	rendered, ok := renderHumanDiff(renderer, diff, cause)
+	if !ok {
+		return htmlChange{}, false
+	}
+	action := jsonplan.UnmarshalActions(diff.change.Change.Actions)
+	summary, _, _ := strings.Cut(resourceChangeComment(diff.change, action, cause), "\n")
+	resource := diff.change.Address
+	if addr, diags := addrs.ParseAbsResourceInstanceStr(diff.change.Address); !diags.HasErrors() {
+		resource = addr.ConfigResource().String()
+	}
+	return htmlChange{
+		Address:  diff.change.Address,
+		Resource: resource,
+		Action:   htmlActionName(diff, action),
+		Summary:  strings.TrimPrefix(strings.TrimSpace(renderer.Colorize.Color(summary)), "# "),
+		Lines:    htmlDiffLines(rendered),
+	}, true
+}
+
+func htmlActionName(diff diff, action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	case plans.Delete:
+		return "delete"
+	case plans.Read:
+		return "read"
+	case plans.Forget:
+		return "forget"
+	}
+	if diff.Importing() {
+		return "import"
+	}
+	return "move"
+}
+
+func htmlDiffLines(rendered string) []htmlLine {
+	lines := strings.Split(rendered, "\n")
+	ret := make([]htmlLine, len(lines))
+	for i, line := range lines {
+		ret[i].Text = line
+		switch trimmed := strings.TrimLeft(line, " "); {
+		case strings.HasPrefix(trimmed, "-/+"), strings.HasPrefix(trimmed, "+/-"):
+			ret[i].Class = "replace"
+		case strings.HasPrefix(trimmed, "+"):
+			ret[i].Class = "add"
+		case strings.HasPrefix(trimmed, "-"):
+			ret[i].Class = "remove"
+		case strings.HasPrefix(trimmed, "~"):
+			ret[i].Class = "modify"
+		case strings.HasPrefix(trimmed, "#"):
+			ret[i].Class = "comment"
+		}
+	}
+	return ret
+}
+
+// Dimensions of the dependency graph, in pixels.
+const (
+	htmlNodeWidth  = 300
+	htmlNodeHeight = 28
+	htmlColumnGap  = 60
+	htmlRowGap     = 12
+	htmlGraphPad   = 10
+)
+
+// htmlDependencyGraph lays out the resources with changes in columns, so that
+// each resource is to the right of everything it depends on. Dependencies on
+// resources without any changes are followed through to the changed
+// resources beyond them, so indirect dependencies are still shown.
+func htmlDependencyGraph(changes []htmlChange, dependencies map[string][]string) *htmlGraph {
+	// Each node is a resource, which may have many changed instances.
+	actions := make(map[string]string)
+	var resources []string
+	for _, change := range changes {
+		key := change.Resource
+		if _, exists := actions[key]; !exists {
+			resources = append(resources, key)
+		}
+		if htmlActionRank(change.Action) > htmlActionRank(actions[key]) {
+			actions[key] = change.Action
+		}
+	}
+
+	changedDeps := make(map[string][]string)
+	for _, resource := range resources {
+		seen := map[string]bool{resource: true}
+		found := make(map[string]struct{})
+		pending := append([]string(nil), dependencies[resource]...)
+		for len(pending) > 0 {
+			dep := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if _, changed := actions[dep]; changed {
+				found[dep] = struct{}{}
+				continue
+			}
+			pending = append(pending, dependencies[dep]...)
+		}
+		changedDeps[resource] = sortedKeys(found)
+	}
+
+	columns := make(map[string]int)
+	var column func(resource string, visiting map[string]bool) int
+	column = func(resource string, visiting map[string]bool) int {
+		if col, ok := columns[resource]; ok {
+			return col
+		}
+		if visiting[resource] {
+			// Dependency cycles are invalid, but we shouldn't hang if we
+			// somehow encounter one.
+			return 0
+		}
+		visiting[resource] = true
+		col := 0
+		for _, dep := range changedDeps[resource] {
+			col = max(col, column(dep, visiting)+1)
+		}
+		columns[resource] = col
+		return col
+	}
+
+	sort.Strings(resources)
+	graph := &htmlGraph{NodeWidth: htmlNodeWidth, NodeHeight: htmlNodeHeight}
+	rows := make(map[int]int)
+	positions := make(map[string]htmlNode)
+	for _, resource := range resources {
+		col := column(resource, make(map[string]bool))
+		node := htmlNode{
+			Address: resource,
+			Label:   resource,
+			Action:  actions[resource],
+			X:       htmlGraphPad + col*(htmlNodeWidth+htmlColumnGap),
+			Y:       htmlGraphPad + rows[col]*(htmlNodeHeight+htmlRowGap),
+		}
+		if len(node.Label) > 40 {
+			node.Label = "…" + node.Label[len(node.Label)-39:]
+		}
+		rows[col]++
+		positions[resource] = node
+		graph.Nodes = append(graph.Nodes, node)
+		graph.Width = max(graph.Width, node.X+htmlNodeWidth+htmlGraphPad)
+		graph.Height = max(graph.Height, node.Y+htmlNodeHeight+htmlGraphPad)
+	}
+	for _, resource := range resources {
+		to := positions[resource]
+		for _, dep := range changedDeps[resource] {
+			from := positions[dep]
+			graph.Edges = append(graph.Edges, htmlEdge{
+				X1: from.X + htmlNodeWidth,
+				Y1: from.Y + htmlNodeHeight/2,
+				X2: to.X,
+				Y2: to.Y + htmlNodeHeight/2,
+			})
+		}
+	}
+	return graph
+}
+
+// htmlActionRank orders actions by how disruptive they are, so that a
+// resource with several changed instances is shown with the most disruptive.
+func htmlActionRank(action string) int {
+	switch action {
+	case "replace":
+		return 7
+	case "delete":
+		return 6
+	case "forget":
+		return 5
+	case "update":
+		return 4
+	case "create":
+		return 3
+	case "import":
+		return 2
+	case "read", "move":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	ret := make([]string, 0, len(set))
+	for key := range set {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>OpenTofu plan report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #1f2328; }
+h1 { margin-top: 0; }
+.notice { padding: 0.6em 1em; border-radius: 6px; background: #f6f8fa; margin-bottom: 1em; }
+.notice.error { background: #ffebe9; }
+.controls { display: flex; flex-wrap: wrap; gap: 1em; align-items: center; margin: 1em 0; }
+.controls input[type=search] { min-width: 20em; padding: 0.3em; }
+details.resource-change, details.drift { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.5em 0; }
+details.resource-change > summary, details.drift > summary { cursor: pointer; padding: 0.5em 1em; }
+details.resource-change > summary::before { display: inline-block; width: 5.5em; font-size: 0.8em; font-weight: bold; text-transform: uppercase; content: attr(data-label); }
+pre { margin: 0; padding: 0.5em 1em; overflow-x: auto; background: #f6f8fa; }
+.add, .create { color: #116329; }
+.remove, .delete, .forget { color: #a40e26; }
+.modify, .update { color: #7d4e00; }
+.replace { color: #8250df; }
+.comment { color: #59636e; }
+svg .node rect { fill: #fff; stroke: currentColor; stroke-width: 2; cursor: pointer; }
+svg .node text { fill: #1f2328; font-size: 12px; font-family: ui-monospace, monospace; cursor: pointer; }
+svg .edge { fill: none; stroke: #818b98; marker-end: url(#arrow); }
+</style>
+</head>
+<body>
+<h1>OpenTofu plan report</h1>
+{{if .Errored}}<p class="notice error"><strong>Planning failed.</strong> OpenTofu encountered an error while generating this plan.</p>
+{{end}}{{if .Masked}}<p class="notice">Sensitive values are masked in this report.</p>
+{{end}}{{if .Summary}}<p><strong>Plan:</strong> {{.Summary}}</p>
+{{else if not (or .Drift .Outputs)}}<p><strong>No changes.</strong> Your infrastructure matches the configuration.</p>
+{{end}}
+{{- if .Graph}}
+<h2>Dependency graph</h2>
+<p>Each resource with changes appears to the right of the changed resources it depends on. Select a resource to show only its changes, and select it again to show all changes.</p>
+<svg id="graph" width="{{.Graph.Width}}" height="{{.Graph.Height}}" viewBox="0 0 {{.Graph.Width}} {{.Graph.Height}}">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="#818b98"/></marker></defs>
+{{range .Graph.Edges}}<path class="edge" d="M{{.X1}},{{.Y1}} C{{.X2}},{{.Y1}} {{.X1}},{{.Y2}} {{.X2}},{{.Y2}}"/>
+{{end}}{{range .Graph.Nodes}}<g class="node {{.Action}}" data-resource="{{.Address}}"><title>{{.Address}}</title><rect x="{{.X}}" y="{{.Y}}" width="{{$.Graph.NodeWidth}}" height="{{$.Graph.NodeHeight}}" rx="4"/><text x="{{.X}}" y="{{.Y}}" dx="8" dy="18">{{.Label}}</text></g>
+{{end}}</svg>
+{{- end}}
+{{- if .Changes}}
+<h2>Resource changes</h2>
+<div class="controls">
+<input type="search" id="search" placeholder="Search addresses and diffs" aria-label="Search">
+{{range .Actions}}<label><input type="checkbox" name="action" value="{{.Name}}" checked> {{.Label}} ({{.Count}})</label>
+{{end}}<button type="button" id="expand">Expand all</button>
+<button type="button" id="collapse">Collapse all</button>
+</div>
+<div id="changes">
+{{range .Changes}}<details class="resource-change" data-action="{{.Action}}" data-resource="{{.Resource}}"><summary class="{{.Action}}" data-label="{{.Action}}">{{.Summary}}</summary>
+<pre>{{range .Lines}}<span class="{{.Class}}">{{.Text}}</span>
+{{end}}</pre></details>
+{{end}}</div>
+{{- end}}
+{{- if .Drift}}
+<h2>Changes outside of OpenTofu</h2>
+{{range .Drift}}<details class="drift"><summary class="{{.Action}}">{{.Summary}}</summary>
+<pre>{{range .Lines}}<span class="{{.Class}}">{{.Text}}</span>
+{{end}}</pre></details>
+{{end}}
+{{- end}}
+{{- if .Outputs}}
+<h2>Changes to outputs</h2>
+<pre>{{range .Outputs}}<span class="{{.Class}}">{{.Text}}</span>
+{{end}}</pre>
+{{- end}}
+<script>
+(function () {
+  var search = document.getElementById("search");
+  if (!search) {
+    return;
+  }
+  var boxes = document.querySelectorAll("input[name=action]");
+  var changes = document.querySelectorAll("details.resource-change");
+  var selected = "";
+  function filter() {
+    var query = search.value.toLowerCase();
+    var enabled = {};
+    boxes.forEach(function (box) { enabled[box.value] = box.checked; });
+    changes.forEach(function (change) {
+      var matches = query === "" || change.textContent.toLowerCase().indexOf(query) !== -1;
+      var inSelection = selected === "" || change.dataset.resource === selected;
+      change.hidden = !(enabled[change.dataset.action] && matches && inSelection);
+    });
+  }
+  search.addEventListener("input", filter);
+  boxes.forEach(function (box) { box.addEventListener("change", filter); });
+  document.getElementById("expand").addEventListener("click", function () {
+    changes.forEach(function (change) { if (!change.hidden) { change.open = true; } });
+  });
+  document.getElementById("collapse").addEventListener("click", function () {
+    changes.forEach(function (change) { change.open = false; });
+  });
+  document.querySelectorAll("#graph .node").forEach(function (node) {
+    node.addEventListener("click", function () {
+      selected = selected === node.dataset.resource ? "" : node.dataset.resource;
+      filter();
+      if (selected !== "") {
+        search.scrollIntoView();
+      }
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+func TestRenderHTMLPlan(t *testing.T) {
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"secret": {
+								AttributeType: marshalJson(t, "string"),
+								Sensitive:     true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	resource := func(name string, actions []string, before, after map[string]interface{}) jsonplan.ResourceChange {
+		return jsonplan.ResourceChange{
+			Address:      "test_resource." + name,
+			Mode:         "managed",
+			Type:         "test_resource",
+			Name:         name,
+			ProviderName: "test",
+			Change: jsonplan.Change{
+				Actions:         actions,
+				Before:          marshalJson(t, before),
+				After:           marshalJson(t, after),
+				BeforeSensitive: marshalJson(t, map[string]interface{}{"secret": true}),
+				AfterSensitive:  marshalJson(t, map[string]interface{}{"secret": true}),
+			},
+		}
+	}
+	plan := Plan{
+		ResourceChanges: []jsonplan.ResourceChange{
+			resource("network", []string{"create"}, nil, map[string]interface{}{
+				"id":     "<script>alert(1)</script>",
+				"secret": "hunter2",
+			}),
+			resource("app", []string{"delete", "create"}, map[string]interface{}{
+				"id":     "old",
+				"secret": "hunter2",
+			}, map[string]interface{}{
+				"id":     "new",
+				"secret": "hunter2",
+			}),
+		},
+		ProviderSchemas: schemas,
+	}
+	opts := HTMLOpts{
+		Dependencies: map[string][]string{
+			"test_resource.app":     {"test_resource.unchanged"},
+			"test_resource.network": {},
+			// Dependencies on resources without changes are followed through
+			// to the changed resources beyond them.
+			"test_resource.unchanged": {"test_resource.network"},
+		},
+	}
+
+	report := plan.htmlReport(Renderer{}, plans.NormalMode, opts)
+	if got, want := report.Summary, "2 to add, 0 to change, 1 to destroy."; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	var actions []string
+	for _, action := range report.Actions {
+		actions = append(actions, action.Name)
+	}
+	if diff := cmp.Diff([]string{"create", "replace"}, actions); diff != "" {
+		t.Errorf("wrong filterable actions\n%s", diff)
+	}
+	if report.Graph == nil {
+		t.Fatal("report has no dependency graph")
+	}
+	var nodes []string
+	for _, node := range report.Graph.Nodes {
+		nodes = append(nodes, node.Address)
+	}
+	if diff := cmp.Diff([]string{"test_resource.app", "test_resource.network"}, nodes); diff != "" {
+		t.Errorf("wrong graph nodes\n%s", diff)
+	}
+	// The app depends on the network, so it's in the following column.
+	app, network := report.Graph.Nodes[0], report.Graph.Nodes[1]
+	if app.X <= network.X {
+		t.Errorf("dependent resource at x=%d is not to the right of its dependency at x=%d", app.X, network.X)
+	}
+	if diff := cmp.Diff([]htmlEdge{{
+		X1: network.X + htmlNodeWidth,
+		Y1: network.Y + htmlNodeHeight/2,
+		X2: app.X,
+		Y2: app.Y + htmlNodeHeight/2,
+	}}, report.Graph.Edges); diff != "" {
+		t.Errorf("wrong graph edges\n%s", diff)
+	}
+
+	streams, done := terminal.StreamsForTesting(t)
+	if err := (Renderer{Streams: streams}).RenderHTMLPlan(plan, plans.NormalMode, opts); err != nil {
+		t.Fatal(err)
+	}
+	got := done(t).Stdout()
+	for _, want := range []string{
+		"<title>OpenTofu plan report</title>",
+		"Sensitive values are masked in this report.",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<details class="resource-change" data-action="replace" data-resource="test_resource.app">`,
+		"(sensitive value)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report does not contain %q\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"hunter2", "<script>alert(1)"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("report contains %q", unwanted)
+		}
+	}
+}
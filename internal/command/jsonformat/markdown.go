@@ -0,0 +1,296 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/mitchellh/colorstring"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// MarkdownOpts controls how much of a plan is included when rendering it as
+// Markdown. The zero value applies no limits.
+type MarkdownOpts struct {
+	// MaxDiffLines is the maximum number of lines of diff to include for
+	// each resource instance before the rest is elided.
+	MaxDiffLines int
+
+	// MaxLength is the maximum length of the whole document, in bytes. Once
+	// the limit would be exceeded the remaining resource changes are omitted
+	// and replaced with a note saying how many are missing.
+	MaxLength int
+}
+
+// markdownActions lists the actions in the order they appear in the summary
+// table, along with the label used for each.
+var markdownActions = []struct {
+	action plans.Action
+	label  string
+}{
+	{plans.Create, "Create"},
+	{plans.Update, "Update in-place"},
+	{plans.DeleteThenCreate, "Replace (destroy then create)"},
+	{plans.CreateThenDelete, "Replace (create then destroy)"},
+	{plans.Delete, "Destroy"},
+	{plans.Read, "Read"},
+	{plans.Forget, "Forget"},
+}
+
+// changeSummary describes the resource instance changes included in a plan
+// document, selected using the same rules as the human-oriented renderer.
+type changeSummary struct {
+	changes   []diff
+	counts    map[plans.Action]int
+	importing int
+	moving    int
+}
+
+func summarizeChanges(diffs diffs) changeSummary {
+	ret := changeSummary{counts: make(map[plans.Action]int)}
+	for _, diff := range diffs.changes {
+		action := jsonplan.UnmarshalActions(diff.change.Change.Actions)
+		if action == plans.NoOp && !diff.Moved() && !diff.Importing() {
+			continue
+		}
+		if action == plans.Delete && diff.change.Mode != jsonstate.ManagedResourceMode {
+			continue
+		}
+		ret.changes = append(ret.changes, diff)
+		if diff.Importing() {
+			ret.importing++
+		}
+		if diff.Moved() {
+			ret.moving++
+		}
+		if action != plans.NoOp {
+			ret.counts[action]++
+		}
+	}
+	return ret
+}
+
+// String returns the same summary as the "Plan:" line of the human-oriented
+// output, without the prefix.
+func (s changeSummary) String() string {
+	var buf strings.Builder
+	if s.importing > 0 {
+		fmt.Fprintf(&buf, "%d to import, ", s.importing)
+	}
+	fmt.Fprintf(&buf, "%d to add, %d to change, %d to destroy",
+		s.counts[plans.Create]+s.counts[plans.DeleteThenCreate]+s.counts[plans.CreateThenDelete],
+		s.counts[plans.Update],
+		s.counts[plans.Delete]+s.counts[plans.DeleteThenCreate]+s.counts[plans.CreateThenDelete])
+	if s.counts[plans.Forget] > 0 {
+		fmt.Fprintf(&buf, ", %d to forget", s.counts[plans.Forget])
+	}
+	buf.WriteString(".")
+	return buf.String()
+}
+
+// reportedDrift returns the changes made outside of OpenTofu that a plan
+// document should include. As in the human-oriented output, move-only drift
+// is only reported for refresh-only plans.
+func reportedDrift(diffs diffs, mode plans.Mode) []diff {
+	var ret []diff
+	for _, dr := range diffs.drift {
+		if mode == plans.RefreshOnlyMode || dr.diff.Action != plans.NoOp {
+			ret = append(ret, dr)
+		}
+	}
+	return ret
+}
+
+// RenderMarkdownPlan writes the given plan as a Markdown document, intended
+// to be posted as a comment on a pull request.
+//
+// Each resource instance change is rendered as a collapsible section
+// containing the same diff that "tofu show" would produce, without any
+// terminal formatting.
+func (renderer Renderer) RenderMarkdownPlan(plan Plan, mode plans.Mode, opts MarkdownOpts, qualities ...plans.Quality) {
+	renderer.Streams.Print(plan.renderMarkdown(renderer, mode, opts, qualities...))
+}
+
+func (plan Plan) renderMarkdown(renderer Renderer, mode plans.Mode, opts MarkdownOpts, qualities ...plans.Quality) string {
+	errored := false
+	for _, quality := range qualities {
+		if quality == plans.Errored {
+			errored = true
+		}
+	}
+
+	// The diffs are embedded in code blocks, where terminal escape sequences
+	// would just be noise.
+	renderer.Colorize = &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	diffs := precomputeDiffs(plan, mode)
+	summary := summarizeChanges(diffs)
+	changes, counts := summary.changes, summary.counts
+	drift := reportedDrift(diffs, mode)
+
+	outputs := renderHumanDiffOutputs(renderer, diffs.outputs)
+
+	var buf strings.Builder
+	buf.WriteString("## OpenTofu plan\n\n")
+
+	switch {
+	case errored:
+		buf.WriteString("> [!CAUTION]\n> **Planning failed.** OpenTofu encountered an error while generating this plan.\n\n")
+	case len(changes) == 0 && len(outputs) == 0 && (len(drift) == 0 || mode != plans.RefreshOnlyMode):
+		buf.WriteString("**No changes.** Your infrastructure matches the configuration.\n")
+		return buf.String()
+	}
+
+	if len(changes) > 0 {
+		fmt.Fprintf(&buf, "**Plan:** %s\n\n", summary)
+
+		buf.WriteString("| Action | Resources |\n| --- | ---: |\n")
+		for _, row := range markdownActions {
+			if counts[row.action] > 0 {
+				fmt.Fprintf(&buf, "| %s | %d |\n", row.label, counts[row.action])
+			}
+		}
+		if summary.importing > 0 {
+			fmt.Fprintf(&buf, "| Import | %d |\n", summary.importing)
+		}
+		if summary.moving > 0 {
+			fmt.Fprintf(&buf, "| Move | %d |\n", summary.moving)
+		}
+		buf.WriteString("\n")
+	}
+
+	// The summary is always included, but the sections that follow are only
+	// included while they fit within MaxLength, leaving enough room for the
+	// note about anything omitted.
+	var sections []markdownSection
+	if len(drift) > 0 {
+		sections = append(sections, markdownSection{text: "### Changes outside of OpenTofu\n\n", heading: true})
+		for _, dr := range drift {
+			if rendered, ok := renderMarkdownDiff(renderer, dr, detectedDrift, opts); ok {
+				sections = append(sections, markdownSection{text: rendered})
+			}
+		}
+	}
+	if len(changes) > 0 {
+		sections = append(sections, markdownSection{text: "### Resource changes\n\n", heading: true})
+		for _, change := range changes {
+			if rendered, ok := renderMarkdownDiff(renderer, change, proposedChange, opts); ok {
+				sections = append(sections, markdownSection{text: rendered})
+			}
+		}
+	}
+	if len(outputs) > 0 {
+		sections = append(sections,
+			markdownSection{text: "### Changes to outputs\n\n", heading: true},
+			markdownSection{text: markdownCodeBlock(markdownDiffLines(outputs, opts.MaxDiffLines))},
+		)
+	}
+
+	for i, section := range sections {
+		if opts.MaxLength > 0 {
+			omitted := 0
+			for _, rest := range sections[i:] {
+				if !rest.heading {
+					omitted++
+				}
+			}
+			note := markdownOmittedNote(omitted)
+			if buf.Len()+len(section.text)+len(note) > opts.MaxLength {
+				buf.WriteString(note)
+				break
+			}
+		}
+		buf.WriteString(section.text)
+	}
+	return buf.String()
+}
+
+type markdownSection struct {
+	text    string
+	heading bool
+}
+
+// renderMarkdownDiff renders a single resource instance change as a
+// collapsible section, returning false if the change has nothing to show.
+func renderMarkdownDiff(renderer Renderer, diff diff, cause string, opts MarkdownOpts) (string, bool) {
+	rendered, ok := renderHumanDiff(renderer, diff, cause)
+	if !ok {
+		return "", false
+	}
+
+	action := jsonplan.UnmarshalActions(diff.change.Change.Actions)
+	summary, _, _ := strings.Cut(resourceChangeComment(diff.change, action, cause), "\n")
+	summary = strings.TrimPrefix(strings.TrimSpace(renderer.Colorize.Color(summary)), "# ")
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "<details><summary>%s</summary>\n\n", html.EscapeString(summary))
+	buf.WriteString(markdownCodeBlock(markdownDiffLines(rendered, opts.MaxDiffLines)))
+	buf.WriteString("\n</details>\n\n")
+	return buf.String(), true
+}
+
+// markdownDiffLines converts the human-oriented diff into a form that Markdown
+// renderers will highlight as a diff, by moving each change symbol to the
+// start of its line, and applies the line limit.
+func markdownDiffLines(rendered string, maxLines int) string {
+	lines := strings.Split(rendered, "\n")
+	omitted := 0
+	if maxLines > 0 && len(lines) > maxLines {
+		omitted = len(lines) - maxLines
+		lines = lines[:maxLines]
+	}
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 || indent == len(line) {
+			continue
+		}
+		symbol := line[indent]
+		switch symbol {
+		case '+', '-':
+		case '~':
+			// Diff highlighters don't recognize "~", but many of them show
+			// lines starting with "!" as modified.
+			symbol = '!'
+		default:
+			continue
+		}
+		lines[i] = string(symbol) + line[1:indent] + " " + line[indent+1:]
+	}
+	if omitted > 0 {
+		lines = append(lines, fmt.Sprintf("# ... %d more lines not shown", omitted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownCodeBlock wraps the given text in a fenced "diff" code block, using
+// a fence long enough that no sequence of backticks within the text can end
+// the block early.
+func markdownCodeBlock(text string) string {
+	longest, current := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + "diff\n" + text + "\n" + fence + "\n"
+}
+
+func markdownOmittedNote(omitted int) string {
+	what := "changes were"
+	if omitted == 1 {
+		what = "change was"
+	}
+	return fmt.Sprintf("_%d more %s omitted to stay within the length limit. Run `tofu show` with the saved plan to see the full plan._\n", omitted, what)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"value": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	plan := Plan{
+		ResourceChanges: []jsonplan.ResourceChange{
+			{
+				Address:      `test_resource.created["a"]`,
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "created",
+				Index:        marshalJson(t, "a"),
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"create"},
+					Before:  marshalJson(t, nil),
+					After: marshalJson(t, map[string]interface{}{
+						"id":    "new",
+						"value": "Hello, ```World```!",
+					}),
+				},
+			},
+			{
+				Address:      "test_resource.updated",
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "updated",
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"update"},
+					Before: marshalJson(t, map[string]interface{}{
+						"id":    "existing",
+						"value": "before",
+					}),
+					After: marshalJson(t, map[string]interface{}{
+						"id":    "existing",
+						"value": "after",
+					}),
+				},
+			},
+			{
+				Address:      "test_resource.deleted",
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "deleted",
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"delete"},
+					Before: marshalJson(t, map[string]interface{}{
+						"id":    "old",
+						"value": "goodbye",
+					}),
+					After: marshalJson(t, nil),
+				},
+			},
+		},
+		OutputChanges: map[string]jsonplan.Change{
+			"greeting": {
+				Actions: []string{"create"},
+				Before:  marshalJson(t, nil),
+				After:   marshalJson(t, "hello"),
+			},
+		},
+		ProviderSchemas: schemas,
+	}
+
+	tcs := map[string]struct {
+		opts MarkdownOpts
+		want string
+	}{
+		"unlimited": {
+			opts: MarkdownOpts{},
+			want: `## OpenTofu plan
+
+**Plan:** 1 to add, 1 to change, 1 to destroy.
+
+| Action | Resources |
+| --- | ---: |
+| Create | 1 |
+| Update in-place | 1 |
+| Destroy | 1 |
+
+### Resource changes
+
+<details><summary>test_resource.created[&#34;a&#34;] will be created</summary>
+
+` + "````" + `diff
+  # test_resource.created["a"] will be created
++   resource "test_resource" "created" {
++       id    = "new"
++       value = "Hello, ` + "```World```" + `!"
+    }
+` + "````" + `
+
+</details>
+
+<details><summary>test_resource.updated will be updated in-place</summary>
+
+` + "```" + `diff
+  # test_resource.updated will be updated in-place
+!   resource "test_resource" "updated" {
+        id    = "existing"
+!       value = "before" -> "after"
+    }
+` + "```" + `
+
+</details>
+
+<details><summary>test_resource.deleted will be destroyed</summary>
+
+` + "```" + `diff
+  # test_resource.deleted will be destroyed
+-   resource "test_resource" "deleted" {
+-       id    = "old" -> null
+-       value = "goodbye" -> null
+    }
+` + "```" + `
+
+</details>
+
+### Changes to outputs
+
+` + "```" + `diff
++   greeting = "hello"
+` + "```" + `
+`,
+		},
+		"truncated": {
+			opts: MarkdownOpts{MaxDiffLines: 3, MaxLength: 900},
+			want: `## OpenTofu plan
+
+**Plan:** 1 to add, 1 to change, 1 to destroy.
+
+| Action | Resources |
+| --- | ---: |
+| Create | 1 |
+| Update in-place | 1 |
+| Destroy | 1 |
+
+### Resource changes
+
+<details><summary>test_resource.created[&#34;a&#34;] will be created</summary>
+
+` + "```" + `diff
+  # test_resource.created["a"] will be created
++   resource "test_resource" "created" {
++       id    = "new"
+# ... 2 more lines not shown
+` + "```" + `
+
+</details>
+
+<details><summary>test_resource.updated will be updated in-place</summary>
+
+` + "```" + `diff
+  # test_resource.updated will be updated in-place
+!   resource "test_resource" "updated" {
+        id    = "existing"
+# ... 2 more lines not shown
+` + "```" + `
+
+</details>
+
+_2 more changes were omitted to stay within the length limit. Run ` + "`tofu show`" + ` with the saved plan to see the full plan._
+`,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got := plan.renderMarkdown(Renderer{}, plans.NormalMode, tc.opts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected output\ngot:\n%s\ndiff:\n%s", got, diff)
+			}
+			if tc.opts.MaxLength > 0 && len(got) > tc.opts.MaxLength {
+				t.Errorf("output is %d bytes, exceeding the limit of %d", len(got), tc.opts.MaxLength)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_noChanges(t *testing.T) {
+	got := Plan{}.renderMarkdown(Renderer{}, plans.NormalMode, MarkdownOpts{})
+	want := "## OpenTofu plan\n\n**No changes.** Your infrastructure matches the configuration.\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected output\n%s", diff)
+	}
+}
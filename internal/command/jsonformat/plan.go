@@ -32,6 +32,9 @@ type Plan struct {
 	ResourceChanges    []jsonplan.ResourceChange  `json:"resource_changes"`
 	ResourceDrift      []jsonplan.ResourceChange  `json:"resource_drift"`
 	RelevantAttributes []jsonplan.ResourceAttr    `json:"relevant_attributes"`
+	CostEstimate       *jsonplan.CostEstimate     `json:"cost_estimate,omitempty"`
+
+	ProvisionerPreviews []jsonplan.ProvisionerPreview `json:"provisioner_previews,omitempty"`
 
 	ProviderFormatVersion string                            `json:"provider_format_version"`
 	ProviderSchemas       map[string]*jsonprovider.Provider `json:"provider_schemas"`
@@ -275,6 +278,33 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 				renderer.Streams.Stdout.Columns()))
 		}
 	}
+
+	if plan.CostEstimate != nil {
+		renderHumanCostEstimate(renderer, plan.CostEstimate)
+	}
+}
+
+func renderHumanCostEstimate(renderer Renderer, estimate *jsonplan.CostEstimate) {
+	if len(estimate.Resources) > 0 {
+		renderer.Streams.Print(renderer.Colorize.Color("\n[bold]Cost estimate:[reset]\n"))
+		for _, resource := range estimate.Resources {
+			if resource.PastMonthlyCost != "" {
+				renderer.Streams.Printf("  %s: %s %s/month (previously %s %s/month)\n", resource.Address, resource.MonthlyCost, estimate.Currency, resource.PastMonthlyCost, estimate.Currency)
+			} else {
+				renderer.Streams.Printf("  %s: %s %s/month\n", resource.Address, resource.MonthlyCost, estimate.Currency)
+			}
+		}
+	}
+
+	if estimate.PastTotalMonthlyCost != "" {
+		renderer.Streams.Printf(
+			renderer.Colorize.Color("\n[bold]Estimated monthly cost:[reset] %s %s (previously %s %s)\n"),
+			estimate.TotalMonthlyCost, estimate.Currency, estimate.PastTotalMonthlyCost, estimate.Currency)
+	} else {
+		renderer.Streams.Printf(
+			renderer.Colorize.Color("\n[bold]Estimated monthly cost:[reset] %s %s\n"),
+			estimate.TotalMonthlyCost, estimate.Currency)
+	}
 }
 
 func renderHumanDiffOutputs(renderer Renderer, outputs map[string]computed.Diff) string {
@@ -387,6 +417,22 @@ func renderHumanDiff(renderer Renderer, diff diff, cause string) (string, bool)
 	opts.ShowUnchangedChildren = diff.Importing()
 
 	buf.WriteString(fmt.Sprintf("%s %s %s", renderer.Colorize.Color(renderers.DiffActionSymbol(action)), resourceChangeHeader(diff.change), diff.diff.RenderHuman(0, opts)))
+
+	for _, provisioner := range diff.provisioners {
+		buf.WriteString("\n\n")
+		if hook := provisioner.preview.Hook; hook != "" {
+			buf.WriteString(renderer.Colorize.Color(fmt.Sprintf("[bold]    # hook %q[reset] will run %s\n", hook, resourceHookTiming(hook, diff.change.Address))))
+			buf.WriteString(fmt.Sprintf("    hook %q %s", hook, provisioner.diff.RenderHuman(0, computed.NewRenderHumanOpts(renderer.Colorize, renderer.ShowSensitive))))
+			continue
+		}
+		switch provisioner.preview.When {
+		case "destroy":
+			buf.WriteString(renderer.Colorize.Color(fmt.Sprintf("[bold]    # provisioner %q[reset] will run before %s is destroyed\n", provisioner.preview.Type, diff.change.Address)))
+		default:
+			buf.WriteString(renderer.Colorize.Color(fmt.Sprintf("[bold]    # provisioner %q[reset] will run after %s is created\n", provisioner.preview.Type, diff.change.Address)))
+		}
+		buf.WriteString(fmt.Sprintf("    provisioner %q %s", provisioner.preview.Type, provisioner.diff.RenderHuman(0, computed.NewRenderHumanOpts(renderer.Colorize, renderer.ShowSensitive))))
+	}
 	return buf.String(), true
 }
 
@@ -413,6 +459,8 @@ func resourceChangeComment(resource jsonplan.ResourceChange, action plans.Action
 			buf.WriteString("\n  # (depends on a resource or a module with changes pending)")
 		case jsonplan.ResourceInstanceReadBecauseCheckNested:
 			buf.WriteString("\n  # (config will be reloaded to verify a check block)")
+		case jsonplan.ResourceInstanceReadBecauseDeferredByConfig:
+			buf.WriteString("\n  # (defer_read_until_apply is set)")
 		}
 	case plans.Update:
 		switch changeCause {
@@ -545,6 +593,19 @@ func resourceChangeComment(resource jsonplan.ResourceChange, action plans.Action
 	return buf.String()
 }
 
+// resourceHookTiming describes when a lifecycle hook for the given event runs
+// relative to the change to the resource instance at the given address.
+func resourceHookTiming(event, addr string) string {
+	switch event {
+	case "post_update":
+		return fmt.Sprintf("after %s is updated", addr)
+	case "pre_destroy":
+		return fmt.Sprintf("before %s is destroyed", addr)
+	default:
+		return fmt.Sprintf("after %s is created", addr)
+	}
+}
+
 func resourceChangeHeader(change jsonplan.ResourceChange) string {
 	mode := "resource"
 	if change.Mode != jsonstate.ManagedResourceMode {
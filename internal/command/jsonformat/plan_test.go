@@ -82,6 +82,410 @@ found no differences, so no changes are needed.
 	}
 }
 
+func TestRenderHuman_CostEstimate(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+	streams, done := terminal.StreamsForTesting(t)
+
+	outputVal, _ := json.Marshal("some-text")
+	plan := Plan{
+		OutputChanges: map[string]jsonplan.Change{
+			"a_string": {
+				Actions: []string{"create"},
+				After:   outputVal,
+			},
+		},
+		CostEstimate: &jsonplan.CostEstimate{
+			Currency:             "USD",
+			TotalMonthlyCost:     "12.50",
+			PastTotalMonthlyCost: "10",
+			Resources: []jsonplan.ResourceCost{
+				{Address: "test_instance.a", MonthlyCost: "2.50"},
+				{Address: "test_instance.b", MonthlyCost: "10", PastMonthlyCost: "10"},
+			},
+		},
+	}
+
+	renderer := Renderer{Colorize: color, Streams: streams}
+	plan.renderHuman(renderer, plans.NormalMode)
+
+	want := `
+Changes to Outputs:
+  + a_string = "some-text"
+
+You can apply this plan to save these new output values to the OpenTofu
+state, without changing any real infrastructure.
+
+Cost estimate:
+  test_instance.a: 2.50 USD/month
+  test_instance.b: 10 USD/month (previously 10 USD/month)
+
+Estimated monthly cost: 12.50 USD (previously 10 USD)
+`
+
+	got := done(t).Stdout()
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+	}
+}
+
+func TestRenderHuman_ProvisionerPreviews(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+	streams, done := terminal.StreamsForTesting(t)
+
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	previews, err := jsonplan.MarshalProvisionerPreviews(&plans.Changes{
+		Resources: []*plans.ResourceInstanceChangeSrc{
+			{
+				Addr: addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_resource",
+					Name: "resource",
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				Provisioners: []plans.ProvisionerPreview{
+					{
+						Type:    "local-exec",
+						Destroy: true,
+						Config: cty.ObjectVal(map[string]cty.Value{
+							"command":     cty.StringVal("deregister 1234"),
+							"environment": cty.NullVal(cty.Map(cty.String)),
+						}),
+						Connection: cty.NullVal(cty.EmptyObject),
+					},
+					{
+						Type: "remote-exec",
+						Config: cty.ObjectVal(map[string]cty.Value{
+							"inline": cty.ListVal([]cty.Value{
+								cty.StringVal("rm -rf /var/cache/app"),
+								cty.StringVal("register").Mark(marks.Sensitive),
+							}),
+						}),
+						Connection: cty.ObjectVal(map[string]cty.Value{
+							"host": cty.UnknownVal(cty.String),
+							"user": cty.StringVal("admin"),
+							"port": cty.NullVal(cty.Number),
+						}),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := Plan{
+		ResourceChanges: []jsonplan.ResourceChange{
+			{
+				Address:      "test_resource.resource",
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "resource",
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"delete", "create"},
+					Before: marshalJson(t, map[string]interface{}{
+						"id": "1234",
+					}),
+					After:        marshalJson(t, map[string]interface{}{}),
+					AfterUnknown: marshalJson(t, map[string]interface{}{"id": true}),
+				},
+			},
+		},
+		ProviderSchemas:     schemas,
+		ProvisionerPreviews: previews,
+	}
+
+	renderer := Renderer{Colorize: color, Streams: streams}
+	plan.renderHuman(renderer, plans.NormalMode)
+
+	want := `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+-/+ destroy and then create replacement
+
+OpenTofu will perform the following actions:
+
+  # test_resource.resource must be replaced
+-/+ resource "test_resource" "resource" {
+      ~ id = "1234" -> (known after apply)
+    }
+
+    # provisioner "local-exec" will run before test_resource.resource is destroyed
+    provisioner "local-exec" {
+      + command = "deregister 1234"
+    }
+
+    # provisioner "remote-exec" will run after test_resource.resource is created
+    provisioner "remote-exec" {
+      + connection = {
+          + host = (known after apply)
+          + user = "admin"
+        }
+      + inline     = [
+          + "rm -rf /var/cache/app",
+          + (sensitive value),
+        ]
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+`
+
+	got := done(t).Stdout()
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+	}
+}
+
+func TestRenderHuman_ResourceHookPreviews(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+	streams, done := terminal.StreamsForTesting(t)
+
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"value": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	previews, err := jsonplan.MarshalProvisionerPreviews(&plans.Changes{
+		Resources: []*plans.ResourceInstanceChangeSrc{
+			{
+				Addr: addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_resource",
+					Name: "resource",
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				Provisioners: []plans.ProvisionerPreview{
+					{
+						Type: "local-exec",
+						Hook: "post_update",
+						Config: cty.ObjectVal(map[string]cty.Value{
+							"command":     cty.StringVal("./flush-cache.sh 1234"),
+							"environment": cty.NullVal(cty.Map(cty.String)),
+						}),
+						Connection: cty.NullVal(cty.EmptyObject),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := Plan{
+		ResourceChanges: []jsonplan.ResourceChange{
+			{
+				Address:      "test_resource.resource",
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "resource",
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"update"},
+					Before: marshalJson(t, map[string]interface{}{
+						"id":    "1234",
+						"value": "old",
+					}),
+					After: marshalJson(t, map[string]interface{}{
+						"id":    "1234",
+						"value": "new",
+					}),
+				},
+			},
+		},
+		ProviderSchemas:     schemas,
+		ProvisionerPreviews: previews,
+	}
+
+	renderer := Renderer{Colorize: color, Streams: streams}
+	plan.renderHuman(renderer, plans.NormalMode)
+
+	want := `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  ~ update in-place
+
+OpenTofu will perform the following actions:
+
+  # test_resource.resource will be updated in-place
+  ~ resource "test_resource" "resource" {
+        id    = "1234"
+      ~ value = "old" -> "new"
+    }
+
+    # hook "post_update" will run after test_resource.resource is updated
+    hook "post_update" {
+      + command = "./flush-cache.sh 1234"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`
+
+	got := done(t).Stdout()
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+	}
+}
+
+func TestRenderHuman_MaskedPaths(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"user_data": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"tags": {
+								AttributeType: marshalJson(t, []interface{}{"map", "string"}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	plan := Plan{
+		ResourceChanges: []jsonplan.ResourceChange{
+			{
+				Address:      "test_resource.resource",
+				Mode:         "managed",
+				Type:         "test_resource",
+				Name:         "resource",
+				ProviderName: "test",
+				Change: jsonplan.Change{
+					Actions: []string{"update"},
+					Before: marshalJson(t, map[string]interface{}{
+						"id":        "1234",
+						"user_data": "#cloud-config\npassword: old\n",
+						"tags": map[string]interface{}{
+							"name":   "web",
+							"secret": "old",
+						},
+					}),
+					After: marshalJson(t, map[string]interface{}{
+						"id":        "1234",
+						"user_data": "#cloud-config\npassword: new\n",
+						"tags": map[string]interface{}{
+							"name":   "web",
+							"secret": "new",
+						},
+					}),
+					AfterUnknown:    marshalJson(t, map[string]interface{}{}),
+					BeforeSensitive: marshalJson(t, false),
+					AfterSensitive:  marshalJson(t, map[string]interface{}{}),
+					MaskedPaths: marshalJson(t, [][]interface{}{
+						{"user_data"},
+						{"tags", "secret"},
+					}),
+				},
+			},
+		},
+		ProviderSchemas: schemas,
+	}
+
+	tcs := map[string]struct {
+		showSensitive bool
+		want          string
+	}{
+		"masked": {
+			want: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  ~ update in-place
+
+OpenTofu will perform the following actions:
+
+  # test_resource.resource will be updated in-place
+  ~ resource "test_resource" "resource" {
+        id        = "1234"
+      ~ tags      = {
+            "name"   = "web"
+          ~ "secret" = (sensitive value)
+        }
+      ~ user_data = (sensitive value)
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+		"shown": {
+			showSensitive: true,
+			want: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  ~ update in-place
+
+OpenTofu will perform the following actions:
+
+  # test_resource.resource will be updated in-place
+  ~ resource "test_resource" "resource" {
+        id        = "1234"
+      ~ tags      = {
+            "name"   = "web"
+          ~ "secret" = "old" -> "new"
+        }
+      ~ user_data = <<-EOT
+            #cloud-config
+          - password: old
+          + password: new
+        EOT
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			renderer := Renderer{Colorize: color, Streams: streams, ShowSensitive: tc.showSensitive}
+			plan.renderHuman(renderer, plans.NormalMode)
+
+			got := done(t).Stdout()
+			if diff := cmp.Diff(tc.want, got); len(diff) > 0 {
+				t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, tc.want, diff)
+			}
+		})
+	}
+}
+
 func TestRenderHuman_Imports(t *testing.T) {
 	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
 
@@ -100,8 +100,8 @@ func FromJsonChange(change jsonplan.Change, relevantAttributes attribute_path.Ma
 		Before:             UnmarshalGeneric(change.Before),
 		After:              UnmarshalGeneric(change.After),
 		Unknown:            UnmarshalGeneric(change.AfterUnknown),
-		BeforeSensitive:    UnmarshalGeneric(change.BeforeSensitive),
-		AfterSensitive:     UnmarshalGeneric(change.AfterSensitive),
+		BeforeSensitive:    maskPaths(UnmarshalGeneric(change.BeforeSensitive), change.MaskedPaths),
+		AfterSensitive:     maskPaths(UnmarshalGeneric(change.AfterSensitive), change.MaskedPaths),
 		ReplacePaths:       attribute_path.Parse(change.ReplacePaths, false),
 		RelevantAttributes: relevantAttributes,
 	}
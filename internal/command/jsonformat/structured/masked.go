@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structured
+
+import (
+	"encoding/json"
+)
+
+// maskPaths returns the given generic sensitivity structure with every value
+// at the given JSON-encoded paths marked as sensitive, so that the values that
+// a resource asks to mask in plan output are rendered in the same way as
+// sensitive values.
+func maskPaths(sensitive interface{}, raw json.RawMessage) interface{} {
+	if raw == nil {
+		return sensitive
+	}
+
+	var paths [][]interface{}
+	if err := json.Unmarshal(raw, &paths); err != nil {
+		panic("failed to unmarshal masked paths: " + err.Error())
+	}
+	for _, path := range paths {
+		sensitive = maskPath(sensitive, path)
+	}
+	return sensitive
+}
+
+func maskPath(sensitive interface{}, path []interface{}) interface{} {
+	if len(path) == 0 {
+		return true
+	}
+	if concrete, ok := sensitive.(bool); ok && concrete {
+		// The whole value is already sensitive.
+		return true
+	}
+
+	switch step := path[0].(type) {
+	case string:
+		m := genericToMap(sensitive)
+		if m == nil {
+			m = make(map[string]interface{})
+		}
+		m[step] = maskPath(m[step], path[1:])
+		return m
+	case float64:
+		ix := int(step)
+		s := genericToSlice(sensitive)
+		for len(s) <= ix {
+			s = append(s, false)
+		}
+		s[ix] = maskPath(s[ix], path[1:])
+		return s
+	default:
+		return sensitive
+	}
+}
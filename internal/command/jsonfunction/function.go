@@ -63,6 +63,10 @@ func Marshal(f map[string]function.Function) ([]byte, tfdiags.Diagnostics) {
 			signatures.Signatures[name] = marshalCan(v)
 		case "try", lang.CoreNamespace + "try":
 			signatures.Signatures[name] = marshalTry(v)
+		case "catch", lang.CoreNamespace + "catch":
+			signatures.Signatures[name] = marshalCatch(v)
+		case "must", lang.CoreNamespace + "must":
+			signatures.Signatures[name] = marshalMust(v)
 		default:
 			signature, err := marshalFunction(v)
 			if err != nil {
@@ -150,3 +154,44 @@ func marshalCan(can function.Function) *FunctionSignature {
 		},
 	}
 }
+
+// marshalCatch returns a static function signature for the catch function.
+// We need this exception because the function implementation uses capsule
+// types that we can't marshal.
+func marshalCatch(catch function.Function) *FunctionSignature {
+	return &FunctionSignature{
+		Description: catch.Description(),
+		ReturnType: cty.Object(map[string]cty.Type{
+			"ok":    cty.Bool,
+			"value": cty.DynamicPseudoType,
+			"error": cty.String,
+		}),
+		Parameters: []*parameter{
+			{
+				Name:        catch.Params()[0].Name,
+				Description: catch.Params()[0].Description,
+				IsNullable:  catch.Params()[0].AllowNull,
+				Type:        cty.DynamicPseudoType,
+			},
+		},
+	}
+}
+
+// marshalMust returns a static function signature for the must function.
+// We need this exception because the function implementation uses capsule
+// types that we can't marshal.
+func marshalMust(must function.Function) *FunctionSignature {
+	return &FunctionSignature{
+		Description: must.Description(),
+		ReturnType:  cty.DynamicPseudoType,
+		Parameters: []*parameter{
+			{
+				Name:        must.Params()[0].Name,
+				Description: must.Params()[0].Description,
+				IsNullable:  must.Params()[0].AllowNull,
+				Type:        cty.DynamicPseudoType,
+			},
+			marshalParameter(&must.Params()[1]),
+		},
+	}
+}
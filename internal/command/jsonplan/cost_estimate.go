@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// CostEstimate is the JSON representation of a plan's cost estimate. It's
+// both the "cost_estimate" property of a JSON plan and the format that a cost
+// estimation program must write to its standard output.
+type CostEstimate struct {
+	Currency             string         `json:"currency"`
+	TotalMonthlyCost     string         `json:"total_monthly_cost"`
+	PastTotalMonthlyCost string         `json:"past_total_monthly_cost,omitempty"`
+	Resources            []ResourceCost `json:"resources,omitempty"`
+}
+
+// ResourceCost is the JSON representation of the estimated cost of a single
+// resource instance.
+type ResourceCost struct {
+	Address         string `json:"address"`
+	MonthlyCost     string `json:"monthly_cost"`
+	PastMonthlyCost string `json:"past_monthly_cost,omitempty"`
+}
+
+// MarshalCostEstimate returns the JSON representation of the given cost
+// estimate, or nil if there is none.
+func MarshalCostEstimate(estimate *plans.CostEstimate) *CostEstimate {
+	if estimate == nil {
+		return nil
+	}
+	ret := &CostEstimate{
+		Currency:             estimate.Currency,
+		TotalMonthlyCost:     estimate.TotalMonthlyCost,
+		PastTotalMonthlyCost: estimate.PastTotalMonthlyCost,
+	}
+	for _, resource := range estimate.Resources {
+		ret.Resources = append(ret.Resources, ResourceCost{
+			Address:         resource.Address,
+			MonthlyCost:     resource.MonthlyCost,
+			PastMonthlyCost: resource.PastMonthlyCost,
+		})
+	}
+	// The estimator can report the resources in any order, so we sort them
+	// by address to make the output deterministic.
+	sort.SliceStable(ret.Resources, func(i, j int) bool {
+		return ret.Resources[i].Address < ret.Resources[j].Address
+	})
+	return ret
+}
+
+// UnmarshalCostEstimate decodes a cost estimate in its JSON representation,
+// as written by a cost estimation program, and checks that all of its costs
+// are valid decimal numbers.
+func UnmarshalCostEstimate(src []byte) (*plans.CostEstimate, error) {
+	var raw CostEstimate
+	if err := json.Unmarshal(src, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Currency == "" {
+		return nil, fmt.Errorf("missing currency")
+	}
+	if err := checkCost("total_monthly_cost", raw.TotalMonthlyCost, false); err != nil {
+		return nil, err
+	}
+	if err := checkCost("past_total_monthly_cost", raw.PastTotalMonthlyCost, true); err != nil {
+		return nil, err
+	}
+
+	ret := &plans.CostEstimate{
+		Currency:             raw.Currency,
+		TotalMonthlyCost:     raw.TotalMonthlyCost,
+		PastTotalMonthlyCost: raw.PastTotalMonthlyCost,
+	}
+	for _, resource := range raw.Resources {
+		if resource.Address == "" {
+			return nil, fmt.Errorf("resource cost without an address")
+		}
+		if err := checkCost(fmt.Sprintf("monthly_cost of %s", resource.Address), resource.MonthlyCost, false); err != nil {
+			return nil, err
+		}
+		if err := checkCost(fmt.Sprintf("past_monthly_cost of %s", resource.Address), resource.PastMonthlyCost, true); err != nil {
+			return nil, err
+		}
+		ret.Resources = append(ret.Resources, plans.ResourceCost{
+			Address:         resource.Address,
+			MonthlyCost:     resource.MonthlyCost,
+			PastMonthlyCost: resource.PastMonthlyCost,
+		})
+	}
+	return ret, nil
+}
+
+func checkCost(name, cost string, optional bool) error {
+	if cost == "" {
+		if optional {
+			return nil
+		}
+		return fmt.Errorf("missing %s", name)
+	}
+	if _, err := strconv.ParseFloat(cost, 64); err != nil {
+		return fmt.Errorf("invalid %s %q: must be a decimal number", name, cost)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestUnmarshalCostEstimate(t *testing.T) {
+	tests := map[string]struct {
+		Input   string
+		Want    *plans.CostEstimate
+		WantErr string
+	}{
+		"total only": {
+			Input: `{"currency":"EUR","total_monthly_cost":"3"}`,
+			Want: &plans.CostEstimate{
+				Currency:         "EUR",
+				TotalMonthlyCost: "3",
+			},
+		},
+		"with resources": {
+			Input: `{"currency":"USD","total_monthly_cost":"4.5","past_total_monthly_cost":"1.25","resources":[{"address":"test.a","monthly_cost":"4.5","past_monthly_cost":"1.25"}]}`,
+			Want: &plans.CostEstimate{
+				Currency:             "USD",
+				TotalMonthlyCost:     "4.5",
+				PastTotalMonthlyCost: "1.25",
+				Resources: []plans.ResourceCost{
+					{Address: "test.a", MonthlyCost: "4.5", PastMonthlyCost: "1.25"},
+				},
+			},
+		},
+		"not json": {
+			Input:   `nope`,
+			WantErr: "invalid character",
+		},
+		"missing currency": {
+			Input:   `{"total_monthly_cost":"3"}`,
+			WantErr: "missing currency",
+		},
+		"missing total": {
+			Input:   `{"currency":"USD"}`,
+			WantErr: "missing total_monthly_cost",
+		},
+		"invalid past total": {
+			Input:   `{"currency":"USD","total_monthly_cost":"3","past_total_monthly_cost":"$2"}`,
+			WantErr: `invalid past_total_monthly_cost "$2": must be a decimal number`,
+		},
+		"resource without address": {
+			Input:   `{"currency":"USD","total_monthly_cost":"3","resources":[{"monthly_cost":"3"}]}`,
+			WantErr: "resource cost without an address",
+		},
+		"resource without cost": {
+			Input:   `{"currency":"USD","total_monthly_cost":"3","resources":[{"address":"test.a"}]}`,
+			WantErr: "missing monthly_cost of test.a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalCostEstimate([]byte(test.Input))
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success; want error containing %q", test.WantErr)
+				}
+				if !strings.Contains(err.Error(), test.WantErr) {
+					t.Fatalf("wrong error %q; want error containing %q", err, test.WantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if diff := cmp.Diff(got, roundTrip(got)); diff != "" {
+				t.Errorf("MarshalCostEstimate doesn't round-trip\n%s", diff)
+			}
+		})
+	}
+}
+
+func roundTrip(estimate *plans.CostEstimate) *plans.CostEstimate {
+	raw := MarshalCostEstimate(estimate)
+	ret := &plans.CostEstimate{
+		Currency:             raw.Currency,
+		TotalMonthlyCost:     raw.TotalMonthlyCost,
+		PastTotalMonthlyCost: raw.PastTotalMonthlyCost,
+	}
+	for _, resource := range raw.Resources {
+		ret.Resources = append(ret.Resources, plans.ResourceCost(resource))
+	}
+	return ret
+}
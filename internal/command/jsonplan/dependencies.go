@@ -0,0 +1,148 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// ResourceDependencies returns the resources that each resource in the given
+// configuration depends upon, keyed by the string form of its
+// [addrs.ConfigResource].
+//
+// Dependencies are found statically from the references in each resource's
+// arguments, count, for_each, and depends_on, following references through
+// local values, module input variables, and module outputs so that the
+// result only ever contains resources. Each list is sorted and free of
+// duplicates.
+func ResourceDependencies(config *configs.Config, schemas *tofu.Schemas) map[string][]string {
+	ret := make(map[string][]string)
+	if config == nil {
+		return ret
+	}
+	r := &dependencyResolver{
+		schemas: schemas,
+		memo:    make(map[string]map[string]struct{}),
+	}
+	config.DeepEach(func(c *configs.Config) {
+		resources := make([]*configs.Resource, 0, len(c.Module.ManagedResources)+len(c.Module.DataResources))
+		for _, rc := range c.Module.ManagedResources {
+			resources = append(resources, rc)
+		}
+		for _, rc := range c.Module.DataResources {
+			resources = append(resources, rc)
+		}
+		for _, rc := range resources {
+			deps := make(map[string]struct{})
+			r.addExpr(c, rc.Count, deps)
+			r.addExpr(c, rc.ForEach, deps)
+			if schemas != nil {
+				if schema, _ := schemas.ResourceTypeConfig(rc.Provider, rc.Mode, rc.Type); schema != nil {
+					refs, _ := lang.ReferencesInBlock(addrs.ParseRef, rc.Config, schema)
+					r.addRefs(c, refs, deps)
+				}
+			}
+			dependsOn, _ := lang.References(addrs.ParseRef, rc.DependsOn)
+			r.addRefs(c, dependsOn, deps)
+
+			key := addrs.ConfigResource{Module: c.Path, Resource: rc.Addr()}.String()
+			delete(deps, key)
+			ret[key] = sortedKeys(deps)
+		}
+	})
+	return ret
+}
+
+type dependencyResolver struct {
+	schemas *tofu.Schemas
+
+	// memo records the resources that each local value, module output, and
+	// module input variable refers to, so that each is only analyzed once.
+	// An entry is added before the analysis starts, which also prevents
+	// following a reference cycle forever.
+	memo map[string]map[string]struct{}
+}
+
+func (r *dependencyResolver) addExpr(c *configs.Config, expr hcl.Expression, into map[string]struct{}) {
+	refs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+	r.addRefs(c, refs, into)
+}
+
+func (r *dependencyResolver) addRefs(c *configs.Config, refs []*addrs.Reference, into map[string]struct{}) {
+	for _, ref := range refs {
+		switch subject := ref.Subject.(type) {
+		case addrs.Resource:
+			into[addrs.ConfigResource{Module: c.Path, Resource: subject}.String()] = struct{}{}
+		case addrs.ResourceInstance:
+			into[addrs.ConfigResource{Module: c.Path, Resource: subject.Resource}.String()] = struct{}{}
+		case addrs.LocalValue:
+			if local, ok := c.Module.Locals[subject.Name]; ok {
+				r.addNamed(c.Path.String()+"/local."+subject.Name, c, local.Expr, into)
+			}
+		case addrs.InputVariable:
+			if c.Parent == nil {
+				continue
+			}
+			call, ok := c.Parent.Module.ModuleCalls[c.Path[len(c.Path)-1]]
+			if !ok || call.Config == nil {
+				continue
+			}
+			attrs, _ := call.Config.JustAttributes()
+			if attr, ok := attrs[subject.Name]; ok {
+				r.addNamed(c.Path.String()+"/var."+subject.Name, c.Parent, attr.Expr, into)
+			}
+		case addrs.ModuleCallInstanceOutput:
+			r.addModuleOutputs(c, subject.Call.Call.Name, subject.Name, into)
+		case addrs.ModuleCallInstance:
+			r.addModuleOutputs(c, subject.Call.Name, "", into)
+		case addrs.ModuleCall:
+			r.addModuleOutputs(c, subject.Name, "", into)
+		}
+	}
+}
+
+// addModuleOutputs adds the dependencies of the named output of a child
+// module, or of all of its outputs if the name is empty.
+func (r *dependencyResolver) addModuleOutputs(c *configs.Config, call string, name string, into map[string]struct{}) {
+	child, ok := c.Children[call]
+	if !ok {
+		return
+	}
+	for outputName, output := range child.Module.Outputs {
+		if name != "" && outputName != name {
+			continue
+		}
+		r.addNamed(child.Path.String()+"/output."+outputName, child, output.Expr, into)
+	}
+}
+
+func (r *dependencyResolver) addNamed(key string, c *configs.Config, expr hcl.Expression, into map[string]struct{}) {
+	deps, ok := r.memo[key]
+	if !ok {
+		deps = make(map[string]struct{})
+		r.memo[key] = deps
+		r.addExpr(c, expr, deps)
+	}
+	for dep := range deps {
+		into[dep] = struct{}{}
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	ret := make([]string, 0, len(set))
+	for key := range set {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
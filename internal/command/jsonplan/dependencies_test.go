@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestResourceDependencies(t *testing.T) {
+	config, _ := initwd.MustLoadConfigForTests(t, "testdata/dependencies", "tests")
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_thing": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"id":    {Type: cty.String, Computed: true},
+								"input": {Type: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := ResourceDependencies(config, schemas)
+	want := map[string][]string{
+		"test_thing.base":                  {},
+		"test_thing.app":                   {"module.network.test_thing.subnet"},
+		"test_thing.ordered":               {"module.network.test_thing.subnet", "test_thing.app"},
+		"module.network.test_thing.subnet": {"test_thing.base"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong dependencies\n%s", diff)
+	}
+}
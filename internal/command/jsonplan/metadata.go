@@ -0,0 +1,99 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// Metadata describes where a saved plan came from and whether it can still
+// be applied, without any of the changes it proposes.
+type Metadata struct {
+	FormatVersion string `json:"format_version"`
+
+	// Timestamp is when the plan was created, and ExpiresAt is when it can
+	// no longer be applied, if it has a maximum age. Both use RFC 3339
+	// format.
+	Timestamp string `json:"timestamp"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Expired   bool   `json:"expired"`
+
+	// Mode is one of "normal", "destroy", or "refresh-only".
+	Mode string `json:"mode"`
+
+	Errored   bool `json:"errored"`
+	Applyable bool `json:"applyable"`
+
+	Backend    MetadataBackend `json:"backend"`
+	PriorState MetadataState   `json:"prior_state"`
+
+	Targets  []string `json:"targets,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// MetadataBackend describes the backend that a saved plan will be applied
+// with.
+type MetadataBackend struct {
+	Type      string `json:"type"`
+	Workspace string `json:"workspace"`
+}
+
+// MetadataState identifies the state snapshot that a saved plan was created
+// from, which must still be the latest snapshot when the plan is applied.
+type MetadataState struct {
+	Lineage string `json:"lineage,omitempty"`
+	Serial  uint64 `json:"serial"`
+}
+
+// NewMetadata returns the metadata for the given plan and the prior state
+// snapshot saved with it, deciding whether the plan has expired as of now.
+func NewMetadata(p *plans.Plan, sf *statefile.File, now time.Time) *Metadata {
+	ret := &Metadata{
+		FormatVersion: FormatVersion,
+		Timestamp:     p.Timestamp.Format(time.RFC3339),
+		Expired:       p.Expired(now),
+		Mode:          metadataMode(p.UIMode),
+		Errored:       p.Errored,
+		Applyable:     p.CanApply() && !p.Expired(now),
+		Backend: MetadataBackend{
+			Type:      p.Backend.Type,
+			Workspace: p.Backend.Workspace,
+		},
+	}
+	if !p.ExpiresAt.IsZero() {
+		ret.ExpiresAt = p.ExpiresAt.Format(time.RFC3339)
+	}
+	if sf != nil {
+		ret.PriorState = MetadataState{Lineage: sf.Lineage, Serial: sf.Serial}
+	}
+	for _, addr := range p.TargetAddrs {
+		ret.Targets = append(ret.Targets, addr.String())
+	}
+	for _, addr := range p.ExcludeAddrs {
+		ret.Excludes = append(ret.Excludes, addr.String())
+	}
+	return ret
+}
+
+// MarshalMetadata returns the JSON encoding of the given metadata.
+func MarshalMetadata(m *Metadata) ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func metadataMode(mode plans.Mode) string {
+	switch mode {
+	case plans.DestroyMode:
+		return "destroy"
+	case plans.RefreshOnlyMode:
+		return "refresh-only"
+	default:
+		return "normal"
+	}
+}
@@ -6,6 +6,7 @@
 package jsonplan
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -46,6 +47,7 @@ const (
 	ResourceInstanceReadBecauseConfigUnknown      = "read_because_config_unknown"
 	ResourceInstanceReadBecauseDependencyPending  = "read_because_dependency_pending"
 	ResourceInstanceReadBecauseCheckNested        = "read_because_check_nested"
+	ResourceInstanceReadBecauseDeferredByConfig   = "read_because_deferred_by_config"
 )
 
 // Plan is the top-level representation of the json format of a plan. It includes
@@ -64,6 +66,7 @@ type Plan struct {
 	Config             json.RawMessage   `json:"configuration,omitempty"`
 	RelevantAttributes []ResourceAttr    `json:"relevant_attributes,omitempty"`
 	Checks             json.RawMessage   `json:"checks,omitempty"`
+	CostEstimate       *CostEstimate     `json:"cost_estimate,omitempty"`
 	Timestamp          string            `json:"timestamp,omitempty"`
 	Errored            bool              `json:"errored"`
 }
@@ -131,6 +134,13 @@ type Change struct {
 	// string.
 	ReplacePaths json.RawMessage `json:"replace_paths,omitempty"`
 
+	// MaskedPaths is an array of arrays representing a set of paths into the
+	// object value whose values should be hidden in user interfaces, as
+	// requested by the "mask_in_output" lifecycle argument of the resource.
+	// Unlike sensitive values, these values are not otherwise treated
+	// differently. Each path has the same form as in ReplacePaths.
+	MaskedPaths json.RawMessage `json:"masked_paths,omitempty"`
+
 	// Importing contains the import metadata about this operation. If importing
 	// is present (ie. not null) then the change is an import operation in
 	// addition to anything mentioned in the actions field. The actual contents
@@ -281,9 +291,12 @@ func MarshalForLog(
 
 	// output.Checks
 	if p.Checks != nil && p.Checks.ConfigResults.Len() > 0 {
-		output.Checks = jsonchecks.MarshalCheckStates(p.Checks)
+		output.Checks = jsonchecks.MarshalCheckStates(p.Checks, config)
 	}
 
+	// output.CostEstimate
+	output.CostEstimate = MarshalCostEstimate(p.CostEstimate)
+
 	// output.PriorState
 	if sf != nil && !sf.State.Empty() {
 		output.PriorState, err = jsonstate.Marshal(sf, schemas)
@@ -479,6 +492,10 @@ func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schema
 		if err != nil {
 			return nil, err
 		}
+		maskedPaths, err := encodePaths(rc.MaskedPaths)
+		if err != nil {
+			return nil, err
+		}
 
 		var importing *Importing
 		if rc.Importing != nil {
@@ -493,6 +510,7 @@ func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schema
 			BeforeSensitive: json.RawMessage(beforeSensitive),
 			AfterSensitive:  json.RawMessage(afterSensitive),
 			ReplacePaths:    replacePaths,
+			MaskedPaths:     maskedPaths,
 			Importing:       importing,
 			GeneratedConfig: rc.GeneratedConfig,
 		}
@@ -551,6 +569,8 @@ func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schema
 			r.ActionReason = ResourceInstanceReadBecauseDependencyPending
 		case plans.ResourceInstanceReadBecauseCheckNested:
 			r.ActionReason = ResourceInstanceReadBecauseCheckNested
+		case plans.ResourceInstanceReadBecauseDeferredByConfig:
+			r.ActionReason = ResourceInstanceReadBecauseDeferredByConfig
 		default:
 			return nil, fmt.Errorf("resource %s has an unsupported action reason %s", r.Address, rc.ActionReason)
 		}
@@ -710,10 +730,14 @@ func (p *Plan) marshalRelevantAttrs(plan *plans.Plan) error {
 		p.RelevantAttributes = append(p.RelevantAttributes, ResourceAttr{addr, path})
 	}
 
-	// We sort the relevant attributes by resource address to make the output
-	// deterministic. Our own equivalence tests rely on it.
+	// We sort the relevant attributes by resource address, and then by
+	// attribute path, to make the output deterministic. Our own equivalence
+	// tests rely on it.
 	sort.Slice(p.RelevantAttributes, func(i, j int) bool {
-		return p.RelevantAttributes[i].Resource < p.RelevantAttributes[j].Resource
+		if p.RelevantAttributes[i].Resource != p.RelevantAttributes[j].Resource {
+			return p.RelevantAttributes[i].Resource < p.RelevantAttributes[j].Resource
+		}
+		return bytes.Compare(p.RelevantAttributes[i].Attr, p.RelevantAttributes[j].Attr) < 0
 	})
 
 	return nil
@@ -873,6 +897,11 @@ func actionString(action string) []string {
 	}
 }
 
+// MarshalActions returns the JSON plan representation of the given action.
+func MarshalActions(action plans.Action) []string {
+	return actionString(action.String())
+}
+
 // UnmarshalActions reverses the actionString function.
 func UnmarshalActions(actions []string) plans.Action {
 	if len(actions) == 2 {
@@ -934,6 +963,12 @@ func encodePaths(pathSet cty.PathSet) (json.RawMessage, error) {
 		jsonPaths = append(jsonPaths, jsonPath)
 	}
 
+	// The order of the paths in a set is undefined, so we sort them by their
+	// encoding to make the output deterministic.
+	sort.Slice(jsonPaths, func(i, j int) bool {
+		return bytes.Compare(jsonPaths[i], jsonPaths[j]) < 0
+	})
+
 	return json.Marshal(jsonPaths)
 }
 
@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// ProvisionerPreview is the JSON representation of what a provisioner will
+// run when a planned resource instance change is applied.
+//
+// Provisioner previews aren't saved in plan files, so they are never part of
+// the JSON plan representation and are only used to render plans in memory.
+type ProvisionerPreview struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+
+	// When is "create" for a creation-time provisioner and "destroy" for a
+	// destroy-time provisioner.
+	When string `json:"when"`
+
+	// Hook is the event of the resource lifecycle hook that the provisioner
+	// runs, or empty for a "provisioner" block.
+	Hook string `json:"hook,omitempty"`
+
+	// Config describes the provisioner configuration as the creation of an
+	// object with only the arguments that are set, plus a "connection"
+	// property describing the remote host that the provisioner connects to,
+	// if any.
+	Config Change `json:"config"`
+}
+
+// MarshalProvisionerPreviews returns the JSON representation of the
+// provisioner previews in the given changes, in the order of the changes.
+func MarshalProvisionerPreviews(changes *plans.Changes) ([]ProvisionerPreview, error) {
+	if changes == nil {
+		return nil, nil
+	}
+
+	var ret []ProvisionerPreview
+	for _, rc := range changes.Resources {
+		for _, preview := range rc.Provisioners {
+			val := omitNullAttrs(preview.Config)
+			if !preview.Connection.IsNull() {
+				val = withAttr(val, "connection", omitNullAttrs(preview.Connection))
+			}
+
+			change, err := GenerateChange(cty.NullVal(val.Type()), val)
+			if err != nil {
+				return nil, err
+			}
+			change.Actions = actionString(plans.Create.String())
+
+			when := "create"
+			if preview.Destroy {
+				when = "destroy"
+			}
+			ret = append(ret, ProvisionerPreview{
+				Address: rc.Addr.String(),
+				Type:    preview.Type,
+				When:    when,
+				Hook:    preview.Hook,
+				Config:  *change,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// omitNullAttrs returns the given object value without its null attributes,
+// preserving any marks on the object itself.
+func omitNullAttrs(val cty.Value) cty.Value {
+	val, valMarks := val.Unmark()
+	if val.IsNull() || !val.IsKnown() {
+		return val.WithMarks(valMarks)
+	}
+
+	attrs := make(map[string]cty.Value)
+	for name, attr := range val.AsValueMap() {
+		if attr.IsNull() {
+			continue
+		}
+		attrs[name] = attr
+	}
+	return cty.ObjectVal(attrs).WithMarks(valMarks)
+}
+
+func withAttr(val cty.Value, name string, attr cty.Value) cty.Value {
+	val, valMarks := val.Unmark()
+	if !val.IsKnown() {
+		return val.WithMarks(valMarks)
+	}
+	attrs := val.AsValueMap()
+	if attrs == nil {
+		attrs = make(map[string]cty.Value)
+	}
+	attrs[name] = attr
+	return cty.ObjectVal(attrs).WithMarks(valMarks)
+}
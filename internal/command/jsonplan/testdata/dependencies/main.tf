@@ -0,0 +1,20 @@
+resource "test_thing" "base" {
+}
+
+locals {
+  base_id = test_thing.base.id
+}
+
+module "network" {
+  source = "./network"
+  base   = local.base_id
+}
+
+resource "test_thing" "app" {
+  input = module.network.subnet_id
+}
+
+resource "test_thing" "ordered" {
+  count      = length(test_thing.app[*].id)
+  depends_on = [module.network]
+}
@@ -0,0 +1,11 @@
+variable "base" {
+  type = string
+}
+
+resource "test_thing" "subnet" {
+  input = var.base
+}
+
+output "subnet_id" {
+  value = test_thing.subnet.id
+}
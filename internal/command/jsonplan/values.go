@@ -171,10 +171,6 @@ func marshalPlannedValues(changes *plans.Changes, schemas *tofu.Schemas) (Module
 	if err != nil {
 		return ret, err
 	}
-	sort.Slice(childModules, func(i, j int) bool {
-		return childModules[i].Address < childModules[j].Address
-	})
-
 	ret.ChildModules = childModules
 
 	return ret, nil
@@ -298,5 +294,11 @@ func marshalPlanModules(
 		ret = append(ret, cm)
 	}
 
+	// sort the child modules by address for consistency, at every level of
+	// the module tree.
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Address < ret[j].Address
+	})
+
 	return ret, nil
 }
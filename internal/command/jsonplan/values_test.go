@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -347,6 +348,70 @@ func TestMarshalPlanValuesNoopDeposed(t *testing.T) {
 	}
 }
 
+func TestMarshalPlanValuesNestedModuleOrder(t *testing.T) {
+	after, err := plans.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+		"woozles": cty.StringVal("woo"),
+		"foozles": cty.NullVal(cty.String),
+	}), cty.Object(map[string]cty.Type{
+		"woozles": cty.String,
+		"foozles": cty.String,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := plans.NewDynamicValue(cty.NullVal(cty.DynamicPseudoType), cty.DynamicPseudoType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change := func(addr string) *plans.ResourceInstanceChangeSrc {
+		return &plans.ResourceInstanceChangeSrc{
+			Addr: mustAddr(addr),
+			ProviderAddr: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			ChangeSrc: plans.ChangeSrc{
+				Action: plans.Create,
+				Before: before,
+				After:  after,
+			},
+		}
+	}
+
+	// The same changes in a different order must produce the same values.
+	addrStrs := []string{
+		"module.a.module.c.test_thing.example",
+		"module.a.module.b.test_thing.example",
+		"module.a.test_thing.example",
+	}
+	var want Module
+	for i := range addrStrs {
+		changes := &plans.Changes{}
+		for j := range addrStrs {
+			changes.Resources = append(changes.Resources, change(addrStrs[(i+j)%len(addrStrs)]))
+		}
+		got, err := marshalPlannedValues(changes, testSchemas())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			want = got
+			continue
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("wrong result with changes in a different order\n%s", diff)
+		}
+	}
+
+	var gotOrder []string
+	for _, mod := range want.ChildModules[0].ChildModules {
+		gotOrder = append(gotOrder, mod.Address)
+	}
+	if diff := cmp.Diff([]string{"module.a.module.b", "module.a.module.c"}, gotOrder); diff != "" {
+		t.Fatalf("wrong child module order\n%s", diff)
+	}
+}
+
 func testSchemas() *tofu.Schemas {
 	return &tofu.Schemas{
 		Providers: map[addrs.Provider]providers.ProviderSchema{
@@ -0,0 +1,415 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonsarif produces a SARIF (Static Analysis Results Interchange
+// Format) report describing the risks in a saved plan, so that code scanning
+// tools can show them alongside the configuration that caused them.
+package jsonsarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+const (
+	// FormatVersion is the version of the SARIF specification that the
+	// report conforms to.
+	FormatVersion = "2.1.0"
+
+	schemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// The identifiers of the rules that results can be reported against.
+const (
+	RuleResourceDelete  = "resource-delete"
+	RuleResourceReplace = "resource-replace"
+	RuleCheckFailed     = "check-failed"
+	RuleDeprecated      = "deprecated"
+)
+
+var rules = []Rule{
+	{
+		ID:               RuleResourceDelete,
+		ShortDescription: Message{Text: "The plan destroys an existing infrastructure object."},
+	},
+	{
+		ID:               RuleResourceReplace,
+		ShortDescription: Message{Text: "The plan replaces an existing infrastructure object, destroying the original."},
+	},
+	{
+		ID:               RuleCheckFailed,
+		ShortDescription: Message{Text: "A condition or check block assertion failed while planning."},
+	},
+	{
+		ID:               RuleDeprecated,
+		ShortDescription: Message{Text: "The configuration uses a deprecated feature."},
+	},
+}
+
+// Report is the top-level object of a SARIF log.
+type Report struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID               string  `json:"id"`
+	ShortDescription Message `json:"shortDescription"`
+}
+
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// Marshal returns the SARIF report for the given plan.
+//
+// Results are located in the configuration where possible. Objects that
+// are being destroyed because they were removed from the configuration have
+// no location to report.
+func Marshal(plan *plans.Plan, config *configs.Config, schemas *tofu.Schemas) ([]byte, error) {
+	report := Report{
+		Schema:  schemaURI,
+		Version: FormatVersion,
+		Runs: []Run{
+			{
+				Tool: Tool{
+					Driver: Driver{
+						Name:           "OpenTofu",
+						Version:        version.String(),
+						InformationURI: "https://opentofu.org",
+						Rules:          rules,
+					},
+				},
+				Results: planResults(plan, config, schemas),
+			},
+		},
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func planResults(plan *plans.Plan, config *configs.Config, schemas *tofu.Schemas) []Result {
+	// SARIF consumers expect an empty array rather than null when there
+	// are no results.
+	results := []Result{}
+	results = append(results, changeResults(plan, config)...)
+	results = append(results, checkResults(plan, config)...)
+	results = append(results, deprecationResults(config, schemas)...)
+	return results
+}
+
+func changeResults(plan *plans.Plan, config *configs.Config) []Result {
+	var results []Result
+	if plan.Changes == nil {
+		return results
+	}
+	for _, change := range plan.Changes.Resources {
+		if change.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+
+		var ruleID, verb string
+		switch change.Action {
+		case plans.Delete:
+			ruleID, verb = RuleResourceDelete, "destroyed"
+		case plans.DeleteThenCreate, plans.CreateThenDelete:
+			ruleID, verb = RuleResourceReplace, "replaced"
+		default:
+			continue
+		}
+
+		addr := change.Addr.String()
+		if change.DeposedKey != states.NotDeposed {
+			// Deposed objects are always left over from an earlier
+			// create_before_destroy replacement.
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, change.DeposedKey)
+		}
+		msg := fmt.Sprintf("%s will be %s", addr, verb)
+		if reason := actionReasonText(change.ActionReason); reason != "" {
+			msg += " " + reason
+		}
+
+		result := Result{
+			RuleID:  ruleID,
+			Level:   "warning",
+			Message: Message{Text: msg + "."},
+		}
+		if rc := resourceConfig(config, change.Addr); rc != nil {
+			result.Locations = locations(rc.DeclRange)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func resourceConfig(config *configs.Config, addr addrs.AbsResourceInstance) *configs.Resource {
+	if config == nil {
+		return nil
+	}
+	mod := config.DescendentForInstance(addr.Module)
+	if mod == nil {
+		return nil
+	}
+	return mod.Module.ResourceByAddr(addr.Resource.Resource)
+}
+
+func actionReasonText(reason plans.ResourceInstanceChangeActionReason) string {
+	switch reason {
+	case plans.ResourceInstanceReplaceBecauseTainted:
+		return "because it is tainted"
+	case plans.ResourceInstanceReplaceByRequest:
+		return "as requested"
+	case plans.ResourceInstanceReplaceByTriggers:
+		return "because of replace_triggered_by"
+	case plans.ResourceInstanceReplaceBecauseCannotUpdate:
+		return "because some of its arguments can't be updated in-place"
+	case plans.ResourceInstanceDeleteBecauseNoResourceConfig:
+		return "because it is no longer in the configuration"
+	case plans.ResourceInstanceDeleteBecauseNoModule:
+		return "because its module is no longer in the configuration"
+	case plans.ResourceInstanceDeleteBecauseWrongRepetition, plans.ResourceInstanceDeleteBecauseCountIndex, plans.ResourceInstanceDeleteBecauseEachKey:
+		return "because its instance key is no longer declared"
+	default:
+		return ""
+	}
+}
+
+func checkResults(plan *plans.Plan, config *configs.Config) []Result {
+	var results []Result
+	if plan.Checks == nil {
+		return results
+	}
+	for _, configElem := range plan.Checks.ConfigResults.Elems {
+		var rng *hcl.Range
+		if config != nil {
+			if mod := config.Descendent(configCheckableModule(configElem.Key)); mod != nil {
+				rng = checkableDeclRange(mod.Module, configElem.Key)
+			}
+		}
+
+		for _, objElem := range configElem.Value.ObjectResults.Elems {
+			if objElem.Value.Status != checks.StatusFail {
+				continue
+			}
+			msg := fmt.Sprintf("Checks failed for %s.", objElem.Key)
+			if len(objElem.Value.FailureMessages) != 0 {
+				msg = fmt.Sprintf("Checks failed for %s: %s", objElem.Key, strings.Join(objElem.Value.FailureMessages, " "))
+			}
+			result := Result{
+				RuleID:  RuleCheckFailed,
+				Level:   "error",
+				Message: Message{Text: msg},
+			}
+			if rng != nil {
+				result.Locations = locations(*rng)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func configCheckableModule(addr addrs.ConfigCheckable) addrs.Module {
+	switch addr := addr.(type) {
+	case addrs.ConfigResource:
+		return addr.Module
+	case addrs.ConfigOutputValue:
+		return addr.Module
+	case addrs.ConfigCheck:
+		return addr.Module
+	case addrs.ConfigInputVariable:
+		return addr.Module
+	default:
+		return addrs.RootModule
+	}
+}
+
+func checkableDeclRange(mod *configs.Module, addr addrs.ConfigCheckable) *hcl.Range {
+	switch addr := addr.(type) {
+	case addrs.ConfigResource:
+		if rc := mod.ResourceByAddr(addr.Resource); rc != nil {
+			return &rc.DeclRange
+		}
+	case addrs.ConfigOutputValue:
+		if oc, ok := mod.Outputs[addr.OutputValue.Name]; ok {
+			return &oc.DeclRange
+		}
+	case addrs.ConfigCheck:
+		if cc, ok := mod.Checks[addr.Check.Name]; ok {
+			return &cc.DeclRange
+		}
+	case addrs.ConfigInputVariable:
+		if vc, ok := mod.Variables[addr.Variable.Name]; ok {
+			return &vc.DeclRange
+		}
+	}
+	return nil
+}
+
+// deprecationResults reports deprecated resource types and arguments that
+// the configuration uses, along with deprecated module input variables that
+// module calls set.
+func deprecationResults(config *configs.Config, schemas *tofu.Schemas) []Result {
+	var results []Result
+	if config == nil {
+		return results
+	}
+	config.DeepEach(func(c *configs.Config) {
+		if schemas != nil {
+			for _, key := range sortedKeys(c.Module.ManagedResources) {
+				results = append(results, resourceDeprecations(c.Module.ManagedResources[key], schemas)...)
+			}
+			for _, key := range sortedKeys(c.Module.DataResources) {
+				results = append(results, resourceDeprecations(c.Module.DataResources[key], schemas)...)
+			}
+		}
+
+		for _, name := range sortedKeys(c.Module.ModuleCalls) {
+			call := c.Module.ModuleCalls[name]
+			child := c.Children[name]
+			if child == nil || call.Config == nil {
+				continue
+			}
+			attrs, _ := call.Config.JustAttributes()
+			for _, attrName := range sortedKeys(attrs) {
+				v, ok := child.Module.Variables[attrName]
+				if !ok || v.Deprecated == "" {
+					continue
+				}
+				results = append(results, Result{
+					RuleID:    RuleDeprecated,
+					Level:     "warning",
+					Message:   Message{Text: fmt.Sprintf("Input variable %q of module %q is deprecated: %s", attrName, name, v.Deprecated)},
+					Locations: locations(attrs[attrName].Range),
+				})
+			}
+		}
+	})
+	return results
+}
+
+func resourceDeprecations(rc *configs.Resource, schemas *tofu.Schemas) []Result {
+	var results []Result
+	schema, _ := schemas.ResourceTypeConfig(rc.Provider, rc.Mode, rc.Type)
+	if schema == nil {
+		return results
+	}
+	addr := rc.Addr().String()
+	if schema.Deprecated {
+		results = append(results, Result{
+			RuleID:    RuleDeprecated,
+			Level:     "warning",
+			Message:   Message{Text: fmt.Sprintf("%s uses the deprecated resource type %q.", addr, rc.Type)},
+			Locations: locations(rc.DeclRange),
+		})
+	}
+
+	var deprecated []hcl.AttributeSchema
+	for _, name := range sortedKeys(schema.Attributes) {
+		if schema.Attributes[name].Deprecated {
+			deprecated = append(deprecated, hcl.AttributeSchema{Name: name})
+		}
+	}
+	if len(deprecated) == 0 || rc.Config == nil {
+		return results
+	}
+	content, _, _ := rc.Config.PartialContent(&hcl.BodySchema{Attributes: deprecated})
+	if content == nil {
+		return results
+	}
+	for _, name := range sortedKeys(content.Attributes) {
+		results = append(results, Result{
+			RuleID:    RuleDeprecated,
+			Level:     "warning",
+			Message:   Message{Text: fmt.Sprintf("The argument %q of %s is deprecated.", name, addr)},
+			Locations: locations(content.Attributes[name].Range),
+		})
+	}
+	return results
+}
+
+func locations(rng hcl.Range) []Location {
+	if rng.Filename == "" {
+		return nil
+	}
+	return []Location{
+		{
+			PhysicalLocation: PhysicalLocation{
+				ArtifactLocation: ArtifactLocation{URI: filepath.ToSlash(rng.Filename)},
+				Region: Region{
+					StartLine:   rng.Start.Line,
+					StartColumn: rng.Start.Column,
+					EndLine:     rng.End.Line,
+					EndColumn:   rng.End.Column,
+				},
+			},
+		},
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
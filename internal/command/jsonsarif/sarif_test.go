@@ -0,0 +1,150 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonsarif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestMarshal(t *testing.T) {
+	config, _ := initwd.MustLoadConfigForTests(t, "testdata/basic", "tests")
+
+	resourceAddr := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_thing",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	change := func(name string, action plans.Action, reason plans.ResourceInstanceChangeActionReason) *plans.ResourceInstanceChangeSrc {
+		return &plans.ResourceInstanceChangeSrc{
+			Addr:         resourceAddr(name),
+			PrevRunAddr:  resourceAddr(name),
+			ActionReason: reason,
+			ChangeSrc:    plans.ChangeSrc{Action: action},
+		}
+	}
+
+	check := addrs.Check{Name: "healthy"}
+	plan := &plans.Plan{
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				change("replaced", plans.DeleteThenCreate, plans.ResourceInstanceReplaceBecauseTainted),
+				change("kept", plans.Update, plans.ResourceInstanceChangeNoReason),
+				change("removed", plans.Delete, plans.ResourceInstanceDeleteBecauseNoResourceConfig),
+			},
+		},
+		Checks: &states.CheckResults{
+			ConfigResults: addrs.MakeMap(
+				addrs.MakeMapElem[addrs.ConfigCheckable](check.InModule(addrs.RootModule), &states.CheckResultAggregate{
+					Status: checks.StatusFail,
+					ObjectResults: addrs.MakeMap(
+						addrs.MakeMapElem[addrs.Checkable](check.Absolute(addrs.RootModuleInstance), &states.CheckResultObject{
+							Status:          checks.StatusFail,
+							FailureMessages: []string{"Not healthy."},
+						}),
+					),
+				}),
+			),
+		},
+	}
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_thing": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"legacy": {Type: cty.String, Optional: true, Deprecated: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	src, err := Marshal(plan, config, schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(src, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Version != FormatVersion || len(report.Runs) != 1 {
+		t.Fatalf("unexpected report structure:\n%s", src)
+	}
+
+	location := func(file string, line, col, endLine, endCol int) []Location {
+		return []Location{{PhysicalLocation: PhysicalLocation{
+			ArtifactLocation: ArtifactLocation{URI: file},
+			Region:           Region{StartLine: line, StartColumn: col, EndLine: endLine, EndColumn: endCol},
+		}}}
+	}
+	want := []Result{
+		{
+			RuleID:    RuleResourceReplace,
+			Level:     "warning",
+			Message:   Message{Text: "test_thing.replaced will be replaced because it is tainted."},
+			Locations: location("testdata/basic/main.tf", 1, 1, 1, 33),
+		},
+		{
+			RuleID:  RuleResourceDelete,
+			Level:   "warning",
+			Message: Message{Text: "test_thing.removed will be destroyed because it is no longer in the configuration."},
+		},
+		{
+			RuleID:    RuleCheckFailed,
+			Level:     "error",
+			Message:   Message{Text: "Checks failed for check.healthy: Not healthy."},
+			Locations: location("testdata/basic/main.tf", 8, 1, 8, 16),
+		},
+		{
+			RuleID:    RuleDeprecated,
+			Level:     "warning",
+			Message:   Message{Text: `The argument "legacy" of test_thing.replaced is deprecated.`},
+			Locations: location("testdata/basic/main.tf", 2, 3, 2, 17),
+		},
+		{
+			RuleID:    RuleDeprecated,
+			Level:     "warning",
+			Message:   Message{Text: `Input variable "old" of module "child" is deprecated: Use new instead.`},
+			Locations: location("testdata/basic/main.tf", 17, 3, 17, 19),
+		},
+	}
+	if diff := cmp.Diff(want, report.Runs[0].Results); diff != "" {
+		t.Errorf("wrong results\n%s", diff)
+	}
+}
+
+func TestMarshal_noResults(t *testing.T) {
+	src, err := Marshal(&plans.Plan{Changes: plans.NewChanges()}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report map[string]any
+	if err := json.Unmarshal(src, &report); err != nil {
+		t.Fatal(err)
+	}
+	results := report["runs"].([]any)[0].(map[string]any)["results"]
+	if got, ok := results.([]any); !ok || len(got) != 0 {
+		t.Fatalf("results should be an empty array, got %#v", results)
+	}
+}
@@ -0,0 +1,5 @@
+variable "old" {
+  type       = string
+  default    = null
+  deprecated = "Use new instead."
+}
@@ -0,0 +1,18 @@
+resource "test_thing" "replaced" {
+  legacy = "yes"
+}
+
+resource "test_thing" "kept" {
+}
+
+check "healthy" {
+  assert {
+    condition     = test_thing.kept.id != null
+    error_message = "Not healthy."
+  }
+}
+
+module "child" {
+  source = "./child"
+  old    = "value"
+}
@@ -186,7 +186,7 @@ func MarshalForLog(sf *statefile.File, schemas *tofu.Schemas) (*State, error) {
 
 	// output.Checks
 	if sf.State.CheckResults != nil && sf.State.CheckResults.ConfigResults.Len() > 0 {
-		output.Checks = jsonchecks.MarshalCheckStates(sf.State.CheckResults)
+		output.Checks = jsonchecks.MarshalCheckStates(sf.State.CheckResults, nil)
 	}
 
 	return output, nil
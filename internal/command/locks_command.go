@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// LocksCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type LocksCommand struct {
+	Meta
+}
+
+func (c *LocksCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *LocksCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] locks <subcommand> [options] [args]
+
+  This command has subcommands for inspecting state locks.
+
+  Locks held on any workspace can be released with the "force-unlock"
+  command and its -workspace option.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LocksCommand) Synopsis() string {
+	return "Inspect state locks"
+}
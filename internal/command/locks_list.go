@@ -0,0 +1,200 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// LocksListCommand is a Command implementation that lists the state locks
+// currently held across all workspaces of the configured backend.
+type LocksListCommand struct {
+	Meta
+}
+
+// locksList is the JSON representation of the output of LocksListCommand
+// with the -json option.
+type locksList struct {
+	Locks []heldLock `json:"locks"`
+
+	// Unsupported lists the workspaces whose locks can't be inspected, so
+	// that any locks held on them are missing from Locks.
+	Unsupported []string `json:"unsupported"`
+}
+
+// heldLock is the JSON representation of a single held lock, as produced by
+// LocksListCommand with the -json option.
+type heldLock struct {
+	Workspace string             `json:"workspace"`
+	Lock      *statemgr.LockInfo `json:"lock"`
+}
+
+func (c *LocksListCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+
+	var jsonOutput bool
+	cmdFlags := c.Meta.defaultFlagSet("locks list")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	configPath, err := modulePath(args)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// This command will not write state
+	c.ignoreRemoteVersionConflict(b)
+
+	workspaces, err := b.Workspaces(ctx)
+	if err == backend.ErrWorkspacesNotSupported {
+		// Backends without workspaces still have the default one.
+		workspaces, err = []string{backend.DefaultStateName}, nil
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	held := []heldLock{}
+	unsupported := []string{}
+	for _, workspace := range workspaces {
+		stateMgr, err := b.StateMgr(ctx, workspace)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to load state for workspace %q: %s", workspace, err))
+			return 1
+		}
+
+		inspector, ok := stateMgr.(statemgr.LockInspector)
+		if !ok {
+			unsupported = append(unsupported, workspace)
+			continue
+		}
+		info, err := inspector.CurrentLock(ctx)
+		if errors.Is(err, statemgr.ErrLockInspectionUnsupported) {
+			unsupported = append(unsupported, workspace)
+			continue
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read the lock for workspace %q: %s", workspace, err))
+			return 1
+		}
+		if info != nil {
+			held = append(held, heldLock{Workspace: workspace, Lock: info})
+		}
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(locksList{Locks: held, Unsupported: unsupported}, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal locks to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		return 0
+	}
+
+	if len(unsupported) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Some locks cannot be listed",
+			fmt.Sprintf("The configured backend cannot report lock details, so locks held on the following workspaces are not listed: %s.", strings.Join(unsupported, ", ")),
+		))
+	}
+
+	if len(held) == 0 {
+		c.Ui.Output("No state locks are currently held.")
+	}
+	for _, lock := range held {
+		c.Ui.Output(fmt.Sprintf("Workspace %q:\n%s", lock.Workspace, lock.Lock.String()))
+	}
+
+	c.showDiagnostics(diags)
+	return 0
+}
+
+func (c *LocksListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("")
+}
+
+func (c *LocksListCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json": complete.PredictNothing,
+	}
+}
+
+func (c *LocksListCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] locks list [options]
+
+  List the state locks currently held in any workspace of the configured
+  backend, along with details of who is holding each lock.
+
+  Not all backends are able to report lock details. Workspaces whose locks
+  cannot be inspected are reported in a warning, or in the "unsupported"
+  property of the JSON output.
+
+Options:
+
+  -json              Produce output in a machine-readable JSON format.
+
+  -var 'foo=bar'     Set a value for one of the input variables in the root
+                     module of the configuration. Use this option more than
+                     once to set more than one variable.
+
+  -var-file=filename Load variable values from the given file, in addition
+                     to the default files terraform.tfvars and *.auto.tfvars.
+                     Use this option more than once to include more than one
+                     variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LocksListCommand) Synopsis() string {
+	return "List held state locks across workspaces"
+}
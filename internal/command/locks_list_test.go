@@ -0,0 +1,168 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestLocksList_inmemBackend(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("backend-inmem-locked"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	ci := &InitCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := ci.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter)
+	}
+
+	// Lock a second workspace directly through the backend, as another
+	// process would.
+	b := backend.TestBackendConfig(t, inmem.New(encryption.StateEncryptionDisabled()), hcl.EmptyBody())
+	stagingMgr, err := b.StateMgr(t.Context(), "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := statemgr.NewLockInfo()
+	info.Operation = "OperationTypeApply"
+	stagingLockID, err := stagingMgr.Lock(t.Context(), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui = new(cli.MockUi)
+	c := &LocksListCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var list locksList
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &list); err != nil {
+		t.Fatalf("invalid json output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	if len(list.Unsupported) != 0 {
+		t.Errorf("unexpected unsupported workspaces: %#v", list.Unsupported)
+	}
+	got := list.Locks
+	if len(got) != 2 {
+		t.Fatalf("expected 2 held locks, got %d:\n%s", len(got), ui.OutputWriter.String())
+	}
+	if got[0].Workspace != "default" || got[0].Lock.ID != "2b6a6738-5dd5-50d6-c0ae-f6352977666b" {
+		t.Errorf("wrong default workspace lock: %#v", got[0])
+	}
+	if got[1].Workspace != "staging" || got[1].Lock.ID != stagingLockID {
+		t.Errorf("wrong staging workspace lock: %#v", got[1])
+	}
+
+	// The staging lock can be released without selecting that workspace.
+	ui = new(cli.MockUi)
+	uc := &UnlockCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := uc.Run([]string{"-force", "-workspace=staging", stagingLockID}); code != 0 {
+		t.Fatalf("bad: %d\n%s\n%s", code, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	c = &LocksListCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter.String())
+	}
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, `Workspace "default"`) {
+		t.Errorf("missing default workspace lock in output:\n%s", output)
+	}
+	if strings.Contains(output, `Workspace "staging"`) {
+		t.Errorf("unexpected staging workspace lock in output:\n%s", output)
+	}
+}
+
+func TestLocksList_noLocks(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &LocksListCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "No state locks are currently held."; !strings.Contains(got, want) {
+		t.Errorf("wrong output %q; want %q", got, want)
+	}
+}
+
+func TestLocksList_jsonUnsupported(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-backend-http"), td)
+	t.Chdir(td)
+
+	// The http backend can't report lock details, so its only workspace is
+	// listed as unsupported rather than silently left out.
+	dataState, srv := testBackendState(t, nil, 200)
+	defer srv.Close()
+	testStateFileRemote(t, dataState)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &LocksListCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got locksList
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatalf("invalid json output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	if len(got.Locks) != 0 {
+		t.Errorf("unexpected held locks: %#v", got.Locks)
+	}
+	if want := []string{"default"}; !slices.Equal(got.Unsupported, want) {
+		t.Errorf("wrong unsupported workspaces %#v; want %#v", got.Unsupported, want)
+	}
+}
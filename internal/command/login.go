@@ -195,6 +195,10 @@ func (c *LoginCommand) Run(args []string) int {
 		case clientConfig.SupportedGrantTypes.Has(disco.OAuthAuthzCodeGrant):
 			// We prefer an OAuth code grant if the server supports it.
 			oauthToken, tokenDiags = c.interactiveGetTokenByCode(ctx, hostname, credsCtx, clientConfig)
+		case clientConfig.SupportedGrantTypes.Has(oauthDeviceCodeGrant):
+			// The device authorization grant doesn't need a browser on this
+			// computer, so it also works in remote shells.
+			oauthToken, tokenDiags = c.interactiveGetTokenByDevice(ctx, host, hostname, credsCtx, clientConfig)
 		case clientConfig.SupportedGrantTypes.Has(disco.OAuthOwnerPasswordGrant) && hostname == svchost.Hostname(tfeHost):
 			// The password grant type is allowed only for Terraform Cloud SaaS.
 			// Note this case is purely theoretical at this point, as TFC currently uses
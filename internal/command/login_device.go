@@ -0,0 +1,191 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opentofu/svchost"
+	"github.com/opentofu/svchost/disco"
+	"golang.org/x/oauth2"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// oauthDeviceCodeGrant represents an OAuth device authorization grant, as
+// defined in IETF RFC 8628. The svchost/disco package doesn't know about
+// this grant type, but it preserves it in the client's supported grant types.
+const oauthDeviceCodeGrant = disco.OAuthGrantType("device_code")
+
+// oidcProviderMetadata is the subset of an OpenID Connect provider's
+// discovery document that "tofu login" uses for the device authorization
+// grant.
+type oidcProviderMetadata struct {
+	Issuer                      string `json:"issuer"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+func (c *LoginCommand) interactiveGetTokenByDevice(ctx context.Context, host *disco.Host, hostname svchost.Hostname, credsCtx *loginCredentialsContext, clientConfig *disco.OAuthClient) (*oauth2.Token, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// The login.v1 service can't describe a device authorization endpoint,
+	// so the host must also declare the issuer URL of the OpenID Connect
+	// provider that it delegates authentication to.
+	issuer, err := host.ServiceURL("oidc.v1")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Host does not support OpenTofu login",
+			fmt.Sprintf("The given hostname %q allows the OAuth device authorization grant, but doesn't declare a valid OpenID Connect provider for it with the \"oidc.v1\" service: %s.", hostname.ForDisplay(), err),
+		))
+		return nil, diags
+	}
+
+	confirm, confirmDiags := c.interactiveContextConsent(ctx, hostname, oauthDeviceCodeGrant, credsCtx)
+	diags = diags.Append(confirmDiags)
+	if !confirm {
+		diags = diags.Append(errors.New("Login cancelled"))
+		return nil, diags
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.ShutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpclient.New(ctx))
+
+	provider, err := discoverOIDCProvider(ctx, issuer)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to discover OpenID Connect provider",
+			fmt.Sprintf("OpenTofu couldn't read the configuration of the OpenID Connect provider at %s: %s.", issuer, err),
+		))
+		return nil, diags
+	}
+
+	endpoint := clientConfig.Endpoint()
+	endpoint.DeviceAuthURL = provider.DeviceAuthorizationEndpoint
+	if endpoint.TokenURL == "" {
+		endpoint.TokenURL = provider.TokenEndpoint
+	}
+	scopes := clientConfig.Scopes
+	if len(scopes) == 0 {
+		// OpenID Connect providers require the "openid" scope.
+		scopes = []string{"openid"}
+	}
+	oauthConfig := &oauth2.Config{
+		ClientID: clientConfig.ID,
+		Endpoint: endpoint,
+		Scopes:   scopes,
+	}
+
+	deviceAuth, err := oauthConfig.DeviceAuth(ctx)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to start login",
+			fmt.Sprintf("The OpenID Connect provider did not accept the device authorization request: %s.", err),
+		))
+		return nil, diags
+	}
+
+	if deviceAuth.VerificationURIComplete != "" && c.BrowserLauncher != nil && c.BrowserLauncher.OpenURL(deviceAuth.VerificationURIComplete) == nil {
+		c.Ui.Output(fmt.Sprintf("OpenTofu has opened a web browser to the login page for %s.\n", hostname.ForDisplay()))
+		c.Ui.Output(fmt.Sprintf("If a browser did not open, use a web browser on any device to open the\nfollowing URL and enter the code %s:\n    %s\n", deviceAuth.UserCode, deviceAuth.VerificationURI))
+	} else {
+		c.Ui.Output(fmt.Sprintf("To log in to %s, use a web browser on any device to open the\nfollowing URL and enter the code %s:\n    %s\n", hostname.ForDisplay(), deviceAuth.UserCode, deviceAuth.VerificationURI))
+	}
+	c.Ui.Output("OpenTofu will now wait for you to complete the login.\n")
+
+	token, err := oauthConfig.DeviceAccessToken(ctx, deviceAuth)
+	var retrieveErr *oauth2.RetrieveError
+	switch {
+	case err == nil:
+		return token, diags
+	case errors.Is(err, context.Canceled):
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Action aborted",
+			"Current command was aborted by the calling code.",
+		))
+	case errors.Is(err, context.DeadlineExceeded):
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Login code expired",
+			fmt.Sprintf("The code %s expired before the login was completed. Run \"tofu login\" again to get a new code.", deviceAuth.UserCode),
+		))
+	case errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "access_denied":
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Login denied",
+			"The login request was denied in the web browser.",
+		))
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to obtain auth token",
+			fmt.Sprintf("The remote server did not assign an auth token: %s.", err),
+		))
+	}
+	return nil, diags
+}
+
+// discoverOIDCProvider reads the discovery document of the OpenID Connect
+// provider with the given issuer URL, as defined in OpenID Connect Discovery
+// 1.0.
+func discoverOIDCProvider(ctx context.Context, issuer *url.URL) (*oidcProviderMetadata, error) {
+	issuerStr := strings.TrimSuffix(issuer.String(), "/")
+	req, err := http.NewRequestWithContext(ctx, "GET", issuerStr+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpclient.New(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery document request returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var ret oidcProviderMetadata
+	if err := json.Unmarshal(body, &ret); err != nil {
+		return nil, fmt.Errorf("invalid discovery document: %w", err)
+	}
+	// The issuer in the document must match the one we requested it for, so
+	// that a provider can't impersonate another one.
+	if strings.TrimSuffix(ret.Issuer, "/") != issuerStr {
+		return nil, fmt.Errorf("discovery document is for issuer %q", ret.Issuer)
+	}
+	if ret.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the provider doesn't support the device authorization grant")
+	}
+	if ret.TokenEndpoint == "" {
+		return nil, fmt.Errorf("discovery document has no token endpoint")
+	}
+	return &ret, nil
+}
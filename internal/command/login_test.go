@@ -81,6 +81,29 @@ func TestLogin(t *testing.T) {
 					"scopes": []interface{}{"app1.full_access", "app2.read_only"},
 				},
 			})
+			svcs.ForceHostServices(svchost.Hostname("device.example.com"), map[string]interface{}{
+				// This host delegates login to the OpenID Connect provider
+				// at oidc.v1, using the device authorization grant.
+				"login.v1": map[string]interface{}{
+					"client":      "anything-goes",
+					"grant_types": []interface{}{"device_code"},
+				},
+				"oidc.v1": s.URL,
+			})
+			svcs.ForceHostServices(svchost.Hostname("device-denied.example.com"), map[string]interface{}{
+				// The stub server denies the login for this client ID.
+				"login.v1": map[string]interface{}{
+					"client":      "denied",
+					"grant_types": []interface{}{"device_code"},
+				},
+				"oidc.v1": s.URL,
+			})
+			svcs.ForceHostServices(svchost.Hostname("device-no-oidc.example.com"), map[string]interface{}{
+				"login.v1": map[string]interface{}{
+					"client":      "anything-goes",
+					"grant_types": []interface{}{"device_code"},
+				},
+			})
 			svcs.ForceHostServices(svchost.Hostname(tfeHost), map[string]interface{}{
 				// This represents Terraform Cloud, which does not yet support the
 				// login API, but does support its own bespoke tokens API.
@@ -222,6 +245,62 @@ func TestLogin(t *testing.T) {
 		}
 	}, true))
 
+	t.Run("device.example.com with device authorization flow", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
+		// Enter "yes" at the consent prompt.
+		defer testInputMap(t, map[string]string{
+			"approve": "yes",
+		})()
+		status := c.Run([]string{"device.example.com"})
+		if status != 0 {
+			t.Fatalf("unexpected error code %d\nstderr:\n%s", status, ui.ErrorWriter.String())
+		}
+
+		credsSrc := c.Services.CredentialsSource()
+		creds, err := credsSrc.ForHost(t.Context(), svchost.Hostname("device.example.com"))
+		if err != nil {
+			t.Errorf("failed to retrieve credentials: %s", err)
+		}
+		if got, want := svcauthconfig.HostCredentialsBearerToken(t, creds), "good-token"; got != want {
+			t.Errorf("wrong token %q; want %q", got, want)
+		}
+
+		output := ui.OutputWriter.String()
+		for _, want := range []string{
+			"enter the code ABCD-EFGH:\n    " + s.URL + "/activate\n",
+			"OpenTofu has obtained and saved an API token.",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, but was:\n%s", want, output)
+			}
+		}
+	}, false))
+
+	t.Run("device-denied.example.com with denied device authorization", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
+		// Enter "yes" at the consent prompt.
+		defer testInputMap(t, map[string]string{
+			"approve": "yes",
+		})()
+		status := c.Run([]string{"device-denied.example.com"})
+		if status != 1 {
+			t.Fatalf("unexpected error code %d\nstderr:\n%s", status, ui.ErrorWriter.String())
+		}
+
+		if got, want := ui.ErrorWriter.String(), "The login request was denied in the web browser."; !strings.Contains(got, want) {
+			t.Errorf("expected error to contain %q, but was:\n%s", want, got)
+		}
+	}, false))
+
+	t.Run("device-no-oidc.example.com without an OpenID Connect provider", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
+		status := c.Run([]string{"device-no-oidc.example.com"})
+		if status != 1 {
+			t.Fatalf("unexpected error code %d\nstderr:\n%s", status, ui.ErrorWriter.String())
+		}
+
+		if got, want := ui.ErrorWriter.String(), "doesn't declare a valid OpenID Connect provider"; !strings.Contains(got, want) {
+			t.Errorf("expected error to contain %q, but was:\n%s", want, got)
+		}
+	}, false))
+
 	t.Run("TFE host without login support", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
 		// Enter "yes" at the consent prompt, then paste a token with some
 		// accidental whitespace.
@@ -29,7 +29,11 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/costestimate"
 	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/regopolicy"
+	"github.com/opentofu/opentofu/internal/command/runhook"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/command/webbrowser"
 	"github.com/opentofu/opentofu/internal/command/workdir"
@@ -38,6 +42,7 @@ import (
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
@@ -110,6 +115,40 @@ type Meta struct {
 	// into the given directory.
 	PluginCacheDir string
 
+	// LockNotifier, if non-nil, is told about state lock lifecycle events
+	// for all state locks taken or force-released by commands, such as to
+	// deliver the lock webhooks from the CLI configuration.
+	LockNotifier clistate.LockNotifier
+
+	// LockQueueTimeout is how long plan, apply, and refresh wait in the
+	// queue for a state lock that's held by another process when they aren't
+	// given the -lock-timeout option, from the CLI configuration.
+	LockQueueTimeout time.Duration
+
+	// DriftWebhooks are the HTTP endpoints that "tofu drift" sends its
+	// report to when it detects drift, from the CLI configuration.
+	DriftWebhooks []DriftWebhook
+
+	// RegoPolicies are the Rego policies from the CLI configuration that
+	// plans must pass before they're applied.
+	RegoPolicies []*regopolicy.Policy
+
+	// RunHooks are the external programs from the CLI configuration to run
+	// at points in the lifecycle of plan and apply operations.
+	RunHooks runhook.Hooks
+
+	// PlanSigning configures the keys used to sign saved plan files when
+	// they are created and to verify them before they are applied.
+	PlanSigning planfile.SigningConfig
+
+	// CostEstimator, if non-nil, estimates the cost of plans so that the
+	// estimate can be included when they are rendered.
+	CostEstimator *costestimate.Estimator
+
+	// RequireSignedPlans, if set, makes apply refuse any saved plan file
+	// that doesn't have a valid signature.
+	RequireSignedPlans bool
+
 	// PluginCacheMayBreakDependencyLockFile is a temporary CLI configuration-based
 	// opt out for the behavior of only using the plugin cache dir if its
 	// contents match checksums recorded in the dependency lock file.
@@ -126,6 +165,11 @@ type Meta struct {
 	// longer any compelling reasons for folks to not lock their dependencies.
 	PluginCacheMayBreakDependencyLockFile bool
 
+	// ProviderSchemaCache, if set, makes OpenTofu load provider schemas from
+	// the provider schema cache when it has them, instead of starting the
+	// providers to ask for them, and save the ones it does fetch there.
+	ProviderSchemaCache bool
+
 	// ProviderSource allows determining the available versions of a provider
 	// and determines where a distribution package for a particular
 	// provider version can be obtained.
@@ -259,6 +303,12 @@ type Meta struct {
 	// parallelism is used to control the number of concurrent operations
 	// allowed when walking the graph
 	//
+	// providerParallelism optionally lowers that limit for the resources
+	// of specific providers
+	//
+	// refreshParallelism, if nonzero, gives each provider configuration its
+	// own pool of concurrent operations while planning
+	//
 	// provider is to specify specific resource providers
 	//
 	// stateLock is set to false to disable state locking
@@ -274,6 +324,9 @@ type Meta struct {
 	// migrateState confirms the user wishes to migrate from the prior backend
 	// configuration to a new configuration.
 	//
+	// installJobs (-jobs) is the maximum number of modules and providers that
+	// init retrieves at the same time. Zero means one at a time.
+	//
 	// compactWarnings (-compact-warnings) selects a more compact presentation
 	// of warnings in the output when they are not accompanied by errors.
 	//
@@ -282,18 +335,36 @@ type Meta struct {
 	//
 	// consolidateErrors (-consolidate-errors=true) enables consolidation
 	// of errors in the output, printing a single instances of a particular warning.
+	//
+	// applyTimeout (-apply-timeout) is how long RunOperation lets the
+	// operation run before stopping it, and applyTimeoutGrace
+	// (-apply-timeout-grace) is how long it then waits for the operation to
+	// stop before canceling it. Zero applyTimeout means no timeout.
 	statePath           string
 	stateOutPath        string
 	backupPath          string
 	parallelism         int
+	providerParallelism map[string]int
+	refreshParallelism  int
 	stateLock           bool
 	stateLockTimeout    time.Duration
 	forceInitCopy       bool
 	reconfigure         bool
 	migrateState        bool
+	installJobs         int
 	compactWarnings     bool
 	consolidateWarnings bool
 	consolidateErrors   bool
+	applyTimeout        time.Duration
+	applyTimeoutGrace   time.Duration
+
+	// workspaceOverride (-workspace) selects a workspace for the current
+	// command only, taking precedence over both the TF_WORKSPACE environment
+	// variable and the workspace chosen by "tofu workspace select".
+	// createWorkspace is set if that workspace should be created when it
+	// doesn't already exist.
+	workspaceOverride string
+	createWorkspace   bool
 
 	// Used with commands which write state to allow users to write remote
 	// state even if the remote and local OpenTofu versions don't match.
@@ -530,8 +601,18 @@ func (m *Meta) RunOperation(ctx context.Context, b backend.Enhanced, opReq *back
 		return nil, diags.Append(fmt.Errorf("error starting operation: %w", err))
 	}
 
-	// Wait for the operation to complete or an interrupt to occur
+	var timeoutCh <-chan time.Time
+	if m.applyTimeout > 0 {
+		timer := time.NewTimer(m.applyTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	// Wait for the operation to complete, an interrupt to occur, or the
+	// timeout to be reached
 	select {
+	case <-timeoutCh:
+		return m.stopTimedOutOperation(op, opReq, diags)
 	case <-m.ShutdownCh:
 		// gracefully stop the operation
 		op.Stop()
@@ -566,6 +647,55 @@ func (m *Meta) RunOperation(ctx context.Context, b backend.Enhanced, opReq *back
 	return op, diags
 }
 
+// stopTimedOutOperation gracefully stops an operation that has reached the
+// apply timeout, so that the changes in progress can complete and their
+// results can be saved in the state before the lock is released. If they
+// don't complete within the grace period then the operation is canceled.
+func (m *Meta) stopTimedOutOperation(op *backend.RunningOperation, opReq *backend.Operation, diags tfdiags.Diagnostics) (*backend.RunningOperation, tfdiags.Diagnostics) {
+	grace := m.applyTimeoutGrace
+	if grace <= 0 {
+		grace = arguments.DefaultApplyTimeoutGrace
+	}
+	log.Printf("[WARN] Operation reached the apply timeout of %s; stopping it", m.applyTimeout)
+
+	op.Stop()
+	opReq.View.Diagnostics(tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Apply timeout reached",
+		fmt.Sprintf("The operation has been running for longer than -apply-timeout=%s, so OpenTofu won't start any more changes. Waiting up to %s for the changes in progress to complete...", m.applyTimeout, grace),
+	)))
+
+	graceTimer := time.NewTimer(grace)
+	defer graceTimer.Stop()
+
+	select {
+	case <-op.Done():
+		return op, diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Apply timed out",
+			fmt.Sprintf("OpenTofu stopped the operation because it took longer than -apply-timeout=%s. The state includes the results of all of the changes that completed, so you can run OpenTofu again to apply the remaining changes.", m.applyTimeout),
+		), tfdiags.ErrorCodeApplyTimeout))
+	case <-graceTimer.C:
+	case <-m.ShutdownCh:
+		// An interrupt during the grace period cancels straight away.
+	}
+
+	op.Cancel()
+
+	// The operation should return as soon as possible, but we don't wait
+	// forever in case a provider doesn't respond.
+	select {
+	case <-op.Done():
+	case <-time.After(5 * time.Second):
+	}
+
+	return nil, diags.Append(tfdiags.WithErrorCode(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Apply timed out",
+		fmt.Sprintf("OpenTofu canceled the operation because it took longer than -apply-timeout=%s and the changes in progress didn't complete within %s. The state may not include the results of the changes that were canceled, so check the affected remote objects before running OpenTofu again.", m.applyTimeout, grace),
+	), tfdiags.ErrorCodeApplyTimeout))
+}
+
 // contextOpts returns the options to use to initialize a OpenTofu
 // context with the settings from this Meta.
 func (m *Meta) contextOpts(ctx context.Context) (*tofu.ContextOpts, error) {
@@ -578,6 +708,7 @@ func (m *Meta) contextOpts(ctx context.Context) (*tofu.ContextOpts, error) {
 
 	opts.UIInput = m.UIInput()
 	opts.Parallelism = m.parallelism
+	opts.RefreshParallelism = m.refreshParallelism
 
 	// If testingOverrides are set, we'll skip the plugin discovery process
 	// and just work with what we've been given, thus allowing the tests
@@ -753,6 +884,10 @@ func (m *Meta) showDiagnostics(vals ...interface{}) {
 		return
 	}
 
+	if m.View != nil {
+		m.View.RecordErrorCode(diags)
+	}
+
 	if m.outputInJSON {
 		jsonView := views.NewJSONView(m.View)
 		jsonView.Diagnostics(diags)
@@ -819,11 +954,16 @@ const WorkspaceNameEnvVar = "TF_WORKSPACE"
 
 var errInvalidWorkspaceNameEnvVar = fmt.Errorf("Invalid workspace name set using %s", WorkspaceNameEnvVar)
 
+var errInvalidWorkspaceNameFlag = fmt.Errorf("Invalid workspace name set using -workspace")
+
 // Workspace returns the name of the currently configured workspace, corresponding
 // to the desired named state.
 func (m *Meta) Workspace(ctx context.Context) (string, error) {
 	current, overridden := m.WorkspaceOverridden(ctx)
 	if overridden && !validWorkspaceName(current) {
+		if m.workspaceOverride != "" {
+			return "", errInvalidWorkspaceNameFlag
+		}
 		return "", errInvalidWorkspaceNameEnvVar
 	}
 	return current, nil
@@ -831,8 +971,12 @@ func (m *Meta) Workspace(ctx context.Context) (string, error) {
 
 // WorkspaceOverridden returns the name of the currently configured workspace,
 // corresponding to the desired named state, as well as a bool saying whether
-// this was set via the TF_WORKSPACE environment variable.
+// this was set via the -workspace option or the TF_WORKSPACE environment
+// variable.
 func (m *Meta) WorkspaceOverridden(_ context.Context) (string, bool) {
+	if m.workspaceOverride != "" {
+		return m.workspaceOverride, true
+	}
 	if envVar := os.Getenv(WorkspaceNameEnvVar); envVar != "" {
 		return envVar, true
 	}
@@ -878,9 +1022,22 @@ func isAutoVarFile(path string) bool {
 func (m *Meta) applyStateArguments(args *arguments.State) {
 	m.stateLock = args.Lock
 	m.stateLockTimeout = args.LockTimeout
+	if !args.LockTimeoutSet {
+		m.stateLockTimeout = m.LockQueueTimeout
+	}
 	m.statePath = args.StatePath
 	m.stateOutPath = args.StateOutPath
 	m.backupPath = args.BackupPath
+	m.workspaceOverride = args.Workspace
+	m.createWorkspace = args.CreateWorkspace
+}
+
+// newStateLocker returns a state locker that uses the configured lock timeout
+// and reports lock events for the state of the given workspace to
+// m.LockNotifier. The workspace is empty for locks on states that don't
+// belong to a workspace.
+func (m *Meta) newStateLocker(view views.StateLocker, workspace string) clistate.Locker {
+	return clistate.NewNotifyingLocker(m.stateLockTimeout, view, m.LockNotifier, workspace)
 }
 
 // checkRequiredVersion loads the config and check if the
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -221,6 +222,14 @@ func (m *Meta) Backend(ctx context.Context, opts *BackendOpts, enc encryption.St
 // if the currently selected workspace is valid. If not, it will ask
 // the user to select a workspace from the list.
 func (m *Meta) selectWorkspace(ctx context.Context, b backend.Backend) error {
+	if m.workspaceOverride != "" {
+		// The workspace given with -workspace applies only to the current
+		// command, so we mustn't replace the selected workspace here. It is
+		// checked separately by prepareWorkspaceOverride.
+		log.Printf("[TRACE] Meta.selectWorkspace: using the workspace given with -workspace (%s)", m.workspaceOverride)
+		return nil
+	}
+
 	workspaces, err := b.Workspaces(ctx)
 	if err == backend.ErrWorkspacesNotSupported {
 		return nil
@@ -302,6 +311,68 @@ func (m *Meta) selectWorkspace(ctx context.Context, b backend.Backend) error {
 	return m.SetWorkspace(workspace)
 }
 
+// prepareWorkspaceOverride checks that the workspace selected with the
+// -workspace option exists in the given backend, creating it first if the
+// option included the "create" suffix. It does nothing if the option wasn't
+// used.
+//
+// Unlike "tofu workspace new", this never changes the workspace selected in
+// the working directory.
+func (m *Meta) prepareWorkspaceOverride(ctx context.Context, b backend.Backend) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if m.workspaceOverride == "" {
+		return diags
+	}
+
+	workspace, err := m.Workspace(ctx)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid workspace name",
+			fmt.Sprintf("%s. Workspace names must be valid URL path segments.", err),
+		))
+		return diags
+	}
+
+	workspaces, err := b.Workspaces(ctx)
+	if err == backend.ErrWorkspacesNotSupported {
+		if workspace != backend.DefaultStateName {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Workspaces not supported",
+				fmt.Sprintf("The configured backend doesn't support multiple workspaces, so -workspace can only select the %q workspace.", backend.DefaultStateName),
+			))
+		}
+		return diags
+	}
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to get existing workspaces: %w", err))
+		return diags
+	}
+	if slices.Contains(workspaces, workspace) {
+		return diags
+	}
+
+	if !m.createWorkspace {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Workspace doesn't exist",
+			fmt.Sprintf("The workspace %q given with -workspace doesn't exist. To create it for this command, use -workspace=%s,create.", workspace, workspace),
+		))
+		return diags
+	}
+
+	log.Printf("[TRACE] Meta.prepareWorkspaceOverride: creating workspace %q", workspace)
+	if _, err := b.StateMgr(ctx, workspace); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to create workspace",
+			fmt.Sprintf("Couldn't create the workspace %q given with -workspace: %s.", workspace, err),
+		))
+	}
+	return diags
+}
+
 // BackendForLocalPlan is similar to Backend, but uses backend settings that were
 // stored in a plan.
 //
@@ -430,7 +501,7 @@ func (m *Meta) Operation(ctx context.Context, b backend.Backend, vt arguments.Vi
 	stateLocker := clistate.NewNoopLocker()
 	if m.stateLock {
 		view := views.NewStateLocker(vt, m.View)
-		stateLocker = clistate.NewLocker(m.stateLockTimeout, view)
+		stateLocker = m.newStateLocker(view, workspace)
 	}
 
 	depLocks, diags := m.lockedDependencies()
@@ -443,17 +514,26 @@ func (m *Meta) Operation(ctx context.Context, b backend.Backend, vt arguments.Vi
 		log.Printf("[WARN] Failed to load dependency locks while preparing backend operation (ignored): %s", diags.Err().Error())
 	}
 
-	return &backend.Operation{
-		Encryption:      enc,
-		PlanOutBackend:  planOutBackend,
-		Targets:         m.targets,
-		Excludes:        m.excludes,
-		UIIn:            m.UIInput(),
-		UIOut:           m.Ui,
-		Workspace:       workspace,
-		StateLocker:     stateLocker,
-		DependencyLocks: depLocks,
-	}
+	op := &backend.Operation{
+		Encryption:          enc,
+		PlanOutBackend:      planOutBackend,
+		Targets:             m.targets,
+		Excludes:            m.excludes,
+		UIIn:                m.UIInput(),
+		UIOut:               m.Ui,
+		Workspace:           workspace,
+		StateLocker:         stateLocker,
+		DependencyLocks:     depLocks,
+		ProviderParallelism: m.providerParallelism,
+		RunHooks:            m.RunHooks,
+		RegoPolicies:        m.RegoPolicies,
+	}
+	// Only the human-oriented plan rendering includes the cost estimate, so
+	// we don't run the estimator for the machine-readable UI.
+	if vt == arguments.ViewHuman {
+		op.CostEstimator = m.CostEstimator
+	}
+	return op
 }
 
 // backendConfig returns the local configuration for the backend
@@ -1062,7 +1142,7 @@ func (m *Meta) backend_C_r_s(ctx context.Context, c *configs.Backend, cHash int,
 
 	if m.stateLock {
 		view := views.NewStateLocker(vt, m.View)
-		stateLocker := clistate.NewLocker(m.stateLockTimeout, view)
+		stateLocker := m.newStateLocker(view, "")
 		if d := stateLocker.Lock(sMgr, "backend from plan"); d != nil {
 			diags = diags.Append(fmt.Errorf("Error locking state: %s", d))
 			return nil, diags
@@ -1206,7 +1286,7 @@ func (m *Meta) backend_C_r_S_changed(ctx context.Context, c *configs.Backend, cH
 
 		if m.stateLock {
 			view := views.NewStateLocker(vt, m.View)
-			stateLocker := clistate.NewLocker(m.stateLockTimeout, view)
+			stateLocker := m.newStateLocker(view, "")
 			if d := stateLocker.Lock(sMgr, "backend from plan"); d != nil {
 				diags = diags.Append(fmt.Errorf("Error locking state: %s", d))
 				return nil, diags
@@ -20,7 +20,6 @@ import (
 	"github.com/opentofu/opentofu/internal/backend/remote"
 	"github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
@@ -354,15 +353,13 @@ func (m *Meta) backendMigrateState_s_s(ctx context.Context, opts *backendMigrate
 			vt = arguments.ViewHuman
 		}
 		view := views.NewStateLocker(vt, m.View)
-		locker := clistate.NewLocker(m.stateLockTimeout, view)
-
-		lockerSource := locker.WithContext(lockCtx)
+		lockerSource := m.newStateLocker(view, opts.sourceWorkspace).WithContext(lockCtx)
 		if diags := lockerSource.Lock(sourceState, "migration source state"); diags.HasErrors() {
 			return diags.Err()
 		}
 		defer lockerSource.Unlock()
 
-		lockerDestination := locker.WithContext(lockCtx)
+		lockerDestination := m.newStateLocker(view, opts.destinationWorkspace).WithContext(lockCtx)
 		if diags := lockerDestination.Lock(destinationState, "migration destination state"); diags.HasErrors() {
 			return diags.Err()
 		}
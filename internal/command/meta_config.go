@@ -291,6 +291,7 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 	}
 
 	inst := initwd.NewModuleInstaller(m.modulesDir(), loader, m.registryClient(ctx), m.ModulePackageFetcher)
+	inst.SetConcurrency(m.installJobs)
 
 	call, vDiags := m.rootModuleCall(ctx, rootDir)
 	diags = diags.Append(vDiags)
@@ -310,6 +311,33 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 	return false, diags
 }
 
+// moduleUpgrades reports which version of each remote module called from the
+// configuration in rootDir would be installed by installModules with the
+// upgrade flag set, without installing anything or creating the local
+// modules directory.
+func (m *Meta) moduleUpgrades(ctx context.Context, rootDir, testsDir string) ([]initwd.ModuleUpgrade, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	rootDir = m.normalizePath(rootDir)
+
+	loader, err := m.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+
+	inst := initwd.NewModuleInstaller(m.modulesDir(), loader, m.registryClient(ctx), m.ModulePackageFetcher)
+
+	call, vDiags := m.rootModuleCall(ctx, rootDir)
+	diags = diags.Append(vDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	upgrades, moreDiags := inst.ModuleUpgrades(ctx, rootDir, testsDir, call)
+	diags = diags.Append(moreDiags)
+	return upgrades, diags
+}
+
 // initDirFromModule initializes the given directory (which should be
 // pre-verified as empty by the caller) by copying the source code from the
 // given module address.
@@ -51,3 +51,22 @@ func (m *Meta) PlanFile(path string, enc encryption.PlanEncryption) (*planfile.W
 
 	return planfile.OpenWrapped(path, enc)
 }
+
+// readPlanFile reads the contents of the plan file at the given path, for
+// loading with planfile.OpenWrappedBytes.
+//
+// If the return value and error are both nil, the given path exists but seems
+// to be a configuration directory instead.
+func (m *Meta) readPlanFile(path string) ([]byte, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		// Looks like a configuration directory.
+		return nil, nil
+	}
+
+	return os.ReadFile(path)
+}
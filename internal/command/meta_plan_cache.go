@@ -0,0 +1,176 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// planCacheFormatVersion is the version of the format of the files saved by
+// "tofu plan -incremental". A file with any other version is ignored, which
+// just makes the next incremental plan a full one.
+const planCacheFormatVersion = "1.0"
+
+type planCacheFile struct {
+	FormatVersion string                        `json:"format_version"`
+	Entries       map[string]planCacheFileEntry `json:"entries"`
+}
+
+type planCacheFileEntry struct {
+	Fingerprint      string `json:"fingerprint"`
+	LegacyTypeSystem bool   `json:"legacy_type_system,omitempty"`
+}
+
+// planCacheFilename returns the path of the file where "tofu plan
+// -incremental" saves the resource instances that had no changes, for the
+// currently selected workspace.
+func (m *Meta) planCacheFilename(ctx context.Context) (string, error) {
+	workspace, err := m.Workspace(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(m.DataDir(), "plan-cache", workspace+".json"), nil
+}
+
+// loadPlanCache returns a plan cache with the entries saved by the previous
+// incremental plan in the current workspace. If there was no previous
+// incremental plan, or its entries can't be used, the cache is empty.
+func (m *Meta) loadPlanCache(ctx context.Context) (*tofu.PlanCache, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	filename, err := m.planCacheFilename(ctx)
+	if err != nil {
+		return nil, diags.Append(err)
+	}
+	locks, moreDiags := m.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	providers := m.planCacheProviders(locks)
+
+	src, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return tofu.NewPlanCache(nil, providers), diags
+	} else if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to read the incremental plan cache",
+			fmt.Sprintf("Could not read %s, so every resource instance will be planned: %s.", filename, err),
+		))
+		return tofu.NewPlanCache(nil, providers), diags
+	}
+
+	var file planCacheFile
+	if err := json.Unmarshal(src, &file); err != nil || file.FormatVersion != planCacheFormatVersion {
+		// The cache is only an optimization, so an unusable one just means
+		// that this plan will be a full one.
+		return tofu.NewPlanCache(nil, providers), diags
+	}
+	entries := make(map[string]tofu.PlanCacheEntry, len(file.Entries))
+	for addr, entry := range file.Entries {
+		entries[addr] = tofu.PlanCacheEntry{
+			Fingerprint:      entry.Fingerprint,
+			LegacyTypeSystem: entry.LegacyTypeSystem,
+		}
+	}
+	return tofu.NewPlanCache(entries, providers), diags
+}
+
+// planCacheProviders identifies the installed package of each provider for the
+// plan cache: the version and checksums of each provider in the given
+// dependency locks, and the OpenTofu version for the built-in providers.
+// Providers with development overrides and unmanaged providers are left out,
+// because their packages can change without changing the locks, so their
+// resource instances are always planned.
+func (m *Meta) planCacheProviders(locks *depsfile.Locks) map[addrs.Provider]string {
+	ret := make(map[addrs.Provider]string)
+	for name := range m.internalProviders() {
+		ret[addrs.NewBuiltInProvider(name)] = "built-in " + version.String()
+	}
+	for addr, lock := range locks.AllProviders() {
+		_, devOverride := m.ProviderDevOverrides[addr]
+		_, unmanaged := m.UnmanagedProviders[addr]
+		if devOverride || unmanaged {
+			continue
+		}
+		hashes := make([]string, 0, len(lock.AllHashes()))
+		for _, hash := range lock.AllHashes() {
+			hashes = append(hashes, hash.String())
+		}
+		sort.Strings(hashes)
+		ret[addr] = lock.Version().String() + " " + strings.Join(hashes, " ")
+	}
+	return ret
+}
+
+// savePlanCache saves the entries recorded by an incremental plan for the
+// next one in the current workspace.
+func (m *Meta) savePlanCache(ctx context.Context, cache *tofu.PlanCache) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	filename, err := m.planCacheFilename(ctx)
+	if err != nil {
+		return diags.Append(err)
+	}
+
+	file := planCacheFile{
+		FormatVersion: planCacheFormatVersion,
+		Entries:       make(map[string]planCacheFileEntry),
+	}
+	for addr, entry := range cache.Entries() {
+		file.Entries[addr] = planCacheFileEntry{
+			Fingerprint:      entry.Fingerprint,
+			LegacyTypeSystem: entry.LegacyTypeSystem,
+		}
+	}
+	src, err := json.Marshal(file)
+	if err == nil {
+		err = writePlanCacheFile(filename, src)
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to save the incremental plan cache",
+			fmt.Sprintf("Could not write %s, so the next incremental plan will plan every resource instance: %s.", filename, err),
+		))
+	}
+	return diags
+}
+
+// writePlanCacheFile writes to a temporary file first, so that an
+// interrupted plan never leaves a partially-written cache behind.
+func writePlanCacheFile(filename string, src []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), ".tmp-"+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// signPlanFile writes a signature for the saved plan file at the given path,
+// if the CLI configuration includes a plan signing key.
+func (m *Meta) signPlanFile(path string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !m.PlanSigning.CanSign() {
+		return diags
+	}
+	if err := planfile.Sign(path, m.PlanSigning); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to sign plan file",
+			fmt.Sprintf("The plan was saved to %s, but OpenTofu could not sign it: %s.", path, err),
+		))
+		return diags
+	}
+	log.Printf("[INFO] Signed saved plan file %s", path)
+	return diags
+}
+
+// verifyPlanFileSignature checks the signature of the saved plan file at the
+// given path, whose contents are plan, before it is applied.
+//
+// A signature that doesn't match is always an error when there are keys to
+// check it against, but a plan file without a signature is only rejected if
+// signed plans are required, either by the CLI configuration or because
+// required is set.
+func (m *Meta) verifyPlanFileSignature(path string, plan []byte, required bool) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	required = required || m.RequireSignedPlans
+
+	if !m.PlanSigning.CanVerify() {
+		if required {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"No keys to verify plan signature",
+				"A signed plan file is required, but the CLI configuration has no plan_signing block with keys to verify the signature against.",
+			))
+		}
+		return diags
+	}
+
+	signer, err := planfile.VerifySignatureBytes(path, plan, m.PlanSigning)
+	switch {
+	case errors.Is(err, planfile.ErrNotSigned):
+		if required {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Plan file is not signed",
+				fmt.Sprintf("A signed plan file is required, but there is no signature file %s for the plan file %s.", planfile.SignatureFilename(path), path),
+			))
+		}
+	case err != nil:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid plan file signature",
+			fmt.Sprintf("OpenTofu will not apply %s because its signature could not be verified: %s.\n\nThe plan file may have been modified since it was created, or it may have been signed with a key that is not trusted by the CLI configuration.", path, err),
+		))
+	default:
+		log.Printf("[INFO] Saved plan file %s has a valid signature from key %s", path, signer.PrimaryKey.KeyIdString())
+	}
+	return diags
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/pprof"
+
+	"github.com/opentofu/opentofu/internal/perf"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// startProfile starts recording the profiles requested with the -profile and
+// -profile-cpu options of the given command, if any. It returns a context
+// that carries the performance profile to the operation, and a function that
+// stops recording and writes the profiles, which must be called once the
+// command is done.
+//
+// The operation has already finished by the time the profiles are written,
+// so a failure to write them is only reported as a warning.
+func (m *Meta) startProfile(ctx context.Context, command, path, cpuPath string) (context.Context, func() tfdiags.Diagnostics, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if path == "" && cpuPath == "" {
+		return ctx, func() tfdiags.Diagnostics { return nil }, diags
+	}
+
+	var cpuFile *os.File
+	if cpuPath != "" {
+		f, err := os.Create(cpuPath)
+		if err == nil {
+			err = pprof.StartCPUProfile(f)
+			if err != nil {
+				f.Close()
+			}
+		}
+		if err != nil {
+			return ctx, nil, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to start CPU profile",
+				fmt.Sprintf("Could not start writing a CPU profile to %s: %s.", cpuPath, err),
+			))
+		}
+		cpuFile = f
+	}
+
+	profile := perf.NewProfile()
+	stop := func() tfdiags.Diagnostics {
+		var diags tfdiags.Diagnostics
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Failed to write CPU profile",
+					fmt.Sprintf("Could not write the CPU profile to %s: %s.", cpuPath, err),
+				))
+			}
+		}
+		if path != "" {
+			report := profile.Report(command)
+			report.CPUProfile = cpuPath
+			if err := report.Save(path); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Failed to write performance profile",
+					fmt.Sprintf("Could not write the performance profile to %s: %s.", path, err),
+				))
+			}
+		}
+		return diags
+	}
+	return perf.ContextWithProfile(ctx, profile), stop, diags
+}
@@ -82,6 +82,7 @@ func (m *Meta) providerInstallerCustomSource(source getproviders.Source) *provid
 		unmanagedProviderTypes[ty] = struct{}{}
 	}
 	inst.SetUnmanagedProviderTypes(unmanagedProviderTypes)
+	inst.SetConcurrency(m.installJobs)
 	return inst
 }
 
@@ -324,7 +325,11 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 				continue
 			}
 		}
-		factories[provider] = providerFactory(cached)
+		factory := providerFactory(cached)
+		if m.ProviderSchemaCache {
+			factory = m.schemaCachingProviderFactory(provider, version, lock.PreferredHashes(), factory)
+		}
+		factories[provider] = factory
 	}
 	for provider, localDir := range devOverrideProviders {
 		factories[provider] = devOverrideProviderFactory(provider, localDir)
@@ -0,0 +1,502 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/plugin6/convert"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tfplugin6"
+)
+
+// providerSchemaCacheDir returns the directory where "tofu init -cache-schemas"
+// saves the schemas of the selected providers, and where
+// "tofu validate -cached-schemas" looks for them.
+//
+// The directory is shared by all working directories, so that a schema cached
+// in one of them can be used to validate any configuration that uses the same
+// provider version, even in a working directory that isn't initialized.
+func (m *Meta) providerSchemaCacheDir() (string, error) {
+	if m.CLIConfigDir == "" {
+		return "", errors.New("the CLI configuration directory is not available")
+	}
+	return filepath.Join(m.CLIConfigDir, "provider-schemas"), nil
+}
+
+// providerSchemaCacheFile returns the path of the cached schema for the given
+// provider version, which uses the same directory structure as the provider
+// plugin cache.
+func providerSchemaCacheFile(dir string, provider addrs.Provider, version getproviders.Version) string {
+	return filepath.Join(providerSchemaCacheProviderDir(dir, provider), version.String()+".json")
+}
+
+func providerSchemaCacheProviderDir(dir string, provider addrs.Provider) string {
+	return filepath.Join(dir, provider.Hostname.ForDisplay(), provider.Namespace, provider.Type)
+}
+
+// providerSchemaPackageCacheFile returns the path of the cached schema for
+// the package of the given provider version that matches the given
+// checksums from the dependency lock file.
+//
+// Unlike the schemas saved by "tofu init -cache-schemas", which are keyed
+// only by version, these are used instead of asking the provider itself, so
+// they're also keyed by the checksums that the installed package was
+// verified against, in case a version is ever republished.
+func providerSchemaPackageCacheFile(dir string, provider addrs.Provider, version getproviders.Version, hashes []getproviders.Hash) string {
+	sorted := make([]string, len(hashes))
+	for i, hash := range hashes {
+		sorted[i] = hash.String()
+	}
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return filepath.Join(providerSchemaCacheProviderDir(dir, provider), version.String(), hex.EncodeToString(sum[:])+".json")
+}
+
+// schemaCachingProviderFactory wraps the factory of an installed provider
+// package so that its schema is loaded from the provider schema cache, if
+// it's there, and saved there otherwise when a provider instance returns it.
+//
+// Loading a cached schema puts it in the global provider schema cache, which
+// lets OpenTofu Core use it without starting the provider just to fetch its
+// schema. Provider instances that are started anyway still get asked for
+// their schema if they don't declare that the request is optional.
+//
+// Packages without checksums in the dependency lock file can't be
+// identified reliably, so their schemas are never cached.
+func (m *Meta) schemaCachingProviderFactory(provider addrs.Provider, version getproviders.Version, hashes []getproviders.Hash, factory providers.Factory) providers.Factory {
+	dir, err := m.providerSchemaCacheDir()
+	if err != nil || len(hashes) == 0 {
+		return factory
+	}
+	filename := providerSchemaPackageCacheFile(dir, provider, version, hashes)
+
+	if _, ok := providers.SchemaCache.Get(provider); !ok {
+		schema, err := readProviderSchemaCache(filename)
+		switch {
+		case err == nil:
+			log.Printf("[TRACE] Meta.schemaCachingProviderFactory: loaded schema for %s v%s from %s", provider, version, filename)
+			providers.SchemaCache.Set(provider, schema)
+		case !errors.Is(err, os.ErrNotExist):
+			log.Printf("[WARN] Ignoring cached schema for %s v%s: %s", provider, version, err)
+		}
+	}
+
+	var once sync.Once
+	save := func(schema providers.ProviderSchema) {
+		once.Do(func() {
+			if _, err := os.Stat(filename); err == nil {
+				return
+			}
+			if err := writeProviderSchemaCache(filename, schema); err != nil {
+				// The cache only saves time, so failing to write to it
+				// mustn't fail the command.
+				log.Printf("[WARN] Failed to cache schema for %s v%s: %s", provider, version, err)
+			}
+		})
+	}
+	return func() (providers.Interface, error) {
+		p, err := factory()
+		if err != nil {
+			return nil, err
+		}
+		return &schemaCachingProvider{Interface: p, save: save}, nil
+	}
+}
+
+// schemaCachingProvider is a providers.Interface that saves the first
+// schema that the wrapped provider returns without errors.
+type schemaCachingProvider struct {
+	providers.Interface
+	save func(providers.ProviderSchema)
+}
+
+func (p *schemaCachingProvider) GetProviderSchema(ctx context.Context) providers.GetProviderSchemaResponse {
+	resp := p.Interface.GetProviderSchema(ctx)
+	if !resp.Diagnostics.HasErrors() {
+		p.save(resp)
+	}
+	return resp
+}
+
+// cacheProviderSchemas saves the schemas of all of the providers selected in
+// the dependency lock file to the provider schema cache.
+func (m *Meta) cacheProviderSchemas(ctx context.Context) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	dir, err := m.providerSchemaCacheDir()
+	if err != nil {
+		return diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to cache provider schemas",
+			fmt.Sprintf("Cannot determine where to save provider schemas: %s.", err),
+		))
+	}
+
+	locks, moreDiags := m.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	var factories map[addrs.Provider]providers.Factory
+	if m.testingOverrides != nil {
+		factories = m.testingOverrides.Providers
+	} else {
+		factories, err = m.providerFactories()
+		if err != nil {
+			return diags.Append(err)
+		}
+	}
+
+	providerLocks := locks.AllProviders()
+	selected := make([]addrs.Provider, 0, len(providerLocks))
+	for addr := range providerLocks {
+		// Development builds don't necessarily match the selected version,
+		// so their schemas mustn't be cached under it.
+		_, devOverride := m.ProviderDevOverrides[addr]
+		_, unmanaged := m.UnmanagedProviders[addr]
+		if !devOverride && !unmanaged {
+			selected = append(selected, addr)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].LessThan(selected[j])
+	})
+
+	for _, addr := range selected {
+		version := providerLocks[addr].Version()
+		factory, ok := factories[addr]
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to cache provider schema",
+				fmt.Sprintf("Provider %s v%s is not available.", addr.ForDisplay(), version),
+			))
+			continue
+		}
+		schema, err := loadProviderSchema(ctx, factory)
+		if err == nil {
+			err = writeProviderSchemaCache(providerSchemaCacheFile(dir, addr, version), schema)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to cache provider schema",
+				fmt.Sprintf("Could not cache the schema of provider %s v%s: %s.", addr.ForDisplay(), version, err),
+			))
+		}
+	}
+	return diags
+}
+
+func loadProviderSchema(ctx context.Context, factory providers.Factory) (providers.ProviderSchema, error) {
+	provider, err := factory()
+	if err != nil {
+		return providers.ProviderSchema{}, err
+	}
+	defer provider.Close(ctx)
+
+	schema := provider.GetProviderSchema(ctx)
+	if schema.Diagnostics.HasErrors() {
+		return schema, schema.Diagnostics.Err()
+	}
+	return schema, nil
+}
+
+// writeProviderSchemaCache saves the given schema as the JSON encoding of the
+// plugin protocol's GetProviderSchema response, which is a stable format that
+// can represent everything the schema contains.
+func writeProviderSchemaCache(filename string, schema providers.ProviderSchema) error {
+	resp := &tfplugin6.GetProviderSchema_Response{
+		Provider:          providerSchemaToProto(schema.Provider),
+		ProviderMeta:      providerSchemaToProto(schema.ProviderMeta),
+		ResourceSchemas:   make(map[string]*tfplugin6.Schema, len(schema.ResourceTypes)),
+		DataSourceSchemas: make(map[string]*tfplugin6.Schema, len(schema.DataSources)),
+		Functions:         make(map[string]*tfplugin6.Function, len(schema.Functions)),
+		ServerCapabilities: &tfplugin6.ServerCapabilities{
+			PlanDestroy:               schema.ServerCapabilities.PlanDestroy,
+			GetProviderSchemaOptional: schema.ServerCapabilities.GetProviderSchemaOptional,
+		},
+	}
+	for name, s := range schema.ResourceTypes {
+		resp.ResourceSchemas[name] = providerSchemaToProto(s)
+	}
+	for name, s := range schema.DataSources {
+		resp.DataSourceSchemas[name] = providerSchemaToProto(s)
+	}
+	for name, fn := range schema.Functions {
+		resp.Functions[name] = convert.FunctionSpecToProto(fn)
+	}
+
+	src, err := protojson.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	// We write to a temporary file first, so that a concurrent validate
+	// never sees a partially-written schema.
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), ".tmp-"+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+func providerSchemaToProto(s providers.Schema) *tfplugin6.Schema {
+	if s.Block == nil {
+		return nil
+	}
+	return &tfplugin6.Schema{
+		Version: s.Version,
+		Block:   convert.ConfigSchemaToProto(s.Block),
+	}
+}
+
+func readProviderSchemaCache(filename string) (providers.ProviderSchema, error) {
+	schema := providers.ProviderSchema{
+		ResourceTypes: make(map[string]providers.Schema),
+		DataSources:   make(map[string]providers.Schema),
+		Functions:     make(map[string]providers.FunctionSpec),
+	}
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return schema, err
+	}
+	var resp tfplugin6.GetProviderSchema_Response
+	if err := protojson.Unmarshal(src, &resp); err != nil {
+		return schema, fmt.Errorf("invalid cached schema %s: %w", filename, err)
+	}
+
+	if resp.Provider != nil {
+		schema.Provider = convert.ProtoToProviderSchema(resp.Provider)
+	}
+	if resp.ProviderMeta != nil {
+		schema.ProviderMeta = convert.ProtoToProviderSchema(resp.ProviderMeta)
+	}
+	for name, s := range resp.ResourceSchemas {
+		schema.ResourceTypes[name] = convert.ProtoToProviderSchema(s)
+	}
+	for name, s := range resp.DataSourceSchemas {
+		schema.DataSources[name] = convert.ProtoToProviderSchema(s)
+	}
+	for name, fn := range resp.Functions {
+		schema.Functions[name] = convert.ProtoToFunctionSpec(fn)
+	}
+	if resp.ServerCapabilities != nil {
+		schema.ServerCapabilities.PlanDestroy = resp.ServerCapabilities.PlanDestroy
+		schema.ServerCapabilities.GetProviderSchemaOptional = resp.ServerCapabilities.GetProviderSchemaOptional
+	}
+	return schema, nil
+}
+
+// cachedProviderSchemaVersions returns the versions of the given provider
+// that have a schema in the provider schema cache.
+func cachedProviderSchemaVersions(dir string, provider addrs.Provider) getproviders.VersionList {
+	entries, err := os.ReadDir(providerSchemaCacheProviderDir(dir, provider))
+	if err != nil {
+		return nil
+	}
+	var ret getproviders.VersionList
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if version, err := getproviders.ParseVersion(name); err == nil {
+			ret = append(ret, version)
+		}
+	}
+	ret.Sort()
+	return ret
+}
+
+// cachedSchemaProviderFactories returns factories for all of the providers
+// required by the given configuration that serve only their schemas from the
+// provider schema cache, so that the configuration can be validated without
+// installing or running them.
+//
+// The cached schema for each provider is for the version selected in the
+// dependency lock file, if there is one, or otherwise for the newest cached
+// version that meets the configuration's version constraints.
+func (m *Meta) cachedSchemaProviderFactories(config *configs.Config) (map[addrs.Provider]providers.Factory, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	dir, err := m.providerSchemaCacheDir()
+	if err != nil {
+		return nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider schema cache not available",
+			fmt.Sprintf("Cannot determine where provider schemas are cached: %s.", err),
+		))
+	}
+
+	reqs, _, hclDiags := config.ProviderRequirements()
+	diags = diags.Append(hclDiags)
+	locks, moreDiags := m.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	factories := make(map[addrs.Provider]providers.Factory)
+	for name, factory := range m.internalProviders() {
+		factories[addrs.NewBuiltInProvider(name)] = factory
+	}
+	for provider, constraints := range reqs {
+		if provider.IsBuiltIn() {
+			continue
+		}
+
+		var version getproviders.Version
+		if lock := locks.Provider(provider); lock != nil {
+			version = lock.Version()
+		} else {
+			allowed := getproviders.MeetingConstraints(constraints)
+			version = cachedProviderSchemaVersions(dir, provider).NewestInSet(allowed)
+		}
+		if version == getproviders.UnspecifiedVersion {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Provider schema not cached",
+				fmt.Sprintf("There is no cached schema for a version of provider %s that meets the configuration's version constraints. Run \"tofu init -cache-schemas\" in any working directory that uses a suitable version of the provider to cache its schema.", provider.ForDisplay()),
+			))
+			continue
+		}
+
+		schema, err := readProviderSchemaCache(providerSchemaCacheFile(dir, provider, version))
+		if errors.Is(err, os.ErrNotExist) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Provider schema not cached",
+				fmt.Sprintf("There is no cached schema for provider %s v%s, which is selected in the dependency lock file. Run \"tofu init -cache-schemas\" in any working directory that uses this version of the provider to cache its schema.", provider.ForDisplay(), version),
+			))
+			continue
+		} else if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read cached provider schema",
+				fmt.Sprintf("Could not read the cached schema for provider %s v%s: %s.", provider.ForDisplay(), version, err),
+			))
+			continue
+		}
+		factories[provider] = func() (providers.Interface, error) {
+			return &cachedSchemaProvider{schema: schema}, nil
+		}
+	}
+	return factories, diags
+}
+
+// cachedSchemaProvider is a providers.Interface that only knows the schema of
+// a provider, which is enough for validating configuration against it. It
+// accepts any configuration that conforms to the schema, because it can't
+// run the provider's own validation.
+type cachedSchemaProvider struct {
+	schema providers.ProviderSchema
+}
+
+var _ providers.Interface = (*cachedSchemaProvider)(nil)
+
+var errCachedSchemaProvider = errors.New("only the provider's cached schema is available")
+
+func (p *cachedSchemaProvider) GetProviderSchema(context.Context) providers.GetProviderSchemaResponse {
+	return p.schema
+}
+
+func (p *cachedSchemaProvider) ValidateProviderConfig(_ context.Context, req providers.ValidateProviderConfigRequest) providers.ValidateProviderConfigResponse {
+	return providers.ValidateProviderConfigResponse{PreparedConfig: req.Config}
+}
+
+func (p *cachedSchemaProvider) ValidateResourceConfig(context.Context, providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	return providers.ValidateResourceConfigResponse{}
+}
+
+func (p *cachedSchemaProvider) ValidateDataResourceConfig(context.Context, providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse {
+	return providers.ValidateDataResourceConfigResponse{}
+}
+
+func (p *cachedSchemaProvider) MoveResourceState(context.Context, providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+	return providers.MoveResourceStateResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+// CallFunction returns an unknown value of the function's return type, since
+// the result can't be known without the provider.
+func (p *cachedSchemaProvider) CallFunction(_ context.Context, req providers.CallFunctionRequest) providers.CallFunctionResponse {
+	spec, ok := p.schema.Functions[req.Name]
+	if !ok {
+		return providers.CallFunctionResponse{Error: fmt.Errorf("function %q not found", req.Name)}
+	}
+	return providers.CallFunctionResponse{Result: cty.UnknownVal(spec.Return)}
+}
+
+func (p *cachedSchemaProvider) ConfigureProvider(context.Context, providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	return providers.ConfigureProviderResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) Close(context.Context) error {
+	return nil
+}
+
+func (p *cachedSchemaProvider) Stop(context.Context) error {
+	return nil
+}
+
+func (p *cachedSchemaProvider) UpgradeResourceState(context.Context, providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	return providers.UpgradeResourceStateResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) ReadResource(context.Context, providers.ReadResourceRequest) providers.ReadResourceResponse {
+	return providers.ReadResourceResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) PlanResourceChange(context.Context, providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	return providers.PlanResourceChangeResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) ApplyResourceChange(context.Context, providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	return providers.ApplyResourceChangeResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) ImportResourceState(context.Context, providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	return providers.ImportResourceStateResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) ReadDataSource(context.Context, providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	return providers.ReadDataSourceResponse{Diagnostics: tfdiags.Diagnostics{}.Append(errCachedSchemaProvider)}
+}
+
+func (p *cachedSchemaProvider) GetFunctions(context.Context) providers.GetFunctionsResponse {
+	return providers.GetFunctionsResponse{Functions: p.schema.Functions}
+}
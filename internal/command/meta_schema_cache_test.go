@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+func TestMeta_schemaCachingProviderFactory(t *testing.T) {
+	provider := addrs.NewDefaultProvider("test")
+	version := getproviders.MustParseVersion("1.2.3")
+	hashes := []getproviders.Hash{getproviders.HashScheme1.New("abc")}
+	t.Cleanup(func() {
+		providers.SchemaCache.Remove(provider)
+	})
+
+	m := &Meta{CLIConfigDir: t.TempDir()}
+	p := testProvider()
+	p.GetProviderSchemaResponse = planFixtureSchema()
+	factory := func() (providers.Interface, error) {
+		return p, nil
+	}
+
+	// Nothing is cached yet, so the first provider instance is asked for its
+	// schema, which is then saved.
+	providers.SchemaCache.Remove(provider)
+	instance, err := m.schemaCachingProviderFactory(provider, version, hashes, factory)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp := instance.GetProviderSchema(context.Background()); resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.Err())
+	}
+	if _, ok := providers.SchemaCache.Get(provider); ok {
+		t.Fatal("schema is in the global cache before it was loaded from disk")
+	}
+
+	// The next command loads the saved schema, so it doesn't need to start
+	// the provider to get it.
+	m.schemaCachingProviderFactory(provider, version, hashes, factory)
+	schema, ok := providers.SchemaCache.Get(provider)
+	if !ok {
+		t.Fatal("saved schema not loaded into the global cache")
+	}
+	if _, ok := schema.ResourceTypes["test_instance"]; !ok {
+		t.Errorf("wrong schema loaded: %#v", schema)
+	}
+
+	// A package with different checksums doesn't use the saved schema.
+	providers.SchemaCache.Remove(provider)
+	m.schemaCachingProviderFactory(provider, version, []getproviders.Hash{getproviders.HashScheme1.New("def")}, factory)
+	if _, ok := providers.SchemaCache.Get(provider); ok {
+		t.Error("schema loaded for a package with different checksums")
+	}
+}
@@ -6,6 +6,7 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -83,6 +84,10 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 	// search for all files ending in .auto.tfvars.
 	diags = diags.Append(m.addVarsFromDir(".", ret))
 
+	// The variables files for the selected workspace take precedence over
+	// the ones for all workspaces.
+	diags = diags.Append(m.addVarsFromWorkspaceDir(ret))
+
 	// Finally we process values given explicitly on the command line, either
 	// as individual literal settings or as additional files to read.
 	for _, rawFlag := range m.variableArgs.AllItems() {
@@ -163,6 +168,37 @@ func (m *Meta) addVarsFromDir(currDir string, ret map[string]backend.UnparsedVar
 	return diags
 }
 
+// addVarsFromWorkspaceDir loads all of the variables files in the selected
+// workspace's subdirectory of WorkspaceVarsDir, if it exists, in lexical
+// order. Unlike addVarsFromDir, it doesn't require the ".auto" suffix, since
+// the directory is only used for automatically-loaded files.
+func (m *Meta) addVarsFromWorkspaceDir(ret map[string]backend.UnparsedVariableValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	workspace, err := m.Workspace(context.TODO())
+	if err != nil || workspace == "." || workspace == ".." {
+		// An invalid workspace name is reported elsewhere.
+		return diags
+	}
+
+	dir := filepath.Join(WorkspaceVarsDir, workspace)
+	infos, err := os.ReadDir(dir)
+	if err != nil {
+		return diags
+	}
+	// "infos" is already sorted by name, so we just need to filter it here.
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !(strings.HasSuffix(name, DefaultVarsExtension) || strings.HasSuffix(name, DefaultVarsExtension+".json")) {
+			continue
+		}
+		moreDiags := m.addVarsFromFile(filepath.Join(dir, name), tofu.ValueFromAutoFile, ret)
+		diags = diags.Append(moreDiags)
+	}
+
+	return diags
+}
+
 func (m *Meta) addVarsFromFile(filename string, sourceType tofu.ValueSourceType, to map[string]backend.UnparsedVariableValue) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
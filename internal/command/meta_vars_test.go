@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
 
@@ -76,3 +77,53 @@ func TestMeta_addVarsFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestMeta_collectVariableValues_workspaceDir(t *testing.T) {
+	d := t.TempDir()
+	t.Chdir(d)
+
+	files := map[string]string{
+		"terraform.tfvars":               `shared = "root"` + "\n" + `region = "root"`,
+		"workspaces/prod/a.tfvars":       `region = "prod-a"` + "\n" + `size = "prod-a"`,
+		"workspaces/prod/b.tfvars.json":  `{"size": "prod-b"}`,
+		"workspaces/prod/notes.txt":      `not variables`,
+		"workspaces/staging/main.tfvars": `region = "staging"`,
+	}
+	for name, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Meta{workspaceOverride: "prod"}
+	values, diags := m.collectVariableValues()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	want := map[string]string{
+		"shared": "root",
+		"region": "prod-a",
+		"size":   "prod-b",
+	}
+	if len(values) != len(want) {
+		t.Fatalf("wrong number of values %d; want %d", len(values), len(want))
+	}
+	for name, wantVal := range want {
+		raw, ok := values[name]
+		if !ok {
+			t.Errorf("missing value for %q", name)
+			continue
+		}
+		val, diags := raw.ParseVariableValue(configs.VariableParseLiteral)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		if got := val.Value.AsString(); got != wantVal {
+			t.Errorf("wrong value for %q: got %q, want %q", name, got, wantVal)
+		}
+	}
+}
@@ -153,6 +153,12 @@ Options:
                      string directly, rather than a human-oriented
                      representation of the value.
 
+  -format=FORMAT     Print the outputs for use by other tools, as "dotenv"
+                     variable assignments, "sh" export commands for a POSIX
+                     shell, or "yaml". Values are quoted as the format
+                     requires, and sensitive values are left out unless
+                     -show-sensitive is also used.
+
   -show-sensitive    If specified, sensitive values will be displayed.
 
   -var 'foo=bar'     Set a value for one of the input variables in the root
@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// PerfCommand is a Command implementation that just shows help for the
+// subcommands nested below it.
+type PerfCommand struct {
+	Meta
+}
+
+func (c *PerfCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *PerfCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] perf <subcommand> [options] [args]
+
+  This command has subcommands for working with the performance profiles
+  written by the -profile option of "tofu plan" and "tofu apply".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PerfCommand) Synopsis() string {
+	return "Inspect performance profiles"
+}
@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/perf"
+)
+
+// PerfReportCommand is a Command implementation that shows a performance
+// profile written by the -profile option of "tofu plan" or "tofu apply".
+type PerfReportCommand struct {
+	Meta
+}
+
+func (c *PerfReportCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var top int
+	cmdFlags := c.Meta.defaultFlagSet("perf report")
+	cmdFlags.IntVar(&top, "top", 10, "top")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The perf report command expects exactly one argument: the performance profile file.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	if top < 0 {
+		c.Ui.Error("The -top option must be zero or a positive number.\n")
+		return 1
+	}
+
+	report, err := perf.ReadReport(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read performance profile: %s", err))
+		return 1
+	}
+	c.Ui.Output(c.renderPerfReport(report, top))
+	return 0
+}
+
+// renderPerfReport returns the human-readable rendering of the given report,
+// showing only its top slowest resource instances, or all of them if top is
+// zero.
+func (c *PerfReportCommand) renderPerfReport(report *perf.Report, top int) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "[bold]Performance profile of tofu %s[reset], started %s, took %s.\n",
+		report.Command, report.Started.Format(time.RFC3339), perfDuration(report.DurationMS))
+	if report.CPUProfile != "" {
+		fmt.Fprintf(&buf, "The CPU profile is in %s, which you can inspect with \"go tool pprof\".\n", report.CPUProfile)
+	}
+
+	buf.WriteString("\n[bold]Phases:[reset]\n")
+	if len(report.Phases) == 0 {
+		buf.WriteString("  (none recorded)\n")
+	}
+	width := 0
+	for _, phase := range report.Phases {
+		width = max(width, len(phase.Name))
+	}
+	for _, phase := range report.Phases {
+		fmt.Fprintf(&buf, "  %-*s  %10s", width, phase.Name, perfDuration(phase.DurationMS))
+		if phase.Count > 1 {
+			fmt.Fprintf(&buf, "  (%d times)", phase.Count)
+		}
+		buf.WriteString("\n")
+	}
+
+	resources := report.Resources
+	if top > 0 && len(resources) > top {
+		resources = resources[:top]
+	}
+	fmt.Fprintf(&buf, "\n[bold]Slowest resource instances[reset] (%d of %d):\n", len(resources), len(report.Resources))
+	if len(resources) == 0 {
+		buf.WriteString("  (none recorded)\n")
+	}
+	width = 0
+	for _, resource := range resources {
+		width = max(width, len(resource.Address))
+	}
+	for _, resource := range resources {
+		calls := make([]string, 0, len(resource.Calls))
+		for _, call := range resource.Calls {
+			calls = append(calls, fmt.Sprintf("%s %s", call.Call, perfDuration(call.DurationMS)))
+		}
+		fmt.Fprintf(&buf, "  %-*s  %10s  %s\n", width, resource.Address, perfDuration(resource.DurationMS), strings.Join(calls, ", "))
+	}
+
+	buf.WriteString("\nPhases can overlap, and resource instances are processed concurrently, so\nthe times don't add up to the total.")
+	return c.Colorize().Color(buf.String())
+}
+
+// perfDuration returns the given number of milliseconds as a duration,
+// rounded to the nearest millisecond.
+func perfDuration(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond).String()
+}
+
+func (c *PerfReportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.json")
+}
+
+func (c *PerfReportCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-top": complete.PredictAnything,
+	}
+}
+
+func (c *PerfReportCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] perf report [options] FILE
+
+  Show a performance profile written by the -profile option of "tofu plan"
+  or "tofu apply": how long the operation took, the time spent in each of
+  its phases, such as fetching provider schemas, building graphs, and
+  reading and writing the state, and the resource instances whose provider
+  calls took the longest.
+
+Options:
+
+  -top=n             Show the n slowest resource instances. Defaults to 10.
+                     Use 0 to show all of them.
+
+  -no-color          If specified, output won't contain any color.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PerfReportCommand) Synopsis() string {
+	return "Show a performance profile"
+}
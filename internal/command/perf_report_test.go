@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/perf"
+)
+
+func TestPerfReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.json")
+	report := &perf.Report{
+		FormatVersion: perf.ReportFormatVersion,
+		Command:       "apply",
+		Started:       time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC),
+		DurationMS:    12345,
+		CPUProfile:    "cpu.pprof",
+		Phases: []perf.ReportPhase{
+			{Name: "schema fetch", Count: 2, DurationMS: 2100},
+			{Name: "plan graph walk", Count: 1, DurationMS: 8000.4},
+		},
+		Resources: []perf.ReportResource{
+			{
+				Address:    "test_instance.slow",
+				DurationMS: 5200,
+				Calls: []perf.ReportCall{
+					{Call: "apply", Count: 1, DurationMS: 4100},
+					{Call: "refresh", Count: 1, DurationMS: 1100},
+				},
+			},
+			{
+				Address:    "test_instance.fast",
+				DurationMS: 12,
+				Calls: []perf.ReportCall{
+					{Call: "plan", Count: 1, DurationMS: 12},
+				},
+			},
+		},
+	}
+	if err := report.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &PerfReportCommand{
+		Meta: Meta{Ui: ui},
+	}
+	if code := c.Run([]string{"-no-color", "-top=1", path}); code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		"Performance profile of tofu apply, started 2026-10-15T09:30:00Z, took 12.345s.",
+		"The CPU profile is in cpu.pprof",
+		"  schema fetch           2.1s  (2 times)\n",
+		"  plan graph walk          8s\n",
+		"Slowest resource instances (1 of 2):\n",
+		"  test_instance.slow        5.2s  apply 4.1s, refresh 1.1s\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output doesn't contain %q\n\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "test_instance.fast") {
+		t.Errorf("output includes more than the -top resource instances\n\n%s", got)
+	}
+}
+
+func TestPerfReport_invalid(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &PerfReportCommand{
+		Meta: Meta{Ui: ui},
+	}
+	if code := c.Run([]string{filepath.Join(t.TempDir(), "missing.json")}); code != 1 {
+		t.Fatalf("wrong exit code %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Failed to read performance profile"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\n got: %s\nwant: %s", got, want)
+	}
+}
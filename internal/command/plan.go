@@ -10,10 +10,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -51,6 +54,13 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	ctx, stopProfile, profileDiags := c.startProfile(ctx, "plan", args.ProfilePath, args.ProfileCPUPath)
+	if profileDiags.HasErrors() {
+		view.Diagnostics(profileDiags)
+		return 1
+	}
+	defer func() { view.Diagnostics(stopProfile()) }()
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -71,6 +81,8 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.providerParallelism = args.Operation.ProviderParallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
 
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
 
@@ -100,6 +112,29 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		view.Diagnostics(diags)
 		return 1
 	}
+	opReq.PlanMaxAge = args.MaxAge
+	opReq.ShowProvisioners = args.ShowProvisioners
+	opReq.ReportOrphans = args.ReportOrphansPath != ""
+	opReq.ExportGraph = args.ExportGraphPath != ""
+	opReq.StateVersion = args.StateVersion
+	if args.Incremental {
+		cache, cacheDiags := c.loadPlanCache(ctx)
+		diags = diags.Append(cacheDiags)
+		if cacheDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		opReq.PlanCache = cache
+	}
+	if args.GenerateConfigTemplatePath != "" {
+		templates, templateDiags := genconfig.LoadTemplates(args.GenerateConfigTemplatePath)
+		diags = diags.Append(templateDiags)
+		if templateDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		opReq.GenerateConfigTemplates = templates
+	}
 
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
@@ -114,9 +149,33 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	if op.OrphanReport != nil {
+		if diags := c.saveOrphanReport(args.ReportOrphansPath, op.OrphanReport); diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
+	if op.ExecutionGraph != nil {
+		if diags := c.saveExecutionGraph(args.ExportGraphPath, op.ExecutionGraph); diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+	if args.OutPath != "" {
+		if diags := c.signPlanFile(args.OutPath); diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+	if opReq.PlanCache != nil {
+		// A failure to save the cache only makes the next incremental plan
+		// slower, so it's reported only as a warning.
+		view.Diagnostics(c.savePlanCache(ctx, opReq.PlanCache))
+	}
 	if args.DetailedExitCode && !op.PlanEmpty {
 		return 2
 	}
@@ -124,6 +183,32 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	return op.Result.ExitStatus()
 }
 
+// saveOrphanReport writes the report requested with -report-orphans.
+func (c *PlanCommand) saveOrphanReport(path string, report *backend.OrphanReport) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if err := report.Save(path); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write orphan report",
+			fmt.Sprintf("Could not write the report of orphaned resources to %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+// saveExecutionGraph writes the graph requested with -export-graph.
+func (c *PlanCommand) saveExecutionGraph(path string, graph *backend.ExecutionGraph) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if err := graph.Save(path); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write execution graph",
+			fmt.Sprintf("Could not write the execution graph to %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
 func (c *PlanCommand) PrepareBackend(ctx context.Context, args *arguments.State, viewType arguments.ViewType, enc encryption.Encryption) (backend.Enhanced, tfdiags.Diagnostics) {
 	// FIXME: we need to apply the state arguments to the meta object here
 	// because they are later used when initializing the backend. Carving a
@@ -146,6 +231,13 @@ func (c *PlanCommand) PrepareBackend(ctx context.Context, args *arguments.State,
 		return nil, diags
 	}
 
+	// Check the workspace given with -workspace, if any, now that we know
+	// which backend it belongs to.
+	diags = diags.Append(c.prepareWorkspaceOverride(ctx, be))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	return be, diags
 }
 
@@ -171,7 +263,10 @@ func (c *PlanCommand) OperationRequest(
 	opReq.GenerateConfigOut = generateConfigOut
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
+	opReq.TargetSelectors = args.TargetSelectors
 	opReq.ForceReplace = args.ForceReplace
+	opReq.CascadeReplace = args.CascadeReplace
+	opReq.AllowDeferral = args.AllowDeferral
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
 
@@ -202,6 +297,40 @@ func (c *PlanCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *PlanCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *PlanCommand) AutocompleteFlags() complete.Flags {
+	ctx := c.CommandContext()
+	return complete.Flags{
+		"-compact-warnings":    complete.PredictNothing,
+		"-destroy":             complete.PredictNothing,
+		"-detailed-exitcode":   complete.PredictNothing,
+		"-exclude":             c.completePredictResourceAddress(ctx),
+		"-generate-config-out": complete.PredictFiles("*.tf"),
+		"-input":               completePredictBoolean,
+		"-json":                complete.PredictNothing,
+		"-lock":                completePredictBoolean,
+		"-lock-timeout":        complete.PredictAnything,
+		"-no-color":            complete.PredictNothing,
+		"-out":                 complete.PredictFiles("*"),
+		"-parallelism":         complete.PredictAnything,
+		"-profile":             complete.PredictFiles("*.json"),
+		"-profile-cpu":         complete.PredictFiles("*"),
+		"-refresh":             completePredictBoolean,
+		"-refresh-only":        complete.PredictNothing,
+		"-refresh-parallelism": complete.PredictAnything,
+		"-replace":             c.completePredictResourceAddress(ctx),
+		"-report-orphans":      complete.PredictFiles("*.json"),
+		"-export-graph":        complete.PredictFiles("*.json"),
+		"-state-version":       complete.PredictAnything,
+		"-target":              c.completePredictResourceAddress(ctx),
+		"-var":                 c.completePredictVariableAssignment(ctx),
+		"-var-file":            complete.PredictFiles("*.tfvars"),
+	}
+}
+
 func (c *PlanCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] plan [options]
@@ -241,6 +370,18 @@ Plan Customization Options:
                           You can use this option multiple times to replace
                           more than one object.
 
+  -cascade                With -replace, also replace every resource instance
+                          that depends on the replaced instances, directly or
+                          indirectly, according to the dependency graph.
+
+  -allow-deferral         Instead of returning an error, defer resources whose
+                          count or for_each arguments won't be known until
+                          apply, and everything that depends on them, to a
+                          later plan. The plan lists the deferred resources.
+                          Also leave new objects whose provider configuration
+                          isn't known yet for the provider to plan during
+                          apply.
+
   -target=resource        Limit the planning operation to only the given
                           module, resource, or resource instance and all of its
                           dependencies. You can use this option multiple times
@@ -251,6 +392,11 @@ Plan Customization Options:
   -target-file=filename   Similar to -target, but specifies zero or more
                           resource addresses from a file.
 
+  -target-selector=expr   Similar to -target, but targets the resource
+                          instances whose attribute values in the current
+                          state make the given expression true, such as
+                          'tags["team"] == "payments"'.
+
   -exclude=resource       Limit the planning operation to not operate on the
                           given module, resource, or resource instance and all
                           of the resources and modules that depend on it. You
@@ -266,8 +412,10 @@ Plan Customization Options:
                           more than once to set more than one variable.
 
   -var-file=filename      Load variable values from the given file, in addition
-                          to the default files terraform.tfvars and
-                          *.auto.tfvars. Use this option more than once to
+                          to the default files terraform.tfvars,
+                          *.auto.tfvars, and the .tfvars files in the
+                          workspaces/NAME directory for the selected
+                          workspace. Use this option more than once to
                           include more than one variables file.
 
 Other Options:
@@ -298,6 +446,13 @@ Other Options:
                                OpenTofu may still attempt to write
                                configuration if planning fails with an error.
 
+  -generate-config-template=path
+                               (Experimental) Reshape the configuration
+                               generated by -generate-config-out using the
+                               per-resource-type templates in the given file,
+                               which can move arguments into input variables
+                               and add lifecycle blocks.
+
   -input=false                 Disable prompting for required input variables
                                that are not set some other way.
 
@@ -313,10 +468,24 @@ Other Options:
   -concise                     Disable progress-related messages.
 
   -out=path                    Write a plan file to the given path. This can be
-                               used as input to the "apply" command.
+                               used as input to the "apply" command. The plan
+                               file is signed if the CLI configuration has a
+                               plan_signing block with a signing key.
+
+  -max-age=duration            Refuse to apply the plan file saved by -out once
+                               the given duration, such as "24h", has passed
+                               since the plan was created.
 
   -parallelism=n               Limit the number of concurrent operations.
-                               Defaults to 10.
+                               Defaults to 10. Use provider=n, for example
+                               -parallelism=aws=10,cloudflare=2, to set a
+                               lower limit for a specific provider.
+
+  -refresh-parallelism=n       Give the resources of each provider
+                               configuration their own limit of n concurrent
+                               operations, instead of sharing the overall
+                               -parallelism limit, so that a slow provider
+                               doesn't hold up the others.
 
   -state=statefile             A legacy option used for the local backend only.
                                Refer to the local backend's documentation for
@@ -325,6 +494,43 @@ Other Options:
   -show-sensitive              If specified, sensitive values will not be
                                redacted in te UI output.
 
+  -show-provisioners           Show what the provisioners of each planned change
+                               will run when the plan is applied, such as their
+                               commands and the hosts they connect to, without
+                               running them.
+
+  -incremental                 Skip planning the resource instances whose
+                               configuration, prior state, and provider schema
+                               haven't changed since they had no changes in the
+                               previous incremental plan in this workspace.
+
+  -report-orphans=path         Write a JSON report of the resource instances
+                               in the state whose resource or module is no
+                               longer in the configuration, along with their
+                               module, provider, and planned action.
+
+  -export-graph=path           Write a JSON graph of the changes that applying
+                               the plan would make, with the action, provider,
+                               and dependencies of each change, for tools that
+                               schedule or analyze the changes.
+
+  -state-version=version       Plan against an earlier version of the state
+                               kept by the backend, identified by its version
+                               ID or its serial, instead of the latest one.
+                               The plan can't be saved or applied.
+
+  -profile=path                Write a JSON performance profile of the plan,
+                               with the time spent in each of its phases and
+                               on the provider calls of each resource
+                               instance. Use "tofu perf report" to show it.
+
+  -profile-cpu=path            Write a pprof CPU profile of the plan.
+
+  -workspace=name[,create]     Use the given workspace for this command only,
+                               instead of the currently selected workspace.
+                               Add ",create" to create the workspace if it
+                               doesn't already exist.
+
   -json                        Produce output in a machine-readable JSON
                                format, suitable for use in text editor
                                integrations and other automated systems.
@@ -0,0 +1,223 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+)
+
+// PlanCompareCommand is a Command implementation that reports the differences
+// between the changes proposed by two saved plan files.
+type PlanCompareCommand struct {
+	Meta
+}
+
+// planComparison is the JSON representation of the result of
+// PlanCompareCommand, as produced with the -json option.
+type planComparison struct {
+	Identical   bool                   `json:"identical"`
+	Differences []planChangeDifference `json:"differences"`
+}
+
+type planChangeDifference struct {
+	Address    string   `json:"address"`
+	Difference string   `json:"difference"`
+	OldActions []string `json:"old_actions,omitempty"`
+	NewActions []string `json:"new_actions,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+func (c *PlanCompareCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+
+	var jsonOutput bool
+	cmdFlags := c.Meta.defaultFlagSet("plan compare")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("The plan compare command expects two arguments: the old and new plan files.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	oldPlan, err := readPlanForCompare(args[0], enc)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	newPlan, err := readPlanForCompare(args[1], enc)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	differences := plans.CompareChanges(oldPlan.Changes, newPlan.Changes)
+
+	if jsonOutput {
+		result := planComparison{
+			Identical:   len(differences) == 0,
+			Differences: []planChangeDifference{},
+		}
+		for _, diff := range differences {
+			jd := planChangeDifference{
+				Address:    diff.Addr,
+				Difference: diff.Kind.String(),
+				Fields:     diff.Fields,
+			}
+			if diff.Kind != plans.DifferenceAdded {
+				jd.OldActions = jsonplan.MarshalActions(diff.OldAction)
+			}
+			if diff.Kind != plans.DifferenceRemoved {
+				jd.NewActions = jsonplan.MarshalActions(diff.NewAction)
+			}
+			result.Differences = append(result.Differences, jd)
+		}
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal comparison to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+	} else {
+		c.Ui.Output(c.renderPlanComparison(args[0], args[1], differences))
+	}
+
+	if len(differences) != 0 {
+		return 2
+	}
+	return 0
+}
+
+// readPlanForCompare reads the plan from a local saved plan file. Plans
+// saved from remote runs don't include the planned changes, so they can't be
+// compared.
+func readPlanForCompare(path string, enc encryption.Encryption) (*plans.Plan, error) {
+	pf, err := planfile.OpenWrapped(path, enc.Plan())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read plan file %s: %w", path, err)
+	}
+	lp, ok := pf.Local()
+	if !ok {
+		return nil, fmt.Errorf("The plan file %s was saved from a remote run. Only local plan files can be compared.", path)
+	}
+	plan, err := lp.ReadPlan()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read plan from %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+func (c *PlanCompareCommand) renderPlanComparison(oldPath, newPath string, differences []plans.ChangeDifference) string {
+	if len(differences) == 0 {
+		return c.Colorize().Color(fmt.Sprintf("[bold][green]No differences.[reset] The plans %s and %s propose the same changes.", oldPath, newPath))
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Comparing the changes in %s (old) with %s (new):\n\n", oldPath, newPath)
+	counts := make(map[plans.DifferenceKind]int)
+	for _, diff := range differences {
+		counts[diff.Kind]++
+		switch diff.Kind {
+		case plans.DifferenceAdded:
+			fmt.Fprintf(&buf, "[green]+[reset] [bold]%s[reset]: %s, only in the new plan\n", diff.Addr, compareActionName(diff.NewAction))
+		case plans.DifferenceRemoved:
+			fmt.Fprintf(&buf, "[red]-[reset] [bold]%s[reset]: %s, only in the old plan\n", diff.Addr, compareActionName(diff.OldAction))
+		case plans.DifferenceChanged:
+			action := compareActionName(diff.NewAction)
+			if diff.OldAction != diff.NewAction {
+				action = compareActionName(diff.OldAction) + " -> " + action
+			}
+			fmt.Fprintf(&buf, "[yellow]~[reset] [bold]%s[reset]: %s, with different %s\n", diff.Addr, action, strings.Join(diff.Fields, ", "))
+		}
+	}
+	fmt.Fprintf(&buf, "\n[bold]Differences:[reset] %d added, %d removed, %d changed.",
+		counts[plans.DifferenceAdded], counts[plans.DifferenceRemoved], counts[plans.DifferenceChanged])
+	return c.Colorize().Color(buf.String())
+}
+
+func compareActionName(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update in-place"
+	case plans.DeleteThenCreate:
+		return "replace (destroy then create)"
+	case plans.CreateThenDelete:
+		return "replace (create then destroy)"
+	case plans.Delete:
+		return "destroy"
+	case plans.Read:
+		return "read"
+	case plans.Forget:
+		return "forget"
+	case plans.NoOp:
+		return "no-op"
+	default:
+		return action.String()
+	}
+}
+
+func (c *PlanCompareCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *PlanCompareCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json": complete.PredictNothing,
+	}
+}
+
+func (c *PlanCompareCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] plan compare [options] OLD_PLAN NEW_PLAN
+
+  Compare the changes proposed by two saved plan files, such as a plan that
+  was reviewed and approved and a plan created later for the same
+  configuration, and report the changes that were added, removed, or
+  differ between them.
+
+  The exit code is 0 if both plans propose the same changes, 2 if there are
+  differences, and 1 if either plan could not be read.
+
+  Only the proposed changes are compared, not the prior state or
+  configuration that each plan was created from. No-op changes are ignored
+  unless they import or move an object.
+
+Options:
+
+  -json              Produce output in a machine-readable JSON format.
+
+  -no-color          If specified, output won't contain any color.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PlanCompareCommand) Synopsis() string {
+	return "Compare the changes proposed by two saved plans"
+}
@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestPlanCompare(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	snap := &configload.Snapshot{
+		Modules: map[string]*configload.SnapshotModule{
+			"": {
+				Dir: ".",
+				Files: map[string][]byte{
+					"main.tf": nil,
+				},
+			},
+		},
+	}
+	planWithChange := func(name string, action plans.Action, value string) string {
+		after, err := plans.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+			"id": cty.StringVal(value),
+		}), cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before, err := plans.NewDynamicValue(cty.NullVal(cty.DynamicPseudoType), cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+		plan := testPlan(t)
+		plan.Changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+			Addr:        addr,
+			PrevRunAddr: addr,
+			ProviderAddr: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			ChangeSrc: plans.ChangeSrc{
+				Action: action,
+				Before: before,
+				After:  after,
+			},
+		})
+		return testPlanFile(t, snap, states.NewState(), plan)
+	}
+
+	approved := planWithChange("foo", plans.Create, "a")
+	regenerated := planWithChange("foo", plans.Create, "b")
+
+	t.Run("identical", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &PlanCompareCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run([]string{"-no-color", approved, approved}); code != 0 {
+			t.Fatalf("wrong exit status %d; want 0\n%s", code, ui.ErrorWriter.String())
+		}
+		if got, want := ui.OutputWriter.String(), "No differences."; !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &PlanCompareCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run([]string{"-no-color", approved, regenerated}); code != 2 {
+			t.Fatalf("wrong exit status %d; want 2\n%s", code, ui.ErrorWriter.String())
+		}
+		got := ui.OutputWriter.String()
+		for _, want := range []string{
+			"~ test_instance.foo: create, with different after",
+			"Differences: 0 added, 0 removed, 1 changed.",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output does not contain %q\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &PlanCompareCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run([]string{"-json", approved, planWithChange("bar", plans.Create, "c")}); code != 2 {
+			t.Fatalf("wrong exit status %d; want 2\n%s", code, ui.ErrorWriter.String())
+		}
+		var got planComparison
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+		}
+		want := planComparison{
+			Differences: []planChangeDifference{
+				{Address: "test_instance.bar", Difference: "added", NewActions: []string{"create"}},
+				{Address: "test_instance.foo", Difference: "removed", OldActions: []string{"create"}},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &PlanCompareCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run([]string{approved}); code != 1 {
+			t.Fatalf("wrong exit status %d; want 1", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "expects two arguments"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot: %s\nwant: %s", got, want)
+		}
+	})
+}
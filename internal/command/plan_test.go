@@ -8,26 +8,38 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
 	backendinit "github.com/opentofu/opentofu/internal/backend/init"
+	"github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
+	"github.com/opentofu/opentofu/internal/perf"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -223,6 +235,42 @@ func TestPlan_noState(t *testing.T) {
 	}
 }
 
+func TestPlan_workspaceOverridesEnvVar(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+	t.Setenv(WorkspaceNameEnvVar, "from-env")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-workspace=from-flag,create"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	got, err := c.Workspace(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "from-flag" {
+		t.Fatalf("wrong workspace %q; want %q", got, "from-flag")
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "from-flag")); err != nil {
+		t.Fatalf("workspace wasn't created: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "from-env")); !os.IsNotExist(err) {
+		t.Fatalf("workspace from %s was used: %v", WorkspaceNameEnvVar, err)
+	}
+}
+
 func TestPlan_noTestVars(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan-no-test-vars"), td)
@@ -297,6 +345,48 @@ func TestPlan_generatedConfigPath(t *testing.T) {
 	testFileEquals(t, genPath, filepath.Join(td, "generated.tf.expected"))
 }
 
+func TestPlan_generatedConfigTemplate(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-import-config-gen-template"), td)
+	t.Chdir(td)
+
+	genPath := filepath.Join(td, "generated.tf")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "test_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id":  cty.StringVal("bar"),
+					"ami": cty.StringVal("ami-123"),
+				}),
+			},
+		},
+	}
+
+	args := []string{
+		"-generate-config-out", genPath,
+		"-generate-config-template", "templates.hcl",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	testFileEquals(t, genPath, filepath.Join(td, "generated.tf.expected"))
+}
+
 func TestPlan_outPath(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan"), td)
@@ -329,6 +419,71 @@ func TestPlan_outPath(t *testing.T) {
 	testReadPlan(t, outPath) // will call t.Fatal itself if the file cannot be read
 }
 
+func TestPlan_outPathSigned(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	outPath := filepath.Join(td, "test.plan")
+	signing := planfile.SigningConfig{SigningKeyFile: testPlanSigningKey(t)}
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			PlanSigning:      signing,
+		},
+	}
+
+	p.PlanResourceChangeResponse = &providers.PlanResourceChangeResponse{
+		PlannedState: cty.NullVal(cty.EmptyObject),
+	}
+
+	code := c.Run([]string{"-out", outPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if _, err := planfile.VerifySignature(outPath, signing); err != nil {
+		t.Fatalf("saved plan does not have a valid signature: %s", err)
+	}
+}
+
+func TestPlan_outPathMaxAge(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	outPath := filepath.Join(td, "test.plan")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	p.PlanResourceChangeResponse = &providers.PlanResourceChangeResponse{
+		PlannedState: cty.NullVal(cty.EmptyObject),
+	}
+
+	code := c.Run([]string{"-out", outPath, "-max-age=90m"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	plan := testReadPlan(t, outPath)
+	if got, want := plan.ExpiresAt.Sub(plan.Timestamp), 90*time.Minute; got != want {
+		t.Fatalf("wrong plan expiry %s after creation; want %s", got, want)
+	}
+}
+
 func TestPlan_outPathNoChange(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan"), td)
@@ -1609,6 +1764,95 @@ func TestPlan_targeted(t *testing.T) {
 	}
 }
 
+func TestPlan_targetSelector(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, name := range []string{"foo", "bar", "baz"} {
+			team := "search"
+			if name != "bar" {
+				team = "payments"
+			}
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: name,
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"` + name + `","tags":{"team":"` + team + `"}}`),
+					Status:    states.ObjectReady,
+				},
+				provider,
+				addrs.NoKey,
+			)
+		}
+	})
+	statePath := testStateFile(t, state)
+
+	p := planFixtureProvider()
+	p.GetProviderSchemaResponse.ResourceTypes["test_instance"].Block.Attributes["tags"] = &configschema.Attribute{
+		Type:     cty.Map(cty.String),
+		Optional: true,
+	}
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-destroy",
+		"-state", statePath,
+		"-target-selector", `tags["team"] == "payments"`,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	if want := "0 to add, 0 to change, 2 to destroy"; !strings.Contains(got, want) {
+		t.Fatalf("bad change summary, want %q, got:\n%s", want, got)
+	}
+	if strings.Contains(got, "test_instance.bar will be destroyed") {
+		t.Fatalf("unselected resource instance is planned for destruction:\n%s", got)
+	}
+}
+
+func TestPlan_targetSelectorNoMatch(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-target-selector", `id == "nope"`})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("unexpected success\n%s", output.Stdout())
+	}
+	if got, want := output.Stderr(), "No resource instances selected"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\nwant: %s\ngot:\n%s", want, got)
+	}
+}
+
 // Diagnostics for invalid -target flags
 func TestPlan_targetFlagsDiags(t *testing.T) {
 	testCases := map[string]string{
@@ -1806,6 +2050,304 @@ func TestPlan_replace(t *testing.T) {
 	}
 }
 
+func TestPlan_incremental(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-incremental"), td)
+	t.Chdir(td)
+
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_instance.foo"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"bar","ami":"bar"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	// The resource instances of a provider are only skipped while the same
+	// package of the provider is installed, as recorded in the lock file.
+	lockProvider := func(version string) {
+		t.Helper()
+		locks := depsfile.NewLocks()
+		locks.SetProvider(addrs.NewDefaultProvider("test"), getproviders.MustParseVersion(version), nil, nil)
+		if diags := depsfile.SaveLocksToFile(t.Context(), locks, dependencyLockFilename); diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+	}
+	lockProvider("1.0.0")
+
+	p := planFixtureProvider()
+	run := func() {
+		t.Helper()
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		code := c.Run([]string{"-incremental", "-state", statePath})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+		}
+	}
+
+	// The first incremental plan has no earlier results to use, so the
+	// provider plans the instance and the plan saves its inputs.
+	run()
+	if !p.PlanResourceChangeCalled {
+		t.Fatal("PlanResourceChange not called in the first plan")
+	}
+	if _, err := os.Stat(filepath.Join(DefaultDataDir, "plan-cache", "default.json")); err != nil {
+		t.Fatalf("plan cache not saved: %s", err)
+	}
+
+	// The second one finds that the instance's inputs haven't changed, so
+	// it doesn't need to ask the provider to plan it again.
+	p.PlanResourceChangeCalled = false
+	run()
+	if p.PlanResourceChangeCalled {
+		t.Fatal("PlanResourceChange called in the second plan")
+	}
+
+	// After upgrading the provider, the instance is planned again.
+	lockProvider("1.1.0")
+	run()
+	if !p.PlanResourceChangeCalled {
+		t.Fatal("PlanResourceChange not called after the provider was upgraded")
+	}
+}
+
+func TestPlan_reportOrphans(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-incremental"), td)
+	t.Chdir(td)
+
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	originalState := states.BuildState(func(s *states.SyncState) {
+		for _, addr := range []string{"test_instance.foo", "test_instance.gone", "module.old.test_instance.bar[0]"} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar","ami":"bar"}`),
+					Status:    states.ObjectReady,
+				},
+				provider,
+				addrs.NoKey,
+			)
+		}
+	})
+	statePath := testStateFile(t, originalState)
+	reportPath := filepath.Join(td, "orphans.json")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-report-orphans", reportPath, "-state", statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+	}
+
+	src, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(src, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"orphans": []interface{}{
+			map[string]interface{}{
+				"address":                 "module.old.test_instance.bar[0]",
+				"module_address":          "module.old",
+				"module_in_configuration": false,
+				"type":                    "test_instance",
+				"name":                    "bar",
+				"provider":                `provider["registry.opentofu.org/hashicorp/test"]`,
+				"planned_action":          "delete",
+			},
+			map[string]interface{}{
+				"address":                 "test_instance.gone",
+				"module_in_configuration": true,
+				"type":                    "test_instance",
+				"name":                    "gone",
+				"provider":                `provider["registry.opentofu.org/hashicorp/test"]`,
+				"planned_action":          "delete",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong report\n%s", diff)
+	}
+}
+
+func TestPlan_exportGraph(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-export-graph"), td)
+	t.Chdir(td)
+
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_instance.gone"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"gone","ami":"gone"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+	graphPath := filepath.Join(td, "graph.json")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-export-graph", graphPath, "-state", statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+	}
+
+	src, err := os.ReadFile(graphPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(src, &got); err != nil {
+		t.Fatal(err)
+	}
+	provider := `provider["registry.opentofu.org/hashicorp/test"]`
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"nodes": []interface{}{
+			map[string]interface{}{
+				"id":         "test_instance.app",
+				"address":    "test_instance.app",
+				"operation":  "apply",
+				"action":     "create",
+				"provider":   provider,
+				"depends_on": []interface{}{"test_instance.base"},
+			},
+			map[string]interface{}{
+				"id":         "test_instance.base",
+				"address":    "test_instance.base",
+				"operation":  "apply",
+				"action":     "create",
+				"provider":   provider,
+				"depends_on": []interface{}{},
+			},
+			map[string]interface{}{
+				"id":         "test_instance.gone (destroy)",
+				"address":    "test_instance.gone",
+				"operation":  "destroy",
+				"action":     "delete",
+				"provider":   provider,
+				"depends_on": []interface{}{},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong graph\n%s", diff)
+	}
+}
+
+func TestPlan_showProvisioners(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-show-provisioners"), td)
+	t.Chdir(td)
+
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		return providers.PlanResourceChangeResponse{
+			PlannedState: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.UnknownVal(cty.String),
+			}),
+		}
+	}
+	pr := &tofu.MockProvisioner{
+		GetSchemaResponse: provisioners.GetSchemaResponse{
+			Provisioner: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"command": {Type: cty.String, Required: true},
+				},
+			},
+		},
+	}
+
+	overrides := metaOverridesForProvider(p)
+	overrides.Provisioners = map[string]provisioners.Factory{
+		"shell": func() (provisioners.Interface, error) {
+			return pr, nil
+		},
+	}
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: overrides,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-show-provisioners"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+	}
+
+	stdout := output.Stdout()
+	for _, want := range []string{
+		`# provisioner "shell" will run after test_instance.a is created`,
+		`+ command    = (known after apply)`,
+		`+ host = "a.example.com"`,
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("missing provisioner preview\ngot output:\n%s\n\nwant substring: %s", stdout, want)
+		}
+	}
+	if pr.ProvisionResourceCalled {
+		t.Errorf("provisioner was run during plan")
+	}
+}
+
 // Verify that the parallelism flag allows no more than the desired number of
 // concurrent calls to PlanResourceChange.
 func TestPlan_parallelism(t *testing.T) {
@@ -2111,6 +2653,174 @@ func TestPlan_concise(t *testing.T) {
 // operation with the configuration in testdata/plan. This mock has
 // GetSchemaResponse and PlanResourceChangeFn populated, with the plan
 // step just passing through the new object proposed by OpenTofu Core.
+func TestPlan_stateVersion(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-state-version"), td)
+	t.Chdir(td)
+	t.Setenv("TF_WORKSPACE", "test")
+	defer inmem.Reset()
+
+	// Creating the workspace stores an empty first version of the state,
+	// then the second version has test_instance.foo, and the latest one is
+	// empty again.
+	b := backend.TestBackendConfig(t, inmem.New(encryption.StateEncryptionDisabled()), nil)
+	sMgr, err := b.StateMgr(t.Context(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstState := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_instance.foo"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"foo","ami":"bar"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+	for _, s := range []*states.State{firstState, states.NewState()} {
+		if err := sMgr.WriteState(s); err != nil {
+			t.Fatal(err)
+		}
+		if err := sMgr.PersistState(t.Context(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backendConfig := &configs.Backend{
+		Type:   "inmem",
+		Config: configs.SynthBody("<TestPlan_stateVersion>", map[string]cty.Value{}),
+		Eval:   configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()),
+	}
+	hash, _ := backendConfig.Hash(t.Context(), b.ConfigSchema())
+	backendState := legacy.NewState()
+	backendState.Backend = &legacy.BackendState{
+		Type:      "inmem",
+		ConfigRaw: json.RawMessage(`{}`),
+		Hash:      uint64(hash),
+	}
+	testStateFileRemote(t, backendState)
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "1 to add, 0 to change, 0 to destroy"},
+		{[]string{"-state-version=v2"}, "No changes."},
+	} {
+		p := planFixtureProvider()
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		code := c.Run(tc.args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("%v: wrong exit code %d\n\n%s", tc.args, code, output.Stderr())
+		}
+		if got := output.Stdout(); !strings.Contains(got, tc.want) {
+			t.Errorf("%v: output doesn't contain %q\n\n%s", tc.args, tc.want, got)
+		}
+	}
+
+	// Planning against an earlier version must not change the latest one.
+	if err := sMgr.RefreshState(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if !sMgr.State().Empty() {
+		t.Fatalf("latest state was changed:\n%s", sMgr.State())
+	}
+}
+
+func TestPlan_stateVersionUnsupported(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-state-version=1"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "State versions not supported"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestPlan_profile(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+	profilePath := filepath.Join(td, "perf.json")
+	cpuProfilePath := filepath.Join(td, "cpu.pprof")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-profile", profilePath, "-profile-cpu", cpuProfilePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+	}
+
+	report, err := perf.ReadReport(profilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := report.Command, "plan"; got != want {
+		t.Errorf("wrong command %q; want %q", got, want)
+	}
+	if got, want := report.CPUProfile, cpuProfilePath; got != want {
+		t.Errorf("wrong CPU profile %q; want %q", got, want)
+	}
+	var phases []string
+	for _, phase := range report.Phases {
+		phases = append(phases, phase.Name)
+	}
+	for _, want := range []string{"state read", "config load", "plan graph build", "plan graph walk"} {
+		if !slices.Contains(phases, want) {
+			t.Errorf("no %q phase in %q", want, phases)
+		}
+	}
+	calls := make(map[string][]string)
+	for _, resource := range report.Resources {
+		for _, call := range resource.Calls {
+			calls[resource.Address] = append(calls[resource.Address], call.Call)
+		}
+	}
+	wantCalls := map[string][]string{
+		"test_instance.foo":       {"plan"},
+		"data.test_data_source.a": {"read"},
+	}
+	if diff := cmp.Diff(wantCalls, calls); diff != "" {
+		t.Errorf("wrong resource calls\n%s", diff)
+	}
+
+	if info, err := os.Stat(cpuProfilePath); err != nil || info.Size() == 0 {
+		t.Errorf("CPU profile wasn't written: %v", err)
+	}
+}
+
 func planFixtureProvider() *tofu.MockProvider {
 	p := testProvider()
 	p.GetProviderSchemaResponse = planFixtureSchema()
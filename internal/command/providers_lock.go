@@ -151,6 +151,13 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 	diags = diags.Append(confDiags)
 	reqs, _, hclDiags := config.ProviderRequirements()
 	diags = diags.Append(hclDiags)
+	origins, hclDiags := config.ProviderOrigins()
+	diags = diags.Append(hclDiags)
+	if len(origins) != 0 && fsMirrorDir == "" && netMirrorURL == "" {
+		// Providers whose required_providers entries declare an origin are
+		// fetched from there instead of from their own registries.
+		source = getproviders.NewRedirectSource(source, origins)
+	}
 
 	// If we have explicit provider selections on the command line then
 	// we'll modify "reqs" to only include those. Modifying this is okay
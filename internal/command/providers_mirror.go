@@ -11,10 +11,13 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/hashicorp/go-getter"
 
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/httpclient"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -37,7 +40,11 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("providers mirror")
 	c.Meta.varFlagSet(cmdFlags)
 	var optPlatforms FlagStringSlice
+	var optPrune bool
+	var optManifest string
 	cmdFlags.Var(&optPlatforms, "platform", "target platform")
+	cmdFlags.BoolVar(&optPrune, "prune", false, "prune")
+	cmdFlags.StringVar(&optManifest, "manifest", "", "manifest")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -85,6 +92,8 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	diags = diags.Append(confDiags)
 	reqs, _, moreDiags := config.ProviderRequirements()
 	diags = diags.Append(moreDiags)
+	origins, moreDiags := config.ProviderOrigins()
+	diags = diags.Append(moreDiags)
 
 	// Read lock file
 	lockedDeps, lockedDepsDiags := c.Meta.lockedDependenciesWithPredecessorRegistryShimmed()
@@ -105,14 +114,29 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				fmt.Sprintf("To update the locked dependency selections to match a changed configuration, run:\n  tofu init -upgrade\n got:%v", errs),
 			))
 		}
+	} else if optPrune {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No dependency lock file",
+			"The -prune option removes the provider versions that the dependency lock file doesn't select, so it requires a dependency lock file. To create one, run:\n  tofu init",
+		))
+		c.showDiagnostics(diags)
+		return 1
 	}
 
 	// Unlike other commands, this command always consults the origin registry
 	// for every provider so that it can be used to update a local mirror
 	// directory without needing to first disable that local mirror
 	// in the CLI configuration.
+	//
+	// Providers whose required_providers entries declare an origin are
+	// fetched from there, but are still placed in the mirror directory
+	// under their own addresses.
 	source := getproviders.NewMemoizeSource(
-		getproviders.NewRegistrySource(ctx, c.Services, c.registryHTTPClient(ctx)),
+		getproviders.NewRedirectSource(
+			getproviders.NewRegistrySource(ctx, c.Services, c.registryHTTPClient(ctx)),
+			origins,
+		),
 	)
 
 	// Providers from registries always use HTTP, so we don't need the full
@@ -134,10 +158,15 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	// - It can mirror packages for potentially many different target platforms,
 	//   so that we can construct a multi-platform mirror regardless of which
 	//   platform we run this command on.
-	// - It ignores what's already present and just always downloads everything
-	//   that the configuration requires. This is a command intended to be run
-	//   infrequently to update a mirror, so it doesn't need to optimize away
-	//   fetches of packages that might already be present.
+	// - It skips downloading packages that are already present in the mirror
+	//   directory if they pass the same authentication checks as a newly
+	//   downloaded package would, so that updating a large mirror only
+	//   fetches what has changed.
+
+	// packageStatus records what happened to each package that the
+	// configuration requires, by its filename in the mirror directory, for
+	// the summary and the manifest.
+	packageStatus := make(map[string]string)
 
 	for provider, constraints := range reqs {
 		if provider.IsBuiltIn() {
@@ -180,7 +209,6 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 			c.Ui.Output(fmt.Sprintf("  - Selected v%s with no constraints", selected.String()))
 		}
 		for _, platform := range platforms {
-			c.Ui.Output(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
 			meta, err := source.PackageMeta(ctx, provider, selected, platform)
 			if err != nil {
 				diags = diags.Append(tfdiags.Sourceless(
@@ -220,6 +248,12 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 			// it discoverable to mirror clients. (stagingPath intentionally
 			// does not follow the filesystem mirror file naming convention.)
 			targetPath := meta.PackedFilePath(outputDir)
+			if result, ok := mirroredPackageValid(meta, targetPath); ok {
+				c.Ui.Output(fmt.Sprintf("  - Package for %s is already present: %s", platform.String(), result))
+				packageStatus[mirrorPackageFilename(provider, selected, platform)] = providersMirrorStatusUnchanged
+				continue
+			}
+			c.Ui.Output(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
 			stagingPath := filepath.Join(filepath.Dir(targetPath), "."+filepath.Base(targetPath))
 			err = httpGetter.GetFile(stagingPath, urlObj)
 			if err != nil {
@@ -252,6 +286,19 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
+			packageStatus[mirrorPackageFilename(provider, selected, platform)] = providersMirrorStatusDownloaded
+		}
+	}
+
+	// We only prune if everything else succeeded, so that a failure to reach
+	// the registry can't leave the mirror without any usable packages.
+	var pruned []providersMirrorManifestPackage
+	if optPrune && !diags.HasErrors() {
+		var pruneDiags tfdiags.Diagnostics
+		pruned, pruneDiags = pruneProvidersMirror(outputDir, lockedDeps)
+		diags = diags.Append(pruneDiags)
+		for _, pkg := range pruned {
+			c.Ui.Output(fmt.Sprintf("- Removed %s v%s for %s, which the dependency lock file doesn't select", pkg.Provider, pkg.Version, pkg.Platform))
 		}
 	}
 
@@ -269,6 +316,7 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		))
 		available = nil // the following loop will be a no-op
 	}
+	var packages []providersMirrorManifestPackage
 	for provider, metas := range available {
 		if len(metas) == 0 {
 			continue // should never happen, but we'll be resilient
@@ -301,6 +349,19 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
+			filename := mirrorPackageFilename(provider, version, platform)
+			status, ok := packageStatus[filename]
+			if !ok {
+				status = providersMirrorStatusRetained
+			}
+			packages = append(packages, providersMirrorManifestPackage{
+				Provider: provider.String(),
+				Version:  version.String(),
+				Platform: platform.String(),
+				Filename: filename,
+				Hash:     hash.String(),
+				Status:   status,
+			})
 			indexVersions[meta.Version.String()] = map[string]interface{}{}
 			if _, ok := indexArchives[version]; !ok {
 				indexArchives[version] = map[string]interface{}{}
@@ -355,6 +416,21 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		}
 	}
 
+	if len(packages) > 0 || len(pruned) > 0 {
+		counts := make(map[string]int)
+		for _, pkg := range packages {
+			counts[pkg.Status]++
+		}
+		c.Ui.Output(fmt.Sprintf(
+			"\nMirror summary: %d downloaded, %d already present, %d not required by this configuration, %d removed.",
+			counts[providersMirrorStatusDownloaded], counts[providersMirrorStatusUnchanged], counts[providersMirrorStatusRetained], len(pruned),
+		))
+	}
+
+	if optManifest != "" {
+		diags = diags.Append(writeProvidersMirrorManifest(optManifest, packages, pruned))
+	}
+
 	c.showDiagnostics(diags)
 	if diags.HasErrors() {
 		return 1
@@ -362,6 +438,190 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	return 0
 }
 
+// These are the values of the "status" property of the packages in the
+// manifest that "tofu providers mirror -manifest" writes.
+const (
+	// providersMirrorStatusDownloaded means that the package was downloaded
+	// into the mirror.
+	providersMirrorStatusDownloaded = "downloaded"
+
+	// providersMirrorStatusUnchanged means that the configuration requires
+	// the package and it was already present in the mirror.
+	providersMirrorStatusUnchanged = "unchanged"
+
+	// providersMirrorStatusRetained means that the package was already
+	// present in the mirror, but the configuration doesn't require it.
+	providersMirrorStatusRetained = "retained"
+)
+
+// providersMirrorManifest is the JSON representation of the manifest that
+// "tofu providers mirror -manifest" writes.
+type providersMirrorManifest struct {
+	FormatVersion string                           `json:"format_version"`
+	Packages      []providersMirrorManifestPackage `json:"packages"`
+	Pruned        []providersMirrorManifestPackage `json:"pruned"`
+}
+
+type providersMirrorManifestPackage struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+	Filename string `json:"filename"`
+	Hash     string `json:"hash,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// mirroredPackageValid returns whether the given package is already present
+// at the given path in the mirror directory and passes its authentication
+// checks, along with a description of the authentication result if so.
+//
+// A package without authentication is never considered valid, because there
+// would be no way to tell whether the file is the one the registry offers.
+func mirroredPackageValid(meta getproviders.PackageMeta, path string) (*getproviders.PackageAuthenticationResult, bool) {
+	if meta.Authentication == nil {
+		return nil, false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, false
+	}
+	result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(path))
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// pruneProvidersMirror removes the packages in the given mirror directory
+// whose versions the given dependency locks don't select, along with the
+// JSON index files that refer only to them, and returns the removed
+// packages.
+//
+// Only packages in the packed layout that "tofu providers mirror" produces
+// are removed, and the remaining JSON index files must be regenerated
+// afterwards.
+func pruneProvidersMirror(outputDir string, locks *depsfile.Locks) ([]providersMirrorManifestPackage, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	available, err := getproviders.SearchLocalDirectory(outputDir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to prune the mirror",
+			fmt.Sprintf("Could not scan the output directory for packages to remove: %s.", err),
+		))
+		return nil, diags
+	}
+
+	var pruned []providersMirrorManifestPackage
+	for provider, metas := range available {
+		var selected getproviders.Version
+		if lock := locks.Provider(provider); lock != nil {
+			selected = lock.Version()
+		}
+		removedVersions := make(map[getproviders.Version]bool)
+		keptVersions := make(map[getproviders.Version]bool)
+		for _, meta := range metas {
+			archivePath, ok := meta.Location.(getproviders.PackageLocalArchive)
+			if !ok {
+				continue
+			}
+			if meta.Version == selected {
+				keptVersions[meta.Version] = true
+				continue
+			}
+			if err := os.Remove(string(archivePath)); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to prune the mirror",
+					fmt.Sprintf("Failed to remove %s v%s for %s: %s.", provider, meta.Version, meta.TargetPlatform, err),
+				))
+				keptVersions[meta.Version] = true
+				continue
+			}
+			removedVersions[meta.Version] = true
+			pruned = append(pruned, providersMirrorManifestPackage{
+				Provider: provider.String(),
+				Version:  meta.Version.String(),
+				Platform: meta.TargetPlatform.String(),
+				Filename: mirrorPackageFilename(provider, meta.Version, meta.TargetPlatform),
+			})
+		}
+
+		indexDir := filepath.Dir(getproviders.PackedFilePathForPackage(
+			outputDir, provider, versions.Unspecified, getproviders.CurrentPlatform,
+		))
+		for version := range removedVersions {
+			if keptVersions[version] {
+				continue
+			}
+			diags = diags.Append(removeMirrorIndex(provider, filepath.Join(indexDir, version.String()+".json")))
+		}
+		if len(removedVersions) > 0 && len(keptVersions) == 0 {
+			diags = diags.Append(removeMirrorIndex(provider, filepath.Join(indexDir, "index.json")))
+		}
+	}
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Filename < pruned[j].Filename
+	})
+	return pruned, diags
+}
+
+func removeMirrorIndex(provider addrs.Provider, path string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to prune the mirror",
+			fmt.Sprintf("Failed to remove the JSON index %s for %s: %s.", filepath.Base(path), provider, err),
+		))
+	}
+	return diags
+}
+
+// writeProvidersMirrorManifest writes a JSON manifest describing the
+// packages in the mirror to the given path.
+func writeProvidersMirrorManifest(path string, packages, pruned []providersMirrorManifestPackage) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	manifest := providersMirrorManifest{
+		FormatVersion: "1.0",
+		Packages:      packages,
+		Pruned:        pruned,
+	}
+	if manifest.Packages == nil {
+		manifest.Packages = []providersMirrorManifestPackage{}
+	}
+	if manifest.Pruned == nil {
+		manifest.Pruned = []providersMirrorManifestPackage{}
+	}
+	sort.Slice(manifest.Packages, func(i, j int) bool {
+		return manifest.Packages[i].Filename < manifest.Packages[j].Filename
+	})
+
+	src, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		// Should never happen because the input here is entirely under
+		// our control.
+		panic(fmt.Sprintf("failed to encode mirror manifest: %s", err))
+	}
+	if err := os.WriteFile(path, append(src, '\n'), 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write the mirror manifest",
+			fmt.Sprintf("Could not write the mirror manifest to %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+// mirrorPackageFilename returns the path of the given package relative to
+// the mirror directory, with forward slashes, which is also its relative URL
+// in a network mirror.
+func mirrorPackageFilename(provider addrs.Provider, version getproviders.Version, platform getproviders.Platform) string {
+	return filepath.ToSlash(getproviders.PackedFilePathForPackage("", provider, version, platform))
+}
+
 func (c *ProvidersMirrorCommand) Help() string {
 	return `
 Usage: tofu [global options] providers mirror [options] <target-dir>
@@ -390,6 +650,16 @@ Options:
                      CPU. Each provider is available only for a limited
                      set of target platforms.
 
+  -prune             Remove the provider packages in the target directory
+                     whose versions the dependency lock file doesn't
+                     select, after updating the mirror. This requires a
+                     dependency lock file.
+
+  -manifest=path     Write a JSON manifest describing all of the provider
+                     packages in the mirror, what this command did with
+                     each of them, and which ones it removed, to the given
+                     path.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
@@ -6,10 +6,21 @@
 package command
 
 import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/getproviders"
 )
 
 // More thorough tests for providers mirror can be found in the e2etest
@@ -39,3 +50,162 @@ func TestProvidersMirror(t *testing.T) {
 		}
 	})
 }
+
+func TestProvidersMirror_pruneManifest(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	// The configuration is empty, so the mirror command doesn't need to
+	// contact any registry, but the dependency lock file still selects
+	// a version of one provider to check the pruning against.
+	keep := addrs.NewDefaultProvider("keep")
+	gone := addrs.NewDefaultProvider("gone")
+	locks := depsfile.NewLocks()
+	locks.SetProvider(keep, getproviders.MustParseVersion("1.0.0"), nil, nil)
+
+	outputDir := filepath.Join(td, "mirror")
+	platform := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	for _, pkg := range []struct {
+		provider addrs.Provider
+		version  string
+	}{
+		{keep, "1.0.0"},
+		{keep, "0.9.0"},
+		{gone, "2.0.0"},
+	} {
+		path := getproviders.PackedFilePathForPackage(outputDir, pkg.provider, getproviders.MustParseVersion(pkg.version), platform)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestProviderArchive(t, path)
+		indexDir := filepath.Dir(path)
+		for _, name := range []string{"index.json", pkg.version + ".json"} {
+			if err := os.WriteFile(filepath.Join(indexDir, name), []byte("{}"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ui := cli.NewMockUi()
+	c := &ProvidersMirrorCommand{
+		Meta: Meta{Ui: ui},
+	}
+	if err := c.replaceLockedDependencies(context.Background(), locks); err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := filepath.Join(td, "manifest.json")
+	if code := c.Run([]string{"-prune", "-manifest=" + manifestPath, outputDir}); code != 0 {
+		t.Fatalf("unexpected failure\n%s", ui.ErrorWriter.String())
+	}
+
+	var gotFiles []string
+	err := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		gotFiles = append(gotFiles, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(gotFiles)
+	wantFiles := []string{
+		"registry.opentofu.org/hashicorp/keep/1.0.0.json",
+		"registry.opentofu.org/hashicorp/keep/index.json",
+		"registry.opentofu.org/hashicorp/keep/terraform-provider-keep_1.0.0_linux_amd64.zip",
+	}
+	if diff := cmp.Diff(wantFiles, gotFiles); diff != "" {
+		t.Errorf("wrong files in mirror\n%s", diff)
+	}
+
+	src, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest providersMirrorManifest
+	if err := json.Unmarshal(src, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	for i := range manifest.Packages {
+		// The hash depends on details of the archive format that this test
+		// doesn't care about, so we only check that there is one.
+		if manifest.Packages[i].Hash == "" {
+			t.Errorf("no hash for %s", manifest.Packages[i].Filename)
+		}
+		manifest.Packages[i].Hash = ""
+	}
+	wantManifest := providersMirrorManifest{
+		FormatVersion: "1.0",
+		Packages: []providersMirrorManifestPackage{
+			{
+				Provider: "registry.opentofu.org/hashicorp/keep",
+				Version:  "1.0.0",
+				Platform: "linux_amd64",
+				Filename: "registry.opentofu.org/hashicorp/keep/terraform-provider-keep_1.0.0_linux_amd64.zip",
+				Status:   providersMirrorStatusRetained,
+			},
+		},
+		Pruned: []providersMirrorManifestPackage{
+			{
+				Provider: "registry.opentofu.org/hashicorp/gone",
+				Version:  "2.0.0",
+				Platform: "linux_amd64",
+				Filename: "registry.opentofu.org/hashicorp/gone/terraform-provider-gone_2.0.0_linux_amd64.zip",
+			},
+			{
+				Provider: "registry.opentofu.org/hashicorp/keep",
+				Version:  "0.9.0",
+				Platform: "linux_amd64",
+				Filename: "registry.opentofu.org/hashicorp/keep/terraform-provider-keep_0.9.0_linux_amd64.zip",
+			},
+		},
+	}
+	if diff := cmp.Diff(wantManifest, manifest); diff != "" {
+		t.Errorf("wrong manifest\n%s", diff)
+	}
+
+	if got, want := ui.OutputWriter.String(), "0 downloaded, 0 already present, 1 not required by this configuration, 2 removed"; !strings.Contains(got, want) {
+		t.Errorf("output doesn't contain %q\n%s", want, got)
+	}
+}
+
+func TestProvidersMirror_pruneWithoutLockFile(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	ui := cli.NewMockUi()
+	c := &ProvidersMirrorCommand{
+		Meta: Meta{Ui: ui},
+	}
+	if code := c.Run([]string{"-prune", filepath.Join(td, "mirror")}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Error: No dependency lock file"; !strings.Contains(got, want) {
+		t.Fatalf("error output doesn't contain %q\n%s", want, got)
+	}
+}
+
+// writeTestProviderArchive writes a zip archive to the given path, containing
+// a placeholder for a provider executable.
+func writeTestProviderArchive(t *testing.T, path string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	fw, err := w.Create("terraform-provider-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte("placeholder")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,6 +8,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -36,7 +37,9 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("providers schema")
 	c.Meta.varFlagSet(cmdFlags)
 	var jsonOutput bool
+	var format string
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.StringVar(&format, "format", "", "output format")
 
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
@@ -44,9 +47,18 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
-	if !jsonOutput {
+	switch {
+	case format != "" && format != "json" && format != "markdown":
+		c.Ui.Error(fmt.Sprintf("Unsupported output format %q. The -format option must be \"json\" or \"markdown\".\n", format))
+		return 1
+	case jsonOutput && format == "markdown":
+		c.Ui.Error("The -json and -format=markdown options are mutually-exclusive.\n")
+		return 1
+	case jsonOutput:
+		format = "json"
+	case format == "":
 		c.Ui.Error(
-			"The `tofu providers schema` command requires the `-json` flag.\n")
+			"The `tofu providers schema` command requires the `-json` flag or the `-format` option.\n")
 		cmdFlags.Usage()
 		return 1
 	}
@@ -127,6 +139,14 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
+	if format == "markdown" {
+		// The locks are only used to show the selected version of each
+		// provider, so we don't fail if they can't be loaded.
+		locks, _ := c.lockedDependencies()
+		c.Ui.Output(strings.TrimSuffix(providerSchemasMarkdown(schemas, locks), "\n"))
+		return 0
+	}
+
 	jsonSchemas, err := jsonprovider.Marshal(schemas)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to marshal provider schemas to json: %s", err))
@@ -138,13 +158,21 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 }
 
 const providersSchemaCommandHelp = `
-Usage: tofu [global options] providers schema [options] -json
+Usage: tofu [global options] providers schema [options] (-json | -format=markdown)
 
   Prints out a json representation of the schemas for all providers used 
   in the current configuration.
 
 Options:
 
+  -json              Print the schemas as JSON. Equivalent to -format=json.
+
+  -format=format     Format of the schemas. Can be: json or markdown. The
+                     markdown format renders documentation for the
+                     arguments and attributes of each resource type and data
+                     source, and for each function, of the selected provider
+                     versions.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
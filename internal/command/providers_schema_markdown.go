@@ -0,0 +1,267 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// providerSchemasMarkdown renders documentation for the resource types, data
+// sources, and functions of the given provider schemas as Markdown. If locks
+// is not nil, each provider's documentation includes its selected version.
+func providerSchemasMarkdown(schemas *tofu.Schemas, locks *depsfile.Locks) string {
+	var providerAddrs []addrs.Provider
+	for addr := range schemas.Providers {
+		providerAddrs = append(providerAddrs, addr)
+	}
+	sort.Slice(providerAddrs, func(i, j int) bool {
+		return providerAddrs[i].String() < providerAddrs[j].String()
+	})
+
+	var buf strings.Builder
+	buf.WriteString("# Provider Schemas\n")
+	for _, addr := range providerAddrs {
+		schema := schemas.Providers[addr]
+
+		fmt.Fprintf(&buf, "\n## %s\n", addr.ForDisplay())
+		if locks != nil {
+			if lock := locks.Provider(addr); lock != nil {
+				fmt.Fprintf(&buf, "\nVersion: `%s`\n", lock.Version())
+			}
+		}
+
+		if schema.Provider.Block != nil {
+			buf.WriteString("\n### Provider Configuration\n")
+			writeSchemaBlockMarkdown(&buf, schema.Provider.Block, "")
+		}
+		writeSchemaTypesMarkdown(&buf, "Resources", schema.ResourceTypes)
+		writeSchemaTypesMarkdown(&buf, "Data Sources", schema.DataSources)
+
+		if len(schema.Functions) != 0 {
+			buf.WriteString("\n### Functions\n")
+			for _, name := range sortedKeys(schema.Functions) {
+				writeFunctionMarkdown(&buf, addr, name, schema.Functions[name])
+			}
+		}
+	}
+	return buf.String()
+}
+
+func writeSchemaTypesMarkdown(buf *strings.Builder, title string, types map[string]providers.Schema) {
+	if len(types) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\n### %s\n", title)
+	for _, name := range sortedKeys(types) {
+		fmt.Fprintf(buf, "\n#### %s\n", name)
+		if block := types[name].Block; block != nil {
+			writeSchemaBlockMarkdown(buf, block, "")
+		}
+	}
+}
+
+// writeSchemaBlockMarkdown writes the description of the given block and
+// lists its arguments and read-only attributes, followed by the schemas of
+// any nested attributes and blocks, which are identified by their path from
+// the top-level block.
+func writeSchemaBlockMarkdown(buf *strings.Builder, block *configschema.Block, path string) {
+	if block.Description != "" {
+		fmt.Fprintf(buf, "\n%s\n", block.Description)
+	}
+	if block.Deprecated {
+		buf.WriteString("\n**Deprecated.**\n")
+	}
+
+	type item struct {
+		name, desc string
+		nested     *configschema.Block
+	}
+	var required, optional, readOnly []item
+	for _, name := range sortedKeys(block.Attributes) {
+		attr := block.Attributes[name]
+		it := item{name: name, desc: attributeMarkdown(name, attr)}
+		if attr.NestedType != nil {
+			it.nested = &configschema.Block{Attributes: attr.NestedType.Attributes}
+		}
+		switch {
+		case attr.Required:
+			required = append(required, it)
+		case attr.Optional:
+			optional = append(optional, it)
+		default:
+			readOnly = append(readOnly, it)
+		}
+	}
+	for _, name := range sortedKeys(block.BlockTypes) {
+		nb := block.BlockTypes[name]
+		it := item{name: name, desc: nestedBlockMarkdown(name, nb), nested: &nb.Block}
+		if nb.MinItems > 0 {
+			required = append(required, it)
+		} else {
+			optional = append(optional, it)
+		}
+	}
+
+	var nested []item
+	for _, section := range []struct {
+		title string
+		items []item
+	}{
+		{"Required", required},
+		{"Optional", optional},
+		{"Read-Only", readOnly},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "\n%s:\n\n", section.title)
+		for _, it := range section.items {
+			buf.WriteString(it.desc)
+			if it.nested != nil {
+				nested = append(nested, it)
+			}
+		}
+	}
+
+	for _, it := range nested {
+		nestedPath := it.name
+		if path != "" {
+			nestedPath = path + "." + it.name
+		}
+		fmt.Fprintf(buf, "\nNested schema for `%s`:\n", nestedPath)
+		writeSchemaBlockMarkdown(buf, it.nested, nestedPath)
+	}
+}
+
+func attributeMarkdown(name string, attr *configschema.Attribute) string {
+	var ty string
+	if attr.NestedType != nil {
+		switch attr.NestedType.Nesting {
+		case configschema.NestingList:
+			ty = "list of objects"
+		case configschema.NestingSet:
+			ty = "set of objects"
+		case configschema.NestingMap:
+			ty = "map of objects"
+		default:
+			ty = "object"
+		}
+	} else {
+		ty = "`" + typeexpr.TypeString(attr.Type) + "`"
+	}
+	var flags []string
+	if attr.Optional && attr.Computed {
+		flags = append(flags, "computed if not set")
+	}
+	if attr.Sensitive {
+		flags = append(flags, "sensitive")
+	}
+	if attr.Deprecated {
+		flags = append(flags, "deprecated")
+	}
+	return schemaItemMarkdown(name, ty, flags, attr.Description)
+}
+
+func nestedBlockMarkdown(name string, nb *configschema.NestedBlock) string {
+	var ty string
+	switch nb.Nesting {
+	case configschema.NestingList:
+		ty = "list of blocks"
+	case configschema.NestingSet:
+		ty = "set of blocks"
+	case configschema.NestingMap:
+		ty = "map of blocks"
+	default:
+		ty = "block"
+	}
+	var flags []string
+	switch {
+	case nb.MinItems > 0 && nb.MaxItems > 0:
+		flags = append(flags, fmt.Sprintf("%d to %d", nb.MinItems, nb.MaxItems))
+	case nb.MinItems > 0:
+		flags = append(flags, fmt.Sprintf("at least %d", nb.MinItems))
+	case nb.MaxItems > 0 && nb.Nesting != configschema.NestingSingle && nb.Nesting != configschema.NestingGroup:
+		flags = append(flags, fmt.Sprintf("at most %d", nb.MaxItems))
+	}
+	if nb.Deprecated {
+		flags = append(flags, "deprecated")
+	}
+	return schemaItemMarkdown(name, ty, flags, nb.Description)
+}
+
+// schemaItemMarkdown returns a list item describing one attribute or block,
+// with any further lines of its description indented to keep them in the
+// same item.
+func schemaItemMarkdown(name, ty string, flags []string, desc string) string {
+	details := append([]string{ty}, flags...)
+	item := fmt.Sprintf("- `%s` (%s)", name, strings.Join(details, ", "))
+	if desc = strings.TrimSpace(desc); desc != "" {
+		item += " " + strings.ReplaceAll(desc, "\n", "\n  ")
+	}
+	return item + "\n"
+}
+
+func writeFunctionMarkdown(buf *strings.Builder, provider addrs.Provider, name string, fn providers.FunctionSpec) {
+	fmt.Fprintf(buf, "\n#### %s\n", name)
+	if fn.Summary != "" {
+		fmt.Fprintf(buf, "\n%s\n", fn.Summary)
+	}
+	if fn.Description != "" && fn.Description != fn.Summary {
+		fmt.Fprintf(buf, "\n%s\n", fn.Description)
+	}
+	if fn.DeprecationMessage != "" {
+		fmt.Fprintf(buf, "\n**Deprecated:** %s\n", fn.DeprecationMessage)
+	}
+
+	var params []string
+	for _, p := range fn.Parameters {
+		params = append(params, p.Name+" "+typeexpr.TypeString(p.Type))
+	}
+	if p := fn.VariadicParameter; p != nil {
+		params = append(params, p.Name+" ..."+typeexpr.TypeString(p.Type))
+	}
+	fmt.Fprintf(buf, "\n```\nprovider::%s::%s(%s) %s\n```\n", provider.Type, name, strings.Join(params, ", "), typeexpr.TypeString(fn.Return))
+
+	if len(fn.Parameters) != 0 || fn.VariadicParameter != nil {
+		buf.WriteString("\nParameters:\n\n")
+		for _, p := range fn.Parameters {
+			buf.WriteString(functionParameterMarkdown(p, false))
+		}
+		if p := fn.VariadicParameter; p != nil {
+			buf.WriteString(functionParameterMarkdown(*p, true))
+		}
+	}
+}
+
+func functionParameterMarkdown(p providers.FunctionParameterSpec, variadic bool) string {
+	var flags []string
+	if variadic {
+		flags = append(flags, "variadic")
+	}
+	if p.AllowNullValue {
+		flags = append(flags, "nullable")
+	}
+	return schemaItemMarkdown(p.Name, "`"+typeexpr.TypeString(p.Type)+"`", flags, p.Description)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
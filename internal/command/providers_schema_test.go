@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -177,3 +178,99 @@ func providersSchemaFixtureSchema() *providers.GetProviderSchemaResponse {
 		},
 	}
 }
+
+func TestProvidersSchema_markdown(t *testing.T) {
+	want, err := os.ReadFile(testFixturePath("providers-schema/basic/output.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("providers-schema/basic"), td)
+	t.Chdir(td)
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer close()
+
+	p := providersSchemaFixtureProvider()
+	ui := new(cli.MockUi)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(p),
+		Ui:               ui,
+		ProviderSource:   providerSource,
+	}
+
+	ic := &InitCommand{
+		Meta: m,
+	}
+	if code := ic.Run([]string{}); code != 0 {
+		t.Fatalf("init failed\n%s", ui.ErrorWriter)
+	}
+	ui.OutputWriter.Reset()
+
+	pc := &ProvidersSchemaCommand{Meta: m}
+	if code := pc.Run([]string{"-format=markdown"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+	if diff := cmp.Diff(string(want), ui.OutputWriter.String()); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestProvidersSchema_markdownNestedBlocks(t *testing.T) {
+	block := &configschema.Block{
+		Description: "A web server.",
+		Attributes: map[string]*configschema.Attribute{
+			"password": {
+				Type:        cty.String,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The admin password.\nChange it regularly.",
+			},
+			"arn": {Type: cty.String, Computed: true},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"listener": {
+				Nesting:  configschema.NestingList,
+				MinItems: 1,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"port": {Type: cty.Number, Required: true},
+					},
+					BlockTypes: map[string]*configschema.NestedBlock{
+						"tls": {
+							Nesting: configschema.NestingSingle,
+							Block: configschema.Block{
+								Attributes: map[string]*configschema.Attribute{
+									"legacy": {Type: cty.Bool, Optional: true, Deprecated: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf strings.Builder
+	writeSchemaBlockMarkdown(&buf, block, "")
+	want := "\nA web server.\n" +
+		"\nRequired:\n\n" +
+		"- `password` (`string`, sensitive) The admin password.\n  Change it regularly.\n" +
+		"- `listener` (list of blocks, at least 1)\n" +
+		"\nRead-Only:\n\n" +
+		"- `arn` (`string`)\n" +
+		"\nNested schema for `listener`:\n" +
+		"\nRequired:\n\n" +
+		"- `port` (`number`)\n" +
+		"\nOptional:\n\n" +
+		"- `tls` (block)\n" +
+		"\nNested schema for `listener.tls`:\n" +
+		"\nOptional:\n\n" +
+		"- `legacy` (`bool`, deprecated)\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
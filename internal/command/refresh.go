@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
@@ -70,6 +72,8 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.providerParallelism = args.Operation.ProviderParallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
 
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
@@ -140,6 +144,13 @@ func (c *RefreshCommand) PrepareBackend(ctx context.Context, args *arguments.Sta
 		return nil, diags
 	}
 
+	// Check the workspace given with -workspace, if any, now that we know
+	// which backend it belongs to.
+	diags = diags.Append(c.prepareWorkspaceOverride(ctx, be))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	return be, diags
 }
 
@@ -153,6 +164,7 @@ func (c *RefreshCommand) OperationRequest(ctx context.Context, be backend.Enhanc
 	opReq.Hooks = view.Hooks()
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
+	opReq.TargetSelectors = args.TargetSelectors
 	opReq.Type = backend.OperationTypeRefresh
 	opReq.View = view.Operation()
 
@@ -183,6 +195,31 @@ func (c *RefreshCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *RefreshCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RefreshCommand) AutocompleteFlags() complete.Flags {
+	ctx := c.CommandContext()
+	return complete.Flags{
+		"-backup":              complete.PredictFiles("*.tfstate"),
+		"-compact-warnings":    complete.PredictNothing,
+		"-exclude":             c.completePredictResourceAddress(ctx),
+		"-input":               completePredictBoolean,
+		"-json":                complete.PredictNothing,
+		"-lock":                completePredictBoolean,
+		"-lock-timeout":        complete.PredictAnything,
+		"-no-color":            complete.PredictNothing,
+		"-parallelism":         complete.PredictAnything,
+		"-refresh-parallelism": complete.PredictAnything,
+		"-state":               complete.PredictFiles("*.tfstate"),
+		"-state-out":           complete.PredictFiles("*.tfstate"),
+		"-target":              c.completePredictResourceAddress(ctx),
+		"-var":                 c.completePredictVariableAssignment(ctx),
+		"-var-file":            complete.PredictFiles("*.tfvars"),
+	}
+}
+
 func (c *RefreshCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] refresh [options]
@@ -213,6 +250,9 @@ Options:
                          resources. This flag can be used multiple times. Cannot
                          be used alongside the -target flag.
 
+  -exclude-file=filename Similar to -exclude, but reads the resource addresses
+                         from a file, one per line.
+
   -input=true            Ask for input for variables if not directly set.
 
   -lock=false            Don't hold a state lock during the operation. This is
@@ -226,12 +266,26 @@ Options:
   -concise               Disables progress-related messages in the output.
 
   -parallelism=n         Limit the number of concurrent operations. Defaults to 10.
+                         Use provider=n, for example -parallelism=aws=10, to
+                         set a lower limit for a specific provider.
+
+  -refresh-parallelism=n Give the resources of each provider configuration
+                         their own limit of n concurrent operations, instead
+                         of sharing the overall -parallelism limit, so that a
+                         slow provider doesn't hold up the others.
 
   -target=resource       Resource to target. Operation will be limited to this
                          resource and its dependencies. This flag can be used
                          multiple times.  Cannot be used alongside the -exclude
                          flag.
 
+  -target-file=filename  Similar to -target, but reads the resource addresses
+                         from a file, one per line.
+
+  -target-selector=expr  Similar to -target, but targets the resource
+                         instances whose attribute values in the current
+                         state make the given expression true.
+
   -var 'foo=bar'         Set a variable in the OpenTofu configuration. This
                          flag can be set multiple times.
 
@@ -239,6 +293,12 @@ Options:
                          a file. If "terraform.tfvars" or any ".auto.tfvars"
                          files are present, they will be automatically loaded.
 
+  -workspace=name[,create]
+                         Use the given workspace for this command only,
+                         instead of the currently selected workspace. Add
+                         ",create" to create the workspace if it doesn't
+                         already exist.
+
   -json                  Produce output in a machine-readable JSON format,
                          suitable for use in text editor integrations and 
                          other automated systems. Always disables color.
@@ -0,0 +1,236 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package regopolicy evaluates the Rego policies configured with "rego_policy"
+// blocks in the CLI configuration against plans, using the Open Policy Agent
+// command line tool, so that "tofu apply" checks them without a separate step.
+//
+// The policies come only from the CLI configuration of the user running
+// OpenTofu, not from the working directory, so they're enforced only where
+// that CLI configuration is in place.
+package regopolicy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+const (
+	// DefaultProgram is the OPA program to run if a policy doesn't name one.
+	DefaultProgram = "opa"
+
+	// DefaultQuery is the Rego query to evaluate if a policy doesn't give
+	// one. The policies are expected to define "deny" and "warn" rules in
+	// the "tofu" package, in the same way as for conftest.
+	DefaultQuery = "data.tofu"
+)
+
+// Policy is a set of Rego policies to check plans with.
+//
+// OpenTofu evaluates the query with "opa eval", with the JSON representation
+// of the plan as its input, in the same format as "tofu show -json" produces
+// for a saved plan. The query must produce an object whose "deny" and "warn"
+// properties, both optional, are sets of messages.
+type Policy struct {
+	// Name is the label of the rego_policy block that configured this
+	// policy, used in messages about it.
+	Name string
+
+	// Paths are the Rego files or directories of them to load.
+	Paths []string
+
+	// Query is the Rego query to evaluate.
+	Query string
+
+	// Program is the path to the OPA program. If it doesn't contain a path
+	// separator, it's looked up in the directories named by the PATH
+	// environment variable.
+	Program string
+
+	// OverrideTokenHash, if set, is the hex-encoded SHA-256 hash of a token
+	// that can be given to override this policy's denials.
+	OverrideTokenHash string
+}
+
+// Result is the outcome of evaluating a policy.
+type Result struct {
+	Deny []string
+	Warn []string
+}
+
+// opaOutput is the subset of the output of "opa eval --format=json" that we
+// need.
+type opaOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// Evaluate runs OPA to evaluate the policy with the given JSON plan as its
+// input.
+//
+// A query that produces no value, such as one that refers to a package that
+// none of the policy files define, is an error rather than a pass, so that a
+// mistake in the policy's configuration doesn't let every plan through.
+func (p *Policy) Evaluate(ctx context.Context, planJSON []byte) (*Result, error) {
+	program := p.Program
+	if program == "" {
+		program = DefaultProgram
+	}
+	query := p.Query
+	if query == "" {
+		query = DefaultQuery
+	}
+	args := []string{"eval", "--format=json", "--stdin-input"}
+	for _, path := range p.Paths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, query)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Stdin = bytes.NewReader(planJSON)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if _, isExitErr := err.(*exec.ExitError); isExitErr {
+		errText := strings.TrimSpace(stderr.String())
+		if errText == "" {
+			errText = strings.TrimSpace(stdout.String())
+		}
+		if errText == "" {
+			return nil, fmt.Errorf("%s failed, but it produced no error message", program)
+		}
+		return nil, fmt.Errorf("%s failed: %s", program, errText)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", program, err)
+	}
+
+	var out opaOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("malformed output from %s: %w", program, err)
+	}
+	if len(out.Result) == 0 {
+		return nil, fmt.Errorf("the query %s is undefined, so none of the policies were evaluated", query)
+	}
+	ret := &Result{}
+	for _, result := range out.Result {
+		if len(result.Expressions) == 0 {
+			return nil, fmt.Errorf("the query %s produced a result with no value", query)
+		}
+		for _, expr := range result.Expressions {
+			var value *struct {
+				Deny []json.RawMessage `json:"deny"`
+				Warn []json.RawMessage `json:"warn"`
+			}
+			if err := json.Unmarshal(expr.Value, &value); err != nil {
+				return nil, fmt.Errorf("the query %s must produce an object with deny and warn sets of messages: %w", query, err)
+			}
+			if value == nil {
+				return nil, fmt.Errorf("the query %s must produce an object with deny and warn sets of messages, not null", query)
+			}
+			ret.Deny = append(ret.Deny, messages(value.Deny)...)
+			ret.Warn = append(ret.Warn, messages(value.Warn)...)
+		}
+	}
+	return ret, nil
+}
+
+// messages returns the given policy messages as strings. Messages that aren't
+// strings, such as objects with more detail, are shown as JSON.
+func messages(raws []json.RawMessage) []string {
+	ret := make([]string, 0, len(raws))
+	for _, raw := range raws {
+		var msg string
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			msg = string(raw)
+		}
+		ret = append(ret, msg)
+	}
+	return ret
+}
+
+// Overridden returns true if the given token overrides this policy's
+// denials.
+func (p *Policy) Overridden(token string) bool {
+	if p.OverrideTokenHash == "" || token == "" {
+		return false
+	}
+	hash := sha256.Sum256([]byte(token))
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(hash[:])), []byte(strings.ToLower(p.OverrideTokenHash))) == 1
+}
+
+// CheckPlan evaluates each of the given policies against the JSON
+// representation of the given plan, and returns an error for each denial
+// and a warning for each warning.
+//
+// A policy whose denials are overridden by the given token has them returned
+// as warnings instead. A policy that can't be evaluated is an error, since
+// the changes mustn't be applied without being checked.
+func CheckPlan(ctx context.Context, policies []*Policy, overrideToken string, config *configs.Config, plan *plans.Plan, priorStateFile *statefile.File, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if len(policies) == 0 {
+		return diags
+	}
+
+	planJSON, err := jsonplan.Marshal(config, plan, priorStateFile, schemas)
+	if err != nil {
+		return diags.Append(fmt.Errorf("failed to encode the plan for policy checks: %w", err))
+	}
+
+	for _, p := range policies {
+		result, err := p.Evaluate(ctx, planJSON)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to check policy",
+				fmt.Sprintf("The policy %q couldn't be evaluated, so the changes can't be applied: %s.", p.Name, err),
+			))
+			continue
+		}
+
+		overridden := p.Overridden(overrideToken)
+		for _, msg := range result.Deny {
+			if overridden {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Policy denial overridden",
+					fmt.Sprintf("The policy %q denied the changes, but its denials were overridden: %s", p.Name, msg),
+				))
+				continue
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Policy denied the changes",
+				fmt.Sprintf("The policy %q denied the changes: %s", p.Name, msg),
+			))
+		}
+		for _, msg := range result.Warn {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Policy warning",
+				fmt.Sprintf("The policy %q warned about the changes: %s", p.Name, msg),
+			))
+		}
+	}
+	return diags
+}
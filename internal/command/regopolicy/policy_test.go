@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package regopolicy
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	// The OPA stand-in used in this test assumes a Unix-like environment
+	// where scripts are directly executable based on their #! line and where
+	// bash is available, like the credentials helper tests do.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := filepath.Join(wd, "testdata", "test-opa")
+	planJSON := []byte(`{"format_version":"1.2"}`)
+
+	t.Run("deny and warn", func(t *testing.T) {
+		p := &Policy{Name: "test", Program: program, Paths: []string{"policies"}}
+		got, err := p.Evaluate(t.Context(), planJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &Result{
+			Deny: []string{"instance has no tags"},
+			Warn: []string{`{"resource":"test_instance.foo"}`},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("no rules", func(t *testing.T) {
+		p := &Policy{Name: "test", Program: program, Query: "data.clean"}
+		got, err := p.Evaluate(t.Context(), planJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Deny) != 0 || len(got.Warn) != 0 {
+			t.Errorf("unexpected result: %#v", got)
+		}
+	})
+	t.Run("no value", func(t *testing.T) {
+		// A query that produces nothing must not let the plan through.
+		tests := map[string]string{
+			"data.undefined": "is undefined",
+			"data.noexpr":    "produced a result with no value",
+			"data.null":      "not null",
+		}
+		for query, want := range tests {
+			p := &Policy{Name: "test", Program: program, Query: query}
+			_, err := p.Evaluate(t.Context(), planJSON)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("wrong error for %s: %v", query, err)
+			}
+		}
+	})
+	t.Run("evaluation failure", func(t *testing.T) {
+		p := &Policy{Name: "test", Program: program, Query: "data.broken"}
+		_, err := p.Evaluate(t.Context(), planJSON)
+		if err == nil || !strings.Contains(err.Error(), "failed: 1 error occurred: undefined ref") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+	t.Run("missing program", func(t *testing.T) {
+		p := &Policy{Name: "test", Program: filepath.Join(wd, "testdata", "nonexistent")}
+		_, err := p.Evaluate(t.Context(), planJSON)
+		if err == nil || !strings.Contains(err.Error(), "failed to run") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+}
+
+func TestPolicyOverridden(t *testing.T) {
+	// This is the SHA-256 hash of "let-me-through".
+	p := &Policy{OverrideTokenHash: "952C46E05E2BC3AF57296712457295DAE617FAF086FF88EE759CB188F086CAC0"}
+	if !p.Overridden("let-me-through") {
+		t.Error("correct token doesn't override the policy")
+	}
+	if p.Overridden("") {
+		t.Error("empty token overrides the policy")
+	}
+	if p.Overridden("wrong") {
+		t.Error("wrong token overrides the policy")
+	}
+
+	noHash := &Policy{}
+	if noHash.Overridden("let-me-through") {
+		t.Error("token overrides a policy without an override token hash")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package runhook runs the external programs configured with "run_hook"
+// blocks in the CLI configuration at points in the lifecycle of plan and
+// apply operations, so that organizations can enforce their own rules or
+// send notifications without wrapping the OpenTofu CLI.
+package runhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// InputFormatVersion is the version of the JSON format of Input.
+const InputFormatVersion = "1.0"
+
+// Event identifies a point in the lifecycle of an operation where run hooks
+// can run.
+type Event string
+
+const (
+	// EventPrePlan is before OpenTofu Core creates a plan.
+	EventPrePlan Event = "pre_plan"
+
+	// EventPostPlan is after OpenTofu Core has created a plan, before it's
+	// rendered.
+	EventPostPlan Event = "post_plan"
+
+	// EventPreApply is after a plan has been approved, before OpenTofu Core
+	// starts to apply it.
+	EventPreApply Event = "pre_apply"
+
+	// EventPostApply is after OpenTofu Core has applied a plan and the new
+	// state has been persisted, whether or not all of the changes succeeded.
+	EventPostApply Event = "post_apply"
+)
+
+// Events are all of the valid events, in the order they happen.
+var Events = []Event{
+	EventPrePlan,
+	EventPostPlan,
+	EventPreApply,
+	EventPostApply,
+}
+
+// Blocking returns true if a run hook that fails at this event stops the
+// operation. Hooks can't stop an apply that has already happened, so their
+// failures after the apply are only warnings.
+func (e Event) Blocking() bool {
+	return e != EventPostApply
+}
+
+// Input is the JSON object that each run hook program receives on its
+// standard input.
+type Input struct {
+	FormatVersion string `json:"format_version"`
+	Event         Event  `json:"event"`
+	Workspace     string `json:"workspace"`
+
+	// Plan is the plan in the same JSON format as "tofu show -json" produces
+	// for a saved plan, for the post_plan and pre_apply events.
+	Plan json.RawMessage `json:"plan,omitempty"`
+
+	// State is the new state in the same JSON format as "tofu show -json"
+	// produces for the state, for the post_apply event.
+	State json.RawMessage `json:"state,omitempty"`
+
+	// Errored is true for the post_apply event if any of the changes failed.
+	Errored bool `json:"errored,omitempty"`
+}
+
+// Hook is an external program to run at some of the events.
+type Hook struct {
+	// Name is the label of the run_hook block that configured this hook,
+	// used in messages about it.
+	Name string
+
+	// Program is the path to the program to run. If it doesn't contain a
+	// path separator, it's looked up in the directories named by the PATH
+	// environment variable.
+	Program string
+
+	// Args are the arguments to pass to the program.
+	Args []string
+
+	// Events are the events to run the program at. If empty, it runs at all
+	// of them.
+	Events []Event
+}
+
+// Subscribed returns true if the hook runs at the given event.
+func (h *Hook) Subscribed(event Event) bool {
+	return len(h.Events) == 0 || slices.Contains(h.Events, event)
+}
+
+// Run runs the program with the given JSON input.
+//
+// The program must exit with a non-zero status, and explain why on its
+// standard error, to report a failure. Anything it writes to its standard
+// output is only logged.
+func (h *Hook) Run(ctx context.Context, input []byte) error {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Program, h.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if out := strings.TrimSpace(stdout.String()); out != "" {
+		log.Printf("[INFO] runhook: output from %q: %s", h.Name, out)
+	}
+	if _, isExitErr := err.(*exec.ExitError); isExitErr {
+		errText := strings.TrimSpace(stderr.String())
+		if errText == "" {
+			return fmt.Errorf("%s failed, but it produced no error message", h.Program)
+		}
+		return fmt.Errorf("%s failed: %s", h.Program, errText)
+	} else if err != nil {
+		return fmt.Errorf("failed to run %s: %w", h.Program, err)
+	}
+	return nil
+}
+
+// Hooks are the run hooks from the CLI configuration, in the order to run
+// them.
+type Hooks []Hook
+
+// Subscribed returns true if any of the hooks run at the given event.
+func (hs Hooks) Subscribed(event Event) bool {
+	for i := range hs {
+		if hs[i].Subscribed(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs each of the hooks that are subscribed to the given event with the
+// given input, which has its format version and event set here.
+//
+// Failures are errors for blocking events, and the remaining hooks don't run
+// once one has failed. For other events they're warnings, and all of the
+// hooks run.
+func (hs Hooks) Run(ctx context.Context, event Event, input *Input) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !hs.Subscribed(event) {
+		return diags
+	}
+
+	input.FormatVersion = InputFormatVersion
+	input.Event = event
+	src, err := json.Marshal(input)
+	if err != nil {
+		return diags.Append(fmt.Errorf("failed to encode the input for run hooks: %w", err))
+	}
+
+	for i := range hs {
+		hook := &hs[i]
+		if !hook.Subscribed(event) {
+			continue
+		}
+		log.Printf("[INFO] runhook: running %q for %s", hook.Name, event)
+		err := hook.Run(ctx, src)
+		if err == nil {
+			continue
+		}
+		if event.Blocking() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Run hook failed",
+				fmt.Sprintf("The run hook %q failed at %s, so the operation can't continue: %s.", hook.Name, event, err),
+			))
+			return diags
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Run hook failed",
+			fmt.Sprintf("The run hook %q failed at %s: %s.", hook.Name, event, err),
+		))
+	}
+	return diags
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package runhook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestHooksRun(t *testing.T) {
+	// The hook script used in this test assumes a Unix-like environment
+	// where scripts are directly executable based on their #! line and where
+	// bash is available, like the credentials helper tests do.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := filepath.Join(wd, "testdata", "test-hook")
+
+	t.Run("input", func(t *testing.T) {
+		inputPath := filepath.Join(t.TempDir(), "input.json")
+		hooks := Hooks{
+			{Name: "audit", Program: program, Args: []string{"pass", inputPath}},
+		}
+		diags := hooks.Run(t.Context(), EventPostPlan, &Input{
+			Workspace: "prod",
+			Plan:      json.RawMessage(`{"format_version":"1.2"}`),
+		})
+		if len(diags) != 0 {
+			t.Fatal(diags.Err())
+		}
+
+		src, err := os.ReadFile(inputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := strings.TrimSpace(string(src))
+		want := `{"format_version":"1.0","event":"post_plan","workspace":"prod","plan":{"format_version":"1.2"}}`
+		if got != want {
+			t.Errorf("wrong input\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("unsubscribed", func(t *testing.T) {
+		inputPath := filepath.Join(t.TempDir(), "input.json")
+		hooks := Hooks{
+			{Name: "audit", Program: program, Args: []string{"fail", inputPath}, Events: []Event{EventPreApply}},
+		}
+		if diags := hooks.Run(t.Context(), EventPrePlan, &Input{}); len(diags) != 0 {
+			t.Fatal(diags.Err())
+		}
+		if _, err := os.Stat(inputPath); !os.IsNotExist(err) {
+			t.Errorf("hook ran for an event it isn't subscribed to")
+		}
+	})
+	t.Run("blocking failure", func(t *testing.T) {
+		inputPath := filepath.Join(t.TempDir(), "input.json")
+		hooks := Hooks{
+			{Name: "tickets", Program: program, Args: []string{"fail"}},
+			{Name: "audit", Program: program, Args: []string{"pass", inputPath}},
+		}
+		diags := hooks.Run(t.Context(), EventPreApply, &Input{})
+		if !diags.HasErrors() {
+			t.Fatal("expected an error")
+		}
+		if got, want := diags.Err().Error(), `The run hook "tickets" failed at pre_apply, so the operation can't continue`; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := diags.Err().Error(), "missing ticket reference"; !strings.Contains(got, want) {
+			t.Errorf("error is missing the hook's message\ngot:  %s\nwant: %s", got, want)
+		}
+		if _, err := os.Stat(inputPath); !os.IsNotExist(err) {
+			t.Errorf("hook ran after an earlier hook failed")
+		}
+	})
+	t.Run("non-blocking failure", func(t *testing.T) {
+		inputPath := filepath.Join(t.TempDir(), "input.json")
+		hooks := Hooks{
+			{Name: "notify", Program: program, Args: []string{"silent-fail"}},
+			{Name: "audit", Program: program, Args: []string{"pass", inputPath}},
+		}
+		diags := hooks.Run(t.Context(), EventPostApply, &Input{Errored: true})
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		if len(diags) != 1 || diags[0].Severity() != tfdiags.Warning {
+			t.Fatalf("expected a single warning, got %#v", diags)
+		}
+		if got, want := diags[0].Description().Detail, "produced no error message"; !strings.Contains(got, want) {
+			t.Errorf("wrong warning\ngot:  %s\nwant: %s", got, want)
+		}
+		if _, err := os.Stat(inputPath); err != nil {
+			t.Errorf("later hook didn't run after a non-blocking failure: %s", err)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -21,6 +22,8 @@ import (
 	"github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonformat"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -88,7 +91,21 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	defer span.End()
 
 	// Set up view
-	view := views.NewShow(args.ViewType, c.View)
+	var view views.Show
+	if args.ViewType == arguments.ViewMarkdown {
+		view = views.NewShowMarkdown(c.View, jsonformat.MarkdownOpts{
+			MaxDiffLines: args.MarkdownMaxDiffLines,
+			MaxLength:    args.MarkdownMaxLength,
+		})
+	} else {
+		view = views.NewShow(args.ViewType, c.View)
+	}
+	if len(args.Filters) != 0 || args.FilterModule != nil {
+		view = filteredShow{
+			Show:   view,
+			filter: showFilter{patterns: args.Filters, module: args.FilterModule},
+		}
+	}
 
 	// Check for user-supplied plugin path
 	var err error
@@ -109,7 +126,13 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
-	renderResult, showDiags := c.show(ctx, args.TargetType, args.TargetArg, enc)
+	var renderResult showRenderFunc
+	var showDiags tfdiags.Diagnostics
+	if args.Metadata {
+		renderResult, showDiags = c.showPlanMetadata(ctx, args.TargetArg, enc)
+	} else {
+		renderResult, showDiags = c.show(ctx, args.TargetType, args.TargetArg, enc)
+	}
 	diags = diags.Append(showDiags)
 	if showDiags.HasErrors() {
 		// "tofu show" intentionally ignores warnings unless there is at
@@ -146,6 +169,43 @@ Other options:
 
   -json               Show the information in a machine-readable form.
 
+  -sarif              Show a saved plan as a SARIF report, for code scanning
+                      tools. Destructive changes, failed checks, and uses of
+                      deprecated features are reported as results located in
+                      the configuration.
+
+  -markdown           Show a saved plan as Markdown, for posting as a pull
+                      request comment. Each resource change is shown as a
+                      collapsible diff below a summary table.
+
+  -markdown-max-diff-lines=n
+                      Limit the diff shown for each resource to n lines.
+                      Requires -markdown.
+
+  -markdown-max-length=n
+                      Limit the Markdown output to n bytes, omitting the
+                      remaining resource changes once the limit is reached.
+                      Requires -markdown.
+
+  -html               Show a saved plan as a self-contained HTML report, with
+                      searchable resource diffs and a graph of the
+                      dependencies between changed resources.
+
+  -meta               Show only when a saved plan was created, when it
+                      expires, and the state snapshot and backend it
+                      applies to, instead of the changes it proposes. Can be
+                      combined with -json.
+
+  -filter=pattern     Show only the resource instances whose address matches
+                      the pattern, where * matches any sequence of
+                      characters and ? matches any single character, such
+                      as -filter='aws_instance.*'. Use this option more than
+                      once to show instances matching any of the patterns.
+
+  -filter-module=addr Show only the resource instances in the given module,
+                      such as module.app, and its child modules. Can be
+                      combined with -filter.
+
   -show-sensitive     If specified, sensitive values will be displayed.
 
   -var 'foo=bar'      Set a value for one of the input variables in the root
@@ -267,12 +327,67 @@ func (c *ShowCommand) showFromSavedPlanFile(ctx context.Context, filename string
 	if schemaDiags.HasErrors() {
 		return nil, diags
 	}
+	diags = diags.Append(c.estimatePlanCost(ctx, plan, config, stateFile, schemas))
 
 	return func(view views.Show) int {
 		return view.DisplayPlan(ctx, plan, jsonPlan, config, stateFile, schemas)
 	}, diags
 }
 
+// showPlanMetadata shows where the saved plan file came from and whether it
+// can still be applied. Unlike showing the plan itself this doesn't need the
+// provider schemas, so it works even without an initialized working
+// directory.
+func (c *ShowCommand) showPlanMetadata(ctx context.Context, filename string, enc encryption.Encryption) (showRenderFunc, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	_, span := tracing.Tracer().Start(ctx, "Show Plan Metadata")
+	defer span.End()
+
+	pf, err := planfile.OpenWrapped(filename, enc.Plan())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Couldn't read %s as a saved plan file: %s.", filename, err),
+		))
+		return nil, diags
+	}
+	lp, ok := pf.Local()
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan metadata not available",
+			"The -meta option can't be used with a saved cloud plan, because its metadata is stored remotely.",
+		))
+		return nil, diags
+	}
+
+	plan, err := lp.ReadPlan()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Couldn't read the plan from %s: %s.", filename, err),
+		))
+		return nil, diags
+	}
+	stateFile, err := lp.ReadStateFile()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Couldn't read the prior state snapshot from %s: %s.", filename, err),
+		))
+		return nil, diags
+	}
+
+	meta := jsonplan.NewMetadata(plan, stateFile, time.Now())
+	return func(view views.Show) int {
+		return view.DisplayPlanMetadata(meta)
+	}, diags
+}
+
 func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc encryption.Encryption) (showRenderFunc, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	var planErr, stateErr error
@@ -360,6 +475,7 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 		tracing.SetSpanError(span, diags)
 		return nil, diags
 	}
+	diags = diags.Append(c.estimatePlanCost(ctx, plan, config, stateFile, schemas))
 
 	// If we successfully loaded some things then the show mode we
 	// choose depends on what we loaded.
@@ -377,6 +493,16 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 	}
 }
 
+// estimatePlanCost records the cost estimate of a local plan in the plan, if
+// there's a cost estimator in the CLI configuration. Cost estimates aren't
+// saved in plan files, so we must run the estimator each time we show one.
+func (c *ShowCommand) estimatePlanCost(ctx context.Context, plan *plans.Plan, config *configs.Config, stateFile *statefile.File, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	if c.CostEstimator == nil || plan == nil || config == nil || schemas == nil || !plan.CanApply() {
+		return nil
+	}
+	return c.CostEstimator.AttachToPlan(ctx, config, plan, stateFile, schemas)
+}
+
 // getPlanFromPath returns a plan, json plan, statefile, and config if the
 // user-supplied path points to either a local or cloud plan file. Note that
 // some of the return values will be nil no matter what; local plan files do not
@@ -0,0 +1,183 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// showFilter selects the resource instances that "tofu show" includes when
+// rendering a state snapshot or plan, as requested by the -filter and
+// -filter-module options.
+type showFilter struct {
+	patterns []string
+	module   addrs.ModuleInstance
+}
+
+// Match returns true if the given resource instance should be shown.
+func (f showFilter) Match(addr addrs.AbsResourceInstance) bool {
+	if f.module != nil && !moduleFilterMatch(f.module, addr.Module) {
+		return false
+	}
+	if len(f.patterns) == 0 {
+		return true
+	}
+	instance := addr.String()
+	resource := addr.ContainingResource().String()
+	for _, pattern := range f.patterns {
+		if addressGlobMatch(pattern, instance) || addressGlobMatch(pattern, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterState returns a copy of the given state containing only the resource
+// instances that match the filter. Output values are retained as-is.
+func (f showFilter) FilterState(state *states.State) *states.State {
+	if state == nil {
+		return nil
+	}
+	ret := state.DeepCopy()
+	for _, ms := range ret.Modules {
+		for _, rs := range ms.Resources {
+			for key := range rs.Instances {
+				if !f.Match(rs.Addr.Instance(key)) {
+					ms.ForgetResourceInstanceAll(rs.Addr.Resource.Instance(key))
+				}
+			}
+		}
+		if !ms.Addr.IsRoot() && len(ms.Resources) == 0 && len(ms.OutputValues) == 0 {
+			ret.RemoveModule(ms.Addr)
+		}
+	}
+	return ret
+}
+
+// FilterPlan returns a shallow copy of the given plan whose resource changes,
+// drift, and states include only the resource instances that match the
+// filter. Output changes are retained as-is.
+func (f showFilter) FilterPlan(plan *plans.Plan) *plans.Plan {
+	if plan == nil {
+		return nil
+	}
+	ret := *plan
+	if plan.Changes != nil {
+		ret.Changes = &plans.Changes{
+			Resources: f.filterChanges(plan.Changes.Resources),
+			Outputs:   plan.Changes.Outputs,
+		}
+	}
+	ret.DriftedResources = f.filterChanges(plan.DriftedResources)
+	ret.PrevRunState = f.FilterState(plan.PrevRunState)
+	ret.PriorState = f.FilterState(plan.PriorState)
+	ret.PlannedState = f.FilterState(plan.PlannedState)
+	return &ret
+}
+
+func (f showFilter) filterChanges(changes []*plans.ResourceInstanceChangeSrc) []*plans.ResourceInstanceChangeSrc {
+	var ret []*plans.ResourceInstanceChangeSrc
+	for _, rc := range changes {
+		if f.Match(rc.Addr) {
+			ret = append(ret, rc)
+		}
+	}
+	return ret
+}
+
+// moduleFilterMatch returns true if the module instance addr is the filter
+// module or one of its descendants. A step of the filter without an instance
+// key matches every instance of that module call.
+func moduleFilterMatch(filter, addr addrs.ModuleInstance) bool {
+	if len(addr) < len(filter) {
+		return false
+	}
+	for i, step := range filter {
+		if step.Name != addr[i].Name {
+			return false
+		}
+		if step.InstanceKey != addrs.NoKey && step.InstanceKey != addr[i].InstanceKey {
+			return false
+		}
+	}
+	return true
+}
+
+// addressGlobMatch reports whether the address s matches pattern, where "*"
+// matches any sequence of characters and "?" matches any single character.
+// Unlike path.Match, "*" also matches the "." and "[" characters that
+// separate address steps, and brackets in the pattern are literal so that
+// instance keys can be written as they appear in addresses.
+func addressGlobMatch(pattern, s string) bool {
+	p, n := 0, 0
+	// When we hit a mismatch after a "*", we retry with the star consuming
+	// one more character of s.
+	star, retry := -1, 0
+	for n < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, retry = p, n
+			p++
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[n]):
+			p++
+			n++
+		case star >= 0:
+			retry++
+			p, n = star+1, retry
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// filteredShow wraps a [views.Show] so that the state snapshots and plans it
+// renders include only the resource instances selected by a [showFilter].
+type filteredShow struct {
+	views.Show
+	filter showFilter
+}
+
+func (v filteredShow) DisplayState(ctx context.Context, stateFile *statefile.File, schemas *tofu.Schemas) int {
+	if stateFile != nil {
+		filtered := *stateFile
+		filtered.State = v.filter.FilterState(stateFile.State)
+		stateFile = &filtered
+	}
+	return v.Show.DisplayState(ctx, stateFile, schemas)
+}
+
+func (v filteredShow) DisplayPlan(ctx context.Context, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, config *configs.Config, priorStateFile *statefile.File, schemas *tofu.Schemas) int {
+	if planJSON != nil {
+		var diags tfdiags.Diagnostics
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Filters not supported for cloud plans",
+			"The -filter and -filter-module options can't be used to show a plan created by a cloud backend.",
+		))
+		v.Diagnostics(diags)
+		return 1
+	}
+	if priorStateFile != nil {
+		filtered := *priorStateFile
+		filtered.State = v.filter.FilterState(priorStateFile.State)
+		priorStateFile = &filtered
+	}
+	return v.Show.DisplayPlan(ctx, v.filter.FilterPlan(plan), planJSON, config, priorStateFile, schemas)
+}
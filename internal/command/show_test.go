@@ -10,14 +10,18 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/costestimate"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -506,6 +510,64 @@ func TestShow_plan_json(t *testing.T) {
 	}
 }
 
+func TestShow_plan_jsonCostEstimate(t *testing.T) {
+	// The estimator script assumes a Unix-like environment, as described
+	// in the tests of package costestimate.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("this test only works on Unix-like systems")
+	}
+	program, err := filepath.Abs(filepath.Join("costestimate", "testdata", "test-estimator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	planPath := showFixturePlanFile(t, plans.Create)
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+			CostEstimator: &costestimate.Estimator{
+				Name: "test",
+				Provider: &costestimate.Program{
+					Program: program,
+					Args:    []string{"good"},
+				},
+			},
+		},
+	}
+
+	args := []string{
+		"-plan=" + planPath,
+		"-json",
+		"-no-color",
+	}
+	code := c.Run(args)
+	output := done(t)
+
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	var got struct {
+		CostEstimate *jsonplan.CostEstimate `json:"cost_estimate"`
+	}
+	if err := json.Unmarshal([]byte(output.Stdout()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := &jsonplan.CostEstimate{
+		Currency:             "USD",
+		TotalMonthlyCost:     "12.50",
+		PastTotalMonthlyCost: "10",
+		Resources: []jsonplan.ResourceCost{
+			{Address: "test_instance.foo", MonthlyCost: "2.50"},
+		},
+	}
+	if diff := cmp.Diff(want, got.CostEstimate); diff != "" {
+		t.Errorf("wrong cost estimate\n%s", diff)
+	}
+}
+
 func TestShow_state(t *testing.T) {
 	originalState := testState()
 	root := originalState.RootModule()
@@ -1000,6 +1062,246 @@ func TestShow_planWithNonDefaultStateLineage(t *testing.T) {
 	}
 }
 
+func TestShow_filter(t *testing.T) {
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, addr := range []string{
+			"test_instance.foo",
+			"test_instance.bar",
+			`module.app["blue"].test_instance.baz`,
+			`module.app["green"].test_instance.baz`,
+		} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+				addrs.NoKey,
+			)
+		}
+	})
+	statePath := testStateFile(t, state)
+
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"pattern": {
+			[]string{"-filter=test_instance.ba*"},
+			[]string{"test_instance.bar"},
+		},
+		"several patterns": {
+			[]string{"-filter=test_instance.foo", "-filter=*.baz"},
+			[]string{"test_instance.foo", `module.app["blue"].test_instance.baz`, `module.app["green"].test_instance.baz`},
+		},
+		"module": {
+			[]string{"-filter-module=module.app"},
+			[]string{`module.app["blue"].test_instance.baz`, `module.app["green"].test_instance.baz`},
+		},
+		"module instance": {
+			[]string{`-filter-module=module.app["green"]`},
+			[]string{`module.app["green"].test_instance.baz`},
+		},
+		"module and pattern": {
+			[]string{"-filter-module=module.app", "-filter=*blue*"},
+			[]string{`module.app["blue"].test_instance.baz`},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &ShowCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run(append(test.args, "-no-color", statePath))
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+			}
+
+			var got []string
+			for _, line := range strings.Split(output.Stdout(), "\n") {
+				if addr, ok := strings.CutPrefix(line, "# "); ok {
+					got = append(got, strings.TrimSuffix(addr, ":"))
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong resource instances shown\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShow_filterPlan(t *testing.T) {
+	_, snap := testModuleWithSnapshot(t, "show")
+	plannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.UnknownVal(cty.String),
+		"ami": cty.StringVal("bar"),
+	})
+	priorValRaw, err := plans.NewDynamicValue(cty.NullVal(plannedVal.Type()), plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plannedValRaw, err := plans.NewDynamicValue(plannedVal, plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := testPlan(t)
+	for _, addr := range []string{"test_instance.foo", "test_instance.bar"} {
+		plan.Changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+			Addr: mustResourceInstanceAddr(addr),
+			ProviderAddr: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			ChangeSrc: plans.ChangeSrc{
+				Action: plans.Create,
+				Before: priorValRaw,
+				After:  plannedValRaw,
+			},
+		})
+	}
+	planPath := testPlanFile(t, snap, states.NewState(), plan)
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-plan=" + planPath, "-no-color", "-filter=test_instance.bar"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	if !strings.Contains(got, "test_instance.bar will be created") {
+		t.Errorf("filtered plan doesn't include test_instance.bar\n%s", got)
+	}
+	if strings.Contains(got, "test_instance.foo") {
+		t.Errorf("filtered plan includes test_instance.foo\n%s", got)
+	}
+	if !strings.Contains(got, "Plan: 1 to add, 0 to change, 0 to destroy.") {
+		t.Errorf("filtered plan has the wrong summary\n%s", got)
+	}
+}
+
+func TestAddressGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, addr string
+		want          bool
+	}{
+		{"aws_instance.web", "aws_instance.web", true},
+		{"aws_instance.*", "aws_instance.web", true},
+		{"aws_instance.*", "aws_s3_bucket.logs", false},
+		{"*.web", "module.app.aws_instance.web", true},
+		{"module.app.*", `module.app.aws_instance.web[0]`, true},
+		{`aws_instance.web[?]`, `aws_instance.web[1]`, true},
+		{`aws_instance.web[?]`, `aws_instance.web[10]`, false},
+		{`module.app["*"].*`, `module.app["blue"].aws_instance.web`, true},
+		{"*", "", true},
+		{"", "aws_instance.web", false},
+	}
+	for _, test := range tests {
+		if got := addressGlobMatch(test.pattern, test.addr); got != test.want {
+			t.Errorf("addressGlobMatch(%q, %q) = %t; want %t", test.pattern, test.addr, got, test.want)
+		}
+	}
+}
+
+func TestShow_planMetadata(t *testing.T) {
+	_, snap := testModuleWithSnapshot(t, "show")
+	plan := testPlan(t)
+	plan.Timestamp = time.Date(2020, time.October, 14, 9, 0, 0, 0, time.UTC)
+	plan.ExpiresAt = plan.Timestamp.Add(time.Hour)
+	plan.Backend.Workspace = "default"
+	stateMeta := statemgr.SnapshotMeta{
+		Lineage: "fake-for-plan",
+		Serial:  4,
+	}
+	planPath := testPlanFileMatchState(t, snap, states.NewState(), plan, stateMeta)
+
+	t.Run("human", func(t *testing.T) {
+		view, done := testView(t)
+		c := &ShowCommand{
+			Meta: Meta{
+				View: view,
+			},
+		}
+
+		code := c.Run([]string{"-plan=" + planPath, "-meta", "-no-color"})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+		}
+
+		got := output.Stdout()
+		want := `Created:        2020-10-14T09:00:00Z
+Expires:        2020-10-14T10:00:00Z (expired)
+Mode:           normal
+Backend:        local
+Workspace:      default
+State lineage:  fake-for-plan
+State serial:   4
+Status:         expired; create a new plan to apply these changes
+`
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected output\n%s", diff)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		view, done := testView(t)
+		c := &ShowCommand{
+			Meta: Meta{
+				View: view,
+			},
+		}
+
+		code := c.Run([]string{"-meta", "-json", planPath})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+		}
+
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(output.Stdout()), &got); err != nil {
+			t.Fatalf("invalid JSON output: %s\n%s", err, output.Stdout())
+		}
+		want := map[string]interface{}{
+			"format_version": "1.2",
+			"timestamp":      "2020-10-14T09:00:00Z",
+			"expires_at":     "2020-10-14T10:00:00Z",
+			"expired":        true,
+			"mode":           "normal",
+			"errored":        false,
+			"applyable":      false,
+			"backend": map[string]interface{}{
+				"type":      "local",
+				"workspace": "default",
+			},
+			"prior_state": map[string]interface{}{
+				"lineage": "fake-for-plan",
+				"serial":  float64(4),
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected output\n%s", diff)
+		}
+	})
+}
+
 func TestShow_corruptStatefile(t *testing.T) {
 	td := t.TempDir()
 	inputDir := "testdata/show-corrupt-statefile"
@@ -1688,3 +1990,11 @@ func TestShow_module_noJson(t *testing.T) {
 		t.Errorf("unexpected output\ngot: %s\nwant:\n%s", got, want)
 	}
 }
+
+func mustResourceInstanceAddr(s string) addrs.AbsResourceInstance {
+	addr, diags := addrs.ParseAbsResourceInstanceStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return addr
+}
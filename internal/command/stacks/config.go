@@ -0,0 +1,348 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package stacks implements stack files, which declare multiple root module
+// configurations as components of a larger system, so that "tofu stacks plan"
+// and "tofu stacks apply" can run them in dependency order and pass the
+// outputs of each component to the components that depend on it.
+package stacks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// DefaultFilename is the name of the stack file that the stacks commands
+// read when no other file is given.
+const DefaultFilename = "tofu.stack.hcl"
+
+// Config is the decoded content of a stack file.
+type Config struct {
+	// Inputs is the expression given for the top-level "inputs" argument,
+	// whose attributes are passed to each component that declares an input
+	// variable of the same name. It's nil if the argument isn't set.
+	Inputs hcl.Expression
+
+	// Components are the components declared in the file, by name.
+	Components map[string]*Component
+
+	// BaseDir is the directory containing the stack file, which the
+	// sources of the components are relative to.
+	BaseDir string
+}
+
+// Component is a "component" block in a stack file, which declares one root
+// module configuration of the stack.
+type Component struct {
+	Name string
+
+	// Dir is the absolute path of the directory containing the root module
+	// of the component, given by its "source" argument.
+	Dir string
+
+	// Inputs is the expression given for the "inputs" argument, which gives
+	// values for the input variables of the component's root module, or is
+	// nil if it isn't set. Its attributes override those of the shared inputs.
+	Inputs hcl.Expression
+
+	// BackendConfig is the expression given for the "backend_config"
+	// argument, whose attributes are passed to "tofu init" as partial
+	// backend configuration, or nil if it isn't set.
+	BackendConfig hcl.Expression
+
+	// DependsOn are the names of the components that must be applied before
+	// this one, either because they are given in the "depends_on" argument
+	// or because the inputs refer to their outputs.
+	DependsOn []string
+
+	DeclRange hcl.Range
+}
+
+// LoadConfig reads and decodes the stack file with the given name.
+func LoadConfig(filename string) (*Config, hcl.Diagnostics) {
+	file, diags := hclparse.NewParser().ParseHCLFile(filename)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	baseDir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid stack file path",
+			Detail:   fmt.Sprintf("Cannot determine the directory containing %s: %s.", filename, err),
+		})
+	}
+
+	cfg, moreDiags := decodeConfig(file.Body, baseDir)
+	return cfg, append(diags, moreDiags...)
+}
+
+func decodeConfig(body hcl.Body, baseDir string) (*Config, hcl.Diagnostics) {
+	cfg := &Config{
+		Components: make(map[string]*Component),
+		BaseDir:    baseDir,
+	}
+
+	content, diags := body.Content(configSchema)
+
+	if attr, exists := content.Attributes["inputs"]; exists {
+		cfg.Inputs = attr.Expr
+		for _, traversal := range attr.Expr.Variables() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference in shared inputs",
+				Detail:   "The shared inputs of a stack cannot refer to components or other objects. Set inputs that refer to the outputs of other components in the inputs argument of each component that uses them.",
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+		}
+	}
+
+	for _, block := range content.Blocks {
+		c, moreDiags := decodeComponentBlock(block, baseDir)
+		diags = append(diags, moreDiags...)
+		if c == nil {
+			continue
+		}
+		if existing, exists := cfg.Components[c.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate component",
+				Detail:   fmt.Sprintf("A component named %q was already declared at %s. Component names must be unique within a stack.", c.Name, existing.DeclRange),
+				Subject:  &c.DeclRange,
+			})
+			continue
+		}
+		cfg.Components[c.Name] = c
+	}
+
+	for _, c := range cfg.Components {
+		for _, dep := range c.DependsOn {
+			if _, exists := cfg.Components[dep]; !exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared component",
+					Detail:   fmt.Sprintf("Component %q depends on component %q, which is not declared in this stack.", c.Name, dep),
+					Subject:  &c.DeclRange,
+				})
+			}
+		}
+	}
+
+	return cfg, diags
+}
+
+func decodeComponentBlock(block *hcl.Block, baseDir string) (*Component, hcl.Diagnostics) {
+	c := &Component{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+	var diags hcl.Diagnostics
+
+	if !hclsyntax.ValidIdentifier(c.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid component name",
+			Detail:   "A component name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes.",
+			Subject:  &block.LabelRanges[0],
+		})
+		return nil, diags
+	}
+
+	content, moreDiags := block.Body.Content(componentSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["source"]; exists {
+		var source string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &source)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			dir := source
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(baseDir, dir)
+			}
+			c.Dir = filepath.Clean(dir)
+			if info, err := os.Stat(c.Dir); err != nil || !info.IsDir() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid component source",
+					Detail:   fmt.Sprintf("The source of a component must be a directory containing a root module, but %s is not a directory.", c.Dir),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
+	deps := make(map[string]struct{})
+
+	if attr, exists := content.Attributes["depends_on"]; exists {
+		exprs, listDiags := hcl.ExprList(attr.Expr)
+		diags = append(diags, listDiags...)
+		for _, expr := range exprs {
+			traversal, travDiags := hcl.AbsTraversalForExpr(expr)
+			diags = append(diags, travDiags...)
+			if travDiags.HasErrors() {
+				continue
+			}
+			name, ok := componentRef(traversal)
+			if !ok || len(traversal) != 2 {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid depends_on reference",
+					Detail:   `The depends_on argument of a component must be a list of references to other components, such as component.network.`,
+					Subject:  expr.Range().Ptr(),
+				})
+				continue
+			}
+			deps[name] = struct{}{}
+		}
+	}
+
+	if attr, exists := content.Attributes["inputs"]; exists {
+		c.Inputs = attr.Expr
+		diags = append(diags, addComponentRefs(attr.Expr, deps)...)
+	}
+
+	if attr, exists := content.Attributes["backend_config"]; exists {
+		c.BackendConfig = attr.Expr
+		diags = append(diags, addComponentRefs(attr.Expr, deps)...)
+	}
+
+	if _, exists := deps[c.Name]; exists {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Self-referential component",
+			Detail:   fmt.Sprintf("Component %q cannot depend on itself.", c.Name),
+			Subject:  &c.DeclRange,
+		})
+		delete(deps, c.Name)
+	}
+	for name := range deps {
+		c.DependsOn = append(c.DependsOn, name)
+	}
+	sort.Strings(c.DependsOn)
+
+	return c, diags
+}
+
+// addComponentRefs adds the names of the components whose outputs are
+// referred to by the given expression to deps, and returns errors for any
+// references to other objects.
+func addComponentRefs(expr hcl.Expression, deps map[string]struct{}) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, traversal := range expr.Variables() {
+		name, ok := componentRef(traversal)
+		if !ok || len(traversal) < 3 || !isAttrStep(traversal[2], "outputs") {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference",
+				Detail:   "The inputs of a component can only refer to the outputs of other components, such as component.network.outputs.vpc_id.",
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+			continue
+		}
+		deps[name] = struct{}{}
+	}
+	return diags
+}
+
+// componentRef returns the name of the component that the given traversal
+// refers to, if it starts with "component.NAME".
+func componentRef(traversal hcl.Traversal) (string, bool) {
+	if traversal.RootName() != "component" || len(traversal) < 2 {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+func isAttrStep(step hcl.Traverser, name string) bool {
+	attr, ok := step.(hcl.TraverseAttr)
+	return ok && attr.Name == name
+}
+
+// Order returns the components of the stack in an order where each component
+// comes after all of the components it depends on, or an error if the
+// dependencies form a cycle. Components that don't depend on each other are
+// ordered by name, so that the order is always the same.
+func (c *Config) Order() ([]*Component, hcl.Diagnostics) {
+	names := make([]string, 0, len(c.Components))
+	for name := range c.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(names))
+	ret := make([]*Component, 0, len(names))
+
+	var diags hcl.Diagnostics
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		switch state[name] {
+		case visited:
+			return
+		case visiting:
+			comp := c.Components[name]
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Dependency cycle between components",
+				Detail:   fmt.Sprintf("The components of a stack cannot depend on each other in a cycle: %s -> %s.", strings.Join(path, " -> "), name),
+				Subject:  &comp.DeclRange,
+			})
+			return
+		}
+
+		comp, exists := c.Components[name]
+		if !exists {
+			// Reported when the configuration was decoded.
+			return
+		}
+		state[name] = visiting
+		for _, dep := range comp.DependsOn {
+			visit(dep, append(path, name))
+		}
+		state[name] = visited
+		ret = append(ret, comp)
+	}
+	for _, name := range names {
+		visit(name, nil)
+	}
+
+	return ret, diags
+}
+
+var configSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "inputs"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "component", LabelNames: []string{"name"}},
+	},
+}
+
+var componentSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "source", Required: true},
+		{Name: "inputs"},
+		{Name: "backend_config"},
+		{Name: "depends_on"},
+	},
+}
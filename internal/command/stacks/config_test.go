@@ -0,0 +1,75 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stacks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, diags := LoadConfig(filepath.Join("testdata", "basic", DefaultFilename))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	baseDir, err := filepath.Abs(filepath.Join("testdata", "basic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BaseDir != baseDir {
+		t.Errorf("wrong base directory %q; want %q", cfg.BaseDir, baseDir)
+	}
+	if got, want := cfg.Components["app"].Dir, filepath.Join(baseDir, "app"); got != want {
+		t.Errorf("wrong directory for app %q; want %q", got, want)
+	}
+	if diff := cmp.Diff([]string{"network"}, cfg.Components["app"].DependsOn); diff != "" {
+		t.Errorf("wrong dependencies for app\n%s", diff)
+	}
+	if deps := cfg.Components["network"].DependsOn; len(deps) != 0 {
+		t.Errorf("unexpected dependencies for network: %v", deps)
+	}
+
+	order, diags := cfg.Order()
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	var names []string
+	for _, c := range order {
+		names = append(names, c.Name)
+	}
+	if diff := cmp.Diff([]string{"network", "app"}, names); diff != "" {
+		t.Errorf("wrong order\n%s", diff)
+	}
+}
+
+func TestLoadConfig_undeclaredComponent(t *testing.T) {
+	_, diags := LoadConfig(filepath.Join("testdata", "undeclared", DefaultFilename))
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Error(), `Component "a" depends on component "missing", which is not declared in this stack.`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestConfigOrder_cycle(t *testing.T) {
+	cfg, diags := LoadConfig(filepath.Join("testdata", "cycle", DefaultFilename))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	_, diags = cfg.Order()
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Error(), "cycle: a -> b -> a."; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stacks
+
+import (
+	"context"
+	"io"
+	"os/exec"
+)
+
+// ExecRunner is a Runner that runs each command as a child process of the
+// given tofu executable, using its -chdir option to select the directory of
+// the component.
+type ExecRunner struct {
+	Program string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+var _ Runner = (*ExecRunner)(nil)
+
+func (r *ExecRunner) Run(ctx context.Context, dir string, args []string, stdout io.Writer) error {
+	cmd := exec.CommandContext(ctx, r.Program, append([]string{"-chdir=" + dir}, args...)...)
+	cmd.Stdin = r.Stdin
+	cmd.Stdout = r.Stdout
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.Stderr = r.Stderr
+	return cmd.Run()
+}
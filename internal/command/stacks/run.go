@@ -0,0 +1,459 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stacks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Runner runs a tofu command with the given arguments in the root module
+// directory of a component.
+type Runner interface {
+	// Run runs the command, writing anything it prints to its standard output
+	// into stdout, and returns an error if the command fails.
+	Run(ctx context.Context, dir string, args []string, stdout io.Writer) error
+}
+
+// Orchestrator runs the components of a stack in dependency order.
+type Orchestrator struct {
+	Config *Config
+	Runner Runner
+
+	// AutoApprove is passed to each "tofu apply" as the -auto-approve option.
+	AutoApprove bool
+
+	// Status is called with a message describing each step before it is
+	// run. It can be nil.
+	Status func(msg string)
+}
+
+// Plan creates a plan for each component of the stack. The inputs that
+// refer to the outputs of other components use the outputs that were saved
+// in the state of those components by an earlier apply, and so components
+// whose upstream components have never been applied are skipped with a
+// warning.
+func (o *Orchestrator) Plan(ctx context.Context) tfdiags.Diagnostics {
+	return o.run(ctx, false)
+}
+
+// Apply applies each component of the stack, reading the outputs of each
+// one after it is applied so that they can be passed to the components that
+// depend on it. It stops at the first component that fails.
+func (o *Orchestrator) Apply(ctx context.Context) tfdiags.Diagnostics {
+	return o.run(ctx, true)
+}
+
+func (o *Orchestrator) run(ctx context.Context, apply bool) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	order, orderDiags := o.Config.Order()
+	diags = diags.Append(orderDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	shared, moreDiags := evalInputs(o.Config.Inputs, nil, o.Config.BaseDir)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	outputs := make(map[string]cty.Value, len(order))
+	applied := make(map[string]bool, len(order))
+	skipped := make(map[string]bool)
+	for _, c := range order {
+		if ctx.Err() != nil {
+			return diags.Append(ctx.Err())
+		}
+
+		if skippedDep := firstSkipped(c, skipped); skippedDep != "" {
+			skipped[c.Name] = true
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Component skipped",
+				fmt.Sprintf("Component %q was not planned because it depends on component %q, which was skipped.", c.Name, skippedDep),
+			))
+			continue
+		}
+
+		if !apply {
+			// When planning, the outputs of upstream components are the ones
+			// from their last apply, which we read only when they're needed.
+			for _, dep := range c.DependsOn {
+				if _, exists := applied[dep]; exists {
+					continue
+				}
+				depOutputs, depApplied, err := o.readOutputs(ctx, o.Config.Components[dep])
+				if err != nil {
+					return diags.Append(err)
+				}
+				outputs[dep] = depOutputs
+				applied[dep] = depApplied
+			}
+			if unapplied := firstUnapplied(c, applied); unapplied != "" {
+				skipped[c.Name] = true
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Component skipped",
+					fmt.Sprintf("Component %q was not planned because component %q has not been applied yet. Apply the stack to create the upstream components first.", c.Name, unapplied),
+				))
+				continue
+			}
+		}
+
+		componentDiags := o.runComponent(ctx, c, shared, outputs, apply)
+		diags = diags.Append(componentDiags)
+		if componentDiags.HasErrors() {
+			return diags
+		}
+
+		if apply {
+			componentOutputs, _, err := o.readOutputs(ctx, c)
+			if err != nil {
+				return diags.Append(err)
+			}
+			outputs[c.Name] = componentOutputs
+			applied[c.Name] = true
+		}
+	}
+
+	return diags
+}
+
+func (o *Orchestrator) runComponent(ctx context.Context, c *Component, shared map[string]cty.Value, outputs map[string]cty.Value, apply bool) (diags tfdiags.Diagnostics) {
+	evalCtx := componentEvalContext(outputs)
+	inputs, moreDiags := evalInputs(c.Inputs, evalCtx, o.Config.BaseDir)
+	diags = diags.Append(moreDiags)
+	backendConfig, moreDiags := evalBackendConfig(c.BackendConfig, evalCtx, o.Config.BaseDir)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	vars, moreDiags := componentVariables(c, shared, inputs)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	varFile, err := writeVarFile(vars)
+	if err != nil {
+		return diags.Append(fmt.Errorf("failed to write the inputs of component %q: %w", c.Name, err))
+	}
+	defer func() {
+		// The variables file can contain sensitive values, so failing to
+		// remove it is worth telling the user about.
+		if err := os.Remove(varFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Failed to remove variables file",
+				fmt.Sprintf("The temporary file %s, which holds the inputs of component %q, could not be removed: %s. Remove it yourself, because it may contain sensitive values.", varFile, c.Name, err),
+			))
+		}
+	}()
+
+	initArgs := []string{"init", "-input=false"}
+	keys := make([]string, 0, len(backendConfig))
+	for k := range backendConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		initArgs = append(initArgs, fmt.Sprintf("-backend-config=%s=%s", k, backendConfig[k]))
+	}
+
+	o.status(fmt.Sprintf("Initializing component %q...", c.Name))
+	if err := o.Runner.Run(ctx, c.Dir, initArgs, nil); err != nil {
+		return diags.Append(fmt.Errorf("failed to initialize component %q: %w", c.Name, err))
+	}
+
+	if apply {
+		args := []string{"apply", "-input=false", "-var-file=" + varFile}
+		if o.AutoApprove {
+			args = append(args, "-auto-approve")
+		}
+		o.status(fmt.Sprintf("Applying component %q...", c.Name))
+		if err := o.Runner.Run(ctx, c.Dir, args, nil); err != nil {
+			return diags.Append(fmt.Errorf("failed to apply component %q: %w", c.Name, err))
+		}
+		return diags
+	}
+
+	o.status(fmt.Sprintf("Planning component %q...", c.Name))
+	if err := o.Runner.Run(ctx, c.Dir, []string{"plan", "-input=false", "-var-file=" + varFile}, nil); err != nil {
+		return diags.Append(fmt.Errorf("failed to plan component %q: %w", c.Name, err))
+	}
+	return diags
+}
+
+// readOutputs returns the outputs saved in the state of the given component,
+// as an object value whose sensitive attributes are marked as such, using
+// "tofu state pull". It also returns whether the component has a state at
+// all, which it doesn't until it is first applied.
+func (o *Orchestrator) readOutputs(ctx context.Context, c *Component) (cty.Value, bool, error) {
+	var buf bytes.Buffer
+	if err := o.Runner.Run(ctx, c.Dir, []string{"state", "pull"}, &buf); err != nil {
+		return cty.NilVal, false, fmt.Errorf("failed to read the outputs of component %q: %w", c.Name, err)
+	}
+
+	// "tofu state pull" prints nothing at all when there is no state.
+	file, err := statefile.Read(&buf, encryption.StateEncryptionDisabled())
+	if errors.Is(err, statefile.ErrNoState) {
+		return cty.EmptyObjectVal, false, nil
+	}
+	if err != nil {
+		return cty.NilVal, false, fmt.Errorf("failed to decode the state of component %q: %w", c.Name, err)
+	}
+
+	outputs := file.State.RootModule().OutputValues
+	attrs := make(map[string]cty.Value, len(outputs))
+	for name, output := range outputs {
+		v := output.Value
+		if output.Sensitive {
+			v = v.Mark(marks.Sensitive)
+		}
+		attrs[name] = v
+	}
+	return cty.ObjectVal(attrs), true, nil
+}
+
+func (o *Orchestrator) status(msg string) {
+	if o.Status != nil {
+		o.Status(msg)
+	}
+}
+
+// firstSkipped returns the name of the first component that the given one
+// depends on which was skipped, or an empty string if there is none.
+func firstSkipped(c *Component, skipped map[string]bool) string {
+	for _, dep := range c.DependsOn {
+		if skipped[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// firstUnapplied returns the name of the first component that the given one
+// depends on which has never been applied, or an empty string if there is
+// none.
+func firstUnapplied(c *Component, applied map[string]bool) string {
+	for _, dep := range c.DependsOn {
+		if !applied[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+func componentEvalContext(outputs map[string]cty.Value) *hcl.EvalContext {
+	components := make(map[string]cty.Value, len(outputs))
+	for name, v := range outputs {
+		components[name] = cty.ObjectVal(map[string]cty.Value{
+			"outputs": v,
+		})
+	}
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"component": cty.ObjectVal(components),
+		},
+	}
+}
+
+// evalInputs evaluates an "inputs" argument, which must be an object, and
+// returns its attributes.
+func evalInputs(expr hcl.Expression, evalCtx *hcl.EvalContext, baseDir string) (map[string]cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if expr == nil {
+		return nil, diags
+	}
+
+	v, moreDiags := expr.Value(withFunctions(evalCtx, baseDir))
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	v, _ = v.Unmark()
+
+	if v.IsNull() || !v.IsWhollyKnown() || !(v.Type().IsObjectType() || v.Type().IsMapType()) {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid inputs",
+			Detail:   "The inputs argument must be an object whose attributes are the values of the input variables of the component.",
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+	return v.AsValueMap(), diags
+}
+
+// evalBackendConfig evaluates a "backend_config" argument, which must be an
+// object whose attributes are all strings.
+func evalBackendConfig(expr hcl.Expression, evalCtx *hcl.EvalContext, baseDir string) (map[string]string, tfdiags.Diagnostics) {
+	attrs, diags := evalInputs(expr, evalCtx, baseDir)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if v.IsNull() || !v.Type().Equals(cty.String) {
+			return nil, diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid backend configuration",
+				Detail:   fmt.Sprintf("The backend_config argument of a component must be an object whose attributes are strings, but %q is not a string.", k),
+				Subject:  expr.Range().Ptr(),
+			})
+		}
+		ret[k] = v.AsString()
+	}
+	return ret, diags
+}
+
+func withFunctions(evalCtx *hcl.EvalContext, baseDir string) *hcl.EvalContext {
+	if evalCtx == nil {
+		evalCtx = &hcl.EvalContext{}
+	}
+	scope := &lang.Scope{
+		BaseDir:  baseDir,
+		PureOnly: true,
+	}
+	evalCtx.Functions = scope.Functions()
+	return evalCtx
+}
+
+// componentVariables returns the values to set for the input variables of
+// the given component: the shared inputs for the variables that the
+// component declares, overridden by the component's own inputs.
+//
+// The values are passed to the component in a variables file, which can't
+// carry their sensitivity, so a sensitive value can only be set for an input
+// variable that is itself declared as sensitive.
+func componentVariables(c *Component, shared, inputs map[string]cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := make(map[string]cty.Value, len(shared)+len(inputs))
+	for name, v := range shared {
+		ret[name] = v
+	}
+	for name, v := range inputs {
+		ret[name] = v
+	}
+
+	declared, moreDiags := declaredVariables(c.Dir, ret)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for name := range shared {
+		if _, exists := inputs[name]; !exists && declared[name] == nil {
+			delete(ret, name)
+		}
+	}
+	for name := range inputs {
+		if declared[name] == nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Value for undeclared variable",
+				Detail:   fmt.Sprintf("The inputs of component %q set %q, but the root module of the component has no input variable of that name.", c.Name, name),
+				Subject:  c.Inputs.Range().Ptr(),
+			})
+			delete(ret, name)
+		}
+	}
+	for name, v := range ret {
+		if declared[name].Sensitive || !marks.Contains(v, marks.Sensitive) {
+			continue
+		}
+		diag := &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Sensitive value for non-sensitive variable",
+			Detail:   fmt.Sprintf("The value of %q for component %q is sensitive, but the input variable of that name is not. Declare the variable with sensitive = true so that the component keeps the value redacted.", name, c.Name),
+		}
+		if _, exists := inputs[name]; exists {
+			diag.Subject = c.Inputs.Range().Ptr()
+		}
+		diags = diags.Append(diag)
+	}
+	return ret, diags
+}
+
+// declaredVariables returns the input variables declared in the root module
+// in the given directory. The given values are used for any variables that
+// the module refers to in the expressions it evaluates while loading, such
+// as its module sources.
+func declaredVariables(dir string, values map[string]cty.Value) (map[string]*configs.Variable, hcl.Diagnostics) {
+	call := configs.NewStaticModuleCall(addrs.RootModule, func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+		if val, exists := values[v.Name]; exists {
+			return val, nil
+		}
+		if v.Default != cty.NilVal {
+			return v.Default, nil
+		}
+		return cty.DynamicVal, nil
+	}, dir, "")
+
+	mod, diags := configs.NewParser(nil).LoadConfigDir(dir, call)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return mod.Variables, diags
+}
+
+// writeVarFile writes the given variable values into a new temporary JSON
+// variables file and returns its absolute path.
+func writeVarFile(vars map[string]cty.Value) (string, error) {
+	attrs := make(map[string]cty.Value, len(vars))
+	for name, v := range vars {
+		attrs[name], _ = v.UnmarkDeep()
+	}
+	obj := cty.ObjectVal(attrs)
+	src, err := ctyjson.Marshal(obj, obj.Type())
+	if err != nil {
+		return "", err
+	}
+
+	// The directory is made absolute first so that nothing can fail after
+	// the file is created, except for writing it.
+	dir, err := filepath.Abs(os.TempDir())
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "tofu-stack-*.tfvars.json")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,251 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// fakeRunner is a Runner that records the commands it is asked to run and
+// the variables files they are given, without running anything.
+type fakeRunner struct {
+	// outputs is the JSON object of the outputs saved in the state of each
+	// component, by the base name of its directory. The components that are
+	// not in it have no state.
+	outputs map[string]string
+
+	// fail is the command, such as "app apply", that fails.
+	fail string
+
+	commands []string
+	varFiles map[string]string
+}
+
+func (r *fakeRunner) Run(_ context.Context, dir string, args []string, stdout io.Writer) error {
+	name := filepath.Base(dir)
+	cmd := name + " " + args[0]
+	r.commands = append(r.commands, strings.Join(append([]string{name}, args...), " "))
+	if cmd == r.fail {
+		return fmt.Errorf("%s failed", cmd)
+	}
+
+	for _, arg := range args {
+		if path, ok := strings.CutPrefix(arg, "-var-file="); ok {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if r.varFiles == nil {
+				r.varFiles = make(map[string]string)
+			}
+			r.varFiles[name] = string(src)
+			// The variables file path is different each time, so we record
+			// a placeholder instead.
+			r.commands[len(r.commands)-1] = strings.Replace(r.commands[len(r.commands)-1], path, "VARFILE", 1)
+		}
+	}
+
+	if args[0] == "state" {
+		out, exists := r.outputs[name]
+		if !exists {
+			// "tofu state pull" prints nothing when there is no state.
+			return nil
+		}
+		_, err := fmt.Fprintf(stdout, `{"version":4,"terraform_version":"1.9.0","serial":1,"lineage":"test","outputs":%s,"resources":[]}`, out)
+		return err
+	}
+	return nil
+}
+
+func testOrchestrator(t *testing.T, name string, runner *fakeRunner) *Orchestrator {
+	t.Helper()
+	cfg, diags := LoadConfig(filepath.Join("testdata", name, DefaultFilename))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return &Orchestrator{Config: cfg, Runner: runner, AutoApprove: true}
+}
+
+func TestOrchestratorApply(t *testing.T) {
+	runner := &fakeRunner{
+		outputs: map[string]string{
+			"network": `{"subnet_id":{"sensitive":false,"type":"string","value":"subnet-eu-west-1"}}`,
+		},
+	}
+	diags := testOrchestrator(t, "basic", runner).Apply(t.Context())
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	wantCommands := []string{
+		"network init -input=false -backend-config=bucket=states -backend-config=key=network.tfstate",
+		"network apply -input=false -var-file=VARFILE -auto-approve",
+		"network state pull",
+		"app init -input=false -backend-config=key=app.tfstate",
+		"app apply -input=false -var-file=VARFILE -auto-approve",
+		"app state pull",
+	}
+	if diff := cmp.Diff(wantCommands, runner.commands); diff != "" {
+		t.Errorf("wrong commands\n%s", diff)
+	}
+
+	wantVarFiles := map[string]string{
+		"network": `{"region":"eu-west-1"}`,
+		"app":     `{"region":"eu-west-1","subnet_id":"subnet-eu-west-1","tags":{"name":"APP"}}`,
+	}
+	if diff := cmp.Diff(wantVarFiles, runner.varFiles); diff != "" {
+		t.Errorf("wrong variables files\n%s", diff)
+	}
+}
+
+func TestOrchestratorApply_failure(t *testing.T) {
+	runner := &fakeRunner{fail: "network apply"}
+	diags := testOrchestrator(t, "basic", runner).Apply(t.Context())
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), `failed to apply component "network": network apply failed`; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+
+	for _, cmd := range runner.commands {
+		if strings.HasPrefix(cmd, "app ") {
+			t.Errorf("component app was run after network failed: %s", cmd)
+		}
+	}
+}
+
+func TestOrchestratorPlan(t *testing.T) {
+	t.Run("upstream applied", func(t *testing.T) {
+		runner := &fakeRunner{
+			outputs: map[string]string{
+				"network": `{"subnet_id":{"sensitive":false,"type":"string","value":"subnet-old"}}`,
+			},
+		}
+		diags := testOrchestrator(t, "basic", runner).Plan(t.Context())
+		if len(diags) != 0 {
+			t.Fatal(diags.ErrWithWarnings())
+		}
+
+		wantCommands := []string{
+			"network init -input=false -backend-config=bucket=states -backend-config=key=network.tfstate",
+			"network plan -input=false -var-file=VARFILE",
+			"network state pull",
+			"app init -input=false -backend-config=key=app.tfstate",
+			"app plan -input=false -var-file=VARFILE",
+		}
+		if diff := cmp.Diff(wantCommands, runner.commands); diff != "" {
+			t.Errorf("wrong commands\n%s", diff)
+		}
+		if got, want := runner.varFiles["app"], `{"region":"eu-west-1","subnet_id":"subnet-old","tags":{"name":"APP"}}`; got != want {
+			t.Errorf("wrong variables file for app\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("upstream not applied", func(t *testing.T) {
+		runner := &fakeRunner{}
+		diags := testOrchestrator(t, "basic", runner).Plan(t.Context())
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		if len(diags) != 1 || !strings.Contains(diags[0].Description().Detail, `Component "app" was not planned because component "network" has not been applied yet.`) {
+			t.Errorf("wrong diagnostics: %s", diags.ErrWithWarnings())
+		}
+
+		for _, cmd := range runner.commands {
+			if strings.HasPrefix(cmd, "app ") {
+				t.Errorf("component app was run although network was not applied: %s", cmd)
+			}
+		}
+	})
+	t.Run("upstream applied without outputs", func(t *testing.T) {
+		runner := &fakeRunner{
+			outputs: map[string]string{
+				"base": `{}`,
+			},
+		}
+		diags := testOrchestrator(t, "depends", runner).Plan(t.Context())
+		if len(diags) != 0 {
+			t.Fatal(diags.ErrWithWarnings())
+		}
+
+		wantCommands := []string{
+			"base init -input=false",
+			"base plan -input=false -var-file=VARFILE",
+			"base state pull",
+			"app init -input=false",
+			"app plan -input=false -var-file=VARFILE",
+		}
+		if diff := cmp.Diff(wantCommands, runner.commands); diff != "" {
+			t.Errorf("wrong commands\n%s", diff)
+		}
+	})
+}
+
+func TestOrchestratorPlan_sensitive(t *testing.T) {
+	outputs := map[string]string{
+		"db": `{"password":{"sensitive":true,"type":"string","value":"hunter2"}}`,
+	}
+
+	t.Run("sensitive variable", func(t *testing.T) {
+		// The variable of the app component is declared in YAML.
+		runner := &fakeRunner{outputs: outputs}
+		diags := testOrchestrator(t, "sensitive", runner).Plan(t.Context())
+		if len(diags) != 0 {
+			t.Fatal(diags.ErrWithWarnings())
+		}
+		if got, want := runner.varFiles["app"], `{"password":"hunter2"}`; got != want {
+			t.Errorf("wrong variables file for app\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("non-sensitive variable", func(t *testing.T) {
+		runner := &fakeRunner{outputs: outputs}
+		diags := testOrchestrator(t, "notsensitive", runner).Plan(t.Context())
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := diags.Err().Error(), `Sensitive value for non-sensitive variable`; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+		}
+
+		for _, cmd := range runner.commands {
+			if strings.HasPrefix(cmd, "app ") {
+				t.Errorf("component app was run with a sensitive value: %s", cmd)
+			}
+		}
+	})
+}
+
+func TestWriteVarFile(t *testing.T) {
+	path, err := writeVarFile(map[string]cty.Value{
+		"password": cty.StringVal("hunter2").Mark(marks.Sensitive),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+
+	if !filepath.IsAbs(path) {
+		t.Errorf("path %s is not absolute", path)
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(src), `{"password":"hunter2"}`; got != want {
+		t.Errorf("wrong content\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,11 @@
+variable "region" {
+  type = string
+}
+
+variable "subnet_id" {
+  type = string
+}
+
+variable "tags" {
+  type = map(string)
+}
@@ -0,0 +1,7 @@
+variable "region" {
+  type = string
+}
+
+output "subnet_id" {
+  value = "subnet-${var.region}"
+}
@@ -0,0 +1,26 @@
+inputs = {
+  region = "eu-west-1"
+  unused = "ignored"
+}
+
+component "app" {
+  source = "./app"
+
+  inputs = {
+    subnet_id = component.network.outputs.subnet_id
+    tags      = { name = upper("app") }
+  }
+
+  backend_config = {
+    key = "app.tfstate"
+  }
+}
+
+component "network" {
+  source = "./network"
+
+  backend_config = {
+    key    = "network.tfstate"
+    bucket = "states"
+  }
+}
@@ -0,0 +1,12 @@
+component "a" {
+  source = "./a"
+
+  inputs = {
+    value = component.b.outputs.value
+  }
+}
+
+component "b" {
+  source     = "./b"
+  depends_on = [component.a]
+}
@@ -0,0 +1,8 @@
+component "app" {
+  source     = "./app"
+  depends_on = [component.base]
+}
+
+component "base" {
+  source = "./base"
+}
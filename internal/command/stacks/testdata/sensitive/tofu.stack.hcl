@@ -0,0 +1,11 @@
+component "app" {
+  source = "./app"
+
+  inputs = {
+    password = component.db.outputs.password
+  }
+}
+
+component "db" {
+  source = "./db"
+}
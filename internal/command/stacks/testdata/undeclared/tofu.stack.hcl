@@ -0,0 +1,4 @@
+component "a" {
+  source     = "./a"
+  depends_on = [component.missing]
+}
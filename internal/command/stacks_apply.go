@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/command/stacks"
+)
+
+// StacksApplyCommand is a Command implementation that applies each component
+// of a stack, in dependency order.
+type StacksApplyCommand struct {
+	Meta
+
+	// Runner runs the tofu commands for each component. If it is nil, they
+	// are run by executing the current program.
+	Runner stacks.Runner
+}
+
+func (c *StacksApplyCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+
+	var filename string
+	var autoApprove bool
+	cmdFlags := c.Meta.defaultFlagSet("stacks apply")
+	cmdFlags.StringVar(&filename, "file", stacks.DefaultFilename, "file")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "auto-approve")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The stacks apply command expects no positional arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	orchestrator, diags := c.stackOrchestrator(filename, c.Runner)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	orchestrator.AutoApprove = autoApprove
+
+	diags = diags.Append(orchestrator.Apply(ctx))
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func (c *StacksApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *StacksApplyCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-auto-approve": complete.PredictNothing,
+		"-file":         complete.PredictFiles("*.hcl"),
+	}
+}
+
+func (c *StacksApplyCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] stacks apply [options]
+
+  Apply each component of a stack, in dependency order.
+
+  After each component is applied, its outputs are read from its state and
+  passed to the components that depend on it. The apply stops at the first
+  component that fails, leaving the components after it unchanged.
+
+Options:
+
+  -auto-approve      Skip interactive approval of the plan of each
+                     component before applying it.
+
+  -file=path         The stack file to read. Defaults to tofu.stack.hcl in
+                     the working directory.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StacksApplyCommand) Synopsis() string {
+	return "Apply each component of a stack"
+}
@@ -0,0 +1,88 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/stacks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// StacksCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type StacksCommand struct {
+	Meta
+}
+
+func (c *StacksCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *StacksCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] stacks <subcommand> [options]
+
+  This command has subcommands for working with a stack, which is a set of
+  root module configurations that are planned and applied together.
+
+  The components of a stack are declared in a stack file, named
+  tofu.stack.hcl by default. Each component is a root module directory with
+  its own state, and can use the outputs of other components as the values
+  of its input variables. The subcommands run the components in dependency
+  order, passing the outputs of each component to those that depend on it.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StacksCommand) Synopsis() string {
+	return "Plan and apply multiple root modules together"
+}
+
+// stackOrchestrator loads the given stack file and returns an orchestrator
+// for it. The components are run using runner, or by running the current
+// executable if runner is nil.
+func (m *Meta) stackOrchestrator(filename string, runner stacks.Runner) (*stacks.Orchestrator, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	cfg, hclDiags := stacks.LoadConfig(filename)
+	diags = diags.Append(hclDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if runner == nil {
+		program, err := os.Executable()
+		if err != nil {
+			return nil, diags.Append(fmt.Errorf("failed to find the tofu executable to run the components of the stack: %w", err))
+		}
+		r := &stacks.ExecRunner{
+			Program: program,
+			Stdin:   os.Stdin,
+			Stdout:  os.Stdout,
+			Stderr:  os.Stderr,
+		}
+		if m.Streams != nil {
+			r.Stdin = m.Streams.Stdin.File
+			r.Stdout = m.Streams.Stdout.File
+			r.Stderr = m.Streams.Stderr.File
+		}
+		runner = r
+	}
+
+	return &stacks.Orchestrator{
+		Config: cfg,
+		Runner: runner,
+		Status: func(msg string) {
+			m.Ui.Output(m.Colorize().Color("[bold]" + msg))
+		},
+	}, diags
+}
@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/command/stacks"
+)
+
+// StacksPlanCommand is a Command implementation that creates a plan for each
+// component of a stack, in dependency order.
+type StacksPlanCommand struct {
+	Meta
+
+	// Runner runs the tofu commands for each component. If it is nil, they
+	// are run by executing the current program.
+	Runner stacks.Runner
+}
+
+func (c *StacksPlanCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+
+	var filename string
+	cmdFlags := c.Meta.defaultFlagSet("stacks plan")
+	cmdFlags.StringVar(&filename, "file", stacks.DefaultFilename, "file")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The stacks plan command expects no positional arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	orchestrator, diags := c.stackOrchestrator(filename, c.Runner)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	diags = diags.Append(orchestrator.Plan(ctx))
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func (c *StacksPlanCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *StacksPlanCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-file": complete.PredictFiles("*.hcl"),
+	}
+}
+
+func (c *StacksPlanCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] stacks plan [options]
+
+  Create a plan for each component of a stack, in dependency order.
+
+  Inputs that refer to the outputs of other components use the outputs
+  saved in the state of those components by the last apply. Components
+  whose upstream components have never been applied are skipped with a
+  warning.
+
+Options:
+
+  -file=path         The stack file to read. Defaults to tofu.stack.hcl in
+                     the working directory.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StacksPlanCommand) Synopsis() string {
+	return "Plan each component of a stack"
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -95,6 +96,17 @@ func (c *StateMeta) State(ctx context.Context, enc encryption.Encryption) (state
 	return realState, nil
 }
 
+// stateWorkspace returns the workspace whose state State returns, or an empty
+// string if State returns the state file given with the -state option.
+func (c *StateMeta) stateWorkspace(ctx context.Context) string {
+	if c.statePath != "" {
+		return ""
+	}
+	// State has already reported any error selecting the workspace.
+	workspace, _ := c.Workspace(ctx)
+	return workspace
+}
+
 func (c *StateMeta) lookupResourceInstanceAddr(state *states.State, allowMissing bool, addrStr string) ([]addrs.AbsResourceInstance, tfdiags.Diagnostics) {
 	target, diags := addrs.ParseTargetStr(addrStr)
 	if diags.HasErrors() {
@@ -129,7 +141,7 @@ func (c *StateMeta) lookupResourceInstanceAddr(state *states.State, allowMissing
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Unknown module",
-				fmt.Sprintf(`The current state contains no module at %s. If you've just added this module to the configuration, you must run "tofu apply" first to create the module's entry in the state.`, addr),
+				fmt.Sprintf(`The current state contains no module at %s.%s If you've just added this module to the configuration, you must run "tofu apply" first to create the module's entry in the state.`, addr, stateAddressSuggestion(state, addr.String())),
 			))
 		}
 
@@ -141,7 +153,7 @@ func (c *StateMeta) lookupResourceInstanceAddr(state *states.State, allowMissing
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
 					"Unknown resource",
-					fmt.Sprintf(`The current state contains no resource %s. If you've just added this resource to the configuration, you must run "tofu apply" first to create the resource's entry in the state.`, addr),
+					fmt.Sprintf(`The current state contains no resource %s.%s If you've just added this resource to the configuration, you must run "tofu apply" first to create the resource's entry in the state.`, addr, stateAddressSuggestion(state, addr.String())),
 				))
 			}
 			break
@@ -154,7 +166,7 @@ func (c *StateMeta) lookupResourceInstanceAddr(state *states.State, allowMissing
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
 					"Unknown resource instance",
-					fmt.Sprintf(`The current state contains no resource instance %s. If you've just added its resource to the configuration or have changed the count or for_each arguments, you must run "tofu apply" first to update the resource's entry in the state.`, addr),
+					fmt.Sprintf(`The current state contains no resource instance %s.%s If you've just added its resource to the configuration or have changed the count or for_each arguments, you must run "tofu apply" first to update the resource's entry in the state.`, addr, stateAddressSuggestion(state, addr.String())),
 				))
 			}
 			break
@@ -225,3 +237,35 @@ func (c *StateMeta) lookupAllResources(state *states.State) ([]*states.Resource,
 	}
 	return ret, diags
 }
+
+// stateAddressSuggestion returns a sentence suggesting the address of a
+// module, resource, or resource instance in the given state that is similar
+// to the given address, or an empty string if there is no such address.
+//
+// The result starts with a space so that it can be appended directly to the
+// sentence reporting that the given address wasn't found.
+func stateAddressSuggestion(state *states.State, given string) string {
+	if state == nil {
+		return ""
+	}
+	var suggestions []string
+	for _, ms := range state.Modules {
+		if !ms.Addr.IsRoot() {
+			suggestions = append(suggestions, ms.Addr.String())
+		}
+		for _, rs := range ms.Resources {
+			suggestions = append(suggestions, rs.Addr.String())
+			for key := range rs.Instances {
+				if key != addrs.NoKey {
+					suggestions = append(suggestions, rs.Addr.Instance(key).String())
+				}
+			}
+		}
+	}
+	sort.Strings(suggestions)
+	suggestion := didyoumean.NameSuggestion(given, suggestions)
+	if suggestion == "" {
+		return ""
+	}
+	return fmt.Sprintf(" Did you mean %s?", suggestion)
+}
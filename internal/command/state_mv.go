@@ -11,13 +11,15 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -109,7 +111,7 @@ func (c *StateMvCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), c.stateWorkspace(ctx))
 		if diags := stateLocker.Lock(stateFromMgr, "state-mv"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -147,7 +149,7 @@ func (c *StateMvCommand) Run(args []string) int {
 		}
 
 		if c.stateLock {
-			stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+			stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), c.stateWorkspace(ctx))
 			if diags := stateLocker.Lock(stateToMgr, "state-mv"); diags.HasErrors() {
 				c.showDiagnostics(diags)
 				return 1
@@ -170,6 +172,31 @@ func (c *StateMvCommand) Run(args []string) int {
 		}
 	}
 
+	prefix := "Move"
+	if dryRun {
+		prefix = "Would move"
+	}
+
+	// A source address with wildcards moves each resource instance that
+	// matches it to the address made by substituting the same text into the
+	// wildcards of the destination address.
+	if isStateMvPattern(args[0]) {
+		moves, diags := c.patternMoves(stateFrom, stateTo, args[0], args[1])
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		ssFrom := stateFrom.SyncWrapper()
+		for _, move := range moves {
+			c.Ui.Output(fmt.Sprintf("%s %q to %q", prefix, move.from.String(), move.to.String()))
+			if !dryRun {
+				moveResourceInstanceState(ssFrom, stateTo, move.from, move.to, ssFrom.ResourceInstance(move.from))
+			}
+			forgetMovedDependencies(stateTo, move.from)
+		}
+		return c.persistMove(ctx, enc, stateFromMgr, stateToMgr, stateFrom, stateTo, len(moves), dryRun, diags)
+	}
+
 	var diags tfdiags.Diagnostics
 	sourceAddr, moreDiags := c.lookupSingleStateObjectAddr(stateFrom, args[0])
 	diags = diags.Append(moreDiags)
@@ -180,11 +207,6 @@ func (c *StateMvCommand) Run(args []string) int {
 		return 1
 	}
 
-	prefix := "Move"
-	if dryRun {
-		prefix = "Would move"
-	}
-
 	const msgInvalidSource = "Invalid source address"
 	const msgInvalidTarget = "Invalid target address"
 
@@ -340,28 +362,7 @@ func (c *StateMvCommand) Run(args []string) int {
 			moved++
 			c.Ui.Output(fmt.Sprintf("%s %q to %q", prefix, addrFrom.String(), args[1]))
 			if !dryRun {
-				fromResourceAddr := addrFrom.ContainingResource()
-				fromResource := ssFrom.Resource(fromResourceAddr)
-				fromProviderAddr := fromResource.ProviderConfig
-				ssFrom.ForgetResourceInstanceAll(addrFrom)
-				ssFrom.RemoveResourceIfEmpty(fromResourceAddr)
-
-				rs := stateTo.Resource(addrTo.ContainingResource())
-				if rs == nil {
-					// If we're moving to an address without an index then that
-					// suggests the user's intent is to establish both the
-					// resource and the instance at the same time (since the
-					// address covers both). If there's an index in the
-					// target then allow creating the new instance here.
-					resourceAddr := addrTo.ContainingResource()
-					stateTo.SyncWrapper().SetResourceProvider(
-						resourceAddr,
-						fromProviderAddr, // in this case, we bring the provider along as if we were moving the whole resource
-					)
-					rs = stateTo.Resource(resourceAddr)
-				}
-
-				rs.Instances[addrTo.Resource.Key] = is
+				moveResourceInstanceState(ssFrom, stateTo, addrFrom, addrTo, is)
 			}
 		default:
 			diags = diags.Append(tfdiags.Sourceless(
@@ -371,29 +372,16 @@ func (c *StateMvCommand) Run(args []string) int {
 			))
 		}
 
-		// Look for any dependencies that may be effected and
-		// remove them to ensure they are recreated in full.
-		for _, mod := range stateTo.Modules {
-			for _, res := range mod.Resources {
-				for _, ins := range res.Instances {
-					if ins.Current == nil {
-						continue
-					}
-
-					for _, dep := range ins.Current.Dependencies {
-						// check both directions here, since we may be moving
-						// an instance which is in a resource, or a module
-						// which can contain a resource.
-						if dep.TargetContains(rawAddrFrom) || rawAddrFrom.TargetContains(dep) {
-							ins.Current.Dependencies = nil
-							break
-						}
-					}
-				}
-			}
-		}
+		forgetMovedDependencies(stateTo, rawAddrFrom)
 	}
 
+	return c.persistMove(ctx, enc, stateFromMgr, stateToMgr, stateFrom, stateTo, moved, dryRun, diags)
+}
+
+// persistMove writes the source and destination states after the given
+// number of objects have been moved between them, or just reports that
+// nothing was moved in dry-run mode.
+func (c *StateMvCommand) persistMove(ctx context.Context, enc encryption.Encryption, stateFromMgr, stateToMgr statemgr.Full, stateFrom, stateTo *states.State, moved int, dryRun bool, diags tfdiags.Diagnostics) int {
 	if dryRun {
 		if moved == 0 {
 			c.Ui.Output("Would have moved nothing.")
@@ -448,6 +436,139 @@ func (c *StateMvCommand) Run(args []string) int {
 	return 0
 }
 
+// stateMvPatternMove is one resource instance move requested by a source
+// address with wildcards.
+type stateMvPatternMove struct {
+	from, to addrs.AbsResourceInstance
+}
+
+// isStateMvPattern returns true if the given source address has wildcards.
+func isStateMvPattern(addr string) bool {
+	return strings.Contains(addr, "*")
+}
+
+// patternMoves returns the resource instance moves requested by the given
+// source address pattern and destination address template, in address order.
+// Each "*" in the pattern matches any sequence of characters, and each "*" in
+// the template is replaced by the text matched by the corresponding "*" of the
+// pattern. All of the moves are checked before any are made, so that a
+// mistake in the template doesn't leave the state half-refactored.
+func (c *StateMvCommand) patternMoves(stateFrom, stateTo *states.State, pattern, template string) ([]stateMvPatternMove, tfdiags.Diagnostics) {
+	const msgInvalidSource = "Invalid source address"
+	const msgInvalidTarget = "Invalid target address"
+
+	var diags tfdiags.Diagnostics
+	if strings.Count(template, "*") > strings.Count(pattern, "*") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			msgInvalidTarget,
+			fmt.Sprintf("The target address %s has more wildcards than the source address %s, so OpenTofu can't tell what to substitute for each of them.", template, pattern),
+		))
+		return nil, diags
+	}
+
+	instances, moreDiags := c.lookupAllResourceInstanceAddrs(stateFrom)
+	diags = diags.Append(moreDiags)
+
+	var moves []stateMvPatternMove
+	sources := make(map[string]addrs.AbsResourceInstance)
+	for _, addrFrom := range instances {
+		captures, ok := addressGlobCaptures(pattern, addrFrom.String())
+		if !ok {
+			continue
+		}
+		var dest strings.Builder
+		for i, part := range strings.Split(template, "*") {
+			if i > 0 {
+				dest.WriteString(captures[i-1])
+			}
+			dest.WriteString(part)
+		}
+
+		addrTo, parseDiags := addrs.ParseAbsResourceInstanceStr(dest.String())
+		if parseDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidTarget,
+				fmt.Sprintf("Cannot move %s to %s: %s", addrFrom, dest.String(), parseDiags.Err()),
+			))
+			continue
+		}
+		diags = diags.Append(c.validateResourceMove(addrFrom.ContainingResource(), addrTo.ContainingResource()))
+		if prev, exists := sources[addrTo.String()]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidTarget,
+				fmt.Sprintf("Cannot move both %s and %s to %s.", prev, addrFrom, addrTo),
+			))
+			continue
+		}
+		sources[addrTo.String()] = addrFrom
+		if stateTo.ResourceInstance(addrTo) != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidTarget,
+				fmt.Sprintf("Cannot move %s to %s: there is already a resource instance at that address in the current state.", addrFrom, addrTo),
+			))
+		}
+		moves = append(moves, stateMvPatternMove{from: addrFrom, to: addrTo})
+	}
+
+	if len(moves) == 0 && !diags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			msgInvalidSource,
+			fmt.Sprintf("Cannot move %s: does not match anything in the current state.", pattern),
+		))
+	}
+	return moves, diags
+}
+
+// addressGlobCaptures reports whether the address s matches pattern, where
+// "*" matches any sequence of characters, and if so returns the text matched
+// by each "*". Each "*" matches as little as possible, so that in a pattern
+// like "module.*.aws_instance.*" the first matches only a module name.
+func addressGlobCaptures(pattern, s string) ([]string, bool) {
+	before, after, found := strings.Cut(pattern, "*")
+	if !found {
+		return nil, pattern == s
+	}
+	rest, ok := strings.CutPrefix(s, before)
+	if !ok {
+		return nil, false
+	}
+	for n := 0; n <= len(rest); n++ {
+		if captures, ok := addressGlobCaptures(after, rest[n:]); ok {
+			return append([]string{rest[:n]}, captures...), true
+		}
+	}
+	return nil, false
+}
+
+// forgetMovedDependencies looks for any dependencies that may be affected by
+// moving addrFrom and removes them to ensure they are recreated in full.
+func forgetMovedDependencies(state *states.State, addrFrom addrs.Targetable) {
+	for _, mod := range state.Modules {
+		for _, res := range mod.Resources {
+			for _, ins := range res.Instances {
+				if ins.Current == nil {
+					continue
+				}
+
+				for _, dep := range ins.Current.Dependencies {
+					// check both directions here, since we may be moving
+					// an instance which is in a resource, or a module
+					// which can contain a resource.
+					if dep.TargetContains(addrFrom) || addrFrom.TargetContains(dep) {
+						ins.Current.Dependencies = nil
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
 // sourceObjectAddrs takes a single source object address and expands it to
 // potentially multiple objects that need to be handled within it.
 //
@@ -491,6 +612,33 @@ func (c *StateMvCommand) sourceObjectAddrs(state *states.State, matched addrs.Ta
 	return ret
 }
 
+// moveResourceInstanceState moves the given resource instance object from
+// addrFrom in the source state to addrTo in the destination state.
+func moveResourceInstanceState(ssFrom *states.SyncState, stateTo *states.State, addrFrom, addrTo addrs.AbsResourceInstance, is *states.ResourceInstance) {
+	fromResourceAddr := addrFrom.ContainingResource()
+	fromResource := ssFrom.Resource(fromResourceAddr)
+	fromProviderAddr := fromResource.ProviderConfig
+	ssFrom.ForgetResourceInstanceAll(addrFrom)
+	ssFrom.RemoveResourceIfEmpty(fromResourceAddr)
+
+	rs := stateTo.Resource(addrTo.ContainingResource())
+	if rs == nil {
+		// If we're moving to an address without an index then that
+		// suggests the user's intent is to establish both the
+		// resource and the instance at the same time (since the
+		// address covers both). If there's an index in the
+		// target then allow creating the new instance here.
+		resourceAddr := addrTo.ContainingResource()
+		stateTo.SyncWrapper().SetResourceProvider(
+			resourceAddr,
+			fromProviderAddr, // in this case, we bring the provider along as if we were moving the whole resource
+		)
+		rs = stateTo.Resource(resourceAddr)
+	}
+
+	rs.Instances[addrTo.Resource.Key] = is
+}
+
 func (c *StateMvCommand) validateResourceMove(addrFrom, addrTo addrs.AbsResource) tfdiags.Diagnostics {
 	const msgInvalidRequest = "Invalid state move request"
 
@@ -528,6 +676,25 @@ func (c *StateMvCommand) validateResourceMove(addrFrom, addrTo addrs.AbsResource
 	return diags
 }
 
+func (c *StateMvCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictResourceAddress(c.CommandContext()),
+		complete.PredictAnything,
+	}
+}
+
+func (c *StateMvCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-backup":       complete.PredictFiles("*.tfstate"),
+		"-backup-out":   complete.PredictFiles("*.tfstate"),
+		"-dry-run":      complete.PredictNothing,
+		"-lock":         completePredictBoolean,
+		"-lock-timeout": complete.PredictAnything,
+		"-state":        complete.PredictFiles("*.tfstate"),
+		"-state-out":    complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *StateMvCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state (move|mv) [options] SOURCE DESTINATION
@@ -548,6 +715,14 @@ Usage: tofu [global options] state (move|mv) [options] SOURCE DESTINATION
  If you're moving an item to a different state file, a backup will be created
  for each state file.
 
+ If SOURCE contains "*" wildcards, each resource instance whose address
+ matches it is moved to the address made by replacing each "*" in
+ DESTINATION with the text matched by the corresponding "*" in SOURCE. For
+ example, 'module.app[*].aws_instance.*' and 'module.web[*].aws_instance.*'
+ move every instance of every aws_instance resource in each instance of
+ module.app to the same instance of module.web. Use -dry-run to preview the
+ full mapping first.
+
 Options:
 
   -dry-run                If set, prints out what would've been moved but doesn't
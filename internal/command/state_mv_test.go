@@ -2164,3 +2164,204 @@ module.foo:
     bar = value
     foo = value
 `
+
+func testStateMvPatternState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		provider := addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		}
+		for _, addr := range []string{
+			"module.app[0].test_instance.foo",
+			"module.app[1].test_instance.foo",
+			"module.app[1].test_instance.bar",
+			"test_instance.baz",
+		} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"` + addr + `"}`),
+					Status:    states.ObjectReady,
+				},
+				provider,
+				addrs.NoKey,
+			)
+		}
+	})
+}
+
+func TestStateMv_pattern(t *testing.T) {
+	statePath := testStateFile(t, testStateMvPatternState())
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateMvCommand{
+		StateMeta{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"module.app[*].test_instance.*",
+		"module.web[*].test_instance.*",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := `Move "module.app[0].test_instance.foo" to "module.web[0].test_instance.foo"
+Move "module.app[1].test_instance.bar" to "module.web[1].test_instance.bar"
+Move "module.app[1].test_instance.foo" to "module.web[1].test_instance.foo"
+Successfully moved 3 object(s).
+`
+	if diff := cmp.Diff(want, ui.OutputWriter.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+	testStateOutput(t, statePath, testStateMvPatternStr)
+}
+
+func TestStateMv_patternDryRun(t *testing.T) {
+	state := testStateMvPatternState()
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateMvCommand{
+		StateMeta{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		},
+	}
+
+	args := []string{
+		"-dry-run",
+		"-state", statePath,
+		"module.app[1].test_instance.*",
+		"test_instance.*_1",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := `Would move "module.app[1].test_instance.bar" to "test_instance.bar_1"
+Would move "module.app[1].test_instance.foo" to "test_instance.foo_1"
+`
+	if diff := cmp.Diff(want, ui.OutputWriter.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+	testStateOutput(t, statePath, state.String())
+}
+
+func TestStateMv_patternInvalid(t *testing.T) {
+	tests := map[string]struct {
+		source, dest string
+		want         string
+	}{
+		"same destination": {
+			"module.app[*].test_instance.foo",
+			"test_instance.foo",
+			"Cannot move both module.app[0].test_instance.foo and module.app[1].test_instance.foo to test_instance.foo.",
+		},
+		"existing destination": {
+			"module.app[0].test_instance.*",
+			"test_instance.baz",
+			"there is already a resource instance at that address",
+		},
+		"too many wildcards": {
+			"module.app[0].test_instance.*",
+			"module.*.test_instance.*",
+			"has more wildcards than the source address",
+		},
+		"no match": {
+			"module.db[*].test_instance.*",
+			"module.web[*].test_instance.*",
+			"does not match anything in the current state",
+		},
+		"invalid destination": {
+			"module.app[*].test_instance.foo",
+			"module.web[*].test_instance",
+			"Cannot move module.app[0].test_instance.foo to module.web[0].test_instance",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := testStateMvPatternState()
+			statePath := testStateFile(t, state)
+
+			p := testProvider()
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			c := &StateMvCommand{
+				StateMeta{
+					Meta: Meta{
+						testingOverrides: metaOverridesForProvider(p),
+						Ui:               ui,
+						View:             view,
+					},
+				},
+			}
+
+			args := []string{"-state", statePath, test.source, test.dest}
+			if code := c.Run(args); code != 1 {
+				t.Fatalf("wrong exit status %d; want 1\n%s", code, ui.OutputWriter.String())
+			}
+			if got := strings.Join(strings.Fields(ui.ErrorWriter.String()), " "); !strings.Contains(got, test.want) {
+				t.Errorf("wrong error\nwant substring: %s\ngot: %s", test.want, got)
+			}
+			testStateOutput(t, statePath, state.String())
+		})
+	}
+}
+
+func TestAddressGlobCaptures(t *testing.T) {
+	tests := []struct {
+		pattern, addr string
+		want          []string
+		match         bool
+	}{
+		{"test_instance.foo", "test_instance.foo", nil, true},
+		{"test_instance.*", "test_instance.foo[0]", []string{"foo[0]"}, true},
+		{"module.*.test_instance.*", "module.a.test_instance.foo", []string{"a", "foo"}, true},
+		{"module.app[*].*", `module.app["x"].test_instance.foo`, []string{`"x"`, "test_instance.foo"}, true},
+		{"*.foo", "test_instance.foo", []string{"test_instance"}, true},
+		{"test_instance.*", "data.test_instance.foo", nil, false},
+	}
+	for _, test := range tests {
+		got, match := addressGlobCaptures(test.pattern, test.addr)
+		if match != test.match {
+			t.Errorf("addressGlobCaptures(%q, %q) matched = %t, want %t", test.pattern, test.addr, match, test.match)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("wrong captures for %q and %q\n%s", test.pattern, test.addr, diff)
+		}
+	}
+}
+
+const testStateMvPatternStr = `
+test_instance.baz:
+  ID = test_instance.baz
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+
+module.web[0]:
+  test_instance.foo:
+    ID = module.app[0].test_instance.foo
+    provider = provider["registry.opentofu.org/hashicorp/test"]
+module.web[1]:
+  test_instance.bar:
+    ID = module.app[1].test_instance.bar
+    provider = provider["registry.opentofu.org/hashicorp/test"]
+  test_instance.foo:
+    ID = module.app[1].test_instance.foo
+    provider = provider["registry.opentofu.org/hashicorp/test"]
+`
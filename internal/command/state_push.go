@@ -15,7 +15,6 @@ import (
 	"github.com/mitchellh/cli"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/statefile"
@@ -117,7 +116,7 @@ func (c *StatePushCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), workspace)
 		if diags := stateLocker.Lock(stateMgr, "state-push"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -11,10 +11,10 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -66,7 +66,7 @@ func (c *StateRmCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), c.stateWorkspace(ctx))
 		if diags := stateLocker.Lock(stateMgr, "state-rm"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -93,10 +93,14 @@ func (c *StateRmCommand) Run(args []string) int {
 	// also clean up any modules and resources left empty by actions it takes.
 	var addrs []addrs.AbsResourceInstance
 	var diags tfdiags.Diagnostics
+	var suggestions string
 	for _, addrStr := range args {
 		moreAddrs, moreDiags := c.lookupResourceInstanceAddr(state, true, addrStr)
 		addrs = append(addrs, moreAddrs...)
 		diags = diags.Append(moreDiags)
+		if len(moreAddrs) == 0 {
+			suggestions += stateAddressSuggestion(state, addrStr)
+		}
 	}
 	if diags.HasErrors() {
 		c.showDiagnostics(diags)
@@ -158,7 +162,7 @@ func (c *StateRmCommand) Run(args []string) int {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Invalid target address",
-			"No matching objects found. To view the available instances, use \"tofu state list\". Please modify the address to reference a specific instance.",
+			"No matching objects found."+suggestions+" To view the available instances, use \"tofu state list\". Please modify the address to reference a specific instance.",
 		))
 		c.showDiagnostics(diags)
 		return 1
@@ -168,6 +172,20 @@ func (c *StateRmCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *StateRmCommand) AutocompleteArgs() complete.Predictor {
+	return c.completePredictResourceAddress(c.CommandContext())
+}
+
+func (c *StateRmCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-backup":       complete.PredictFiles("*.tfstate"),
+		"-dry-run":      complete.PredictNothing,
+		"-lock":         completePredictBoolean,
+		"-lock-timeout": complete.PredictAnything,
+		"-state":        complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *StateRmCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state (remove|rm) [options] ADDRESS...
@@ -299,6 +299,9 @@ func TestStateRmNonExist(t *testing.T) {
 	if code := c.Run(args); code != 1 {
 		t.Fatalf("expected exit status %d, got: %d", 1, code)
 	}
+	if got, want := ui.ErrorWriter.String(), "Did you mean test_instance.bar?"; !strings.Contains(got, want) {
+		t.Fatalf("expected error to contain %q, got: %s", want, got)
+	}
 }
 
 func TestStateRm_backupExplicit(t *testing.T) {
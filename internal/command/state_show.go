@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
@@ -160,6 +161,9 @@ func (c *StateShowCommand) Run(args []string) int {
 	is := state.ResourceInstance(addr)
 	if !is.HasCurrent() {
 		c.Streams.Eprintln(errNoInstanceFound)
+		if suggestion := stateAddressSuggestion(state, addr.String()); suggestion != "" {
+			c.Streams.Eprintln(strings.TrimSpace(suggestion))
+		}
 		return 1
 	}
 
@@ -202,6 +206,21 @@ func (c *StateShowCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *StateShowCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictResourceAddress(c.CommandContext()),
+	}
+}
+
+func (c *StateShowCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-state":          complete.PredictFiles("*.tfstate"),
+		"-show-sensitive": complete.PredictNothing,
+		"-var":            c.completePredictVariableAssignment(c.CommandContext()),
+		"-var-file":       complete.PredictFiles("*.tfvars"),
+	}
+}
+
 func (c *StateShowCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state show [options] ADDRESS
@@ -8,13 +8,16 @@ package command
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -29,8 +32,10 @@ func (c *TaintCommand) Run(args []string) int {
 	ctx := c.CommandContext()
 	args = c.Meta.process(args)
 	var allowMissing bool
+	var filterExpr string
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("taint")
 	cmdFlags.BoolVar(&allowMissing, "allow-missing", false, "allow missing")
+	cmdFlags.StringVar(&filterExpr, "filter", "", "filter")
 	cmdFlags.StringVar(&c.Meta.backupPath, "backup", "", "path")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
@@ -44,24 +49,45 @@ func (c *TaintCommand) Run(args []string) int {
 
 	var diags tfdiags.Diagnostics
 
-	// Require the one argument for the resource to taint
 	args = cmdFlags.Args()
-	if len(args) != 1 {
-		c.Ui.Error("The taint command expects exactly one argument.")
-		cmdFlags.Usage()
-		return 1
-	}
+	var addr addrs.AbsResourceInstance
+	var filter *taintFilter
+	if filterExpr != "" {
+		// With a filter, the address is optional and can select any number
+		// of resource instances.
+		if len(args) > 1 {
+			c.Ui.Error("The taint command expects at most one argument when using -filter.")
+			cmdFlags.Usage()
+			return 1
+		}
 
-	addr, addrDiags := addrs.ParseAbsResourceInstanceStr(args[0])
-	diags = diags.Append(addrDiags)
-	if addrDiags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
-	}
+		var filterDiags tfdiags.Diagnostics
+		filter, filterDiags = c.parseTaintFilter(ctx, filterExpr, args)
+		diags = diags.Append(filterDiags)
+		if filterDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	} else {
+		// Require the one argument for the resource to taint
+		if len(args) != 1 {
+			c.Ui.Error("The taint command expects exactly one argument.")
+			cmdFlags.Usage()
+			return 1
+		}
 
-	if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
-		c.Ui.Error(fmt.Sprintf("Resource instance %s cannot be tainted", addr))
-		return 1
+		var addrDiags tfdiags.Diagnostics
+		addr, addrDiags = addrs.ParseAbsResourceInstanceStr(args[0])
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			c.Ui.Error(fmt.Sprintf("Resource instance %s cannot be tainted", addr))
+			return 1
+		}
 	}
 
 	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
@@ -107,7 +133,7 @@ func (c *TaintCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), workspace)
 		if diags := stateLocker.Lock(stateMgr, "taint"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -127,6 +153,11 @@ func (c *TaintCommand) Run(args []string) int {
 	// Get the actual state structure
 	state := stateMgr.State()
 	if state.Empty() {
+		if filter != nil {
+			c.showDiagnostics(diags)
+			c.Ui.Output("No resource instances match the filter.")
+			return 0
+		}
 		if allowMissing {
 			return c.allowMissingExit(addr)
 		}
@@ -148,6 +179,10 @@ func (c *TaintCommand) Run(args []string) int {
 		diags = diags.Append(schemaDiags)
 	}
 
+	if filter != nil {
+		return c.taintFiltered(stateMgr, state, schemas, filter, diags)
+	}
+
 	ss := state.SyncWrapper()
 
 	// Get the resource and instance we're going to taint
@@ -204,9 +239,90 @@ func (c *TaintCommand) Run(args []string) int {
 	return 0
 }
 
+// taintFiltered marks each resource instance that matches the given filter
+// as tainted and persists the state. The filter is evaluated for every
+// resource instance before any of them are tainted, so that an error doesn't
+// leave only some of them tainted.
+func (c *TaintCommand) taintFiltered(stateMgr statemgr.Full, state *states.State, schemas *tofu.Schemas, filter *taintFilter, diags tfdiags.Diagnostics) int {
+	var matched []addrs.AbsResourceInstance
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+				continue
+			}
+			for key, is := range rs.Instances {
+				if is.Current == nil {
+					continue
+				}
+				addr := rs.Addr.Instance(key)
+				match, moreDiags := filter.Match(addr, is.Current)
+				diags = diags.Append(moreDiags)
+				if moreDiags.HasErrors() {
+					c.showDiagnostics(diags)
+					return 1
+				}
+				if match {
+					matched = append(matched, addr)
+				}
+			}
+		}
+	}
+	if len(matched) == 0 {
+		c.showDiagnostics(diags)
+		c.Ui.Output("No resource instances match the filter.")
+		return 0
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Less(matched[j])
+	})
+
+	ss := state.SyncWrapper()
+	for _, addr := range matched {
+		rs := ss.Resource(addr.ContainingResource())
+		is := ss.ResourceInstance(addr)
+		obj := is.Current
+		obj.Status = states.ObjectTainted
+		ss.SetResourceInstanceCurrent(addr, obj, rs.ProviderConfig, is.ProviderKey)
+	}
+
+	if err := stateMgr.WriteState(state); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+		return 1
+	}
+	if err := stateMgr.PersistState(context.TODO(), schemas); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	for _, addr := range matched {
+		c.Ui.Output(fmt.Sprintf("Resource instance %s has been marked as tainted.", addr))
+	}
+	return 0
+}
+
+func (c *TaintCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictResourceAddress(c.CommandContext()),
+	}
+}
+
+func (c *TaintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-allow-missing": complete.PredictNothing,
+		"-backup":        complete.PredictFiles("*.tfstate"),
+		"-filter":        complete.PredictAnything,
+		"-lock":          completePredictBoolean,
+		"-lock-timeout":  complete.PredictAnything,
+		"-state":         complete.PredictFiles("*.tfstate"),
+		"-state-out":     complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *TaintCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] taint [options] <address>
+       tofu [global options] taint [options] -filter=EXPR [<address>]
 
   OpenTofu uses the term "tainted" to describe a resource instance
   which may not be fully functional, either because its creation
@@ -229,11 +345,22 @@ Usage: tofu [global options] taint [options] <address>
   address will reach OpenTofu correctly, without any special
   interpretation.
 
+  With -filter, every managed resource instance for which the given
+  expression is true is marked as tainted. The expression can refer
+  to the attributes of each instance as "values" and to input
+  variables as "var", for example:
+    -filter='values.ami != var.expected_ami'
+  The address is then optional, and can be a resource, resource
+  instance, or module to only consider the instances within it.
+
 Options:
 
   -allow-missing          If specified, the command will succeed (exit code 0)
                           even if the resource is missing.
 
+  -filter=EXPR            Taint each resource instance for which the given
+                          expression is true.
+
   -lock=false             Don't hold a state lock during the operation. This is
                           dangerous if others might concurrently run commands
                           against the same workspace.
@@ -0,0 +1,199 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// taintFilterFilename is the synthetic filename of the expression given with
+// "tofu taint -filter", for use in diagnostics.
+const taintFilterFilename = "<filter>"
+
+// taintFilter selects the resource instances that "tofu taint -filter"
+// marks as tainted, by evaluating a boolean expression against each of them.
+type taintFilter struct {
+	expr hcl.Expression
+
+	// target, if not nil, limits the filter to the resource instances that
+	// belong to it.
+	target addrs.Targetable
+
+	ctx *hcl.EvalContext
+}
+
+// parseTaintFilter parses the given filter expression, which can refer to the
+// root module input variables set by the usual -var and -var-file options,
+// along with an optional address to limit which resource instances it's
+// evaluated for.
+func (c *TaintCommand) parseTaintFilter(ctx context.Context, src string, args []string) (*taintFilter, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	c.registerSynthConfigSource(taintFilterFilename, []byte(src))
+	expr, hclDiags := hclsyntax.ParseExpression([]byte(src), taintFilterFilename, hcl.InitialPos)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	filter := &taintFilter{expr: expr}
+	for _, traversal := range expr.Variables() {
+		switch name := traversal.RootName(); name {
+		case "values", "var":
+		default:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference in filter",
+				Detail:   fmt.Sprintf("The filter can only refer to the attributes of each resource instance, as \"values\", and to input variables, as \"var\", not to %q.", name),
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+		}
+	}
+
+	if len(args) == 1 {
+		target, moreDiags := addrs.ParseTargetStr(args[0])
+		diags = diags.Append(moreDiags)
+		if !moreDiags.HasErrors() {
+			filter.target = target.Subject
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	vars, moreDiags := c.taintFilterVariables(ctx)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	scope := &lang.Scope{BaseDir: ".", PureOnly: true}
+	filter.ctx = &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(vars),
+		},
+		Functions: scope.Functions(),
+	}
+	return filter, diags
+}
+
+// taintFilterVariables returns the values of the root module input variables
+// for use in a taint filter. Variables declared in the configuration in the
+// current directory are converted to their declared types and default to
+// their declared defaults. Values for undeclared variables are taken as
+// strings, so that a filter can be used without any configuration present.
+func (c *TaintCommand) taintFilterVariables(ctx context.Context) (map[string]cty.Value, tfdiags.Diagnostics) {
+	raw, diags := c.collectVariableValues()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var decls map[string]*configs.Variable
+	if c.dirIsConfigPath(".") {
+		mod, moreDiags := c.loadSingleModule(ctx, ".", configs.SelectiveLoadAll)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		decls = mod.Variables
+	}
+
+	vars := make(map[string]cty.Value)
+	for name, decl := range decls {
+		if !decl.Default.IsNull() {
+			vars[name] = decl.Default
+		}
+	}
+	for name, rv := range raw {
+		mode := configs.VariableParseLiteral
+		decl, declared := decls[name]
+		if declared {
+			mode = decl.ParsingMode
+		}
+		val, moreDiags := rv.ParseVariableValue(mode)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		v := val.Value
+		if declared && decl.ConstraintType != cty.NilType {
+			var err error
+			v, err = convert.Convert(v, decl.ConstraintType)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid value for input variable",
+					fmt.Sprintf("The value given for var.%s is not suitable: %s.", name, tfdiags.FormatError(err)),
+				))
+				continue
+			}
+		}
+		vars[name] = v
+	}
+	return vars, diags
+}
+
+// Match evaluates the filter for the given resource instance object.
+func (f *taintFilter) Match(addr addrs.AbsResourceInstance, obj *states.ResourceInstanceObjectSrc) (bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if f.target != nil && !f.target.TargetContains(addr) {
+		return false, diags
+	}
+
+	// We don't need the provider's schema to evaluate a filter, so we use
+	// the type implied by the JSON representation of the object's
+	// attributes, as "tofu show -json" would show them.
+	values := cty.EmptyObjectVal
+	if len(obj.AttrsJSON) != 0 {
+		ty, err := ctyjson.ImpliedType(obj.AttrsJSON)
+		if err == nil {
+			values, err = ctyjson.Unmarshal(obj.AttrsJSON, ty)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid resource instance state",
+				fmt.Sprintf("Can't decode the attributes of %s to evaluate the filter: %s.", addr, err),
+			))
+			return false, diags
+		}
+	}
+
+	ctx := f.ctx.NewChild()
+	ctx.Variables = map[string]cty.Value{"values": values}
+	result, hclDiags := f.expr.Value(ctx)
+	for _, diag := range hclDiags {
+		diag.Detail = fmt.Sprintf("%s\n\nThis happened while evaluating the filter for %s.", diag.Detail, addr)
+	}
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return false, diags
+	}
+
+	result, err := convert.Convert(result, cty.Bool)
+	if err != nil || result.IsNull() || !result.IsKnown() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid filter result",
+			Detail:   fmt.Sprintf("The filter must return either true or false for each resource instance, but it didn't for %s.", addr),
+			Subject:  f.expr.Range().Ptr(),
+		})
+		return false, diags
+	}
+	return result.True(), diags
+}
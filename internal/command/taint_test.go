@@ -7,6 +7,7 @@ package command
 
 import (
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
@@ -581,3 +582,181 @@ module.child:
     ID = blah
     provider = provider["registry.opentofu.org/hashicorp/test"]
 `
+
+func testTaintFilterState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		for addr, attrs := range map[string]string{
+			"test_instance.web[0]":              `{"id":"web0","ami":"ami-old","size":1}`,
+			"test_instance.web[1]":              `{"id":"web1","ami":"ami-new","size":2}`,
+			"test_instance.web[2]":              `{"id":"web2","ami":"ami-old","size":3}`,
+			"module.child.test_instance.web[0]": `{"id":"child","ami":"ami-old","size":1}`,
+		} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(attrs),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+				addrs.NoKey,
+			)
+		}
+	})
+}
+
+func testTaintedAddrs(t *testing.T, statePath string) []string {
+	t.Helper()
+	var ret []string
+	state := testStateRead(t, statePath)
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				if is.Current.Status == states.ObjectTainted {
+					ret = append(ret, rs.Addr.Instance(key).String())
+				}
+			}
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestTaint_filter(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"with address": {
+			[]string{"-filter", "values.ami != var.expected_ami", "-var", "expected_ami=ami-new", "test_instance.web"},
+			[]string{"test_instance.web[0]", "test_instance.web[2]"},
+		},
+		"without address": {
+			[]string{"-filter", `values.ami == "ami-old"`},
+			[]string{"module.child.test_instance.web[0]", "test_instance.web[0]", "test_instance.web[2]"},
+		},
+		"module address": {
+			[]string{"-filter", `startswith(values.ami, "ami-")`, "module.child"},
+			[]string{"module.child.test_instance.web[0]"},
+		},
+		"no matches": {
+			[]string{"-filter", "values.size > 10"},
+			nil,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testCwdTemp(t)
+			statePath := testStateFile(t, testTaintFilterState())
+
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			c := &TaintCommand{
+				Meta: Meta{
+					Ui:   ui,
+					View: view,
+				},
+			}
+
+			args := append([]string{"-state", statePath}, test.args...)
+			if code := c.Run(args); code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+			}
+			if diff := cmp.Diff(test.want, testTaintedAddrs(t, statePath)); diff != "" {
+				t.Errorf("wrong tainted instances\n%s", diff)
+			}
+			for _, addr := range test.want {
+				if want := "Resource instance " + addr + " has been marked as tainted."; !strings.Contains(ui.OutputWriter.String(), want) {
+					t.Errorf("output is missing %q\n%s", want, ui.OutputWriter.String())
+				}
+			}
+		})
+	}
+}
+
+func TestTaint_filterDeclaredVariable(t *testing.T) {
+	testCwdTemp(t)
+	if err := os.WriteFile("main.tf", []byte(`
+variable "min_size" {
+  type    = number
+  default = 2
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statePath := testStateFile(t, testTaintFilterState())
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &TaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-filter", "values.size < var.min_size",
+		"test_instance.web",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if diff := cmp.Diff([]string{"test_instance.web[0]"}, testTaintedAddrs(t, statePath)); diff != "" {
+		t.Errorf("wrong tainted instances\n%s", diff)
+	}
+}
+
+func TestTaint_filterInvalid(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"invalid reference": {
+			[]string{"-filter", "local.ami != values.ami"},
+			"The filter can only refer to the attributes of each resource instance",
+		},
+		"not a boolean": {
+			[]string{"-filter", "values.ami"},
+			"The filter must return either true or false for each resource instance",
+		},
+		"missing attribute": {
+			[]string{"-filter", "values.region == \"us-east-1\""},
+			"This happened while evaluating the filter for",
+		},
+		"too many arguments": {
+			[]string{"-filter", "true", "test_instance.web", "module.child"},
+			"expects at most one argument when using -filter",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testCwdTemp(t)
+			statePath := testStateFile(t, testTaintFilterState())
+
+			ui := new(cli.MockUi)
+			view, done := testView(t)
+			c := &TaintCommand{
+				Meta: Meta{
+					Ui:   ui,
+					View: view,
+				},
+			}
+
+			args := append([]string{"-state", statePath}, test.args...)
+			if code := c.Run(args); code != 1 {
+				t.Fatalf("wrong exit status %d; want 1", code)
+			}
+			got := ui.ErrorWriter.String() + done(t).Stderr()
+			if got := strings.Join(strings.Fields(got), " "); !strings.Contains(got, test.want) {
+				t.Errorf("wrong error\nwant substring: %s\ngot: %s", test.want, got)
+			}
+			if got := testTaintedAddrs(t, statePath); len(got) != 0 {
+				t.Errorf("unexpected tainted instances: %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+resource "test_instance" "bad" {
+  ami = "bad"
+}
+
+resource "test_instance" "dependent" {
+  ami = test_instance.bad.id
+}
+
+resource "test_instance" "good" {
+  ami = "good"
+}
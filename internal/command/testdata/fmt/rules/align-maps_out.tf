@@ -0,0 +1,8 @@
+locals {
+  tags = {
+    Name          = "foo"
+    Environment   = var.enabled ? "prod" : "dev"
+    "Cost-Center" = "123"
+  }
+  names = { for k, v in var.m : k => v }
+}
@@ -0,0 +1,13 @@
+resource "test_instance" "foo" {
+  ami = "${var.ami}"
+  tags = {
+    Name = "${var.name}"
+    "${var.key}" = "value"
+    Env = "prefix-${var.env}"
+  }
+  list = ["${var.a}", "${upper("${var.b}")}"]
+  multi = [
+    "${var.enabled
+    ? "a" : "b"}",
+  ]
+}
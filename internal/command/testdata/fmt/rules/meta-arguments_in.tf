@@ -0,0 +1,27 @@
+resource "test_instance" "foo" {
+  ami = "bar"
+
+  # Create one instance per zone.
+  for_each = var.zones
+  provider = test.west
+  tags     = {}
+}
+
+resource "test_instance" "bar" {
+  count = 2
+
+  ami = "bar"
+}
+
+data "test_data_source" "baz" {
+  id    = "baz"
+  count = 1
+}
+
+module "child" {
+  for_each = var.zones
+
+  version = "1.0.0"
+  source  = "example/child/test"
+  zone    = each.key
+}
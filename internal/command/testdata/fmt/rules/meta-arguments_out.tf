@@ -0,0 +1,28 @@
+resource "test_instance" "foo" {
+  # Create one instance per zone.
+  for_each = var.zones
+  provider = test.west
+
+  ami  = "bar"
+  tags = {}
+}
+
+resource "test_instance" "bar" {
+  count = 2
+
+  ami = "bar"
+}
+
+data "test_data_source" "baz" {
+  count = 1
+
+  id = "baz"
+}
+
+module "child" {
+  source   = "example/child/test"
+  version  = "1.0.0"
+  for_each = var.zones
+
+  zone = each.key
+}
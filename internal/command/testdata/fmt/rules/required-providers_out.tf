@@ -0,0 +1,15 @@
+terraform {
+  required_providers {
+    # The AWS provider.
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    google = {
+      source = "hashicorp/google"
+    }
+    test = {
+      source = "hashicorp/test"
+    }
+  }
+}
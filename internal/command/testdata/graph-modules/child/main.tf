@@ -0,0 +1,7 @@
+variable "ami" {
+  type = string
+}
+
+resource "test_instance" "baz" {
+  ami = var.ami
+}
@@ -0,0 +1,8 @@
+resource "test_instance" "foo" {
+  ami = "bar"
+}
+
+module "child" {
+  source = "./child"
+  ami    = test_instance.foo.id
+}
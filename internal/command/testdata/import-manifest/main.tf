@@ -0,0 +1,11 @@
+provider "test" {
+  alias = "west"
+}
+
+resource "test_instance" "foo" {
+}
+
+resource "test_instance" "bar" {
+  count    = 2
+  provider = test.west
+}
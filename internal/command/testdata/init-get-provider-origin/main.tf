@@ -0,0 +1,11 @@
+terraform {
+  required_providers {
+    alpha = {
+      source  = "acme/alpha"
+      origin  = "registry.example.com/mirror/alpha"
+      version = "1.2.3"
+    }
+  }
+}
+
+resource "alpha_resource" "a" {}
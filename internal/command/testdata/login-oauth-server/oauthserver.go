@@ -17,9 +17,14 @@ import (
 // OAuth server implementation with the following endpoints:
 //
 //	/authz  - authorization endpoint
+//	/device - device authorization endpoint
 //	/token  - token endpoint
 //	/revoke - token revocation (logout) endpoint
 //
+// It also serves an OpenID Connect discovery document at
+// /.well-known/openid-configuration, describing the server itself as the
+// issuer.
+//
 // The authorization endpoint returns HTML per normal OAuth conventions, but
 // it also includes an HTTP header X-Redirect-To giving the same URL that the
 // link in the HTML indicates, allowing a non-browser user-agent to traverse
@@ -32,6 +37,10 @@ func (h handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	switch req.URL.Path {
 	case "/authz":
 		h.serveAuthz(resp, req)
+	case "/device":
+		h.serveDevice(resp, req)
+	case "/.well-known/openid-configuration":
+		h.serveOIDCConfig(resp, req)
 	case "/token":
 		h.serveToken(resp, req)
 	case "/revoke":
@@ -83,6 +92,43 @@ func (h handler) serveAuthz(resp http.ResponseWriter, req *http.Request) {
 	resp.Write([]byte(respBody))
 }
 
+func (h handler) serveDevice(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		resp.WriteHeader(405)
+		log.Printf("/device: unsupported request method %q", req.Method)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		resp.WriteHeader(500)
+		log.Printf("/device: error parsing body: %s", err)
+		return
+	}
+
+	// The device code is the client ID, so that tests can choose the result
+	// of the token request by choosing the client ID. The "denied" client ID
+	// causes the login to be denied.
+	baseURL := "http://" + req.Host
+	respBody := fmt.Sprintf(
+		`{"device_code":%q,"user_code":"ABCD-EFGH","verification_uri":%q,"verification_uri_complete":%q,"interval":1,"expires_in":300}`,
+		req.Form.Get("client_id"), baseURL+"/activate", baseURL+"/activate?user_code=ABCD-EFGH",
+	)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(200)
+	resp.Write([]byte(respBody))
+}
+
+func (h handler) serveOIDCConfig(resp http.ResponseWriter, req *http.Request) {
+	baseURL := "http://" + req.Host
+	respBody := fmt.Sprintf(
+		`{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"device_authorization_endpoint":%q}`,
+		baseURL, baseURL+"/authz", baseURL+"/token", baseURL+"/device",
+	)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(200)
+	resp.Write([]byte(respBody))
+}
+
 func (h handler) serveToken(resp http.ResponseWriter, req *http.Request) {
 	if req.Method != "POST" {
 		resp.WriteHeader(405)
@@ -164,6 +210,20 @@ func (h handler) serveToken(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(`{"access_token":"good-token","token_type":"bearer"}`))
 		log.Println("/token: successful request")
 
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		if req.Form.Get("device_code") == "denied" {
+			resp.Header().Set("Content-Type", "application/json")
+			resp.WriteHeader(400)
+			resp.Write([]byte(`{"error":"access_denied"}`))
+			log.Println("/token: denied device code")
+			return
+		}
+
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(200)
+		resp.Write([]byte(`{"access_token":"good-token","token_type":"bearer"}`))
+		log.Println("/token: successful request")
+
 	default:
 		resp.WriteHeader(400)
 		log.Printf("/token: unsupported grant type %q", grantType)
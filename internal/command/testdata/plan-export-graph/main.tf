@@ -0,0 +1,11 @@
+resource "test_instance" "base" {
+  ami = "base"
+}
+
+locals {
+  base_id = test_instance.base.id
+}
+
+resource "test_instance" "app" {
+  ami = local.base_id
+}
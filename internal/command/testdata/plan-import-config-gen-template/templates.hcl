@@ -0,0 +1,7 @@
+resource_template "test_instance" {
+  variables = ["ami"]
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
@@ -0,0 +1,9 @@
+resource "test_instance" "a" {
+  connection {
+    host = "a.example.com"
+  }
+
+  provisioner "shell" {
+    command = "register ${self.id}"
+  }
+}
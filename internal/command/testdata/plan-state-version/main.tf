@@ -0,0 +1,7 @@
+terraform {
+  backend "inmem" {}
+}
+
+resource "test_instance" "foo" {
+  ami = "bar"
+}
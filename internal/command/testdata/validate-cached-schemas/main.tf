@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    test = {
+      source  = "hashicorp/test"
+      version = "1.2.3"
+    }
+  }
+}
+
+resource "test_instance" "foo" {
+  ami = "bar"
+
+  network_interface {
+    device_index = 0
+  }
+}
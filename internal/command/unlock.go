@@ -9,7 +9,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 
 	"github.com/mitchellh/cli"
@@ -28,9 +30,11 @@ func (c *UnlockCommand) Run(args []string) int {
 	ctx := c.CommandContext()
 	args = c.Meta.process(args)
 	var force bool
+	var workspace string
 	cmdFlags := c.Meta.defaultFlagSet("force-unlock")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.BoolVar(&force, "force", false, "force")
+	cmdFlags.StringVar(&workspace, "workspace", "", "workspace")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -83,10 +87,13 @@ func (c *UnlockCommand) Run(args []string) int {
 	// unlocking is read only when looking at state data
 	c.ignoreRemoteVersionConflict(b)
 
-	env, err := c.Workspace(ctx)
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
-		return 1
+	env := workspace
+	if env == "" {
+		env, err = c.Workspace(ctx)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+			return 1
+		}
 	}
 	stateMgr, err := b.StateMgr(ctx, env)
 	if err != nil {
@@ -132,11 +139,29 @@ func (c *UnlockCommand) Run(args []string) int {
 		}
 	}
 
+	// Capture the details of the lock we're about to break, if possible, so
+	// that we can include them in the notification.
+	brokenLock := &statemgr.LockInfo{ID: lockID}
+	if inspector, ok := stateMgr.(statemgr.LockInspector); ok {
+		if info, err := inspector.CurrentLock(ctx); err == nil && info != nil && info.ID == lockID {
+			brokenLock = info
+		}
+	}
+
 	if err := stateMgr.Unlock(context.TODO(), lockID); err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to unlock state: %s", err))
 		return 1
 	}
 
+	if c.LockNotifier != nil {
+		c.LockNotifier.NotifyLock(ctx, clistate.LockEvent{
+			Type:      clistate.LockEventForceUnlocked,
+			Workspace: env,
+			Lock:      brokenLock,
+			Timestamp: time.Now().UTC(),
+		})
+	}
+
 	c.Ui.Output(c.Colorize().Color(strings.TrimSpace(outputUnlockSuccess)))
 	return 0
 }
@@ -148,14 +173,19 @@ Usage: tofu [global options] force-unlock [options] LOCK_ID
   Manually unlock the state for the defined configuration.
 
   This will not modify your infrastructure. This command removes the lock on the
-  state for the current workspace. The behavior of this lock is dependent
-  on the backend being used. Local state files cannot be unlocked by another
-  process.
+  state for the current workspace, or for the workspace given by the
+  -workspace option. The behavior of this lock is dependent on the backend
+  being used. Local state files cannot be unlocked by another process.
+
+  Use "tofu locks list" to find the lock IDs of locks held in any workspace.
 
 Options:
 
   -force                 Don't ask for input for unlock confirmation.
 
+  -workspace=name        Unlock the state of the given workspace instead of
+                         the currently-selected workspace.
+
   -var 'foo=bar'         Set a value for one of the input variables in the root
                          module of the configuration. Use this option more than
                          once to set more than one variable.
@@ -10,9 +10,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -98,7 +99,7 @@ func (c *UntaintCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), workspace)
 		if diags := stateLocker.Lock(stateMgr, "untaint"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -205,6 +206,23 @@ func (c *UntaintCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *UntaintCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictResourceAddress(c.CommandContext()),
+	}
+}
+
+func (c *UntaintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-allow-missing": complete.PredictNothing,
+		"-backup":        complete.PredictFiles("*.tfstate"),
+		"-lock":          completePredictBoolean,
+		"-lock-timeout":  complete.PredictAnything,
+		"-state":         complete.PredictFiles("*.tfstate"),
+		"-state-out":     complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *UntaintCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] untaint [options] name
@@ -62,7 +62,7 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
 
-	validateDiags := c.validate(ctx, dir, args.TestDirectory, args.NoTests)
+	validateDiags := c.validate(ctx, dir, args.TestDirectory, args.NoTests, args.CachedSchemas)
 	diags = diags.Append(validateDiags)
 
 	// Validating with dev overrides in effect means that the result might
@@ -91,7 +91,7 @@ func (c *ValidateCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
-func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noTests bool) tfdiags.Diagnostics {
+func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noTests, cachedSchemas bool) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	var cfg *configs.Config
 
@@ -108,6 +108,17 @@ func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noT
 		var diags tfdiags.Diagnostics
 
 		opts, err := c.contextOpts(ctx)
+		if cachedSchemas && opts != nil {
+			// The installed providers aren't used, so it doesn't matter if
+			// they failed to load.
+			var moreDiags tfdiags.Diagnostics
+			opts.Providers, moreDiags = c.cachedSchemaProviderFactories(cfg)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				return diags
+			}
+			err = nil
+		}
 		if err != nil {
 			diags = diags.Append(err)
 			return diags
@@ -193,6 +204,10 @@ Usage: tofu [global options] validate [options]
   validation without accessing any configured remote backend, use:
       tofu init -backend=false
 
+  Alternatively, -cached-schemas validates against provider schemas that
+  "tofu init -cache-schemas" cached in any other working directory, so that
+  the providers don't need to be installed.
+
   To verify configuration in the context of a particular run (a particular
   target workspace, input variable values, etc), use the 'tofu plan'
   command instead, which includes an implied validation check.
@@ -211,6 +226,12 @@ Options:
                         will be performed. All locations, for all errors
                         will be listed. Disabled by default
 
+  -cached-schemas       Validate against the provider schemas cached by
+                        "tofu init -cache-schemas", which doesn't require
+                        the providers to be installed. Only the schemas are
+                        checked, not any additional validation rules the
+                        providers implement.
+
   -json                 Produce output in a machine-readable JSON format, 
                         suitable for use in text editor integrations and other 
                         automated systems. Always disables color.
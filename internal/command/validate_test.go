@@ -26,6 +26,21 @@ import (
 
 func setupTest(t *testing.T, fixturepath string, args ...string) (*terminal.TestOutput, int) {
 	view, done := testView(t)
+	c := &ValidateCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(validateTestProvider()),
+			View:             view,
+		},
+	}
+
+	args = append(args, "-no-color")
+	args = append(args, testFixturePath(fixturepath))
+
+	code := c.Run(args)
+	return done(t), code
+}
+
+func validateTestProvider() providers.Interface {
 	p := testProvider()
 	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
 		ResourceTypes: map[string]providers.Schema{
@@ -50,18 +65,7 @@ func setupTest(t *testing.T, fixturepath string, args ...string) (*terminal.Test
 			},
 		},
 	}
-	c := &ValidateCommand{
-		Meta: Meta{
-			testingOverrides: metaOverridesForProvider(p),
-			View:             view,
-		},
-	}
-
-	args = append(args, "-no-color")
-	args = append(args, testFixturePath(fixturepath))
-
-	code := c.Run(args)
-	return done(t), code
+	return p
 }
 
 func TestValidateCommand(t *testing.T) {
@@ -395,3 +399,86 @@ func TestValidate_json(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_cachedSchemas(t *testing.T) {
+	cliConfigDir := t.TempDir()
+
+	// The schema is cached by initializing one working directory...
+	initDir := t.TempDir()
+	testCopyDir(t, testFixturePath("validate-cached-schemas"), initDir)
+	t.Chdir(initDir)
+	providerSource, closeSource := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer closeSource()
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	initCmd := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(validateTestProvider()),
+			Ui:               ui,
+			View:             view,
+			ProviderSource:   providerSource,
+			CLIConfigDir:     cliConfigDir,
+		},
+	}
+	if code := initCmd.Run([]string{"-backend=false", "-cache-schemas"}); code != 0 {
+		t.Fatalf("init failed\n%s", ui.ErrorWriter.String())
+	}
+	cacheFile := path.Join(cliConfigDir, "provider-schemas", "registry.opentofu.org", "hashicorp", "test", "1.2.3.json")
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Fatalf("schema not cached: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	// ...and then used to validate another one, without installing the
+	// provider or having a dependency lock file.
+	validateDir := t.TempDir()
+	testCopyDir(t, testFixturePath("validate-cached-schemas"), validateDir)
+	t.Chdir(validateDir)
+	validate := func(t *testing.T, cliConfigDir string) (*terminal.TestOutput, int) {
+		view, done := testView(t)
+		c := &ValidateCommand{
+			Meta: Meta{
+				View:         view,
+				CLIConfigDir: cliConfigDir,
+			},
+		}
+		code := c.Run([]string{"-no-color", "-cached-schemas"})
+		return done(t), code
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		output, code := validate(t, cliConfigDir)
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		output, code := validate(t, t.TempDir())
+		if code != 1 {
+			t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+		}
+		if got, want := output.Stderr(), "Provider schema not cached"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		src := `
+resource "test_instance" "bar" {
+  amii = "bar"
+}
+`
+		if err := os.WriteFile("invalid.tf", []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		output, code := validate(t, cliConfigDir)
+		if code != 1 {
+			t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+		}
+		if got, want := output.Stderr(), `An argument named "amii" is not expected here. Did you mean "ami"?`; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+}
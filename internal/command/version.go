@@ -7,14 +7,18 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/modsdir"
 )
 
 // VersionCommand is a Command implementation prints the version.
@@ -30,6 +34,22 @@ type VersionOutput struct {
 	Version            string            `json:"terraform_version"`
 	Platform           string            `json:"platform"`
 	ProviderSelections map[string]string `json:"provider_selections"`
+	Modules            []VersionModule   `json:"modules,omitempty"`
+	Backend            *VersionBackend   `json:"backend,omitempty"`
+}
+
+// VersionModule describes a module installed in the current working
+// directory, in the output of "tofu version -json -dependencies".
+type VersionModule struct {
+	Key     string `json:"key"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// VersionBackend describes the backend that the current working directory
+// was initialized with, in the output of "tofu version -json -dependencies".
+type VersionBackend struct {
+	Type string `json:"type"`
 }
 
 func (c *VersionCommand) Help() string {
@@ -40,7 +60,10 @@ Usage: tofu [global options] version [options]
 
 Options:
 
-  -json       Output the version information as a JSON object.
+  -json          Output the version information as a JSON object.
+
+  -dependencies  Also show the modules installed in the current working
+                 directory and the backend that it was initialized with.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -48,9 +71,10 @@ Options:
 func (c *VersionCommand) Run(args []string) int {
 	var versionString bytes.Buffer
 	args = c.Meta.process(args)
-	var jsonOutput bool
+	var jsonOutput, showDependencies bool
 	cmdFlags := c.Meta.defaultFlagSet("version")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&showDependencies, "dependencies", false, "dependencies")
 	// Enable but ignore the global version flags. In main.go, if any of the
 	// arguments are -v, -version, or --version, this command will be called
 	// with the rest of the arguments, so we need to be able to cope with
@@ -90,6 +114,15 @@ func (c *VersionCommand) Run(args []string) int {
 		}
 	}
 
+	// The modules and the backend are recorded by "tofu init" too, so the same
+	// caveats apply to them as to the provider versions above.
+	var modules []VersionModule
+	var backend *VersionBackend
+	if showDependencies {
+		modules = c.installedModules()
+		backend = &VersionBackend{Type: c.initializedBackendType()}
+	}
+
 	if jsonOutput {
 		selectionsOutput := make(map[string]string)
 		for providerAddr, lock := range providerLocks {
@@ -108,6 +141,8 @@ func (c *VersionCommand) Run(args []string) int {
 			Version:            versionOutput,
 			Platform:           c.Platform.String(),
 			ProviderSelections: selectionsOutput,
+			Modules:            modules,
+			Backend:            backend,
 		}
 
 		jsonOutput, err := json.MarshalIndent(output, "", "  ")
@@ -127,11 +162,65 @@ func (c *VersionCommand) Run(args []string) int {
 				c.Ui.Output(str)
 			}
 		}
+		for _, module := range modules {
+			if module.Version != "" {
+				c.Ui.Output(fmt.Sprintf("+ module %s %s v%s", module.Key, module.Source, module.Version))
+			} else {
+				c.Ui.Output(fmt.Sprintf("+ module %s %s", module.Key, module.Source))
+			}
+		}
+		if backend != nil {
+			c.Ui.Output(fmt.Sprintf("+ backend %s", backend.Type))
+		}
 	}
 
 	return 0
 }
 
+// installedModules returns the modules recorded in the module manifest of
+// the current working directory, sorted by their keys, or nil if there are
+// none.
+func (c *VersionCommand) installedModules() []VersionModule {
+	manifest, err := modsdir.ReadManifestSnapshotForDir(c.modulesDir())
+	if err != nil {
+		return nil
+	}
+	var ret []VersionModule
+	for key, record := range manifest {
+		if key == "" {
+			// The root module is always in the manifest, but it isn't
+			// a dependency.
+			continue
+		}
+		module := VersionModule{
+			Key:    key,
+			Source: record.SourceAddr,
+		}
+		if record.Version != nil {
+			module.Version = record.Version.String()
+		}
+		ret = append(ret, module)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Key < ret[j].Key
+	})
+	return ret
+}
+
+// initializedBackendType returns the type of the backend that the current
+// working directory was initialized with, which is "local" if "tofu init"
+// didn't record any other backend.
+func (c *VersionCommand) initializedBackendType() string {
+	sMgr := &clistate.LocalState{Path: filepath.Join(c.DataDir(), DefaultStateFilename)}
+	if err := sMgr.RefreshState(context.TODO()); err != nil {
+		return "local"
+	}
+	if s := sMgr.State(); s != nil && s.Backend != nil && s.Backend.Type != "" {
+		return s.Backend.Type
+	}
+	return "local"
+}
+
 func (c *VersionCommand) Synopsis() string {
 	return "Show the current OpenTofu version"
 }
@@ -7,15 +7,19 @@ package command
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	version "github.com/hashicorp/go-version"
 	"github.com/mitchellh/cli"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/modsdir"
 )
 
 func TestVersionCommand_implements(t *testing.T) {
@@ -173,3 +177,110 @@ func TestVersion_json(t *testing.T) {
 	}
 
 }
+
+func TestVersion_dependencies(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	locks := depsfile.NewLocks()
+	locks.SetProvider(
+		addrs.NewDefaultProvider("test"),
+		getproviders.MustParseVersion("1.2.3"),
+		nil,
+		nil,
+	)
+
+	// These are the files that "tofu init" would have written when
+	// installing the modules and initializing the backend.
+	modulesDir := filepath.Join(DefaultDataDir, "modules")
+	if err := os.MkdirAll(modulesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := modsdir.Manifest{
+		"": {
+			Key: "",
+			Dir: ".",
+		},
+		"vpc": {
+			Key:        "vpc",
+			SourceAddr: "registry.opentofu.org/terraform-aws-modules/vpc/aws",
+			Version:    version.Must(version.NewVersion("5.8.1")),
+			Dir:        ".terraform/modules/vpc",
+		},
+		"app": {
+			Key:        "app",
+			SourceAddr: "./modules/app",
+			Dir:        "modules/app",
+		},
+	}
+	if err := manifest.WriteSnapshotToDir(modulesDir); err != nil {
+		t.Fatal(err)
+	}
+	backendState := `{"version": 3, "serial": 0, "backend": {"type": "s3", "config": {}, "hash": 0}}`
+	if err := os.WriteFile(filepath.Join(DefaultDataDir, DefaultStateFilename), []byte(backendState), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &VersionCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+		Version:  "4.5.6",
+		Platform: getproviders.Platform{OS: "aros", Arch: "riscv64"},
+	}
+	if err := c.replaceLockedDependencies(context.Background(), locks); err != nil {
+		t.Fatal(err)
+	}
+
+	// `tofu version -dependencies`
+	if code := c.Run([]string{"-dependencies"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	expected := strings.TrimSpace(`
+OpenTofu v4.5.6
+on aros_riscv64
++ provider registry.opentofu.org/hashicorp/test v1.2.3
++ module app ./modules/app
++ module vpc registry.opentofu.org/terraform-aws-modules/vpc/aws v5.8.1
++ backend s3
+`)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+
+	ui.OutputWriter.Reset()
+
+	// `tofu version -json -dependencies`
+	if code := c.Run([]string{"-json", "-dependencies"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	actual = strings.TrimSpace(ui.OutputWriter.String())
+	expected = strings.TrimSpace(`
+{
+  "terraform_version": "4.5.6",
+  "platform": "aros_riscv64",
+  "provider_selections": {
+    "registry.opentofu.org/hashicorp/test": "1.2.3"
+  },
+  "modules": [
+    {
+      "key": "app",
+      "source": "./modules/app"
+    },
+    {
+      "key": "vpc",
+      "source": "registry.opentofu.org/terraform-aws-modules/vpc/aws",
+      "version": "5.8.1"
+    }
+  ],
+  "backend": {
+    "type": "s3"
+  }
+}
+`)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
@@ -36,6 +36,7 @@ func NewApply(vt arguments.ViewType, destroy bool, view *View) Apply {
 			view:      NewJSONView(view),
 			destroy:   destroy,
 			countHook: &countHook{},
+			progress:  newApplyProgressTracker(),
 		}
 	case arguments.ViewHuman:
 		return &ApplyHuman{
@@ -146,6 +147,7 @@ type ApplyJSON struct {
 	destroy bool
 
 	countHook *countHook
+	progress  *applyProgressTracker
 }
 
 var _ Apply = (*ApplyJSON)(nil)
@@ -175,13 +177,15 @@ func (v *ApplyJSON) Outputs(outputValues map[string]*states.OutputValue) {
 }
 
 func (v *ApplyJSON) Operation() Operation {
-	return &OperationJSON{view: v.view}
+	return &OperationJSON{view: v.view, progress: v.progress}
 }
 
 func (v *ApplyJSON) Hooks() []tofu.Hook {
+	hook := newJSONHook(v.view)
+	hook.progress = v.progress
 	return []tofu.Hook{
 		v.countHook,
-		newJSONHook(v.view),
+		hook,
 	}
 }
 
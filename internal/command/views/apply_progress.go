@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// applyProgressTracker follows the progress of applying a plan, so that the
+// JSON view can report how much of the apply is complete and estimate how
+// long the rest of it will take.
+//
+// Estimates are based on the apply durations recorded in the prior state:
+// each change is expected to take as long as the previous change to the same
+// object, or otherwise the average for other objects of the same resource
+// type. Changes with no history are expected to take as long as the average
+// of the changes completed so far.
+type applyProgressTracker struct {
+	mu sync.Mutex
+
+	started time.Time
+	retries int
+	changes map[string]*trackedChange
+}
+
+type trackedChange struct {
+	estimate time.Duration
+
+	// A replace action is applied in two passes, each reported separately
+	// through the apply hooks, and the change is only complete once all of
+	// its passes are.
+	passes    int
+	remaining int
+
+	inProgress int
+	errored    bool
+	elapsed    time.Duration
+}
+
+func newApplyProgressTracker() *applyProgressTracker {
+	return &applyProgressTracker{}
+}
+
+// start begins tracking progress of applying the given plan, returning the
+// initial summary message. Retries is the number of earlier attempts to
+// apply the plan that didn't complete its changes.
+func (t *applyProgressTracker) start(plan *plans.Plan, retries int, now time.Time) json.Hook {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.started = now
+	t.retries = retries
+	t.changes = make(map[string]*trackedChange)
+
+	estimates := newApplyDurationEstimates(plan.PriorState)
+	for _, change := range plan.Changes.Resources {
+		if change.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+		passes := 1
+		switch change.Action {
+		case plans.NoOp, plans.Read, plans.Forget:
+			// These changes aren't reported through PreApply and PostApply.
+			continue
+		case plans.DeleteThenCreate, plans.CreateThenDelete:
+			passes = 2
+		}
+		key := change.Addr.String()
+		tc, ok := t.changes[key]
+		if !ok {
+			tc = &trackedChange{estimate: estimates.forInstance(change.Addr)}
+			t.changes[key] = tc
+		}
+		// A deposed object planned for destruction shares its address with
+		// the current object, so we track them as one change.
+		tc.passes += passes
+		tc.remaining += passes
+	}
+	return t.summaryLocked(now)
+}
+
+// resourceStarted records that a pass of the change for the given resource
+// instance has started, returning what's known about its history.
+func (t *applyProgressTracker) resourceStarted(addr addrs.AbsResourceInstance) json.ApplyHistory {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tc, ok := t.changes[addr.String()]
+	if !ok {
+		return json.ApplyHistory{}
+	}
+	tc.inProgress++
+	return json.ApplyHistory{
+		Retries:  t.retries,
+		Estimate: tc.estimate,
+	}
+}
+
+// resourceFinished records that a pass of the change for the given resource
+// instance has finished after the given elapsed time, returning an updated
+// summary message if this finished the change as a whole.
+func (t *applyProgressTracker) resourceFinished(addr addrs.AbsResourceInstance, elapsed time.Duration, errored bool, now time.Time) json.Hook {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tc, ok := t.changes[addr.String()]
+	if !ok || tc.remaining == 0 {
+		return nil
+	}
+	if tc.inProgress > 0 {
+		tc.inProgress--
+	}
+	tc.elapsed += elapsed
+	if errored {
+		// OpenTofu won't attempt any further passes after one fails.
+		tc.errored = true
+		tc.remaining = 0
+	} else {
+		tc.remaining--
+	}
+	if tc.remaining > 0 {
+		return nil
+	}
+	return t.summaryLocked(now)
+}
+
+// retryCount returns the number of earlier attempts that didn't complete the
+// change for the given resource instance.
+func (t *applyProgressTracker) retryCount(addr addrs.AbsResourceInstance) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.changes[addr.String()]; !ok {
+		return 0
+	}
+	return t.retries
+}
+
+func (t *applyProgressTracker) summaryLocked(now time.Time) json.Hook {
+	var completed, errored, inProgress int
+	for _, tc := range t.changes {
+		switch {
+		case tc.errored:
+			errored++
+		case tc.remaining == 0:
+			completed++
+		case tc.inProgress > 0:
+			inProgress++
+		}
+	}
+	elapsed := now.Sub(t.started).Round(time.Second)
+	eta, ok := t.etaLocked(elapsed)
+	return json.NewApplyOverallProgress(len(t.changes), completed, errored, inProgress, elapsed, eta, ok)
+}
+
+// etaLocked estimates how long the remaining changes will take, returning
+// false if there's no basis for an estimate yet.
+func (t *applyProgressTracker) etaLocked(elapsed time.Duration) (time.Duration, bool) {
+	// Changes without any history are expected to take as long as the
+	// average of those that have finished successfully so far.
+	var finished int
+	var finishedTotal time.Duration
+	for _, tc := range t.changes {
+		if tc.remaining == 0 && !tc.errored {
+			finished++
+			finishedTotal += tc.elapsed
+		}
+	}
+	var fallback time.Duration
+	haveFallback := finished > 0
+	if haveFallback {
+		fallback = finishedTotal / time.Duration(finished)
+	}
+
+	var remaining, done time.Duration
+	for _, tc := range t.changes {
+		if tc.errored {
+			continue
+		}
+		estimate := tc.estimate
+		if estimate == 0 {
+			if !haveFallback {
+				return 0, false
+			}
+			estimate = fallback
+		}
+		left := estimate * time.Duration(tc.remaining) / time.Duration(tc.passes)
+		remaining += left
+		done += estimate - left
+	}
+	if remaining == 0 {
+		return 0, true
+	}
+
+	// Once some of the estimated work is done we scale the estimate by how
+	// quickly that work actually happened, which accounts for changes being
+	// applied concurrently and for the estimates being consistently too
+	// long or too short.
+	if done > 0 && elapsed > 0 {
+		remaining = time.Duration(float64(remaining) * float64(elapsed) / float64(done))
+	}
+	return remaining.Round(time.Second), true
+}
+
+// applyDurationEstimates looks up the durations recorded in a state snapshot
+// for estimating how long future changes will take.
+type applyDurationEstimates struct {
+	state  *states.State
+	byType map[string]time.Duration
+}
+
+func newApplyDurationEstimates(state *states.State) applyDurationEstimates {
+	ret := applyDurationEstimates{
+		state:  state,
+		byType: make(map[string]time.Duration),
+	}
+	if state == nil {
+		return ret
+	}
+
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+				continue
+			}
+			for _, is := range rs.Instances {
+				if is.Current == nil || is.Current.ApplyDuration == 0 {
+					continue
+				}
+				totals[rs.Addr.Resource.Type] += is.Current.ApplyDuration
+				counts[rs.Addr.Resource.Type]++
+			}
+		}
+	}
+	for typeName, total := range totals {
+		ret.byType[typeName] = total / time.Duration(counts[typeName])
+	}
+	return ret
+}
+
+// forInstance returns the estimated duration for a change to the given
+// resource instance, or zero if there's nothing to base an estimate on.
+func (e applyDurationEstimates) forInstance(addr addrs.AbsResourceInstance) time.Duration {
+	if e.state != nil {
+		if is := e.state.ResourceInstance(addr); is != nil && is.Current != nil && is.Current.ApplyDuration != 0 {
+			return is.Current.ApplyDuration
+		}
+	}
+	return e.byType[addr.Resource.Resource.Type]
+}
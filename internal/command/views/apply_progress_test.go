@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestApplyProgressTracker(t *testing.T) {
+	a := mustResourceInstanceAddr("test_instance.a")
+	b := mustResourceInstanceAddr("test_instance.b")
+	c := mustResourceInstanceAddr("test_instance.c")
+	d := mustResourceInstanceAddr("test_thing.d")
+
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	prior := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(a, &states.ResourceInstanceObjectSrc{
+			Status:        states.ObjectReady,
+			AttrsJSON:     []byte(`{"id":"a"}`),
+			ApplyDuration: 10 * time.Second,
+		}, provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(b, &states.ResourceInstanceObjectSrc{
+			Status:        states.ObjectReady,
+			AttrsJSON:     []byte(`{"id":"b"}`),
+			ApplyDuration: 30 * time.Second,
+		}, provider, addrs.NoKey)
+	})
+	change := func(addr addrs.AbsResourceInstance, action plans.Action) *plans.ResourceInstanceChangeSrc {
+		return &plans.ResourceInstanceChangeSrc{
+			Addr:         addr,
+			PrevRunAddr:  addr,
+			ProviderAddr: provider,
+			ChangeSrc:    plans.ChangeSrc{Action: action},
+		}
+	}
+	plan := &plans.Plan{
+		PriorState: prior,
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				change(a, plans.Update),
+				change(b, plans.DeleteThenCreate),
+				change(c, plans.Create),
+			},
+		},
+	}
+
+	start := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	tracker := newApplyProgressTracker()
+
+	// The new instance of test_instance is expected to take the average
+	// of the existing ones.
+	checkProgress(t, tracker.start(plan, 2, start), "Applied 0 of 3 changes, about 1m0s remaining")
+
+	if got, want := tracker.resourceStarted(a), (json.ApplyHistory{Retries: 2, Estimate: 10 * time.Second}); got != want {
+		t.Errorf("wrong history for %s\ngot:  %#v\nwant: %#v", a, got, want)
+	}
+	if got, want := tracker.resourceStarted(c), (json.ApplyHistory{Retries: 2, Estimate: 20 * time.Second}); got != want {
+		t.Errorf("wrong history for %s\ngot:  %#v\nwant: %#v", c, got, want)
+	}
+	if got := tracker.resourceStarted(d); got != (json.ApplyHistory{}) {
+		t.Errorf("unexpected history for untracked %s: %#v", d, got)
+	}
+
+	// The first change took half as long as expected, so the remaining
+	// estimate is halved too.
+	checkProgress(t, tracker.resourceFinished(a, 5*time.Second, false, start.Add(5*time.Second)), "Applied 1 of 3 changes, about 25s remaining")
+
+	// The first pass of a replacement doesn't finish the change.
+	tracker.resourceStarted(b)
+	if got := tracker.resourceFinished(b, 4*time.Second, false, start.Add(9*time.Second)); got != nil {
+		t.Errorf("unexpected progress after first pass of replacement: %s", got)
+	}
+
+	checkProgress(t, tracker.resourceFinished(c, 10*time.Second, true, start.Add(10*time.Second)), "Applied 1 of 3 changes, 1 errored, about 6s remaining")
+
+	tracker.resourceStarted(b)
+	checkProgress(t, tracker.resourceFinished(b, 6*time.Second, false, start.Add(15*time.Second)), "Applied 2 of 3 changes, 1 errored")
+
+	if got := tracker.retryCount(b); got != 2 {
+		t.Errorf("wrong retry count %d for %s; want 2", got, b)
+	}
+	if got := tracker.retryCount(d); got != 0 {
+		t.Errorf("wrong retry count %d for untracked %s; want 0", got, d)
+	}
+}
+
+func TestApplyProgressTracker_noHistory(t *testing.T) {
+	a := mustResourceInstanceAddr("test_instance.a")
+	b := mustResourceInstanceAddr("test_instance.b")
+
+	plan := &plans.Plan{
+		PriorState: states.NewState(),
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				{Addr: a, PrevRunAddr: a, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+				{Addr: b, PrevRunAddr: b, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+				{Addr: mustResourceInstanceAddr("data.test_data_source.c"), ChangeSrc: plans.ChangeSrc{Action: plans.Read}},
+			},
+		},
+	}
+
+	start := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	tracker := newApplyProgressTracker()
+
+	// Without any history there's no basis for an estimate until the first
+	// change has finished.
+	checkProgress(t, tracker.start(plan, 0, start), "Applied 0 of 2 changes")
+
+	tracker.resourceStarted(a)
+	tracker.resourceStarted(b)
+	checkProgress(t, tracker.resourceFinished(a, 8*time.Second, false, start.Add(8*time.Second)), "Applied 1 of 2 changes, about 8s remaining")
+}
+
+func checkProgress(t *testing.T, hook json.Hook, want string) {
+	t.Helper()
+	if hook == nil {
+		t.Fatalf("no progress reported; want %q", want)
+	}
+	if got := hook.HookType(); got != json.MessageApplyOverallProgress {
+		t.Fatalf("wrong message type %q", got)
+	}
+	if got := hook.String(); got != want {
+		t.Errorf("wrong progress\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func mustResourceInstanceAddr(s string) addrs.AbsResourceInstance {
+	addr, diags := addrs.ParseAbsResourceInstanceStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return addr
+}
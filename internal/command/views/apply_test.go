@@ -12,8 +12,10 @@ import (
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -284,3 +286,99 @@ func TestApplyJSON_outputs(t *testing.T) {
 	}
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
+
+func TestApplyJSON_progress(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewApply(arguments.ViewJSON, false, NewView(streams))
+
+	addr := mustResourceInstanceAddr("test_instance.foo")
+	plan := &plans.Plan{
+		PriorState: states.NewState(),
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				{Addr: addr, PrevRunAddr: addr, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+			},
+		},
+	}
+	v.Operation().ApplyStarting(plan, 1)
+
+	var hook tofu.Hook
+	for _, h := range v.Hooks() {
+		if _, ok := h.(*jsonHook); ok {
+			hook = h
+		}
+	}
+	priorState := cty.NullVal(cty.Object(map[string]cty.Type{"id": cty.String}))
+	newState := cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("boop")})
+	if _, err := hook.PreApply(addr, states.CurrentGen, plans.Create, priorState, newState); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hook.PostApply(addr, states.CurrentGen, newState, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	wantResource := map[string]interface{}{
+		"addr":             "test_instance.foo",
+		"implied_provider": "test",
+		"module":           "",
+		"resource":         "test_instance.foo",
+		"resource_key":     nil,
+		"resource_name":    "foo",
+		"resource_type":    "test_instance",
+	}
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Applied 0 of 1 changes",
+			"@module":  "tofu.ui",
+			"type":     "apply_overall_progress",
+			"hook": map[string]interface{}{
+				"total":           float64(1),
+				"completed":       float64(0),
+				"errored":         float64(0),
+				"in_progress":     float64(0),
+				"elapsed_seconds": float64(0),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "test_instance.foo: Creating...",
+			"@module":  "tofu.ui",
+			"type":     "apply_start",
+			"hook": map[string]interface{}{
+				"action":   "create",
+				"resource": wantResource,
+				"retries":  float64(1),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "test_instance.foo: Creation complete after 0s [id=boop]",
+			"@module":  "tofu.ui",
+			"type":     "apply_complete",
+			"hook": map[string]interface{}{
+				"action":          "create",
+				"elapsed_seconds": float64(0),
+				"id_key":          "id",
+				"id_value":        "boop",
+				"resource":        wantResource,
+				"retries":         float64(1),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Applied 1 of 1 changes",
+			"@module":  "tofu.ui",
+			"type":     "apply_overall_progress",
+			"hook": map[string]interface{}{
+				"total":           float64(1),
+				"completed":       float64(1),
+				"errored":         float64(0),
+				"in_progress":     float64(0),
+				"elapsed_seconds": float64(0),
+				"eta_seconds":     float64(0),
+			},
+		},
+	}
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
@@ -44,6 +44,11 @@ type jsonHook struct {
 	// progress, and post-apply messages to share data about the resource
 	applying map[string]applyProgress
 
+	// progress, if set, follows the progress of the apply as a whole. It's
+	// shared with the OperationJSON view, which starts it once the plan to
+	// apply is known.
+	progress *applyProgressTracker
+
 	// Mockable functions for testing the progress timer goroutine
 	timeNow   func() time.Time
 	timeAfter func(time.Duration) <-chan time.Time
@@ -69,8 +74,12 @@ type applyProgress struct {
 
 func (h *jsonHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
 	if action != plans.NoOp {
+		var history json.ApplyHistory
+		if h.progress != nil {
+			history = h.progress.resourceStarted(addr)
+		}
 		idKey, idValue := format.ObjectValueIDOrName(priorState)
-		h.view.Hook(json.NewApplyStart(addr, action, idKey, idValue))
+		h.view.Hook(json.NewApplyStart(addr, action, idKey, idValue, history))
 	}
 
 	progress := applyProgress{
@@ -121,16 +130,26 @@ func (h *jsonHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generati
 		return tofu.HookActionContinue, nil
 	}
 
-	elapsed := h.timeNow().Round(time.Second).Sub(progress.start)
+	now := h.timeNow()
+	elapsed := now.Round(time.Second).Sub(progress.start)
 
+	var retries int
+	if h.progress != nil {
+		retries = h.progress.retryCount(addr)
+	}
 	if err != nil {
 		// Errors are collected and displayed post-apply, so no need to
 		// re-render them here. Instead just signal that this resource failed
 		// to apply.
-		h.view.Hook(json.NewApplyErrored(addr, progress.action, elapsed))
+		h.view.Hook(json.NewApplyErrored(addr, progress.action, elapsed, retries))
 	} else {
 		idKey, idValue := format.ObjectValueID(newState)
-		h.view.Hook(json.NewApplyComplete(addr, progress.action, idKey, idValue, elapsed))
+		h.view.Hook(json.NewApplyComplete(addr, progress.action, idKey, idValue, elapsed, retries))
+	}
+	if h.progress != nil {
+		if summary := h.progress.resourceFinished(addr, elapsed, err != nil, now); summary != nil {
+			h.view.Hook(summary)
+		}
 	}
 	return tofu.HookActionContinue, nil
 }
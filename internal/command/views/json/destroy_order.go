@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/jsonentities"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// DestroyOrder describes the order in which the planned destroy actions will
+// be applied, as waves of objects that only wait for the destruction of
+// objects in earlier waves.
+type DestroyOrder struct {
+	Waves [][]DestroyOrderObject `json:"waves"`
+}
+
+type DestroyOrderObject struct {
+	Resource jsonentities.ResourceAddr `json:"resource"`
+	Deposed  string                    `json:"deposed,omitempty"`
+}
+
+func NewDestroyOrder(waves [][]*plans.ResourceInstanceChangeSrc) *DestroyOrder {
+	ret := &DestroyOrder{
+		Waves: make([][]DestroyOrderObject, 0, len(waves)),
+	}
+	for _, wave := range waves {
+		objects := make([]DestroyOrderObject, 0, len(wave))
+		for _, change := range wave {
+			obj := DestroyOrderObject{
+				Resource: jsonentities.NewResourceAddr(change.Addr),
+			}
+			if change.DeposedKey != states.NotDeposed {
+				obj.Deposed = change.DeposedKey.String()
+			}
+			objects = append(objects, obj)
+		}
+		ret.Waves = append(ret.Waves, objects)
+	}
+	return ret
+}
+
+func (o *DestroyOrder) String() string {
+	var count int
+	for _, wave := range o.Waves {
+		count += len(wave)
+	}
+	return fmt.Sprintf("Destroy order: %d objects in %d waves", count, len(o.Waves))
+}
@@ -19,12 +19,27 @@ type Hook interface {
 	String() string
 }
 
+// ApplyHistory describes what is known about earlier attempts to apply a
+// resource instance change, when applying a plan.
+type ApplyHistory struct {
+	// Retries is the number of earlier attempts to apply the same saved plan
+	// that didn't complete this change.
+	Retries int
+
+	// Estimate is how long the change is expected to take, based on the
+	// durations recorded in the prior state, or zero if there's no basis
+	// for an estimate.
+	Estimate time.Duration
+}
+
 // ApplyStart: triggered by PreApply hook
 type applyStart struct {
 	Resource   jsonentities.ResourceAddr `json:"resource"`
 	Action     jsonentities.ChangeAction `json:"action"`
 	IDKey      string                    `json:"id_key,omitempty"`
 	IDValue    string                    `json:"id_value,omitempty"`
+	Retries    int                       `json:"retries,omitempty"`
+	Estimate   float64                   `json:"estimated_seconds,omitempty"`
 	actionVerb string
 }
 
@@ -42,12 +57,14 @@ func (h *applyStart) String() string {
 	return fmt.Sprintf("%s: %s...%s", h.Resource.Addr, h.actionVerb, id)
 }
 
-func NewApplyStart(addr addrs.AbsResourceInstance, action plans.Action, idKey string, idValue string) Hook {
+func NewApplyStart(addr addrs.AbsResourceInstance, action plans.Action, idKey string, idValue string, history ApplyHistory) Hook {
 	hook := &applyStart{
 		Resource:   jsonentities.NewResourceAddr(addr),
 		Action:     jsonentities.ParseChangeAction(action),
 		IDKey:      idKey,
 		IDValue:    idValue,
+		Retries:    history.Retries,
+		Estimate:   history.Estimate.Seconds(),
 		actionVerb: startActionVerb(action),
 	}
 
@@ -91,6 +108,7 @@ type applyComplete struct {
 	IDKey      string                    `json:"id_key,omitempty"`
 	IDValue    string                    `json:"id_value,omitempty"`
 	Elapsed    float64                   `json:"elapsed_seconds"`
+	Retries    int                       `json:"retries,omitempty"`
 	actionNoun string
 	elapsed    time.Duration
 }
@@ -109,13 +127,14 @@ func (h *applyComplete) String() string {
 	return fmt.Sprintf("%s: %s complete after %s%s", h.Resource.Addr, h.actionNoun, h.elapsed, id)
 }
 
-func NewApplyComplete(addr addrs.AbsResourceInstance, action plans.Action, idKey, idValue string, elapsed time.Duration) Hook {
+func NewApplyComplete(addr addrs.AbsResourceInstance, action plans.Action, idKey, idValue string, elapsed time.Duration, retries int) Hook {
 	return &applyComplete{
 		Resource:   jsonentities.NewResourceAddr(addr),
 		Action:     jsonentities.ParseChangeAction(action),
 		IDKey:      idKey,
 		IDValue:    idValue,
 		Elapsed:    elapsed.Seconds(),
+		Retries:    retries,
 		actionNoun: actionNoun(action),
 		elapsed:    elapsed,
 	}
@@ -127,6 +146,7 @@ type applyErrored struct {
 	Resource   jsonentities.ResourceAddr `json:"resource"`
 	Action     jsonentities.ChangeAction `json:"action"`
 	Elapsed    float64                   `json:"elapsed_seconds"`
+	Retries    int                       `json:"retries,omitempty"`
 	actionNoun string
 	elapsed    time.Duration
 }
@@ -141,16 +161,66 @@ func (h *applyErrored) String() string {
 	return fmt.Sprintf("%s: %s errored after %s", h.Resource.Addr, h.actionNoun, h.elapsed)
 }
 
-func NewApplyErrored(addr addrs.AbsResourceInstance, action plans.Action, elapsed time.Duration) Hook {
+func NewApplyErrored(addr addrs.AbsResourceInstance, action plans.Action, elapsed time.Duration, retries int) Hook {
 	return &applyErrored{
 		Resource:   jsonentities.NewResourceAddr(addr),
 		Action:     jsonentities.ParseChangeAction(action),
 		Elapsed:    elapsed.Seconds(),
+		Retries:    retries,
 		actionNoun: actionNoun(action),
 		elapsed:    elapsed,
 	}
 }
 
+// ApplyOverallProgress: triggered when an apply starts and each time a
+// resource instance change is completed or errors, summarizing the progress
+// of the apply as a whole.
+type applyOverallProgress struct {
+	Total      int      `json:"total"`
+	Completed  int      `json:"completed"`
+	Errored    int      `json:"errored"`
+	InProgress int      `json:"in_progress"`
+	Elapsed    float64  `json:"elapsed_seconds"`
+	ETA        *float64 `json:"eta_seconds,omitempty"`
+	eta        time.Duration
+}
+
+var _ Hook = (*applyOverallProgress)(nil)
+
+func (h *applyOverallProgress) HookType() MessageType {
+	return MessageApplyOverallProgress
+}
+
+func (h *applyOverallProgress) String() string {
+	msg := fmt.Sprintf("Applied %d of %d changes", h.Completed, h.Total)
+	if h.Errored > 0 {
+		msg += fmt.Sprintf(", %d errored", h.Errored)
+	}
+	if h.ETA != nil && h.Completed+h.Errored < h.Total {
+		msg += fmt.Sprintf(", about %s remaining", h.eta)
+	}
+	return msg
+}
+
+// NewApplyOverallProgress returns a summary of the progress of an apply. If
+// etaKnown is false then there's no basis yet for estimating how long the
+// remaining changes will take, and eta is ignored.
+func NewApplyOverallProgress(total, completed, errored, inProgress int, elapsed, eta time.Duration, etaKnown bool) Hook {
+	ret := &applyOverallProgress{
+		Total:      total,
+		Completed:  completed,
+		Errored:    errored,
+		InProgress: inProgress,
+		Elapsed:    elapsed.Seconds(),
+	}
+	if etaKnown {
+		seconds := eta.Seconds()
+		ret.ETA = &seconds
+		ret.eta = eta
+	}
+	return ret
+}
+
 // ProvisionStart: triggered by PreProvisionInstanceStep hook
 type provisionStart struct {
 	Resource    jsonentities.ResourceAddr `json:"resource"`
@@ -18,18 +18,20 @@ const (
 	MessagePlannedChange MessageType = "planned_change"
 	MessageChangeSummary MessageType = "change_summary"
 	MessageOutputs       MessageType = "outputs"
+	MessageDestroyOrder  MessageType = "destroy_order"
 
 	// Hook-driven messages
-	MessageApplyStart        MessageType = "apply_start"
-	MessageApplyProgress     MessageType = "apply_progress"
-	MessageApplyComplete     MessageType = "apply_complete"
-	MessageApplyErrored      MessageType = "apply_errored"
-	MessageProvisionStart    MessageType = "provision_start"
-	MessageProvisionProgress MessageType = "provision_progress"
-	MessageProvisionComplete MessageType = "provision_complete"
-	MessageProvisionErrored  MessageType = "provision_errored"
-	MessageRefreshStart      MessageType = "refresh_start"
-	MessageRefreshComplete   MessageType = "refresh_complete"
+	MessageApplyStart           MessageType = "apply_start"
+	MessageApplyProgress        MessageType = "apply_progress"
+	MessageApplyComplete        MessageType = "apply_complete"
+	MessageApplyErrored         MessageType = "apply_errored"
+	MessageApplyOverallProgress MessageType = "apply_overall_progress"
+	MessageProvisionStart       MessageType = "provision_start"
+	MessageProvisionProgress    MessageType = "provision_progress"
+	MessageProvisionComplete    MessageType = "provision_complete"
+	MessageProvisionErrored     MessageType = "provision_errored"
+	MessageRefreshStart         MessageType = "refresh_start"
+	MessageRefreshComplete      MessageType = "refresh_complete"
 
 	// Test messages
 	MessageTestAbstract  MessageType = "test_abstract"
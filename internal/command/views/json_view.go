@@ -20,7 +20,7 @@ import (
 // This version describes the schema of JSON UI messages. This version must be
 // updated after making any changes to this view, the jsonHook, or any of the
 // command/views/json package.
-const JSON_UI_VERSION = "1.2"
+const JSON_UI_VERSION = "1.3"
 
 func NewJSONView(view *View) *JSONView {
 	log := hclog.New(&hclog.LoggerOptions{
@@ -75,6 +75,7 @@ func (v *JSONView) StateDump(state string) {
 }
 
 func (v *JSONView) Diagnostics(diags tfdiags.Diagnostics, metadata ...interface{}) {
+	v.view.RecordErrorCode(diags)
 	sources := v.view.configSources()
 	for _, diag := range diags {
 		diagnostic := jsonentities.NewDiagnostic(diag, sources)
@@ -115,6 +116,14 @@ func (v *JSONView) ChangeSummary(cs *json.ChangeSummary) {
 	)
 }
 
+func (v *JSONView) DestroyOrder(o *json.DestroyOrder) {
+	v.log.Info(
+		o.String(),
+		"type", json.MessageDestroyOrder,
+		"destroy_order", o,
+	)
+}
+
 func (v *JSONView) Hook(h json.Hook) {
 	v.log.Info(
 		h.String(),
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -20,6 +21,7 @@ import (
 	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -37,6 +39,16 @@ type Operation interface {
 	Plan(plan *plans.Plan, schemas *tofu.Schemas)
 	PlanNextStep(planPath string, genConfigPath string)
 
+	// DestroyOrder shows the order in which the destroy actions in a plan
+	// will be applied, as waves of objects that only wait for the
+	// destruction of objects in earlier waves.
+	DestroyOrder(waves [][]*plans.ResourceInstanceChangeSrc)
+
+	// ApplyStarting is called just before the changes in the given plan are
+	// applied. Retries is the number of earlier attempts to apply the same
+	// saved plan that didn't complete its remaining changes.
+	ApplyStarting(plan *plans.Plan, retries int)
+
 	Diagnostics(diags tfdiags.Diagnostics)
 }
 
@@ -101,6 +113,11 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
 		return
 	}
+	provisioners, err := jsonplan.MarshalProvisionerPreviews(plan.Changes)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return
+	}
 
 	renderer := jsonformat.Renderer{
 		Colorize:            v.view.colorize,
@@ -117,6 +134,8 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		ResourceDrift:         drift,
 		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 		RelevantAttributes:    attrs,
+		CostEstimate:          jsonplan.MarshalCostEstimate(plan.CostEstimate),
+		ProvisionerPreviews:   provisioners,
 	}
 
 	// Side load some data that we can't extract from the JSON plan.
@@ -172,12 +191,47 @@ func (v *OperationHuman) PlanNextStep(planPath string, genConfigPath string) {
 	}
 }
 
+func (v *OperationHuman) DestroyOrder(waves [][]*plans.ResourceInstanceChangeSrc) {
+	if len(waves) == 0 {
+		v.view.streams.Println(format.WordWrap(
+			"\nThe plan doesn't destroy any objects.",
+			v.view.outputColumns(),
+		))
+		return
+	}
+
+	v.view.streams.Println(v.view.colorize.Color("\n[bold]Destroy order:[reset]"))
+	for i, wave := range waves {
+		v.view.streams.Printf("\n  Wave %d:\n", i+1)
+		for _, change := range wave {
+			if change.DeposedKey != states.NotDeposed {
+				v.view.streams.Printf("    - %s (deposed object %s)\n", change.Addr, change.DeposedKey)
+				continue
+			}
+			v.view.streams.Printf("    - %s\n", change.Addr)
+		}
+	}
+	v.view.streams.Println(format.WordWrap(
+		"\nEach object is destroyed only after the objects in earlier waves that depend on it. Objects in the same wave may be destroyed concurrently.",
+		v.view.outputColumns(),
+	))
+}
+
+// ApplyStarting does nothing for the human view, which reports the progress of
+// each change as it happens through its hooks.
+func (v *OperationHuman) ApplyStarting(plan *plans.Plan, retries int) {
+}
+
 func (v *OperationHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
 type OperationJSON struct {
 	view *JSONView
+
+	// progress, if set, is shared with the JSON hook so that it can report
+	// the progress of applying the plan as a whole.
+	progress *applyProgressTracker
 }
 
 var _ Operation = (*OperationJSON)(nil)
@@ -285,6 +339,19 @@ func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 func (v *OperationJSON) PlanNextStep(planPath string, genConfigPath string) {
 }
 
+func (v *OperationJSON) DestroyOrder(waves [][]*plans.ResourceInstanceChangeSrc) {
+	v.view.DestroyOrder(viewsjson.NewDestroyOrder(waves))
+}
+
+// ApplyStarting logs a summary of the changes that are about to be applied,
+// which is then updated each time one of those changes finishes.
+func (v *OperationJSON) ApplyStarting(plan *plans.Plan, retries int) {
+	if v.progress == nil {
+		return
+	}
+	v.view.Hook(v.progress.start(plan, retries, time.Now()))
+}
+
 func (v *OperationJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -1337,3 +1337,67 @@ func TestOperationJSON_plannedChange(t *testing.T) {
 
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
+
+func TestOperationJSON_destroyOrder(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	root := addrs.RootModuleInstance
+	boop := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "boop"}
+	beep := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "beep"}
+
+	v.DestroyOrder([][]*plans.ResourceInstanceChangeSrc{
+		{
+			{
+				Addr:        boop.Instance(addrs.NoKey).Absolute(root),
+				PrevRunAddr: boop.Instance(addrs.NoKey).Absolute(root),
+				DeposedKey:  states.DeposedKey("00000001"),
+				ChangeSrc:   plans.ChangeSrc{Action: plans.Delete},
+			},
+		},
+		{
+			{
+				Addr:        beep.Instance(addrs.NoKey).Absolute(root),
+				PrevRunAddr: beep.Instance(addrs.NoKey).Absolute(root),
+				ChangeSrc:   plans.ChangeSrc{Action: plans.Delete},
+			},
+		},
+	})
+
+	resource := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"addr":             "test_instance." + name,
+			"implied_provider": "test",
+			"module":           "",
+			"resource":         "test_instance." + name,
+			"resource_key":     nil,
+			"resource_name":    name,
+			"resource_type":    "test_instance",
+		}
+	}
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Destroy order: 2 objects in 2 waves",
+			"@module":  "tofu.ui",
+			"type":     "destroy_order",
+			"destroy_order": map[string]interface{}{
+				"waves": []interface{}{
+					[]interface{}{
+						map[string]interface{}{
+							"resource": resource("boop"),
+							"deposed":  "00000001",
+						},
+					},
+					[]interface{}{
+						map[string]interface{}{
+							"resource": resource("beep"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
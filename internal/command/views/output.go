@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strings"
 
+	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -38,6 +39,12 @@ func NewOutput(vt arguments.ViewType, view *View) Output {
 		return &OutputRaw{view: view}
 	case arguments.ViewHuman:
 		return &OutputHuman{view: view}
+	case arguments.ViewDotenv:
+		return &OutputEnv{view: view, quote: dotenvQuote}
+	case arguments.ViewShell:
+		return &OutputEnv{view: view, quote: shellQuote, prefix: "export "}
+	case arguments.ViewYAML:
+		return &OutputYAML{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -261,6 +268,197 @@ func (v *OutputJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+// The OutputEnv implementation renders outputs as environment variable
+// assignments, either in a dotenv file or as shell commands. Strings, numbers,
+// and booleans are rendered as in raw mode, null values as empty strings, and
+// values of any other type as JSON.
+//
+// Unlike the other formats, sensitive values are omitted unless the
+// -show-sensitive option is used, because the result is usually written to a
+// file or evaluated by a shell rather than shown to the user.
+type OutputEnv struct {
+	view   *View
+	quote  func(string) string
+	prefix string
+}
+
+var _ Output = (*OutputEnv)(nil)
+
+func (v *OutputEnv) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectPortableOutputs(name, outputs, v.view.showSensitive)
+	if diags.HasErrors() || len(names) == 0 {
+		return diags
+	}
+
+	// Output names can contain dashes, which aren't valid in environment
+	// variable names, so we must check that replacing them doesn't make
+	// two names the same.
+	varNames := make(map[string]string, len(names))
+	var buf strings.Builder
+	for _, n := range names {
+		varName := strings.ReplaceAll(n, "-", "_")
+		if other, exists := varNames[varName]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Conflicting variable names",
+				fmt.Sprintf("The output values %q and %q would both be written to the variable %s.", other, n, varName),
+			))
+			return diags
+		}
+		varNames[varName] = n
+
+		value, err := envOutputValue(outputs[n].Value)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unsupported output value",
+				fmt.Sprintf("Can't render output value %q as a variable: %s.", n, err),
+			))
+			return diags
+		}
+		fmt.Fprintf(&buf, "%s%s=%s\n", v.prefix, varName, v.quote(value))
+	}
+	v.view.streams.Print(buf.String())
+	return diags
+}
+
+func (v *OutputEnv) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func envOutputValue(val cty.Value) (string, error) {
+	if !val.IsWhollyKnown() {
+		// Values from the state are always known, so this is just a
+		// safeguard.
+		return "", fmt.Errorf("the value won't be known until after a successful tofu apply")
+	}
+	if val.IsNull() {
+		return "", nil
+	}
+	if strV, err := convert.Convert(val, cty.String); err == nil {
+		return strV.AsString(), nil
+	}
+	src, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return "", err
+	}
+	return string(src), nil
+}
+
+// dotenvQuote quotes a value for a dotenv file. Single-quoted values are
+// taken literally by all common dotenv parsers, so we use double quotes only
+// when the value contains characters that can't be single-quoted.
+func dotenvQuote(s string) string {
+	if !strings.ContainsAny(s, "'\n\r") {
+		return "'" + s + "'"
+	}
+	return `"` + dotenvEscaper.Replace(s) + `"`
+}
+
+var dotenvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`$`, `\$`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// shellQuote quotes a value for a POSIX shell, where single-quoted strings
+// can contain anything other than a single quote.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// The OutputYAML implementation renders either a single output value as a
+// YAML document, or all outputs as a mapping from their names to their
+// values. Sensitive values are omitted unless the -show-sensitive option is
+// used, as for OutputEnv.
+type OutputYAML struct {
+	view *View
+}
+
+var _ Output = (*OutputYAML)(nil)
+
+func (v *OutputYAML) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectPortableOutputs(name, outputs, v.view.showSensitive)
+	if diags.HasErrors() || len(names) == 0 {
+		return diags
+	}
+
+	var val cty.Value
+	if name != "" {
+		val = outputs[name].Value
+	} else {
+		attrs := make(map[string]cty.Value, len(names))
+		for _, n := range names {
+			attrs[n] = outputs[n].Value
+		}
+		val = cty.ObjectVal(attrs)
+	}
+
+	src, err := ctyyaml.Marshal(val)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported output value",
+			fmt.Sprintf("Can't render the output values as YAML: %s.", err),
+		))
+		return diags
+	}
+	v.view.streams.Print(string(src))
+	return diags
+}
+
+func (v *OutputYAML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// selectPortableOutputs returns the names of the outputs to render in the
+// formats meant for other tools, in order. It leaves out sensitive outputs
+// unless showSensitive is set, with a warning, or returns an error if the
+// single output requested is sensitive.
+func selectPortableOutputs(name string, outputs map[string]*states.OutputValue, showSensitive bool) ([]string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if len(outputs) == 0 {
+		return nil, diags.Append(noOutputsWarning())
+	}
+
+	if name != "" {
+		output, ok := outputs[name]
+		if !ok {
+			return nil, diags.Append(missingOutputError(name))
+		}
+		if output.Sensitive && !showSensitive {
+			return nil, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Output value is sensitive",
+				fmt.Sprintf("The output value %q is sensitive. Use the -show-sensitive option to include its value.", name),
+			))
+		}
+		return []string{name}, diags
+	}
+
+	var names, omitted []string
+	for n, output := range outputs {
+		if output.Sensitive && !showSensitive {
+			omitted = append(omitted, n)
+			continue
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(omitted) != 0 {
+		sort.Strings(omitted)
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Sensitive output values omitted",
+			fmt.Sprintf("The following output values are sensitive and were left out: %s. Use the -show-sensitive option to include them.", strings.Join(omitted, ", ")),
+		))
+	}
+	return names, diags
+}
+
 // For text and raw output modes, an empty map of outputs is considered a
 // separate and higher priority failure mode than an output not being present
 // in a non-empty map. This warning diagnostic explains how this might have
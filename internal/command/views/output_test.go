@@ -377,3 +377,155 @@ func TestOutput_missing(t *testing.T) {
 		})
 	}
 }
+
+// The dotenv, shell, and YAML formats are meant for other tools, so they
+// quote values as needed and leave out sensitive values unless asked to
+// show them.
+func TestOutput_portable(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo": {
+			Value:     cty.StringVal("secret"),
+			Sensitive: true,
+		},
+		"bar": {
+			Value: cty.ListVal([]cty.Value{cty.True, cty.False}),
+		},
+		"quoted-value": {
+			Value: cty.StringVal("it's \"$HOME\"\nand more"),
+		},
+		"number": {
+			Value: cty.NumberFloatVal(1.5),
+		},
+		"nothing": {
+			Value: cty.NullVal(cty.String),
+		},
+	}
+
+	testCases := map[string]struct {
+		vt            arguments.ViewType
+		showSensitive bool
+		want          string
+	}{
+		"dotenv": {
+			vt: arguments.ViewDotenv,
+			want: `bar='[true,false]'
+nothing=''
+number='1.5'
+quoted_value="it's \"\$HOME\"\nand more"
+`,
+		},
+		"sh": {
+			vt: arguments.ViewShell,
+			want: `export bar='[true,false]'
+export nothing=''
+export number='1.5'
+export quoted_value='it'\''s "$HOME"
+and more'
+`,
+		},
+		"sh with sensitive": {
+			vt:            arguments.ViewShell,
+			showSensitive: true,
+			want: `export bar='[true,false]'
+export foo='secret'
+export nothing=''
+export number='1.5'
+export quoted_value='it'\''s "$HOME"
+and more'
+`,
+		},
+		"yaml": {
+			vt: arguments.ViewYAML,
+			want: `"bar":
+- true
+- false
+"nothing": null
+"number": 1.5
+"quoted-value": |-
+  it's "$HOME"
+  and more
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			view := NewView(streams)
+			view.SetShowSensitive(tc.showSensitive)
+			v := NewOutput(tc.vt, view)
+			diags := v.Output("", outputs)
+
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+			if tc.showSensitive {
+				if len(diags) != 0 {
+					t.Errorf("unexpected diagnostics: %s", diags)
+				}
+			} else if len(diags) != 1 || diags[0].Description().Summary != "Sensitive output values omitted" {
+				t.Errorf("expected a warning about the sensitive value, got: %s", diags)
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutput_portableSingle(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo": {
+			Value: cty.StringVal("hello"),
+		},
+		"secret": {
+			Value:     cty.StringVal("hunter2"),
+			Sensitive: true,
+		},
+	}
+
+	testCases := map[string]struct {
+		vt   arguments.ViewType
+		want string
+	}{
+		"dotenv": {arguments.ViewDotenv, "foo='hello'\n"},
+		"sh":     {arguments.ViewShell, "export foo='hello'\n"},
+		"yaml":   {arguments.ViewYAML, "\"hello\"\n"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := NewOutput(tc.vt, NewView(streams))
+
+			if diags := v.Output("foo", outputs); len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+			diags := v.Output("secret", outputs)
+			if !diags.HasErrors() || diags.Err().Error() != `Output value is sensitive: The output value "secret" is sensitive. Use the -show-sensitive option to include its value.` {
+				t.Errorf("wrong diagnostics for sensitive output: %s", diags.Err())
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutputEnv_conflictingNames(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutput(arguments.ViewDotenv, NewView(streams))
+
+	diags := v.Output("", map[string]*states.OutputValue{
+		"a-b": {Value: cty.StringVal("dash")},
+		"a_b": {Value: cty.StringVal("underscore")},
+	})
+	if !diags.HasErrors() || diags[0].Description().Summary != "Conflicting variable names" {
+		t.Errorf("expected an error about conflicting names, got: %s", diags)
+	}
+	if got := done(t).Stdout(); got != "" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
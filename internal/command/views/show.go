@@ -10,6 +10,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -17,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonformat"
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/command/jsonsarif"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -39,6 +42,11 @@ type Show interface {
 	// preferring planJSON if it is not nil and using plan otherwise.
 	DisplayPlan(ctx context.Context, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, config *configs.Config, priorStateFile *statefile.File, schemas *tofu.Schemas) int
 
+	// DisplayPlanMetadata renders where a saved plan came from and whether it
+	// can still be applied, without the changes it proposes, returning a
+	// status code for "tofu show" to return.
+	DisplayPlanMetadata(meta *jsonplan.Metadata) int
+
 	// DisplayConfig renders the given configuration, returning a status code for "tofu show" to return.
 	DisplayConfig(config *configs.Config, schemas *tofu.Schemas) int
 
@@ -57,6 +65,12 @@ func NewShow(vt arguments.ViewType, view *View) Show {
 		return &ShowJSON{view: view}
 	case arguments.ViewHuman:
 		return &ShowHuman{view: view}
+	case arguments.ViewSARIF:
+		return &ShowSARIF{view: view}
+	case arguments.ViewMarkdown:
+		return NewShowMarkdown(view, jsonformat.MarkdownOpts{})
+	case arguments.ViewHTML:
+		return &ShowHTML{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -139,6 +153,7 @@ func (v *ShowHuman) DisplayPlan(_ context.Context, plan *plans.Plan, planJSON *c
 			ResourceDrift:         drift,
 			ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 			RelevantAttributes:    attrs,
+			CostEstimate:          jsonplan.MarshalCostEstimate(plan.CostEstimate),
 		}
 
 		var opts []plans.Quality
@@ -156,6 +171,47 @@ func (v *ShowHuman) DisplayPlan(_ context.Context, plan *plans.Plan, planJSON *c
 	return 0
 }
 
+func (v *ShowHuman) DisplayPlanMetadata(meta *jsonplan.Metadata) int {
+	var buf strings.Builder
+	row := func(label, value string) {
+		fmt.Fprintf(&buf, "%-15s %s\n", label+":", value)
+	}
+	row("Created", meta.Timestamp)
+	switch {
+	case meta.ExpiresAt == "":
+		row("Expires", "never")
+	case meta.Expired:
+		row("Expires", meta.ExpiresAt+" [bold][red](expired)[reset]")
+	default:
+		row("Expires", meta.ExpiresAt)
+	}
+	row("Mode", meta.Mode)
+	row("Backend", meta.Backend.Type)
+	row("Workspace", meta.Backend.Workspace)
+	if meta.PriorState.Lineage != "" {
+		row("State lineage", meta.PriorState.Lineage)
+	}
+	row("State serial", strconv.FormatUint(meta.PriorState.Serial, 10))
+	if len(meta.Targets) > 0 {
+		row("Targets", strings.Join(meta.Targets, ", "))
+	}
+	if len(meta.Excludes) > 0 {
+		row("Excludes", strings.Join(meta.Excludes, ", "))
+	}
+	switch {
+	case meta.Errored:
+		row("Status", "errored; this plan is incomplete and can't be applied")
+	case meta.Expired:
+		row("Status", "expired; create a new plan to apply these changes")
+	case !meta.Applyable:
+		row("Status", "no changes to apply")
+	default:
+		row("Status", "ready to apply")
+	}
+	v.view.streams.Print(v.view.colorize.Color(buf.String()))
+	return 0
+}
+
 func (v *ShowHuman) DisplayConfig(config *configs.Config, schemas *tofu.Schemas) int {
 	// The human view should never be called for configuration display
 	// since we require -json for -config
@@ -216,6 +272,16 @@ func (v *ShowJSON) DisplayPlan(_ context.Context, plan *plans.Plan, planJSON *cl
 	return 0
 }
 
+func (v *ShowJSON) DisplayPlanMetadata(meta *jsonplan.Metadata) int {
+	metaJSON, err := jsonplan.MarshalMetadata(meta)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan metadata to JSON: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(metaJSON))
+	return 0
+}
+
 func (v *ShowJSON) DisplayConfig(config *configs.Config, schemas *tofu.Schemas) int {
 	configJSON, err := jsonconfig.Marshal(config, schemas)
 	if err != nil {
@@ -242,3 +308,229 @@ func (v *ShowJSON) DisplaySingleModule(module *configs.Module) int {
 func (v *ShowJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
+
+// ShowSARIF renders a saved plan as a SARIF report for code scanning tools.
+// It can't render anything other than a locally-created plan.
+type ShowSARIF struct {
+	view *View
+}
+
+var _ Show = (*ShowSARIF)(nil)
+
+func (v *ShowSARIF) DisplayState(_ context.Context, _ *statefile.File, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("SARIF output is only available for saved plan files, not for state.\n")
+	return 1
+}
+
+func (v *ShowSARIF) DisplayPlan(_ context.Context, plan *plans.Plan, _ *cloudplan.RemotePlanJSON, config *configs.Config, _ *statefile.File, schemas *tofu.Schemas) int {
+	if plan == nil {
+		v.view.streams.Eprintf("SARIF output is not available for plans created by remote runs.\n")
+		return 1
+	}
+	report, err := jsonsarif.Marshal(plan, config, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to SARIF: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(report))
+	return 0
+}
+
+func (v *ShowSARIF) DisplayPlanMetadata(_ *jsonplan.Metadata) int {
+	v.view.streams.Eprintf("Internal error: SARIF view should not be used for plan metadata display")
+	return 1
+}
+
+func (v *ShowSARIF) DisplayConfig(_ *configs.Config, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("Internal error: SARIF view should not be used for configuration display")
+	return 1
+}
+
+func (v *ShowSARIF) DisplaySingleModule(_ *configs.Module) int {
+	v.view.streams.Eprintf("Internal error: SARIF view should not be used for module display")
+	return 1
+}
+
+// Diagnostics renders human-readable diagnostics, as in [ShowJSON.Diagnostics].
+func (v *ShowSARIF) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// ShowMarkdown renders a saved plan as a Markdown document, for posting as a
+// comment on a pull request.
+type ShowMarkdown struct {
+	view *View
+	opts jsonformat.MarkdownOpts
+}
+
+var _ Show = (*ShowMarkdown)(nil)
+
+// NewShowMarkdown returns a Markdown view for "tofu show" which limits the
+// size of its output using the given options.
+func NewShowMarkdown(view *View, opts jsonformat.MarkdownOpts) *ShowMarkdown {
+	return &ShowMarkdown{view: view, opts: opts}
+}
+
+func (v *ShowMarkdown) DisplayState(_ context.Context, _ *statefile.File, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("Markdown output is only available for saved plan files, not for state.\n")
+	return 1
+}
+
+func (v *ShowMarkdown) DisplayPlan(_ context.Context, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, _ *configs.Config, _ *statefile.File, schemas *tofu.Schemas) int {
+	renderer := jsonformat.Renderer{
+		Streams:       v.view.streams,
+		ShowSensitive: v.view.showSensitive,
+	}
+
+	if planJSON != nil {
+		if !planJSON.Redacted {
+			v.view.streams.Eprintf("Didn't get renderable JSON plan format for Markdown display")
+			return 1
+		}
+		p := jsonformat.Plan{}
+		r := bytes.NewReader(planJSON.JSONBytes)
+		if err := json.NewDecoder(r).Decode(&p); err != nil {
+			v.view.streams.Eprintf("Couldn't decode renderable JSON plan format: %s", err)
+			return 1
+		}
+		renderer.RenderMarkdownPlan(p, planJSON.Mode, v.opts, planJSON.Qualities...)
+		return 0
+	}
+	if plan == nil {
+		v.view.streams.Eprintf("No plan.\n")
+		return 1
+	}
+
+	outputs, changed, drift, attrs, err := jsonplan.MarshalForRenderer(plan, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return 1
+	}
+	jplan := jsonformat.Plan{
+		PlanFormatVersion:     jsonplan.FormatVersion,
+		ProviderFormatVersion: jsonprovider.FormatVersion,
+		OutputChanges:         outputs,
+		ResourceChanges:       changed,
+		ResourceDrift:         drift,
+		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
+		RelevantAttributes:    attrs,
+	}
+
+	var opts []plans.Quality
+	if plan.Errored {
+		opts = append(opts, plans.Errored)
+	}
+	renderer.RenderMarkdownPlan(jplan, plan.UIMode, v.opts, opts...)
+	return 0
+}
+
+func (v *ShowMarkdown) DisplayPlanMetadata(_ *jsonplan.Metadata) int {
+	v.view.streams.Eprintf("Internal error: Markdown view should not be used for plan metadata display")
+	return 1
+}
+
+func (v *ShowMarkdown) DisplayConfig(_ *configs.Config, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("Internal error: Markdown view should not be used for configuration display")
+	return 1
+}
+
+func (v *ShowMarkdown) DisplaySingleModule(_ *configs.Module) int {
+	v.view.streams.Eprintf("Internal error: Markdown view should not be used for module display")
+	return 1
+}
+
+// Diagnostics renders human-readable diagnostics, as in [ShowJSON.Diagnostics].
+func (v *ShowMarkdown) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// ShowHTML renders a saved plan as a self-contained HTML report.
+type ShowHTML struct {
+	view *View
+}
+
+var _ Show = (*ShowHTML)(nil)
+
+func (v *ShowHTML) DisplayState(_ context.Context, _ *statefile.File, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("HTML output is only available for saved plan files, not for state.\n")
+	return 1
+}
+
+func (v *ShowHTML) DisplayPlan(_ context.Context, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, config *configs.Config, _ *statefile.File, schemas *tofu.Schemas) int {
+	renderer := jsonformat.Renderer{
+		Streams:       v.view.streams,
+		ShowSensitive: v.view.showSensitive,
+	}
+
+	if planJSON != nil {
+		if !planJSON.Redacted {
+			v.view.streams.Eprintf("Didn't get renderable JSON plan format for HTML display")
+			return 1
+		}
+		p := jsonformat.Plan{}
+		r := bytes.NewReader(planJSON.JSONBytes)
+		if err := json.NewDecoder(r).Decode(&p); err != nil {
+			v.view.streams.Eprintf("Couldn't decode renderable JSON plan format: %s", err)
+			return 1
+		}
+		// Remote plans don't come with their configuration, so there's no
+		// dependency graph.
+		if err := renderer.RenderHTMLPlan(p, planJSON.Mode, jsonformat.HTMLOpts{}, planJSON.Qualities...); err != nil {
+			v.view.streams.Eprintf("Failed to render HTML report: %s", err)
+			return 1
+		}
+		return 0
+	}
+	if plan == nil {
+		v.view.streams.Eprintf("No plan.\n")
+		return 1
+	}
+
+	outputs, changed, drift, attrs, err := jsonplan.MarshalForRenderer(plan, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return 1
+	}
+	jplan := jsonformat.Plan{
+		PlanFormatVersion:     jsonplan.FormatVersion,
+		ProviderFormatVersion: jsonprovider.FormatVersion,
+		OutputChanges:         outputs,
+		ResourceChanges:       changed,
+		ResourceDrift:         drift,
+		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
+		RelevantAttributes:    attrs,
+	}
+
+	var opts []plans.Quality
+	if plan.Errored {
+		opts = append(opts, plans.Errored)
+	}
+	htmlOpts := jsonformat.HTMLOpts{
+		Dependencies: jsonplan.ResourceDependencies(config, schemas),
+	}
+	if err := renderer.RenderHTMLPlan(jplan, plan.UIMode, htmlOpts, opts...); err != nil {
+		v.view.streams.Eprintf("Failed to render HTML report: %s", err)
+		return 1
+	}
+	return 0
+}
+
+func (v *ShowHTML) DisplayPlanMetadata(_ *jsonplan.Metadata) int {
+	v.view.streams.Eprintf("Internal error: HTML view should not be used for plan metadata display")
+	return 1
+}
+
+func (v *ShowHTML) DisplayConfig(_ *configs.Config, _ *tofu.Schemas) int {
+	v.view.streams.Eprintf("Internal error: HTML view should not be used for configuration display")
+	return 1
+}
+
+func (v *ShowHTML) DisplaySingleModule(_ *configs.Module) int {
+	v.view.streams.Eprintf("Internal error: HTML view should not be used for module display")
+	return 1
+}
+
+// Diagnostics renders human-readable diagnostics, as in [ShowJSON.Diagnostics].
+func (v *ShowHTML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
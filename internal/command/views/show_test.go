@@ -332,3 +332,117 @@ func testState() *states.State {
 		// operation, and all fields have been copied correctly.
 	}).DeepCopy()
 }
+
+func TestShowSARIF(t *testing.T) {
+	t.Run("plan", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewShow(arguments.ViewSARIF, NewView(streams))
+
+		code := v.DisplayPlan(t.Context(), &plans.Plan{Changes: plans.NewChanges()}, nil, nil, nil, nil)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("expected 0 return code, got %d\n%s", code, output.Stderr())
+		}
+		var report struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal([]byte(output.Stdout()), &report); err != nil {
+			t.Fatalf("output is not valid JSON: %s\n%s", err, output.Stdout())
+		}
+		if report.Version != "2.1.0" {
+			t.Errorf("wrong SARIF version %q", report.Version)
+		}
+	})
+	t.Run("state", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewShow(arguments.ViewSARIF, NewView(streams))
+
+		code := v.DisplayState(t.Context(), nil, nil)
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("expected 1 return code, got %d", code)
+		}
+		if got, want := output.Stderr(), "SARIF output is only available for saved plan files"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\n got: %s\nwant: %s", got, want)
+		}
+	})
+}
+
+func TestShowMarkdown(t *testing.T) {
+	redactedPath := "./testdata/plans/redacted-plan.json"
+	redactedPlanJson, err := os.ReadFile(redactedPath)
+	if err != nil {
+		t.Fatalf("couldn't read json plan test data at %s for showing a cloud plan. Did the file get moved?", redactedPath)
+	}
+	testCases := map[string]struct {
+		plan       *plans.Plan
+		jsonPlan   *cloudplan.RemotePlanJSON
+		schemas    *tofu.Schemas
+		wantString string
+	}{
+		"plan file": {
+			testPlan(t),
+			nil,
+			testSchemas(),
+			"<details><summary>test_resource.foo will be created</summary>",
+		},
+		"cloud plan file": {
+			nil,
+			&cloudplan.RemotePlanJSON{
+				JSONBytes: redactedPlanJson,
+				Redacted:  true,
+				Mode:      plans.NormalMode,
+			},
+			nil,
+			"<details><summary>null_resource.foo will be created</summary>",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := NewShow(arguments.ViewMarkdown, NewView(streams))
+
+			code := v.DisplayPlan(t.Context(), testCase.plan, testCase.jsonPlan, nil, nil, testCase.schemas)
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("expected 0 return code, got %d\n%s", code, output.Stderr())
+			}
+			if got := output.Stdout(); !strings.Contains(got, testCase.wantString) {
+				t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, testCase.wantString)
+			}
+		})
+	}
+	t.Run("state", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewShow(arguments.ViewMarkdown, NewView(streams))
+
+		code := v.DisplayState(t.Context(), nil, nil)
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("expected 1 return code, got %d", code)
+		}
+		if got, want := output.Stderr(), "Markdown output is only available for saved plan files"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\n got: %s\nwant: %s", got, want)
+		}
+	})
+}
+
+func TestShowHTML(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewShow(arguments.ViewHTML, NewView(streams))
+
+	code := v.DisplayPlan(t.Context(), testPlan(t), nil, nil, nil, testSchemas())
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected 0 return code, got %d\n%s", code, output.Stderr())
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`data-resource="test_resource.foo"`,
+		"test_resource.foo will be created",
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
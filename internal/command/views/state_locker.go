@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
 // The StateLocker view is used to display locking/unlocking status messages
@@ -18,6 +19,10 @@ import (
 type StateLocker interface {
 	Locking()
 	Unlocking()
+
+	// LockWaiting reports that the state lock is held by another process
+	// and that locking will be retried, as allowed by -lock-timeout.
+	LockWaiting(status statemgr.LockWaitStatus)
 }
 
 // NewStateLocker returns an initialized StateLocker implementation for the given ViewType.
@@ -49,6 +54,20 @@ func (v *StateLockerHuman) Unlocking() {
 	v.view.streams.Println("Releasing state lock. This may take a few moments...")
 }
 
+func (v *StateLockerHuman) LockWaiting(status statemgr.LockWaitStatus) {
+	msg := fmt.Sprintf("State lock is held by another process; retrying in %s (waited %s so far).", status.NextAttempt, status.Waited.Round(time.Second))
+	if h := status.Holder; h != nil {
+		msg += fmt.Sprintf("\n  Lock ID:   %s\n  Held by:   %s\n  Operation: %s\n  Since:     %s", h.ID, h.Who, h.Operation, h.Created.Format(time.RFC3339))
+		if run := h.Run; run != nil && run.JobURL != "" {
+			msg += fmt.Sprintf("\n  CI Job:    %s", run.JobURL)
+		}
+	}
+	if status.Waiters > 0 {
+		msg += fmt.Sprintf("\n  %d other process(es) are also waiting for this lock.", status.Waiters)
+	}
+	v.view.streams.Println(msg)
+}
+
 // StateLockerJSON is an implementation of StateLocker which prints the state lock status
 // to a terminal in machine-readable JSON form.
 type StateLockerJSON struct {
@@ -82,3 +101,27 @@ func (v *StateLockerJSON) Unlocking() {
 	lock_info_message, _ := json.Marshal(json_data)
 	v.view.streams.Println(string(lock_info_message))
 }
+
+func (v *StateLockerJSON) LockWaiting(status statemgr.LockWaitStatus) {
+	current_timestamp := time.Now().Format(time.RFC3339)
+
+	json_data := map[string]interface{}{
+		"@level":       "info",
+		"@message":     "State lock is held by another process; waiting to retry...",
+		"@module":      "tofu.ui",
+		"@timestamp":   current_timestamp,
+		"type":         "state_lock_wait",
+		"attempt":      status.Attempt,
+		"waited":       status.Waited.Seconds(),
+		"next_attempt": status.NextAttempt.Seconds(),
+	}
+	if status.Waiters >= 0 {
+		json_data["waiters"] = status.Waiters
+	}
+	if status.Holder != nil {
+		json_data["holder"] = status.Holder
+	}
+
+	lock_info_message, _ := json.Marshal(json_data)
+	v.view.streams.Println(string(lock_info_message))
+}
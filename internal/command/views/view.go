@@ -48,6 +48,10 @@ type View struct {
 	// will be dereferenced as late as possible when rendering diagnostics in
 	// order to access the config loader cache.
 	configSources func() map[string]*hcl.File
+
+	// errorCode is the error code of the first coded error rendered by
+	// this view, which decides the exit code of a failed command.
+	errorCode tfdiags.ErrorCode
 }
 
 // Initialize a View with the given streams, a disabled colorize object, and a
@@ -96,9 +100,26 @@ func (v *View) SetConfigSources(cb func() map[string]*hcl.File) {
 	v.configSources = cb
 }
 
+// RecordErrorCode remembers the error code of the given diagnostics, if
+// they have one and no error code was recorded before. Diagnostics and
+// JSONView.Diagnostics call this automatically, so only code that renders
+// diagnostics some other way needs to call it directly.
+func (v *View) RecordErrorCode(diags tfdiags.Diagnostics) {
+	if v.errorCode == "" {
+		v.errorCode = diags.ErrorCode()
+	}
+}
+
+// ErrorCode returns the error code of the first coded error that this view
+// rendered, or an empty string if it didn't render any.
+func (v *View) ErrorCode() tfdiags.ErrorCode {
+	return v.errorCode
+}
+
 // Diagnostics renders a set of warnings and errors in human-readable form.
 // Warnings are printed to stdout, and errors to stderr.
 func (v *View) Diagnostics(diags tfdiags.Diagnostics) {
+	v.RecordErrorCode(diags)
 	diags.Sort()
 
 	if len(diags) == 0 {
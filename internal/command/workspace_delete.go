@@ -126,7 +126,7 @@ func (c *WorkspaceDeleteCommand) Run(args []string) int {
 
 	var stateLocker clistate.Locker
 	if stateLock {
-		stateLocker = clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker = c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), workspace)
 		if diags := stateLocker.Lock(stateMgr, "state-replace-provider"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -16,7 +16,6 @@ import (
 	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
-	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/statefile"
@@ -143,7 +142,7 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 	}
 
 	if stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := c.newStateLocker(views.NewStateLocker(arguments.ViewHuman, c.View), workspace)
 		if diags := stateLocker.Lock(stateMgr, "workspace-new"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
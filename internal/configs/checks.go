@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -155,12 +156,40 @@ var checkRuleBlockSchema = &hcl.BodySchema{
 type Check struct {
 	Name string
 
+	// Severity decides how OpenTofu reports a failure of any of the check's
+	// assertions. It's CheckSeverityWarning if the check block doesn't set
+	// the "severity" argument.
+	Severity CheckSeverity
+
+	// Metadata is the author-defined "metadata" map, which OpenTofu doesn't
+	// interpret but includes in machine-readable check results so that
+	// external tools can categorize failures.
+	Metadata map[string]string
+
 	DataResource *Resource
 	Asserts      []*CheckRule
 
 	DeclRange hcl.Range
 }
 
+// CheckSeverity is the value of the "severity" argument in a check block.
+type CheckSeverity string
+
+const (
+	// CheckSeverityError makes failed assertions into errors during the
+	// apply phase, so that the apply fails. They are still reported as
+	// warnings during planning, because the checked objects might change
+	// when the plan is applied.
+	CheckSeverityError CheckSeverity = "error"
+
+	// CheckSeverityWarning reports failed assertions as warnings.
+	CheckSeverityWarning CheckSeverity = "warning"
+
+	// CheckSeverityInfo records failed assertions in the check results
+	// without reporting any diagnostics for them.
+	CheckSeverityInfo CheckSeverity = "info"
+)
+
 func (c Check) Addr() addrs.Check {
 	return addrs.Check{
 		Name: c.Name,
@@ -179,6 +208,7 @@ func decodeCheckBlock(block *hcl.Block, override bool) (*Check, hcl.Diagnostics)
 
 	check := &Check{
 		Name:      block.Labels[0],
+		Severity:  CheckSeverityWarning,
 		DeclRange: block.DefRange,
 	}
 
@@ -208,6 +238,30 @@ func decodeCheckBlock(block *hcl.Block, override bool) (*Check, hcl.Diagnostics)
 		})
 	}
 
+	if attr, exists := content.Attributes["severity"]; exists {
+		var severity string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &severity)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			switch s := CheckSeverity(severity); s {
+			case CheckSeverityError, CheckSeverityWarning, CheckSeverityInfo:
+				check.Severity = s
+			default:
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid check severity",
+					Detail:   fmt.Sprintf(`The severity of a check block must be "error", "warning", or "info", not %q.`, severity),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["metadata"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &check.Metadata)
+		diags = append(diags, valDiags...)
+	}
+
 	for _, block := range content.Blocks {
 		switch block.Type {
 		case "data":
@@ -255,6 +309,10 @@ func decodeCheckBlock(block *hcl.Block, override bool) (*Check, hcl.Diagnostics)
 }
 
 var checkBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "severity"},
+		{Name: "metadata"},
+	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "data", LabelNames: []string{"type", "name"}},
 		{Type: "assert"},
@@ -817,6 +817,47 @@ func (c *Config) ProviderTypes() []addrs.Provider {
 	return ret
 }
 
+// ProviderOrigins searches the full tree of modules under the receiver for
+// required_providers entries that declare an origin, and returns a map from
+// each such provider to the address its packages are installed from.
+//
+// Different modules may declare the same origin for a provider, but it is an
+// error for them to declare different origins.
+func (c *Config) ProviderOrigins() (map[addrs.Provider]addrs.Provider, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	origins := make(map[addrs.Provider]addrs.Provider)
+	ranges := make(map[addrs.Provider]hcl.Range)
+
+	c.DeepEach(func(c *Config) {
+		if c.Module.ProviderRequirements == nil {
+			return
+		}
+		for _, rp := range c.Module.ProviderRequirements.RequiredProviders {
+			if rp.Origin.IsZero() {
+				continue
+			}
+			if existing, exists := origins[rp.Type]; exists {
+				if existing != rp.Origin {
+					diags = diags.Append(&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Conflicting provider origins",
+						Detail: fmt.Sprintf(
+							"The provider %s has origin %s here, but origin %s at %s. All modules must declare the same origin for a provider.",
+							rp.Type.ForDisplay(), rp.Origin.ForDisplay(), existing.ForDisplay(), ranges[rp.Type],
+						),
+						Subject: rp.OriginRange.Ptr(),
+					})
+				}
+				continue
+			}
+			origins[rp.Type] = rp.Origin
+			ranges[rp.Type] = rp.OriginRange
+		}
+	})
+
+	return origins, diags
+}
+
 // ResolveAbsProviderAddr returns the AbsProviderConfig represented by the given
 // ProviderConfig address, which must not be nil or this method will panic.
 //
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
@@ -129,13 +130,14 @@ func buildChildModules(ctx context.Context, parent *Config, walker ModuleWalker)
 	}
 	sort.Strings(callNames)
 
-	for _, callName := range callNames {
+	reqs := make([]*ModuleRequest, len(callNames))
+	for i, callName := range callNames {
 		call := calls[callName]
 		path := make([]string, len(parent.Path)+1)
 		copy(path, parent.Path)
 		path[len(path)-1] = call.Name
 
-		req := ModuleRequest{
+		req := &ModuleRequest{
 			Name:              call.Name,
 			Path:              path,
 			SourceAddr:        call.SourceAddr,
@@ -148,15 +150,40 @@ func buildChildModules(ctx context.Context, parent *Config, walker ModuleWalker)
 			// Invalid modules sometimes have a nil source field which is handled through loadModule below
 			req.SourceAddrRange = call.Source.Range()
 		}
-		child, modDiags := loadModule(ctx, parent.Root, &req, walker)
-		diags = append(diags, modDiags...)
+		reqs[i] = req
+	}
+
+	children := make([]*Config, len(reqs))
+	childDiags := make([]hcl.Diagnostics, len(reqs))
+	if _, concurrent := walker.(ConcurrentModuleWalkerFunc); concurrent && len(reqs) > 1 {
+		// The walker can load sibling modules concurrently, but we still
+		// collect the results in the order of the calls so that the
+		// resulting diagnostics are the same as for a sequential walk.
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				children[i], childDiags[i] = loadModule(ctx, parent.Root, req, walker)
+			}()
+		}
+		wg.Wait()
+	} else {
+		for i, req := range reqs {
+			children[i], childDiags[i] = loadModule(ctx, parent.Root, req, walker)
+		}
+	}
+
+	for i, child := range children {
+		diags = append(diags, childDiags[i]...)
 		if child == nil {
 			// This means an error occurred, there should be diagnostics within
-			// modDiags for this.
+			// childDiags for this.
 			continue
 		}
 
-		ret[call.Name] = child
+		diags = append(diags, checkSensitiveOutputs(calls[reqs[i].Name], child.Module)...)
+		ret[reqs[i].Name] = child
 	}
 
 	return ret, diags
@@ -257,6 +284,16 @@ func (f ModuleWalkerFunc) LoadModule(ctx context.Context, req *ModuleRequest) (*
 	return f(ctx, req)
 }
 
+// ConcurrentModuleWalkerFunc is an implementation of ModuleWalker that wraps
+// a callback function which is safe to call concurrently, so that
+// BuildConfig can load sibling modules in parallel.
+type ConcurrentModuleWalkerFunc func(ctx context.Context, req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics)
+
+// LoadModule implements ModuleWalker.
+func (f ConcurrentModuleWalkerFunc) LoadModule(ctx context.Context, req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+	return f(ctx, req)
+}
+
 // ModuleRequest is used with the ModuleWalker interface to describe a child
 // module that must be loaded.
 type ModuleRequest struct {
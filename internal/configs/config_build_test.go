@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -78,6 +79,58 @@ func TestBuildConfig(t *testing.T) {
 	}
 }
 
+func TestBuildConfig_concurrent(t *testing.T) {
+	parser := NewParser(nil)
+	mod, diags := parser.LoadConfigDir("testdata/config-build", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+	if mod == nil {
+		t.Fatal("got nil root module; want non-nil")
+	}
+
+	// The parser isn't safe for concurrent use, so we serialize our own
+	// calls to it, but otherwise let the walk proceed concurrently. Each
+	// module returns a warning so that we can check that the diagnostics are
+	// in the same order as for a sequential walk.
+	var mu sync.Mutex
+	cfg, diags := BuildConfig(t.Context(), mod, ConcurrentModuleWalkerFunc(
+		func(_ context.Context, req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			mu.Lock()
+			defer mu.Unlock()
+			sourcePath := filepath.Join("testdata/config-build", req.SourceAddr.String())
+			mod, modDiags := parser.LoadConfigDir(sourcePath, req.Call)
+			modDiags = modDiags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  req.Path.String(),
+			})
+			return mod, nil, modDiags
+		},
+	))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Summary)
+	}
+	want := []string{
+		"module.child_a",
+		"module.child_a.module.child_c",
+		"module.child_b",
+		"module.child_b.module.child_c",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong diagnostics order\ngot: %swant: %s", spew.Sdump(got), spew.Sdump(want))
+	}
+
+	if _, exists := cfg.Children["child_a"].Children["child_c"].Module.Outputs["hello"]; !exists {
+		t.Fatalf("missing output 'hello' in child_a.child_c")
+	}
+	if _, exists := cfg.Children["child_b"].Children["child_c"].Module.Outputs["hello"]; !exists {
+		t.Fatalf("missing output 'hello' in child_b.child_c")
+	}
+}
+
 func TestBuildConfigDiags(t *testing.T) {
 	parser := NewParser(nil)
 	mod, diags := parser.LoadConfigDir("testdata/nested-errors", RootModuleCallForTesting())
@@ -299,6 +299,22 @@ func TestConfigProviderRequirementsDuplicate(t *testing.T) {
 	assertDiagnosticSummary(t, diags, "Duplicate required provider")
 }
 
+func TestConfigProviderOrigins(t *testing.T) {
+	cfg, diags := testNestedModuleConfigFromDir(t, "testdata/provider-origins")
+	assertNoDiagnostics(t, diags)
+
+	got, diags := cfg.ProviderOrigins()
+	assertDiagnosticCount(t, diags, 1)
+	assertDiagnosticSummary(t, diags, "Conflicting provider origins")
+
+	want := map[addrs.Provider]addrs.Provider{
+		addrs.NewDefaultProvider("test"): addrs.NewProvider("registry.example.com", "mirror", "test"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
 func TestConfigProviderForEach(t *testing.T) {
 	_, diags := testNestedModuleConfigFromDir(t, "testdata/provider_for_each")
 	assertDiagnosticCount(t, diags, 4)
@@ -38,7 +38,7 @@ func (l *Loader) LoadConfigWithSnapshot(ctx context.Context, rootDir string, cal
 	cfg, cDiags := configs.BuildConfig(ctx, rootMod, walker)
 	diags = append(diags, cDiags...)
 
-	addDiags := l.addModuleToSnapshot(snap, "", rootDir, "", nil)
+	addDiags := l.addModuleToSnapshot(snap, "", rootDir, "", nil, call)
 	diags = append(diags, addDiags...)
 
 	return cfg, snap, diags
@@ -152,7 +152,7 @@ func (l *Loader) makeModuleWalkerSnapshot(snap *Snapshot) configs.ModuleWalker {
 				panic(fmt.Sprintf("module %s is not present in manifest", key))
 			}
 
-			addDiags := l.addModuleToSnapshot(snap, key, record.Dir, record.SourceAddr, record.Version)
+			addDiags := l.addModuleToSnapshot(snap, key, record.Dir, record.SourceAddr, record.Version, req.Call)
 			diags = append(diags, addDiags...)
 
 			return mod, v, diags
@@ -160,10 +160,10 @@ func (l *Loader) makeModuleWalkerSnapshot(snap *Snapshot) configs.ModuleWalker {
 	)
 }
 
-func (l *Loader) addModuleToSnapshot(snap *Snapshot, key string, dir string, sourceAddr string, v *version.Version) hcl.Diagnostics {
+func (l *Loader) addModuleToSnapshot(snap *Snapshot, key string, dir string, sourceAddr string, v *version.Version, call configs.StaticModuleCall) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
-	primaryFiles, overrideFiles, moreDiags := l.parser.ConfigDirFiles(dir)
+	primaryFiles, overrideFiles, moreDiags := l.parser.ModuleConfigFiles(dir, call)
 	if moreDiags.HasErrors() {
 		// Any diagnostics we get here should be already present
 		// in diags, so it's weird if we get here but we'll allow it
@@ -114,6 +114,19 @@ func TestBlockInternalValidate(t *testing.T) {
 			},
 			[]string{"foo: cannot set both Computed and Required"},
 		},
+		"attribute write-only and computed": {
+			&Block{
+				Attributes: map[string]*Attribute{
+					"foo": {
+						Type:      cty.String,
+						Optional:  true,
+						Computed:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			[]string{"foo: cannot set both WriteOnly and Computed"},
+		},
 		"attribute optional and computed": {
 			&Block{
 				Attributes: map[string]*Attribute{
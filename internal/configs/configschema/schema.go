@@ -94,6 +94,13 @@ type Attribute struct {
 	Sensitive bool
 
 	Deprecated bool
+
+	// WriteOnly, if set to true, indicates that the attribute's value is
+	// sent to the provider but never persisted in the plan or state. A
+	// write-only attribute is always null in the planned and new states
+	// returned by the provider, and it is the only kind of resource argument
+	// that may be set to an ephemeral value.
+	WriteOnly bool
 }
 
 // Object represents the embedding of a structural object inside an Attribute.
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ContainsWriteOnly returns true if any of the attributes of the receiving
+// block or any of its descendant blocks are marked as write-only.
+func (b *Block) ContainsWriteOnly() bool {
+	for _, attrS := range b.Attributes {
+		if attrS.WriteOnly {
+			return true
+		}
+		if attrS.NestedType != nil && attrS.NestedType.ContainsWriteOnly() {
+			return true
+		}
+	}
+	for _, blockS := range b.BlockTypes {
+		if blockS.ContainsWriteOnly() {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsWriteOnly returns true if any of the attributes of the receiving
+// Object are marked as write-only.
+func (o *Object) ContainsWriteOnly() bool {
+	for _, attrS := range o.Attributes {
+		if attrS.WriteOnly {
+			return true
+		}
+		if attrS.NestedType != nil && attrS.NestedType.ContainsWriteOnly() {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteOnlyAsNull returns a copy of the given value, which must conform to
+// the block's implied type, with the values of all write-only attributes
+// replaced by nulls.
+//
+// Write-only attributes are never persisted in a plan or state, so this is
+// used to remove them from objects returned by a provider.
+func (b *Block) WriteOnlyAsNull(val cty.Value) cty.Value {
+	if val == cty.NilVal || !b.ContainsWriteOnly() {
+		return val
+	}
+	// The callback never returns an error, so neither does Transform.
+	ret, _ := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if len(path) == 0 || v.IsNull() {
+			return v, nil
+		}
+		if _, ok := path[len(path)-1].(cty.GetAttrStep); !ok {
+			return v, nil
+		}
+		if attrS := b.AttributeByPath(path); attrS != nil && attrS.WriteOnly {
+			return cty.NullVal(v.Type()), nil
+		}
+		return v, nil
+	})
+	return ret
+}
+
+// IsWriteOnlyPath returns true if the given path refers to a write-only
+// attribute of the receiving block, or to a value nested inside one.
+func (b *Block) IsWriteOnlyPath(path cty.Path) bool {
+	for i := len(path); i > 0; i-- {
+		if attrS := b.AttributeByPath(path[:i]); attrS != nil && attrS.WriteOnly {
+			return true
+		}
+	}
+	return false
+}
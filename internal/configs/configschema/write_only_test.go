@@ -0,0 +1,79 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configschema
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBlockWriteOnlyAsNull(t *testing.T) {
+	schema := &Block{
+		Attributes: map[string]*Attribute{
+			"name": {
+				Type:     cty.String,
+				Optional: true,
+			},
+			"password": {
+				Type:      cty.String,
+				Optional:  true,
+				WriteOnly: true,
+			},
+		},
+		BlockTypes: map[string]*NestedBlock{
+			"user": {
+				Nesting: NestingList,
+				Block: Block{
+					Attributes: map[string]*Attribute{
+						"name": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"token": {
+							Type:      cty.String,
+							Optional:  true,
+							WriteOnly: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	input := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("example"),
+		"password": cty.StringVal("secret"),
+		"user": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("admin"),
+				"token": cty.StringVal("also secret"),
+			}),
+		}),
+	})
+	want := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("example"),
+		"password": cty.NullVal(cty.String),
+		"user": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("admin"),
+				"token": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+
+	got := schema.WriteOnlyAsNull(input)
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	if !schema.IsWriteOnlyPath(cty.GetAttrPath("user").IndexInt(0).GetAttr("token")) {
+		t.Errorf("user[0].token is not reported as write-only")
+	}
+	if schema.IsWriteOnlyPath(cty.GetAttrPath("user").IndexInt(0).GetAttr("name")) {
+		t.Errorf("user[0].name is reported as write-only")
+	}
+}
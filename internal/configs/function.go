@@ -0,0 +1,266 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// Function represents a "function" block in a module or file, which declares
+// a user-defined function.
+//
+// The result of a function is given by an expression that can refer only to
+// the function's own parameters, so that calling a function never depends on
+// anything else in the configuration.
+type Function struct {
+	Name        string
+	Description string
+
+	// Params are the names of the function's parameters, in the order that
+	// callers must give their arguments.
+	Params []string
+
+	Result hcl.Expression
+
+	// Export is true if the function may also be called from the module
+	// that calls this one.
+	Export bool
+
+	DeclRange hcl.Range
+}
+
+func decodeFunctionBlock(block *hcl.Block) (*Function, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	f := &Function{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	content, moreDiags := block.Body.Content(functionBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if !hclsyntax.ValidIdentifier(f.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid function name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	if attr, exists := content.Attributes["description"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &f.Description)
+		diags = append(diags, valDiags...)
+	}
+
+	if attr, exists := content.Attributes["params"]; exists {
+		params, paramsDiags := decodeFunctionParams(attr)
+		diags = append(diags, paramsDiags...)
+		f.Params = params
+	}
+
+	if attr, exists := content.Attributes["result"]; exists {
+		f.Result = attr.Expr
+		diags = append(diags, checkFunctionResultReferences(f)...)
+	}
+
+	if attr, exists := content.Attributes["export"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &f.Export)
+		diags = append(diags, valDiags...)
+	}
+
+	return f, diags
+}
+
+// decodeFunctionParams decodes the "params" argument of a function block,
+// which is a list of bare parameter names.
+func decodeFunctionParams(attr *hcl.Attribute) ([]string, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(attr.Expr)
+	ret := make([]string, 0, len(exprs))
+	seen := make(map[string]hcl.Range, len(exprs))
+
+	for _, expr := range exprs {
+		name := hcl.ExprAsKeyword(expr)
+		if name == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid function parameter",
+				Detail:   "Each function parameter must be a single name, given without quotes.",
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		if prev, exists := seen[name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate function parameter",
+				Detail:   fmt.Sprintf("A parameter named %q was already declared at %s. Parameter names must be unique within a function.", name, prev),
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		seen[name] = expr.Range()
+		ret = append(ret, name)
+	}
+
+	return ret, diags
+}
+
+// checkFunctionResultReferences returns error diagnostics for any references
+// in the result expression of the given function that don't refer to one of
+// its parameters.
+func checkFunctionResultReferences(f *Function) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	params := make(map[string]struct{}, len(f.Params))
+	for _, name := range f.Params {
+		params[name] = struct{}{}
+	}
+	for _, traversal := range f.Result.Variables() {
+		if _, ok := params[traversal.RootName()]; ok {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid reference in function result",
+			Detail:   fmt.Sprintf("The result of function %q can refer only to the function's parameters, so %q is not allowed here.", f.Name, traversal.RootName()),
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+	}
+	return diags
+}
+
+// localFunctionCalls returns the names of the functions declared in the same
+// module that the result expression of the given function calls, along with
+// the source range of the first call to each.
+func localFunctionCalls(f *Function) map[string]hcl.Range {
+	ret := make(map[string]hcl.Range)
+	fexpr, ok := f.Result.(hcl.ExpressionWithFunctions)
+	if !ok {
+		return ret
+	}
+	for _, traversal := range fexpr.Functions() {
+		fn := addrs.ParseFunction(traversal.RootName())
+		if len(fn.Namespaces) != 1 || !fn.IsNamespace(addrs.FunctionNamespaceLocal) {
+			continue
+		}
+		if _, exists := ret[fn.Name]; !exists {
+			ret[fn.Name] = traversal.SourceRange()
+		}
+	}
+	return ret
+}
+
+// checkModuleFunctions returns error diagnostics for any calls between the
+// functions declared in the given module that refer to undeclared functions
+// or that would make a function call itself, directly or indirectly.
+func checkModuleFunctions(m *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(m.Functions))
+	calls := make(map[string]map[string]hcl.Range, len(m.Functions))
+	for name, f := range m.Functions {
+		names = append(names, name)
+		if f.Result != nil {
+			calls[name] = localFunctionCalls(f)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for callee, rng := range calls[name] {
+			if _, exists := m.Functions[callee]; !exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Call to undeclared function",
+					Detail:   fmt.Sprintf("The result of function %q calls %s::%s, but there is no function named %q declared in this module.", name, addrs.FunctionNamespaceLocal, callee, callee),
+					Subject:  rng.Ptr(),
+				})
+			}
+		}
+	}
+
+	// We report each cycle only once, from the function that sorts first.
+	reported := make(map[string]bool)
+	for _, name := range names {
+		if reported[name] {
+			continue
+		}
+		path := findReferenceCycle(name, calls, nil)
+		if path == nil {
+			continue
+		}
+		for _, n := range path {
+			reported[n] = true
+		}
+		rng := calls[path[len(path)-2]][name]
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Recursive function call",
+			Detail:   fmt.Sprintf("Functions cannot call themselves, either directly or through other functions: %s.", strings.Join(path, " -> ")),
+			Subject:  rng.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// findReferenceCycle returns a path of names that starts and ends with the
+// first name in path, following the given references between names, or nil
+// if there's no such path. It's used to find both function calls and local
+// values that would depend on themselves.
+func findReferenceCycle(name string, refs map[string]map[string]hcl.Range, path []string) []string {
+	if len(path) > 0 && name == path[0] {
+		return append(path, name)
+	}
+	for _, n := range path {
+		if n == name {
+			// A cycle that doesn't include the starting name, which will
+			// be reported when we start from one of its members.
+			return nil
+		}
+	}
+	path = append(path, name)
+
+	targets := make([]string, 0, len(refs[name]))
+	for target := range refs[name] {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		if cycle := findReferenceCycle(target, refs, path); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+var functionBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "description",
+		},
+		{
+			Name:     "params",
+			Required: true,
+		},
+		{
+			Name:     "result",
+			Required: true,
+		},
+		{
+			Name: "export",
+		},
+	},
+}
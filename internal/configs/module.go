@@ -48,6 +48,8 @@ type Module struct {
 	Locals    map[string]*Local
 	Outputs   map[string]*Output
 
+	Functions map[string]*Function
+
 	ModuleCalls map[string]*ModuleCall
 
 	ManagedResources map[string]*Resource
@@ -103,6 +105,8 @@ type File struct {
 	Locals    []*Local
 	Outputs   []*Output
 
+	Functions []*Function
+
 	ModuleCalls []*ModuleCall
 
 	ManagedResources []*Resource
@@ -175,6 +179,7 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		Variables:          map[string]*Variable{},
 		Locals:             map[string]*Local{},
 		Outputs:            map[string]*Output{},
+		Functions:          map[string]*Function{},
 		ModuleCalls:        map[string]*ModuleCall{},
 		ManagedResources:   map[string]*Resource{},
 		DataResources:      map[string]*Resource{},
@@ -257,6 +262,8 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 	}
 
 	diags = append(diags, checkModuleExperiments(mod)...)
+	diags = append(diags, checkModuleFunctions(mod)...)
+	diags = append(diags, checkModuleLocals(mod)...)
 
 	// Generate the FQN -> LocalProviderName map
 	mod.gatherProviderLocalNames()
@@ -408,6 +415,18 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		m.Outputs[o.Name] = o
 	}
 
+	for _, f := range file.Functions {
+		if existing, exists := m.Functions[f.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate function definition",
+				Detail:   fmt.Sprintf("A function named %q was already defined at %s. Function names must be unique within a module.", existing.Name, existing.DeclRange),
+				Subject:  &f.DeclRange,
+			})
+		}
+		m.Functions[f.Name] = f
+	}
+
 	for _, mc := range file.ModuleCalls {
 		if existing, exists := m.ModuleCalls[mc.Name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
@@ -754,6 +773,15 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		})
 	}
 
+	for _, f := range file.Functions {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cannot override 'function' blocks",
+			Detail:   "Functions can be declared only in normal files, not in override files.",
+			Subject:  f.DeclRange.Ptr(),
+		})
+	}
+
 	for _, m := range file.Removed {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
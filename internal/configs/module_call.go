@@ -15,6 +15,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/getmodules"
 )
 
@@ -39,10 +40,22 @@ type ModuleCall struct {
 	Count   hcl.Expression
 	ForEach hcl.Expression
 
+	// Enabled is the expression given for the "enabled" lifecycle argument,
+	// or nil if it isn't set. A module call that sets it has either a single
+	// instance with no key or no instances at all, depending on its value.
+	Enabled hcl.Expression
+
 	Providers []PassedProviderConfig
 
 	DependsOn []hcl.Traversal
 
+	// SensitiveOutputs, if not nil, redeclares the sensitivity of the child
+	// module's output values as seen by the calling module: the outputs
+	// named here are sensitive and all of the others are not, regardless of
+	// how the child module declares them.
+	SensitiveOutputs      []string
+	SensitiveOutputsRange hcl.Range
+
 	DeclRange hcl.Range
 }
 
@@ -110,9 +123,37 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 		mc.Providers = append(mc.Providers, providers...)
 	}
 
+	if attr, exists := content.Attributes["sensitive_outputs"]; exists {
+		names, namesDiags := decodeSensitiveOutputs(attr)
+		diags = append(diags, namesDiags...)
+		mc.SensitiveOutputs = names
+		mc.SensitiveOutputsRange = attr.Expr.Range()
+	}
+
 	var seenEscapeBlock *hcl.Block
+	var seenLifecycle *hcl.Block
 	for _, block := range content.Blocks {
 		switch block.Type {
+		case "lifecycle":
+			if seenLifecycle != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate lifecycle block",
+					Detail:   fmt.Sprintf("This module call already has a lifecycle block at %s.", seenLifecycle.DefRange),
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+			seenLifecycle = block
+
+			lcContent, lcDiags := block.Body.Content(moduleLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				mc.Enabled = attr.Expr
+				diags = append(diags, checkEnabledRepetition(attr, mc.Count, mc.ForEach)...)
+			}
+
 		case "_":
 			if seenEscapeBlock != nil {
 				diags = append(diags, &hcl.Diagnostic{
@@ -147,6 +188,56 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 	return mc, diags
 }
 
+// decodeSensitiveOutputs decodes the "sensitive_outputs" argument of a module
+// block, which is a list of output value names given as string literals. The
+// result is never nil, so that an empty list can be distinguished from the
+// argument not being set at all.
+func decodeSensitiveOutputs(attr *hcl.Attribute) ([]string, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(attr.Expr)
+	ret := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		val, valDiags := expr.Value(nil)
+		if valDiags.HasErrors() || val.Type() != cty.String || val.IsNull() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid sensitive_outputs element",
+				Detail:   "Each element of sensitive_outputs must be the name of an output value of the child module, given as a string.",
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		ret = append(ret, val.AsString())
+	}
+	return ret, diags
+}
+
+// checkSensitiveOutputs returns error diagnostics for any names in the
+// "sensitive_outputs" argument of the given module call that are not output
+// values declared in the child module.
+func checkSensitiveOutputs(mc *ModuleCall, child *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, name := range mc.SensitiveOutputs {
+		if _, exists := child.Outputs[name]; exists {
+			continue
+		}
+		suggestions := make([]string, 0, len(child.Outputs))
+		for k := range child.Outputs {
+			suggestions = append(suggestions, k)
+		}
+		suggestion := didyoumean.NameSuggestion(name, suggestions)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Reference to undeclared output value",
+			Detail:   fmt.Sprintf("The sensitive_outputs argument of module %q includes %q, but the module has no output value with that name.%s", mc.Name, name, suggestion),
+			Subject:  mc.SensitiveOutputsRange.Ptr(),
+		})
+	}
+	return diags
+}
+
 func (mc *ModuleCall) decodeStaticFields(ctx context.Context, eval *StaticEvaluator) hcl.Diagnostics {
 	mc.Workspace = eval.call.workspace
 	mc.decodeStaticVariables(ctx, eval)
@@ -357,17 +448,28 @@ var moduleBlockSchema = &hcl.BodySchema{
 		{
 			Name: "providers",
 		},
+		{
+			Name: "sensitive_outputs",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "_"}, // meta-argument escaping block
+		{Type: "lifecycle"},
 
 		// These are all reserved for future use.
-		{Type: "lifecycle"},
 		{Type: "locals"},
 		{Type: "provider", LabelNames: []string{"type"}},
 	},
 }
 
+var moduleLifecycleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "enabled",
+		},
+	},
+}
+
 func moduleSourceAddrEntersNewPackage(addr addrs.ModuleSource) bool {
 	switch addr.(type) {
 	case nil:
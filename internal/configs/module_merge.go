@@ -51,6 +51,10 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Sensitive = ov.Sensitive
 		v.SensitiveSet = ov.SensitiveSet
 	}
+	if ov.EphemeralSet {
+		v.Ephemeral = ov.Ephemeral
+		v.EphemeralSet = ov.EphemeralSet
+	}
 	if ov.Deprecated != "" {
 		v.Deprecated = ov.Deprecated
 	}
@@ -149,10 +153,19 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 	if oo.Expr != nil {
 		o.Expr = oo.Expr
 	}
+	if oo.Type != cty.NilType {
+		o.Type = oo.Type
+		o.ConstraintType = oo.ConstraintType
+		o.TypeDefaults = oo.TypeDefaults
+	}
 	if oo.SensitiveSet {
 		o.Sensitive = oo.Sensitive
 		o.SensitiveSet = oo.SensitiveSet
 	}
+	if oo.EphemeralSet {
+		o.Ephemeral = oo.Ephemeral
+		o.EphemeralSet = oo.EphemeralSet
+	}
 	if oo.Deprecated != "" {
 		o.Deprecated = oo.Deprecated
 	}
@@ -187,6 +200,10 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 		mc.ForEach = omc.ForEach
 	}
 
+	if omc.Enabled != nil {
+		mc.Enabled = omc.Enabled
+	}
+
 	if omc.VersionAttr != nil {
 		mc.VersionAttr = omc.VersionAttr
 	}
@@ -197,6 +214,11 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 		mc.Providers = omc.Providers
 	}
 
+	if omc.SensitiveOutputs != nil {
+		mc.SensitiveOutputs = omc.SensitiveOutputs
+		mc.SensitiveOutputsRange = omc.SensitiveOutputsRange
+	}
+
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
 	if len(omc.DependsOn) != 0 {
@@ -226,6 +248,16 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 	if or.ForEach != nil {
 		r.ForEach = or.ForEach
 	}
+	if or.Enabled != nil {
+		r.Enabled = or.Enabled
+	}
+	if or.DeferReadUntilApplySet {
+		r.DeferReadUntilApply = or.DeferReadUntilApply
+		r.DeferReadUntilApplySet = or.DeferReadUntilApplySet
+	}
+	if len(or.MaskInOutput) != 0 {
+		r.MaskInOutput = or.MaskInOutput
+	}
 
 	if or.ProviderConfigRef != nil {
 		r.ProviderConfigRef = or.ProviderConfigRef
@@ -261,6 +293,15 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 		if len(or.Managed.Provisioners) != 0 {
 			r.Managed.Provisioners = or.Managed.Provisioners
 		}
+		if len(or.Managed.Hooks) != 0 {
+			r.Managed.Hooks = or.Managed.Hooks
+		}
+		if or.Managed.Timeouts != nil {
+			r.Managed.Timeouts = or.Managed.Timeouts
+		}
+		if or.Managed.Retry != nil {
+			r.Managed.Retry = or.Managed.Retry
+		}
 	}
 
 	r.Config = MergeBodies(r.Config, or.Config)
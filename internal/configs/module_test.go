@@ -6,6 +6,8 @@
 package configs
 
 import (
+	"context"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -443,3 +445,62 @@ func TestModule_cloud_duplicate_overrides(t *testing.T) {
 		t.Fatalf("expected module error to contain %q\nerror was:\n%s", want, got)
 	}
 }
+
+func TestModule_functions(t *testing.T) {
+	cfg, diags := testModuleConfigFromFile(context.Background(), "testdata/valid-files/functions.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	mod := cfg.Module
+
+	f, exists := mod.Functions["subnet_name"]
+	if !exists {
+		t.Fatal("no function named \"subnet_name\"")
+	}
+	if got, want := f.Params, []string{"env", "zone"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong params\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if f.Export {
+		t.Errorf("subnet_name is exported; want not exported")
+	}
+	if !mod.Functions["prefix"].Export {
+		t.Errorf("prefix is not exported; want exported")
+	}
+}
+
+func TestModule_localsCycle(t *testing.T) {
+	_, diags := testModuleFromDir("testdata/invalid-modules/locals-cycle")
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Error())
+	}
+	want := `Local values cannot refer to themselves, either directly or through other local values. local.a depends on itself through the following references:
+
+  local.a refers to local.b at testdata/invalid-modules/locals-cycle/main.tf:2,10-17
+  local.b refers to local.c at testdata/invalid-modules/locals-cycle/main.tf:3,13-20
+  local.c refers to local.a at testdata/invalid-modules/locals-cycle/main.tf:4,8-15`
+	if got := diags[0].Detail; got != want {
+		t.Errorf("wrong detail\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := diags[0].Subject.String(), "testdata/invalid-modules/locals-cycle/main.tf:4,8-15"; got != want {
+		t.Errorf("wrong subject\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestModule_functionsInvalidCalls(t *testing.T) {
+	tests := map[string]string{
+		"testdata/invalid-modules/function-recursive":  `Functions cannot call themselves, either directly or through other functions: a -> b -> a.`,
+		"testdata/invalid-modules/function-undeclared": `The result of function "a" calls local::missing, but there is no function named "missing" declared in this module.`,
+	}
+
+	for dir, want := range tests {
+		t.Run(dir, func(t *testing.T) {
+			_, diags := testModuleFromDir(dir)
+			if len(diags) != 1 {
+				t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Error())
+			}
+			if got := diags[0].Detail; got != want {
+				t.Errorf("wrong detail\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
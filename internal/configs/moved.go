@@ -7,6 +7,7 @@ package configs
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/opentofu/opentofu/internal/addrs"
 )
 
@@ -27,7 +28,7 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 	diags = append(diags, moreDiags...)
 
 	if attr, exists := content.Attributes["from"]; exists {
-		from, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		from, traversalDiags := movedEndpointTraversal(attr.Expr)
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
 			from, fromDiags := addrs.ParseMoveEndpoint(from)
@@ -37,7 +38,7 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 	}
 
 	if attr, exists := content.Attributes["to"]; exists {
-		to, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		to, traversalDiags := movedEndpointTraversal(attr.Expr)
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
 			to, toDiags := addrs.ParseMoveEndpoint(to)
@@ -57,12 +58,52 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 				Detail:   "The \"from\" and \"to\" addresses must either both refer to resources or both refer to modules.",
 				Subject:  &moved.DeclRange,
 			})
+		} else if moved.From.WildcardCount() != moved.To.WildcardCount() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"moved\" addresses",
+				Detail:   "The \"from\" and \"to\" addresses must use the same number of [*] wildcards, so that each wildcard in \"to\" can take the instance key matched by the corresponding wildcard in \"from\".",
+				Subject:  &moved.DeclRange,
+			})
 		}
 	}
 
 	return moved, diags
 }
 
+// movedEndpointTraversal is like hcl.AbsTraversalForExpr, except that it also
+// accepts the splat operator [*] in place of instance keys, which it returns
+// as hcl.TraverseSplat steps for addrs.ParseMoveEndpoint to interpret as
+// wildcards.
+func movedEndpointTraversal(expr hcl.Expression) (hcl.Traversal, hcl.Diagnostics) {
+	switch expr := expr.(type) {
+	case *hclsyntax.SplatExpr:
+		traversal, diags := movedEndpointTraversal(expr.Source)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		each, moreDiags := movedEndpointTraversal(expr.Each)
+		diags = append(diags, moreDiags...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		traversal = append(traversal, hcl.TraverseSplat{SrcRange: expr.MarkerRange})
+		return append(traversal, each...), diags
+	case *hclsyntax.RelativeTraversalExpr:
+		// This can only be the remainder of the address after a splat
+		// operator, relative to the anonymous symbol below.
+		traversal, diags := movedEndpointTraversal(expr.Source)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return append(traversal, expr.Traversal...), diags
+	case *hclsyntax.AnonSymbolExpr:
+		return nil, nil
+	default:
+		return hcl.AbsTraversalForExpr(expr)
+	}
+}
+
 var movedBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
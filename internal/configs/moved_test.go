@@ -191,6 +191,9 @@ func TestMovedBlock_inModule(t *testing.T) {
 		{`module.a`, `module.b`},
 		{`module.a`, `module.a["foo"]`},
 		{`test.foo`, `module.a.test.foo`},
+		{`module.old[*].test.foo`, `module.new[*].test.foo`},
+		{`module.a[*].module.b[*]`, `module.c[*].module.d[*]`},
+		{`module.a[*].test.foo`, `test.foo[*]`},
 		{`data.test.foo`, `data.test.bar`},
 	}
 	if diff := cmp.Diff(wantPairs, gotPairs); diff != "" {
@@ -7,6 +7,7 @@ package configs
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -20,6 +21,14 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 )
 
+// moduleArgumentsAllowedAsVariables are the names of module block arguments
+// that were added after existing modules could already declare variables with
+// the same names. Those variables are still allowed, and callers can set them
+// using the "_" escaping block.
+var moduleArgumentsAllowedAsVariables = map[string]bool{
+	"sensitive_outputs": true,
+}
+
 // A consistent detail message for all "not a valid identifier" diagnostics.
 const badIdentifierDetail = "A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes."
 
@@ -41,8 +50,14 @@ type Variable struct {
 	Sensitive   bool
 	Deprecated  string
 
+	// Ephemeral indicates that the variable's value is available only during
+	// the current operation, and so must never be persisted in a plan or
+	// state.
+	Ephemeral bool
+
 	DescriptionSet bool
 	SensitiveSet   bool
+	EphemeralSet   bool
 
 	// Nullable indicates that null is a valid value for this variable. Setting
 	// Nullable to false means that the module can expect this variable to
@@ -84,7 +99,7 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 	// reserved attribute and block type names in a "module" block, since
 	// these won't be usable for child modules.
 	for _, attr := range moduleBlockSchema.Attributes {
-		if attr.Name == v.Name {
+		if attr.Name == v.Name && !moduleArgumentsAllowedAsVariables[attr.Name] {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid variable name",
@@ -125,6 +140,12 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		v.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Ephemeral)
+		diags = append(diags, valDiags...)
+		v.EphemeralSet = true
+	}
+
 	if attr, exists := content.Attributes["deprecated"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Deprecated)
 		diags = append(diags, valDiags...)
@@ -419,10 +440,24 @@ type Output struct {
 	Sensitive   bool
 	Deprecated  string
 
+	// Ephemeral indicates that the output value may be derived from ephemeral
+	// values, and so must never be persisted in a plan or state. Only outputs
+	// of child modules can be ephemeral.
+	Ephemeral bool
+
+	// Type is the concrete type of the output value, or cty.NilType if the
+	// output block doesn't declare a type constraint.
+	Type cty.Type
+	// ConstraintType is used for type conversions, and may contain nested
+	// ObjectWithOptionalAttr types.
+	ConstraintType cty.Type
+	TypeDefaults   *typeexpr.Defaults
+
 	Preconditions []*CheckRule
 
 	DescriptionSet bool
 	SensitiveSet   bool
+	EphemeralSet   bool
 
 	DeclRange hcl.Range
 
@@ -470,12 +505,28 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.Expr = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["type"]; exists {
+		// Output values are always produced by expressions, so the parsing
+		// mode that decodeVariableType returns isn't meaningful here.
+		ty, tyDefaults, _, tyDiags := decodeVariableType(attr.Expr)
+		diags = append(diags, tyDiags...)
+		o.ConstraintType = ty
+		o.TypeDefaults = tyDefaults
+		o.Type = ty.WithoutOptionalAttributesDeep()
+	}
+
 	if attr, exists := content.Attributes["sensitive"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Sensitive)
 		diags = append(diags, valDiags...)
 		o.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Ephemeral)
+		diags = append(diags, valDiags...)
+		o.EphemeralSet = true
+	}
+
 	if attr, exists := content.Attributes["deprecated"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Deprecated)
 		diags = append(diags, valDiags...)
@@ -567,6 +618,72 @@ func (l *Local) Addr() addrs.LocalValue {
 	}
 }
 
+// localReferences returns the names of the other local values that the
+// expression of the given local value refers to, along with the source range
+// of the first reference to each.
+func localReferences(l *Local) map[string]hcl.Range {
+	ret := make(map[string]hcl.Range)
+	for _, traversal := range l.Expr.Variables() {
+		if traversal.RootName() != "local" || len(traversal) < 2 {
+			continue
+		}
+		step, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		if _, exists := ret[step.Name]; !exists {
+			ret[step.Name] = hcl.RangeBetween(traversal[0].SourceRange(), step.SrcRange)
+		}
+	}
+	return ret
+}
+
+// checkModuleLocals returns error diagnostics for any local values in the
+// given module that would depend on themselves, either directly or through
+// other local values.
+//
+// Such cycles would otherwise be reported only when building the graph,
+// without saying which expressions are involved, so we describe each
+// reference in the chain along with its source range.
+func checkModuleLocals(m *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(m.Locals))
+	refs := make(map[string]map[string]hcl.Range, len(m.Locals))
+	for name, l := range m.Locals {
+		names = append(names, name)
+		refs[name] = localReferences(l)
+	}
+	sort.Strings(names)
+
+	// We report each cycle only once, from the local value that sorts first.
+	reported := make(map[string]bool)
+	for _, name := range names {
+		if reported[name] {
+			continue
+		}
+		path := findReferenceCycle(name, refs, nil)
+		if path == nil {
+			continue
+		}
+		var chain strings.Builder
+		for i, n := range path[:len(path)-1] {
+			reported[n] = true
+			next := path[i+1]
+			fmt.Fprintf(&chain, "\n  local.%s refers to local.%s at %s", n, next, refs[n][next])
+		}
+		rng := refs[path[len(path)-2]][name]
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cycle in local values",
+			Detail:   fmt.Sprintf("Local values cannot refer to themselves, either directly or through other local values. local.%s depends on itself through the following references:\n%s", name, chain.String()),
+			Subject:  rng.Ptr(),
+		})
+	}
+
+	return diags
+}
+
 var variableBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
@@ -581,6 +698,9 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 		{
 			Name: "deprecated",
 		},
@@ -604,12 +724,18 @@ var outputBlockSchema = &hcl.BodySchema{
 			Name:     "value",
 			Required: true,
 		},
+		{
+			Name: "type",
+		},
 		{
 			Name: "depends_on",
 		},
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 		{
 			Name: "deprecated",
 		},
@@ -76,6 +76,32 @@ func (p *Parser) LoadHCLFile(path string) (hcl.Body, hcl.Diagnostics) {
 	switch {
 	case strings.HasSuffix(path, ".json"):
 		file, diags = p.p.ParseJSON(src, path)
+	case strings.HasSuffix(path, ".jsonc"):
+		jsonSrc, jsoncDiags := jsoncToJSON(src, path)
+		if jsoncDiags.HasErrors() {
+			return hcl.EmptyBody(), jsoncDiags
+		}
+		file, diags = p.p.ParseJSON(jsonSrc, path)
+		if file != nil {
+			// The comments were replaced with whitespace, so the original
+			// source has everything at the same position.
+			p.p.AddFile(path, &hcl.File{Body: file.Body, Bytes: src, Nav: file.Nav})
+		}
+	case strings.HasSuffix(path, tofuYAMLExt):
+		jsonSrc, yamlDiags := yamlToJSON(src, path)
+		if yamlDiags.HasErrors() {
+			return hcl.EmptyBody(), yamlDiags
+		}
+		// The translated document keeps values on the same lines as in the
+		// YAML source, and once both are aligned every line starts at the
+		// same byte, so we cache the aligned YAML source for use in
+		// diagnostic snippets. Columns can still differ; see alignYAMLLines.
+		yamlSrc, jsonSrc := alignYAMLLines(src, jsonSrc)
+		file, diags = p.p.ParseJSON(jsonSrc, path)
+		diags = append(yamlDiags, diags...)
+		if file != nil {
+			p.p.AddFile(path, &hcl.File{Body: file.Body, Bytes: yamlSrc, Nav: file.Nav})
+		}
 	default:
 		file, diags = p.p.ParseHCL(src, path)
 	}
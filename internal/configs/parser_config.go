@@ -162,6 +162,13 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 				file.Outputs = append(file.Outputs, cfg)
 			}
 
+		case "function":
+			cfg, cfgDiags := decodeFunctionBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				file.Functions = append(file.Functions, cfg)
+			}
+
 		case "module":
 			cfg, cfgDiags := decodeModuleBlock(block, override)
 			diags = append(diags, cfgDiags...)
@@ -286,6 +293,10 @@ var configFileSchema = &hcl.BodySchema{
 			Type:       "output",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       "function",
+			LabelNames: []string{"name"},
+		},
 		{
 			Type:       "module",
 			LabelNames: []string{"name"},
@@ -26,6 +26,9 @@ const (
 	tofuExt         = ".tofu"
 	tfJSONExt       = ".tf.json"
 	tofuJSONExt     = ".tofu.json"
+	tfJSONCExt      = ".tf.jsonc"
+	tofuJSONCExt    = ".tofu.jsonc"
+	tofuYAMLExt     = ".tofu.yaml"
 	tfTestExt       = ".tftest.hcl"
 	tofuTestExt     = ".tofutest.hcl"
 	tfTestJSONExt   = ".tftest.json"
@@ -50,7 +53,12 @@ const (
 // Parser.IsConfigDir if they wish to recognize that situation.
 //
 // .tf files are parsed using the HCL native syntax while .tf.json files are
-// parsed using the HCL JSON syntax.
+// parsed using the HCL JSON syntax. .tf.jsonc files are parsed in the same
+// way after removing any comments and trailing commas, and .tofu.yaml files
+// are translated into the HCL JSON syntax before parsing.
+//
+// Workspace-specific override files, such as main_override.prod.tf, are
+// loaded only when the workspace of the given call has the same name.
 func (p *Parser) LoadConfigDir(path string, call StaticModuleCall) (*Module, hcl.Diagnostics) {
 	return p.LoadConfigDirSelective(path, call, SelectiveLoadAll)
 }
@@ -59,6 +67,7 @@ func (p *Parser) LoadConfigDirSelective(path string, call StaticModuleCall, load
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	overridePaths = workspaceOverrideFiles(overridePaths, call.workspace)
 
 	primary, fDiags := p.loadFiles(primaryPaths, false)
 	diags = append(diags, fDiags...)
@@ -78,6 +87,7 @@ func (p *Parser) LoadConfigDirWithTests(path string, testDirectory string, call
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	overridePaths = workspaceOverrideFiles(overridePaths, call.workspace)
 
 	primary, fDiags := p.loadFiles(primaryPaths, false)
 	diags = append(diags, fDiags...)
@@ -93,7 +103,8 @@ func (p *Parser) LoadConfigDirWithTests(path string, testDirectory string, call
 }
 
 // ConfigDirFiles returns lists of the primary and override files configuration
-// files in the given directory.
+// files in the given directory. The override files include the
+// workspace-specific override files of every workspace.
 //
 // If the given directory does not exist or cannot be read, error diagnostics
 // are returned. If errors are returned, the resulting lists may be incomplete.
@@ -102,6 +113,17 @@ func (p Parser) ConfigDirFiles(dir string) (primary, override []string, diags hc
 	return primary, override, diags
 }
 
+// ModuleConfigFiles matches ConfigDirFiles except that it returns only the
+// files that LoadConfigDir would load for the given call, leaving out the
+// workspace-specific override files of other workspaces.
+func (p Parser) ModuleConfigFiles(dir string, call StaticModuleCall) (primary, override []string, diags hcl.Diagnostics) {
+	primary, override, _, diags = p.dirFiles(dir, "")
+	// Loading the module reports the files of other workspaces, so we don't
+	// need to report them again here.
+	override = workspaceOverrideFiles(override, call.workspace)
+	return primary, override, diags
+}
+
 // ConfigDirFilesWithTests matches ConfigDirFiles except it also returns the
 // paths to any test files within the module.
 func (p Parser) ConfigDirFilesWithTests(dir string, testDirectory string) (primary, override, tests []string, diags hcl.Diagnostics) {
@@ -229,7 +251,7 @@ func (p *Parser) dirFiles(dir string, testsDir string) (primary, override, tests
 		}
 
 		baseName := name[:len(name)-len(ext)] // strip extension
-		isOverride := baseName == "override" || strings.HasSuffix(baseName, "_override")
+		_, isOverride := overrideFileWorkspace(baseName)
 
 		fullPath := filepath.Join(dir, name)
 		if isOverride {
@@ -242,6 +264,44 @@ func (p *Parser) dirFiles(dir string, testsDir string) (primary, override, tests
 	return filterTfPathsWithTofuAlternatives(primary), filterTfPathsWithTofuAlternatives(override), filterTfPathsWithTofuAlternatives(tests), diags
 }
 
+// overrideFileWorkspace determines whether a file with the given name, without
+// its extension, is an override file. If it is a workspace-specific override
+// file, such as main_override.prod.tf or override.prod.tf, then it also
+// returns the name of the workspace the file applies to.
+func overrideFileWorkspace(baseName string) (workspace string, isOverride bool) {
+	if baseName == "override" || strings.HasSuffix(baseName, "_override") {
+		return "", true
+	}
+	if workspace, ok := strings.CutPrefix(baseName, "override."); ok && workspace != "" {
+		return workspace, true
+	}
+	if i := strings.LastIndex(baseName, "_override."); i >= 0 && i+len("_override.") < len(baseName) {
+		return baseName[i+len("_override."):], true
+	}
+	return "", false
+}
+
+// workspaceOverrideFiles returns the override files from the given paths that
+// apply when the given workspace is selected: all of the override files that
+// are not workspace-specific, followed by the ones specific to the workspace,
+// which therefore take precedence over the others.
+func workspaceOverrideFiles(paths []string, workspace string) []string {
+	var general, specific []string
+	for _, p := range paths {
+		name := filepath.Base(p)
+		fileWorkspace, _ := overrideFileWorkspace(name[:len(name)-len(fileExt(name))])
+		switch fileWorkspace {
+		case "":
+			general = append(general, p)
+		case workspace:
+			specific = append(specific, p)
+		default:
+			log.Printf("[TRACE] workspaceOverrideFiles: ignoring %s, which is specific to workspace %q", p, fileWorkspace)
+		}
+	}
+	return append(general, specific...)
+}
+
 // filterTfPathsWithTofuAlternatives filters out .tf files if they have an
 // alternative .tofu file with the same name.
 // For example, if there are both 'resources.tf.json' and
@@ -325,6 +385,8 @@ func tfFileExt(path string) string {
 		return tfExt
 	case strings.HasSuffix(path, tfJSONExt):
 		return tfJSONExt
+	case strings.HasSuffix(path, tfJSONCExt):
+		return tfJSONCExt
 	case strings.HasSuffix(path, tfTestExt):
 		return tfTestExt
 	case strings.HasSuffix(path, tfTestJSONExt):
@@ -342,6 +404,10 @@ func tofuFileExt(path string) string {
 		return tofuExt
 	case strings.HasSuffix(path, tofuJSONExt):
 		return tofuJSONExt
+	case strings.HasSuffix(path, tofuJSONCExt):
+		return tofuJSONCExt
+	case strings.HasSuffix(path, tofuYAMLExt):
+		return tofuYAMLExt
 	case strings.HasSuffix(path, tofuTestExt):
 		return tofuTestExt
 	case strings.HasSuffix(path, tofuTestJSONExt):
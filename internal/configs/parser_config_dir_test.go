@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/zclconf/go-cty/cty"
@@ -320,6 +321,64 @@ func TestParserLoadConfigDirWithTests_TofuFiles(t *testing.T) {
 	}
 }
 
+func TestParserLoadConfigDir_workspaceOverrides(t *testing.T) {
+	tests := map[string]struct {
+		workspace     string
+		wantA         string
+		wantB         string
+		wantOverrides []string
+	}{
+		"default": {
+			workspace:     "default",
+			wantA:         "override",
+			wantB:         "primary",
+			wantOverrides: []string{"b_override.tf"},
+		},
+		"prod": {
+			// The workspace-specific override file takes precedence over the
+			// general one, even though its name sorts first.
+			workspace:     "prod",
+			wantA:         "prod",
+			wantB:         "primary",
+			wantOverrides: []string{"b_override.tf", "a_override.prod.tf"},
+		},
+		"staging": {
+			workspace:     "staging",
+			wantA:         "override",
+			wantB:         "staging",
+			wantOverrides: []string{"b_override.tf", "override.staging.tf"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := NewParser(nil)
+			dir := "testdata/valid-modules/override-workspace"
+			call := NewStaticModuleCall(addrs.RootModule, nil, dir, test.workspace)
+
+			mod, diags := parser.LoadConfigDir(dir, call)
+			assertNoDiagnostics(t, diags)
+
+			if got := mod.Variables["a"].Default.AsString(); got != test.wantA {
+				t.Errorf("wrong default for a %q; want %q", got, test.wantA)
+			}
+			if got := mod.Variables["b"].Default.AsString(); got != test.wantB {
+				t.Errorf("wrong default for b %q; want %q", got, test.wantB)
+			}
+
+			_, overrides, diags := parser.ModuleConfigFiles(dir, call)
+			assertNoDiagnostics(t, diags)
+			var got []string
+			for _, path := range overrides {
+				got = append(got, filepath.Base(path))
+			}
+			if diff := cmp.Diff(test.wantOverrides, got); diff != "" {
+				t.Errorf("wrong override files\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestIsEmptyDir(t *testing.T) {
 	val, err := IsEmptyDir(filepath.Join("testdata", "valid-files"))
 	if err != nil {
@@ -8,10 +8,12 @@ package configs
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -106,12 +108,90 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			"Invalid data resource lifecycle argument",
 			`The lifecycle argument "ignore_changes" is defined only for managed resources ("resource" blocks), and is not valid for data resources.`,
 		},
+		{
+			"invalid-files/data-resource-hook.tf",
+			hcl.DiagError,
+			"Invalid data resource lifecycle block",
+			`Lifecycle hooks are defined only for managed resources ("resource" blocks), and are not valid for data resources.`,
+		},
+		{
+			"invalid-files/data-resource-retry.tf",
+			hcl.DiagError,
+			"Invalid data resource lifecycle block",
+			`The lifecycle block type "retry" is defined only for managed resources ("resource" blocks), and is not valid for data resources.`,
+		},
+		{
+			"invalid-files/resource-mask-in-output-invalid.tf",
+			hcl.DiagError,
+			"Invalid expression",
+			"A single static variable reference is required: only attribute access and indexing with constant keys. No calculations, function calls, template expressions, etc are allowed here.",
+		},
+		{
+			"invalid-files/resource-timeouts-invalid.tf",
+			hcl.DiagError,
+			"Invalid timeout",
+			`The "create" timeout must be a positive duration, such as "30s", "10m" or "1h30m".`,
+		},
+		{
+			"invalid-files/resource-retry-invalid-pattern.tf",
+			hcl.DiagError,
+			"Invalid error pattern",
+			"The pattern \"(\" is not a valid regular expression: error parsing regexp: missing closing ): `(`.",
+		},
+		{
+			"invalid-files/resource-hook-invalid-event.tf",
+			hcl.DiagError,
+			"Invalid hook event",
+			`The hook event "post_read" is not supported. The supported events are post_create, post_update and pre_destroy.`,
+		},
+		{
+			"invalid-files/resource-defer-read.tf",
+			hcl.DiagError,
+			"Invalid resource lifecycle argument",
+			`The lifecycle argument "defer_read_until_apply" is defined only for data resources ("data" blocks), and is not valid for managed resources.`,
+		},
+		{
+			"invalid-files/module-sensitive-outputs-invalid.tf",
+			hcl.DiagError,
+			"Invalid sensitive_outputs element",
+			`Each element of sensitive_outputs must be the name of an output value of the child module, given as a string.`,
+		},
+		{
+			"invalid-files/yaml-merge-key.tofu.yaml",
+			hcl.DiagError,
+			"Unsupported YAML merge key",
+			`Merge keys are not supported in configuration files. Use an alias to reuse a whole value instead.`,
+		},
+		{
+			"invalid-files/check-invalid-severity.tf",
+			hcl.DiagError,
+			"Invalid check severity",
+			`The severity of a check block must be "error", "warning", or "info", not "fatal".`,
+		},
+		{
+			"invalid-files/moved-wildcard-mismatch.tf",
+			hcl.DiagError,
+			`Invalid "moved" addresses`,
+			`The "from" and "to" addresses must use the same number of [*] wildcards, so that each wildcard in "to" can take the instance key matched by the corresponding wildcard in "from".`,
+		},
 		{
 			"invalid-files/variable-type-unknown.tf",
 			hcl.DiagError,
 			"Invalid type specification",
 			`The keyword "notatype" is not a valid type specification.`,
 		},
+		{
+			"invalid-files/output-type-unknown.tf",
+			hcl.DiagError,
+			"Invalid type specification",
+			`The keyword "notatype" is not a valid type specification.`,
+		},
+		{
+			"invalid-files/function-invalid-reference.tf",
+			hcl.DiagError,
+			"Invalid reference in function result",
+			`The result of function "name" can refer only to the function's parameters, so "var" is not allowed here.`,
+		},
 		{
 			"invalid-files/unexpected-attr.tf",
 			hcl.DiagError,
@@ -130,6 +210,12 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			`Invalid combination of "count" and "for_each"`,
 			`The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of resources to be created.`,
 		},
+		{
+			"invalid-files/resource-count-and-enabled.tf",
+			hcl.DiagError,
+			`Invalid combination of "count" and "enabled"`,
+			`The "count" meta-argument and the "enabled" lifecycle argument are mutually-exclusive. Use "enabled" to declare either a single instance or none at all, or "count" to declare any number of instances.`,
+		},
 		{
 			"invalid-files/data-count-and-for_each.tf",
 			hcl.DiagError,
@@ -319,3 +405,141 @@ func TestParserLoadConfigFileError(t *testing.T) {
 		})
 	}
 }
+
+func TestParserLoadConfigFile_ignoreChangesPatterns(t *testing.T) {
+	tests := map[string][]string{
+		"resources-ignorechanges-patterns.tf": {
+			`tags["kubernetes.io/*"]`,
+			`ebs_block_device[*].tags`,
+			`network_interface[*].description`,
+		},
+		"resources-ignorechanges-patterns.tf.json": {
+			`tags["kubernetes.io/*"]`,
+			`ebs_block_device[*].tags`,
+		},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(filepath.Join("testdata/valid-files", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := testParser(map[string]string{
+				name: string(src),
+			})
+
+			file, diags := parser.LoadConfigFile(name)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+
+			var got []string
+			for _, traversal := range file.ManagedResources[0].Managed.IgnoreChanges {
+				var b strings.Builder
+				for _, step := range traversal {
+					switch step := step.(type) {
+					case hcl.TraverseAttr:
+						if b.Len() > 0 {
+							b.WriteString(".")
+						}
+						b.WriteString(step.Name)
+					case hcl.TraverseIndex:
+						fmt.Fprintf(&b, "[%q]", step.Key.AsString())
+					case hcl.TraverseSplat:
+						b.WriteString("[*]")
+					}
+				}
+				got = append(got, b.String())
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong ignore_changes\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserLoadConfigFile_timeoutsRetry(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata/valid-files", "resource-lifecycle-timeouts-retry.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := testParser(map[string]string{
+		"main.tf": string(src),
+	})
+
+	file, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	r := file.ManagedResources[0].Managed
+	if r.Timeouts == nil {
+		t.Fatal("resource has no timeouts")
+	}
+	if got, want := r.Timeouts.Create, 30*time.Minute; got != want {
+		t.Errorf("wrong create timeout %s; want %s", got, want)
+	}
+	if got, want := r.Timeouts.Update, time.Duration(0); got != want {
+		t.Errorf("wrong update timeout %s; want %s", got, want)
+	}
+	if got, want := r.Timeouts.Delete, time.Hour; got != want {
+		t.Errorf("wrong delete timeout %s; want %s", got, want)
+	}
+
+	if r.Retry == nil {
+		t.Fatal("resource has no retry settings")
+	}
+	if got, want := r.Retry.Attempts, 3; got != want {
+		t.Errorf("wrong attempts %d; want %d", got, want)
+	}
+	for msg, want := range map[string]bool{
+		"RequestLimitExceeded: Request limit exceeded.": true,
+		"Error: Throttling: Rate exceeded":              true,
+		"InvalidAMIID.NotFound":                         false,
+	} {
+		if got := r.Retry.Retryable(msg); got != want {
+			t.Errorf("wrong result for %q: %t; want %t", msg, got, want)
+		}
+	}
+}
+
+func TestParserLoadConfigFile_maskInOutput(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata/valid-files", "resource-lifecycle-mask-in-output.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := testParser(map[string]string{
+		"main.tf": string(src),
+	})
+
+	file, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	tests := map[string]struct {
+		r    *Resource
+		want []string
+	}{
+		"data resource": {
+			file.DataResources[0],
+			[]string{"rendered"},
+		},
+		"managed resource": {
+			file.ManagedResources[0],
+			[]string{"user_data", `tags["secret"]`},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, traversal := range test.r.MaskInOutput {
+				got = append(got, string(traversal.SourceRange().SliceBytes(src)))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// jsoncToJSON translates a configuration file written in the JSON syntax with
+// comments and trailing commas into plain JSON, so that it can be parsed in
+// the same way as a .tf.json file.
+//
+// Both "//" line comments and "/* */" block comments are supported. Each
+// comment and trailing comma is replaced by the same number of spaces, with
+// any newlines kept, so that every other byte stays at the same offset and
+// source ranges in diagnostics refer to the original file.
+func jsoncToJSON(src []byte, filename string) ([]byte, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	ret := bytes.Clone(src)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if ret[i] != '\n' && ret[i] != '\r' {
+				ret[i] = ' '
+			}
+		}
+	}
+
+	// First we remove the comments, so that looking for trailing commas
+	// below only needs to skip whitespace.
+	inString := false
+	for i := 0; i < len(ret); i++ {
+		c := ret[i]
+		switch {
+		case inString:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(ret) && ret[i+1] == '/':
+			end := bytes.IndexByte(ret[i:], '\n')
+			if end < 0 {
+				end = len(ret) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case c == '/' && i+1 < len(ret) && ret[i+1] == '*':
+			end := bytes.Index(ret[i+2:], []byte("*/"))
+			if end < 0 {
+				rng := jsoncRange(src, filename, i)
+				return nil, diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unterminated comment",
+					Detail:   `This block comment has no closing "*/".`,
+					Subject:  &rng,
+				})
+			}
+			blank(i, i+2+end+2)
+			i += 2 + end + 1
+		}
+	}
+
+	inString = false
+	for i := 0; i < len(ret); i++ {
+		c := ret[i]
+		switch {
+		case inString:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			next := i + 1
+			for next < len(ret) && isJSONWhitespace(ret[next]) {
+				next++
+			}
+			if next < len(ret) && (ret[next] == '}' || ret[next] == ']') {
+				ret[i] = ' '
+			}
+		}
+	}
+
+	return ret, diags
+}
+
+func isJSONWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// jsoncRange returns a zero-length range at the given byte offset in src.
+func jsoncRange(src []byte, filename string, offset int) hcl.Range {
+	pos := hcl.InitialPos
+	for _, c := range src[:offset] {
+		if c == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else if c&0xC0 != 0x80 {
+			// Only the first byte of each UTF-8 sequence starts a column.
+			pos.Column++
+		}
+	}
+	pos.Byte = offset
+	return hcl.Range{Filename: filename, Start: pos, End: pos}
+}
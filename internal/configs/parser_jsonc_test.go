@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestJSONCToJSON(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		want    string
+		wantErr string
+	}{
+		"plain JSON": {
+			`{"a": [1, 2]}`,
+			`{"a": [1, 2]}`,
+			``,
+		},
+		"line comment": {
+			"{\n  // note\n  \"a\": 1\n}",
+			"{\n         \n  \"a\": 1\n}",
+			``,
+		},
+		"block comment keeps newlines": {
+			"{/* one\ntwo */\"a\": 1}",
+			"{      \n      \"a\": 1}",
+			``,
+		},
+		"trailing commas": {
+			`{"a": [1, 2,], "b": {"c": 3, }, }`,
+			`{"a": [1, 2 ], "b": {"c": 3  }  }`,
+			``,
+		},
+		"trailing comma before comment": {
+			"{\"a\": 1, // last\n}",
+			"{\"a\": 1         \n}",
+			``,
+		},
+		"comment markers in strings": {
+			`{"a": "//x", "b": "/*y*/", "c": "\"//,]"}`,
+			`{"a": "//x", "b": "/*y*/", "c": "\"//,]"}`,
+			``,
+		},
+		"unterminated comment": {
+			"{\n  /* oops\n}",
+			``,
+			`This block comment has no closing "*/".`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := jsoncToJSON([]byte(test.src), "test.tf.jsonc")
+
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("succeeded; want error\ngot: %s", got)
+				}
+				if got := diags[0].Detail; got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				if got, want := diags[0].Subject.Start.Line, 2; got != want {
+					t.Errorf("wrong error line %d; want %d", got, want)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if string(got) != test.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParserLoadConfigFile_jsonc(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf.jsonc": `{
+  // The default comes from the platform team.
+  "variable": {
+    "region": {
+      "default": "us-west-2",
+    },
+  },
+}`,
+	})
+
+	file, diags := parser.LoadConfigFile("main.tf.jsonc")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	if got, want := len(file.Variables), 1; got != want {
+		t.Fatalf("wrong number of variables %d; want %d", got, want)
+	}
+	v := file.Variables[0]
+	if got, want := v.Default, cty.StringVal("us-west-2"); !got.RawEquals(want) {
+		t.Errorf("wrong default\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if got, want := v.DeclRange.Start.Line, 4; got != want {
+		t.Errorf("wrong declaration line %d; want %d", got, want)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlToJSON translates a configuration file written in YAML into the
+// equivalent document in the HCL JSON syntax, so that it can be decoded using
+// the same schema as .tf.json files.
+//
+// Each value in the result is placed on the same line as the corresponding
+// value in the YAML source, and at the same column where possible, so that
+// source ranges in diagnostics about the JSON document also make sense when
+// shown with the YAML source, once both are padded with alignYAMLLines.
+//
+// Anchors and aliases are allowed so that a value can be reused, but an
+// anchored value may not itself contain aliases, which keeps the size of the
+// result proportional to the size of the source. Merge keys and custom tags
+// are not supported.
+func yamlToJSON(src []byte, filename string) ([]byte, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			// A file with no content except comments is an empty module.
+			return []byte("{}"), diags
+		}
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid YAML syntax",
+			Detail:   fmt.Sprintf("The file could not be parsed as YAML: %s.", strings.TrimPrefix(err.Error(), "yaml: ")),
+			Subject:  yamlNodeRange(src, filename, &yaml.Node{}).Ptr(),
+		})
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Multiple YAML documents",
+			Detail:   "A configuration file must contain only one YAML document.",
+			Subject:  yamlNodeRange(src, filename, &extra).Ptr(),
+		})
+	}
+
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid YAML configuration",
+			Detail:   "The root of a configuration file must be a mapping.",
+			Subject:  yamlNodeRange(src, filename, root).Ptr(),
+		})
+	}
+
+	w := &yamlJSONWriter{src: src, filename: filename, line: 1, column: 1}
+	w.node(root, false)
+	if w.diags.HasErrors() {
+		return nil, w.diags
+	}
+	return w.buf.Bytes(), w.diags
+}
+
+// alignYAMLLines pads the lines of the YAML source and of the JSON document
+// translated from it by yamlToJSON with trailing spaces, so that each line
+// starts at the same byte offset in both.
+//
+// The byte offsets in source ranges of the JSON document then refer to the
+// same lines of the padded YAML source, so that diagnostic snippets taken
+// from it show the right lines. Columns still refer to the JSON document,
+// which places each value at its column in the YAML source where possible,
+// but not within flow collections, where JSON needs more space.
+func alignYAMLLines(yamlSrc, jsonSrc []byte) (paddedYAML, paddedJSON []byte) {
+	yamlLines := bytes.Split(yamlSrc, []byte("\n"))
+	jsonLines := bytes.Split(jsonSrc, []byte("\n"))
+	for i := 0; i < len(yamlLines) && i < len(jsonLines); i++ {
+		width := max(len(yamlLines[i]), len(jsonLines[i]))
+		yamlLines[i] = padLine(yamlLines[i], width)
+		jsonLines[i] = padLine(jsonLines[i], width)
+	}
+	return bytes.Join(yamlLines, []byte("\n")), bytes.Join(jsonLines, []byte("\n"))
+}
+
+func padLine(line []byte, width int) []byte {
+	if len(line) >= width {
+		return line
+	}
+	return append(line[:len(line):len(line)], bytes.Repeat([]byte(" "), width-len(line))...)
+}
+
+// yamlJSONWriter writes the JSON equivalent of a tree of YAML nodes, keeping
+// track of its position so that it can place each value in the same position
+// as in the YAML source.
+type yamlJSONWriter struct {
+	src      []byte
+	filename string
+
+	buf          bytes.Buffer
+	line, column int
+
+	diags hcl.Diagnostics
+}
+
+func (w *yamlJSONWriter) node(n *yaml.Node, inAnchor bool) {
+	if n.Anchor != "" {
+		inAnchor = true
+	}
+
+	switch n.Kind {
+	case yaml.AliasNode:
+		if inAnchor {
+			w.error(n, "Invalid YAML alias", "An anchored value cannot refer to other anchors.")
+			return
+		}
+		w.pad(n)
+		w.node(n.Alias, false)
+	case yaml.MappingNode:
+		w.pad(n)
+		w.write("{")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Kind != yaml.ScalarNode || k.ShortTag() == "!!null" {
+				w.error(k, "Invalid YAML mapping key", "Each mapping key must be a string.")
+				return
+			}
+			if k.ShortTag() == "!!merge" {
+				w.error(k, "Unsupported YAML merge key", "Merge keys are not supported in configuration files. Use an alias to reuse a whole value instead.")
+				return
+			}
+			if i > 0 {
+				w.write(",")
+			}
+			w.pad(k)
+			w.string(k.Value)
+			w.write(":")
+			w.node(v, inAnchor)
+		}
+		w.write("}")
+	case yaml.SequenceNode:
+		w.pad(n)
+		w.write("[")
+		for i, v := range n.Content {
+			if i > 0 {
+				w.write(",")
+			}
+			w.node(v, inAnchor)
+		}
+		w.write("]")
+	case yaml.ScalarNode:
+		w.pad(n)
+		w.scalar(n)
+	default:
+		w.error(n, "Invalid YAML configuration", "Unexpected YAML value.")
+	}
+}
+
+func (w *yamlJSONWriter) scalar(n *yaml.Node) {
+	switch n.ShortTag() {
+	case "!!null":
+		w.write("null")
+	case "!!bool":
+		var v bool
+		if err := n.Decode(&v); err != nil {
+			w.error(n, "Invalid YAML value", fmt.Sprintf("Invalid boolean value: %s.", err))
+			return
+		}
+		w.write(strconv.FormatBool(v))
+	case "!!int", "!!float":
+		var v float64
+		if err := n.Decode(&v); err != nil {
+			w.error(n, "Invalid YAML value", fmt.Sprintf("Invalid number: %s.", err))
+			return
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			w.error(n, "Invalid YAML value", "Infinity and NaN are not valid numbers in configuration files.")
+			return
+		}
+		if n.ShortTag() == "!!int" && yamlDecimalIntRe.MatchString(n.Value) {
+			// Decimal integers are kept as written, so that large values
+			// don't lose precision.
+			w.write(n.Value)
+			return
+		}
+		w.write(strconv.FormatFloat(v, 'g', -1, 64))
+	case "!!str", "!!timestamp", "!!binary":
+		// Timestamps and binary data have no JSON equivalent, so they are
+		// kept as the strings they were written as.
+		w.string(n.Value)
+	default:
+		w.error(n, "Unsupported YAML tag", fmt.Sprintf("The tag %q is not supported in configuration files.", n.Tag))
+	}
+}
+
+var yamlDecimalIntRe = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+
+func (w *yamlJSONWriter) string(s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // can't fail for a string
+	w.write(strings.TrimSuffix(buf.String(), "\n"))
+}
+
+// pad writes whitespace to move to the position of the given node in the
+// YAML source, if that position is not already behind us.
+func (w *yamlJSONWriter) pad(n *yaml.Node) {
+	if n.Line > w.line {
+		w.buf.WriteString(strings.Repeat("\n", n.Line-w.line))
+		w.line, w.column = n.Line, 1
+	}
+	if n.Line == w.line && n.Column > w.column {
+		w.buf.WriteString(strings.Repeat(" ", n.Column-w.column))
+		w.column = n.Column
+	}
+}
+
+func (w *yamlJSONWriter) write(s string) {
+	w.buf.WriteString(s)
+	w.column += utf8.RuneCountInString(s)
+}
+
+func (w *yamlJSONWriter) error(n *yaml.Node, summary, detail string) {
+	w.diags = w.diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  summary,
+		Detail:   detail,
+		Subject:  yamlNodeRange(w.src, w.filename, n).Ptr(),
+	})
+}
+
+// yamlNodeRange returns a zero-length range at the start of the given node
+// in the YAML source.
+func yamlNodeRange(src []byte, filename string, n *yaml.Node) hcl.Range {
+	if n.Line == 0 {
+		return hcl.Range{Filename: filename, Start: hcl.InitialPos, End: hcl.InitialPos}
+	}
+	pos := hcl.Pos{Line: n.Line, Column: n.Column}
+	line, offset := 1, 0
+	for line < n.Line && offset < len(src) {
+		i := bytes.IndexByte(src[offset:], '\n')
+		if i < 0 {
+			offset = len(src)
+			break
+		}
+		offset += i + 1
+		line++
+	}
+	for col := 1; col < n.Column && offset < len(src); col++ {
+		_, size := utf8.DecodeRune(src[offset:])
+		offset += size
+	}
+	pos.Byte = offset
+	return hcl.Range{Filename: filename, Start: pos, End: pos}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		want    string
+		wantErr string
+	}{
+		"empty": {
+			"# nothing here\n",
+			`{}`,
+			``,
+		},
+		"nested mappings keep their lines": {
+			"a:\n  b: 1\n  c: two\nd: [x, y]\n",
+			"{\"a\":\n  {\"b\":1,\n  \"c\":\"two\"},\n\"d\":[\"x\",\"y\"]}",
+			``,
+		},
+		"scalars": {
+			"n: ~\nb: false\ni: 0x1F\nf: 1.5\nbig: 12345678901234567890\ns: '007'\nt: 2001-12-14\n",
+			"{\"n\":null,\n\"b\":false,\n\"i\":31,\n\"f\":1.5,\n\"big\":12345678901234567890,\n\"s\":\"007\",\n\"t\":\"2001-12-14\"}",
+			``,
+		},
+		"block scalar": {
+			"s: |\n  line one\n  line two\nn: 1\n",
+			"{\"s\":\"line one\\nline two\\n\",\n\n\n\"n\":1}",
+			``,
+		},
+		"alias": {
+			"a: &x [1, 2]\nb: *x\n",
+			"{\"a\":[ 1, 2],\n\"b\":[1,2]}",
+			``,
+		},
+		"alias within anchor": {
+			"a: &x [1]\nb: &y [*x]\n",
+			``,
+			`An anchored value cannot refer to other anchors.`,
+		},
+		"merge key": {
+			"a: &x {k: v}\nb:\n  <<: *x\n",
+			``,
+			`Merge keys are not supported in configuration files. Use an alias to reuse a whole value instead.`,
+		},
+		"custom tag": {
+			"a: !secret foo\n",
+			``,
+			`The tag "!secret" is not supported in configuration files.`,
+		},
+		"infinity": {
+			"a: .inf\n",
+			``,
+			`Infinity and NaN are not valid numbers in configuration files.`,
+		},
+		"root is not a mapping": {
+			"- a\n- b\n",
+			``,
+			`The root of a configuration file must be a mapping.`,
+		},
+		"multiple documents": {
+			"a: 1\n---\nb: 2\n",
+			``,
+			`A configuration file must contain only one YAML document.`,
+		},
+		"syntax error": {
+			"a: [1\n",
+			``,
+			`The file could not be parsed as YAML: line 1: did not find expected ',' or ']'.`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := yamlToJSON([]byte(test.src), "test.tofu.yaml")
+
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("succeeded; want error\ngot: %s", got)
+				}
+				if got := diags[0].Detail; got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if string(got) != test.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParserLoadConfigFile_yaml(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata/valid-files", "yaml-syntax.tofu.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := testParser(map[string]string{
+		"main.tofu.yaml": string(src),
+	})
+
+	file, diags := parser.LoadConfigFile("main.tofu.yaml")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	if got, want := len(file.Variables), 1; got != want {
+		t.Fatalf("wrong number of variables %d; want %d", got, want)
+	}
+	if got, want := file.Variables[0].Default, cty.StringVal("us-west-2"); !got.RawEquals(want) {
+		t.Errorf("wrong default\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if got, want := len(file.Locals), 2; got != want {
+		t.Errorf("wrong number of locals %d; want %d", got, want)
+	}
+	if got, want := len(file.ManagedResources), 1; got != want {
+		t.Fatalf("wrong number of resources %d; want %d", got, want)
+	}
+	r := file.ManagedResources[0]
+	if got, want := r.Addr().String(), "aws_s3_bucket.example"; got != want {
+		t.Errorf("wrong resource address %q; want %q", got, want)
+	}
+	if r.Count == nil {
+		t.Errorf("resource has no count")
+	}
+	// Source ranges refer to the lines in the YAML source.
+	if got, want := r.DeclRange.Start.Line, 16; got != want {
+		t.Errorf("wrong declaration line %d; want %d", got, want)
+	}
+	if got, want := len(file.Outputs), 1; got != want {
+		t.Errorf("wrong number of outputs %d; want %d", got, want)
+	}
+}
+
+func TestParserLoadConfigFile_yamlDiagnosticRange(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tofu.yaml": "# A variable with a mistake.\nvariable:\n  region: {type: string,\n    bogus: true}\n",
+	})
+
+	_, diags := parser.LoadConfigFile("main.tofu.yaml")
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want an error for the unsupported argument")
+	}
+	subject := diags[0].Subject
+	if subject == nil {
+		t.Fatalf("diagnostic has no subject: %s", diags[0])
+	}
+	if got, want := subject.Start.Line, 4; got != want {
+		t.Errorf("wrong line %d; want %d", got, want)
+	}
+
+	// The byte offsets refer to the same line of the source that snippets
+	// are taken from, even though the translated JSON document is wider
+	// than the YAML source on the earlier lines.
+	src := parser.Sources()["main.tofu.yaml"].Bytes
+	if got, want := string(src[subject.Start.Byte:]), "bogus: true}"; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong source at the start of the subject\ngot:  %q\nwant: %q", got, want)
+	}
+}
@@ -25,6 +25,15 @@ type RequiredProvider struct {
 	Requirement VersionConstraint
 	DeclRange   hcl.Range
 	Aliases     []addrs.LocalProviderConfig
+
+	// Origin is the address that the provider's packages are installed from,
+	// such as a copy of the provider in an internal registry, if it differs
+	// from Type. The provider is still identified by Type everywhere else,
+	// including in the dependency lock file.
+	//
+	// Origin is the zero value if the provider is installed from Type.
+	Origin      addrs.Provider
+	OriginRange hcl.Range
 }
 
 type RequiredProviders struct {
@@ -176,6 +185,33 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (*RequiredProviders, hcl.Dia
 				rp.Source = source.AsString()
 				rp.Type = fqn
 
+			case "origin":
+				origin, err := kv.Value.Value(nil)
+				if err != nil || !origin.Type().Equals(cty.String) {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid origin",
+						Detail:   "Origin must be specified as a string.",
+						Subject:  kv.Value.Range().Ptr(),
+					})
+					continue
+				}
+
+				fqn, originDiags := addrs.ParseProviderSourceString(origin.AsString())
+				if originDiags.HasErrors() {
+					hclDiags := originDiags.ToHCL()
+					for _, diag := range hclDiags {
+						if diag.Subject == nil {
+							diag.Subject = kv.Value.Range().Ptr()
+						}
+					}
+					diags = append(diags, hclDiags...)
+					continue
+				}
+
+				rp.Origin = fqn
+				rp.OriginRange = kv.Value.Range()
+
 			case "configuration_aliases":
 				exprs, listDiags := hcl.ExprList(kv.Value)
 				if listDiags.HasErrors() {
@@ -218,7 +254,7 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (*RequiredProviders, hcl.Dia
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Invalid required_providers object",
-					Detail:   `required_providers objects can only contain "version", "source" and "configuration_aliases" attributes. To configure a provider, use a "provider" block.`,
+					Detail:   `required_providers objects can only contain "version", "source", "origin" and "configuration_aliases" attributes. To configure a provider, use a "provider" block.`,
 					Subject:  kv.Key.Range().Ptr(),
 				})
 				break LOOP
@@ -246,6 +282,23 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (*RequiredProviders, hcl.Dia
 			}
 		}
 
+		if !rp.Origin.IsZero() {
+			switch {
+			case rp.Origin == rp.Type:
+				// An origin that is the same as the source is redundant, so
+				// we just ignore it.
+				rp.Origin = addrs.Provider{}
+			case rp.Type.IsBuiltIn() || rp.Origin.IsBuiltIn():
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid origin",
+					Detail:   "Built-in providers are not installed, and so they cannot have an origin.",
+					Subject:  rp.OriginRange.Ptr(),
+				})
+				continue
+			}
+		}
+
 		ret.RequiredProviders[rp.Name] = rp
 	}
 
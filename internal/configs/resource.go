@@ -29,6 +29,24 @@ type Resource struct {
 	Count   hcl.Expression
 	ForEach hcl.Expression
 
+	// Enabled is the expression given for the "enabled" lifecycle argument,
+	// or nil if it isn't set. A resource that sets it has either a single
+	// instance with no key or no instances at all, depending on its value.
+	Enabled hcl.Expression
+
+	// DeferReadUntilApply is set by the "defer_read_until_apply" lifecycle
+	// argument, which is valid only for data resources. If it's true then
+	// the data resource is always read during the apply phase, after the
+	// resources it depends on, rather than during planning.
+	DeferReadUntilApply    bool
+	DeferReadUntilApplySet bool
+
+	// MaskInOutput are the attributes given in the "mask_in_output" lifecycle
+	// argument. Their values are hidden wherever changes to the resource are
+	// shown in plan output, but unlike sensitive values they are not marked,
+	// and so they can be used elsewhere in the configuration as normal.
+	MaskInOutput []hcl.Traversal
+
 	ProviderConfigRef *ProviderConfigRef
 	Provider          addrs.Provider
 
@@ -68,6 +86,15 @@ type ManagedResource struct {
 	Connection   *Connection
 	Provisioners []*Provisioner
 
+	// Hooks are the "hook" blocks in the resource's lifecycle block, in the
+	// order they were declared.
+	Hooks []*ResourceHook
+
+	// Timeouts and Retry are the "timeouts" and "retry" blocks in the
+	// resource's lifecycle block, or nil if they are not present.
+	Timeouts *ResourceTimeouts
+	Retry    *ResourceRetry
+
 	CreateBeforeDestroy bool
 	PreventDestroy      bool
 	IgnoreChanges       []hcl.Traversal
@@ -198,6 +225,26 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 			lcContent, lcDiags := block.Body.Content(resourceLifecycleBlockSchema)
 			diags = append(diags, lcDiags...)
 
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				r.Enabled = attr.Expr
+				diags = append(diags, checkEnabledRepetition(attr, r.Count, r.ForEach)...)
+			}
+
+			if attr, exists := lcContent.Attributes["defer_read_until_apply"]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid resource lifecycle argument",
+					Detail:   "The lifecycle argument \"defer_read_until_apply\" is defined only for data resources (\"data\" blocks), and is not valid for managed resources.",
+					Subject:  attr.NameRange.Ptr(),
+				})
+			}
+
+			if attr, exists := lcContent.Attributes["mask_in_output"]; exists {
+				traversals, travDiags := decodeMaskInOutput(attr)
+				diags = append(diags, travDiags...)
+				r.MaskInOutput = traversals
+			}
+
 			if attr, exists := lcContent.Attributes["create_before_destroy"]; exists {
 				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.CreateBeforeDestroy)
 				diags = append(diags, valDiags...)
@@ -259,7 +306,7 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 						expr, shimDiags := shimTraversalInString(expr, false)
 						diags = append(diags, shimDiags...)
 
-						traversal, travDiags := hcl.RelTraversalForExpr(expr)
+						traversal, travDiags := decodeIgnoreChangesTraversal(expr)
 						diags = append(diags, travDiags...)
 						if len(traversal) != 0 {
 							r.Managed.IgnoreChanges = append(r.Managed.IgnoreChanges, traversal)
@@ -294,6 +341,38 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 					case "postcondition":
 						r.Postconditions = append(r.Postconditions, cr)
 					}
+				case "hook":
+					h, moreDiags := decodeResourceHookBlock(block)
+					diags = append(diags, moreDiags...)
+					if h != nil {
+						r.Managed.Hooks = append(r.Managed.Hooks, h)
+					}
+				case "timeouts":
+					if r.Managed.Timeouts != nil {
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Duplicate timeouts block",
+							Detail:   fmt.Sprintf("This resource already has a timeouts block at %s.", r.Managed.Timeouts.DeclRange),
+							Subject:  block.DefRange.Ptr(),
+						})
+						continue
+					}
+					t, moreDiags := decodeResourceTimeoutsBlock(block)
+					diags = append(diags, moreDiags...)
+					r.Managed.Timeouts = t
+				case "retry":
+					if r.Managed.Retry != nil {
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Duplicate retry block",
+							Detail:   fmt.Sprintf("This resource already has a retry block at %s.", r.Managed.Retry.DeclRange),
+							Subject:  block.DefRange.Ptr(),
+						})
+						continue
+					}
+					rr, moreDiags := decodeResourceRetryBlock(block)
+					diags = append(diags, moreDiags...)
+					r.Managed.Retry = rr
 				default:
 					// The cases above should be exhaustive for all block types
 					// defined in the lifecycle schema, so this shouldn't happen.
@@ -498,10 +577,30 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 			lcContent, lcDiags := block.Body.Content(resourceLifecycleBlockSchema)
 			diags = append(diags, lcDiags...)
 
-			// All of the attributes defined for resource lifecycle are for
-			// managed resources only, so we can emit a common error message
-			// for any given attributes that HCL accepted.
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				r.Enabled = attr.Expr
+				diags = append(diags, checkEnabledRepetition(attr, r.Count, r.ForEach)...)
+			}
+
+			if attr, exists := lcContent.Attributes["defer_read_until_apply"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.DeferReadUntilApply)
+				diags = append(diags, valDiags...)
+				r.DeferReadUntilApplySet = true
+			}
+
+			if attr, exists := lcContent.Attributes["mask_in_output"]; exists {
+				traversals, travDiags := decodeMaskInOutput(attr)
+				diags = append(diags, travDiags...)
+				r.MaskInOutput = traversals
+			}
+
+			// All of the other attributes defined for resource lifecycle are
+			// for managed resources only, so we can emit a common error
+			// message for any given attributes that HCL accepted.
 			for name, attr := range lcContent.Attributes {
+				if name == "enabled" || name == "defer_read_until_apply" || name == "mask_in_output" {
+					continue
+				}
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Invalid data resource lifecycle argument",
@@ -525,6 +624,20 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 					case "postcondition":
 						r.Postconditions = append(r.Postconditions, cr)
 					}
+				case "hook":
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid data resource lifecycle block",
+						Detail:   "Lifecycle hooks are defined only for managed resources (\"resource\" blocks), and are not valid for data resources.",
+						Subject:  block.DefRange.Ptr(),
+					})
+				case "timeouts", "retry":
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid data resource lifecycle block",
+						Detail:   fmt.Sprintf("The lifecycle block type %q is defined only for managed resources (\"resource\" blocks), and is not valid for data resources.", block.Type),
+						Subject:  block.DefRange.Ptr(),
+					})
 				default:
 					// The cases above should be exhaustive for all block types
 					// defined in the lifecycle schema, so this shouldn't happen.
@@ -547,6 +660,73 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 	return r, diags
 }
 
+// decodeIgnoreChangesTraversal returns the relative traversal given by an
+// element of ignore_changes.
+//
+// As well as plain traversals, an element can use splat expressions such as
+// rule[*].description to refer to all of the elements of a list or map. Each
+// splat becomes a hcl.TraverseSplat step with no Each traversal of its own,
+// followed by the steps that apply to each element.
+func decodeIgnoreChangesTraversal(expr hcl.Expression) (hcl.Traversal, hcl.Diagnostics) {
+	if hcljson.IsJSONExpression(expr) {
+		// The JSON syntax has no splat expressions, so we parse the string
+		// as a native syntax expression instead.
+		var diags hcl.Diagnostics
+		expr, diags = hcl2shim.ConvertJSONExpressionToHCL(expr)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	switch expr := expr.(type) {
+	case *hclsyntax.SplatExpr:
+		traversal, diags := decodeIgnoreChangesTraversal(expr.Source)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		each, eachDiags := decodeIgnoreChangesTraversal(expr.Each)
+		diags = append(diags, eachDiags...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		traversal = append(traversal, hcl.TraverseSplat{SrcRange: expr.MarkerRange})
+		return append(traversal, each...), diags
+	case *hclsyntax.RelativeTraversalExpr:
+		traversal, diags := decodeIgnoreChangesTraversal(expr.Source)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return append(traversal, expr.Traversal...), diags
+	case *hclsyntax.AnonSymbolExpr:
+		// This is the element placeholder at the start of the traversal
+		// that a splat expression applies to each element.
+		return nil, nil
+	default:
+		return hcl.RelTraversalForExpr(expr)
+	}
+}
+
+// decodeMaskInOutput decodes the "mask_in_output" lifecycle argument, which is
+// a list of relative traversals to attributes of the resource:
+//
+//	mask_in_output = [user_data, tags["secret"]]
+func decodeMaskInOutput(attr *hcl.Attribute) ([]hcl.Traversal, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(attr.Expr)
+
+	var traversals []hcl.Traversal
+	for _, expr := range exprs {
+		expr, shimDiags := shimTraversalInString(expr, false)
+		diags = append(diags, shimDiags...)
+
+		traversal, travDiags := hcl.RelTraversalForExpr(expr)
+		diags = append(diags, travDiags...)
+		if len(traversal) != 0 {
+			traversals = append(traversals, traversal)
+		}
+	}
+	return traversals, diags
+}
+
 // decodeReplaceTriggeredBy decodes and does basic validation of the
 // replace_triggered_by expressions, ensuring they only contains references to
 // a single resource, and the only extra variables are count.index or each.key.
@@ -850,6 +1030,29 @@ func (r *ProviderConfigRef) InstanceValidation(blockType string, isInstanced boo
 	return diags
 }
 
+// checkEnabledRepetition returns an error if the given "enabled" lifecycle
+// argument is used together with either of the repetition meta-arguments,
+// which would make the number of instances ambiguous.
+func checkEnabledRepetition(enabled *hcl.Attribute, count, forEach hcl.Expression) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	var other string
+	switch {
+	case count != nil:
+		other = "count"
+	case forEach != nil:
+		other = "for_each"
+	default:
+		return diags
+	}
+	diags = append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf(`Invalid combination of %q and "enabled"`, other),
+		Detail:   fmt.Sprintf(`The %q meta-argument and the "enabled" lifecycle argument are mutually-exclusive. Use "enabled" to declare either a single instance or none at all, or %q to declare any number of instances.`, other, other),
+		Subject:  &enabled.NameRange,
+	})
+	return diags
+}
+
 var commonResourceAttributes = []hcl.AttributeSchema{
 	{
 		Name: "count",
@@ -896,6 +1099,9 @@ var resourceLifecycleBlockSchema = &hcl.BodySchema{
 	// than that. We deal with that after decoding so that we can return
 	// more specific error messages than HCL would typically return itself.
 	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "enabled",
+		},
 		{
 			Name: "create_before_destroy",
 		},
@@ -908,9 +1114,18 @@ var resourceLifecycleBlockSchema = &hcl.BodySchema{
 		{
 			Name: "replace_triggered_by",
 		},
+		{
+			Name: "defer_read_until_apply",
+		},
+		{
+			Name: "mask_in_output",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
 		{Type: "postcondition"},
+		{Type: "hook", LabelNames: []string{"event"}},
+		{Type: "timeouts"},
+		{Type: "retry"},
 	},
 }
@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ResourceHookProvisioner is the type of the provisioner that runs the
+// commands of resource lifecycle hooks. The body of a "hook" block accepts
+// the same arguments as a provisioner of this type.
+const ResourceHookProvisioner = "local-exec"
+
+// ResourceHookEvent is the event that makes a resource lifecycle hook run.
+type ResourceHookEvent string
+
+const (
+	// ResourceHookPostCreate hooks run after each new object of the resource
+	// is created, including the replacement objects of a replace action.
+	ResourceHookPostCreate ResourceHookEvent = "post_create"
+
+	// ResourceHookPostUpdate hooks run after each existing object of the
+	// resource is updated in-place.
+	ResourceHookPostUpdate ResourceHookEvent = "post_update"
+
+	// ResourceHookPreDestroy hooks run before each object of the resource is
+	// destroyed, including the objects being replaced by a replace action.
+	ResourceHookPreDestroy ResourceHookEvent = "pre_destroy"
+)
+
+// ResourceHook represents a "hook" block within the "lifecycle" block of a
+// managed resource, which runs a command on the machine where OpenTofu is
+// running when one of the resource's objects is changed.
+type ResourceHook struct {
+	Event     ResourceHookEvent
+	Config    hcl.Body
+	OnFailure ProvisionerOnFailure
+
+	DeclRange  hcl.Range
+	EventRange hcl.Range
+}
+
+// Provisioner returns the provisioner that runs the command of the receiving
+// hook, so that hooks can be evaluated and run in the same way as the
+// provisioners of the resource.
+func (h *ResourceHook) Provisioner() *Provisioner {
+	when := ProvisionerWhenCreate
+	if h.Event == ResourceHookPreDestroy {
+		when = ProvisionerWhenDestroy
+	}
+	return &Provisioner{
+		Type:      ResourceHookProvisioner,
+		Config:    h.Config,
+		When:      when,
+		OnFailure: h.OnFailure,
+		DeclRange: h.DeclRange,
+		TypeRange: h.EventRange,
+	}
+}
+
+func decodeResourceHookBlock(block *hcl.Block) (*ResourceHook, hcl.Diagnostics) {
+	h := &ResourceHook{
+		Event:      ResourceHookEvent(block.Labels[0]),
+		OnFailure:  ProvisionerOnFailureFail,
+		DeclRange:  block.DefRange,
+		EventRange: block.LabelRanges[0],
+	}
+
+	content, config, diags := block.Body.PartialContent(resourceHookBlockSchema)
+	h.Config = config
+
+	switch h.Event {
+	case ResourceHookPostCreate, ResourceHookPostUpdate:
+	case ResourceHookPreDestroy:
+		// pre_destroy hooks are evaluated in the same way as destroy-time
+		// provisioners, so they can only refer to the object being destroyed.
+		diags = append(diags, onlySelfRefs(config)...)
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid hook event",
+			Detail:   fmt.Sprintf("The hook event %q is not supported. The supported events are post_create, post_update and pre_destroy.", h.Event),
+			Subject:  &h.EventRange,
+		})
+		return nil, diags
+	}
+
+	if attr, exists := content.Attributes["on_failure"]; exists {
+		expr, shimDiags := shimTraversalInString(attr.Expr, true)
+		diags = append(diags, shimDiags...)
+
+		switch hcl.ExprAsKeyword(expr) {
+		case "continue":
+			h.OnFailure = ProvisionerOnFailureContinue
+		case "fail":
+			h.OnFailure = ProvisionerOnFailureFail
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"on_failure\" keyword",
+				Detail:   "The \"on_failure\" argument requires one of the following keywords: continue or fail.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return h, diags
+}
+
+var resourceHookBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "on_failure"},
+	},
+}
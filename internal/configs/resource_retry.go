@@ -0,0 +1,158 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+)
+
+// ResourceTimeouts represents a "timeouts" block within the "lifecycle" block
+// of a managed resource, which limits how long OpenTofu waits for the
+// provider to apply each kind of change to one of the resource's objects.
+//
+// A zero duration means that there is no limit for that kind of change.
+type ResourceTimeouts struct {
+	Create time.Duration
+	Update time.Duration
+	Delete time.Duration
+
+	DeclRange hcl.Range
+}
+
+// ResourceRetry represents a "retry" block within the "lifecycle" block of a
+// managed resource, which makes OpenTofu repeat a change that the provider
+// failed to apply.
+type ResourceRetry struct {
+	// Attempts is the maximum number of times the change is attempted,
+	// including the first attempt.
+	Attempts int
+
+	// OnErrors are the patterns of the errors that make the change be
+	// retried. If there are none, any error makes the change be retried.
+	OnErrors []*regexp.Regexp
+
+	DeclRange hcl.Range
+}
+
+// Retryable returns true if the given error message should make a failed
+// change be retried.
+func (r *ResourceRetry) Retryable(msg string) bool {
+	if len(r.OnErrors) == 0 {
+		return true
+	}
+	for _, re := range r.OnErrors {
+		if re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeResourceTimeoutsBlock(block *hcl.Block) (*ResourceTimeouts, hcl.Diagnostics) {
+	t := &ResourceTimeouts{
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(resourceTimeoutsBlockSchema)
+
+	for _, timeout := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"create", &t.Create},
+		{"update", &t.Update},
+		{"delete", &t.Delete},
+	} {
+		name, dst := timeout.name, timeout.dst
+		attr, exists := content.Attributes[name]
+		if !exists {
+			continue
+		}
+
+		var raw string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid timeout",
+				Detail:   fmt.Sprintf("The %q timeout must be a positive duration, such as \"30s\", \"10m\" or \"1h30m\".", name),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
+		}
+		*dst = d
+	}
+
+	return t, diags
+}
+
+func decodeResourceRetryBlock(block *hcl.Block) (*ResourceRetry, hcl.Diagnostics) {
+	r := &ResourceRetry{
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(resourceRetryBlockSchema)
+
+	if attr, exists := content.Attributes["attempts"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Attempts)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && r.Attempts < 1 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid retry attempts",
+				Detail:   "The number of attempts must be at least 1.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["on_errors"]; exists {
+		var patterns []string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &patterns)
+		diags = append(diags, valDiags...)
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid error pattern",
+					Detail:   fmt.Sprintf("The pattern %q is not a valid regular expression: %s.", pattern, err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			r.OnErrors = append(r.OnErrors, re)
+		}
+	}
+
+	return r, diags
+}
+
+var resourceTimeoutsBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "create"},
+		{Name: "update"},
+		{Name: "delete"},
+	},
+}
+
+var resourceRetryBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "attempts", Required: true},
+		{Name: "on_errors"},
+	},
+}
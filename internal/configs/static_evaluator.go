@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/dynblock"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -111,9 +112,25 @@ func (s StaticEvaluator) DecodeExpression(ctx context.Context, expr hcl.Expressi
 	return diags.Extend(gohcl.DecodeValue(srcVal, expr.StartRange(), expr.Range(), val))
 }
 
+// DecodeBlock evaluates the given body using the given spec, expanding any
+// "dynamic" blocks first.
 func (s StaticEvaluator) DecodeBlock(ctx context.Context, body hcl.Body, spec hcldec.Spec, ident StaticIdentifier) (cty.Value, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
+	// The for_each and labels arguments of any dynamic blocks must be
+	// evaluated before we can know what the expanded body contains.
+	expandRefs, refsDiags := lang.References(addrs.ParseRef, dynblock.ExpandVariablesHCLDec(body, spec))
+	diags = append(diags, refsDiags.ToHCL()...)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	expandCtx, ctxDiags := s.scope(ident).EvalContext(ctx, expandRefs)
+	diags = append(diags, ctxDiags.ToHCL()...)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	body = dynblock.Expand(body, expandCtx)
+
 	refs, refsDiags := lang.References(addrs.ParseRef, hcldec.Variables(body, spec))
 	diags = append(diags, refsDiags.ToHCL()...)
 	if diags.HasErrors() {
@@ -208,15 +208,15 @@ resource "foo" "bar" {}
 			diags []string
 		}{
 			{"circular", []string{
-				"eval.tf:41,2-27: Circular reference; local.circular is self referential",
+				"eval.tf:41,2-27: Circular reference; local.circular is self referential: local.circular -> local.circular",
 			}},
 			{"circular_ref", []string{
-				"eval.tf:41,2-27: Circular reference; local.circular is self referential",
+				"eval.tf:41,2-27: Circular reference; local.circular is self referential: local.circular -> local.circular",
 				"eval.tf:42,2-31: Unable to compute static value; local.circular_ref depends on local.circular which is not available",
 			}},
 			{"circular_a", []string{
 				"eval.tf:43,2-31: Unable to compute static value; local.circular_a depends on local.circular_b which is not available",
-				"eval.tf:43,2-31: Circular reference; local.circular_a is self referential",
+				"eval.tf:43,2-31: Circular reference; local.circular_a is self referential: local.circular_a -> local.circular_b -> local.circular_a",
 			}},
 		}
 		for _, local := range locals {
@@ -417,3 +417,90 @@ terraform {
 		})
 	}
 }
+
+func TestStaticEvaluator_DecodeBlockDynamic(t *testing.T) {
+	schema := &configschema.Block{
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"header": {
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"name":  {Type: cty.String, Required: true},
+						"value": {Type: cty.String, Required: true},
+					},
+				},
+				Nesting: configschema.NestingList,
+			},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		parser := testParser(map[string]string{"eval.tf": `
+locals {
+	headers = {
+		"X-Team" = "platform"
+		"X-Env"  = "prod"
+	}
+}
+
+terraform {
+	backend "dynamic" {
+		dynamic "header" {
+			for_each = local.headers
+			content {
+				name  = header.key
+				value = header.value
+			}
+		}
+	}
+}`})
+		file, fileDiags := parser.LoadConfigFile("eval.tf")
+		if fileDiags.HasErrors() {
+			t.Fatal(fileDiags)
+		}
+		mod, _ := NewModule([]*File{file}, nil, RootModuleCallForTesting(), "dir", SelectiveLoadAll)
+
+		got, diags := mod.Backend.Decode(t.Context(), schema)
+		assertNoDiagnostics(t, diags)
+
+		want := cty.ObjectVal(map[string]cty.Value{
+			"header": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"name":  cty.StringVal("X-Env"),
+					"value": cty.StringVal("prod"),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"name":  cty.StringVal("X-Team"),
+					"value": cty.StringVal("platform"),
+				}),
+			}),
+		})
+		if !got.RawEquals(want) {
+			t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+
+	t.Run("dynamic for_each", func(t *testing.T) {
+		parser := testParser(map[string]string{"eval.tf": `
+terraform {
+	backend "dynamic" {
+		dynamic "header" {
+			for_each = aws_instance.foo.tags
+			content {
+				name  = header.key
+				value = header.value
+			}
+		}
+	}
+}`})
+		file, fileDiags := parser.LoadConfigFile("eval.tf")
+		if fileDiags.HasErrors() {
+			t.Fatal(fileDiags)
+		}
+		mod, _ := NewModule([]*File{file}, nil, RootModuleCallForTesting(), "dir", SelectiveLoadAll)
+
+		_, diags := mod.Backend.Decode(t.Context(), schema)
+		assertExactDiagnostics(t, diags, []string{
+			`eval.tf:5,15-31: Dynamic value in static context; Unable to use aws_instance.foo in static context, which is required by backend.dynamic`,
+		})
+	})
+}
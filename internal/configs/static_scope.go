@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -47,12 +48,17 @@ var _ lang.Data = (*staticScopeData)(nil)
 func (s staticScopeData) scope(ident StaticIdentifier) (*lang.Scope, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
-	for _, frame := range s.stack {
+	for i, frame := range s.stack {
 		if frame.String() == ident.String() {
+			chain := make([]string, 0, len(s.stack)-i+1)
+			for _, f := range s.stack[i:] {
+				chain = append(chain, f.String())
+			}
+			chain = append(chain, ident.String())
 			return nil, diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Circular reference",
-				Detail:   fmt.Sprintf("%s is self referential", ident.String()), // TODO use stack in error message
+				Detail:   fmt.Sprintf("%s is self referential: %s", ident.String(), strings.Join(chain, " -> ")),
 				Subject:  ident.DeclRange.Ptr(),
 			})
 		}
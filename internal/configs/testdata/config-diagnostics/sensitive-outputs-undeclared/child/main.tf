@@ -0,0 +1,4 @@
+output "password" {
+  value     = "xyz"
+  sensitive = true
+}
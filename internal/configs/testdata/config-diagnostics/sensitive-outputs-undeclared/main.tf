@@ -0,0 +1,5 @@
+module "child" {
+  source = "./child"
+
+  sensitive_outputs = ["pasword"]
+}
@@ -0,0 +1,12 @@
+variable "healthy" {
+  type = bool
+}
+
+check "health" {
+  severity = "fatal"
+
+  assert {
+    condition     = var.healthy
+    error_message = "Unhealthy."
+  }
+}
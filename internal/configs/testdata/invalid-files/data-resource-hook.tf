@@ -0,0 +1,7 @@
+data "example" "example" {
+  lifecycle {
+    hook "post_create" {
+      command = "echo hello"
+    }
+  }
+}
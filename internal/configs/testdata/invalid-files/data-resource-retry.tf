@@ -0,0 +1,7 @@
+data "example" "example" {
+  lifecycle {
+    retry {
+      attempts = 2
+    }
+  }
+}
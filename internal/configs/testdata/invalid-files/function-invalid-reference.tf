@@ -0,0 +1,9 @@
+
+variable "suffix" {
+  type = string
+}
+
+function "name" {
+  params = [prefix]
+  result = "${prefix}-${var.suffix}"
+}
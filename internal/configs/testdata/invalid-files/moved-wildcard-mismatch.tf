@@ -0,0 +1,4 @@
+moved {
+  from = module.old[*].test.foo
+  to   = test.foo
+}
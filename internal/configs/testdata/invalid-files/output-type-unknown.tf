@@ -0,0 +1,4 @@
+output "bad_type" {
+  type  = notatype
+  value = "hello"
+}
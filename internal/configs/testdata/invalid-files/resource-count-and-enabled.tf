@@ -0,0 +1,7 @@
+resource "test" "foo" {
+  count = 2
+
+  lifecycle {
+    enabled = true
+  }
+}
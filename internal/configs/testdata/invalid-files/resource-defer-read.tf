@@ -0,0 +1,6 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    # defer_read_until_apply is valid only for data resources.
+    defer_read_until_apply = true
+  }
+}
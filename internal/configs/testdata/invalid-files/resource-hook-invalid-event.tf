@@ -0,0 +1,7 @@
+resource "example" "example" {
+  lifecycle {
+    hook "post_read" {
+      command = "echo hello"
+    }
+  }
+}
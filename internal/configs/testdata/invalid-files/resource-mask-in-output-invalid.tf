@@ -0,0 +1,5 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    mask_in_output = [upper(user_data)]
+  }
+}
@@ -0,0 +1,8 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    retry {
+      attempts  = 2
+      on_errors = ["("]
+    }
+  }
+}
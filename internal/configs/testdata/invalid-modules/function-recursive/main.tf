@@ -0,0 +1,10 @@
+
+function "a" {
+  params = [n]
+  result = n == 0 ? 0 : local::b(n - 1)
+}
+
+function "b" {
+  params = [n]
+  result = local::a(n)
+}
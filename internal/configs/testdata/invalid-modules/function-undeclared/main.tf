@@ -0,0 +1,5 @@
+
+function "a" {
+  params = [n]
+  result = local::missing(n)
+}
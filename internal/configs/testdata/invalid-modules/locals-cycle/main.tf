@@ -0,0 +1,8 @@
+locals {
+  a = "${local.b}-a"
+  b = upper(local.c)
+  c = [local.a][0]
+
+  # Not part of the cycle, so not reported
+  d = local.a
+}
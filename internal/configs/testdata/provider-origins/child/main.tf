@@ -0,0 +1,11 @@
+terraform {
+  required_providers {
+    test = {
+      source = "hashicorp/test"
+      origin = "registry.example.com/mirror/test"
+    }
+    null = {
+      source = "hashicorp/null"
+    }
+  }
+}
@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    test = {
+      source = "hashicorp/test"
+      origin = "registry.example.com/mirror/test"
+    }
+  }
+}
+
+module "child" {
+  source = "./child"
+}
+
+module "other" {
+  source = "./other"
+}
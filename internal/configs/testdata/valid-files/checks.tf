@@ -0,0 +1,27 @@
+variable "healthy" {
+  type = bool
+}
+
+check "health" {
+  assert {
+    condition     = var.healthy
+    error_message = "Unhealthy."
+  }
+}
+
+check "certificate" {
+  severity = "error"
+  metadata = {
+    owner   = "platform"
+    runbook = "https://example.com/runbooks/certificates"
+  }
+
+  data "tls_certificate" "example" {
+    url = "https://example.com"
+  }
+
+  assert {
+    condition     = data.tls_certificate.example.certificates[0].not_after != ""
+    error_message = "The certificate has no expiry time."
+  }
+}
@@ -0,0 +1,14 @@
+resource "aws_instance" "web" {
+}
+
+data "aws_instance" "web" {
+  instance_id = aws_instance.web.id
+
+  lifecycle {
+    defer_read_until_apply = true
+  }
+
+  depends_on = [
+    aws_instance.web,
+  ]
+}
@@ -0,0 +1,24 @@
+variable "monitoring" {
+  type    = bool
+  default = false
+}
+
+resource "aws_cloudwatch_dashboard" "main" {
+  lifecycle {
+    enabled = var.monitoring
+  }
+}
+
+data "aws_sns_topic" "alerts" {
+  lifecycle {
+    enabled = var.monitoring
+  }
+}
+
+module "monitoring" {
+  source = "./monitoring"
+
+  lifecycle {
+    enabled = var.monitoring
+  }
+}
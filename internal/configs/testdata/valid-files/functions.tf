@@ -0,0 +1,17 @@
+
+function "subnet_name" {
+  description = "Returns the conventional name for a subnet."
+  params      = [env, zone]
+  result      = "${local::prefix(env)}-${zone}"
+}
+
+function "prefix" {
+  params = [env]
+  result = lower("net-${env}")
+  export = true
+}
+
+function "constant" {
+  params = []
+  result = 1
+}
@@ -24,3 +24,13 @@ output "π" {
     pizza.cheese,
   ]
 }
+
+output "typed" {
+  type = object({
+    name = string
+    tags = optional(map(string), {})
+  })
+  value = {
+    name = "example"
+  }
+}
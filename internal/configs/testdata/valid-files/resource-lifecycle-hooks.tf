@@ -0,0 +1,14 @@
+resource "example" "example" {
+  lifecycle {
+    hook "post_create" {
+      command = "curl -sf https://${self.hostname}/healthz"
+    }
+    hook "post_update" {
+      command    = "./flush-cache.sh"
+      on_failure = continue
+    }
+    hook "pre_destroy" {
+      command = "echo destroying ${self.id}"
+    }
+  }
+}
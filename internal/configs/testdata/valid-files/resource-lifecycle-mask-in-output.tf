@@ -0,0 +1,13 @@
+data "cloudinit_config" "web" {
+  lifecycle {
+    mask_in_output = [rendered]
+  }
+}
+
+resource "aws_instance" "web" {
+  user_data = data.cloudinit_config.web.rendered
+
+  lifecycle {
+    mask_in_output = [user_data, tags["secret"]]
+  }
+}
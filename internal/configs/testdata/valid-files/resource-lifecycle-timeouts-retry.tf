@@ -0,0 +1,13 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    timeouts {
+      create = "30m"
+      delete = "1h"
+    }
+
+    retry {
+      attempts  = 3
+      on_errors = ["RequestLimitExceeded", "(?i)throttl"]
+    }
+  }
+}
@@ -0,0 +1,9 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    ignore_changes = [
+      tags["kubernetes.io/*"],
+      ebs_block_device[*].tags,
+      network_interface.*.description,
+    ]
+  }
+}
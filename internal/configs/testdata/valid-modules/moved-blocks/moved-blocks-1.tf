@@ -22,3 +22,18 @@ moved {
   from = test.foo
   to   = module.a.test.foo
 }
+
+moved {
+  from = module.old[*].test.foo
+  to   = module.new[*].test.foo
+}
+
+moved {
+  from = module.a[*].module.b[*]
+  to   = module.c[*].module.d[*]
+}
+
+moved {
+  from = module.a[*].test.foo
+  to   = test.foo[*]
+}
@@ -0,0 +1,7 @@
+variable "a" {
+  default = "primary"
+}
+
+variable "b" {
+  default = "primary"
+}
@@ -27,6 +27,19 @@ func Lock(f *os.File) error {
 	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, flock)
 }
 
+// LockShared takes a shared (read) lock, which can be held alongside other
+// shared locks on the same file but not alongside a lock taken by Lock.
+func LockShared(f *os.File) error {
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_RDLCK,
+		Whence: int16(io.SeekStart),
+		Start:  0,
+		Len:    0,
+	}
+
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, flock)
+}
+
 func Unlock(f *os.File) error {
 	flock := &syscall.Flock_t{
 		Type:   syscall.F_UNLCK,
@@ -48,6 +48,25 @@ func Lock(f *os.File) error {
 	)
 }
 
+// LockShared takes a shared (read) lock, which can be held alongside other
+// shared locks on the same file but not alongside a lock taken by Lock.
+func LockShared(f *os.File) error {
+	ol, err := newOverlapped()
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(ol.HEvent)
+
+	return lockFileEx(
+		syscall.Handle(f.Fd()),
+		_LOCKFILE_FAIL_IMMEDIATELY,
+		0,              // reserved
+		0,              // bytes low
+		math.MaxUint32, // bytes high
+		ol,
+	)
+}
+
 func Unlock(*os.File) error {
 	// the lock is released when Close() is called
 	return nil
@@ -0,0 +1,238 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package genconfig
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Templates are the rules, per resource type, for reshaping the configuration
+// generated for imported resources before it's written out, as loaded from
+// the file given with the -generate-config-template option.
+//
+// A template file contains one resource_template block for each resource
+// type to reshape:
+//
+//	resource_template "aws_instance" {
+//	  variables = ["ami", "instance_type"]
+//
+//	  lifecycle {
+//	    prevent_destroy = true
+//	  }
+//	}
+//
+// The attributes named in variables are moved into input variables, using the
+// generated values as their defaults, and the lifecycle block is added to each
+// generated resource block as written.
+type Templates struct {
+	resources map[string]*resourceTemplate
+}
+
+type resourceTemplate struct {
+	variables []string
+
+	// lifecycle is the source code of the template's lifecycle block, if
+	// any. We keep the source rather than a parsed block so that each
+	// generated resource gets its own copy with the original formatting.
+	lifecycle []byte
+}
+
+var templateFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource_template", LabelNames: []string{"type"}},
+	},
+}
+
+var resourceTemplateSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "variables"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "lifecycle"},
+	},
+}
+
+// LoadTemplates reads the config generation templates from the given file.
+func LoadTemplates(path string) (*Templates, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read config generation template",
+			fmt.Sprintf("Could not read %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	file, hclDiags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+	content, hclDiags := file.Body.Content(templateFileSchema)
+	diags = diags.Append(hclDiags)
+
+	ret := &Templates{resources: make(map[string]*resourceTemplate)}
+	declared := make(map[string]hcl.Range)
+	for _, block := range content.Blocks {
+		typeName := block.Labels[0]
+		if prev, exists := declared[typeName]; exists {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate resource template",
+				Detail:   fmt.Sprintf("A template for resource type %q was already declared at %s.", typeName, prev),
+				Subject:  block.LabelRanges[0].Ptr(),
+			})
+			continue
+		}
+		declared[typeName] = block.DefRange
+
+		tmpl, moreDiags := decodeResourceTemplate(src, block)
+		diags = diags.Append(moreDiags)
+		ret.resources[typeName] = tmpl
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return ret, diags
+}
+
+func decodeResourceTemplate(src []byte, block *hcl.Block) (*resourceTemplate, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	tmpl := &resourceTemplate{}
+
+	content, hclDiags := block.Body.Content(resourceTemplateSchema)
+	diags = diags.Append(hclDiags)
+
+	if attr, ok := content.Attributes["variables"]; ok {
+		diags = diags.Append(gohcl.DecodeExpression(attr.Expr, nil, &tmpl.variables))
+		for _, name := range tmpl.variables {
+			if !hclsyntax.ValidIdentifier(name) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid attribute name",
+					Detail:   fmt.Sprintf("%q is not a valid attribute name. Each element of variables must name a top-level argument of the resource type.", name),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
+	for _, lifecycle := range content.Blocks {
+		if tmpl.lifecycle != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate lifecycle block",
+				Detail:   "A resource template can have only one lifecycle block.",
+				Subject:  lifecycle.DefRange.Ptr(),
+			})
+			continue
+		}
+		body, ok := lifecycle.Body.(*hclsyntax.Body)
+		if !ok {
+			// Can't happen because we parsed the file with hclsyntax.
+			panic(fmt.Sprintf("unsupported lifecycle body type %T", lifecycle.Body))
+		}
+		rng := hcl.RangeBetween(lifecycle.TypeRange, body.SrcRange)
+		// The source ends at the closing brace, so we add the newline that
+		// hclwrite requires to terminate the block.
+		tmpl.lifecycle = append(slices.Clone(src[rng.Start.Byte:rng.End.Byte]), '\n')
+	}
+
+	return tmpl, diags
+}
+
+// Apply reshapes the generated configuration of the given change according
+// to the template for its resource type, if there is one.
+func (t *Templates) Apply(c *Change) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if t == nil || len(c.GeneratedConfig) == 0 {
+		return diags
+	}
+
+	file, hclDiags := hclwrite.ParseConfig([]byte(c.GeneratedConfig), c.Addr, hcl.InitialPos)
+	if hclDiags.HasErrors() {
+		// The generated config is always valid HCL, so this would be a bug.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to apply config generation template",
+			fmt.Sprintf("OpenTofu couldn't parse the configuration generated for %s: %s. This is a bug in OpenTofu; please report it!", c.Addr, hclDiags.Error()),
+		))
+		return diags
+	}
+
+	var resource *hclwrite.Block
+	for _, block := range file.Body().Blocks() {
+		if block.Type() == "resource" && len(block.Labels()) == 2 {
+			resource = block
+			break
+		}
+	}
+	if resource == nil {
+		return diags
+	}
+	labels := resource.Labels()
+	tmpl, ok := t.resources[labels[0]]
+	if !ok {
+		return diags
+	}
+
+	var variables []*hclwrite.Block
+	for _, attrName := range tmpl.variables {
+		attr := resource.Body().GetAttribute(attrName)
+		if attr == nil {
+			continue
+		}
+		value := attr.Expr().BuildTokens(nil)
+		if strings.TrimSpace(string(value.Bytes())) == "null" {
+			// There's no value worth extracting, and sensitive values are
+			// also generated as null.
+			continue
+		}
+		varName := fmt.Sprintf("%s_%s_%s", labels[0], labels[1], attrName)
+		variable := hclwrite.NewBlock("variable", []string{varName})
+		variable.Body().SetAttributeRaw("default", value)
+		variables = append(variables, variable)
+		resource.Body().SetAttributeTraversal(attrName, hcl.Traversal{
+			hcl.TraverseRoot{Name: "var"},
+			hcl.TraverseAttr{Name: varName},
+		})
+	}
+
+	if tmpl.lifecycle != nil {
+		lifecycle, hclDiags := hclwrite.ParseConfig(tmpl.lifecycle, "", hcl.InitialPos)
+		if !hclDiags.HasErrors() {
+			if existing := resource.Body().FirstMatchingBlock("lifecycle", nil); existing != nil {
+				resource.Body().RemoveBlock(existing)
+			}
+			resource.Body().AppendNewline()
+			for _, block := range lifecycle.Body().Blocks() {
+				resource.Body().AppendBlock(block)
+			}
+		}
+	}
+
+	var buf strings.Builder
+	for _, variable := range variables {
+		buf.Write(variable.BuildTokens(nil).Bytes())
+		buf.WriteString("\n")
+	}
+	buf.Write(file.Bytes())
+	c.GeneratedConfig = strings.TrimSpace(string(hclwrite.Format([]byte(buf.String()))))
+	return diags
+}
@@ -0,0 +1,146 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package genconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTemplatesApply(t *testing.T) {
+	templates := loadTestTemplates(t, `
+resource_template "test_instance" {
+  variables = ["ami", "password", "missing"]
+
+  lifecycle {
+    prevent_destroy = true
+    ignore_changes  = [tags]
+  }
+}
+`)
+
+	tcs := map[string]struct {
+		config   string
+		expected string
+	}{
+		"matching type": {
+			config: `resource "test_instance" "web" {
+  ami      = "ami-123"
+  password = null # sensitive
+  tags = {
+    Name = "web"
+  }
+}`,
+			expected: `variable "test_instance_web_ami" {
+  default = "ami-123"
+}
+
+resource "test_instance" "web" {
+  ami      = var.test_instance_web_ami
+  password = null # sensitive
+  tags = {
+    Name = "web"
+  }
+
+  lifecycle {
+    prevent_destroy = true
+    ignore_changes  = [tags]
+  }
+}`,
+		},
+		"other type": {
+			config: `resource "test_other" "web" {
+  ami = "ami-123"
+}`,
+			expected: `resource "test_other" "web" {
+  ami = "ami-123"
+}`,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			change := &Change{Addr: "test_instance.web", GeneratedConfig: tc.config}
+			if diags := templates.Apply(change); diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+			if diff := cmp.Diff(tc.expected, change.GeneratedConfig); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTemplates_invalid(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"duplicate type": {
+			src: `
+resource_template "test_instance" {}
+resource_template "test_instance" {}
+`,
+			want: "Duplicate resource template",
+		},
+		"duplicate lifecycle": {
+			src: `
+resource_template "test_instance" {
+  lifecycle {}
+  lifecycle {}
+}
+`,
+			want: "Duplicate lifecycle block",
+		},
+		"invalid variable": {
+			src: `
+resource_template "test_instance" {
+  variables = ["tags.Name"]
+}
+`,
+			want: "Invalid attribute name",
+		},
+		"unsupported argument": {
+			src: `
+resource_template "test_instance" {
+  provider = "test"
+}
+`,
+			want: "Unsupported argument",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "templates.hcl")
+			if err := os.WriteFile(path, []byte(tc.src), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, diags := LoadTemplates(path)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, tc.want) {
+				t.Errorf("wrong error\nwant substring: %s\ngot: %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func loadTestTemplates(t *testing.T, src string) *Templates {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "templates.hcl")
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	templates, diags := LoadTemplates(path)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	return templates
+}
@@ -91,22 +91,26 @@ var goGetterDecompressorMediaTypes = map[string]string{
 	"archive/zip": "zip",
 }
 
-// goGetterGetters is an initial table of getters that we use as a starting
-// point when building a _real_ table of getters to pass into a
-// [reusingGetter] instance.
+// goGetterGetters is an initial table of constructors for the getters that
+// we use as a starting point when building a _real_ table of getters to pass
+// into a [reusingGetter] instance.
+//
+// We construct new getters for each request because go-getter modifies their
+// internal state before calling into them, and so a getter instance must not
+// be shared between concurrent requests.
 //
 // The elements mapped to nil here are those which are populated dynamically
 // based on arguments to [NewPackageFetcher], included here only so it's
 // easier to refer to the entire list of supported getter keys in one place.
-var goGetterGetters = map[string]getter.Getter{
-	"file":  new(getter.FileGetter),
-	"gcs":   new(getter.GCSGetter),
-	"git":   new(getter.GitGetter),
-	"hg":    new(getter.HgGetter),
+var goGetterGetters = map[string]func() getter.Getter{
+	"file":  func() getter.Getter { return new(getter.FileGetter) },
+	"gcs":   func() getter.Getter { return new(getter.GCSGetter) },
+	"git":   func() getter.Getter { return new(getter.GitGetter) },
+	"hg":    func() getter.Getter { return new(getter.HgGetter) },
 	"http":  nil, // configured dynamically in NewPackageFetcher
 	"https": nil, // configured dynamically in NewPackageFetcher
 	"oci":   nil, // configured dynamically using [PackageFetcherEnvironment.OCIRepositoryStore]
-	"s3":    new(getter.S3Getter),
+	"s3":    func() getter.Getter { return new(getter.S3Getter) },
 }
 
 // A reusingGetter is a helper for the module installer that remembers
@@ -121,18 +125,19 @@ var goGetterGetters = map[string]getter.Getter{
 // imports getmodules in order to indirectly access our go-getter
 // configuration.)
 type reusingGetter struct {
-	// getters are the go-getter getters that this particular instance of
-	// reusingGetter should use.
-	getters map[string]getter.Getter
+	// getters are the constructors for the go-getter getters that this
+	// particular instance of reusingGetter should use.
+	getters map[string]func() getter.Getter
 
-	previousInstalls   map[string]string // initialized on first install request
-	previousInstallsMu sync.Mutex        // must hold while interacting with previousInstalls
+	previousInstalls   map[string]string      // initialized on first install request
+	packageLocks       map[string]*sync.Mutex // initialized on first install request
+	previousInstallsMu sync.Mutex             // must hold while interacting with previousInstalls or packageLocks
 }
 
-func newReusingGetter(getters map[string]getter.Getter) *reusingGetter {
+func newReusingGetter(getters map[string]func() getter.Getter) *reusingGetter {
 	return &reusingGetter{
 		getters: getters,
-		// previousInstalls initialized only on request
+		// previousInstalls and packageLocks initialized only on request
 	}
 }
 
@@ -157,28 +162,18 @@ func newReusingGetter(getters map[string]getter.Getter) *reusingGetter {
 // reasonable way to improve these error messages at this layer because
 // the underlying errors are not separately recognizable.
 func (g *reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr string) error {
-	var err error
-
-	// For now we hold the "previousInstalls" mutex throughout our entire work here
-	// since we don't currently try to use a single getter concurrently anyway.
-	// If we _do_ want to enable more concurrency in future then we'll need a
-	// more interesting strategy to make sure that only concurrent attempts to
-	// install the _same_ package get serialized, but we'll wait until we have
-	// that need before we introduce that complexity.
-	//
-	// NOTE WELL: [getter.Client.Get] modifies internal state inside each of the
-	// getters passed in [getter.Client.Getters] before calling into them, so
-	// it is _not_ safe to reuse the same getter instances across multiple
-	// concurrent calls. If we want to make this work concurrently in future
-	// then we'll need to instead instantiate the getters on demand for each
-	// request.
+	// Concurrent attempts to install the _same_ package are serialized, so
+	// that only the first one fetches it and the others can then copy the
+	// result, but requests for different packages can proceed concurrently.
+	pkgMu := g.packageLock(packageAddr)
+	pkgMu.Lock()
+	defer pkgMu.Unlock()
+
 	g.previousInstallsMu.Lock()
-	defer g.previousInstallsMu.Unlock()
-	if g.previousInstalls == nil {
-		g.previousInstalls = make(map[string]string)
-	}
+	prevDir, exists := g.previousInstalls[packageAddr]
+	g.previousInstallsMu.Unlock()
 
-	if prevDir, exists := g.previousInstalls[packageAddr]; exists {
+	if exists {
 		log.Printf("[TRACE] getmodules: copying previous install of %q from %s to %s", packageAddr, prevDir, instPath)
 		err := os.Mkdir(instPath, os.ModePerm)
 		if err != nil {
@@ -190,6 +185,14 @@ func (g *reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAd
 		}
 	} else {
 		log.Printf("[TRACE] getmodules: fetching %q to %q", packageAddr, instPath)
+
+		// NOTE WELL: [getter.Client.Get] modifies internal state inside each
+		// of the getters passed in [getter.Client.Getters] before calling
+		// into them, so we instantiate new getters for each request.
+		getters := make(map[string]getter.Getter, len(g.getters))
+		for name, newGetter := range g.getters {
+			getters[name] = newGetter()
+		}
 		client := getter.Client{
 			Src: packageAddr,
 			Dst: instPath,
@@ -199,16 +202,18 @@ func (g *reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAd
 
 			Detectors:     goGetterNoDetectors, // our caller should've already done detection
 			Decompressors: goGetterDecompressors,
-			Getters:       g.getters,
+			Getters:       getters,
 			Ctx:           ctx,
 		}
-		err = client.Get()
+		err := client.Get()
 		if err != nil {
 			return err
 		}
 		// Remember where we installed this so we might reuse this directory
 		// on subsequent calls to avoid re-downloading.
+		g.previousInstallsMu.Lock()
 		g.previousInstalls[packageAddr] = instPath
+		g.previousInstallsMu.Unlock()
 	}
 
 	// If we get down here then we've either downloaded the package or
@@ -217,6 +222,23 @@ func (g *reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAd
 	return nil
 }
 
+// packageLock returns the mutex that serializes installations of the package
+// at the given address.
+func (g *reusingGetter) packageLock(packageAddr string) *sync.Mutex {
+	g.previousInstallsMu.Lock()
+	defer g.previousInstallsMu.Unlock()
+	if g.previousInstalls == nil {
+		g.previousInstalls = make(map[string]string)
+		g.packageLocks = make(map[string]*sync.Mutex)
+	}
+	mu, exists := g.packageLocks[packageAddr]
+	if !exists {
+		mu = &sync.Mutex{}
+		g.packageLocks[packageAddr] = mu
+	}
+	return mu
+}
+
 // withoutQueryParams implements getter.Detector and can be used to wrap another detector.
 // This will look for any query params that might exist in the src and strip that away before calling
 // getter.Detector#Detect. After the response is returned, the query params are attached back to the resulted src.
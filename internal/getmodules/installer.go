@@ -9,15 +9,22 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"net/url"
+	"strings"
 
 	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
 	"go.opentelemetry.io/otel/trace"
 
 	getter "github.com/hashicorp/go-getter"
 	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/offline"
 	"github.com/opentofu/opentofu/internal/tracing"
 )
 
+// maxHTTPPackageResumeCount is the number of times that the download of a
+// module package over HTTP can resume after the connection fails.
+const maxHTTPPackageResumeCount = 2
+
 // PackageFetcher is a low-level utility for fetching remote module packages
 // into local filesystem directories in preparation for use by higher-level
 // module installer functionality implemented elsewhere.
@@ -55,21 +62,29 @@ func NewPackageFetcher(ctx context.Context, env PackageFetcherEnvironment) *Pack
 
 	// The OCI Distribution getter needs to acquire credentials based on
 	// centrally-configured policy, encapsulated in env.OCIRepositoryStore.
-	getters["oci"] = &ociDistributionGetter{
-		getOCIRepositoryStore: env.OCIRepositoryStore,
+	getters["oci"] = func() getter.Getter {
+		return &ociDistributionGetter{
+			getOCIRepositoryStore: env.OCIRepositoryStore,
+		}
 	}
 
 	// The HTTP getter (used for both "http" and "https" schemes) uses
 	// the HTTP client we instantiated above, whose behavior can be
 	// incluenced by the ctx argument we passed to it, such as by
 	// enabling OpenTelemetry tracing when appropriate.
-	var httpGetter = &getter.HttpGetter{
-		Client:             httpClient,
-		Netrc:              true,
-		XTerraformGetLimit: 10,
+	//
+	// Downloads of module packages resume where they stopped if the
+	// connection fails partway through, rather than starting over.
+	downloadClient := httpclient.WithResumableDownloads(httpClient, maxHTTPPackageResumeCount)
+	newHTTPGetter := func() getter.Getter {
+		return &getter.HttpGetter{
+			Client:             downloadClient,
+			Netrc:              true,
+			XTerraformGetLimit: 10,
+		}
 	}
-	getters["http"] = httpGetter
-	getters["https"] = httpGetter
+	getters["http"] = newHTTPGetter
+	getters["https"] = newHTTPGetter
 
 	return &PackageFetcher{
 		getter: newReusingGetter(getters),
@@ -88,11 +103,20 @@ func NewPackageFetcher(ctx context.Context, env PackageFetcherEnvironment) *Pack
 // a module source address which includes a subdirectory portion then the
 // caller must resolve that itself, possibly with the help of the
 // getmodules.SplitPackageSubdir and getmodules.ExpandSubdirGlobs functions.
+//
+// It's safe to call FetchPackage concurrently with different installation
+// directories. Concurrent calls for the same package address are serialized
+// so that the package is fetched only once.
 func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string) error {
 	ctx, span := tracing.Tracer().Start(ctx, "Fetch Package",
 		trace.WithAttributes(semconv.URLFull(packageAddr)),
 	)
 	defer span.End()
+	if !isLocalPackageAddr(packageAddr) {
+		if err := offline.Check(fmt.Sprintf("the module package %s", packageAddr)); err != nil {
+			return err
+		}
+	}
 	err := f.getter.getWithGoGetter(ctx, instDir, packageAddr)
 	if err != nil {
 		span.RecordError(err)
@@ -101,6 +125,17 @@ func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packa
 	return nil
 }
 
+// isLocalPackageAddr returns whether the given package address refers to
+// the local filesystem, such as "git::file:///srv/modules/vpc", so that
+// fetching it doesn't need network access.
+func isLocalPackageAddr(packageAddr string) bool {
+	if _, rest, ok := strings.Cut(packageAddr, "::"); ok {
+		packageAddr = rest
+	}
+	u, err := url.Parse(packageAddr)
+	return err == nil && u.Scheme == "file"
+}
+
 // PackageFetcherEnvironment is an interface used with [NewPackageFetcher]
 // to allow the caller to define how the package fetcher should interact
 // with the rest of OpenTofu and with OpenTofu's execution environment.
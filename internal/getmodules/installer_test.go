@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestPackageFetcher_concurrent(t *testing.T) {
+	// We fetch each of a few local packages several times at once, so that
+	// the race detector can catch any unsafe sharing between concurrent
+	// fetches whether or not they are for the same package.
+	srcDir := t.TempDir()
+	var packageAddrs []string
+	for i := range 3 {
+		dir := filepath.Join(srcDir, fmt.Sprintf("pkg%d", i))
+		if err := os.Mkdir(dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(fmt.Sprintf("# package %d\n", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		packageAddrs = append(packageAddrs, "file://"+filepath.ToSlash(dir))
+	}
+
+	fetcher := NewPackageFetcher(t.Context(), nil)
+	instDir := t.TempDir()
+	var wg sync.WaitGroup
+	errs := make([]error, 4*len(packageAddrs))
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instPath := filepath.Join(instDir, fmt.Sprintf("inst%d", i))
+			errs[i] = fetcher.FetchPackage(t.Context(), instPath, packageAddrs[i%len(packageAddrs)])
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("fetch %d failed: %s", i, err)
+		}
+		got, err := os.ReadFile(filepath.Join(instDir, fmt.Sprintf("inst%d", i), "main.tf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("# package %d\n", i%len(packageAddrs)); string(got) != want {
+			t.Errorf("wrong content for fetch %d\ngot:  %q\nwant: %q", i, got, want)
+		}
+	}
+}
@@ -50,9 +50,14 @@ func (p PackageHTTPURL) InstallProviderPackage(ctx context.Context, meta Package
 	// through X-Terraform-Get header, attempting partial fetches for
 	// files that already exist, etc.)
 
+	// If the connection fails partway through the download then the client
+	// resumes it where it stopped, which matters for large provider
+	// packages. The package is still checked against its expected checksums
+	// once it's complete.
 	retryableClient := retryablehttp.NewClient()
-	retryableClient.HTTPClient = httpclient.New(ctx)
+	retryableClient.HTTPClient = httpclient.WithResumableDownloads(httpclient.New(ctx), maxHTTPPackageRetryCount)
 	retryableClient.RetryMax = maxHTTPPackageRetryCount
+	retryableClient.CheckRetry = httpclient.OfflineAwareRetryPolicy
 	retryableClient.RequestLogHook = func(logger retryablehttp.Logger, _ *http.Request, i int) {
 		if i > 0 {
 			logger.Printf("[INFO] failed to fetch provider package; retrying")
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"context"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// RedirectSource is a Source that wraps another Source and installs some
+// providers from a different address, known as the provider's origin, such as
+// a copy of the provider in an internal registry.
+//
+// The package metadata returned for a redirected provider still refers to the
+// provider it was requested for, so that the rest of OpenTofu, including the
+// dependency lock file, only knows the provider by its original address.
+type RedirectSource struct {
+	underlying Source
+	origins    map[addrs.Provider]addrs.Provider
+}
+
+var _ Source = (*RedirectSource)(nil)
+
+// NewRedirectSource constructs and returns a new RedirectSource that wraps
+// the given underlying source, installing each provider that is a key in the
+// given map from the address it maps to.
+func NewRedirectSource(underlying Source, origins map[addrs.Provider]addrs.Provider) *RedirectSource {
+	return &RedirectSource{
+		underlying: underlying,
+		origins:    origins,
+	}
+}
+
+// AvailableVersions returns the versions of the given provider that are
+// available at its origin.
+func (s *RedirectSource) AvailableVersions(ctx context.Context, provider addrs.Provider) (VersionList, Warnings, error) {
+	return s.underlying.AvailableVersions(ctx, s.origin(provider))
+}
+
+// PackageMeta returns the metadata for a package of the given provider that is
+// available at its origin.
+func (s *RedirectSource) PackageMeta(ctx context.Context, provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	meta, err := s.underlying.PackageMeta(ctx, s.origin(provider), version, target)
+	if err != nil {
+		return meta, err
+	}
+	meta.Provider = provider
+	return meta, nil
+}
+
+func (s *RedirectSource) ForDisplay(provider addrs.Provider) string {
+	return s.underlying.ForDisplay(s.origin(provider))
+}
+
+func (s *RedirectSource) origin(provider addrs.Provider) addrs.Provider {
+	if origin, ok := s.origins[provider]; ok {
+		return origin
+	}
+	return provider
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestRedirectSource(t *testing.T) {
+	canonical := addrs.NewDefaultProvider("foo")
+	origin := addrs.MustParseProviderSourceString("registry.example.com/mirror/foo")
+	other := addrs.NewDefaultProvider("bar")
+	version := MustParseVersion("1.0.0")
+	protocols := VersionList{MustParseVersion("5.0")}
+	platform := Platform{OS: "gameboy", Arch: "lr35902"}
+
+	mock := NewMockSource([]PackageMeta{
+		FakePackageMeta(origin, version, protocols, platform),
+		FakePackageMeta(canonical, MustParseVersion("2.0.0"), protocols, platform),
+		FakePackageMeta(other, version, protocols, platform),
+	}, nil)
+	source := NewRedirectSource(mock, map[addrs.Provider]addrs.Provider{
+		canonical: origin,
+	})
+
+	t.Run("AvailableVersions for redirected provider", func(t *testing.T) {
+		got, _, err := source.AvailableVersions(context.Background(), canonical)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(VersionList{version}, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("AvailableVersions for other provider", func(t *testing.T) {
+		got, _, err := source.AvailableVersions(context.Background(), other)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(VersionList{version}, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("PackageMeta for redirected provider", func(t *testing.T) {
+		got, err := source.PackageMeta(context.Background(), canonical, version, platform)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := FakePackageMeta(origin, version, protocols, platform)
+		want.Provider = canonical
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("PackageMeta for version missing from origin", func(t *testing.T) {
+		_, err := source.PackageMeta(context.Background(), canonical, MustParseVersion("2.0.0"), platform)
+		if err == nil {
+			t.Fatal("succeeded; want error")
+		}
+	})
+}
@@ -62,5 +62,9 @@ func New(ctx context.Context) *http.Client {
 		cli.Transport = otelhttp.NewTransport(cli.Transport)
 	}
 
+	// All requests fail immediately while OpenTofu is running in offline
+	// mode, which applies to every client that this package creates.
+	cli.Transport = &offlineRoundTripper{inner: cli.Transport}
+
 	return cli
 }
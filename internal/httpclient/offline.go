@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/opentofu/opentofu/internal/offline"
+)
+
+// offlineRoundTripper rejects all requests while OpenTofu is running in
+// offline mode.
+//
+// It checks offline mode for each request rather than when the client is
+// created, because some clients are created before the command line has
+// been parsed.
+type offlineRoundTripper struct {
+	inner http.RoundTripper
+}
+
+func (rt *offlineRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := offline.Check(req.URL.Redacted()); err != nil {
+		return nil, err
+	}
+	return rt.inner.RoundTrip(req)
+}
+
+// OfflineAwareRetryPolicy is a retry policy for retryablehttp clients that
+// behaves like retryablehttp.DefaultRetryPolicy, except that it never retries
+// a request that failed because OpenTofu is running in offline mode, since
+// it would fail again in exactly the same way.
+func OfflineAwareRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if offline.IsError(err) {
+		return false, err
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
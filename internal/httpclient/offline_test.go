@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/offline"
+)
+
+func TestNew_offline(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	offline.Enable(true)
+	t.Cleanup(func() { offline.Enable(false) })
+
+	_, err := New(context.Background()).Get(server.URL)
+	if !offline.IsError(err) {
+		t.Fatalf("wrong error %#v; want an offline mode error", err)
+	}
+
+	// The registry client must give up at once instead of retrying.
+	client := NewForRegistryRequests(context.Background(), 3, time.Minute)
+	start := time.Now()
+	_, err = client.Get(server.URL)
+	if !offline.IsError(err) {
+		t.Fatalf("wrong error %#v; want an offline mode error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("registry client retried the request for %s", elapsed)
+	}
+
+	if requests != 0 {
+		t.Errorf("server received %d requests; want none", requests)
+	}
+}
@@ -7,6 +7,7 @@ package httpclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -47,6 +48,7 @@ func NewForRegistryRequests(ctx context.Context, retryCount int, timeout time.Du
 	retryableClient := retryablehttp.NewClient()
 	retryableClient.HTTPClient = baseClient
 	retryableClient.RetryMax = retryCount
+	retryableClient.CheckRetry = OfflineAwareRetryPolicy
 	retryableClient.RequestLogHook = registryRequestLogHook
 	retryableClient.ErrorHandler = registryMaxRetryErrorHandler
 
@@ -65,21 +67,21 @@ func registryMaxRetryErrorHandler(resp *http.Response, err error, numTries int)
 		resp.Body.Close()
 	}
 
-	// Additional error detail: if we have a response, use the status code;
-	// if we have an error, use that; otherwise nothing. We will never have
-	// both response and error.
-	var errMsg string
-	if resp != nil {
-		errMsg = fmt.Sprintf(": %s returned from %s", resp.Status, resp.Request.URL)
-	} else if err != nil {
-		errMsg = fmt.Sprintf(": %s", err)
-	}
-
 	// This function is always called with numTries=RetryMax+1. If we made any
 	// retry attempts, include that in the error message.
+	prefix := "request failed"
 	if numTries > 1 {
-		return resp, fmt.Errorf("request failed after %d attempts%s",
-			numTries, errMsg)
+		prefix = fmt.Sprintf("request failed after %d attempts", numTries)
+	}
+
+	// Additional error detail: if we have a response, use the status code;
+	// if we have an error, use that (wrapped, so that callers can still
+	// recognize it); otherwise nothing. We will never have both response
+	// and error.
+	if resp != nil {
+		return resp, fmt.Errorf("%s: %s returned from %s", prefix, resp.Status, resp.Request.URL)
+	} else if err != nil {
+		return resp, fmt.Errorf("%s: %w", prefix, err)
 	}
-	return resp, fmt.Errorf("request failed%s", errMsg)
+	return resp, errors.New(prefix)
 }
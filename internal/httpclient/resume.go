@@ -0,0 +1,158 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithResumableDownloads returns a copy of the given client whose GET
+// responses resume after the connection fails partway through the response
+// body, instead of failing the whole download.
+//
+// To resume, the client sends a range request for the rest of the body with
+// an If-Range header that has the validator (the ETag or Last-Modified
+// header) of the original response, so that the server only sends the rest
+// of the body if it hasn't changed in the meantime. Responses whose server
+// doesn't support range requests, or doesn't provide a validator or the
+// length of the body, aren't resumed.
+//
+// maxResumes is the number of times each response body can resume. Callers
+// should still verify the content of the complete body where possible,
+// such as by comparing its checksum to a known one.
+func WithResumableDownloads(client *http.Client, maxResumes int) *http.Client {
+	ret := *client
+	inner := ret.Transport
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	ret.Transport = &resumingRoundTripper{
+		inner:      inner,
+		maxResumes: maxResumes,
+		wait:       time.Second,
+	}
+	return &ret
+}
+
+type resumingRoundTripper struct {
+	inner      http.RoundTripper
+	maxResumes int
+
+	// wait is the time to wait before the first attempt to resume a
+	// response body, which then increases with each further attempt.
+	wait time.Duration
+}
+
+func (rt *resumingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.inner.RoundTrip(req)
+	if err != nil || rt.maxResumes <= 0 || req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusOK || resp.ContentLength <= 0 || resp.Header.Get("Accept-Ranges") != "bytes" || resp.Header.Get("Content-Encoding") != "" {
+		return resp, nil
+	}
+	validator := resp.Header.Get("ETag")
+	if strings.HasPrefix(validator, "W/") {
+		// Weak validators can't be used for range requests.
+		validator = ""
+	}
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if validator == "" {
+		return resp, nil
+	}
+
+	resp.Body = &resumingBody{
+		rt:        rt,
+		req:       req,
+		body:      resp.Body,
+		validator: validator,
+		size:      resp.ContentLength,
+	}
+	return resp, nil
+}
+
+// resumingBody is a response body that resumes with a range request for the
+// rest of the body when reading from the underlying connection fails.
+type resumingBody struct {
+	rt        *resumingRoundTripper
+	req       *http.Request
+	body      io.ReadCloser
+	validator string
+
+	// offset is the number of bytes read so far, out of size.
+	offset, size int64
+	resumes      int
+}
+
+func (b *resumingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.offset += int64(n)
+	if err == nil {
+		return n, nil
+	}
+	if errors.Is(err, io.EOF) {
+		if b.offset == b.size {
+			return n, io.EOF
+		}
+		err = io.ErrUnexpectedEOF
+	}
+	if b.offset >= b.size || b.resumes >= b.rt.maxResumes || b.req.Context().Err() != nil {
+		return n, err
+	}
+
+	log.Printf("[WARN] Download from %s failed after %d of %d bytes: %s; resuming", b.req.URL.Redacted(), b.offset, b.size, err)
+	if resumeErr := b.resume(); resumeErr != nil {
+		log.Printf("[WARN] Can't resume download from %s: %s", b.req.URL.Redacted(), resumeErr)
+		return n, err
+	}
+	return n, nil
+}
+
+func (b *resumingBody) resume() error {
+	b.resumes++
+	b.body.Close()
+	b.body = http.NoBody
+
+	ctx := b.req.Context()
+	select {
+	case <-time.After(time.Duration(b.resumes) * b.rt.wait):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	req := b.req.Clone(ctx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", b.offset))
+	req.Header.Set("If-Range", b.validator)
+	resp, err := b.rt.inner.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		// A server that gets a range request with an If-Range header
+		// responds with the whole body if it has changed.
+		return fmt.Errorf("server responded with %s instead of the rest of the content, which may mean that the content has changed", resp.Status)
+	}
+	want := fmt.Sprintf("bytes %d-%d/%d", b.offset, b.size-1, b.size)
+	if got := resp.Header.Get("Content-Range"); got != want {
+		resp.Body.Close()
+		return fmt.Errorf("server responded with content range %q instead of %q", got, want)
+	}
+	b.body = resp.Body
+	return nil
+}
+
+func (b *resumingBody) Close() error {
+	return b.body.Close()
+}
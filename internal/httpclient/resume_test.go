@@ -0,0 +1,120 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithResumableDownloads(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+	tests := map[string]struct {
+		// failures is the number of responses that fail partway.
+		failures   int
+		changed    bool
+		maxResumes int
+		wantErr    bool
+	}{
+		"no failures": {
+			maxResumes: 2,
+		},
+		"one failure": {
+			failures:   1,
+			maxResumes: 2,
+		},
+		"failures up to the limit": {
+			failures:   2,
+			maxResumes: 2,
+		},
+		"too many failures": {
+			failures:   3,
+			maxResumes: 2,
+			wantErr:    true,
+		},
+		"resuming disabled": {
+			failures:   1,
+			maxResumes: 0,
+			wantErr:    true,
+		},
+		"content changed": {
+			failures:   1,
+			changed:    true,
+			maxResumes: 2,
+			wantErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				n := int(requests.Add(1))
+				etag := `"v1"`
+				if test.changed && n > 1 {
+					etag = `"v2"`
+				}
+				w.Header().Set("ETag", etag)
+				if n > test.failures {
+					http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+					return
+				}
+
+				// Send a tenth of the remaining content and then drop the
+				// connection, as if the network failed.
+				start := 0
+				if rng := req.Header.Get("Range"); rng != "" {
+					start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+					w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(content)-1)+"/"+strconv.Itoa(len(content)))
+					w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+					w.WriteHeader(http.StatusPartialContent)
+				} else {
+					w.Header().Set("Accept-Ranges", "bytes")
+					w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+					w.WriteHeader(http.StatusOK)
+				}
+				_, _ = w.Write(content[start : start+(len(content)-start)/10])
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			}))
+			defer server.Close()
+
+			client := WithResumableDownloads(New(context.Background()), test.maxResumes)
+			client.Transport.(*resumingRoundTripper).wait = 0
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer resp.Body.Close()
+			got, err := io.ReadAll(resp.Body)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("wrong content: got %d bytes, want %d bytes", len(got), len(content))
+			}
+			if got, want := int(requests.Load()), test.failures+1; got != want {
+				t.Errorf("wrong number of requests %d; want %d", got, want)
+			}
+		})
+	}
+}
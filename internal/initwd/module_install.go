@@ -9,12 +9,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 	version "github.com/hashicorp/go-version"
@@ -49,6 +52,17 @@ type ModuleInstaller struct {
 	// The keys in moduleVersionsUrl are the moduleVersion struct below and
 	// addresses and the values are underlying remote source addresses.
 	registryPackageSources map[moduleVersion]addrs.ModuleSourceRemote
+
+	// concurrency is the maximum number of modules to retrieve from remote
+	// sources at the same time. Zero is treated the same as one.
+	concurrency int
+
+	// When installing concurrently, mu is held by each module installation
+	// except while it's waiting for a remote source, and sem limits how
+	// many of them can wait at once. sem is nil when installing modules one
+	// at a time.
+	mu  sync.Mutex
+	sem chan struct{}
 }
 
 type moduleVersion struct {
@@ -85,6 +99,17 @@ func NewModuleInstaller(modsDir string, loader *configload.Loader, registryClien
 	}
 }
 
+// SetConcurrency sets the maximum number of modules the receiver will retrieve
+// from remote sources at the same time. The hooks passed to InstallModules
+// are still called one at a time, but the order of calls for different
+// modules is unspecified.
+//
+// The default, if this method isn't called, is to install one module at a
+// time.
+func (i *ModuleInstaller) SetConcurrency(n int) {
+	i.concurrency = n
+}
+
 // InstallModules analyses the root module in the given directory and installs
 // all of its direct and transitive dependencies into the given modules
 // directory, which must already exist.
@@ -170,6 +195,41 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 }
 
 func (i *ModuleInstaller) moduleInstallWalker(_ context.Context, manifest modsdir.Manifest, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) configs.ModuleWalker {
+	walk := i.moduleInstallWalkerFunc(manifest, upgrade, hooks, fetcher)
+	if i.concurrency <= 1 {
+		i.sem = nil
+		return configs.ModuleWalkerFunc(walk)
+	}
+
+	// Everything other than waiting for remote sources is serialized by
+	// holding i.mu, so that the manifest, the installer's caches, the config
+	// parser, and the hooks are only ever used by one module at a time.
+	i.sem = make(chan struct{}, i.concurrency)
+	return configs.ConcurrentModuleWalkerFunc(
+		func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+			i.mu.Lock()
+			defer i.mu.Unlock()
+			return walk(ctx, req)
+		},
+	)
+}
+
+// waitRemote calls fn, which waits for a remote source, allowing other
+// modules to be installed in the meantime if the installer is installing
+// modules concurrently.
+func (i *ModuleInstaller) waitRemote(fn func()) {
+	if i.sem == nil {
+		fn()
+		return
+	}
+	i.mu.Unlock()
+	defer i.mu.Lock()
+	i.sem <- struct{}{}
+	defer func() { <-i.sem }()
+	fn()
+}
+
+func (i *ModuleInstaller) moduleInstallWalkerFunc(manifest modsdir.Manifest, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) configs.ModuleWalkerFunc {
 	return configs.ModuleWalkerFunc(
 		func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
 			var diags hcl.Diagnostics
@@ -342,13 +402,20 @@ func (i *ModuleInstaller) installDescendentModules(ctx context.Context, rootMod
 	// need to create a closure to capture the installation diagnostics
 	// separately.
 	var instDiags hcl.Diagnostics
+	var instDiagsMu sync.Mutex
 	walker := installWalker
 	if installErrsOnly {
-		walker = configs.ModuleWalkerFunc(func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+		collect := func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
 			mod, version, diags := installWalker.LoadModule(ctx, req)
+			instDiagsMu.Lock()
 			instDiags = instDiags.Extend(diags)
+			instDiagsMu.Unlock()
 			return mod, version, diags
-		})
+		}
+		walker = configs.ModuleWalkerFunc(collect)
+		if _, concurrent := installWalker.(configs.ConcurrentModuleWalkerFunc); concurrent {
+			walker = configs.ConcurrentModuleWalkerFunc(collect)
+		}
 	}
 
 	cfg, cDiags := configs.BuildConfig(ctx, rootMod, walker)
@@ -491,6 +558,165 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	hostname := addr.Package.Host
 	reg := i.reg
+
+	// A registry entry isn't _really_ a module package, but we'll pretend it's
+	// one for the sake of this reporting by just trimming off any source
+	// directory.
+	packageAddr := addr.Package
+
+	// Our registry client is still using the legacy model of addresses, so
+	// we'll shim it here for now.
+	regsrcAddr := regsrc.ModuleFromRegistryPackageAddr(packageAddr)
+
+	resp, moreDiags := i.registryModuleVersions(ctx, req, key, addr)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		tracing.SetSpanError(span, diags)
+		return nil, nil, diags
+	}
+	latestMatch, _, moreDiags := selectRegistryModuleVersion(req, key, addr, resp)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		tracing.SetSpanError(span, diags)
+		return nil, nil, diags
+	}
+
+	// Report up to the caller that we're about to start downloading.
+	hooks.Download(key, packageAddr.String(), latestMatch)
+
+	// If we manage to get down here then we've found a suitable version to
+	// install, so we need to ask the registry where we should download it from.
+	// The response to this is a go-getter-style address string.
+
+	// first check the cache for the download URL
+	moduleAddr := moduleVersion{module: packageAddr, version: latestMatch.String()}
+	if _, exists := i.registryPackageSources[moduleAddr]; !exists {
+		var realAddrRaw string
+		var err error
+		i.waitRemote(func() {
+			realAddrRaw, err = reg.ModuleLocation(ctx, regsrcAddr, latestMatch.String())
+		})
+		if err != nil {
+			log.Printf("[ERROR] %s from %s %s: %s", key, addr, latestMatch, err)
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Error accessing remote module registry",
+				Detail:   fmt.Sprintf("Failed to retrieve a download URL for %s %s from %s: %s", addr, latestMatch, hostname, err),
+			})
+			tracing.SetSpanError(span, diags)
+			return nil, nil, diags
+		}
+		realAddr, err := addrs.ParseModuleSource(realAddrRaw)
+		if err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid package location from module registry",
+				Detail:   fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: %s.", hostname, realAddrRaw, addr, latestMatch, err),
+			})
+			tracing.SetSpanError(span, diags)
+			return nil, nil, diags
+		}
+
+		span.SetAttributes(otelAttr.String(traceattrs.ModuleSource, realAddr.String()))
+
+		switch realAddr := realAddr.(type) {
+		// Only a remote source address is allowed here: a registry isn't
+		// allowed to return a local path (because it doesn't know what
+		// its being called from) and we also don't allow recursively pointing
+		// at another registry source for simplicity's sake.
+		case addrs.ModuleSourceRemote:
+			i.registryPackageSources[moduleAddr] = realAddr
+		default:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid package location from module registry",
+				Detail:   fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: must be a direct remote package address.", hostname, realAddrRaw, addr, latestMatch),
+			})
+			tracing.SetSpanError(span, diags)
+			return nil, nil, diags
+		}
+	}
+
+	dlAddr := i.registryPackageSources[moduleAddr]
+
+	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
+
+	err := i.fetchPackage(ctx, key, packageAddr.String(), instPath, dlAddr.Package.String(), hooks, fetcher)
+	if errors.Is(err, context.Canceled) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Module download was interrupted",
+			Detail:   fmt.Sprintf("Interrupt signal received when downloading module %s.", addr),
+		})
+		return nil, nil, diags
+	}
+	if err != nil {
+		// Errors returned by go-getter have very inconsistent quality as
+		// end-user error messages, but for now we're accepting that because
+		// we have no way to recognize any specific errors to improve them
+		// and masking the error entirely would hide valuable diagnostic
+		// information from the user.
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to download module",
+			Detail:   fmt.Sprintf("Could not download module %q (%s:%d) source code from %q: %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, dlAddr, err),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return nil, nil, diags
+	}
+
+	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, dlAddr.Package, instPath)
+
+	// Incorporate any subdir information from the original path into the
+	// address returned by the registry in order to find the final directory
+	// of the target module.
+	finalAddr := dlAddr.FromRegistry(addr)
+	subDir := filepath.FromSlash(finalAddr.Subdir)
+	modDir := filepath.Join(instPath, subDir)
+
+	log.Printf("[TRACE] ModuleInstaller: %s should now be at %s", key, modDir)
+
+	// Finally we are ready to try actually loading the module.
+	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir, req.Call)
+	if mod == nil {
+		// nil indicates missing or unreadable directory, so we'll
+		// discard the returned diags and return a more specific
+		// error message here. For registry modules this actually
+		// indicates a bug in the code above, since it's not the
+		// user's responsibility to create the directory in this case.
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unreadable module directory",
+			Detail:   fmt.Sprintf("The directory %s could not be read. This is a bug in OpenTofu and should be reported.", modDir),
+		})
+	} else if vDiags := mod.CheckCoreVersionRequirements(req.Path, req.SourceAddr); vDiags.HasErrors() {
+		// If the core version requirements are not met, we drop any other
+		// diagnostics, as they may reflect language changes from future
+		// OpenTofu versions.
+		diags = diags.Extend(vDiags)
+	} else {
+		diags = diags.Extend(mDiags)
+	}
+
+	// Note the local location in our manifest.
+	manifest[key] = modsdir.Record{
+		Key:        key,
+		Version:    latestMatch,
+		Dir:        modDir,
+		SourceAddr: req.SourceAddr.String(),
+	}
+	log.Printf("[DEBUG] Module installer: %s installed at %s", key, modDir)
+	hooks.Install(key, latestMatch, modDir)
+
+	return mod, latestMatch, diags
+}
+
+// registryModuleVersions returns the versions of the given registry module
+// that are available, consulting the registry only the first time each module
+// package is requested.
+func (i *ModuleInstaller) registryModuleVersions(ctx context.Context, req *configs.ModuleRequest, key string, addr addrs.ModuleSourceRegistry) (*response.ModuleVersions, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	hostname := addr.Package.Host
 	var resp *response.ModuleVersions
 	var exists bool
 
@@ -509,7 +735,9 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 	} else {
 		var err error
 		log.Printf("[DEBUG] %s listing available versions of %s at %s", key, addr, hostname)
-		resp, err = reg.ModuleVersions(ctx, regsrcAddr)
+		i.waitRemote(func() {
+			resp, err = i.reg.ModuleVersions(ctx, regsrcAddr)
+		})
 		if err != nil {
 			if registry.IsModuleNotFound(err) {
 				suggestion := ""
@@ -537,11 +765,18 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 					Subject:  req.CallRange.Ptr(),
 				})
 			}
-			tracing.SetSpanError(span, diags)
-			return nil, nil, diags
+			return nil, diags
 		}
 		i.registryPackageVersions[packageAddr] = resp
 	}
+	return resp, diags
+}
+
+// selectRegistryModuleVersion chooses the newest of the available versions
+// that meets the module call's version constraint, returning it along with
+// the newest available version regardless of the constraint.
+func selectRegistryModuleVersion(req *configs.ModuleRequest, key string, addr addrs.ModuleSourceRegistry, resp *response.ModuleVersions) (latestMatch, latestVersion *version.Version, diags hcl.Diagnostics) {
+	hostname := addr.Package.Host
 
 	// The response might contain information about dependencies to allow us
 	// to potentially optimize future requests, but we don't currently do that
@@ -561,8 +796,6 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	modMeta := resp.Modules[0]
 
-	var latestMatch *version.Version
-	var latestVersion *version.Version
 	for _, mv := range modMeta.Versions {
 		v, err := version.NewVersion(mv.Version)
 		if err != nil {
@@ -687,8 +920,7 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 			Detail:   fmt.Sprintf("Module %q (%s:%d) has no versions available on %s.", addr, req.CallRange.Filename, req.CallRange.Start.Line, hostname),
 			Subject:  req.CallRange.Ptr(),
 		})
-		tracing.SetSpanError(span, diags)
-		return nil, nil, diags
+		return nil, latestVersion, diags
 	}
 
 	if latestMatch == nil {
@@ -698,134 +930,43 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 			Detail:   fmt.Sprintf("There is no available version of module %q (%s:%d) which matches the given version constraint. The newest available version is %s.", addr, req.CallRange.Filename, req.CallRange.Start.Line, latestVersion),
 			Subject:  req.CallRange.Ptr(),
 		})
-		tracing.SetSpanError(span, diags)
-		return nil, nil, diags
+		return nil, latestVersion, diags
 	}
 
-	// Report up to the caller that we're about to start downloading.
-	hooks.Download(key, packageAddr.String(), latestMatch)
+	return latestMatch, latestVersion, diags
+}
 
-	// If we manage to get down here then we've found a suitable version to
-	// install, so we need to ask the registry where we should download it from.
-	// The response to this is a go-getter-style address string.
+// fetchPackage retrieves the module package at the given physical address
+// into instPath and, if successful, reports its size to the hooks.
+func (i *ModuleInstaller) fetchPackage(ctx context.Context, key, packageAddr, instPath, fetchAddr string, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) error {
+	var err error
+	started := time.Now()
+	i.waitRemote(func() {
+		err = fetcher.FetchPackage(ctx, instPath, fetchAddr)
+	})
+	if err != nil {
+		return err
+	}
+	hooks.Downloaded(key, packageAddr, packageDirSize(instPath), time.Since(started))
+	return nil
+}
 
-	// first check the cache for the download URL
-	moduleAddr := moduleVersion{module: packageAddr, version: latestMatch.String()}
-	if _, exists := i.registryPackageSources[moduleAddr]; !exists {
-		realAddrRaw, err := reg.ModuleLocation(ctx, regsrcAddr, latestMatch.String())
+// packageDirSize returns the total size in bytes of the files in the given
+// directory, or zero if it can't be determined.
+func packageDirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
 		if err != nil {
-			log.Printf("[ERROR] %s from %s %s: %s", key, addr, latestMatch, err)
-			diags = diags.Append(&hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Error accessing remote module registry",
-				Detail:   fmt.Sprintf("Failed to retrieve a download URL for %s %s from %s: %s", addr, latestMatch, hostname, err),
-			})
-			tracing.SetSpanError(span, diags)
-			return nil, nil, diags
+			return nil // we're only reporting on a best-effort basis
 		}
-		realAddr, err := addrs.ParseModuleSource(realAddrRaw)
-		if err != nil {
-			diags = diags.Append(&hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid package location from module registry",
-				Detail:   fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: %s.", hostname, realAddrRaw, addr, latestMatch, err),
-			})
-			tracing.SetSpanError(span, diags)
-			return nil, nil, diags
-		}
-
-		span.SetAttributes(otelAttr.String(traceattrs.ModuleSource, realAddr.String()))
-
-		switch realAddr := realAddr.(type) {
-		// Only a remote source address is allowed here: a registry isn't
-		// allowed to return a local path (because it doesn't know what
-		// its being called from) and we also don't allow recursively pointing
-		// at another registry source for simplicity's sake.
-		case addrs.ModuleSourceRemote:
-			i.registryPackageSources[moduleAddr] = realAddr
-		default:
-			diags = diags.Append(&hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid package location from module registry",
-				Detail:   fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: must be a direct remote package address.", hostname, realAddrRaw, addr, latestMatch),
-			})
-			tracing.SetSpanError(span, diags)
-			return nil, nil, diags
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
 		}
-	}
-
-	dlAddr := i.registryPackageSources[moduleAddr]
-
-	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
-
-	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String())
-	if errors.Is(err, context.Canceled) {
-		diags = diags.Append(&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Module download was interrupted",
-			Detail:   fmt.Sprintf("Interrupt signal received when downloading module %s.", addr),
-		})
-		return nil, nil, diags
-	}
-	if err != nil {
-		// Errors returned by go-getter have very inconsistent quality as
-		// end-user error messages, but for now we're accepting that because
-		// we have no way to recognize any specific errors to improve them
-		// and masking the error entirely would hide valuable diagnostic
-		// information from the user.
-		diags = diags.Append(&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Failed to download module",
-			Detail:   fmt.Sprintf("Could not download module %q (%s:%d) source code from %q: %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, dlAddr, err),
-			Subject:  req.CallRange.Ptr(),
-		})
-		return nil, nil, diags
-	}
-
-	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, dlAddr.Package, instPath)
-
-	// Incorporate any subdir information from the original path into the
-	// address returned by the registry in order to find the final directory
-	// of the target module.
-	finalAddr := dlAddr.FromRegistry(addr)
-	subDir := filepath.FromSlash(finalAddr.Subdir)
-	modDir := filepath.Join(instPath, subDir)
-
-	log.Printf("[TRACE] ModuleInstaller: %s should now be at %s", key, modDir)
-
-	// Finally we are ready to try actually loading the module.
-	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir, req.Call)
-	if mod == nil {
-		// nil indicates missing or unreadable directory, so we'll
-		// discard the returned diags and return a more specific
-		// error message here. For registry modules this actually
-		// indicates a bug in the code above, since it's not the
-		// user's responsibility to create the directory in this case.
-		diags = diags.Append(&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Unreadable module directory",
-			Detail:   fmt.Sprintf("The directory %s could not be read. This is a bug in OpenTofu and should be reported.", modDir),
-		})
-	} else if vDiags := mod.CheckCoreVersionRequirements(req.Path, req.SourceAddr); vDiags.HasErrors() {
-		// If the core version requirements are not met, we drop any other
-		// diagnostics, as they may reflect language changes from future
-		// OpenTofu versions.
-		diags = diags.Extend(vDiags)
-	} else {
-		diags = diags.Extend(mDiags)
-	}
-
-	// Note the local location in our manifest.
-	manifest[key] = modsdir.Record{
-		Key:        key,
-		Version:    latestMatch,
-		Dir:        modDir,
-		SourceAddr: req.SourceAddr.String(),
-	}
-	log.Printf("[DEBUG] Module installer: %s installed at %s", key, modDir)
-	hooks.Install(key, latestMatch, modDir)
-
-	return mod, latestMatch, diags
+		return nil
+	})
+	return size
 }
 
 func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
@@ -858,7 +999,7 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	err := i.fetchPackage(ctx, key, packageAddr.String(), instPath, packageAddr.String(), hooks, fetcher)
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
@@ -6,6 +6,8 @@
 package initwd
 
 import (
+	"time"
+
 	version "github.com/hashicorp/go-version"
 )
 
@@ -21,6 +23,11 @@ type ModuleInstallHooks interface {
 	// on progress through a possibly-long sequence of downloads.
 	Download(moduleAddr, packageAddr string, version *version.Version)
 
+	// Downloaded is called after a module package is retrieved from a remote
+	// source, with the total size in bytes of the files that were retrieved
+	// and how long it took.
+	Downloaded(moduleAddr, packageAddr string, size int64, elapsed time.Duration)
+
 	// Install is called for each module that is installed, even if it did
 	// not need to be downloaded from a remote source.
 	Install(moduleAddr string, version *version.Version, localPath string)
@@ -35,6 +42,9 @@ type ModuleInstallHooksImpl struct {
 func (h ModuleInstallHooksImpl) Download(moduleAddr, packageAddr string, version *version.Version) {
 }
 
+func (h ModuleInstallHooksImpl) Downloaded(moduleAddr, packageAddr string, size int64, elapsed time.Duration) {
+}
+
 func (h ModuleInstallHooksImpl) Install(moduleAddr string, version *version.Version, localPath string) {
 }
 
@@ -11,8 +11,10 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-test/deep"
@@ -97,6 +99,66 @@ func TestModuleInstaller(t *testing.T) {
 	assertResultDeepEqual(t, gotTraces, wantTraces)
 }
 
+func TestModuleInstaller_concurrent(t *testing.T) {
+	fixtureDir := filepath.Clean("testdata/local-modules-concurrent")
+	dir := tempChdir(t, fixtureDir)
+
+	hooks := &testInstallHooks{}
+
+	modulesDir := filepath.Join(dir, ".terraform/modules")
+	loader := configload.NewLoaderForTests(t)
+	inst := NewModuleInstaller(modulesDir, loader, nil, nil)
+	inst.SetConcurrency(4)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	// Sibling modules are installed concurrently, so the hooks can be
+	// called in any order.
+	var gotInstalls []string
+	for _, call := range hooks.Calls {
+		gotInstalls = append(gotInstalls, call.Name+" "+call.ModuleAddr)
+	}
+	sort.Strings(gotInstalls)
+	wantInstalls := []string{
+		"Install child_a",
+		"Install child_a.grandchild",
+		"Install child_b",
+		"Install child_b.grandchild",
+		"Install child_c",
+		"Install child_c.grandchild",
+	}
+	if assertResultDeepEqual(t, gotInstalls, wantInstalls) {
+		return
+	}
+
+	loader, err := configload.NewLoader(&configload.Config{
+		ModulesDir: modulesDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Make sure the configuration is loadable now.
+	// (This ensures that correct information is recorded in the manifest.)
+	config, loadDiags := loader.LoadConfig(t.Context(), ".", configs.RootModuleCallForTesting())
+	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
+
+	wantTraces := map[string]string{
+		"":                   "in root module",
+		"child_a":            "in child_a module",
+		"child_a.grandchild": "in child_a grandchild module",
+		"child_b":            "in child_b module",
+		"child_b.grandchild": "in child_b grandchild module",
+		"child_c":            "in child_c module",
+		"child_c.grandchild": "in child_c grandchild module",
+	}
+	gotTraces := map[string]string{}
+	config.DeepEach(func(c *configs.Config) {
+		gotTraces[strings.Join(c.Path, ".")] = c.Module.Variables["v"].Description
+	})
+	assertResultDeepEqual(t, gotTraces, wantTraces)
+}
+
 func TestModuleInstaller_error(t *testing.T) {
 	fixtureDir := filepath.Clean("testdata/local-module-error")
 	dir := tempChdir(t, fixtureDir)
@@ -920,6 +982,11 @@ func (h *testInstallHooks) Download(moduleAddr, packageAddr string, version *ver
 	})
 }
 
+// Downloaded isn't recorded because the reported size and duration vary
+// between runs.
+func (h *testInstallHooks) Downloaded(moduleAddr, packageAddr string, size int64, elapsed time.Duration) {
+}
+
 func (h *testInstallHooks) Install(moduleAddr string, version *version.Version, localPath string) {
 	h.Calls = append(h.Calls, testInstallHookCall{
 		Name:       "Install",
@@ -0,0 +1,166 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/modsdir"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ModuleUpgrade describes what upgrading a single module call would do, as
+// reported by [ModuleInstaller.ModuleUpgrades].
+type ModuleUpgrade struct {
+	// Key is the module's key in the modules manifest, such as "vpc" or
+	// "vpc.subnets".
+	Key string
+
+	SourceAddr addrs.ModuleSource
+	Constraint version.Constraints
+
+	// Installed is true if the module is currently installed from the same
+	// source address, in which case Current is its installed version, or nil
+	// if its source isn't versioned.
+	Installed bool
+	Current   *version.Version
+
+	// Selected is the version that an upgrade would install and Newest is the
+	// newest version available regardless of the version constraint. Both
+	// are nil for module sources that aren't versioned.
+	Selected *version.Version
+	Newest   *version.Version
+}
+
+// ModuleUpgrades determines which version of each of the remote modules
+// called from the root module in the given directory would be installed by
+// InstallModules with the upgrade flag set, without changing anything in the
+// modules directory.
+//
+// Only the installed copies of modules are inspected for calls to further
+// modules, so the result doesn't include the descendents of any module that
+// isn't already installed from the same source address.
+//
+// Local modules have no versions of their own and so aren't included in the
+// result, but the modules they call are.
+func (i *ModuleInstaller) ModuleUpgrades(ctx context.Context, rootDir, testsDir string, call configs.StaticModuleCall) ([]ModuleUpgrade, tfdiags.Diagnostics) {
+	log.Printf("[TRACE] ModuleInstaller: finding module upgrades for %s", rootDir)
+	var diags tfdiags.Diagnostics
+
+	rootMod, mDiags := i.loader.Parser().LoadConfigDirWithTests(rootDir, testsDir, call)
+	if mDiags.HasErrors() {
+		diags = diags.Append(mDiags)
+		return nil, diags
+	}
+
+	manifest, err := modsdir.ReadManifestSnapshotForDir(i.modsDir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read modules manifest file",
+			fmt.Sprintf("Error reading manifest for %s: %s.", i.modsDir, err),
+		))
+		return nil, diags
+	}
+
+	// dirs tracks where we found each module we've visited, so that we can
+	// find the directories of local modules relative to their parents.
+	dirs := map[string]string{"": rootDir}
+	var ret []ModuleUpgrade
+	var walkDiags hcl.Diagnostics
+	i.sem = nil
+	walker := configs.ModuleWalkerFunc(
+		func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+			if req.SourceAddr == nil || req.Name == "" || !hclsyntax.ValidIdentifier(req.Name) {
+				// The configuration loader will report these problems when
+				// the modules are actually installed.
+				return nil, nil, nil
+			}
+
+			key := manifest.ModuleKey(req.Path)
+			record, recorded := manifest[key]
+			installed := recorded && record.SourceAddr == req.SourceAddr.String()
+			upgrade := ModuleUpgrade{
+				Key:        key,
+				SourceAddr: req.SourceAddr,
+				Constraint: req.VersionConstraint.Required,
+				Installed:  installed,
+			}
+			if installed {
+				upgrade.Current = record.Version
+			}
+
+			switch addr := req.SourceAddr.(type) {
+			case addrs.ModuleSourceLocal:
+				parentDir, ok := dirs[manifest.ModuleKey(req.Parent.Path)]
+				if !ok {
+					return nil, nil, nil
+				}
+				dir := filepath.Join(parentDir, addr.String())
+				dirs[key] = dir
+				return i.loadUpgradeModule(dir, req.Call), nil, nil
+
+			case addrs.ModuleSourceRegistry:
+				if i.reg == nil {
+					walkDiags = walkDiags.Append(&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Registry-style module sources not supported",
+						Detail:   "Only local module sources are supported in this context.",
+						Subject:  req.CallRange.Ptr(),
+					})
+					return nil, nil, nil
+				}
+				resp, moreDiags := i.registryModuleVersions(ctx, req, key, addr)
+				walkDiags = append(walkDiags, moreDiags...)
+				if !moreDiags.HasErrors() {
+					var moreDiags hcl.Diagnostics
+					upgrade.Selected, upgrade.Newest, moreDiags = selectRegistryModuleVersion(req, key, addr, resp)
+					walkDiags = append(walkDiags, moreDiags...)
+				}
+			}
+			ret = append(ret, upgrade)
+
+			if !installed {
+				return nil, nil, nil
+			}
+			dirs[key] = record.Dir
+			return i.loadUpgradeModule(record.Dir, req.Call), record.Version, nil
+		},
+	)
+
+	// We only want the diagnostics about the module sources here. Any
+	// problems with the configuration itself will be reported when the
+	// modules are really installed.
+	_, _ = configs.BuildConfig(ctx, rootMod, walker)
+	diags = diags.Append(walkDiags)
+
+	sort.Slice(ret, func(a, b int) bool {
+		return ret[a].Key < ret[b].Key
+	})
+	return ret, diags
+}
+
+// loadUpgradeModule loads the module in the given directory for
+// ModuleUpgrades to find its module calls, returning nil if the directory
+// doesn't contain a readable module.
+func (i *ModuleInstaller) loadUpgradeModule(dir string, call configs.StaticModuleCall) *configs.Module {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	mod, _ := i.loader.Parser().LoadConfigDir(dir, call)
+	return mod
+}
@@ -0,0 +1,85 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	version "github.com/hashicorp/go-version"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/modsdir"
+	"github.com/opentofu/opentofu/internal/registry"
+	"github.com/opentofu/opentofu/internal/registry/test"
+)
+
+func TestModuleInstaller_ModuleUpgrades(t *testing.T) {
+	server := test.Registry()
+	defer server.Close()
+
+	dir := tempChdir(t, filepath.Clean("testdata/module-upgrades"))
+	modulesDir := filepath.Join(dir, ".terraform/modules")
+
+	// "child" is already installed at an older version that still meets its
+	// version constraint, while "local.nested" isn't installed at all.
+	childDir := filepath.Join(modulesDir, "child")
+	if err := os.MkdirAll(childDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(childDir, "main.tf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := modsdir.Manifest{
+		"child": {
+			Key:        "child",
+			SourceAddr: "example.com/test-versions/name/provider",
+			Version:    version.Must(version.NewVersion("1.2.1")),
+			Dir:        childDir,
+		},
+	}
+	if err := manifest.WriteSnapshotToDir(modulesDir); err != nil {
+		t.Fatal(err)
+	}
+	wantManifest, err := modsdir.ReadManifestSnapshotForDir(modulesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := configload.NewLoaderForTests(t)
+	reg := registry.NewClient(t.Context(), test.Disco(server), nil)
+	inst := NewModuleInstaller(modulesDir, loader, reg, nil)
+	upgrades, diags := inst.ModuleUpgrades(t.Context(), ".", "tests", configs.RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	var got []string
+	for _, upgrade := range upgrades {
+		got = append(got, fmt.Sprintf("%s installed=%t current=%s selected=%s newest=%s", upgrade.Key, upgrade.Installed, upgrade.Current, upgrade.Selected, upgrade.Newest))
+	}
+	want := []string{
+		"child installed=true current=1.2.1 selected=1.2.2 newest=2.2.0",
+		"local.nested installed=false current=<nil> selected=2.1.1 newest=2.2.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong upgrades\n%s", diff)
+	}
+
+	// Finding the upgrades must not change what's installed.
+	gotManifest, err := modsdir.ReadManifestSnapshotForDir(modulesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantManifest, gotManifest); diff != "" {
+		t.Errorf("manifest changed\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(modulesDir, "local.nested")); !os.IsNotExist(err) {
+		t.Errorf("local.nested was installed")
+	}
+}
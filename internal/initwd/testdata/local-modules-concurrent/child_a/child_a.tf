@@ -0,0 +1,8 @@
+variable "v" {
+  description = "in child_a module"
+  default     = ""
+}
+
+module "grandchild" {
+  source = "./grandchild"
+}
@@ -0,0 +1,8 @@
+variable "v" {
+  description = "in child_b module"
+  default     = ""
+}
+
+module "grandchild" {
+  source = "./grandchild"
+}
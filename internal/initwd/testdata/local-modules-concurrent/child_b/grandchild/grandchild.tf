@@ -0,0 +1,4 @@
+variable "v" {
+  description = "in child_b grandchild module"
+  default     = ""
+}
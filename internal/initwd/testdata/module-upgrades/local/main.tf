@@ -0,0 +1,4 @@
+module "nested" {
+  source  = "example.com/test-versions/name/provider"
+  version = ">= 2.0.0, < 2.2.0"
+}
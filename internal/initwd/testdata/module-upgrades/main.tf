@@ -0,0 +1,11 @@
+# The test registry has versions 2.2.0, 2.1.1, 1.2.2 and 1.2.1 of
+# test-versions/name/provider.
+
+module "child" {
+  source  = "example.com/test-versions/name/provider"
+  version = "~> 1.2.0"
+}
+
+module "local" {
+  source = "./local"
+}
@@ -64,6 +64,17 @@ func (e *Expander) SetModuleCount(parentAddr addrs.ModuleInstance, callAddr addr
 	e.setModuleExpansion(parentAddr, callAddr, expansionCount(count))
 }
 
+// SetModuleEnabled records that the given module call inside the given parent
+// module instance uses the "enabled" lifecycle argument, with the given value,
+// and is therefore either a singleton or has no instances at all.
+func (e *Expander) SetModuleEnabled(parentAddr addrs.ModuleInstance, callAddr addrs.ModuleCall, enabled bool) {
+	if enabled {
+		e.setModuleExpansion(parentAddr, callAddr, expansionSingleVal)
+	} else {
+		e.setModuleExpansion(parentAddr, callAddr, expansionCount(0))
+	}
+}
+
 // SetModuleForEach records that the given module call inside the given parent
 // module instance uses the "for_each" repetition argument, with the given
 // map value.
@@ -87,6 +98,17 @@ func (e *Expander) SetResourceCount(moduleAddr addrs.ModuleInstance, resourceAdd
 	e.setResourceExpansion(moduleAddr, resourceAddr, expansionCount(count))
 }
 
+// SetResourceEnabled records that the given resource inside the given module
+// uses the "enabled" lifecycle argument, with the given value, and is
+// therefore either a singleton or has no instances at all.
+func (e *Expander) SetResourceEnabled(moduleAddr addrs.ModuleInstance, resourceAddr addrs.Resource, enabled bool) {
+	if enabled {
+		e.setResourceExpansion(moduleAddr, resourceAddr, expansionSingleVal)
+	} else {
+		e.setResourceExpansion(moduleAddr, resourceAddr, expansionCount(0))
+	}
+}
+
 // SetResourceForEach records that the given resource inside the given module
 // uses the "for_each" repetition argument, with the given map value.
 //
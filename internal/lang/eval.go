@@ -248,6 +248,12 @@ func enhanceFunctionDiag(diag *hcl.Diagnostic, funcExtra hclsyntax.FunctionCallU
 		// Error is in core namespace, mirror non-core equivalent
 		enhanced.Summary = "Call to unknown function"
 		enhanced.Detail = fmt.Sprintf("There is no builtin (%s::) function named %q.", addrs.FunctionNamespaceCore, funcName)
+	} else if fn.IsNamespace(addrs.FunctionNamespaceLocal) && len(fn.Namespaces) == 1 {
+		enhanced.Summary = "Call to unknown function"
+		enhanced.Detail = fmt.Sprintf("There is no function named %q declared in this module.", funcName)
+	} else if fn.IsNamespace(addrs.FunctionNamespaceModule) && len(fn.Namespaces) == 2 {
+		enhanced.Summary = "Call to unknown function"
+		enhanced.Detail = fmt.Sprintf("There is no function named %q exported by a module call named %q in this module.", funcName, fn.Namespaces[1])
 	} else if fn.IsNamespace(addrs.FunctionNamespaceProvider) {
 		if _, err := fn.AsProviderFunction(); err != nil {
 			// complete mismatch or invalid prefix
@@ -969,7 +969,7 @@ func Test_enhanceFunctionDiags(t *testing.T) {
 			"Invalid prefix",
 			"attr = magic::missing_function(54)",
 			"Unknown function namespace",
-			"Function \"magic::missing_function\" does not exist within a valid namespace (provider,core)",
+			"Function \"magic::missing_function\" does not exist within a valid namespace (provider,core,local,module)",
 		},
 		{
 			"Too many namespaces",
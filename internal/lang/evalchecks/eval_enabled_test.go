@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package evalchecks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEvaluateEnabledExpression(t *testing.T) {
+	tests := map[string]struct {
+		val             cty.Value
+		want            bool
+		WantSummary     string
+		WantDetail      string
+		CausedByUnknown bool
+	}{
+		"true": {
+			val:  cty.True,
+			want: true,
+		},
+		"false": {
+			val:  cty.False,
+			want: false,
+		},
+		"sensitive": {
+			val:  cty.True.Mark(marks.Sensitive),
+			want: true,
+		},
+		"null": {
+			val:         cty.NullVal(cty.Bool),
+			WantSummary: "Invalid enabled argument",
+			WantDetail:  `The given "enabled" argument value is null. A boolean is required.`,
+		},
+		"unknown": {
+			val:             cty.UnknownVal(cty.Bool),
+			WantSummary:     "Invalid enabled argument",
+			WantDetail:      `The "enabled" value depends on resource attributes that cannot be determined until apply`,
+			CausedByUnknown: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr := addrs.RootModuleInstance.Resource(addrs.ManagedResourceMode, "test", "a")
+			got, diags := EvaluateEnabledExpression(hcltest.MockExprLiteral(test.val), mockEvaluateFunc(test.val), addr)
+
+			if test.WantSummary == "" {
+				if len(diags) != 0 {
+					t.Fatalf("unexpected diagnostics: %s", diags.Err())
+				}
+				if got != test.want {
+					t.Errorf("wrong result %t; want %t", got, test.want)
+				}
+				return
+			}
+
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics; want 1", len(diags))
+			}
+			if got, want := diags[0].Description().Summary, test.WantSummary; got != want {
+				t.Errorf("wrong diagnostic summary\ngot:  %s\nwant: %s", got, want)
+			}
+			if got, want := diags[0].Description().Detail, test.WantDetail; !strings.Contains(got, want) {
+				t.Errorf("wrong diagnostic detail\ngot:  %s\nwant substring: %s", got, want)
+			}
+			if got, want := tfdiags.DiagnosticCausedByUnknown(diags[0]), test.CausedByUnknown; got != want {
+				t.Errorf("wrong result from tfdiags.DiagnosticCausedByUnknown\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ package funcs
 import (
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/apparentlymart/go-cidr/cidr"
 	"github.com/opentofu/opentofu/internal/ipaddr"
@@ -270,6 +271,197 @@ var CidrContainsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrOverlapsFunc constructs a function that checks whether two IP network
+// address prefixes have any addresses in common.
+var CidrOverlapsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix_a",
+			Type: cty.String,
+		},
+		{
+			Name: "prefix_b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, a, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		_, b, err := ipaddr.ParseCIDR(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid CIDR expression: %s", err)
+		}
+
+		// As with cidrcontains, we return an error for prefixes of different
+		// address families rather than a result that is always false.
+		if len(a.IP) != len(b.IP) {
+			return cty.UnknownVal(cty.Bool), fmt.Errorf("address family mismatch: %s vs. %s", args[0].AsString(), args[1].AsString())
+		}
+
+		// Two prefixes overlap only if one of them contains the other, and
+		// so contains its first address.
+		return cty.BoolVal(a.Contains(b.IP) || b.Contains(a.IP)), nil
+	},
+})
+
+// CidrMergeFunc constructs a function that merges a collection of IP network
+// address prefixes into the smallest list of prefixes that covers exactly the
+// same addresses.
+var CidrMergeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefixes",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		var ranges []cidrRange
+		for it := args[0].ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "prefixes must not contain null values")
+			}
+			_, network, err := ipaddr.ParseCIDR(v.AsString())
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+			}
+			first, last := cidr.AddressRange(network)
+			ranges = append(ranges, cidrRange{
+				bits:  len(network.IP) * 8,
+				first: new(big.Int).SetBytes(first),
+				last:  new(big.Int).SetBytes(last),
+			})
+		}
+		if len(ranges) == 0 {
+			return cty.ListValEmpty(cty.String), nil
+		}
+
+		// We sort all of the IPv4 ranges before the IPv6 ones, and then by
+		// their first address, so that each range can only be merged with
+		// the one before it.
+		sort.Slice(ranges, func(i, j int) bool {
+			if ranges[i].bits != ranges[j].bits {
+				return ranges[i].bits < ranges[j].bits
+			}
+			return ranges[i].first.Cmp(ranges[j].first) < 0
+		})
+		merged := ranges[:1]
+		for _, r := range ranges[1:] {
+			current := &merged[len(merged)-1]
+			next := new(big.Int).Add(current.last, big.NewInt(1))
+			if r.bits == current.bits && r.first.Cmp(next) <= 0 {
+				if r.last.Cmp(current.last) > 0 {
+					current.last = r.last
+				}
+				continue
+			}
+			merged = append(merged, r)
+		}
+
+		var retVals []cty.Value
+		for _, r := range merged {
+			for _, prefix := range r.prefixes() {
+				retVals = append(retVals, cty.StringVal(prefix))
+			}
+		}
+		return cty.ListVal(retVals), nil
+	},
+})
+
+// CidrSplitFunc constructs a function that splits an IP network address
+// prefix into all of the subnets of a given, longer prefix length.
+var CidrSplitFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "newbits",
+			Type: cty.Number,
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		var newbits int
+		if err := gocty.FromCtyValue(args[1], &newbits); err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(1, err)
+		}
+
+		if newbits < 1 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "must extend prefix by at least one bit")
+		}
+		// Each additional bit doubles the number of subnets, so we limit
+		// the extension to keep the result to a manageable size.
+		if newbits > 16 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "may not extend prefix by more than 16 bits")
+		}
+		prefixLen, addrLen := network.Mask.Size()
+		if prefixLen+newbits > addrLen {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "would extend prefix to %d bits, which is too long for an address of %d bits", prefixLen+newbits, addrLen)
+		}
+
+		retVals := make([]cty.Value, 1<<newbits)
+		for i := range retVals {
+			subnet, err := cidr.Subnet(network, newbits, i)
+			if err != nil {
+				return cty.UnknownVal(retType), err
+			}
+			retVals[i] = cty.StringVal(subnet.String())
+		}
+		return cty.ListVal(retVals), nil
+	},
+})
+
+// cidrRange is a range of consecutive IP addresses, each represented as an
+// integer of the given number of bits.
+type cidrRange struct {
+	bits        int
+	first, last *big.Int
+}
+
+// prefixes returns the smallest list of prefixes, in CIDR notation, that
+// together contain exactly the addresses in the range.
+func (r cidrRange) prefixes() []string {
+	var ret []string
+	first := new(big.Int).Set(r.first)
+	for first.Cmp(r.last) <= 0 {
+		// The largest prefix that starts at the first address is limited by
+		// the number of trailing zero bits in that address, and then by the
+		// end of the range.
+		size := r.bits
+		if first.Sign() != 0 {
+			size = int(first.TrailingZeroBits())
+		}
+		for {
+			end := new(big.Int).Lsh(big.NewInt(1), uint(size))
+			end.Add(end, first)
+			end.Sub(end, big.NewInt(1))
+			if end.Cmp(r.last) <= 0 {
+				break
+			}
+			size--
+		}
+
+		ip := ipaddr.IP(first.FillBytes(make([]byte, r.bits/8)))
+		ret = append(ret, fmt.Sprintf("%s/%d", ip, r.bits-size))
+
+		first.Add(first, new(big.Int).Lsh(big.NewInt(1), uint(size)))
+	}
+	return ret
+}
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
@@ -298,3 +490,21 @@ func CidrSubnets(prefix cty.Value, newbits ...cty.Value) (cty.Value, error) {
 func CidrContains(prefix, address cty.Value) (cty.Value, error) {
 	return CidrContainsFunc.Call([]cty.Value{prefix, address})
 }
+
+// CidrOverlaps checks whether two IP network address prefixes have any
+// addresses in common.
+func CidrOverlaps(prefixA, prefixB cty.Value) (cty.Value, error) {
+	return CidrOverlapsFunc.Call([]cty.Value{prefixA, prefixB})
+}
+
+// CidrMerge merges a list of IP network address prefixes into the smallest
+// list of prefixes that covers exactly the same addresses.
+func CidrMerge(prefixes cty.Value) (cty.Value, error) {
+	return CidrMergeFunc.Call([]cty.Value{prefixes})
+}
+
+// CidrSplit splits an IP network address prefix into all of its subnets that
+// are longer by the given number of bits.
+func CidrSplit(prefix, newbits cty.Value) (cty.Value, error) {
+	return CidrSplitFunc.Call([]cty.Value{prefix, newbits})
+}
@@ -520,3 +520,284 @@ func TestCidrContains(t *testing.T) {
 		})
 	}
 }
+
+func TestCidrOverlaps(t *testing.T) {
+	tests := []struct {
+		PrefixA cty.Value
+		PrefixB cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.StringVal("10.0.128.0/24"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("10.0.128.0/24"),
+			cty.StringVal("10.0.0.0/16"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cty.StringVal("10.0.1.0/24"),
+			cty.False,
+			``,
+		},
+		{
+			cty.StringVal("fe80::/48"),
+			cty.StringVal("fe80:0:1::/64"),
+			cty.False,
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.StringVal("fe80::/48"),
+			cty.UnknownVal(cty.Bool),
+			`address family mismatch: 10.0.0.0/16 vs. fe80::/48`,
+		},
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.StringVal("10.0.0.1"),
+			cty.UnknownVal(cty.Bool),
+			`invalid CIDR expression: invalid CIDR address: 10.0.0.1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidroverlaps(%#v, %#v)", test.PrefixA, test.PrefixB), func(t *testing.T) {
+			got, err := CidrOverlaps(test.PrefixA, test.PrefixB)
+			wantErr := test.Err != ""
+
+			if wantErr {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrMerge(t *testing.T) {
+	tests := []struct {
+		Prefixes cty.Value
+		Want     cty.Value
+		Err      string
+	}{
+		{
+			cty.ListValEmpty(cty.String),
+			cty.ListValEmpty(cty.String),
+			``,
+		},
+		{
+			// Adjacent prefixes are merged into their common prefix.
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.1.0/24"),
+				cty.StringVal("10.0.0.0/24"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/23"),
+			}),
+			``,
+		},
+		{
+			// Contained prefixes are dropped.
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/16"),
+				cty.StringVal("10.0.3.0/24"),
+				cty.StringVal("10.0.3.0/24"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/16"),
+			}),
+			``,
+		},
+		{
+			// Adjacent prefixes that can't be covered by a single prefix
+			// are returned as the fewest prefixes that cover them.
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.1.0/24"),
+				cty.StringVal("10.0.2.0/24"),
+				cty.StringVal("192.168.0.0/24"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.1.0/24"),
+				cty.StringVal("10.0.2.0/24"),
+				cty.StringVal("192.168.0.0/24"),
+			}),
+			``,
+		},
+		{
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.1.0/24"),
+				cty.StringVal("10.0.2.0/23"),
+				cty.StringVal("10.0.0.0/24"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/22"),
+			}),
+			``,
+		},
+		{
+			// IPv4 prefixes come before IPv6 prefixes, which aren't merged
+			// with them.
+			cty.ListVal([]cty.Value{
+				cty.StringVal("fe80:0:0:1::/64"),
+				cty.StringVal("fe80::/64"),
+				cty.StringVal("0.0.0.0/1"),
+				cty.StringVal("128.0.0.0/1"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("0.0.0.0/0"),
+				cty.StringVal("fe80::/63"),
+			}),
+			``,
+		},
+		{
+			// The host part of a prefix is ignored.
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.5/24"),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/24"),
+			}),
+			``,
+		},
+		{
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/24"),
+				cty.StringVal("not-a-prefix"),
+			}),
+			cty.UnknownVal(cty.List(cty.String)),
+			`invalid CIDR expression: invalid CIDR address: not-a-prefix`,
+		},
+		{
+			cty.ListVal([]cty.Value{
+				cty.NullVal(cty.String),
+			}),
+			cty.UnknownVal(cty.List(cty.String)),
+			`prefixes must not contain null values`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrmerge(%#v)", test.Prefixes), func(t *testing.T) {
+			got, err := CidrMerge(test.Prefixes)
+			wantErr := test.Err != ""
+
+			if wantErr {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrSplit(t *testing.T) {
+	tests := []struct {
+		Prefix  cty.Value
+		Newbits cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.NumberIntVal(2),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/18"),
+				cty.StringVal("10.0.64.0/18"),
+				cty.StringVal("10.0.128.0/18"),
+				cty.StringVal("10.0.192.0/18"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("fe80::/48"),
+			cty.NumberIntVal(1),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("fe80::/49"),
+				cty.StringVal("fe80:0:0:8000::/49"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/31"),
+			cty.NumberIntVal(1),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/32"),
+				cty.StringVal("10.0.0.1/32"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.NumberIntVal(0),
+			cty.UnknownVal(cty.List(cty.String)),
+			`must extend prefix by at least one bit`,
+		},
+		{
+			cty.StringVal("fe80::/48"),
+			cty.NumberIntVal(17),
+			cty.UnknownVal(cty.List(cty.String)),
+			`may not extend prefix by more than 16 bits`,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cty.NumberIntVal(9),
+			cty.UnknownVal(cty.List(cty.String)),
+			`would extend prefix to 33 bits, which is too long for an address of 32 bits`,
+		},
+		{
+			cty.StringVal("not-a-prefix"),
+			cty.NumberIntVal(1),
+			cty.UnknownVal(cty.List(cty.String)),
+			`invalid CIDR expression: invalid CIDR address: not-a-prefix`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrsplit(%#v, %#v)", test.Prefix, test.Newbits), func(t *testing.T) {
+			got, err := CidrSplit(test.Prefix, test.Newbits)
+			wantErr := test.Err != ""
+
+			if wantErr {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -624,6 +624,80 @@ var TransposeFunc = function.New(&function.Spec{
 	},
 })
 
+// DeepMergeFunc constructs a function that takes an arbitrary number of maps
+// or objects and returns a single object containing a merged set of elements
+// from all of them. Unlike the merge function, nested maps and objects that
+// appear in more than one argument under the same key are merged recursively,
+// rather than replaced.
+var DeepMergeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{},
+	VarParam: &function.Parameter{
+		Name:             "maps",
+		Type:             cty.DynamicPseudoType,
+		AllowUnknown:     true,
+		AllowDynamicType: true,
+		AllowNull:        true,
+		AllowMarked:      true,
+	},
+	Type: func(args []cty.Value) (ret cty.Type, err error) {
+		for i, arg := range args {
+			ty := arg.Type()
+			if !ty.IsObjectType() && !ty.IsMapType() && ty != cty.DynamicPseudoType {
+				return cty.NilType, function.NewArgErrorf(i, "arguments must be maps or objects, got %s", ty.FriendlyName())
+			}
+		}
+		for _, arg := range args {
+			if !arg.IsKnown() {
+				return cty.DynamicPseudoType, nil
+			}
+		}
+		return deepMerge(args).Type(), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		if retType == cty.DynamicPseudoType {
+			return cty.DynamicVal, nil
+		}
+		return deepMerge(args), nil
+	},
+})
+
+// deepMerge merges the given known maps and objects, ignoring any nulls. When
+// more than one of them has a map or object under the same key those are
+// merged too, and otherwise the value from the last argument wins.
+func deepMerge(vals []cty.Value) cty.Value {
+	attrs := make(map[string]cty.Value)
+	var valMarks []cty.ValueMarks
+	for _, val := range vals {
+		val, m := val.Unmark()
+		valMarks = append(valMarks, m)
+		if val.IsNull() {
+			continue
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			key := k.AsString()
+			existing, exists := attrs[key]
+			if exists && deepMergeable(existing) && deepMergeable(v) {
+				if existing.IsKnown() && v.IsKnown() {
+					v = deepMerge([]cty.Value{existing, v})
+				} else {
+					// We can't tell which attributes the result will have
+					// until both values are known.
+					v = cty.DynamicVal.WithMarks(existing.Marks(), v.Marks())
+				}
+			}
+			attrs[key] = v
+		}
+	}
+	return cty.ObjectVal(attrs).WithMarks(valMarks...)
+}
+
+func deepMergeable(val cty.Value) bool {
+	ty := val.Type()
+	return (ty.IsObjectType() || ty.IsMapType()) && !val.IsNull()
+}
+
 // ListFunc constructs a function that takes an arbitrary number of arguments
 // and returns a list containing those values in the same order.
 //
@@ -689,6 +763,12 @@ func Coalesce(args ...cty.Value) (cty.Value, error) {
 	return CoalesceFunc.Call(args)
 }
 
+// DeepMerge takes an arbitrary number of maps or objects, and returns a single
+// object that contains a recursively merged set of elements from all of them.
+func DeepMerge(maps ...cty.Value) (cty.Value, error) {
+	return DeepMergeFunc.Call(maps)
+}
+
 // Index finds the element index for a given value in a list.
 func Index(list, value cty.Value) (cty.Value, error) {
 	return IndexFunc.Call([]cty.Value{list, value})
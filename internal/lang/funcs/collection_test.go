@@ -454,6 +454,138 @@ func TestCoalesce(t *testing.T) {
 	}
 }
 
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		Values []cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a"),
+					"nested": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.StringVal("b"),
+						"c": cty.StringVal("c"),
+					}),
+				}),
+				cty.MapVal(map[string]cty.Value{
+					"nested": cty.MapVal(map[string]cty.Value{
+						"c": cty.StringVal("C"),
+						"d": cty.StringVal("D"),
+					}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"nested": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.StringVal("b"),
+					"c": cty.StringVal("C"),
+					"d": cty.StringVal("D"),
+				}),
+			}),
+			false,
+		},
+		{
+			// Values that aren't maps or objects are replaced rather than merged,
+			// even when they are collections.
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"list":   cty.ListVal([]cty.Value{cty.StringVal("a")}),
+					"nested": cty.ObjectVal(map[string]cty.Value{"b": cty.StringVal("b")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"list":   cty.ListVal([]cty.Value{cty.StringVal("b")}),
+					"nested": cty.StringVal("replaced"),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"list":   cty.ListVal([]cty.Value{cty.StringVal("b")}),
+				"nested": cty.StringVal("replaced"),
+			}),
+			false,
+		},
+		{
+			[]cty.Value{
+				cty.NullVal(cty.DynamicPseudoType),
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+				cty.NullVal(cty.Map(cty.String)),
+			},
+			cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+			false,
+		},
+		{
+			[]cty.Value{},
+			cty.EmptyObjectVal,
+			false,
+		},
+		{
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"nested": cty.ObjectVal(map[string]cty.Value{"b": cty.StringVal("b")}),
+					"other":  cty.ObjectVal(map[string]cty.Value{"b": cty.StringVal("b")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"nested": cty.UnknownVal(cty.Map(cty.String)),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"nested": cty.DynamicVal,
+				"other":  cty.ObjectVal(map[string]cty.Value{"b": cty.StringVal("b")}),
+			}),
+			false,
+		},
+		{
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+				cty.UnknownVal(cty.Map(cty.String)),
+			},
+			cty.DynamicVal,
+			false,
+		},
+		{
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}).Mark(marks.Sensitive),
+				cty.ObjectVal(map[string]cty.Value{
+					"b": cty.StringVal("b").Mark(marks.Sensitive),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.StringVal("b").Mark(marks.Sensitive),
+			}).Mark(marks.Sensitive),
+			false,
+		},
+		{
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+				cty.ListVal([]cty.Value{cty.StringVal("b")}),
+			},
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("DeepMerge(%#v...)", test.Values), func(t *testing.T) {
+			got, err := DeepMerge(test.Values...)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestIndex(t *testing.T) {
 	tests := []struct {
 		List  cty.Value
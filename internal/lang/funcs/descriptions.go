@@ -82,6 +82,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`can` evaluates the given expression and returns a boolean value indicating whether the expression produced a result without any errors.",
 		ParamDescription: []string{""},
 	},
+	"catch": {
+		Description:      "`catch` evaluates the given expression and returns an object with attributes `ok`, `value` and `error` that describes whether the expression produced a result without any errors, the result, and the error message.",
+		ParamDescription: []string{""},
+	},
 	"ceil": {
 		Description:      "`ceil` returns the closest whole number that is greater than or equal to the given value, which may be a fraction.",
 		ParamDescription: []string{""},
@@ -111,12 +115,32 @@ var DescriptionList = map[string]descriptionEntry{
 			"`hostnum` is a whole number that can be represented as a binary integer with no more than the number of digits remaining in the address after the given prefix.",
 		},
 	},
+	"cidrmerge": {
+		Description: "`cidrmerge` merges a list of IP network address prefixes into the smallest list of prefixes that covers exactly the same addresses.",
+		ParamDescription: []string{
+			"`prefixes` is a list of address prefixes given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+		},
+	},
 	"cidrnetmask": {
 		Description: "`cidrnetmask` converts an IPv4 address prefix given in CIDR notation into a subnet mask address.",
 		ParamDescription: []string{
 			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
 		},
 	},
+	"cidroverlaps": {
+		Description: "`cidroverlaps` determines whether two IP network address prefixes given in CIDR notation have any addresses in common.",
+		ParamDescription: []string{
+			"`prefix_a` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`prefix_b` must be given in CIDR notation, and must belong to the same address family as `prefix_a`.",
+		},
+	},
+	"cidrsplit": {
+		Description: "`cidrsplit` splits an IP network address prefix into all of the subnets that extend it by a given number of bits.",
+		ParamDescription: []string{
+			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`newbits` is the number of additional bits with which to extend the prefix, from 1 to 16.",
+		},
+	},
 	"cidrsubnet": {
 		Description: "`cidrsubnet` calculates a subnet address within given IP network address prefix.",
 		ParamDescription: []string{
@@ -161,6 +185,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`dirname` takes a string containing a filesystem path and removes the last portion from it.",
 		ParamDescription: []string{""},
 	},
+	"deepmerge": {
+		Description:      "`deepmerge` takes an arbitrary number of maps or objects, and returns a single object that contains a merged set of elements from all arguments, merging any nested maps or objects that appear in more than one argument.",
+		ParamDescription: []string{""},
+	},
 	"distinct": {
 		Description:      "`distinct` takes a list and returns a new list with any duplicate elements removed.",
 		ParamDescription: []string{""},
@@ -263,6 +291,13 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`jsonencode` encodes a given value to a string using JSON syntax.",
 		ParamDescription: []string{""},
 	},
+	"jsonpatch": {
+		Description: "`jsonpatch` applies a JSON Patch document, as defined in [RFC 6902](https://tools.ietf.org/html/rfc6902), to a JSON document and returns the result as a JSON string.",
+		ParamDescription: []string{
+			"`doc` is the JSON document to modify.",
+			"`patch` is a JSON array of patch operations to apply to `doc`, in order.",
+		},
+	},
 	"keys": {
 		Description: "`keys` takes a map and returns a list containing the keys from that map.",
 		ParamDescription: []string{
@@ -313,6 +348,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`min` takes one or more numbers and returns the smallest number from the set.",
 		ParamDescription: []string{""},
 	},
+	"must": {
+		Description:      "`must` evaluates the given expression and returns its result, or fails with the given message followed by the errors that the expression produced.",
+		ParamDescription: []string{"", ""},
+	},
 	"nonsensitive": {
 		Description:      "`nonsensitive` takes a sensitive value and returns a copy of that value with the sensitive marking removed, thereby exposing the sensitive value.",
 		ParamDescription: []string{""},
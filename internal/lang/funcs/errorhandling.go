@@ -0,0 +1,156 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/customdecode"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// catchResultType is the type of the unknown value that CatchFunc returns
+// when the result of the caught expression is not yet known. Known results
+// instead have the type of the expression's result in their "value"
+// attribute.
+var catchResultType = cty.Object(map[string]cty.Type{
+	"ok":    cty.Bool,
+	"value": cty.DynamicPseudoType,
+	"error": cty.String,
+})
+
+// CatchFunc evaluates the expression given in its argument and returns an
+// object describing the result, with the error message if the evaluation
+// failed, so that the caller can decide how to handle it.
+//
+// Like the "can" and "try" functions, this is implemented in terms of the HCL
+// customdecode extension and so it can be called only within an HCL
+// EvalContext.
+var CatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "expression",
+			Type: customdecode.ExpressionClosureType,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		v, err := catchResult(args[0])
+		if err != nil {
+			return cty.NilType, err
+		}
+		return v.Type(), nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return catchResult(args[0])
+	},
+})
+
+// MustFunc evaluates the expression given in its first argument and returns
+// its result, or fails with the message given in its second argument
+// followed by the errors that the expression produced.
+var MustFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "expression",
+			Type: customdecode.ExpressionClosureType,
+		},
+		{
+			Name: "message",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[1].IsKnown() {
+			// We can't report a failure without its message, so the result
+			// stays unknown until the message is known.
+			return cty.DynamicPseudoType, nil
+		}
+		v, err := must(args[0], args[1])
+		if err != nil {
+			return cty.NilType, err
+		}
+		return v.Type(), nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return must(args[0], args[1])
+	},
+})
+
+func catchResult(arg cty.Value) (cty.Value, error) {
+	closure := customdecode.ExpressionClosureFromVal(arg)
+	v, diags := closure.Value()
+	if diags.HasErrors() {
+		return cty.ObjectVal(map[string]cty.Value{
+			"ok":    cty.False,
+			"value": cty.NullVal(cty.DynamicPseudoType),
+			"error": cty.StringVal(errorDiagsMessage(diags)),
+		}), nil
+	}
+
+	if !v.IsWhollyKnown() {
+		// As with "try", we can't be certain the expression will succeed
+		// until its value is completely known, because index expressions
+		// may yet fail once the collections they refer to are known.
+		return cty.UnknownVal(catchResultType), nil
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"ok":    cty.True,
+		"value": v,
+		"error": cty.NullVal(cty.String),
+	}), nil
+}
+
+func must(arg, message cty.Value) (cty.Value, error) {
+	closure := customdecode.ExpressionClosureFromVal(arg)
+	v, diags := closure.Value()
+	if diags.HasErrors() {
+		var buf strings.Builder
+		buf.WriteString(message.AsString())
+		buf.WriteString("\n\nThe expression failed with the following errors:\n")
+		for _, diag := range diags {
+			if diag.Severity != hcl.DiagError {
+				continue
+			}
+			if diag.Subject != nil {
+				fmt.Fprintf(&buf, "- %s (at %s)\n  %s\n", diag.Summary, diag.Subject, diag.Detail)
+			} else {
+				fmt.Fprintf(&buf, "- %s\n  %s\n", diag.Summary, diag.Detail)
+			}
+		}
+		// HCL adds a period after the message of a failed function call, so
+		// we remove the one that usually ends the last error's detail.
+		return cty.NilVal, errors.New(strings.TrimSuffix(strings.TrimSuffix(buf.String(), "\n"), "."))
+	}
+
+	if !v.IsWhollyKnown() {
+		// The type of a partially-unknown value might not be the final one,
+		// so we return a wholly unknown value as "try" does.
+		return cty.DynamicVal, nil
+	}
+	return v, nil
+}
+
+// errorDiagsMessage returns a message that combines the summaries and details
+// of the errors in the given diagnostics, one per line.
+func errorDiagsMessage(diags hcl.Diagnostics) string {
+	var lines []string
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		if diag.Detail == "" {
+			lines = append(lines, diag.Summary)
+			continue
+		}
+		lines = append(lines, diag.Summary+": "+diag.Detail)
+	}
+	return strings.Join(lines, "\n")
+}
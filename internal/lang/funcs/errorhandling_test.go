@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func TestCatchAndMust(t *testing.T) {
+	evalCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"known": cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+			}),
+			"unknown": cty.UnknownVal(cty.Map(cty.String)),
+			"message": cty.UnknownVal(cty.String),
+		},
+		Functions: map[string]function.Function{
+			"catch": CatchFunc,
+			"must":  MustFunc,
+		},
+	}
+
+	tests := []struct {
+		expr    string
+		want    cty.Value
+		wantErr string
+	}{
+		{
+			`catch(known.name)`,
+			cty.ObjectVal(map[string]cty.Value{
+				"ok":    cty.True,
+				"value": cty.StringVal("web"),
+				"error": cty.NullVal(cty.String),
+			}),
+			``,
+		},
+		{
+			`catch(known.size)`,
+			cty.ObjectVal(map[string]cty.Value{
+				"ok":    cty.False,
+				"value": cty.NullVal(cty.DynamicPseudoType),
+				"error": cty.StringVal(`Unsupported attribute: This object does not have an attribute named "size".`),
+			}),
+			``,
+		},
+		{
+			`catch(unknown["a"])`,
+			cty.UnknownVal(catchResultType),
+			``,
+		},
+		{
+			`must(known.name, "the name is required")`,
+			cty.StringVal("web"),
+			``,
+		},
+		{
+			`must(unknown["a"], "a is required")`,
+			cty.DynamicVal,
+			``,
+		},
+		{
+			`must(known.size, message)`,
+			cty.DynamicVal,
+			``,
+		},
+		{
+			`must(known.size, "the size is required")`,
+			cty.NilVal,
+			`test.tf:1,1-6: Error in function call; Call to function "must" failed: the size is required
+
+The expression failed with the following errors:
+- Unsupported attribute (at test.tf:1,11-16)
+  This object does not have an attribute named "size".`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.expr), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+
+			got, diags := expr.Value(evalCtx)
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("succeeded; want error\ngot: %#v", got)
+				}
+				if got := diags.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
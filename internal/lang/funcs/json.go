@@ -0,0 +1,394 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// JSONPatchFunc constructs a function that applies a JSON Patch document, as
+// defined in RFC 6902, to a JSON document and returns the result as a JSON
+// string.
+var JSONPatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:        "doc",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+		{
+			Name:        "patch",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		docVal, docMarks := args[0].Unmark()
+		patchVal, patchMarks := args[1].Unmark()
+
+		doc, err := decodeJSONValue(docVal.AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid JSON document: %s", err)
+		}
+		rawPatch, err := decodeJSONValue(patchVal.AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "invalid JSON patch: %s", err)
+		}
+		ops, ok := rawPatch.([]any)
+		if !ok {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "invalid JSON patch: must be an array of operations")
+		}
+
+		for i, rawOp := range ops {
+			doc, err = applyJSONPatchOp(doc, rawOp)
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "patch operation %d: %s", i, err)
+			}
+		}
+
+		buf, err := json.Marshal(doc)
+		if err != nil {
+			// Should never happen, because doc contains only decoded JSON.
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to encode result: %w", err)
+		}
+		return cty.StringVal(string(buf)).WithMarks(docMarks, patchMarks), nil
+	},
+})
+
+// JSONPatch applies a JSON Patch document, as defined in RFC 6902, to a JSON
+// document and returns the result as a JSON string.
+func JSONPatch(doc, patch cty.Value) (cty.Value, error) {
+	return JSONPatchFunc.Call([]cty.Value{doc, patch})
+}
+
+// decodeJSONValue decodes a single JSON value from the given string,
+// preserving numbers exactly as written.
+func decodeJSONValue(src string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+	var ret any
+	if err := dec.Decode(&ret); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("extra data after the JSON value")
+	}
+	return ret, nil
+}
+
+// applyJSONPatchOp applies a single JSON Patch operation to the given
+// document, returning the updated document.
+func applyJSONPatchOp(doc any, rawOp any) (any, error) {
+	op, ok := rawOp.(map[string]any)
+	if !ok {
+		return nil, errors.New("must be an object")
+	}
+	opName, ok := op["op"].(string)
+	if !ok {
+		return nil, errors.New(`must have a string "op" member`)
+	}
+	path, err := jsonPatchPointer(op, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	switch opName {
+	case "add", "replace", "test":
+		val, ok := op["value"]
+		if !ok {
+			return nil, fmt.Errorf(`%q operation must have a "value" member`, opName)
+		}
+		switch opName {
+		case "add":
+			return jsonPointerAdd(doc, path, val)
+		case "replace":
+			doc, _, err := jsonPointerRemove(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			return jsonPointerAdd(doc, path, val)
+		default:
+			got, err := jsonPointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !jsonValuesEqual(got, val) {
+				return nil, fmt.Errorf("test failed: value at %q does not match", jsonPointerString(path))
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err := jsonPointerRemove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := jsonPatchPointer(op, "from")
+		if err != nil {
+			return nil, err
+		}
+		if opName == "move" {
+			if len(from) < len(path) && jsonPointerHasPrefix(path, from) {
+				return nil, fmt.Errorf("cannot move %q into one of its own children", jsonPointerString(from))
+			}
+			doc, val, err := jsonPointerRemove(doc, from)
+			if err != nil {
+				return nil, err
+			}
+			return jsonPointerAdd(doc, path, val)
+		}
+		val, err := jsonPointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(doc, path, copyJSONValue(val))
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", opName)
+	}
+}
+
+// jsonPatchPointer returns the JSON Pointer in the given member of a JSON
+// Patch operation, split into its reference tokens.
+func jsonPatchPointer(op map[string]any, name string) ([]string, error) {
+	raw, ok := op[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("must have a string %q member", name)
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(raw, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with a slash", raw)
+	}
+	tokens := strings.Split(raw[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// jsonPointerString returns the string representation of the given JSON
+// Pointer tokens, for use in error messages.
+func jsonPointerString(tokens []string) string {
+	var buf strings.Builder
+	for _, token := range tokens {
+		buf.WriteByte('/')
+		buf.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return buf.String()
+}
+
+func jsonPointerHasPrefix(tokens, prefix []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if tokens[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var jsonArrayIndexRe = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+
+// jsonArrayIndex parses a JSON Pointer reference token as an index into an
+// array of the given length. If allowEnd is true then the index may also
+// refer to the position just after the last element, either by number or
+// using the special token "-".
+func jsonArrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	if !jsonArrayIndexRe.MatchString(token) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx > length || (idx == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %s is out of range", token)
+	}
+	return idx, nil
+}
+
+func jsonPointerGet(doc any, tokens []string) (any, error) {
+	for i, token := range tokens {
+		switch d := doc.(type) {
+		case map[string]any:
+			v, ok := d[token]
+			if !ok {
+				return nil, fmt.Errorf("no value at %q", jsonPointerString(tokens[:i+1]))
+			}
+			doc = v
+		case []any:
+			idx, err := jsonArrayIndex(token, len(d), false)
+			if err != nil {
+				return nil, fmt.Errorf("at %q: %s", jsonPointerString(tokens[:i+1]), err)
+			}
+			doc = d[idx]
+		default:
+			return nil, fmt.Errorf("no value at %q", jsonPointerString(tokens[:i+1]))
+		}
+	}
+	return doc, nil
+}
+
+func jsonPointerAdd(doc any, tokens []string, val any) (any, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	parent, err := jsonPointerGet(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[token] = val
+		return doc, nil
+	case []any:
+		idx, err := jsonArrayIndex(token, len(p), true)
+		if err != nil {
+			return nil, fmt.Errorf("at %q: %s", jsonPointerString(tokens), err)
+		}
+		p = append(p, nil)
+		copy(p[idx+1:], p[idx:])
+		p[idx] = val
+		return jsonPointerSet(doc, tokens[:len(tokens)-1], p)
+	default:
+		return nil, fmt.Errorf("cannot add a value at %q: parent is not an object or array", jsonPointerString(tokens))
+	}
+}
+
+// jsonPointerSet replaces the existing value at the given location. It's used
+// to put an array back into its parent after its length has changed.
+func jsonPointerSet(doc any, tokens []string, val any) (any, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	parent, err := jsonPointerGet(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[token] = val
+	case []any:
+		idx, err := jsonArrayIndex(token, len(p), false)
+		if err != nil {
+			return nil, fmt.Errorf("at %q: %s", jsonPointerString(tokens), err)
+		}
+		p[idx] = val
+	}
+	return doc, nil
+}
+
+// jsonPointerRemove removes the value at the given location, returning both
+// the updated document and the value that was removed.
+func jsonPointerRemove(doc any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := jsonPointerGet(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		v, ok := p[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("no value at %q", jsonPointerString(tokens))
+		}
+		delete(p, token)
+		return doc, v, nil
+	case []any:
+		idx, err := jsonArrayIndex(token, len(p), false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("at %q: %s", jsonPointerString(tokens), err)
+		}
+		v := p[idx]
+		p = append(p[:idx:idx], p[idx+1:]...)
+		doc, err = jsonPointerSet(doc, tokens[:len(tokens)-1], p)
+		return doc, v, err
+	default:
+		return nil, nil, fmt.Errorf("no value at %q", jsonPointerString(tokens))
+	}
+}
+
+// copyJSONValue returns a deep copy of the given decoded JSON value, so that
+// later operations on either copy won't affect the other.
+func copyJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, ev := range v {
+			ret[k] = copyJSONValue(ev)
+		}
+		return ret
+	case []any:
+		ret := make([]any, len(v))
+		for i, ev := range v {
+			ret[i] = copyJSONValue(ev)
+		}
+		return ret
+	default:
+		return v
+	}
+}
+
+// jsonValuesEqual compares two decoded JSON values, treating numbers as
+// equal if they have the same value regardless of how they were written.
+func jsonValuesEqual(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonValuesEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonValuesEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, _, errA := big.ParseFloat(string(a), 10, 512, big.ToNearestEven)
+		bf, _, errB := big.ParseFloat(string(b), 10, 512, big.ToNearestEven)
+		if errA != nil || errB != nil {
+			return a == b
+		}
+		return af.Cmp(bf) == 0
+	default:
+		return a == b
+	}
+}
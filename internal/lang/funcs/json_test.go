@@ -0,0 +1,166 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		Doc   cty.Value
+		Patch cty.Value
+		Want  cty.Value
+		Err   string
+	}{
+		{
+			cty.StringVal(`{"a":1}`),
+			cty.StringVal(`[]`),
+			cty.StringVal(`{"a":1}`),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":1,"list":["x","z"]}`),
+			cty.StringVal(`[
+				{"op": "add", "path": "/b", "value": {"c": true}},
+				{"op": "add", "path": "/list/1", "value": "y"},
+				{"op": "add", "path": "/list/-", "value": "end"}
+			]`),
+			cty.StringVal(`{"a":1,"b":{"c":true},"list":["x","y","z","end"]}`),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":1,"b":2,"list":["x","y","z"]}`),
+			cty.StringVal(`[
+				{"op": "remove", "path": "/a"},
+				{"op": "replace", "path": "/b", "value": 3},
+				{"op": "remove", "path": "/list/1"}
+			]`),
+			cty.StringVal(`{"b":3,"list":["x","z"]}`),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":{"b":[1,2]},"c":null}`),
+			cty.StringVal(`[
+				{"op": "copy", "from": "/a/b", "path": "/d"},
+				{"op": "move", "from": "/a/b/0", "path": "/c"},
+				{"op": "add", "path": "/d/-", "value": 3}
+			]`),
+			cty.StringVal(`{"a":{"b":[2]},"c":1,"d":[1,2,3]}`),
+			``,
+		},
+		{
+			// Keys containing slashes and tildes are escaped in pointers.
+			cty.StringVal(`{"a/b":1,"c~d":2}`),
+			cty.StringVal(`[
+				{"op": "test", "path": "/a~1b", "value": 1.0},
+				{"op": "remove", "path": "/c~0d"}
+			]`),
+			cty.StringVal(`{"a/b":1}`),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":1}`),
+			cty.StringVal(`[{"op": "replace", "path": "", "value": ["whole"]}]`),
+			cty.StringVal(`["whole"]`),
+			``,
+		},
+		{
+			// Numbers are preserved exactly as written.
+			cty.StringVal(`{"big":12345678901234567890}`),
+			cty.StringVal(`[]`),
+			cty.StringVal(`{"big":12345678901234567890}`),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":1}`).Mark(marks.Sensitive),
+			cty.StringVal(`[{"op": "add", "path": "/b", "value": 2}]`),
+			cty.StringVal(`{"a":1,"b":2}`).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal(`[]`),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.StringVal(`{"a":1}`),
+			cty.StringVal(`[{"op": "test", "path": "/a", "value": 2}]`),
+			cty.NilVal,
+			`patch operation 0: test failed: value at "/a" does not match`,
+		},
+		{
+			cty.StringVal(`{"a":1}`),
+			cty.StringVal(`[{"op": "remove", "path": "/b"}]`),
+			cty.NilVal,
+			`patch operation 0: no value at "/b"`,
+		},
+		{
+			cty.StringVal(`{"list":[]}`),
+			cty.StringVal(`[{"op": "add", "path": "/list/1", "value": 1}]`),
+			cty.NilVal,
+			`patch operation 0: at "/list/1": array index 1 is out of range`,
+		},
+		{
+			cty.StringVal(`{"a":{}}`),
+			cty.StringVal(`[{"op": "move", "from": "/a", "path": "/a/b"}]`),
+			cty.NilVal,
+			`patch operation 0: cannot move "/a" into one of its own children`,
+		},
+		{
+			cty.StringVal(`{}`),
+			cty.StringVal(`[{"op": "frob", "path": ""}]`),
+			cty.NilVal,
+			`patch operation 0: unsupported operation "frob"`,
+		},
+		{
+			cty.StringVal(`{}`),
+			cty.StringVal(`[{"op": "add", "path": "a", "value": 1}]`),
+			cty.NilVal,
+			`patch operation 0: invalid JSON pointer "a": must be empty or start with a slash`,
+		},
+		{
+			cty.StringVal(`{}`),
+			cty.StringVal(`{"op": "add", "path": "/a", "value": 1}`),
+			cty.NilVal,
+			`invalid JSON patch: must be an array of operations`,
+		},
+		{
+			cty.StringVal(`{} {}`),
+			cty.StringVal(`[]`),
+			cty.NilVal,
+			`invalid JSON document: extra data after the JSON value`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("jsonpatch(%#v, %#v)", test.Doc, test.Patch), func(t *testing.T) {
+			got, err := JSONPatch(test.Doc, test.Patch)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -43,7 +43,17 @@ var LogFunc = function.New(&function.Spec{
 	},
 })
 
-// PowFunc constructs a function that returns the logarithm of a given number in a given base.
+// maxExactPowBits is the largest number of bits in the result of PowFunc
+// that we'll calculate exactly. It matches the precision that cty uses for
+// number literals, beyond which we fall back to floating point.
+const maxExactPowBits = 512
+
+// PowFunc constructs a function that calculates an exponent, by raising its
+// first argument to the power of the second argument.
+//
+// If the base is a whole number and the power is a whole number that isn't
+// negative then the result is exact, so that large integers such as 64-bit
+// identifiers are not rounded to the precision of a floating point number.
 var PowFunc = function.New(&function.Spec{
 	Params: []function.Parameter{
 		{
@@ -58,6 +68,10 @@ var PowFunc = function.New(&function.Spec{
 	Type:         function.StaticReturnType(cty.Number),
 	RefineResult: refineNotNull,
 	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		if result, ok := powInt(args[0].AsBigFloat(), args[1].AsBigFloat()); ok {
+			return cty.NumberVal(result), nil
+		}
+
 		var num float64
 		if err := gocty.FromCtyValue(args[0], &num); err != nil {
 			return cty.UnknownVal(cty.String), err
@@ -72,8 +86,28 @@ var PowFunc = function.New(&function.Spec{
 	},
 })
 
-// SignumFunc constructs a function that returns the closest whole number greater
-// than or equal to the given value.
+// powInt returns num raised to the given power if both are whole numbers, the
+// power isn't negative and the result has no more than maxExactPowBits bits.
+// Otherwise the second return value is false.
+func powInt(num, power *big.Float) (*big.Float, bool) {
+	if !num.IsInt() || !power.IsInt() || power.Sign() < 0 {
+		return nil, false
+	}
+	p, acc := power.Int64()
+	if acc != big.Exact {
+		return nil, false
+	}
+	n, _ := num.Int(nil)
+	if bits := int64(n.BitLen()); bits > 1 && (p > maxExactPowBits || bits*p > maxExactPowBits) {
+		return nil, false
+	}
+
+	result := new(big.Int).Exp(n, big.NewInt(p), nil)
+	return new(big.Float).SetInt(result), true
+}
+
+// SignumFunc constructs a function that returns -1, 0 or +1 depending on
+// whether the given number is negative, zero or positive.
 var SignumFunc = function.New(&function.Spec{
 	Params: []function.Parameter{
 		{
@@ -84,18 +118,14 @@ var SignumFunc = function.New(&function.Spec{
 	Type:         function.StaticReturnType(cty.Number),
 	RefineResult: refineNotNull,
 	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
-		var num int
-		if err := gocty.FromCtyValue(args[0], &num); err != nil {
-			return cty.UnknownVal(cty.String), err
-		}
-		switch {
-		case num < 0:
-			return cty.NumberIntVal(-1), nil
-		case num > 0:
-			return cty.NumberIntVal(+1), nil
-		default:
-			return cty.NumberIntVal(0), nil
+		// We use the sign of the number directly, rather than converting it
+		// to a Go int, so that this works for integers of any size, but it
+		// still only accepts whole numbers.
+		num := args[0].AsBigFloat()
+		if !num.IsInt() {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "value must be a whole number")
 		}
+		return cty.NumberIntVal(int64(num.Sign())), nil
 	},
 })
 
@@ -165,7 +195,7 @@ func Log(num, base cty.Value) (cty.Value, error) {
 	return LogFunc.Call([]cty.Value{num, base})
 }
 
-// Pow returns the logarithm of a given number in a given base.
+// Pow returns the given number raised to the given power.
 func Pow(num, power cty.Value) (cty.Value, error) {
 	return PowFunc.Call([]cty.Value{num, power})
 }
@@ -7,6 +7,7 @@ package funcs
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/lang/marks"
@@ -123,6 +124,38 @@ func TestPow(t *testing.T) {
 			cty.NumberFloatVal(0),
 			false,
 		},
+		{
+			cty.NumberFloatVal(1.5),
+			cty.NumberFloatVal(2),
+			cty.NumberFloatVal(2.25),
+			false,
+		},
+		{
+			cty.NumberIntVal(2),
+			cty.NumberIntVal(63),
+			cty.MustParseNumberVal("9223372036854775808"),
+			false,
+		},
+		{
+			cty.NumberIntVal(-3),
+			cty.NumberIntVal(41),
+			cty.MustParseNumberVal("-36472996377170786403"),
+			false,
+		},
+		{
+			cty.NumberIntVal(1),
+			cty.NumberIntVal(1 << 40),
+			cty.NumberIntVal(1),
+			false,
+		},
+		{
+			// Results larger than maxExactPowBits fall back to floating
+			// point, as for all results that aren't whole numbers.
+			cty.NumberIntVal(2),
+			cty.NumberIntVal(600),
+			cty.NumberFloatVal(math.Pow(2, 600)),
+			false,
+		},
 	}
 
 	for _, test := range tests {
@@ -166,6 +199,16 @@ func TestSignum(t *testing.T) {
 			cty.NumberFloatVal(-1),
 			false,
 		},
+		{
+			cty.NumberFloatVal(0.5),
+			cty.UnknownVal(cty.String),
+			true,
+		},
+		{
+			cty.MustParseNumberVal("-18446744073709551616"),
+			cty.NumberFloatVal(-1),
+			false,
+		},
 	}
 
 	for _, test := range tests {
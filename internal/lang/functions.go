@@ -7,6 +7,7 @@ package lang
 
 import (
 	"fmt"
+	"maps"
 
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
 	ctyyaml "github.com/zclconf/go-cty-yaml"
@@ -64,6 +65,11 @@ func (s *Scope) Functions() map[string]function.Function {
 		for _, name := range coreNames {
 			s.funcs[CoreNamespace+name] = s.funcs[name]
 		}
+
+		// User-defined functions are added last, and given their own copy
+		// of the builtin functions, so that their result expressions can't
+		// call the user-defined functions of any other module.
+		maps.Copy(s.funcs, s.UserFunctions.functions(maps.Clone(s.funcs)))
 	}
 	s.funcsLock.Unlock()
 
@@ -129,11 +135,15 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"base64sha512":     funcs.Base64Sha512Func,
 		"bcrypt":           funcs.BcryptFunc,
 		"can":              tryfunc.CanFunc,
+		"catch":            funcs.CatchFunc,
 		"ceil":             stdlib.CeilFunc,
 		"chomp":            stdlib.ChompFunc,
 		"cidrcontains":     funcs.CidrContainsFunc,
 		"cidrhost":         funcs.CidrHostFunc,
+		"cidrmerge":        funcs.CidrMergeFunc,
 		"cidrnetmask":      funcs.CidrNetmaskFunc,
+		"cidroverlaps":     funcs.CidrOverlapsFunc,
+		"cidrsplit":        funcs.CidrSplitFunc,
 		"cidrsubnet":       funcs.CidrSubnetFunc,
 		"cidrsubnets":      funcs.CidrSubnetsFunc,
 		"coalesce":         funcs.CoalesceFunc,
@@ -143,6 +153,7 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"contains":         stdlib.ContainsFunc,
 		"csvdecode":        stdlib.CSVDecodeFunc,
 		"dirname":          funcs.DirnameFunc,
+		"deepmerge":        funcs.DeepMergeFunc,
 		"distinct":         stdlib.DistinctFunc,
 		"element":          stdlib.ElementFunc,
 		"endswith":         funcs.EndsWithFunc,
@@ -167,6 +178,7 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"join":             stdlib.JoinFunc,
 		"jsondecode":       stdlib.JSONDecodeFunc,
 		"jsonencode":       stdlib.JSONEncodeFunc,
+		"jsonpatch":        funcs.JSONPatchFunc,
 		"keys":             stdlib.KeysFunc,
 		"length":           funcs.LengthFunc,
 		"list":             funcs.ListFunc,
@@ -179,10 +191,11 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"md5":              funcs.Md5Func,
 		"merge":            stdlib.MergeFunc,
 		"min":              stdlib.MinFunc,
+		"must":             funcs.MustFunc,
 		"one":              funcs.OneFunc,
 		"parseint":         stdlib.ParseIntFunc,
 		"pathexpand":       funcs.PathExpandFunc,
-		"pow":              stdlib.PowFunc,
+		"pow":              funcs.PowFunc,
 		"range":            stdlib.RangeFunc,
 		"regex":            stdlib.RegexFunc,
 		"regexall":         stdlib.RegexAllFunc,
@@ -199,7 +212,7 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"sha1":             funcs.Sha1Func,
 		"sha256":           funcs.Sha256Func,
 		"sha512":           funcs.Sha512Func,
-		"signum":           stdlib.SignumFunc,
+		"signum":           funcs.SignumFunc,
 		"slice":            stdlib.SliceFunc,
 		"sort":             stdlib.SortFunc,
 		"split":            stdlib.SplitFunc,
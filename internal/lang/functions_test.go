@@ -161,6 +161,27 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"catch": {
+			{
+				`catch("a")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"ok":    cty.True,
+					"value": cty.StringVal("a"),
+					"error": cty.NullVal(cty.String),
+				}),
+			},
+			{
+				// As with "can", only errors during dynamic evaluation are
+				// caught.
+				`catch({}.baz)`,
+				cty.ObjectVal(map[string]cty.Value{
+					"ok":    cty.False,
+					"value": cty.NullVal(cty.DynamicPseudoType),
+					"error": cty.StringVal(`Unsupported attribute: This object does not have an attribute named "baz".`),
+				}),
+			},
+		},
+
 		"ceil": {
 			{
 				`ceil(1.2)`,
@@ -206,6 +227,15 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidrmerge": {
+			{
+				`cidrmerge(["10.0.1.0/24", "10.0.0.0/24"])`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("10.0.0.0/23"),
+				}),
+			},
+		},
+
 		"cidrnetmask": {
 			{
 				`cidrnetmask("192.168.1.0/24")`,
@@ -213,6 +243,23 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidroverlaps": {
+			{
+				`cidroverlaps("10.0.0.0/16", "10.0.1.0/24")`,
+				cty.True,
+			},
+		},
+
+		"cidrsplit": {
+			{
+				`cidrsplit("10.0.0.0/16", 1)`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("10.0.0.0/17"),
+					cty.StringVal("10.0.128.0/17"),
+				}),
+			},
+		},
+
 		"cidrsubnet": {
 			{
 				`cidrsubnet("192.168.2.0/20", 4, 6)`,
@@ -320,6 +367,18 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"deepmerge": {
+			{
+				`deepmerge({a={b="b"}}, {a={c="c"}})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.StringVal("b"),
+						"c": cty.StringVal("c"),
+					}),
+				}),
+			},
+		},
+
 		"distinct": {
 			{
 				`distinct(["a", "b", "a", "b"])`,
@@ -570,6 +629,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"jsonpatch": {
+			{
+				`jsonpatch("{\"hello\": \"world\"}", "[{\"op\": \"add\", \"path\": \"/goodbye\", \"value\": \"moon\"}]")`,
+				cty.StringVal("{\"goodbye\":\"moon\",\"hello\":\"world\"}"),
+			},
+		},
+
 		"keys": {
 			{
 				`keys({"hello"=1, "goodbye"=42})`,
@@ -665,6 +731,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"must": {
+			{
+				`must({a = "b"}.a, "a is required")`,
+				cty.StringVal("b"),
+			},
+		},
+
 		"nonsensitive": {
 			{
 				// Due to how this test is set up we have no way to get
@@ -714,6 +787,12 @@ func TestFunctions(t *testing.T) {
 				`pow(1,0)`,
 				cty.NumberFloatVal(1),
 			},
+			{
+				// Whole-number results are exact, even beyond the
+				// precision of a float64.
+				`jsonencode(pow(2, 63))`,
+				cty.StringVal("9223372036854775808"),
+			},
 		},
 
 		"range": {
@@ -859,6 +938,10 @@ func TestFunctions(t *testing.T) {
 				`signum(12)`,
 				cty.NumberFloatVal(1),
 			},
+			{
+				`signum(-18446744073709551616)`,
+				cty.NumberIntVal(-1),
+			},
 		},
 
 		"slice": {
@@ -48,6 +48,15 @@ func Contains(val cty.Value, mark valueMark) bool {
 // OpenTofu.
 const Sensitive = valueMark("Sensitive")
 
+// Ephemeral indicates that this value is ephemeral in the context of
+// OpenTofu: it is available only during the current operation and so must
+// never be persisted in a plan or state.
+const Ephemeral = valueMark("Ephemeral")
+
+// Deferred indicates that this value is derived from a resource whose
+// planning was deferred to a later plan, and so it won't be known until then.
+const Deferred = valueMark("Deferred")
+
 // TypeType is used to indicate that the value contains a representation of
 // another value's type. This is part of the implementation of the console-only
 // `type` function.
@@ -74,6 +74,12 @@ type Scope struct {
 	PlanTimestamp time.Time
 
 	ProviderFunctions ProviderFunction
+
+	// UserFunctions are the user-defined functions declared in the module
+	// that the scope belongs to and in its child modules. The module's own
+	// functions are available in the local:: namespace, and the exported
+	// functions of each child module as module::<call>::<name>.
+	UserFunctions *UserFunctions
 }
 
 type ProviderFunction func(context.Context, addrs.ProviderFunction, tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics)
@@ -0,0 +1,139 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+const (
+	// LocalNamespace is the namespace of the user-defined functions declared
+	// in the module that an expression belongs to.
+	LocalNamespace = addrs.FunctionNamespaceLocal + "::"
+
+	// ModuleNamespace is the namespace of the user-defined functions exported
+	// by the child modules of the module that an expression belongs to. Each
+	// function name is further prefixed by the name of the module call.
+	ModuleNamespace = addrs.FunctionNamespaceModule + "::"
+)
+
+// UserFunction is a function declared in the configuration, whose result is
+// given by an expression that can refer only to the function's parameters.
+type UserFunction struct {
+	Description string
+	Params      []string
+	Result      hcl.Expression
+
+	// Export is true if the function may be called from the module that
+	// calls the module where it's declared.
+	Export bool
+}
+
+// UserFunctions are the user-defined functions declared in a single module,
+// along with those declared in each of its child modules.
+type UserFunctions struct {
+	Funcs map[string]*UserFunction
+
+	// Children are the functions declared in each of the module's child
+	// module calls, keyed by the call name. Only the functions that are
+	// exported are available to the module itself.
+	Children map[string]*UserFunctions
+}
+
+// functions returns the user-defined functions that are available within
+// the receiving module, keyed by their names including the local:: or
+// module::<call>:: namespace prefix.
+//
+// The result expressions are evaluated with the given builtin functions and
+// the functions that are available within the module where each function is
+// declared. The configs package rejects declarations that would make a
+// function call itself.
+func (u *UserFunctions) functions(builtin map[string]function.Function) map[string]function.Function {
+	ret := make(map[string]function.Function)
+	if u == nil {
+		return ret
+	}
+
+	// The local functions can call each other and the exported functions of
+	// the child modules, so they all share this table, which is complete by
+	// the time any of them can be called.
+	var table map[string]function.Function
+	localFuncs := func() map[string]function.Function {
+		return table
+	}
+
+	for callName, child := range u.Children {
+		// The tables for child modules are built only when needed, so that
+		// we don't need to visit the whole module tree for every scope.
+		childFuncs := sync.OnceValue(func() map[string]function.Function {
+			childTable := maps.Clone(builtin)
+			maps.Copy(childTable, child.functions(builtin))
+			return childTable
+		})
+		for name, decl := range child.Funcs {
+			if decl.Export {
+				ret[ModuleNamespace+callName+"::"+name] = makeUserFunction(decl, childFuncs)
+			}
+		}
+	}
+	for name, decl := range u.Funcs {
+		ret[LocalNamespace+name] = makeUserFunction(decl, localFuncs)
+	}
+
+	table = maps.Clone(builtin)
+	maps.Copy(table, ret)
+	return ret
+}
+
+// makeUserFunction returns a cty function that evaluates the result
+// expression of the given declaration using the functions returned by funcs.
+func makeUserFunction(decl *UserFunction, funcs func() map[string]function.Function) function.Function {
+	params := make([]function.Parameter, len(decl.Params))
+	for i, name := range decl.Params {
+		params[i] = function.Parameter{
+			Name:             name,
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowMarked:      true,
+		}
+	}
+
+	call := func(args []cty.Value) (cty.Value, error) {
+		vars := make(map[string]cty.Value, len(args))
+		for i, arg := range args {
+			vars[decl.Params[i]] = arg
+		}
+		val, diags := decl.Result.Value(&hcl.EvalContext{
+			Variables: vars,
+			Functions: funcs(),
+		})
+		if diags.HasErrors() {
+			return cty.DynamicVal, diags
+		}
+		return val, nil
+	}
+
+	return function.New(&function.Spec{
+		Description: decl.Description,
+		Params:      params,
+		Type: func(args []cty.Value) (cty.Type, error) {
+			val, err := call(args)
+			return val.Type(), err
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return call(args)
+		},
+	})
+}
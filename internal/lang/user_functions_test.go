@@ -0,0 +1,153 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestScopeUserFunctions(t *testing.T) {
+	mustExpr := func(src string) hcl.Expression {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "test.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return expr
+	}
+
+	scope := &Scope{
+		UserFunctions: &UserFunctions{
+			Funcs: map[string]*UserFunction{
+				"greet": {
+					Params: []string{"name"},
+					Result: mustExpr(`"${local::prefix()}, ${upper(name)}!"`),
+				},
+				"prefix": {
+					Params: []string{},
+					Result: mustExpr(`"Hello"`),
+				},
+				"quadruple": {
+					Params: []string{"n"},
+					Result: mustExpr(`module::child::double(module::child::double(n))`),
+				},
+			},
+			Children: map[string]*UserFunctions{
+				"child": {
+					Funcs: map[string]*UserFunction{
+						"double": {
+							Params: []string{"n"},
+							Result: mustExpr(`local::twice(n)`),
+							Export: true,
+						},
+						"twice": {
+							Params: []string{"n"},
+							Result: mustExpr(`module::grandchild::times(n, 2)`),
+						},
+					},
+					Children: map[string]*UserFunctions{
+						"grandchild": {
+							Funcs: map[string]*UserFunction{
+								"times": {
+									Params: []string{"a", "b"},
+									Result: mustExpr(`a * b`),
+									Export: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		expr    string
+		want    cty.Value
+		wantErr string
+	}{
+		"local function": {
+			`local::greet("world")`,
+			cty.StringVal("Hello, WORLD!"),
+			``,
+		},
+		"exported module function": {
+			`module::child::double(2)`,
+			cty.NumberIntVal(4),
+			``,
+		},
+		"local function calling module functions": {
+			`local::quadruple(2)`,
+			cty.NumberIntVal(8),
+			``,
+		},
+		"unknown argument": {
+			`local::greet(unknown)`,
+			cty.UnknownVal(cty.String).Refine().NotNull().StringPrefixFull("Hello, ").NewValue(),
+			``,
+		},
+		"marked argument": {
+			`module::child::double(secret)`,
+			cty.NumberIntVal(6).Mark(marks.Sensitive),
+			``,
+		},
+		"unexported module function": {
+			`module::child::twice(2)`,
+			cty.NilVal,
+			`There is no function named "twice" exported by a module call named "child" in this module.`,
+		},
+		"grandchild module function": {
+			`module::grandchild::times(2, 3)`,
+			cty.NilVal,
+			`There is no function named "times" exported by a module call named "grandchild" in this module.`,
+		},
+		"undeclared local function": {
+			`local::twice(2)`,
+			cty.NilVal,
+			`There is no function named "twice" declared in this module.`,
+		},
+		"wrong number of arguments": {
+			`local::greet("a", "b")`,
+			cty.NilVal,
+			`Function "local::greet" expects only 1 argument(s).`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"unknown": cty.UnknownVal(cty.String),
+					"secret":  cty.NumberIntVal(3).Mark(marks.Sensitive),
+				},
+				Functions: scope.Functions(),
+			}
+			got, diags := mustExpr(test.expr).Value(ctx)
+			diags = enhanceFunctionDiags(diags)
+
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				if got := diags[0].Detail; got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
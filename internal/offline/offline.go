@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package offline implements OpenTofu's offline mode, in which OpenTofu
+// refuses to access registries, remote backends, and other network services,
+// and so can only use providers and modules that are already available
+// locally.
+//
+// Offline mode is a process-wide setting, enabled by the -offline global
+// option or the TF_OFFLINE environment variable, because it must apply to
+// every part of OpenTofu that could access the network.
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// EnvVar is the environment variable that enables offline mode.
+const EnvVar = "TF_OFFLINE"
+
+var enabled atomic.Bool
+
+// Enable turns offline mode on or off for the rest of the process.
+func Enable(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled returns whether OpenTofu is running in offline mode.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Error is the error returned when OpenTofu needs to access the network while
+// running in offline mode.
+type Error struct {
+	// Target describes what OpenTofu tried to access, such as a URL.
+	Target string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("OpenTofu is running in offline mode, so it can't access %s", e.Target)
+}
+
+// Check returns an [Error] for the given target if OpenTofu is running in
+// offline mode, or nil otherwise.
+func Check(target string) error {
+	if !Enabled() {
+		return nil
+	}
+	return &Error{Target: target}
+}
+
+// IsError returns whether the given error is or wraps an [Error].
+func IsError(err error) bool {
+	var offlineErr *Error
+	return errors.As(err, &offlineErr)
+}
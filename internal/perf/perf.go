@@ -0,0 +1,177 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package perf records the timings of an operation, such as how long
+// OpenTofu spent building graphs or waiting for provider calls for each
+// resource instance, for the performance profiles requested with the
+// -profile option of "tofu plan" and "tofu apply".
+//
+// The profile travels in the context of the operation, so the code that
+// records timings doesn't need to know whether a profile was requested.
+package perf
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Profile collects the timings of a single operation. All of its methods are
+// safe to call concurrently, and do nothing on a nil Profile.
+type Profile struct {
+	started time.Time
+
+	mu        sync.Mutex
+	phases    []*phaseTiming
+	resources map[string]map[string]*callTiming
+}
+
+type phaseTiming struct {
+	name     string
+	count    int
+	duration time.Duration
+}
+
+type callTiming struct {
+	count    int
+	duration time.Duration
+}
+
+// NewProfile returns a profile whose total duration starts now.
+func NewProfile() *Profile {
+	return &Profile{
+		started:   time.Now(),
+		resources: make(map[string]map[string]*callTiming),
+	}
+}
+
+type profileContextKey struct{}
+
+// ContextWithProfile returns a context that makes StartPhase and
+// FromContext use the given profile.
+func ContextWithProfile(ctx context.Context, p *Profile) context.Context {
+	return context.WithValue(ctx, profileContextKey{}, p)
+}
+
+// FromContext returns the profile of the given context, or nil if no
+// profile was requested.
+func FromContext(ctx context.Context) *Profile {
+	if ctx == nil {
+		return nil
+	}
+	p, _ := ctx.Value(profileContextKey{}).(*Profile)
+	return p
+}
+
+// StartPhase starts timing a phase of the operation for the profile of the
+// given context, if any, and returns the function that ends it. Phases of the
+// same name are added together, so the same phase can be timed more than
+// once, or concurrently.
+//
+//	defer perf.StartPhase(ctx, "graph build")()
+func StartPhase(ctx context.Context, name string) func() {
+	p := FromContext(ctx)
+	if p == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		p.addPhase(name, time.Since(start))
+	}
+}
+
+func (p *Profile) addPhase(name string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, phase := range p.phases {
+		if phase.name == name {
+			phase.count++
+			phase.duration += d
+			return
+		}
+	}
+	p.phases = append(p.phases, &phaseTiming{name: name, count: 1, duration: d})
+}
+
+// AddResourceCall records that a call of the given kind, such as "refresh"
+// or "plan", took the given time for the resource instance with the given
+// address.
+func (p *Profile) AddResourceCall(addr, call string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	calls := p.resources[addr]
+	if calls == nil {
+		calls = make(map[string]*callTiming)
+		p.resources[addr] = calls
+	}
+	timing := calls[call]
+	if timing == nil {
+		timing = &callTiming{}
+		calls[call] = timing
+	}
+	timing.count++
+	timing.duration += d
+}
+
+// Report returns the report of the timings recorded so far, for the given
+// command, with the time since the profile was created as its total
+// duration.
+func (p *Profile) Report(command string) *Report {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ret := &Report{
+		FormatVersion: ReportFormatVersion,
+		Command:       command,
+		Started:       p.started.UTC(),
+		DurationMS:    milliseconds(time.Since(p.started)),
+		Phases:        []ReportPhase{},
+		Resources:     []ReportResource{},
+	}
+	for _, phase := range p.phases {
+		ret.Phases = append(ret.Phases, ReportPhase{
+			Name:       phase.name,
+			Count:      phase.count,
+			DurationMS: milliseconds(phase.duration),
+		})
+	}
+	for addr, calls := range p.resources {
+		resource := ReportResource{Address: addr}
+		var total time.Duration
+		for call, timing := range calls {
+			resource.Calls = append(resource.Calls, ReportCall{
+				Call:       call,
+				Count:      timing.count,
+				DurationMS: milliseconds(timing.duration),
+			})
+			total += timing.duration
+		}
+		sort.Slice(resource.Calls, func(i, j int) bool {
+			return resource.Calls[i].Call < resource.Calls[j].Call
+		})
+		resource.DurationMS = milliseconds(total)
+		ret.Resources = append(ret.Resources, resource)
+	}
+	sort.Slice(ret.Resources, func(i, j int) bool {
+		a, b := ret.Resources[i], ret.Resources[j]
+		if a.DurationMS != b.DurationMS {
+			return a.DurationMS > b.DurationMS
+		}
+		return a.Address < b.Address
+	})
+	return ret
+}
+
+// milliseconds returns the given duration in milliseconds, to the nearest
+// microsecond.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
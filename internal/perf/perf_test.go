@@ -0,0 +1,97 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package perf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestProfile(t *testing.T) {
+	// Without a profile in the context, phases aren't recorded anywhere.
+	StartPhase(t.Context(), "ignored")()
+
+	p := NewProfile()
+	ctx := ContextWithProfile(t.Context(), p)
+	if got := FromContext(ctx); got != p {
+		t.Fatalf("wrong profile from context %p; want %p", got, p)
+	}
+
+	StartPhase(ctx, "state read")()
+	StartPhase(ctx, "graph build")()
+	StartPhase(ctx, "state read")()
+	p.AddResourceCall("test_instance.a", "refresh", time.Millisecond)
+	p.AddResourceCall("test_instance.a", "plan", 2*time.Millisecond)
+	p.AddResourceCall("test_instance.a", "plan", 2*time.Millisecond)
+	p.AddResourceCall("test_instance.b", "plan", 10*time.Millisecond)
+
+	got := p.Report("plan")
+	want := &Report{
+		FormatVersion: ReportFormatVersion,
+		Command:       "plan",
+		Phases: []ReportPhase{
+			{Name: "state read", Count: 2},
+			{Name: "graph build", Count: 1},
+		},
+		Resources: []ReportResource{
+			{
+				Address:    "test_instance.b",
+				DurationMS: 10,
+				Calls: []ReportCall{
+					{Call: "plan", Count: 1, DurationMS: 10},
+				},
+			},
+			{
+				Address:    "test_instance.a",
+				DurationMS: 5,
+				Calls: []ReportCall{
+					{Call: "plan", Count: 2, DurationMS: 4},
+					{Call: "refresh", Count: 1, DurationMS: 1},
+				},
+			},
+		},
+	}
+	// The phases were too quick to have predictable durations.
+	opts := []cmp.Option{
+		cmpopts.IgnoreFields(Report{}, "Started", "DurationMS"),
+		cmpopts.IgnoreFields(ReportPhase{}, "DurationMS"),
+	}
+	if diff := cmp.Diff(want, got, opts...); diff != "" {
+		t.Errorf("wrong report\n%s", diff)
+	}
+
+	// A nil profile ignores resource calls.
+	var nilProfile *Profile
+	nilProfile.AddResourceCall("test_instance.a", "plan", time.Second)
+}
+
+func TestReport_saveRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.json")
+	report := NewProfile().Report("apply")
+	report.CPUProfile = "cpu.pprof"
+	if err := report.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(report, got); diff != "" {
+		t.Errorf("wrong report\n%s", diff)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"format_version":"2.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadReport(path); err == nil {
+		t.Error("expected error for an unsupported format version")
+	}
+}
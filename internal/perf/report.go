@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReportFormatVersion is the version of the JSON format of Report. The minor
+// version is incremented for compatible changes, and the major version for
+// breaking ones.
+const ReportFormatVersion = "1.0"
+
+// Report is the JSON representation of a performance profile, as written by
+// the -profile option.
+type Report struct {
+	FormatVersion string    `json:"format_version"`
+	Command       string    `json:"command"`
+	Started       time.Time `json:"started"`
+	DurationMS    float64   `json:"duration_ms"`
+
+	// CPUProfile is the path of the pprof CPU profile captured during the
+	// same operation, if one was requested.
+	CPUProfile string `json:"cpu_profile,omitempty"`
+
+	// Phases are in the order in which each phase first started.
+	Phases []ReportPhase `json:"phases"`
+
+	// Resources are sorted by their total duration, slowest first.
+	Resources []ReportResource `json:"resources"`
+}
+
+// ReportPhase is the total time spent in a phase of the operation, such as
+// building a graph, over all the times it ran. Phases can overlap, so their
+// durations don't add up to the duration of the operation.
+type ReportPhase struct {
+	Name       string  `json:"name"`
+	Count      int     `json:"count"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// ReportResource is the time spent waiting for the provider calls of a
+// resource instance.
+type ReportResource struct {
+	Address    string       `json:"address"`
+	DurationMS float64      `json:"duration_ms"`
+	Calls      []ReportCall `json:"calls"`
+}
+
+// ReportCall is the total time spent on one kind of provider call for a
+// resource instance, such as "refresh", "plan", "apply", or "read".
+type ReportCall struct {
+	Call       string  `json:"call"`
+	Count      int     `json:"count"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// Save writes the report as JSON to the file at the given path.
+func (r *Report) Save(path string) error {
+	src, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(src, '\n'), 0644)
+}
+
+// ReadReport reads a report that was written by Save.
+func ReadReport(path string) (*Report, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ret Report
+	if err := json.Unmarshal(src, &ret); err != nil {
+		return nil, fmt.Errorf("invalid performance profile %s: %w", path, err)
+	}
+	major, _, _ := strings.Cut(ret.FormatVersion, ".")
+	supportedMajor, _, _ := strings.Cut(ReportFormatVersion, ".")
+	if major != supportedMajor {
+		return nil, fmt.Errorf("unsupported performance profile %s: format version %q", path, ret.FormatVersion)
+	}
+	return &ret, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"fmt"
+)
+
+// AutoApprovePolicy decides whether a plan can be applied without asking for
+// approval, based on the actions it proposes.
+type AutoApprovePolicy string
+
+const (
+	// NoAutoApprovePolicy means that every plan must be approved, unless
+	// approval is skipped altogether.
+	NoAutoApprovePolicy AutoApprovePolicy = ""
+
+	// AutoApproveNoDestroy approves plans that don't destroy any existing
+	// objects, either by deleting or by replacing them.
+	AutoApproveNoDestroy AutoApprovePolicy = "no-destroy"
+
+	// AutoApproveNoReplace approves plans that don't replace any existing
+	// objects. Deleting objects that are no longer in the configuration is
+	// still approved.
+	AutoApproveNoReplace AutoApprovePolicy = "no-replace"
+)
+
+// ParseAutoApprovePolicy returns the policy with the given name, as used for
+// the -auto-approve-policy command line option.
+func ParseAutoApprovePolicy(s string) (AutoApprovePolicy, error) {
+	switch p := AutoApprovePolicy(s); p {
+	case NoAutoApprovePolicy, AutoApproveNoDestroy, AutoApproveNoReplace:
+		return p, nil
+	default:
+		return NoAutoApprovePolicy, fmt.Errorf("unsupported policy %q; must be either %q or %q", s, AutoApproveNoDestroy, AutoApproveNoReplace)
+	}
+}
+
+// BlockingChanges returns the resource instance changes in the given set of
+// changes that the policy doesn't approve, in the order they appear in the
+// plan. A plan can be applied without asking for approval only if there are
+// none.
+func (p AutoApprovePolicy) BlockingChanges(changes *Changes) []*ResourceInstanceChangeSrc {
+	if changes == nil {
+		return nil
+	}
+	var ret []*ResourceInstanceChangeSrc
+	for _, rc := range changes.Resources {
+		if p.blocks(rc.Action) {
+			ret = append(ret, rc)
+		}
+	}
+	return ret
+}
+
+func (p AutoApprovePolicy) blocks(action Action) bool {
+	switch p {
+	case AutoApproveNoDestroy:
+		return action == Delete || action.IsReplace()
+	case AutoApproveNoReplace:
+		return action.IsReplace()
+	default:
+		return true
+	}
+}
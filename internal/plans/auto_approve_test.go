@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestAutoApprovePolicyBlockingChanges(t *testing.T) {
+	changes := &Changes{}
+	for name, action := range map[string]Action{
+		"create":  Create,
+		"update":  Update,
+		"delete":  Delete,
+		"replace": DeleteThenCreate,
+		"cbd":     CreateThenDelete,
+		"forget":  Forget,
+	} {
+		changes.Resources = append(changes.Resources, &ResourceInstanceChangeSrc{
+			Addr: addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_thing",
+				Name: name,
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			ChangeSrc: ChangeSrc{Action: action},
+		})
+	}
+
+	testCases := map[AutoApprovePolicy][]string{
+		AutoApproveNoDestroy: {"test_thing.cbd", "test_thing.delete", "test_thing.replace"},
+		AutoApproveNoReplace: {"test_thing.cbd", "test_thing.replace"},
+	}
+	for policy, want := range testCases {
+		t.Run(string(policy), func(t *testing.T) {
+			var got []string
+			for _, rc := range policy.BlockingChanges(changes) {
+				got = append(got, rc.Addr.String())
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong blocking changes\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAutoApprovePolicy(t *testing.T) {
+	for _, s := range []string{"", "no-destroy", "no-replace"} {
+		got, err := ParseAutoApprovePolicy(s)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", s, err)
+		}
+		if string(got) != s {
+			t.Errorf("wrong policy for %q: %q", s, got)
+		}
+	}
+	if _, err := ParseAutoApprovePolicy("always"); err == nil {
+		t.Error("expected error for unsupported policy")
+	}
+}
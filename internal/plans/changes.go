@@ -245,6 +245,23 @@ type ResourceInstanceChange struct {
 	// currently survive a round-trip through a saved plan file.
 	RequiredReplace cty.PathSet
 
+	// Provisioners describes the provisioners that will run when this change
+	// is applied, in the order they will run. It's populated only when
+	// provisioner previews were requested for the plan.
+	//
+	// This is retained only for UI-plan-rendering purposes and so it does not
+	// currently survive a round-trip through a saved plan file.
+	Provisioners []ProvisionerPreview
+
+	// MaskedPaths is a set of paths within the before and after values whose
+	// values are hidden when the change is shown in the UI, as requested by
+	// the "mask_in_output" lifecycle argument. Unlike sensitive values these
+	// are not marked, and so they have no effect on how the change is applied.
+	//
+	// This is retained only for UI-plan-rendering purposes and so it does not
+	// currently survive a round-trip through a saved plan file.
+	MaskedPaths cty.PathSet
+
 	// Private allows a provider to stash any extra data that is opaque to
 	// OpenTofu that relates to this change. OpenTofu will save this
 	// byte-for-byte and return it to the provider in the apply call.
@@ -273,6 +290,8 @@ func (rc *ResourceInstanceChange) Encode(ty cty.Type) (*ResourceInstanceChangeSr
 		ChangeSrc:       *cs,
 		ActionReason:    rc.ActionReason,
 		RequiredReplace: rc.RequiredReplace,
+		Provisioners:    rc.Provisioners,
+		MaskedPaths:     rc.MaskedPaths,
 		Private:         rc.Private,
 	}, err
 }
@@ -467,6 +486,12 @@ const (
 	// a check block and when the check assertions execute we want them to use
 	// the most up-to-date data.
 	ResourceInstanceReadBecauseCheckNested ResourceInstanceChangeActionReason = '#'
+
+	// ResourceInstanceReadBecauseDeferredByConfig indicates that the resource
+	// must be read during apply (rather than during planning) because its
+	// configuration sets the "defer_read_until_apply" lifecycle argument.
+	// This reason applies only to data resources.
+	ResourceInstanceReadBecauseDeferredByConfig ResourceInstanceChangeActionReason = '>'
 )
 
 // OutputChange describes a change to an output value.
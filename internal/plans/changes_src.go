@@ -70,6 +70,16 @@ type ResourceInstanceChangeSrc struct {
 	// Replace.
 	RequiredReplace cty.PathSet
 
+	// Provisioners describes the provisioners that will run when this change
+	// is applied. See the field of the same name in ResourceInstanceChange
+	// for more details.
+	Provisioners []ProvisionerPreview
+
+	// MaskedPaths is a set of paths whose values are hidden when the change is
+	// shown in the UI. See the field of the same name in
+	// ResourceInstanceChange for more details.
+	MaskedPaths cty.PathSet
+
 	// Private allows a provider to stash any extra data that is opaque to
 	// OpenTofu that relates to this change. OpenTofu will save this
 	// byte-for-byte and return it to the provider in the apply call.
@@ -98,6 +108,8 @@ func (rcs *ResourceInstanceChangeSrc) Decode(ty cty.Type) (*ResourceInstanceChan
 		Change:          *change,
 		ActionReason:    rcs.ActionReason,
 		RequiredReplace: rcs.RequiredReplace,
+		Provisioners:    rcs.Provisioners,
+		MaskedPaths:     rcs.MaskedPaths,
 		Private:         rcs.Private,
 	}, nil
 }
@@ -117,6 +129,7 @@ func (rcs *ResourceInstanceChangeSrc) DeepCopy() *ResourceInstanceChangeSrc {
 	ret := *rcs
 
 	ret.RequiredReplace = cty.NewPathSet(ret.RequiredReplace.List()...)
+	ret.MaskedPaths = cty.NewPathSet(ret.MaskedPaths.List()...)
 
 	if len(ret.Private) != 0 {
 		private := make([]byte, len(ret.Private))
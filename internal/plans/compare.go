@@ -0,0 +1,213 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// DifferenceKind describes how a change differs between two plans.
+type DifferenceKind rune
+
+const (
+	// DifferenceAdded means that only the new plan includes the change.
+	DifferenceAdded DifferenceKind = '+'
+
+	// DifferenceRemoved means that only the old plan includes the change.
+	DifferenceRemoved DifferenceKind = '-'
+
+	// DifferenceChanged means that both plans include a change for the same
+	// object, but the changes differ.
+	DifferenceChanged DifferenceKind = '~'
+)
+
+func (k DifferenceKind) String() string {
+	switch k {
+	case DifferenceAdded:
+		return "added"
+	case DifferenceRemoved:
+		return "removed"
+	case DifferenceChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("DifferenceKind(%q)", rune(k))
+	}
+}
+
+// ChangeDifference describes a difference between the changes that two plans
+// propose for the same object.
+type ChangeDifference struct {
+	// Addr is the address of the resource instance or output value the
+	// changes apply to. For deposed objects it includes the deposed key.
+	Addr string
+
+	Kind DifferenceKind
+
+	// OldAction and NewAction are the actions proposed by each plan. Only
+	// the action for the plan that includes the change is set when Kind is
+	// DifferenceAdded or DifferenceRemoved.
+	OldAction, NewAction Action
+
+	// Fields lists which aspects of the change differ, when Kind is
+	// DifferenceChanged. The possible values are "action", "action_reason",
+	// "before", "after", "replace_paths", "importing", and
+	// "previous_address".
+	Fields []string
+}
+
+// CompareChanges returns the differences between the changes proposed by two
+// plans, sorted by address.
+//
+// Only changes that would have an effect are compared, so no-op changes are
+// treated the same as an absent change unless they import or move an object.
+// Provider-specific private data is not compared, because it can legitimately
+// differ between two plans for the same changes.
+func CompareChanges(old, new *Changes) []ChangeDifference {
+	var ret []ChangeDifference
+
+	oldResources := comparableResourceChanges(old)
+	newResources := comparableResourceChanges(new)
+	for addr, oldChange := range oldResources {
+		newChange, ok := newResources[addr]
+		if !ok {
+			ret = append(ret, ChangeDifference{Addr: addr, Kind: DifferenceRemoved, OldAction: oldChange.Action})
+			continue
+		}
+		var fields []string
+		if oldChange.ActionReason != newChange.ActionReason {
+			fields = append(fields, "action_reason")
+		}
+		if !oldChange.RequiredReplace.Equal(newChange.RequiredReplace) {
+			fields = append(fields, "replace_paths")
+		}
+		if (oldChange.Importing == nil) != (newChange.Importing == nil) ||
+			(oldChange.Importing != nil && oldChange.Importing.ID != newChange.Importing.ID) {
+			fields = append(fields, "importing")
+		}
+		if !prevRunAddr(oldChange).Equal(prevRunAddr(newChange)) {
+			fields = append(fields, "previous_address")
+		}
+		if diff, ok := compareChangeSrc(addr, &oldChange.ChangeSrc, &newChange.ChangeSrc, fields); ok {
+			ret = append(ret, diff)
+		}
+	}
+	for addr, newChange := range newResources {
+		if _, ok := oldResources[addr]; !ok {
+			ret = append(ret, ChangeDifference{Addr: addr, Kind: DifferenceAdded, NewAction: newChange.Action})
+		}
+	}
+
+	oldOutputs := comparableOutputChanges(old)
+	newOutputs := comparableOutputChanges(new)
+	for addr, oldChange := range oldOutputs {
+		newChange, ok := newOutputs[addr]
+		if !ok {
+			ret = append(ret, ChangeDifference{Addr: addr, Kind: DifferenceRemoved, OldAction: oldChange.Action})
+			continue
+		}
+		if diff, ok := compareChangeSrc(addr, &oldChange.ChangeSrc, &newChange.ChangeSrc, nil); ok {
+			ret = append(ret, diff)
+		}
+	}
+	for addr, newChange := range newOutputs {
+		if _, ok := oldOutputs[addr]; !ok {
+			ret = append(ret, ChangeDifference{Addr: addr, Kind: DifferenceAdded, NewAction: newChange.Action})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Addr < ret[j].Addr
+	})
+	return ret
+}
+
+// compareChangeSrc compares the parts of a change that are common to
+// resource instances and output values, adding to the given fields that
+// already differ.
+func compareChangeSrc(addr string, old, new *ChangeSrc, fields []string) (ChangeDifference, bool) {
+	var common []string
+	if old.Action != new.Action {
+		common = append(common, "action")
+	}
+	fields = append(common, fields...)
+	if !bytes.Equal(old.Before, new.Before) || !marksEqual(old.BeforeValMarks, new.BeforeValMarks) {
+		fields = append(fields, "before")
+	}
+	if !bytes.Equal(old.After, new.After) || !marksEqual(old.AfterValMarks, new.AfterValMarks) {
+		fields = append(fields, "after")
+	}
+	if len(fields) == 0 {
+		return ChangeDifference{}, false
+	}
+	return ChangeDifference{
+		Addr:      addr,
+		Kind:      DifferenceChanged,
+		OldAction: old.Action,
+		NewAction: new.Action,
+		Fields:    fields,
+	}, true
+}
+
+func comparableResourceChanges(changes *Changes) map[string]*ResourceInstanceChangeSrc {
+	ret := make(map[string]*ResourceInstanceChangeSrc)
+	if changes == nil {
+		return ret
+	}
+	for _, change := range changes.Resources {
+		moved := !prevRunAddr(change).Equal(change.Addr)
+		if change.Action == NoOp && change.Importing == nil && !moved {
+			continue
+		}
+		addr := change.Addr.String()
+		if change.DeposedKey != states.NotDeposed {
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, change.DeposedKey)
+		}
+		ret[addr] = change
+	}
+	return ret
+}
+
+// prevRunAddr returns the address the object had in the previous run, which
+// is the same as its current address if it hasn't moved.
+func prevRunAddr(change *ResourceInstanceChangeSrc) addrs.AbsResourceInstance {
+	if change.PrevRunAddr.Resource.Resource.Type == "" {
+		return change.Addr
+	}
+	return change.PrevRunAddr
+}
+
+func comparableOutputChanges(changes *Changes) map[string]*OutputChangeSrc {
+	ret := make(map[string]*OutputChangeSrc)
+	if changes == nil {
+		return ret
+	}
+	for _, change := range changes.Outputs {
+		if change.Action == NoOp {
+			continue
+		}
+		ret[change.Addr.String()] = change
+	}
+	return ret
+}
+
+func marksEqual(a, b []cty.PathValueMarks) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
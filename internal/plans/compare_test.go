@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestCompareChanges(t *testing.T) {
+	instance := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_thing",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	value := func(v cty.Value) DynamicValue {
+		dv, err := NewDynamicValue(v, cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dv
+	}
+	change := func(name string, action Action, after cty.Value) *ResourceInstanceChangeSrc {
+		return &ResourceInstanceChangeSrc{
+			Addr:        instance(name),
+			PrevRunAddr: instance(name),
+			ChangeSrc: ChangeSrc{
+				Action: action,
+				Before: value(cty.NullVal(cty.String)),
+				After:  value(after),
+			},
+		}
+	}
+	output := func(name string, action Action) *OutputChangeSrc {
+		return &OutputChangeSrc{
+			Addr: addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance),
+			ChangeSrc: ChangeSrc{
+				Action: action,
+			},
+		}
+	}
+
+	replaced := change("replaced", DeleteThenCreate, cty.StringVal("a"))
+	replaced.ActionReason = ResourceInstanceReplaceBecauseTainted
+	old := &Changes{
+		Resources: []*ResourceInstanceChangeSrc{
+			change("same", Create, cty.StringVal("a")),
+			change("removed", Delete, cty.NullVal(cty.String)),
+			change("updated", Update, cty.StringVal("a")),
+			change("noop", NoOp, cty.StringVal("a")),
+		},
+		Outputs: []*OutputChangeSrc{
+			output("same", Create),
+			output("gone", Create),
+		},
+	}
+	new := &Changes{
+		Resources: []*ResourceInstanceChangeSrc{
+			change("same", Create, cty.StringVal("a")),
+			change("updated", DeleteThenCreate, cty.StringVal("b")),
+			replaced,
+		},
+		Outputs: []*OutputChangeSrc{
+			output("same", Create),
+		},
+	}
+
+	got := CompareChanges(old, new)
+	want := []ChangeDifference{
+		{Addr: "output.gone", Kind: DifferenceRemoved, OldAction: Create},
+		{Addr: "test_thing.removed", Kind: DifferenceRemoved, OldAction: Delete},
+		{Addr: "test_thing.replaced", Kind: DifferenceAdded, NewAction: DeleteThenCreate},
+		{Addr: "test_thing.updated", Kind: DifferenceChanged, OldAction: Update, NewAction: DeleteThenCreate, Fields: []string{"action", "after"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong differences\n%s", diff)
+	}
+
+	if got := CompareChanges(old, old); len(got) != 0 {
+		t.Errorf("comparing a plan with itself found differences: %#v", got)
+	}
+}
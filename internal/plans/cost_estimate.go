@@ -0,0 +1,41 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+// CostEstimate is an estimate of the monthly cost of the infrastructure that
+// a plan would produce, as reported by an external cost estimation program.
+//
+// The costs are decimal numbers kept in the string form that the program
+// reported them in, so that OpenTofu doesn't lose any precision by
+// converting them.
+type CostEstimate struct {
+	// Currency is the code of the currency of all of the costs, such as
+	// "USD".
+	Currency string
+
+	// TotalMonthlyCost is the estimated monthly cost of the infrastructure
+	// after applying the plan.
+	TotalMonthlyCost string
+
+	// PastTotalMonthlyCost is the estimated monthly cost of the
+	// infrastructure before applying the plan, or an empty string if the
+	// estimator didn't report it.
+	PastTotalMonthlyCost string
+
+	// Resources are the estimated monthly costs of individual resource
+	// instances, in the order the estimator reported them.
+	Resources []ResourceCost
+}
+
+// ResourceCost is the estimated monthly cost of a single resource instance.
+type ResourceCost struct {
+	// Address is the address of the resource instance, as the estimator
+	// reported it.
+	Address string
+
+	MonthlyCost     string
+	PastMonthlyCost string
+}
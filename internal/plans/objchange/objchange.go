@@ -73,6 +73,81 @@ func PlannedDataResourceObject(schema *configschema.Block, config cty.Value) cty
 	return proposedNew(schema, prior, config)
 }
 
+// PlannedDeferredResourceObject returns the planned new value for a managed
+// resource that OpenTofu Core must plan on the provider's behalf, because the
+// provider couldn't plan it with a provider configuration that isn't yet
+// known. The real plan is then created during the apply phase, once the
+// provider configuration is known.
+//
+// The result is like PlannedDataResourceObject, except that it replaces all of
+// the attributes not set in the configuration with unknown values, including
+// the ones that aren't Computed, so that it's compatible with any default
+// values the provider might add when it finally plans the change.
+func PlannedDeferredResourceObject(schema *configschema.Block, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	newAttrs := make(map[string]cty.Value, len(schema.Attributes)+len(schema.BlockTypes))
+	for name, attr := range schema.Attributes {
+		v := config.GetAttr(name)
+		if v.IsNull() {
+			v = cty.UnknownVal(attr.ImpliedType())
+		}
+		newAttrs[name] = v
+	}
+	for name, blockType := range schema.BlockTypes {
+		newAttrs[name] = plannedDeferredNestedBlock(blockType, config.GetAttr(name))
+	}
+	return cty.ObjectVal(newAttrs)
+}
+
+func plannedDeferredNestedBlock(schema *configschema.NestedBlock, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	switch schema.Nesting {
+	case configschema.NestingSingle, configschema.NestingGroup:
+		return PlannedDeferredResourceObject(&schema.Block, config)
+	case configschema.NestingList, configschema.NestingSet, configschema.NestingMap:
+		if config.LengthInt() == 0 {
+			return config
+		}
+		ty := config.Type()
+		switch {
+		case ty.IsListType() || ty.IsTupleType():
+			var elems []cty.Value
+			for it := config.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				elems = append(elems, PlannedDeferredResourceObject(&schema.Block, v))
+			}
+			if ty.IsTupleType() {
+				return cty.TupleVal(elems)
+			}
+			return cty.ListVal(elems)
+		case ty.IsSetType():
+			var elems []cty.Value
+			for it := config.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				elems = append(elems, PlannedDeferredResourceObject(&schema.Block, v))
+			}
+			return cty.SetVal(elems)
+		case ty.IsMapType() || ty.IsObjectType():
+			elems := make(map[string]cty.Value)
+			for it := config.ElementIterator(); it.Next(); {
+				k, v := it.Element()
+				elems[k.AsString()] = PlannedDeferredResourceObject(&schema.Block, v)
+			}
+			if ty.IsObjectType() {
+				return cty.ObjectVal(elems)
+			}
+			return cty.MapVal(elems)
+		}
+	}
+	return config
+}
+
 func proposedNew(schema *configschema.Block, prior, config cty.Value) cty.Value {
 	if config.IsNull() || !config.IsKnown() {
 		// A block config should never be null at this point. The only nullable
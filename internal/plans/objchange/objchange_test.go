@@ -2742,6 +2742,69 @@ func TestProposedNew(t *testing.T) {
 	}
 }
 
+func TestPlannedDeferredResourceObject(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id": {
+				Type:     cty.String,
+				Computed: true,
+			},
+			"name": {
+				Type:     cty.String,
+				Required: true,
+			},
+			"port": {
+				Type:     cty.Number,
+				Optional: true,
+			},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"rule": {
+				Nesting: configschema.NestingList,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"cidr": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"protocol": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	config := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.NullVal(cty.String),
+		"name": cty.StringVal("example"),
+		"port": cty.NullVal(cty.Number),
+		"rule": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"cidr":     cty.StringVal("10.0.0.0/8"),
+				"protocol": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.UnknownVal(cty.String),
+		"name": cty.StringVal("example"),
+		"port": cty.UnknownVal(cty.Number),
+		"rule": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"cidr":     cty.StringVal("10.0.0.0/8"),
+				"protocol": cty.UnknownVal(cty.String),
+			}),
+		}),
+	})
+
+	got := PlannedDeferredResourceObject(schema, config)
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %swant: %s", dump.Value(got), dump.Value(want))
+	}
+}
+
 var testAttributes = map[string]*configschema.Attribute{
 	"optional": {
 		Type:     cty.String,
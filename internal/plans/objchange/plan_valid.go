@@ -285,6 +285,11 @@ func assertPlannedValueValid(attrS *configschema.Attribute, priorV, configV, pla
 	}
 
 	switch {
+	// Write-only attributes are never persisted, so OpenTofu always plans
+	// them as null regardless of the configuration.
+	case attrS.WriteOnly && plannedV.IsNull():
+		return errs
+
 	// The provider can plan any value for a computed-only attribute. There may
 	// be a config value here in the case where a user used `ignore_changes` on
 	// a computed attribute and ignored the warning, or we failed to validate
@@ -30,6 +30,27 @@ func TestAssertPlanValid(t *testing.T) {
 			cty.EmptyObjectVal,
 			nil,
 		},
+		"write-only attribute planned as null": {
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"password": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			cty.NullVal(cty.Object(map[string]cty.Type{
+				"password": cty.String,
+			})),
+			cty.ObjectVal(map[string]cty.Value{
+				"password": cty.StringVal("secret"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"password": cty.NullVal(cty.String),
+			}),
+			nil,
+		},
 		"no computed, all match": {
 			&configschema.Block{
 				Attributes: map[string]*configschema.Attribute{
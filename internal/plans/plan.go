@@ -42,7 +42,16 @@ type Plan struct {
 	// checked carefully against existing destroy behaviors.
 	UIMode Mode
 
-	VariableValues    map[string]DynamicValue
+	VariableValues map[string]DynamicValue
+
+	// EphemeralVariableValues records the values of any ephemeral root
+	// module variables that were set when creating the plan.
+	//
+	// Unlike VariableValues, these are never written to a saved plan file,
+	// so a caller applying a saved plan must populate this field again with
+	// values provided for the apply.
+	EphemeralVariableValues map[string]DynamicValue
+
 	Changes           *Changes
 	DriftedResources  []*ResourceInstanceChangeSrc
 	TargetAddrs       []addrs.Targetable
@@ -115,8 +124,35 @@ type Plan struct {
 	// representation of the plan.
 	ExternalReferences []*addrs.Reference
 
+	// CostEstimate is the estimate of the cost of this plan's changes that
+	// was reported by the cost estimator in the CLI configuration, if any.
+	//
+	// Like PlannedState this is never written into the binary plan file.
+	// OpenTofu instead runs the cost estimator again each time it renders a
+	// saved plan, so that the estimate reflects current prices.
+	CostEstimate *CostEstimate
+
 	// Timestamp is the record of truth for when the plan happened.
 	Timestamp time.Time
+
+	// ExpiresAt, if not zero, is the time after which the plan can no longer
+	// be applied.
+	ExpiresAt time.Time
+
+	// DeferredResources lists the resources that OpenTofu didn't plan
+	// because their count or for_each arguments, or the configuration of
+	// their instances, depended on values that won't be known until apply.
+	// Another plan after this one is applied will plan them.
+	//
+	// This is only populated when the plan was created with deferral
+	// allowed; otherwise the unknown values are reported as errors.
+	DeferredResources []addrs.AbsResource
+}
+
+// Expired returns true if the plan has an expiry time that has passed as of
+// the given time.
+func (p *Plan) Expired(now time.Time) bool {
+	return !p.ExpiresAt.IsZero() && now.After(p.ExpiresAt)
 }
 
 // CanApply returns true if and only if the receiving plan includes content
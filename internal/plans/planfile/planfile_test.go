@@ -6,6 +6,7 @@
 package planfile
 
 import (
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -217,3 +218,22 @@ func TestWrappedCloud(t *testing.T) {
 		t.Fatalf("wrapped plan claims to be both kinds of plan at once")
 	}
 }
+
+func TestOpenWrappedBytes(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "cloudplan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpf, err := OpenWrappedBytes(src, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("failed to open valid cloud plan: %s", err)
+	}
+	if !wpf.IsCloud() {
+		t.Fatalf("failed to open cloud plan contents as a cloud plan")
+	}
+
+	_, err = OpenWrappedBytes([]byte("not a plan"), encryption.PlanEncryptionDisabled())
+	if err == nil || !strings.Contains(err.Error(), "not a valid zip file") {
+		t.Fatalf("wrong error for invalid contents: %v", err)
+	}
+}
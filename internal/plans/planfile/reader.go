@@ -64,7 +64,12 @@ func Open(filename string, enc encryption.PlanEncryption) (*Reader, error) {
 	if err != nil {
 		return nil, err
 	}
+	return OpenBytes(raw, enc)
+}
 
+// OpenBytes is like Open, but reads the plan file from the given bytes that
+// have already been read from it, such as after verifying its signature.
+func OpenBytes(raw []byte, enc encryption.PlanEncryption) (*Reader, error) {
 	decrypted, diags := enc.DecryptPlan(raw)
 	if diags != nil {
 		return nil, diags
@@ -79,10 +84,8 @@ func Open(filename string, enc encryption.PlanEncryption) (*Reader, error) {
 
 		// To give a better error message, we'll sniff to see if this looks
 		// like our old plan format from versions prior to 0.12.
-		if b, sErr := os.ReadFile(filename); sErr == nil {
-			if bytes.HasPrefix(b, []byte("tfplan")) {
-				return nil, errUnusable(fmt.Errorf("the given plan file was created by an earlier version of OpenTofu, or an earlier version of Terraform; plan files cannot be shared between different OpenTofu or Terraform versions"))
-			}
+		if bytes.HasPrefix(raw, []byte("tfplan")) {
+			return nil, errUnusable(fmt.Errorf("the given plan file was created by an earlier version of OpenTofu, or an earlier version of Terraform; plan files cannot be shared between different OpenTofu or Terraform versions"))
 		}
 		return nil, err
 	}
@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// ErrNotSigned is returned by [VerifySignature] when a plan file has no
+// signature file alongside it.
+var ErrNotSigned = errors.New("plan file is not signed")
+
+// SigningConfig describes the OpenPGP keys used to sign saved plan files and
+// to verify their signatures.
+type SigningConfig struct {
+	// SigningKeyFile is the path to an ASCII-armored OpenPGP private key
+	// used to sign new plan files. Plan files are not signed if this is
+	// empty.
+	SigningKeyFile string
+
+	// SigningKeyPassphrase decrypts the signing key, if it is encrypted.
+	SigningKeyPassphrase string
+
+	// TrustedKeyFiles are paths to ASCII-armored OpenPGP public keys whose
+	// signatures are accepted when verifying a plan file. The public part
+	// of the signing key is always trusted too.
+	TrustedKeyFiles []string
+}
+
+// CanSign returns true if the configuration includes a signing key.
+func (c SigningConfig) CanSign() bool {
+	return c.SigningKeyFile != ""
+}
+
+// CanVerify returns true if the configuration includes at least one key that
+// signatures can be verified against.
+func (c SigningConfig) CanVerify() bool {
+	return c.SigningKeyFile != "" || len(c.TrustedKeyFiles) != 0
+}
+
+// SignatureFilename returns the path of the detached signature file for the
+// plan file at the given path.
+func SignatureFilename(filename string) string {
+	return filename + ".sig"
+}
+
+// Sign writes an ASCII-armored detached signature for the plan file with the
+// given filename, overwriting any signature that already exists.
+//
+// The signature covers the plan file exactly as it was written to disk, so it
+// also covers encrypted plan files without needing the encryption key.
+func Sign(filename string, config SigningConfig) error {
+	keyring, err := readKeyRing(config.SigningKeyFile)
+	if err != nil {
+		return err
+	}
+	var signer *openpgp.Entity
+	for _, entity := range keyring {
+		if entity.PrivateKey != nil {
+			signer = entity
+			break
+		}
+	}
+	if signer == nil {
+		return fmt.Errorf("signing key file %s does not contain a private key", config.SigningKeyFile)
+	}
+	if signer.PrivateKey.Encrypted {
+		if config.SigningKeyPassphrase == "" {
+			return fmt.Errorf("signing key in %s is encrypted, but no passphrase is configured", config.SigningKeyFile)
+		}
+		if err := signer.DecryptPrivateKeys([]byte(config.SigningKeyPassphrase)); err != nil {
+			return fmt.Errorf("failed to decrypt signing key in %s: %w", config.SigningKeyFile, err)
+		}
+	}
+
+	plan, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, signer, bytes.NewReader(plan), nil); err != nil {
+		return fmt.Errorf("failed to sign %s: %w", filename, err)
+	}
+	return os.WriteFile(SignatureFilename(filename), sig.Bytes(), 0644)
+}
+
+// VerifySignature checks the detached signature of the plan file with the
+// given filename against the keys in the given configuration, returning the
+// key that made the signature.
+//
+// If there is no signature file then the error is [ErrNotSigned].
+func VerifySignature(filename string, config SigningConfig) (*openpgp.Entity, error) {
+	plan, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return VerifySignatureBytes(filename, plan, config)
+}
+
+// VerifySignatureBytes is like VerifySignature, but checks the signature
+// against the given contents of the plan file. Callers that go on to load the
+// plan should load it from the same bytes, so that the file can't be replaced
+// after its signature is verified.
+func VerifySignatureBytes(filename string, plan []byte, config SigningConfig) (*openpgp.Entity, error) {
+	sig, err := os.ReadFile(SignatureFilename(filename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotSigned
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keyring openpgp.EntityList
+	keyFiles := config.TrustedKeyFiles
+	if config.SigningKeyFile != "" {
+		keyFiles = append([]string{config.SigningKeyFile}, keyFiles...)
+	}
+	for _, keyFile := range keyFiles {
+		keys, err := readKeyRing(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		keyring = append(keyring, keys...)
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(plan), bytes.NewReader(sig), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature in %s: %w", SignatureFilename(filename), err)
+	}
+	return signer, nil
+}
+
+func readKeyRing(filename string) (openpgp.EntityList, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	defer f.Close()
+	keyring, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", filename, err)
+	}
+	return keyring, nil
+}
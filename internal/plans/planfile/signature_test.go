@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestSignAndVerifySignature(t *testing.T) {
+	dir := t.TempDir()
+	signing := writeTestKey(t, dir, "signing", true)
+	trusted := writeTestKey(t, dir, "trusted", false)
+	untrusted := writeTestKey(t, dir, "untrusted", true)
+
+	plan := filepath.Join(dir, "saved.tfplan")
+	if err := os.WriteFile(plan, []byte("not really a plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifySignature(plan, SigningConfig{SigningKeyFile: signing}); !errors.Is(err, ErrNotSigned) {
+		t.Fatalf("wrong error for unsigned plan: %v", err)
+	}
+
+	if err := Sign(plan, SigningConfig{SigningKeyFile: signing}); err != nil {
+		t.Fatalf("failed to sign: %s", err)
+	}
+	if _, err := os.Stat(SignatureFilename(plan)); err != nil {
+		t.Fatalf("no signature file: %s", err)
+	}
+
+	// The signing key is trusted along with the trusted key files.
+	if _, err := VerifySignature(plan, SigningConfig{SigningKeyFile: signing}); err != nil {
+		t.Fatalf("unexpected error verifying with the signing key: %s", err)
+	}
+	if _, err := VerifySignature(plan, SigningConfig{TrustedKeyFiles: []string{trusted, signing}}); err != nil {
+		t.Fatalf("unexpected error verifying with trusted keys: %s", err)
+	}
+	if _, err := VerifySignature(plan, SigningConfig{SigningKeyFile: untrusted, TrustedKeyFiles: []string{trusted}}); err == nil {
+		t.Fatal("expected error verifying with untrusted keys, but got none")
+	}
+
+	if err := os.WriteFile(plan, []byte("a tampered plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifySignature(plan, SigningConfig{SigningKeyFile: signing}); err == nil {
+		t.Fatal("expected error verifying a modified plan, but got none")
+	}
+
+	// The signature is checked against the given contents, not whatever is
+	// in the plan file by then.
+	if _, err := VerifySignatureBytes(plan, []byte("not really a plan"), SigningConfig{SigningKeyFile: signing}); err != nil {
+		t.Fatalf("unexpected error verifying the signed contents: %s", err)
+	}
+	if _, err := VerifySignatureBytes(plan, []byte("a tampered plan"), SigningConfig{SigningKeyFile: signing}); err == nil {
+		t.Fatal("expected error verifying modified contents, but got none")
+	}
+}
+
+func TestSign_publicKeyOnly(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, "public", false)
+	plan := filepath.Join(dir, "saved.tfplan")
+	if err := os.WriteFile(plan, []byte("not really a plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Sign(plan, SigningConfig{SigningKeyFile: key}); err == nil {
+		t.Fatal("expected error signing with a public key, but got none")
+	}
+}
+
+// writeTestKey generates a new OpenPGP key and writes it to an ASCII-armored
+// file in the given directory, returning the filename.
+func writeTestKey(t *testing.T, dir, name string, private bool) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, name+".asc")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	blockType := openpgp.PublicKeyType
+	if private {
+		blockType = openpgp.PrivateKeyType
+	}
+	w, err := armor.Encode(f, blockType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if private {
+		err = entity.SerializePrivate(w, nil)
+	} else {
+		err = entity.Serialize(w)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
@@ -239,6 +239,14 @@ func readTfplan(r io.Reader) (*plans.Plan, error) {
 		plan.ForceReplaceAddrs = append(plan.ForceReplaceAddrs, addr)
 	}
 
+	for _, rawDeferredAddr := range rawPlan.DeferredResources {
+		addr, diags := addrs.ParseAbsResourceStr(rawDeferredAddr)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("plan contains invalid deferred resource address %q: %w", rawDeferredAddr, diags.Err())
+		}
+		plan.DeferredResources = append(plan.DeferredResources, addr)
+	}
+
 	for name, rawVal := range rawPlan.Variables {
 		val, err := valueFromTfplan(rawVal)
 		if err != nil {
@@ -264,6 +272,11 @@ func readTfplan(r io.Reader) (*plans.Plan, error) {
 	if plan.Timestamp, err = time.Parse(time.RFC3339, rawPlan.Timestamp); err != nil {
 		return nil, fmt.Errorf("invalid value for timestamp %s: %w", rawPlan.Timestamp, err)
 	}
+	if rawPlan.ExpiresAt != "" {
+		if plan.ExpiresAt, err = time.Parse(time.RFC3339, rawPlan.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("invalid value for expires_at %s: %w", rawPlan.ExpiresAt, err)
+		}
+	}
 
 	return plan, nil
 }
@@ -358,6 +371,8 @@ func resourceChangeFromTfplan(rawChange *planproto.ResourceInstanceChange) (*pla
 		ret.ActionReason = plans.ResourceInstanceReadBecauseCheckNested
 	case planproto.ResourceInstanceActionReason_DELETE_BECAUSE_NO_MOVE_TARGET:
 		ret.ActionReason = plans.ResourceInstanceDeleteBecauseNoMoveTarget
+	case planproto.ResourceInstanceActionReason_READ_BECAUSE_DEFERRED_BY_CONFIG:
+		ret.ActionReason = plans.ResourceInstanceReadBecauseDeferredByConfig
 	default:
 		return nil, fmt.Errorf("resource has invalid action reason %s", rawChange.ActionReason)
 	}
@@ -626,6 +641,10 @@ func writeTfplan(plan *plans.Plan, w io.Writer) error {
 		rawPlan.ForceReplaceAddrs = append(rawPlan.ForceReplaceAddrs, replaceAddr.String())
 	}
 
+	for _, deferredAddr := range plan.DeferredResources {
+		rawPlan.DeferredResources = append(rawPlan.DeferredResources, deferredAddr.String())
+	}
+
 	for name, val := range plan.VariableValues {
 		rawPlan.Variables[name] = valueToTfplan(val)
 	}
@@ -644,6 +663,9 @@ func writeTfplan(plan *plans.Plan, w io.Writer) error {
 	}
 
 	rawPlan.Timestamp = plan.Timestamp.Format(time.RFC3339)
+	if !plan.ExpiresAt.IsZero() {
+		rawPlan.ExpiresAt = plan.ExpiresAt.Format(time.RFC3339)
+	}
 
 	src, err := proto.Marshal(rawPlan)
 	if err != nil {
@@ -758,6 +780,8 @@ func resourceChangeToTfplan(change *plans.ResourceInstanceChangeSrc) (*planproto
 		ret.ActionReason = planproto.ResourceInstanceActionReason_READ_BECAUSE_CHECK_NESTED
 	case plans.ResourceInstanceDeleteBecauseNoMoveTarget:
 		ret.ActionReason = planproto.ResourceInstanceActionReason_DELETE_BECAUSE_NO_MOVE_TARGET
+	case plans.ResourceInstanceReadBecauseDeferredByConfig:
+		ret.ActionReason = planproto.ResourceInstanceActionReason_READ_BECAUSE_DEFERRED_BY_CONFIG
 	default:
 		return nil, fmt.Errorf("resource %s has unsupported action reason %s", change.Addr, change.ActionReason)
 	}
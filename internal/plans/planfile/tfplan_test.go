@@ -8,6 +8,7 @@ package planfile
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/zclconf/go-cty/cty"
@@ -290,6 +291,14 @@ func TestTFPlanRoundTrip(t *testing.T) {
 			),
 			Workspace: "default",
 		},
+		ExpiresAt: time.Date(2026, time.October, 15, 9, 30, 0, 0, time.UTC),
+		DeferredResources: []addrs.AbsResource{
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_thing",
+				Name: "later",
+			}.Absolute(addrs.RootModuleInstance),
+		},
 	}
 
 	var buf bytes.Buffer
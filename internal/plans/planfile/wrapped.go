@@ -85,6 +85,24 @@ func OpenWrapped(filename string, enc encryption.PlanEncryption) (*WrappedPlanFi
 	}
 	// Then, try to load it as a cloud plan.
 	cloud, cloudErr := cloudplan.LoadSavedPlanBookmark(filename)
+	return wrappedCloud(cloud, localErr, cloudErr)
+}
+
+// OpenWrappedBytes is like OpenWrapped, but reads the plan file from the given
+// bytes that have already been read from it, such as after verifying its
+// signature, so that the file can't change in between.
+func OpenWrappedBytes(raw []byte, enc encryption.PlanEncryption) (*WrappedPlanFile, error) {
+	local, localErr := OpenBytes(raw, enc)
+	if localErr == nil {
+		return &WrappedPlanFile{local: local}, nil
+	}
+	cloud, cloudErr := cloudplan.ParseSavedPlanBookmark(raw)
+	return wrappedCloud(cloud, localErr, cloudErr)
+}
+
+// wrappedCloud returns the given cloud plan, after failing to load a local
+// plan from the same file, or an error describing both failures.
+func wrappedCloud(cloud cloudplan.SavedPlanBookmark, localErr, cloudErr error) (*WrappedPlanFile, error) {
 	if cloudErr == nil {
 		return &WrappedPlanFile{cloud: &cloud}, nil
 	}
@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ProvisionerPreview describes what a provisioner of a resource instance will
+// run when the planned change to that resource instance is applied, so that
+// it can be reviewed before applying.
+type ProvisionerPreview struct {
+	// Type is the provisioner type, such as "remote-exec".
+	Type string
+
+	// Destroy is true for a destroy-time provisioner, which runs before the
+	// current object is destroyed, and false for a creation-time provisioner,
+	// which runs after the new object is created.
+	Destroy bool
+
+	// Hook is the event of the resource lifecycle hook that this provisioner
+	// runs, such as "post_create", or empty for a "provisioner" block.
+	Hook string
+
+	// Config is the evaluated provisioner configuration, conforming to the
+	// provisioner's schema. It may contain unknown values for anything that
+	// won't be known until the change is applied, and it retains any marks
+	// from the configuration.
+	Config cty.Value
+
+	// Connection describes the remote host that the provisioner will connect
+	// to, if any. It's an object with only the arguments from the connection
+	// configuration that identify the host, such as "type", "host" and
+	// "user", or a null value if there's no connection configuration.
+	Connection cty.Value
+}
@@ -22,13 +22,14 @@ func _() {
 	_ = x[ResourceInstanceReadBecauseConfigUnknown-63]
 	_ = x[ResourceInstanceReadBecauseDependencyPending-33]
 	_ = x[ResourceInstanceReadBecauseCheckNested-35]
+	_ = x[ResourceInstanceReadBecauseDeferredByConfig-62]
 }
 
 const (
 	_ResourceInstanceChangeActionReason_name_0 = "ResourceInstanceChangeNoReason"
 	_ResourceInstanceChangeActionReason_name_1 = "ResourceInstanceReadBecauseDependencyPending"
 	_ResourceInstanceChangeActionReason_name_2 = "ResourceInstanceReadBecauseCheckNested"
-	_ResourceInstanceChangeActionReason_name_3 = "ResourceInstanceReadBecauseConfigUnknown"
+	_ResourceInstanceChangeActionReason_name_3 = "ResourceInstanceReadBecauseDeferredByConfigResourceInstanceReadBecauseConfigUnknown"
 	_ResourceInstanceChangeActionReason_name_4 = "ResourceInstanceDeleteBecauseNoMoveTarget"
 	_ResourceInstanceChangeActionReason_name_5 = "ResourceInstanceDeleteBecauseCountIndexResourceInstanceReplaceByTriggersResourceInstanceDeleteBecauseEachKeyResourceInstanceReplaceBecauseCannotUpdate"
 	_ResourceInstanceChangeActionReason_name_6 = "ResourceInstanceDeleteBecauseNoModuleResourceInstanceDeleteBecauseNoResourceConfig"
@@ -38,6 +39,7 @@ const (
 )
 
 var (
+	_ResourceInstanceChangeActionReason_index_3 = [...]uint8{0, 43, 83}
 	_ResourceInstanceChangeActionReason_index_5 = [...]uint8{0, 39, 72, 108, 150}
 	_ResourceInstanceChangeActionReason_index_6 = [...]uint8{0, 37, 82}
 )
@@ -50,8 +52,9 @@ func (i ResourceInstanceChangeActionReason) String() string {
 		return _ResourceInstanceChangeActionReason_name_1
 	case i == 35:
 		return _ResourceInstanceChangeActionReason_name_2
-	case i == 63:
-		return _ResourceInstanceChangeActionReason_name_3
+	case 62 <= i && i <= 63:
+		i -= 62
+		return _ResourceInstanceChangeActionReason_name_3[_ResourceInstanceChangeActionReason_index_3[i]:_ResourceInstanceChangeActionReason_index_3[i+1]]
 	case i == 65:
 		return _ResourceInstanceChangeActionReason_name_4
 	case 67 <= i && i <= 70:
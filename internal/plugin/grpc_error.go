@@ -50,18 +50,24 @@ func grpcErr(err error) (diags tfdiags.Diagnostics) {
 	case codes.Unavailable:
 		// This case is when the plugin has stopped running for some reason,
 		// and is usually the result of a crash.
-		diags = diags.Append(tfdiags.WholeContainingBody(
+		diags = diags.Append(tfdiags.WithErrorCode(tfdiags.WholeContainingBody(
 			tfdiags.Error,
 			"Plugin did not respond",
 			fmt.Sprintf("The plugin encountered an error, and failed to respond to the %s call. "+
 				"The plugin logs may contain more details.", requestName),
-		))
+		), tfdiags.ErrorCodeProviderCrashed))
 	case codes.Canceled:
 		diags = diags.Append(tfdiags.WholeContainingBody(
 			tfdiags.Error,
 			"Request cancelled",
 			fmt.Sprintf("The %s request was cancelled.", requestName),
 		))
+	case codes.DeadlineExceeded:
+		diags = diags.Append(tfdiags.WholeContainingBody(
+			tfdiags.Error,
+			"Request timed out",
+			fmt.Sprintf("The %s request did not complete before its deadline.", requestName),
+		))
 	case codes.Unimplemented:
 		diags = diags.Append(tfdiags.WholeContainingBody(
 			tfdiags.Error,
@@ -7,13 +7,20 @@ package plugin
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/legacy/hcl2shim"
@@ -778,6 +785,88 @@ func TestGRPCProvider_ApplyResourceChange(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expectedPrivate, resp.Private)
 	}
 }
+
+// deadlineProviderServer is a provider server whose ApplyResourceChange
+// never completes on its own, so that it only ever returns once the request
+// context ends.
+type deadlineProviderServer struct {
+	proto.UnimplementedProviderServer
+
+	deadline chan bool
+}
+
+func (s *deadlineProviderServer) GetSchema(context.Context, *proto.GetProviderSchema_Request) (*proto.GetProviderSchema_Response, error) {
+	return providerProtoSchema(), nil
+}
+
+func (s *deadlineProviderServer) ApplyResourceChange(ctx context.Context, _ *proto.ApplyResourceChange_Request) (*proto.ApplyResourceChange_Response, error) {
+	_, ok := ctx.Deadline()
+	s.deadline <- ok
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestGRPCProvider_ApplyResourceChangeTimeout(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	provider := &deadlineProviderServer{deadline: make(chan bool, 1)}
+	proto.RegisterProviderServer(server, provider)
+	go server.Serve(listener) //nolint:errcheck // ends with server.Stop
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		"bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	p := &GRPCProvider{
+		client: proto.NewProviderClient(conn),
+	}
+
+	// The operation is cancelled before the lifecycle timeout expires,
+	// which must not end the request; only the timeout does.
+	opCtx, cancelOp := context.WithCancel(t.Context())
+	ctx, cancel := context.WithTimeout(opCtx, 200*time.Millisecond)
+	defer cancel()
+	go func() {
+		if <-provider.deadline {
+			cancelOp()
+		} else {
+			t.Error("request reached the plugin without a deadline")
+		}
+	}()
+
+	resp := p.ApplyResourceChange(ctx, providers.ApplyResourceChangeRequest{
+		TypeName: "resource",
+		PriorState: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("foo"),
+		}),
+		PlannedState: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+	})
+	checkDiagsHasError(t, resp.Diagnostics)
+
+	if deadline, _ := ctx.Deadline(); time.Now().Before(deadline) {
+		t.Error("request ended before its deadline")
+	}
+	for _, diag := range resp.Diagnostics {
+		if got, want := diag.Description().Summary, "Request timed out"; got != want {
+			t.Errorf("wrong diagnostic summary %q; want %q", got, want)
+		}
+	}
+}
+
 func TestGRPCProvider_ApplyResourceChangeJSON(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -0,0 +1,46 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// Test that we can convert function specs to protobuf types and back again.
+func TestConvertFunctionSpec(t *testing.T) {
+	spec := providers.FunctionSpec{
+		Parameters: []providers.FunctionParameterSpec{
+			{
+				Name:              "input",
+				Type:              cty.List(cty.String),
+				AllowNullValue:    true,
+				Description:       "The *input* list.",
+				DescriptionFormat: providers.TextFormattingMarkdown,
+			},
+		},
+		VariadicParameter: &providers.FunctionParameterSpec{
+			Name:               "extra",
+			Type:               cty.DynamicPseudoType,
+			AllowUnknownValues: true,
+			DescriptionFormat:  providers.TextFormattingPlain,
+		},
+		Return:             cty.Map(cty.Number),
+		Summary:            "Does a thing",
+		Description:        "Does a thing with the input.",
+		DescriptionFormat:  providers.TextFormattingPlain,
+		DeprecationMessage: "Use the other thing instead.",
+	}
+
+	got := ProtoToFunctionSpec(FunctionSpecToProto(spec))
+	if diff := cmp.Diff(spec, got, typeComparer); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
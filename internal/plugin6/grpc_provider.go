@@ -41,6 +41,11 @@ var clientCapabilities = &proto6.ClientCapabilities{
 	// for there to be a "deferred" object in the response from various
 	// other provider RPC functions.
 	DeferralAllowed: true,
+
+	// WriteOnlyAttributesAllowed tells the provider that we support
+	// write-only attributes, whose values we send to the provider but
+	// never persist in the plan or state.
+	WriteOnlyAttributesAllowed: true,
 }
 
 func (p *GRPCProviderPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
@@ -561,7 +566,17 @@ func (p *GRPCProvider) ApplyResourceChange(ctx context.Context, r providers.Appl
 	// signal, because we ask a provider plugin to gracefully cancel by
 	// calling the Stop method and then its apply operation must be allowed
 	// to run to completion to terminate gracefully if possible.
+	//
+	// A deadline is different: it's only set for the timeouts in the
+	// resource's lifecycle block, and ends just this one request rather than
+	// stopping everything the provider is doing.
+	deadline, hasDeadline := ctx.Deadline()
 	ctx = context.WithoutCancel(ctx)
+	if hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
 
 	resSchema, ok := schema.ResourceTypes[r.TypeName]
 	if !ok {
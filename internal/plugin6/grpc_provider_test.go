@@ -7,14 +7,17 @@ package plugin6
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/zclconf/go-cty/cty"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/legacy/hcl2shim"
@@ -784,6 +787,51 @@ func TestGRPCProvider_ApplyResourceChange(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expectedPrivate, resp.Private)
 	}
 }
+
+func TestGRPCProvider_ApplyResourceChangeDeadline(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(t.Context(), deadline)
+
+	client.EXPECT().ApplyResourceChange(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, _ *proto.ApplyResourceChange_Request, _ ...grpc.CallOption) (*proto.ApplyResourceChange_Response, error) {
+		// Canceling the operation must not end the request, but the
+		// deadline of a lifecycle timeout must.
+		cancel()
+		if err := ctx.Err(); err != nil {
+			t.Errorf("request context ended with the operation: %s", err)
+		}
+		if got, ok := ctx.Deadline(); !ok || !got.Equal(deadline) {
+			t.Errorf("wrong deadline %s (%t); want %s", got, ok, deadline)
+		}
+		return &proto.ApplyResourceChange_Response{
+			NewState: &proto.DynamicValue{
+				Msgpack: []byte("\x81\xa4attr\xa3bar"),
+			},
+		}, nil
+	})
+
+	resp := p.ApplyResourceChange(ctx, providers.ApplyResourceChangeRequest{
+		TypeName: "resource",
+		PriorState: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("foo"),
+		}),
+		PlannedState: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+	})
+	checkDiags(t, resp.Diagnostics)
+}
+
 func TestGRPCProvider_ApplyResourceChangeJSON(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -8,10 +8,14 @@ package providercache
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 	otelAttr "go.opentelemetry.io/otel/attribute"
@@ -64,6 +68,10 @@ type Installer struct {
 	// lifecycle for, and therefore does not need to worry about the
 	// installation of.
 	unmanagedProviderTypes map[addrs.Provider]struct{}
+
+	// concurrency is the maximum number of provider packages to install at
+	// the same time. Zero is treated the same as one.
+	concurrency int
 }
 
 // NewInstaller constructs and returns a new installer with the given target
@@ -167,6 +175,16 @@ func (i *Installer) SetUnmanagedProviderTypes(types map[addrs.Provider]struct{})
 	i.unmanagedProviderTypes = types
 }
 
+// SetConcurrency sets the maximum number of provider packages the receiver
+// will install at the same time. Installer events are still delivered one at
+// a time, but the order of events for different providers is unspecified.
+//
+// The default, if this method isn't called, is to install one provider
+// package at a time.
+func (i *Installer) SetConcurrency(n int) {
+	i.concurrency = n
+}
+
 // EnsureProviderVersions compares the given provider requirements with what
 // is already available in the installer's target directory and then takes
 // appropriate installation actions to ensure that suitable packages
@@ -227,7 +245,7 @@ func (i *Installer) EnsureProviderVersions(ctx context.Context, locks *depsfile.
 	// in the set of acceptable versions.
 	//
 	// This produces a set of packages to install to our cache in the next step.
-	need, err := i.ensureProviderVersionsNeed(ctx, locks, reqs, mightNeed, locked, errs)
+	need, err := i.ensureProviderVersionsNeed(ctx, locks, reqs, mightNeed, locked, nil, errs)
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +285,51 @@ func (i *Installer) EnsureProviderVersions(ctx context.Context, locks *depsfile.
 	return locks, nil
 }
 
+// ProviderVersionSelection describes the version of a provider that
+// [Installer.SelectProviderVersions] would select for installation.
+type ProviderVersionSelection struct {
+	// Selected is the version that would be installed.
+	Selected getproviders.Version
+
+	// Newest is the newest release available from the installer's source,
+	// regardless of the version constraints.
+	Newest getproviders.Version
+}
+
+// SelectProviderVersions is like [Installer.EnsureProviderVersions] except
+// that it only determines which version of each provider it would install,
+// without installing anything or changing the given locks.
+//
+// The result includes only the providers that EnsureProviderVersions would
+// consult the installer's source about, so it excludes built-in and unmanaged
+// providers. Events are reported through any InstallerEvents value in the
+// given context in the same way as for EnsureProviderVersions, up to and
+// including the QueryPackages... events.
+func (i *Installer) SelectProviderVersions(ctx context.Context, locks *depsfile.Locks, reqs getproviders.Requirements, mode InstallMode) (map[addrs.Provider]ProviderVersionSelection, error) {
+	errs := map[addrs.Provider]error{}
+	newest := map[addrs.Provider]getproviders.Version{}
+
+	mightNeed, locked := i.ensureProviderVersionsMightNeed(ctx, locks, reqs, mode, errs)
+	need, err := i.ensureProviderVersionsNeed(ctx, locks, reqs, mightNeed, locked, newest, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[addrs.Provider]ProviderVersionSelection, len(need))
+	for provider, version := range need {
+		ret[provider] = ProviderVersionSelection{
+			Selected: version,
+			Newest:   newest[provider],
+		}
+	}
+	if len(errs) > 0 {
+		return ret, InstallerError{
+			ProviderErrors: errs,
+		}
+	}
+	return ret, nil
+}
+
 func (i *Installer) ensureProviderVersionsMightNeed(
 	ctx context.Context,
 	locks *depsfile.Locks,
@@ -364,6 +427,7 @@ func (i *Installer) ensureProviderVersionsNeed(
 	reqs getproviders.Requirements,
 	mightNeed map[addrs.Provider]getproviders.VersionSet,
 	locked map[addrs.Provider]bool,
+	newest map[addrs.Provider]getproviders.Version,
 	errs map[addrs.Provider]error,
 ) (map[addrs.Provider]getproviders.Version, error) {
 	evts := installerEventsForContext(ctx)
@@ -403,7 +467,10 @@ NeedProvider:
 				cb(provider, warnings)
 			}
 		}
-		available.Sort()                           // put the versions in increasing order of precedence
+		available.Sort() // put the versions in increasing order of precedence
+		if newest != nil {
+			newest[provider] = available.NewestInSet(versions.Released)
+		}
 		for i := len(available) - 1; i >= 0; i-- { // walk backwards to consider newer versions first
 			if acceptableVersions.Has(available[i]) {
 				need[provider] = available[i]
@@ -446,39 +513,64 @@ func (i *Installer) ensureProviderVersionsInstall(
 ) (map[addrs.Provider]*getproviders.PackageAuthenticationResult, error) {
 	authResults := map[addrs.Provider]*getproviders.PackageAuthenticationResult{} // record auth results for all successfully fetched providers
 
+	// Each provider is installed in its own goroutine, with at most
+	// i.concurrency of them running at once. The goroutines hold mu whenever
+	// they work with the shared locks and results or emit events, and release
+	// it only while waiting for slow operations such as package downloads.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(i.concurrency, 1))
 	for provider, version := range need {
-		traceCtx, span := tracing.Tracer().Start(ctx,
-			fmt.Sprintf("Install Provider %q", provider.String()),
-			trace.WithAttributes(
-				otelAttr.String(traceattrs.ProviderAddress, provider.String()),
-				otelAttr.String(traceattrs.ProviderVersion, version.String()),
-				otelAttr.String(traceattrs.TargetPlatform, targetPlatform.String()),
-			),
-		)
-
-		if err := traceCtx.Err(); err != nil {
+		sem <- struct{}{}
+		if err := ctx.Err(); err != nil {
 			// If our context has been cancelled or reached a timeout then
 			// we'll abort early, because subsequent operations against
 			// that context will fail immediately anyway.
-			tracing.SetSpanError(span, err)
-			span.End()
+			wg.Wait()
 			return nil, err
 		}
 
-		authResult, err := i.ensureProviderVersionInstalled(traceCtx, locks, reqs, mode, provider, version, targetPlatform)
-		if authResult != nil {
-			authResults[provider] = authResult
-		}
-		if err != nil {
-			errs[provider] = err
-		}
-		span.End()
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			traceCtx, span := tracing.Tracer().Start(ctx,
+				fmt.Sprintf("Install Provider %q", provider.String()),
+				trace.WithAttributes(
+					otelAttr.String(traceattrs.ProviderAddress, provider.String()),
+					otelAttr.String(traceattrs.ProviderVersion, version.String()),
+					otelAttr.String(traceattrs.TargetPlatform, targetPlatform.String()),
+				),
+			)
+			defer span.End()
+
+			mu.Lock()
+			defer mu.Unlock()
+			authResult, err := i.ensureProviderVersionInstalled(traceCtx, &mu, locks, reqs, mode, provider, version, targetPlatform)
+			if authResult != nil {
+				authResults[provider] = authResult
+			}
+			if err != nil {
+				errs[provider] = err
+			}
+		}()
 	}
+	wg.Wait()
 	return authResults, nil
 }
 
+// withoutLock calls fn with the given mutex, which the caller must hold,
+// temporarily released.
+func withoutLock(mu *sync.Mutex, fn func()) {
+	mu.Unlock()
+	defer mu.Lock()
+	fn()
+}
+
 func (i *Installer) ensureProviderVersionInstalled(
 	ctx context.Context,
+	mu *sync.Mutex,
 	locks *depsfile.Locks,
 	reqs getproviders.Requirements,
 	mode InstallMode,
@@ -519,7 +611,7 @@ func (i *Installer) ensureProviderVersionInstalled(
 		linkTo = nil // no linking needed
 	}
 
-	result, err := i.ensureProviderVersionInDirectory(ctx, locks, reqs, mode, provider, version, targetPlatform, installTo)
+	result, err := i.ensureProviderVersionInDirectory(ctx, mu, locks, reqs, mode, provider, version, targetPlatform, installTo)
 
 	if err != nil {
 		return result, err
@@ -533,7 +625,10 @@ func (i *Installer) ensureProviderVersionInstalled(
 		// We don't do a hash check here because we already did that
 		// as part of the ensureProviderVersionInDirectory call above.
 		new := installTo.ProviderVersion(provider, version)
-		err := linkTo.LinkFromOtherCache(ctx, new, nil)
+		var err error
+		withoutLock(mu, func() {
+			err = linkTo.LinkFromOtherCache(ctx, new, nil)
+		})
 		if err != nil {
 			if cb := evts.LinkFromCacheFailure; cb != nil {
 				cb(provider, version, err)
@@ -571,6 +666,7 @@ func (i *Installer) ensureProviderVersionInstalled(
 
 func (i *Installer) ensureProviderVersionInDirectory(
 	ctx context.Context,
+	mu *sync.Mutex,
 	locks *depsfile.Locks,
 	reqs getproviders.Requirements,
 	mode InstallMode,
@@ -613,7 +709,11 @@ func (i *Installer) ensureProviderVersionInDirectory(
 	if cb := evts.FetchPackageMeta; cb != nil {
 		cb(provider, version)
 	}
-	meta, err := i.source.PackageMeta(ctx, provider, version, targetPlatform)
+	var meta getproviders.PackageMeta
+	var err error
+	withoutLock(mu, func() {
+		meta, err = i.source.PackageMeta(ctx, provider, version, targetPlatform)
+	})
 	if err != nil {
 		if cb := evts.FetchPackageFailure; cb != nil {
 			cb(provider, version, err)
@@ -634,7 +734,12 @@ func (i *Installer) ensureProviderVersionInDirectory(
 	}
 
 	allowSkippingInstallWithoutHashes := i.globalCacheDirMayBreakDependencyLockFile && isGlobalCache
-	authResult, err := installTo.InstallPackage(ctx, meta, allowedHashes, allowSkippingInstallWithoutHashes)
+	var authResult *getproviders.PackageAuthenticationResult
+	started := time.Now()
+	withoutLock(mu, func() {
+		authResult, err = installTo.InstallPackage(ctx, meta, allowedHashes, allowSkippingInstallWithoutHashes)
+	})
+	elapsed := time.Since(started)
 	if err != nil {
 		// TODO: Consider retrying for certain kinds of error that seem
 		// likely to be transient. For now, we just treat all errors equally.
@@ -659,6 +764,9 @@ func (i *Installer) ensureProviderVersionInDirectory(
 		}
 		return nil, err
 	}
+	if cb := evts.FetchPackageDownloaded; cb != nil {
+		cb(provider, version, packageDirSize(new.PackageDir), elapsed)
+	}
 
 	// The InstallPackage call above should've verified that
 	// the package matches one of the hashes previously recorded,
@@ -751,6 +859,24 @@ func (i *Installer) ensureProviderVersionInDirectory(
 	return authResult, nil
 }
 
+// packageDirSize returns the total size in bytes of the files in the given
+// package directory, or zero if it can't be determined.
+func packageDirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // we're only reporting on a best-effort basis
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
 // checkUnspecifiedVersion Check the presence of version 0.0.0 and return an error with a tip
 func checkUnspecifiedVersion(acceptableVersions versions.Set) error {
 	if !acceptableVersions.Exactly(versions.Unspecified) {
@@ -7,6 +7,7 @@ package providercache
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getproviders"
@@ -107,10 +108,14 @@ type InstallerEvents struct {
 	//
 	// The Query, Begin, Success, and Failure events will each occur only once
 	// per distinct provider.
-	FetchPackageMeta    func(provider addrs.Provider, version getproviders.Version) // fetching metadata prior to real download
-	FetchPackageBegin   func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, inProviderCache bool)
-	FetchPackageSuccess func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult)
-	FetchPackageFailure func(provider addrs.Provider, version getproviders.Version, err error)
+	//
+	// The Downloaded event occurs just before Success, reporting the size of
+	// the unpacked package in bytes and how long it took to retrieve it.
+	FetchPackageMeta       func(provider addrs.Provider, version getproviders.Version) // fetching metadata prior to real download
+	FetchPackageBegin      func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, inProviderCache bool)
+	FetchPackageDownloaded func(provider addrs.Provider, version getproviders.Version, size int64, elapsed time.Duration)
+	FetchPackageSuccess    func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult)
+	FetchPackageFailure    func(provider addrs.Provider, version getproviders.Version, err error)
 
 	// The ProvidersLockUpdated event is called whenever the lock file will be
 	// updated. It provides the following information:
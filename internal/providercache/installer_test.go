@@ -15,8 +15,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/apparentlymart/go-versions/versions/constraints"
@@ -2615,6 +2617,74 @@ func TestEnsureProviderVersions_local_source(t *testing.T) {
 	}
 }
 
+func TestEnsureProviderVersions_concurrent(t *testing.T) {
+	source := getproviders.NewFilesystemMirrorSource(t.Context(), "testdata/cachedir")
+	platform := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	dir := NewDirWithPlatform(t.TempDir(), platform)
+	installer := NewInstaller(dir, source)
+	installer.SetConcurrency(4)
+
+	nullProvider := addrs.MustParseProviderSourceString("hashicorp/null")
+	randomProvider := addrs.MustParseProviderSourceString("hashicorp/random")
+	randomBetaProvider := addrs.MustParseProviderSourceString("hashicorp/random-beta")
+	missingProvider := addrs.MustParseProviderSourceString("missing/executable")
+	reqs := getproviders.Requirements{
+		nullProvider:       getproviders.MustParseVersionConstraints("2.0.0"),
+		randomProvider:     getproviders.MustParseVersionConstraints("1.2.0"),
+		randomBetaProvider: getproviders.MustParseVersionConstraints("1.2.0"),
+		missingProvider:    getproviders.MustParseVersionConstraints("2.0.0"),
+	}
+
+	// The installer never calls events concurrently, so the race detector
+	// will catch it if these maps are updated from more than one goroutine
+	// at once.
+	downloaded := map[addrs.Provider]bool{}
+	failed := map[addrs.Provider]bool{}
+	ctx := (&InstallerEvents{
+		FetchPackageDownloaded: func(provider addrs.Provider, version getproviders.Version, size int64, elapsed time.Duration) {
+			downloaded[provider] = true
+		},
+		FetchPackageFailure: func(provider addrs.Provider, version getproviders.Version, err error) {
+			failed[provider] = true
+		},
+	}).OnContext(t.Context())
+
+	newLocks, err := installer.EnsureProviderVersions(ctx, depsfile.NewLocks(), reqs, InstallNewProvidersOnly)
+	installerErr, ok := err.(InstallerError)
+	if !ok {
+		t.Fatalf("wrong error type %T; want InstallerError", err)
+	}
+	if _, ok := installerErr.ProviderErrors[missingProvider]; !ok || len(installerErr.ProviderErrors) != 1 {
+		t.Errorf("wrong provider errors: %s", err)
+	}
+
+	var gotLocked []string
+	for provider := range newLocks.AllProviders() {
+		gotLocked = append(gotLocked, provider.String())
+	}
+	sort.Strings(gotLocked)
+	wantLocked := []string{
+		nullProvider.String(),
+		randomProvider.String(),
+		randomBetaProvider.String(),
+	}
+	if diff := cmp.Diff(wantLocked, gotLocked); diff != "" {
+		t.Errorf("wrong locked providers\n%s", diff)
+	}
+
+	wantDownloaded := map[addrs.Provider]bool{
+		nullProvider:       true,
+		randomProvider:     true,
+		randomBetaProvider: true,
+	}
+	if diff := cmp.Diff(wantDownloaded, downloaded); diff != "" {
+		t.Errorf("wrong downloaded providers\n%s", diff)
+	}
+	if diff := cmp.Diff(map[addrs.Provider]bool{missingProvider: true}, failed); diff != "" {
+		t.Errorf("wrong failed providers\n%s", diff)
+	}
+}
+
 // This test only verifies protocol errors and does not try for successful
 // installation (at the time of writing, the test files aren't signed so the
 // signature verification fails); that's left to the e2e tests.
@@ -2944,3 +3014,58 @@ func tmpDir(t *testing.T) string {
 	}
 	return filepath.Clean(unlinked)
 }
+
+func TestSelectProviderVersions(t *testing.T) {
+	source := getproviders.NewFilesystemMirrorSource(t.Context(), "testdata/cachedir")
+	platform := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	nullProvider := addrs.MustParseProviderSourceString("hashicorp/null")
+
+	tests := map[string]struct {
+		constraints  string
+		wantSelected string
+	}{
+		"upgrade allowed": {
+			constraints:  ">= 2.0.0",
+			wantSelected: "2.1.0",
+		},
+		"upgrade excluded by constraint": {
+			constraints:  "2.0.0",
+			wantSelected: "2.0.0",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := NewDirWithPlatform(t.TempDir(), platform)
+			installer := NewInstaller(dir, source)
+
+			locks := depsfile.NewLocks()
+			locks.SetProvider(nullProvider, getproviders.MustParseVersion("2.0.0"), getproviders.MustParseVersionConstraints(">= 2.0.0"), nil)
+			reqs := getproviders.Requirements{
+				nullProvider: getproviders.MustParseVersionConstraints(test.constraints),
+			}
+
+			got, err := installer.SelectProviderVersions(t.Context(), locks, reqs, InstallUpgrades)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want := map[addrs.Provider]ProviderVersionSelection{
+				nullProvider: {
+					Selected: getproviders.MustParseVersion(test.wantSelected),
+					Newest:   getproviders.MustParseVersion("2.1.0"),
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+
+			// Selecting versions must neither install anything nor change
+			// the given locks.
+			if got := locks.Provider(nullProvider).Version(); got != getproviders.MustParseVersion("2.0.0") {
+				t.Errorf("lock was changed to %s", got)
+			}
+			if all := dir.AllAvailablePackages(); len(all) != 0 {
+				t.Errorf("unexpected cached packages %#v", all)
+			}
+		})
+	}
+}
@@ -106,6 +106,10 @@ type Configured interface {
 	// disconnected from the incoming cancellation chain. The caller doesn't
 	// do this automatically to give implementations flexibility to use a
 	// mixture of both cancelable and non-cancelable requests.
+	//
+	// The context has a deadline only when the resource's lifecycle block
+	// sets a timeout for the change, and implementations should end the
+	// request when it passes, without affecting any other requests.
 	ApplyResourceChange(context.Context, ApplyResourceChangeRequest) ApplyResourceChangeResponse
 
 	// ImportResourceState requests that the given resource be imported.
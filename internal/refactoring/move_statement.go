@@ -7,6 +7,8 @@ package refactoring
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -64,6 +66,69 @@ func findMoveStatements(cfg *configs.Config, into []MoveStatement) []MoveStateme
 	return into
 }
 
+// ExpandMoveStatementWildcards returns the given statements with each one
+// whose endpoints use [*] wildcards replaced by a statement for each distinct
+// combination of instance keys that its "from" endpoint matches in the given
+// previous run state.
+//
+// The other functions in this package don't understand wildcards, so callers
+// must expand the statements from FindMoveStatements before using them.
+func ExpandMoveStatementWildcards(stmts []MoveStatement, prevRunState *states.State) []MoveStatement {
+	ret := make([]MoveStatement, 0, len(stmts))
+	for _, stmt := range stmts {
+		if !stmt.From.HasWildcards() {
+			// The configs package ensures that both endpoints have the same
+			// number of wildcards, so "to" can't have any either.
+			ret = append(ret, stmt)
+			continue
+		}
+
+		keySets := make(map[string][]addrs.InstanceKey)
+		addKeys := func(keys []addrs.InstanceKey, match bool) {
+			if !match {
+				return
+			}
+			var buf strings.Builder
+			for _, key := range keys {
+				// NoKey has an empty string representation, so we add
+				// a separator to keep the combinations distinct.
+				buf.WriteString(key.String())
+				buf.WriteByte(',')
+			}
+			keySets[buf.String()] = keys
+		}
+		for _, ms := range prevRunState.Modules {
+			switch stmt.ObjectKind() {
+			case addrs.MoveEndpointModule:
+				addKeys(stmt.From.MatchWildcards(ms.Addr))
+			case addrs.MoveEndpointResource:
+				for _, rs := range ms.Resources {
+					for key := range rs.Instances {
+						addKeys(stmt.From.MatchWildcards(rs.Addr.Instance(key)))
+					}
+				}
+			}
+		}
+
+		// We sort the combinations only so that the result is deterministic.
+		ids := make([]string, 0, len(keySets))
+		for id := range keySets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			keys := keySets[id]
+			ret = append(ret, MoveStatement{
+				From:      stmt.From.WithWildcardKeys(keys),
+				To:        stmt.To.WithWildcardKeys(keys),
+				DeclRange: stmt.DeclRange,
+				Implied:   stmt.Implied,
+			})
+		}
+	}
+	return ret
+}
+
 // ImpliedMoveStatements compares addresses in the given state with addresses
 // in the given configuration and potentially returns additional MoveStatement
 // objects representing moves we infer automatically, even though they aren't
@@ -7,6 +7,7 @@ package states
 
 import (
 	"sort"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -50,6 +51,12 @@ type ResourceInstanceObject struct {
 	// destroy operations, we need to record the status to ensure a resource
 	// removed from the config will still be destroyed in the same manner.
 	CreateBeforeDestroy bool
+
+	// ApplyDuration is how long the provider took to apply the most recent
+	// change to this object, truncated to whole seconds, or zero if that
+	// isn't known. It's used only to estimate how long future changes to
+	// the same object or to similar objects might take.
+	ApplyDuration time.Duration
 }
 
 // ObjectStatus represents the status of a RemoteObject.
@@ -148,6 +155,7 @@ func (o *ResourceInstanceObject) Encode(ty cty.Type, schemaVersion uint64) (*Res
 		Status:                  o.Status,
 		Dependencies:            dependencies,
 		CreateBeforeDestroy:     o.CreateBeforeDestroy,
+		ApplyDuration:           o.ApplyDuration,
 	}, nil
 }
 
@@ -8,6 +8,7 @@ package states
 import (
 	"bytes"
 	"reflect"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -72,6 +73,7 @@ type ResourceInstanceObjectSrc struct {
 	Status              ObjectStatus
 	Dependencies        []addrs.ConfigResource
 	CreateBeforeDestroy bool
+	ApplyDuration       time.Duration
 }
 
 // Compare two lists using an given element equal function, ignoring order and duplicates
@@ -161,6 +163,10 @@ func (os *ResourceInstanceObjectSrc) Equal(other *ResourceInstanceObjectSrc) boo
 		return false
 	}
 
+	if os.ApplyDuration != other.ApplyDuration {
+		return false
+	}
+
 	return true
 }
 
@@ -211,6 +217,7 @@ func (os *ResourceInstanceObjectSrc) Decode(ty cty.Type) (*ResourceInstanceObjec
 		Dependencies:        os.Dependencies,
 		Private:             os.Private,
 		CreateBeforeDestroy: os.CreateBeforeDestroy,
+		ApplyDuration:       os.ApplyDuration,
 	}, nil
 }
 
@@ -43,6 +43,37 @@ type OptionalClientLocker interface {
 	IsLockingEnabled() bool
 }
 
+// ClientLockWaiter is an optional interface that allows a remote state
+// backend to record callers waiting for a lock held by another process.
+// See statemgr.LockWaiter for more details.
+type ClientLockWaiter interface {
+	ClientLocker
+	statemgr.LockWaiter
+}
+
+// ClientVersions is an optional interface that allows a remote state backend
+// whose storage retains earlier versions of the state to read them back.
+// See statemgr.StateVersionReader for more details.
+type ClientVersions interface {
+	Client
+
+	// StateVersions returns the identifiers of the retained versions of the
+	// state, newest first.
+	StateVersions(ctx context.Context) ([]string, error)
+
+	// GetVersion returns the version of the state with the given identifier,
+	// or nil if there's no such version.
+	GetVersion(ctx context.Context, id string) (*Payload, error)
+}
+
+// ClientLockInspector is an optional interface that allows a remote state
+// backend to report the lock currently held on a state without acquiring it.
+// See statemgr.LockInspector for more details.
+type ClientLockInspector interface {
+	ClientLocker
+	statemgr.LockInspector
+}
+
 // Payload is the return value from the remote state storage.
 type Payload struct {
 	MD5  []byte
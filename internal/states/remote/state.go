@@ -10,6 +10,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
 	"sync"
 
 	uuid "github.com/hashicorp/go-uuid"
@@ -57,6 +59,7 @@ type State struct {
 var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
 var _ statemgr.PersistentMeta = (*State)(nil)
+var _ statemgr.StateVersionReader = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
 
 func NewState(client Client, enc encryption.StateEncryption) *State {
@@ -285,6 +288,97 @@ func (s *State) Unlock(ctx context.Context, id string) error {
 	return nil
 }
 
+// CurrentLock calls the Client's CurrentLock method if it's implemented,
+// or returns statemgr.ErrLockInspectionUnsupported otherwise.
+func (s *State) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.Client.(ClientLockInspector); ok && !s.disableLocks {
+		return c.CurrentLock(ctx)
+	}
+	return nil, statemgr.ErrLockInspectionUnsupported
+}
+
+// AddLockWaiter calls the Client's AddLockWaiter method if it's implemented,
+// or returns statemgr.ErrLockWaitersUnsupported otherwise.
+func (s *State) AddLockWaiter(ctx context.Context, info *statemgr.LockInfo) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.Client.(ClientLockWaiter); ok && !s.disableLocks {
+		return c.AddLockWaiter(ctx, info)
+	}
+	return 0, statemgr.ErrLockWaitersUnsupported
+}
+
+// RemoveLockWaiter calls the Client's RemoveLockWaiter method if it's
+// implemented.
+func (s *State) RemoveLockWaiter(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.Client.(ClientLockWaiter); ok && !s.disableLocks {
+		return c.RemoveLockWaiter(ctx, id)
+	}
+	return nil
+}
+
+// ReadStateVersion calls the Client's version methods if they're implemented,
+// or returns statemgr.ErrStateVersionsUnsupported otherwise. A version that
+// isn't one of the Client's version identifiers is taken as a serial, and the
+// newest version with that serial is returned.
+func (s *State) ReadStateVersion(ctx context.Context, version string) (*statefile.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.Client.(ClientVersions)
+	if !ok {
+		return nil, statemgr.ErrStateVersionsUnsupported
+	}
+	ids, err := c.StateVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state versions: %w", err)
+	}
+
+	if slices.Contains(ids, version) {
+		file, err := s.readStateVersion(ctx, c, version)
+		if err == nil && file == nil {
+			err = fmt.Errorf("state version %s is empty", version)
+		}
+		return file, err
+	}
+	serial, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("there is no state version %q", version)
+	}
+	for _, id := range ids {
+		file, err := s.readStateVersion(ctx, c, id)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil && file.Serial == serial {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no state version with serial %d", serial)
+}
+
+func (s *State) readStateVersion(ctx context.Context, c ClientVersions, id string) (*statefile.File, error) {
+	payload, err := c.GetVersion(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state version %s: %w", id, err)
+	}
+	if payload == nil || len(payload.Data) == 0 {
+		return nil, nil
+	}
+	file, err := statefile.Read(bytes.NewReader(payload.Data), s.encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode state version %s: %w", id, err)
+	}
+	return file, nil
+}
+
 func (s *State) IsLockingEnabled() bool {
 	if s.disableLocks {
 		return false
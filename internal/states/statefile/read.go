@@ -83,7 +83,7 @@ func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
 
 	state, err := readState(decrypted)
 	if err != nil {
-		return nil, err
+		return nil, tfdiags.ErrorWithCode(err, tfdiags.ErrorCodeStateCorrupt)
 	}
 
 	if state == nil {
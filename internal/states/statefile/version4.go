@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/zclconf/go-cty/cty"
@@ -180,6 +181,7 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 			obj := &states.ResourceInstanceObjectSrc{
 				SchemaVersion:       isV4.SchemaVersion,
 				CreateBeforeDestroy: isV4.CreateBeforeDestroy,
+				ApplyDuration:       time.Duration(isV4.ApplyDurationSeconds) * time.Second,
 			}
 
 			{
@@ -581,6 +583,7 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		PrivateRaw:              privateRaw,
 		Dependencies:            deps,
 		CreateBeforeDestroy:     obj.CreateBeforeDestroy,
+		ApplyDurationSeconds:    uint64(obj.ApplyDuration / time.Second),
 	}), diags
 }
 
@@ -798,6 +801,8 @@ type instanceObjectStateV4 struct {
 	Dependencies []string `json:"dependencies,omitempty"`
 
 	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+
+	ApplyDurationSeconds uint64 `json:"apply_duration_seconds,omitempty"`
 }
 
 type checkResultsV4 struct {
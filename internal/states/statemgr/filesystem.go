@@ -14,6 +14,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -54,6 +56,9 @@ type Filesystem struct {
 	// implementation.
 	lockID string
 
+	// lockShared is set when the lock identified by lockID is a shared lock.
+	lockShared bool
+
 	// created is set to true if stateFileOut didn't exist before we created it.
 	// This is mostly so we can clean up empty files during tests, but doesn't
 	// hurt to remove file we never wrote to.
@@ -71,8 +76,16 @@ var (
 	_ Full           = (*Filesystem)(nil)
 	_ PersistentMeta = (*Filesystem)(nil)
 	_ Migrator       = (*Filesystem)(nil)
+	_ LockWaiter     = (*Filesystem)(nil)
 )
 
+// lockWaiterStaleAfter is how long a lock waiter file can go without being
+// refreshed before we assume that it was left behind by a process that
+// stopped waiting without removing it, such as one that crashed. Waiters
+// refresh their files each time they retry the lock, which LockWithProgress
+// does at least every 16 seconds.
+const lockWaiterStaleAfter = time.Minute
+
 // NewFilesystem creates a filesystem-based state manager that reads and writes
 // state snapshots at the given filesystem path.
 //
@@ -345,8 +358,27 @@ func (s *Filesystem) Lock(_ context.Context, info *LockInfo) (string, error) {
 		return "", fmt.Errorf("state %q already locked", s.stateFileOut.Name())
 	}
 
-	log.Printf("[TRACE] statemgr.Filesystem: locking %s", s.path)
-	if err := flock.Lock(s.stateFileOut); err != nil {
+	// Callers that are waiting for the lock take it in the order that they
+	// started waiting, so a caller that isn't at the front of the queue must
+	// keep waiting even if the lock is currently free.
+	if first := s.firstLockWaiter(); first != nil && first.ID != info.ID {
+		holder, err := s.lockInfo()
+		if err != nil {
+			holder = first
+		}
+		return "", &LockError{
+			Info: holder,
+			Err:  fmt.Errorf("state %q has other operations queued for its lock", s.readPath),
+		}
+	}
+
+	lock := flock.Lock
+	if info.Shared {
+		lock = flock.LockShared
+	}
+
+	log.Printf("[TRACE] statemgr.Filesystem: locking %s (shared: %t)", s.path, info.Shared)
+	if err := lock(s.stateFileOut); err != nil {
 		info, infoErr := s.lockInfo()
 		if infoErr != nil {
 			err = multierror.Append(err, infoErr)
@@ -361,9 +393,30 @@ func (s *Filesystem) Lock(_ context.Context, info *LockInfo) (string, error) {
 	}
 
 	s.lockID = info.ID
+	s.lockShared = info.Shared
+	s.removeStaleLockInfo(info.Shared)
 	return s.lockID, s.writeLockInfo(info)
 }
 
+// removeStaleLockInfo removes the lock info files of lock holders that can't
+// exist now that we hold a lock of the given kind, such as those left behind
+// by a plan that crashed without unlocking. Holding an exclusive lock means
+// that there are no shared lock holders, and holding a shared lock means that
+// there is no exclusive lock holder.
+func (s *Filesystem) removeStaleLockInfo(shared bool) {
+	stale := []string{s.lockInfoPath()}
+	if !shared {
+		stale, _ = filepath.Glob(s.sharedLockInfoPath("*"))
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err == nil {
+			log.Printf("[TRACE] statemgr.Filesystem: removed stale lock metadata file %s", path)
+		} else if !os.IsNotExist(err) {
+			log.Printf("[WARN] statemgr.Filesystem: error removing stale lock metadata file %q: %s", path, err)
+		}
+	}
+}
+
 // Unlock is the companion to Lock, completing the implementation of Locker.
 func (s *Filesystem) Unlock(_ context.Context, id string) error {
 	defer s.mutex()()
@@ -386,6 +439,9 @@ func (s *Filesystem) Unlock(_ context.Context, id string) error {
 	}
 
 	lockInfoPath := s.lockInfoPath()
+	if s.lockShared {
+		lockInfoPath = s.sharedLockInfoPath(s.lockID)
+	}
 	err := os.Remove(lockInfoPath)
 	if err != nil {
 		log.Printf(
@@ -404,6 +460,7 @@ func (s *Filesystem) Unlock(_ context.Context, id string) error {
 	s.stateFileOut.Close()
 	s.stateFileOut = nil
 	s.lockID = ""
+	s.lockShared = false
 
 	// clean up the state file if we created it an never wrote to it
 	stat, err := os.Stat(fileName)
@@ -417,6 +474,65 @@ func (s *Filesystem) Unlock(_ context.Context, id string) error {
 	return unlockErr
 }
 
+// CurrentLock implements LockInspector by reading the lock metadata file
+// written alongside the state by whichever process holds the lock.
+func (s *Filesystem) CurrentLock(_ context.Context) (*LockInfo, error) {
+	defer s.mutex()()
+
+	info, err := s.lockInfo()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// AddLockWaiter implements LockWaiter by writing a file for each waiter
+// alongside the state, so that the queue is shared by all of the processes
+// that use the same state file, even on different machines that share the
+// directory containing it.
+func (s *Filesystem) AddLockWaiter(_ context.Context, info *LockInfo) (int, error) {
+	defer s.mutex()()
+
+	path := s.lockWaiterPath(info.ID)
+	if _, err := os.Stat(path); err == nil {
+		// The waiter keeps its place in the queue, which is ordered by the
+		// time recorded in the file, so we only need to show that it's
+		// still waiting.
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return 0, fmt.Errorf("could not refresh lock waiter for %q: %w", s.readPath, err)
+		}
+	} else {
+		waiter := *info
+		waiter.Path = s.readPath
+		waiter.Created = time.Now().UTC()
+		log.Printf("[TRACE] statemgr.Filesystem: writing lock waiter to %s", path)
+		if err := os.WriteFile(path, waiter.Marshal(), 0600); err != nil {
+			return 0, fmt.Errorf("could not write lock waiter for %q: %w", s.readPath, err)
+		}
+	}
+
+	others := 0
+	for _, waiter := range s.lockWaiters() {
+		if waiter.ID != info.ID {
+			others++
+		}
+	}
+	return others, nil
+}
+
+// RemoveLockWaiter is the companion to AddLockWaiter, completing the
+// implementation of LockWaiter.
+func (s *Filesystem) RemoveLockWaiter(_ context.Context, id string) error {
+	defer s.mutex()()
+
+	err := os.Remove(s.lockWaiterPath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 // StateSnapshotMeta returns the metadata from the most recently persisted
 // or refreshed persistent state snapshot.
 //
@@ -531,10 +647,82 @@ func (s *Filesystem) lockInfoPath() string {
 	return filepath.Join(stateDir, fmt.Sprintf(".%s.lock.info", stateName))
 }
 
-// lockInfo returns the data in a lock info file
+// return the path for the lockInfo metadata of a shared lock with the given
+// id. Each holder of a shared lock has its own metadata file, since several
+// of them can hold the lock at once.
+func (s *Filesystem) sharedLockInfoPath(id string) string {
+	return strings.TrimSuffix(s.lockInfoPath(), ".info") + ".shared." + id + ".info"
+}
+
+// return the path for the file that records a caller waiting for the lock,
+// with the given lock id.
+func (s *Filesystem) lockWaiterPath(id string) string {
+	return strings.TrimSuffix(s.lockInfoPath(), ".info") + ".waiter." + id + ".info"
+}
+
+// lockWaiters returns the callers that are waiting for the lock, in the
+// order they started waiting. Waiter files that haven't been refreshed
+// recently are removed rather than returned, so that a process that crashed
+// while waiting doesn't hold up the queue forever.
+func (s *Filesystem) lockWaiters() []*LockInfo {
+	paths, _ := filepath.Glob(s.lockWaiterPath("*"))
+	var ret []*LockInfo
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if time.Since(stat.ModTime()) > lockWaiterStaleAfter {
+			log.Printf("[TRACE] statemgr.Filesystem: removing stale lock waiter %s", path)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("[WARN] statemgr.Filesystem: error removing stale lock waiter %q: %s", path, err)
+			}
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var info LockInfo
+		if err := json.Unmarshal(data, &info); err != nil || info.ID == "" {
+			log.Printf("[WARN] statemgr.Filesystem: ignoring invalid lock waiter %s", path)
+			continue
+		}
+		ret = append(ret, &info)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if !ret[i].Created.Equal(ret[j].Created) {
+			return ret[i].Created.Before(ret[j].Created)
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+// firstLockWaiter returns the caller at the front of the queue for the lock,
+// or nil if no callers are waiting.
+func (s *Filesystem) firstLockWaiter() *LockInfo {
+	waiters := s.lockWaiters()
+	if len(waiters) == 0 {
+		return nil
+	}
+	return waiters[0]
+}
+
+// lockInfo returns the data in a lock info file. If no exclusive lock is
+// recorded then it returns the data for one of the shared lock holders, if
+// any.
 func (s *Filesystem) lockInfo() (*LockInfo, error) {
 	path := s.lockInfoPath()
 	infoData, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		shared, _ := filepath.Glob(s.sharedLockInfoPath("*"))
+		if len(shared) > 0 {
+			sort.Strings(shared)
+			path = shared[0]
+			infoData, err = os.ReadFile(path)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -550,6 +738,9 @@ func (s *Filesystem) lockInfo() (*LockInfo, error) {
 // write a new lock info file
 func (s *Filesystem) writeLockInfo(info *LockInfo) error {
 	path := s.lockInfoPath()
+	if info.Shared {
+		path = s.sharedLockInfoPath(info.ID)
+	}
 	info.Path = s.readPath
 	info.Created = time.Now().UTC()
 
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	version "github.com/hashicorp/go-version"
@@ -110,6 +111,187 @@ func TestFilesystemLocks(t *testing.T) {
 	}
 }
 
+func TestFilesystemLocks_shared(t *testing.T) {
+	s := testFilesystem(t)
+	defer os.Remove(s.readPath)
+	defer func() {
+		// the helper process exits without unlocking, leaving its lock info
+		leftovers, _ := filepath.Glob(s.sharedLockInfoPath("*"))
+		for _, path := range leftovers {
+			os.Remove(path)
+		}
+	}()
+
+	info := NewLockInfo()
+	info.Operation = "plan"
+	info.Shared = true
+	lockID, err := s.Lock(t.Context(), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another shared lock can be taken alongside ours
+	out, err := exec.Command("go", "run", "testdata/lockstate.go", s.path, "shared").CombinedOutput()
+	if err != nil {
+		t.Fatal("unexpected lock failure", err, string(out))
+	}
+	if strings.Contains(string(out), "lock failed") {
+		t.Fatal("expected shared lock to succeed, got", string(out))
+	}
+
+	// but an exclusive lock cannot
+	out, err = exec.Command("go", "run", "testdata/lockstate.go", s.path).CombinedOutput()
+	if err != nil {
+		t.Fatal("unexpected lock failure", err, string(out))
+	}
+	if !strings.Contains(string(out), "lock failed") {
+		t.Fatal("expected 'locked failed', got", string(out))
+	}
+
+	// a shared holder is reported as the current lock holder
+	lockInfo, err := s.lockInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lockInfo.Shared {
+		t.Fatalf("invalid lock info %#v\n", lockInfo)
+	}
+
+	if err := s.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.sharedLockInfoPath(lockID)); !os.IsNotExist(err) {
+		t.Fatal("shared lock info not removed")
+	}
+}
+
+func TestFilesystemLocks_staleSharedInfo(t *testing.T) {
+	s := testFilesystem(t)
+	defer os.Remove(s.readPath)
+
+	// a plan that crashed while holding a shared lock leaves its lock info
+	// behind, even though the lock itself was released
+	stale := NewLockInfo()
+	stale.Operation = "plan"
+	stale.Shared = true
+	if err := os.WriteFile(s.sharedLockInfoPath(stale.ID), stale.Marshal(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lockID, err := s.Lock(t.Context(), NewLockInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.sharedLockInfoPath(stale.ID)); !os.IsNotExist(err) {
+		t.Fatal("stale shared lock info not removed")
+	}
+	lockInfo, err := s.lockInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockInfo.ID != lockID {
+		t.Fatalf("wrong lock holder %q; want %q", lockInfo.ID, lockID)
+	}
+
+	if err := s.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilesystemLocks_queue(t *testing.T) {
+	s := testFilesystem(t)
+	defer os.Remove(s.readPath)
+
+	first := NewLockInfo()
+	first.Operation = "apply"
+	second := NewLockInfo()
+	second.Operation = "apply"
+
+	n, err := s.AddLockWaiter(t.Context(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("wrong number of other waiters %d; want 0", n)
+	}
+	n, err = s.AddLockWaiter(t.Context(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("wrong number of other waiters %d; want 1", n)
+	}
+	// refreshing a waiter doesn't add it again
+	n, err = s.AddLockWaiter(t.Context(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("wrong number of other waiters %d; want 1", n)
+	}
+
+	// the second waiter can't jump the queue while the lock is free
+	_, err = s.Lock(t.Context(), second)
+	lockErr, ok := err.(*LockError)
+	if !ok {
+		t.Fatalf("expected a lock error, got %v", err)
+	}
+	if !lockErr.Retriable() || lockErr.Info.ID != first.ID {
+		t.Fatalf("wrong lock error %#v", lockErr)
+	}
+
+	lockID, err := s.Lock(t.Context(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveLockWaiter(t.Context(), first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+
+	lockID, err = s.Lock(t.Context(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveLockWaiter(t.Context(), second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilesystemLocks_queueStale(t *testing.T) {
+	s := testFilesystem(t)
+	defer os.Remove(s.readPath)
+
+	crashed := NewLockInfo()
+	if _, err := s.AddLockWaiter(t.Context(), crashed); err != nil {
+		t.Fatal(err)
+	}
+	path := s.lockWaiterPath(crashed.ID)
+	old := time.Now().Add(-2 * lockWaiterStaleAfter)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// a waiter that hasn't been refreshed for a while no longer holds up
+	// the queue
+	info := NewLockInfo()
+	lockID, err := s.Lock(t.Context(), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("stale lock waiter not removed")
+	}
+}
+
 // Verify that we can write to the state file, as Windows' mandatory locking
 // will prevent writing to a handle different than the one that hold the lock.
 func TestFilesystem_writeWhileLocked(t *testing.T) {
@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"strings"
+)
+
+// LockRunContext describes the CI/CD job or other automated run that took a
+// lock, when OpenTofu is able to detect one from well-known environment
+// variables.
+type LockRunContext struct {
+	// System is the name of the detected CI/CD system, such as
+	// "github-actions" or "gitlab-ci".
+	System string `json:"System,omitempty"`
+
+	// JobURL is a link to the job or build that is holding the lock.
+	JobURL string `json:"JobURL,omitempty"`
+
+	// PipelineID is the identifier of the pipeline, workflow or build run.
+	PipelineID string `json:"PipelineID,omitempty"`
+
+	// VCSRef is the branch, tag or other version control reference that
+	// the run was triggered for.
+	VCSRef string `json:"VCSRef,omitempty"`
+
+	// Actor is the user or service account that triggered the run.
+	Actor string `json:"Actor,omitempty"`
+}
+
+// lockRunDetector describes how to populate a LockRunContext from the
+// environment variables set by a particular CI/CD system.
+type lockRunDetector struct {
+	system string
+	// marker is an environment variable that is always set by the system.
+	marker     string
+	jobURL     func(getenv func(string) string) string
+	pipelineID []string
+	vcsRef     []string
+	actor      []string
+}
+
+var lockRunDetectors = []lockRunDetector{
+	{
+		system: "github-actions",
+		marker: "GITHUB_ACTIONS",
+		jobURL: func(getenv func(string) string) string {
+			server, repo, run := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID")
+			if server == "" || repo == "" || run == "" {
+				return ""
+			}
+			return strings.TrimSuffix(server, "/") + "/" + repo + "/actions/runs/" + run
+		},
+		pipelineID: []string{"GITHUB_RUN_ID"},
+		vcsRef:     []string{"GITHUB_HEAD_REF", "GITHUB_REF"},
+		actor:      []string{"GITHUB_TRIGGERING_ACTOR", "GITHUB_ACTOR"},
+	},
+	{
+		system:     "gitlab-ci",
+		marker:     "GITLAB_CI",
+		jobURL:     envURL("CI_JOB_URL"),
+		pipelineID: []string{"CI_PIPELINE_ID"},
+		vcsRef:     []string{"CI_COMMIT_REF_NAME"},
+		actor:      []string{"GITLAB_USER_LOGIN"},
+	},
+	{
+		system:     "buildkite",
+		marker:     "BUILDKITE",
+		jobURL:     envURL("BUILDKITE_BUILD_URL"),
+		pipelineID: []string{"BUILDKITE_BUILD_ID"},
+		vcsRef:     []string{"BUILDKITE_BRANCH"},
+		actor:      []string{"BUILDKITE_BUILD_CREATOR_EMAIL", "BUILDKITE_BUILD_CREATOR"},
+	},
+	{
+		system:     "circleci",
+		marker:     "CIRCLECI",
+		jobURL:     envURL("CIRCLE_BUILD_URL"),
+		pipelineID: []string{"CIRCLE_WORKFLOW_ID"},
+		vcsRef:     []string{"CIRCLE_BRANCH", "CIRCLE_TAG"},
+		actor:      []string{"CIRCLE_USERNAME"},
+	},
+	{
+		system: "azure-pipelines",
+		marker: "TF_BUILD",
+		jobURL: func(getenv func(string) string) string {
+			collection, project, build := getenv("SYSTEM_COLLECTIONURI"), getenv("SYSTEM_TEAMPROJECT"), getenv("BUILD_BUILDID")
+			if collection == "" || project == "" || build == "" {
+				return ""
+			}
+			return strings.TrimSuffix(collection, "/") + "/" + project + "/_build/results?buildId=" + build
+		},
+		pipelineID: []string{"BUILD_BUILDID"},
+		vcsRef:     []string{"BUILD_SOURCEBRANCH"},
+		actor:      []string{"BUILD_REQUESTEDFOREMAIL", "BUILD_REQUESTEDFOR"},
+	},
+	{
+		system:     "jenkins",
+		marker:     "JENKINS_URL",
+		jobURL:     envURL("BUILD_URL"),
+		pipelineID: []string{"BUILD_TAG", "BUILD_ID"},
+		vcsRef:     []string{"GIT_BRANCH", "BRANCH_NAME"},
+		actor:      []string{"BUILD_USER_ID"},
+	},
+}
+
+// envURL returns a jobURL function that reads the URL directly from the
+// given environment variable.
+func envURL(name string) func(getenv func(string) string) string {
+	return func(getenv func(string) string) string {
+		return getenv(name)
+	}
+}
+
+// firstEnv returns the value of the first of the given environment variables
+// that is set to a non-empty value.
+func firstEnv(getenv func(string) string, names []string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// detectLockRunContext returns the run context for the first CI/CD system
+// detected through getenv, or nil if OpenTofu doesn't seem to be running in
+// any of the systems it knows about.
+func detectLockRunContext(getenv func(string) string) *LockRunContext {
+	for _, d := range lockRunDetectors {
+		if getenv(d.marker) == "" {
+			continue
+		}
+		return &LockRunContext{
+			System:     d.system,
+			JobURL:     d.jobURL(getenv),
+			PipelineID: firstEnv(getenv, d.pipelineID),
+			VCSRef:     firstEnv(getenv, d.vcsRef),
+			Actor:      firstEnv(getenv, d.actor),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectLockRunContext(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want *LockRunContext
+	}{
+		"no ci": {
+			env:  map[string]string{},
+			want: nil,
+		},
+		"github actions": {
+			env: map[string]string{
+				"GITHUB_ACTIONS":    "true",
+				"GITHUB_SERVER_URL": "https://github.com/",
+				"GITHUB_REPOSITORY": "example/infra",
+				"GITHUB_RUN_ID":     "1234",
+				"GITHUB_REF":        "refs/heads/main",
+				"GITHUB_ACTOR":      "octocat",
+			},
+			want: &LockRunContext{
+				System:     "github-actions",
+				JobURL:     "https://github.com/example/infra/actions/runs/1234",
+				PipelineID: "1234",
+				VCSRef:     "refs/heads/main",
+				Actor:      "octocat",
+			},
+		},
+		"gitlab ci": {
+			env: map[string]string{
+				"GITLAB_CI":          "true",
+				"CI_JOB_URL":         "https://gitlab.example.com/infra/-/jobs/99",
+				"CI_PIPELINE_ID":     "42",
+				"CI_COMMIT_REF_NAME": "feature",
+				"GITLAB_USER_LOGIN":  "alex",
+			},
+			want: &LockRunContext{
+				System:     "gitlab-ci",
+				JobURL:     "https://gitlab.example.com/infra/-/jobs/99",
+				PipelineID: "42",
+				VCSRef:     "feature",
+				Actor:      "alex",
+			},
+		},
+		"partial information": {
+			env: map[string]string{
+				"CIRCLECI": "true",
+			},
+			want: &LockRunContext{
+				System: "circleci",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(k string) string { return test.env[k] }
+			got := detectLockRunContext(getenv)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLockInfoString_runContext(t *testing.T) {
+	info := NewLockInfo()
+	info.Run = &LockRunContext{
+		System: "gitlab-ci",
+		JobURL: "https://gitlab.example.com/infra/-/jobs/99",
+		Actor:  "alex",
+	}
+
+	got := info.String()
+	for _, want := range []string{
+		"CI System: gitlab-ci",
+		"CI Job:    https://gitlab.example.com/infra/-/jobs/99",
+		"Actor:     alex",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Pipeline:") {
+		t.Errorf("unexpected empty pipeline field in\n%s", got)
+	}
+
+	info.Run = nil
+	if got := info.String(); strings.Contains(got, "CI System") {
+		t.Errorf("unexpected run context for lock without one\n%s", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"math/rand"
 	"os"
 	"os/user"
@@ -50,6 +51,12 @@ var rngSource = rand.New(rand.NewSource(time.Now().UnixNano()))
 type Locker interface {
 	// Lock attempts to obtain a lock, using the given lock information.
 	//
+	// If info.Shared is set then the caller only intends to read the state,
+	// and implementations that are able to do so may grant a shared lock
+	// that can be held concurrently with other shared locks, but never with
+	// an exclusive lock. Implementations that do not support shared locks
+	// must treat a shared lock request as an exclusive one.
+	//
 	// The result is an opaque id that can be passed to Unlock to release
 	// the lock, or an error if the lock cannot be acquired. Lock returns
 	// an instance of LockError immediately if the lock is already held,
@@ -76,6 +83,62 @@ type OptionalLocker interface {
 	IsLockingEnabled() bool
 }
 
+// LockInspector is an optional interface for Locker implementations that are
+// able to report details of a lock currently held on the state without
+// trying to acquire it, such as for listing held locks.
+type LockInspector interface {
+	// CurrentLock returns information about the lock currently held on the
+	// state, or nil if the state is not currently locked.
+	CurrentLock(ctx context.Context) (*LockInfo, error)
+}
+
+// ErrLockInspectionUnsupported is returned by LockInspector implementations
+// that wrap another locker which turns out not to be able to report the
+// current lock.
+var ErrLockInspectionUnsupported = errors.New("the current lock cannot be inspected for this state storage")
+
+// LockWaiter is an optional interface for Locker implementations that are
+// able to record which callers are waiting for a lock that is currently held
+// by another process, so that each waiter can report how many others are
+// queued alongside it.
+type LockWaiter interface {
+	// AddLockWaiter records the caller described by info as waiting for the
+	// lock and returns the number of other callers currently waiting.
+	//
+	// Calling AddLockWaiter again with a LockInfo of the same ID refreshes
+	// the existing registration rather than adding a second one, so callers
+	// may call it repeatedly to obtain an up-to-date count.
+	AddLockWaiter(ctx context.Context, info *LockInfo) (int, error)
+
+	// RemoveLockWaiter removes a registration previously made with
+	// AddLockWaiter, identified by the ID of its LockInfo.
+	RemoveLockWaiter(ctx context.Context, id string) error
+}
+
+// ErrLockWaitersUnsupported is returned by LockWaiter implementations that
+// wrap another locker which turns out not to be able to record waiters.
+var ErrLockWaitersUnsupported = errors.New("lock waiters are not recorded by this state storage")
+
+// LockWaitStatus describes the progress of a caller that is waiting for a
+// lock currently held by another process.
+type LockWaitStatus struct {
+	// Holder is the lock information reported for the current lock holder.
+	Holder *LockInfo
+
+	// Attempt is the number of lock attempts made so far.
+	Attempt int
+
+	// Waited is how long the caller has been waiting so far.
+	Waited time.Duration
+
+	// NextAttempt is the delay before the lock will next be attempted.
+	NextAttempt time.Duration
+
+	// Waiters is the number of other callers waiting for the same lock, or
+	// -1 if the state manager is not able to record waiters.
+	Waiters int
+}
+
 // test hook to verify that LockWithContext has attempted a lock
 var postLockHook func()
 
@@ -85,13 +148,38 @@ var postLockHook func()
 // This method has a built-in retry/backoff behavior up to the context's
 // timeout.
 func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, error) {
+	return LockWithProgress(ctx, s, info, nil)
+}
+
+// LockWithProgress is like LockWithContext, but additionally calls the given
+// progress function each time it is about to wait before retrying, so that
+// the caller can report who is holding the lock.
+//
+// If s also implements LockWaiter then the caller is recorded as a waiter for
+// as long as it is retrying, and the number of other waiters is included in
+// the reported status. progress may be nil.
+func LockWithProgress(ctx context.Context, s Locker, info *LockInfo, progress func(LockWaitStatus)) (string, error) {
 	delay := time.Second
 	maxDelay := 16 * time.Second
+	start := time.Now()
+	attempt := 0
+
+	waiter, canWait := s.(LockWaiter)
+	waiting := false
+	defer func() {
+		if waiting {
+			if err := waiter.RemoveLockWaiter(context.WithoutCancel(ctx), info.ID); err != nil {
+				log.Printf("[WARN] failed to remove state lock waiter %s: %s", info.ID, err)
+			}
+		}
+	}()
+
 	for {
 		// We disable cancellation on the context passed to s.Lock
 		// because we want it to run to completion if possible and then
 		// we'll check context cancellation explicitly below.
 		id, err := s.Lock(context.WithoutCancel(ctx), info)
+		attempt++
 		if err == nil {
 			return id, nil
 		}
@@ -115,6 +203,31 @@ func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, err
 			continue
 		}
 
+		if ctx.Err() != nil {
+			return "", err
+		}
+
+		if progress != nil {
+			status := LockWaitStatus{
+				Holder:      le.Info,
+				Attempt:     attempt,
+				Waited:      time.Since(start),
+				NextAttempt: delay,
+				Waiters:     -1,
+			}
+			if canWait {
+				n, werr := waiter.AddLockWaiter(context.WithoutCancel(ctx), info)
+				switch {
+				case werr == nil:
+					waiting = true
+					status.Waiters = n
+				case !errors.Is(werr, ErrLockWaitersUnsupported):
+					log.Printf("[WARN] failed to record state lock waiter %s: %s", info.ID, werr)
+				}
+			}
+			progress(status)
+		}
+
 		// there's an existing lock, wait and try again
 		select {
 		case <-ctx.Done():
@@ -156,6 +269,15 @@ type LockInfo struct {
 
 	// Path to the state file when applicable. Set by the Lock implementation.
 	Path string `json:"Path"`
+
+	// Shared is set when the caller only needs a shared (read) lock, such
+	// as for creating a plan. See Locker.Lock for details.
+	Shared bool `json:"Shared,omitempty"`
+
+	// Run describes the CI/CD job that took the lock, when one could be
+	// detected from the environment. This is nil for locks taken outside
+	// of any recognized automation.
+	Run *LockRunContext `json:"Run,omitempty"`
 }
 
 // NewLockInfo creates a LockInfo object and populates many of its fields
@@ -185,6 +307,7 @@ func NewLockInfo() *LockInfo {
 		Who:     fmt.Sprintf("%s@%s", userName, host),
 		Version: version.Version,
 		Created: time.Now().UTC(),
+		Run:     detectLockRunContext(os.Getenv),
 	}
 	return info
 }
@@ -213,6 +336,24 @@ func (l *LockInfo) String() string {
   Version:   {{.Version}}
   Created:   {{.Created}}
   Info:      {{.Info}}
+{{- if .Shared}}
+  Mode:      shared
+{{- end}}
+{{- with .Run}}
+  CI System: {{.System}}
+{{- with .JobURL}}
+  CI Job:    {{.}}
+{{- end}}
+{{- with .PipelineID}}
+  Pipeline:  {{.}}
+{{- end}}
+{{- with .VCSRef}}
+  VCS Ref:   {{.}}
+{{- end}}
+{{- with .Actor}}
+  Actor:     {{.}}
+{{- end}}
+{{- end}}
 `
 
 	t := template.Must(template.New("LockInfo").Parse(tmpl))
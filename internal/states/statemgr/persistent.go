@@ -7,10 +7,12 @@ package statemgr
 
 import (
 	"context"
+	"errors"
 
 	version "github.com/hashicorp/go-version"
 
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
 
@@ -38,6 +40,21 @@ type OutputReader interface {
 	GetRootOutputValues(context.Context) (map[string]*states.OutputValue, error)
 }
 
+// StateVersionReader is an optional interface for persistent state managers
+// whose storage retains earlier snapshots of the state, allowing them to be
+// read back without changing the latest snapshot.
+type StateVersionReader interface {
+	// ReadStateVersion returns the earlier snapshot identified by version,
+	// which is either an identifier assigned by the storage, such as an
+	// object version ID, or the serial number of the snapshot.
+	ReadStateVersion(ctx context.Context, version string) (*statefile.File, error)
+}
+
+// ErrStateVersionsUnsupported is returned by StateVersionReader
+// implementations that wrap another state storage which turns out not to
+// retain earlier snapshots.
+var ErrStateVersionsUnsupported = errors.New("earlier versions of the state are not kept by this state storage")
+
 // Refresher is the interface for managers that can read snapshots from
 // persistent storage.
 //
@@ -94,6 +94,51 @@ func TestLockWithContext(t *testing.T) {
 	}
 }
 
+func TestLockWithProgress(t *testing.T) {
+	s := NewFullFake(nil, TestFullInitialState())
+
+	holder := NewLockInfo()
+	holder.Operation = "holder"
+	id, err := s.Lock(t.Context(), holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []LockWaitStatus
+	progress := func(status LockWaitStatus) {
+		statuses = append(statuses, status)
+		if err := s.Unlock(t.Context(), id); err != nil {
+			t.Error(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info := NewLockInfo()
+	info.Operation = "waiter"
+	if _, err := LockWithProgress(ctx, s, info, progress); err != nil {
+		t.Fatal("lock should have succeeded after the holder released it:", err)
+	}
+
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 progress report, got %d", len(statuses))
+	}
+	got := statuses[0]
+	if got.Attempt != 1 {
+		t.Errorf("wrong attempt %d; want 1", got.Attempt)
+	}
+	if got.Holder == nil {
+		t.Fatal("missing holder info")
+	}
+	if got.Waiters != -1 {
+		t.Errorf("wrong waiters %d; want -1 for a locker that does not record waiters", got.Waiters)
+	}
+	if got.NextAttempt != time.Second {
+		t.Errorf("wrong next attempt delay %s; want 1s", got.NextAttempt)
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	os.Exit(m.Run())
@@ -10,11 +10,12 @@ import (
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
-// Attempt to open and lock a tofu state file.
+// Attempt to open and lock a tofu state file, taking a shared lock if the
+// optional second argument is "shared".
 // Lock failure exits with 0 and writes "lock failed" to stderr.
 func main() {
-	if len(os.Args) != 2 {
-		log.Fatal(os.Args[0], "statefile")
+	if len(os.Args) != 2 && len(os.Args) != 3 {
+		log.Fatal(os.Args[0], "statefile [shared]")
 	}
 
 	s := statemgr.NewFilesystem(os.Args[1], encryption.StateEncryptionDisabled())
@@ -22,6 +23,7 @@ func main() {
 	info := statemgr.NewLockInfo()
 	info.Operation = "test"
 	info.Info = "state locker"
+	info.Shared = len(os.Args) == 3 && os.Args[2] == "shared"
 
 	_, err := s.Lock(context.Background(), info)
 	if err != nil {
@@ -33,6 +33,10 @@ func (e nativeError) FromExpr() *FromExpr {
 }
 
 func (e nativeError) ExtraInfo() interface{} {
-	// Native errors don't carry any "extra information".
+	// Native errors don't carry any "extra information" of their own, but
+	// they might wrap an error that has an error code.
+	if code := ErrorCodeOf(e.err); code != "" {
+		return &errorCodeExtra{code: code}
+	}
 	return nil
 }
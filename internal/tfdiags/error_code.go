@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+import (
+	"errors"
+)
+
+// ErrorCode is a stable identifier for a class of failure, which automation
+// can rely on instead of matching the text of an error message.
+//
+// Error codes are part of OpenTofu's public interface, through the "code"
+// property of diagnostics in the machine-readable UI and through the exit
+// codes of commands, so existing codes must never be changed or reused.
+type ErrorCode string
+
+const (
+	// ErrorCodeStateLocked means that the state couldn't be locked because
+	// another operation holds the lock.
+	ErrorCodeStateLocked ErrorCode = "state_locked"
+
+	// ErrorCodeAuthFailed means that a remote system rejected OpenTofu's
+	// credentials, or required credentials that OpenTofu didn't have.
+	ErrorCodeAuthFailed ErrorCode = "auth_failed"
+
+	// ErrorCodeStateCorrupt means that a state snapshot exists but couldn't
+	// be decoded.
+	ErrorCodeStateCorrupt ErrorCode = "state_corrupt"
+
+	// ErrorCodeProviderCrashed means that a provider plugin stopped
+	// responding, which is usually the result of a crash.
+	ErrorCodeProviderCrashed ErrorCode = "provider_crashed"
+
+	// ErrorCodePlanStale means that a saved plan can no longer be applied,
+	// either because the state has changed since it was created or because
+	// it has expired.
+	ErrorCodePlanStale ErrorCode = "plan_stale"
+
+	// ErrorCodeApplyTimeout means that an apply was stopped early because
+	// it took longer than its -apply-timeout, and so some of the planned
+	// changes weren't applied.
+	ErrorCodeApplyTimeout ErrorCode = "apply_timeout"
+)
+
+// DiagnosticExtraErrorCode is an interface implemented by values in the
+// Extra field of Diagnostic when the diagnostic represents a failure with
+// a stable error code.
+type DiagnosticExtraErrorCode interface {
+	// DiagnosticErrorCode returns the error code of the associated
+	// diagnostic, or an empty string if it has none.
+	DiagnosticErrorCode() ErrorCode
+}
+
+// DiagnosticErrorCode returns the error code of the given diagnostic, or an
+// empty string if it has none.
+//
+// This is a wrapper around checking if the diagnostic's extra info implements
+// interface DiagnosticExtraErrorCode and then calling its method if so.
+func DiagnosticErrorCode(diag Diagnostic) ErrorCode {
+	maybe := ExtraInfo[DiagnosticExtraErrorCode](diag)
+	if maybe == nil {
+		return ""
+	}
+	return maybe.DiagnosticErrorCode()
+}
+
+// ErrorCode returns the error code of the first diagnostic with error
+// severity that has one, or an empty string if none of them do.
+func (diags Diagnostics) ErrorCode() ErrorCode {
+	for _, diag := range diags {
+		if diag.Severity() != Error {
+			continue
+		}
+		if code := DiagnosticErrorCode(diag); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// WithErrorCode returns a copy of the given diagnostic that has the given
+// error code, while preserving its severity and any other extra info.
+func WithErrorCode(diag Diagnostic, code ErrorCode) Diagnostic {
+	return Override(diag, diag.Severity(), func() DiagnosticExtraWrapper {
+		return &errorCodeExtra{code: code}
+	})
+}
+
+// ErrorWithCode wraps the given error so that the diagnostics created from
+// it have the given error code.
+//
+// If err was returned by Diagnostics.Err then the result is of the same kind,
+// so callers that unwrap the original diagnostics keep working.
+func ErrorWithCode(err error, code ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+	if diagsErr, ok := err.(diagnosticsAsError); ok {
+		var diags Diagnostics
+		for _, diag := range diagsErr.Diagnostics {
+			if diag.Severity() == Error {
+				diag = WithErrorCode(diag, code)
+			}
+			diags = append(diags, diag)
+		}
+		return diagnosticsAsError{diags}
+	}
+	return codedError{err: err, code: code}
+}
+
+// ErrorCodeOf returns the error code carried by the given error or by any
+// error it wraps, or an empty string if there is none.
+func ErrorCodeOf(err error) ErrorCode {
+	var coded codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var diagsErr diagnosticsAsError
+	if errors.As(err, &diagsErr) {
+		return diagsErr.Diagnostics.ErrorCode()
+	}
+	return ""
+}
+
+// codedError is the error type returned by ErrorWithCode for errors that
+// aren't already diagnostics.
+type codedError struct {
+	err  error
+	code ErrorCode
+}
+
+func (e codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e codedError) Unwrap() error {
+	return e.err
+}
+
+// errorCodeExtra is the extra info that WithErrorCode attaches to
+// diagnostics, wrapping any extra info they already had.
+type errorCodeExtra struct {
+	code    ErrorCode
+	wrapped interface{}
+}
+
+var _ DiagnosticExtraErrorCode = (*errorCodeExtra)(nil)
+var _ DiagnosticExtraWrapper = (*errorCodeExtra)(nil)
+var _ DiagnosticExtraUnwrapper = (*errorCodeExtra)(nil)
+
+func (e *errorCodeExtra) DiagnosticErrorCode() ErrorCode {
+	return e.code
+}
+
+func (e *errorCodeExtra) WrapDiagnosticExtra(inner interface{}) {
+	e.wrapped = inner
+}
+
+func (e *errorCodeExtra) UnwrapDiagnosticExtra() interface{} {
+	return e.wrapped
+}
@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithErrorCode(t *testing.T) {
+	diag := WithErrorCode(Sourceless(Error, "Locked", "The state is locked."), ErrorCodeStateLocked)
+	if got, want := DiagnosticErrorCode(diag), ErrorCodeStateLocked; got != want {
+		t.Errorf("wrong error code %q; want %q", got, want)
+	}
+	if got, want := diag.Severity(), Error; got != want {
+		t.Errorf("wrong severity %s; want %s", got, want)
+	}
+	if got, want := diag.Description().Summary, "Locked"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+
+	// The error code must not hide any extra info the diagnostic already had.
+	diag = WithErrorCode(Override(Sourceless(Error, "Unknown", ""), Error, func() DiagnosticExtraWrapper {
+		return &testExtraBecauseUnknown{}
+	}), ErrorCodePlanStale)
+	if got, want := DiagnosticErrorCode(diag), ErrorCodePlanStale; got != want {
+		t.Errorf("wrong error code %q; want %q", got, want)
+	}
+	if !DiagnosticCausedByUnknown(diag) {
+		t.Errorf("lost the existing extra info")
+	}
+}
+
+func TestDiagnostics_ErrorCode(t *testing.T) {
+	var diags Diagnostics
+	diags = diags.Append(WithErrorCode(Sourceless(Warning, "Warning", ""), ErrorCodeAuthFailed))
+	diags = diags.Append(Sourceless(Error, "Uncoded", ""))
+	if got := diags.ErrorCode(); got != "" {
+		t.Errorf("warnings and uncoded errors have error code %q", got)
+	}
+
+	diags = diags.Append(WithErrorCode(Sourceless(Error, "Crashed", ""), ErrorCodeProviderCrashed))
+	diags = diags.Append(WithErrorCode(Sourceless(Error, "Locked", ""), ErrorCodeStateLocked))
+	if got, want := diags.ErrorCode(), ErrorCodeProviderCrashed; got != want {
+		t.Errorf("wrong error code %q; want %q", got, want)
+	}
+}
+
+func TestErrorWithCode(t *testing.T) {
+	t.Run("native error", func(t *testing.T) {
+		inner := errors.New("access denied")
+		err := fmt.Errorf("failed to lock: %w", ErrorWithCode(inner, ErrorCodeAuthFailed))
+		if got, want := err.Error(), "failed to lock: access denied"; got != want {
+			t.Errorf("wrong message %q; want %q", got, want)
+		}
+		if !errors.Is(err, inner) {
+			t.Errorf("original error is no longer wrapped")
+		}
+
+		var diags Diagnostics
+		diags = diags.Append(err)
+		if got, want := diags.ErrorCode(), ErrorCodeAuthFailed; got != want {
+			t.Errorf("wrong error code %q; want %q", got, want)
+		}
+	})
+	t.Run("diagnostics", func(t *testing.T) {
+		var diags Diagnostics
+		diags = diags.Append(Sourceless(Warning, "Old format", ""))
+		diags = diags.Append(Sourceless(Error, "Invalid state", ""))
+		err := ErrorWithCode(diags.Err(), ErrorCodeStateCorrupt)
+
+		// Appending the error must still produce the original diagnostics.
+		var got Diagnostics
+		got = got.Append(err)
+		if len(got) != 2 || got[1].Description().Summary != "Invalid state" {
+			t.Fatalf("wrong diagnostics: %#v", got)
+		}
+		if DiagnosticErrorCode(got[0]) != "" {
+			t.Errorf("warning has an error code")
+		}
+		if got, want := DiagnosticErrorCode(got[1]), ErrorCodeStateCorrupt; got != want {
+			t.Errorf("wrong error code %q; want %q", got, want)
+		}
+
+		// The error code survives the diagnostics being wrapped in another
+		// error, even though that reduces them to a native error.
+		got = Diagnostics{}.Append(fmt.Errorf("error loading state: %w", err))
+		if got, want := got.ErrorCode(), ErrorCodeStateCorrupt; got != want {
+			t.Errorf("wrong error code %q; want %q", got, want)
+		}
+	})
+	t.Run("nil", func(t *testing.T) {
+		if err := ErrorWithCode(nil, ErrorCodeAuthFailed); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+}
+
+type testExtraBecauseUnknown struct {
+	wrapped interface{}
+}
+
+func (e *testExtraBecauseUnknown) DiagnosticCausedByUnknown() bool {
+	return true
+}
+
+func (e *testExtraBecauseUnknown) WrapDiagnosticExtra(inner interface{}) {
+	e.wrapped = inner
+}
@@ -40,10 +40,24 @@ const (
 // ContextOpts are the user-configurable options to create a context with
 // NewContext.
 type ContextOpts struct {
-	Meta         *ContextMeta
-	Hooks        []Hook
-	Parallelism  int
-	Providers    map[addrs.Provider]providers.Factory
+	Meta        *ContextMeta
+	Hooks       []Hook
+	Parallelism int
+	Providers   map[addrs.Provider]providers.Factory
+
+	// ProviderParallelism optionally sets a lower limit on the number of
+	// concurrent operations for resources belonging to specific providers,
+	// within the overall limit set by Parallelism.
+	ProviderParallelism map[addrs.Provider]int
+
+	// RefreshParallelism, if nonzero, gives the resource instances of each
+	// provider configuration their own limit of this many concurrent
+	// operations during plan walks, where existing objects are refreshed,
+	// instead of sharing the limit set by Parallelism. This means that a
+	// slow provider can't hold all of the shared slots while the resources
+	// of other providers wait. Limits from ProviderParallelism still apply.
+	RefreshParallelism int
+
 	Provisioners map[string]provisioners.Factory
 	Encryption   encryption.Encryption
 
@@ -87,6 +101,10 @@ type Context struct {
 	uiInput UIInput
 
 	parallelSem         Semaphore
+	providerSems        map[addrs.Provider]Semaphore
+	refreshParallelism  int
+	refreshSemsLock     sync.Mutex
+	refreshSems         map[string]Semaphore
 	l                   sync.Mutex // Lock acquired during any task
 	providerInputConfig map[string]map[string]cty.Value
 	runCond             *sync.Cond
@@ -135,6 +153,29 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		par = 10
 	}
 
+	providerSems := make(map[addrs.Provider]Semaphore, len(opts.ProviderParallelism))
+	for provider, limit := range opts.ProviderParallelism {
+		if limit <= 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid parallelism value",
+				fmt.Sprintf("The parallelism for provider %s must be a positive value. Not %d.", provider, limit),
+			))
+			continue
+		}
+		providerSems[provider] = NewSemaphore(limit)
+	}
+	if opts.RefreshParallelism < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism value",
+			fmt.Sprintf("The refresh parallelism must be a positive value. Not %d.", opts.RefreshParallelism),
+		))
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	plugins := newContextPlugins(opts.Providers, opts.Provisioners)
 
 	log.Printf("[TRACE] tofu.NewContext: complete")
@@ -147,6 +188,9 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		plugins: plugins,
 
 		parallelSem:         NewSemaphore(par),
+		providerSems:        providerSems,
+		refreshParallelism:  opts.RefreshParallelism,
+		refreshSems:         make(map[string]Semaphore),
 		providerInputConfig: make(map[string]map[string]cty.Value),
 		sh:                  sh,
 
@@ -403,8 +447,16 @@ func (c *Context) checkConfigDependencies(config *configs.Config) tfdiags.Diagno
 			if rc.Managed == nil {
 				continue // should not happen, but we'll be robust
 			}
+			provisionerTypes := make([]string, 0, len(rc.Managed.Provisioners)+1)
 			for _, pc := range rc.Managed.Provisioners {
-				if !c.plugins.HasProvisioner(pc.Type) {
+				provisionerTypes = append(provisionerTypes, pc.Type)
+			}
+			if len(rc.Managed.Hooks) != 0 {
+				// Lifecycle hooks are run by the builtin local-exec provisioner.
+				provisionerTypes = append(provisionerTypes, configs.ResourceHookProvisioner)
+			}
+			for _, typeName := range provisionerTypes {
+				if !c.plugins.HasProvisioner(typeName) {
 					// This is not a very high-quality error, because really
 					// the caller of tofu.NewContext should've already
 					// done equivalent checks when doing plugin discovery.
@@ -416,7 +468,7 @@ func (c *Context) checkConfigDependencies(config *configs.Config) tfdiags.Diagno
 						"Missing required provisioner plugin",
 						fmt.Sprintf(
 							"This configuration requires provisioner plugin %q, which isn't available. If you're intending to use an external provisioner plugin, you must install it manually into one of the plugin search directories before running OpenTofu.",
-							pc.Type,
+							typeName,
 						),
 					))
 				}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/perf"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -89,12 +90,15 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 
 	providerFunctionTracker := make(ProviderFunctionMapping)
 
+	endGraphBuild := perf.StartPhase(ctx, "apply graph build")
 	graph, operation, diags := c.applyGraph(ctx, plan, config, providerFunctionTracker)
+	endGraphBuild()
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
 	workingState := plan.PriorState.DeepCopy()
+	endGraphWalk := perf.StartPhase(ctx, "apply graph walk")
 	walker, walkDiags := c.walk(ctx, graph, operation, &graphWalkOpts{
 		Config:     config,
 		InputState: workingState,
@@ -106,9 +110,15 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 		PlanTimeCheckResults: plan.Checks,
 
 		// We also want to propagate the timestamp from the plan file.
-		PlanTimeTimestamp:       plan.Timestamp,
+		PlanTimeTimestamp: plan.Timestamp,
+
+		// The resources that were deferred during planning have no planned
+		// changes, and references to them must still be treated as unknown.
+		Deferrals: NewDeferrals(false, plan.DeferredResources),
+
 		ProviderFunctionTracker: providerFunctionTracker,
 	})
+	endGraphWalk()
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 
@@ -156,6 +166,48 @@ Note that the -target and -exclude options are not suitable for routine use, and
 }
 
 func (c *Context) applyGraph(ctx context.Context, plan *plans.Plan, config *configs.Config, providerFunctionTracker ProviderFunctionMapping) (*Graph, walkOperation, tfdiags.Diagnostics) {
+	variables, diags := planVariableValues(plan, config)
+	if diags.HasErrors() {
+		return nil, walkApply, diags
+	}
+
+	operation := walkApply
+	if plan.UIMode == plans.DestroyMode {
+		// FIXME: Due to differences in how objects must be handled in the
+		// graph and evaluated during a complete destroy, we must continue to
+		// use plans.DestroyMode to switch on this behavior. If all objects
+		// which require special destroy handling can be tracked in the plan,
+		// then this switch will no longer be needed and we can remove the
+		// walkDestroy operation mode.
+		// TODO: Audit that and remove walkDestroy as an operation mode.
+		operation = walkDestroy
+	}
+
+	graph, moreDiags := (&ApplyGraphBuilder{
+		Config:                  config,
+		Changes:                 plan.Changes,
+		State:                   plan.PriorState,
+		RootVariableValues:      variables,
+		Plugins:                 c.plugins,
+		Targets:                 plan.TargetAddrs,
+		Excludes:                plan.ExcludeAddrs,
+		ForceReplace:            plan.ForceReplaceAddrs,
+		Operation:               operation,
+		ExternalReferences:      plan.ExternalReferences,
+		ProviderFunctionTracker: providerFunctionTracker,
+	}).Build(ctx, addrs.RootModuleInstance)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, walkApply, diags
+	}
+
+	return graph, operation, diags
+}
+
+// planVariableValues returns the root module variable values recorded in the
+// given plan, with placeholders for any variables that were not set when
+// creating it.
+func planVariableValues(plan *plans.Plan, config *configs.Config) (InputValues, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	variables := InputValues{}
@@ -175,8 +227,24 @@ func (c *Context) applyGraph(ctx context.Context, plan *plans.Plan, config *conf
 			SourceType: ValueFromPlan,
 		}
 	}
+	for name, dyVal := range plan.EphemeralVariableValues {
+		val, err := dyVal.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid ephemeral variable value",
+				fmt.Sprintf("Invalid value for ephemeral variable %q: %s.", name, err),
+			))
+			continue
+		}
+
+		variables[name] = &InputValue{
+			Value:      val,
+			SourceType: ValueFromCaller,
+		}
+	}
 	if diags.HasErrors() {
-		return nil, walkApply, diags
+		return nil, diags
 	}
 
 	// The plan.VariableValues field only records variables that were actually
@@ -193,38 +261,7 @@ func (c *Context) applyGraph(ctx context.Context, plan *plans.Plan, config *conf
 			SourceType: ValueFromPlan,
 		}
 	}
-
-	operation := walkApply
-	if plan.UIMode == plans.DestroyMode {
-		// FIXME: Due to differences in how objects must be handled in the
-		// graph and evaluated during a complete destroy, we must continue to
-		// use plans.DestroyMode to switch on this behavior. If all objects
-		// which require special destroy handling can be tracked in the plan,
-		// then this switch will no longer be needed and we can remove the
-		// walkDestroy operation mode.
-		// TODO: Audit that and remove walkDestroy as an operation mode.
-		operation = walkDestroy
-	}
-
-	graph, moreDiags := (&ApplyGraphBuilder{
-		Config:                  config,
-		Changes:                 plan.Changes,
-		State:                   plan.PriorState,
-		RootVariableValues:      variables,
-		Plugins:                 c.plugins,
-		Targets:                 plan.TargetAddrs,
-		Excludes:                plan.ExcludeAddrs,
-		ForceReplace:            plan.ForceReplaceAddrs,
-		Operation:               operation,
-		ExternalReferences:      plan.ExternalReferences,
-		ProviderFunctionTracker: providerFunctionTracker,
-	}).Build(ctx, addrs.RootModuleInstance)
-	diags = diags.Append(moreDiags)
-	if moreDiags.HasErrors() {
-		return nil, walkApply, diags
-	}
-
-	return graph, operation, diags
+	return variables, diags
 }
 
 // ApplyGraphForUI is a last vestige of graphs in the public interface of
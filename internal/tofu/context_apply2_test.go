@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -28,6 +29,7 @@ import (
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -5603,3 +5605,1076 @@ check "http_check" {
 		t.Fatal(diags.Err())
 	}
 }
+
+func TestContext2Apply_providerParallelism(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_instance" "a" {
+  count = 4
+}
+
+resource "other_instance" "b" {
+  count = 4
+}
+`,
+	})
+
+	testP := testProvider("test")
+	testP.PlanResourceChangeFn = testDiffFn
+	testP.ApplyResourceChangeFn = testApplyFn
+	otherP := testProvider("other")
+	otherP.PlanResourceChangeFn = testDiffFn
+	otherP.ApplyResourceChangeFn = testApplyFn
+
+	// The mock providers serialize their own calls, so we measure the
+	// concurrency of each resource type from a hook instead.
+	hook := &concurrencyHook{active: map[string]int{}, highest: map[string]int{}}
+	ctx := testContext2(t, &ContextOpts{
+		Hooks:       []Hook{hook},
+		Parallelism: 10,
+		ProviderParallelism: map[addrs.Provider]int{
+			addrs.NewDefaultProvider("test"): 1,
+		},
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"):  testProviderFuncFixed(testP),
+			addrs.NewDefaultProvider("other"): testProviderFuncFixed(otherP),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	if got := hook.highest["test_instance"]; got != 1 {
+		t.Errorf("applied %d test_instance resources concurrently; want 1", got)
+	}
+	if got := hook.highest["other_instance"]; got < 2 {
+		t.Errorf("applied at most %d other_instance resources concurrently; want them not to be limited", got)
+	}
+}
+
+// concurrencyHook records the largest number of instances of each resource
+// type that were being applied at the same time.
+type concurrencyHook struct {
+	NilHook
+
+	mu      sync.Mutex
+	active  map[string]int
+	highest map[string]int
+}
+
+func (h *concurrencyHook) PreApply(addr addrs.AbsResourceInstance, _ states.Generation, _ plans.Action, _, _ cty.Value) (HookAction, error) {
+	h.mu.Lock()
+	h.active[addr.Resource.Resource.Type]++
+	h.highest[addr.Resource.Resource.Type] = max(h.highest[addr.Resource.Resource.Type], h.active[addr.Resource.Resource.Type])
+	h.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+	return HookActionContinue, nil
+}
+
+func (h *concurrencyHook) PostApply(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value, _ error) (HookAction, error) {
+	h.mu.Lock()
+	h.active[addr.Resource.Resource.Type]--
+	h.mu.Unlock()
+	return HookActionContinue, nil
+}
+
+func TestNewContext_invalidProviderParallelism(t *testing.T) {
+	_, diags := NewContext(&ContextOpts{
+		ProviderParallelism: map[addrs.Provider]int{
+			addrs.NewDefaultProvider("test"): 0,
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error for a non-positive provider parallelism")
+	}
+	if got, want := diags.Err().Error(), "The parallelism for provider registry.opentofu.org/hashicorp/test must be a positive value"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Apply_providerConfigFromUnknownResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "cloud_cluster" "main" {
+}
+
+provider "kube" {
+  host = cloud_cluster.main.endpoint
+}
+
+resource "kube_namespace" "app" {
+  name = "app"
+}
+
+data "kube_version" "current" {
+}
+
+output "version" {
+  value = data.kube_version.current.version
+}
+`,
+	})
+
+	cloudP := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"cloud_cluster": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"endpoint": {Type: cty.String, Computed: true},
+						},
+					},
+				},
+			},
+		},
+		ApplyResourceChangeFn: func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+			resp.NewState = cty.ObjectVal(map[string]cty.Value{
+				"endpoint": cty.StringVal("https://cluster.example.com"),
+			})
+			return resp
+		},
+	}
+
+	// The kube provider can't plan anything until it knows which host to
+	// talk to, like many real providers, so OpenTofu must not ask it to.
+	var host cty.Value
+	kubeP := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Provider: providers.Schema{
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"host": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+			ResourceTypes: map[string]providers.Schema{
+				"kube_namespace": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"name":  {Type: cty.String, Required: true},
+							"phase": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+			DataSources: map[string]providers.Schema{
+				"kube_version": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"version": {Type: cty.String, Computed: true},
+						},
+					},
+				},
+			},
+		},
+		ConfigureProviderFn: func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+			host = req.Config.GetAttr("host")
+			return resp
+		},
+		PlanResourceChangeFn: func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+			if !host.IsKnown() {
+				t.Errorf("PlanResourceChange called before the provider configuration is known")
+				resp.Diagnostics = resp.Diagnostics.Append(errors.New("host is not configured"))
+				return resp
+			}
+			// Like a legacy SDK default for an optional attribute.
+			resp.PlannedState = cty.ObjectVal(map[string]cty.Value{
+				"name":  req.ProposedNewState.GetAttr("name"),
+				"phase": cty.StringVal("Active"),
+			})
+			resp.LegacyTypeSystem = true
+			return resp
+		},
+		ReadDataSourceFn: func(req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
+			if !host.IsKnown() {
+				t.Errorf("ReadDataSource called before the provider configuration is known")
+				resp.Diagnostics = resp.Diagnostics.Append(errors.New("host is not configured"))
+				return resp
+			}
+			resp.State = cty.ObjectVal(map[string]cty.Value{
+				"version": cty.StringVal("1.30"),
+			})
+			return resp
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("cloud"): testProviderFuncFixed(cloudP),
+			addrs.NewDefaultProvider("kube"):  testProviderFuncFixed(kubeP),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+
+	nsAddr := mustResourceInstanceAddr("kube_namespace.app")
+	if change := plan.Changes.ResourceInstance(nsAddr); change == nil || change.Action != plans.Create {
+		t.Fatalf("expected a planned create for %s, got %#v", nsAddr, change)
+	}
+	dataAddr := mustResourceInstanceAddr("data.kube_version.current")
+	if change := plan.Changes.ResourceInstance(dataAddr); change == nil || change.Action != plans.Read || change.ActionReason != plans.ResourceInstanceReadBecauseConfigUnknown {
+		t.Fatalf("expected a deferred read for %s, got %#v", dataAddr, change)
+	}
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	ns := state.ResourceInstance(nsAddr)
+	if ns == nil || ns.Current == nil {
+		t.Fatalf("%s is missing from the state", nsAddr)
+	}
+	want := `{"name":"app","phase":"Active"}`
+	if got := string(ns.Current.AttrsJSON); got != want {
+		t.Errorf("wrong state for %s\ngot:  %s\nwant: %s", nsAddr, got, want)
+	}
+	if got, want := state.RootModule().OutputValues["version"].Value, cty.StringVal("1.30"); !got.RawEquals(want) {
+		t.Errorf("wrong version output %#v; want %#v", got, want)
+	}
+}
+
+func TestContext2Plan_providerConfigFromUnknownResourceNotDeferred(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "cloud_cluster" "main" {
+}
+
+provider "kube" {
+  host = cloud_cluster.main.endpoint
+}
+
+resource "kube_namespace" "app" {
+  name = "app"
+}
+`,
+	})
+
+	cloudP := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"cloud_cluster": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"endpoint": {Type: cty.String, Computed: true},
+						},
+					},
+				},
+			},
+		},
+	}
+	kubeP := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Provider: providers.Schema{
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"host": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+			ResourceTypes: map[string]providers.Schema{
+				"kube_namespace": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"name": {Type: cty.String, Required: true},
+						},
+					},
+				},
+			},
+		},
+		PlanResourceChangeFn: func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+			resp.Diagnostics = resp.Diagnostics.Append(errors.New("host is not configured"))
+			return resp
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("cloud"): testProviderFuncFixed(cloudP),
+			addrs.NewDefaultProvider("kube"):  testProviderFuncFixed(kubeP),
+		},
+	})
+
+	// Without -allow-deferral, the provider is still asked to plan, as it
+	// always was, and its errors are reported.
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want an error from the provider")
+	}
+	if got, want := diags.Err().Error(), "host is not configured"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+	if !kubeP.PlanResourceChangeCalled {
+		t.Error("PlanResourceChange wasn't called")
+	}
+}
+
+func TestContext2Apply_ephemeralWriteOnly(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "password" {
+  type      = string
+  ephemeral = true
+}
+
+module "child" {
+  source   = "./child"
+  password = var.password
+}
+
+resource "test_resource" "a" {
+  name     = "a"
+  password = module.child.password
+}
+`,
+		"child/main.tf": `
+variable "password" {
+  type      = string
+  ephemeral = true
+}
+
+output "password" {
+  value     = var.password
+  ephemeral = true
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_resource": {
+				Attributes: map[string]*configschema.Attribute{
+					"name": {
+						Type:     cty.String,
+						Required: true,
+					},
+					"password": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+		},
+	})
+	var appliedPassword cty.Value
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		appliedPassword = req.Config.GetAttr("password")
+		// The provider returns the write-only value in the new state, which
+		// OpenTofu must discard.
+		resp.NewState = req.Config
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"password": &InputValue{
+				Value:      cty.StringVal("hunter2"),
+				SourceType: ValueFromCLIArg,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	if _, ok := plan.VariableValues["password"]; ok {
+		t.Errorf("ephemeral variable value is recorded in the plan's variable values")
+	}
+	if _, ok := plan.EphemeralVariableValues["password"]; !ok {
+		t.Errorf("ephemeral variable value is missing from the plan's ephemeral variable values")
+	}
+	changeSrc := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_resource.a"))
+	if changeSrc == nil {
+		t.Fatalf("no change planned for test_resource.a")
+	}
+	change, err := changeSrc.Decode(p.GetProviderSchemaResponse.ResourceTypes["test_resource"].Block.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := change.After.GetAttr("password"); !got.IsNull() {
+		t.Errorf("write-only attribute is planned as %#v; want null", got)
+	}
+	if len(changeSrc.AfterValMarks) != 0 {
+		t.Errorf("unexpected marks in the planned value: %#v", changeSrc.AfterValMarks)
+	}
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	if want := cty.StringVal("hunter2"); !appliedPassword.RawEquals(want) {
+		t.Errorf("provider received password %#v during apply; want %#v", appliedPassword, want)
+	}
+	rs := state.ResourceInstance(mustResourceInstanceAddr("test_resource.a"))
+	if rs == nil || rs.Current == nil {
+		t.Fatalf("test_resource.a is missing from the state")
+	}
+	if bytes.Contains(rs.Current.AttrsJSON, []byte("hunter2")) {
+		t.Errorf("write-only attribute value was saved in the state: %s", rs.Current.AttrsJSON)
+	}
+}
+
+func TestContext2Plan_ephemeralInvalidUses(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"non-write-only resource argument": {
+			config: `
+resource "test_resource" "a" {
+  name = var.password
+}
+`,
+			wantErr: "Invalid use of ephemeral value",
+		},
+		"root module output": {
+			config: `
+output "password" {
+  value     = var.password
+  ephemeral = true
+}
+`,
+			wantErr: "Ephemeral output not allowed",
+		},
+		"non-ephemeral output": {
+			config: `
+module "child" {
+  source   = "./child"
+  password = var.password
+}
+`,
+			wantErr: "Output refers to ephemeral values",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testModuleInline(t, map[string]string{
+				"main.tf": `
+variable "password" {
+  type      = string
+  ephemeral = true
+}
+` + test.config,
+				"child/main.tf": `
+variable "password" {
+  type = string
+}
+
+output "password" {
+  value = var.password
+}
+`,
+			})
+
+			p := testProvider("test")
+			p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+				ResourceTypes: map[string]*configschema.Block{
+					"test_resource": {
+						Attributes: map[string]*configschema.Attribute{
+							"name": {
+								Type:     cty.String,
+								Required: true,
+							},
+						},
+					},
+				},
+			})
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"password": &InputValue{
+						Value:      cty.StringVal("hunter2"),
+						SourceType: ValueFromCLIArg,
+					},
+				},
+			})
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want an error")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestContext2Apply_deferUnknownExpansion(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_resource" "a" {
+  name = "a"
+}
+
+resource "test_resource" "b" {
+  for_each = toset([test_resource.a.id])
+  name     = each.key
+}
+
+resource "test_resource" "c" {
+  name = test_resource.b[test_resource.a.id].id
+}
+
+resource "test_resource" "d" {
+  count = 1
+  name  = "d"
+}
+
+output "b" {
+  value = test_resource.b
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_resource": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {
+						Type:     cty.String,
+						Computed: true,
+					},
+					"name": {
+						Type:     cty.String,
+						Required: true,
+					},
+				},
+			},
+		},
+	})
+	p.PlanResourceChangeFn = testDiffFn
+	p.ApplyResourceChangeFn = testApplyFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	// Without deferral, the unknown for_each is an error as usual.
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatalf("plan succeeded without deferral; want an error")
+	}
+	if got, want := diags.Err().Error(), "Invalid for_each argument"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+
+	wantDeferred := []addrs.AbsResource{
+		mustResourceInstanceAddr("test_resource.b").ContainingResource(),
+		mustResourceInstanceAddr("test_resource.c").ContainingResource(),
+	}
+	if diff := cmp.Diff(wantDeferred, plan.DeferredResources); diff != "" {
+		t.Errorf("wrong deferred resources\n%s", diff)
+	}
+	var gotWarning bool
+	for _, diag := range diags {
+		if diag.Severity() == tfdiags.Warning && diag.Description().Summary == "Some resources were deferred" {
+			gotWarning = true
+		}
+	}
+	if !gotWarning {
+		t.Errorf("missing warning about the deferred resources in %s", diags.ErrWithWarnings())
+	}
+
+	var gotChanges []string
+	for _, rc := range plan.Changes.Resources {
+		gotChanges = append(gotChanges, rc.Addr.String())
+	}
+	sort.Strings(gotChanges)
+	if diff := cmp.Diff([]string{"test_resource.a", "test_resource.d[0]"}, gotChanges); diff != "" {
+		t.Errorf("wrong planned changes\n%s", diff)
+	}
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if state.ResourceInstance(mustResourceInstanceAddr("test_resource.a")) == nil {
+		t.Errorf("test_resource.a wasn't created")
+	}
+	if _, ok := state.RootModule().OutputValues["b"]; ok {
+		t.Errorf("output b was set, but it refers to a deferred resource")
+	}
+
+	// The next round plans the resources that were deferred before.
+	plan, diags = ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+	if len(plan.DeferredResources) != 0 {
+		t.Errorf("unexpected deferred resources in the second plan: %s", plan.DeferredResources)
+	}
+	for _, addr := range []string{`test_resource.b["foo"]`, "test_resource.c"} {
+		change := plan.Changes.ResourceInstance(mustResourceInstanceAddr(addr))
+		if change == nil || change.Action != plans.Create {
+			t.Errorf("%s isn't planned for creation in the second plan", addr)
+		}
+	}
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+}
+
+func TestContext2Apply_dataDeferReadUntilApply(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+data "test_object" "a" {
+  test_string = "static"
+
+  lifecycle {
+    defer_read_until_apply = true
+  }
+}
+
+output "result" {
+  value = data.test_object.a.test_string
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		return providers.ReadDataSourceResponse{State: req.Config}
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	if p.ReadDataSourceCalled {
+		t.Fatal("ReadDataSource was called during planning")
+	}
+
+	addr := mustResourceInstanceAddr("data.test_object.a")
+	change := plan.Changes.ResourceInstance(addr)
+	if change == nil {
+		t.Fatalf("no change for %s", addr)
+	}
+	if got, want := change.Action, plans.Read; got != want {
+		t.Errorf("wrong action for %s\ngot:  %s\nwant: %s", addr, got, want)
+	}
+	if got, want := change.ActionReason, plans.ResourceInstanceReadBecauseDeferredByConfig; got != want {
+		t.Errorf("wrong action reason for %s\ngot:  %s\nwant: %s", addr, got, want)
+	}
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if !p.ReadDataSourceCalled {
+		t.Fatal("ReadDataSource was not called during apply")
+	}
+	if got, want := state.RootModule().OutputValues["result"].Value, cty.StringVal("static"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Apply_removedBlockDestroyProvisioner(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+removed {
+  from = test_object.a
+
+  lifecycle {
+    destroy = true
+  }
+
+  provisioner "shell" {
+    when    = destroy
+    command = "deregister ${self.test_string}"
+  }
+}
+`,
+	})
+
+	state := states.NewState()
+	state.EnsureModule(addrs.RootModuleInstance).SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.a").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"foo"}`),
+		},
+		mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		addrs.NoKey,
+	)
+
+	p := simpleMockProvider()
+	pr := testProvisioner()
+	var commands []string
+	pr.ProvisionResourceFn = func(req provisioners.ProvisionResourceRequest) (resp provisioners.ProvisionResourceResponse) {
+		commands = append(commands, req.Config.GetAttr("command").AsString())
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	if got, want := plan.Changes.ResourceInstance(addr).Action, plans.Delete; got != want {
+		t.Fatalf("wrong action for %s\ngot:  %s\nwant: %s", addr, got, want)
+	}
+
+	state, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if diff := cmp.Diff([]string{"deregister foo"}, commands); diff != "" {
+		t.Errorf("wrong provisioner commands\n%s", diff)
+	}
+	if !state.Empty() {
+		t.Errorf("resource was not destroyed\n%s", state)
+	}
+}
+
+func TestContext2Apply_resourceHooks(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+  lifecycle {
+    hook "post_create" {
+      command = "created ${self.test_string}"
+    }
+    hook "post_update" {
+      command = "updated ${self.test_string}"
+    }
+  }
+}
+
+resource "test_object" "b" {
+  test_string = "new"
+  lifecycle {
+    hook "post_create" {
+      command = "created ${self.test_string}"
+    }
+    hook "post_update" {
+      command = "updated ${self.test_string}"
+    }
+  }
+}
+
+resource "test_object" "c" {
+  count       = 1
+  test_string = "c"
+  lifecycle {
+    hook "pre_destroy" {
+      command = "destroying ${self.test_string} ${count.index}"
+    }
+  }
+}
+`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for addr, attrs := range map[string]string{
+			"test_object.b":    `{"test_string":"old"}`,
+			"test_object.c[0]": `{"test_string":"c"}`,
+			"test_object.c[1]": `{"test_string":"c"}`,
+		} {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(addr), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		}
+	})
+
+	p := simpleMockProvider()
+	pr := testProvisioner()
+	var mu sync.Mutex
+	var commands []string
+	pr.ProvisionResourceFn = func(req provisioners.ProvisionResourceRequest) (resp provisioners.ProvisionResourceResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, req.Config.GetAttr("command").AsString())
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"local-exec": testProvisionerFuncFixed(pr),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	if pr.ProvisionResourceCalled {
+		t.Fatalf("hook was run during plan")
+	}
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	// The hooks of different resources run concurrently.
+	sort.Strings(commands)
+	want := []string{
+		"created a",
+		"destroying c 1",
+		"updated new",
+	}
+	if diff := cmp.Diff(want, commands); diff != "" {
+		t.Errorf("wrong hook commands\n%s", diff)
+	}
+}
+
+func TestContext2Apply_resourceRetry(t *testing.T) {
+	baseDelay := resourceRetryBaseDelay
+	resourceRetryBaseDelay = 0
+	t.Cleanup(func() {
+		resourceRetryBaseDelay = baseDelay
+	})
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "throttled" {
+  test_string = "throttled"
+  lifecycle {
+    retry {
+      attempts  = 3
+      on_errors = ["(?i)throttl"]
+    }
+  }
+}
+
+resource "test_object" "denied" {
+  test_string = "denied"
+  lifecycle {
+    retry {
+      attempts  = 3
+      on_errors = ["(?i)throttl"]
+    }
+  }
+}
+
+resource "test_object" "partial" {
+  test_string = "partial"
+  lifecycle {
+    retry {
+      attempts = 3
+    }
+  }
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	var mu sync.Mutex
+	calls := map[string]int{}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := req.PlannedState.GetAttr("test_string").AsString()
+		calls[name]++
+
+		switch {
+		case name == "throttled" && calls[name] < 3:
+			resp.NewState = cty.NullVal(req.PlannedState.Type())
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("request was throttled"))
+		case name == "denied":
+			resp.NewState = cty.NullVal(req.PlannedState.Type())
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("access denied"))
+		case name == "partial":
+			// The object was created but not fully configured, so the
+			// change must not be retried.
+			resp.NewState = req.PlannedState
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("request was throttled"))
+		default:
+			resp.NewState = req.PlannedState
+		}
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	if !diags.HasErrors() {
+		t.Fatal("apply succeeded; want errors")
+	}
+
+	wantCalls := map[string]int{
+		"throttled": 3,
+		"denied":    1,
+		"partial":   1,
+	}
+	if diff := cmp.Diff(wantCalls, calls); diff != "" {
+		t.Errorf("wrong number of attempts\n%s", diff)
+	}
+
+	var gotDiags []string
+	for _, diag := range diags {
+		gotDiags = append(gotDiags, fmt.Sprintf("%s: %s", diag.Description().Summary, diag.Description().Detail))
+	}
+	sort.Strings(gotDiags)
+	wantDiags := []string{
+		"Resource operation retried: The provider failed to apply the creation of test_object.throttled 2 time(s), so OpenTofu retried it as configured in the resource's lifecycle block. The last retried error was: request was throttled",
+		"access denied: ",
+		"request was throttled: ",
+	}
+	if diff := cmp.Diff(wantDiags, gotDiags); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+
+	if state.ResourceInstance(mustResourceInstanceAddr("test_object.throttled")) == nil {
+		t.Errorf("test_object.throttled is not in the state")
+	}
+}
+
+func TestContext2Apply_resourceTimeout(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+  lifecycle {
+    timeouts {
+      create = "10ms"
+    }
+  }
+}
+`,
+	})
+
+	// The provider only returns once the context of the request ends.
+	p := &contextApplyProvider{
+		MockProvider: simpleMockProvider(),
+		applyFn: func(ctx context.Context, req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+			<-ctx.Done()
+			resp.NewState = cty.NullVal(req.PlannedState.Type())
+			resp.Diagnostics = resp.Diagnostics.Append(ctx.Err())
+			return resp
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	if p.StopCalled {
+		t.Errorf("provider was stopped; only the timed out request should be canceled")
+	}
+
+	var found bool
+	for _, diag := range diags {
+		desc := diag.Description()
+		if desc.Summary == "Resource operation timed out" {
+			found = true
+			if got, want := desc.Detail, "The provider did not finish the creation of test_object.a within the 10ms timeout set in the resource's lifecycle block, so OpenTofu canceled the request."; got != want {
+				t.Errorf("wrong detail\ngot:  %s\nwant: %s", got, want)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("missing timeout error; got: %s", diags.ErrWithWarnings())
+	}
+}
+
+// contextApplyProvider is a MockProvider whose applyFn can see the context
+// of each ApplyResourceChange request.
+type contextApplyProvider struct {
+	*MockProvider
+	applyFn func(context.Context, providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse
+}
+
+func (p *contextApplyProvider) ApplyResourceChange(ctx context.Context, req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	return p.applyFn(ctx, req)
+}
+
+func TestContext2Apply_largeIntegers(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  value = pow(2, 63)
+}
+`})
+
+	p := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"test_object": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"value": {
+								Type:     cty.Number,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	want := cty.MustParseNumberVal("9223372036854775808")
+	if got := p.ApplyResourceChangeRequest.Config.GetAttr("value"); !got.RawEquals(want) {
+		t.Errorf("wrong value sent to the provider\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	rs := state.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if rs == nil || rs.Current == nil {
+		t.Fatal("test_object.a is not in the state")
+	}
+	if got, want := string(rs.Current.AttrsJSON), `{"value":9223372036854775808}`; got != want {
+		t.Errorf("wrong state\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -656,6 +656,110 @@ check "error" {
 				},
 			},
 		},
+		"failing with error severity": {
+			configs: map[string]string{
+				"main.tf": `
+provider "checks" {}
+
+check "failing" {
+  severity = "error"
+
+  data "checks_object" "positive" {}
+
+  assert {
+    condition     = data.checks_object.positive.number >= 0
+    error_message = "negative number"
+  }
+}
+`,
+			},
+			plan: map[string]checksTestingStatus{
+				"failing": {
+					status:   checks.StatusFail,
+					messages: []string{"negative number"},
+				},
+			},
+			planWarning: "Check block assertion failed: negative number",
+			applyError:  "Check block assertion failed: negative number",
+			provider: &MockProvider{
+				Meta: "checks",
+				GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+					DataSources: map[string]providers.Schema{
+						"checks_object": {
+							Block: &configschema.Block{
+								Attributes: map[string]*configschema.Attribute{
+									"number": {
+										Type:     cty.Number,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				ReadDataSourceFn: func(request providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+					return providers.ReadDataSourceResponse{
+						State: cty.ObjectVal(map[string]cty.Value{
+							"number": cty.NumberIntVal(-1),
+						}),
+					}
+				},
+			},
+		},
+		"failing with info severity": {
+			configs: map[string]string{
+				"main.tf": `
+provider "checks" {}
+
+check "failing" {
+  severity = "info"
+
+  data "checks_object" "positive" {}
+
+  assert {
+    condition     = data.checks_object.positive.number >= 0
+    error_message = "negative number"
+  }
+}
+`,
+			},
+			plan: map[string]checksTestingStatus{
+				"failing": {
+					status:   checks.StatusFail,
+					messages: []string{"negative number"},
+				},
+			},
+			apply: map[string]checksTestingStatus{
+				"failing": {
+					status:   checks.StatusFail,
+					messages: []string{"negative number"},
+				},
+			},
+			provider: &MockProvider{
+				Meta: "checks",
+				GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+					DataSources: map[string]providers.Schema{
+						"checks_object": {
+							Block: &configschema.Block{
+								Attributes: map[string]*configschema.Attribute{
+									"number": {
+										Type:     cty.Number,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				ReadDataSourceFn: func(request providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+					return providers.ReadDataSourceResponse{
+						State: cty.ObjectVal(map[string]cty.Value{
+							"number": cty.NumberIntVal(-1),
+						}),
+					}
+				},
+			},
+		},
 		"invalid reference into check block": {
 			configs: map[string]string{
 				"main.tf": `
@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DestroyOrder returns the planned destroy actions in the given plan grouped
+// into waves in the order that applying the plan will perform them, as
+// derived from the apply graph.
+//
+// Every object in a wave depends only on the destruction of objects in
+// earlier waves, so all of the objects in the first wave can be destroyed
+// as soon as the apply begins and the objects in each later wave must wait
+// for at least one object in the wave before it. Within each wave the changes
+// are sorted by address. Other changes in the plan are not included, even
+// though destroy actions may also need to wait for them.
+//
+// The given configuration must be the same configuration that was passed
+// earlier to Context.Plan in order to create the plan.
+func (c *Context) DestroyOrder(plan *plans.Plan, config *configs.Config) ([][]*plans.ResourceInstanceChangeSrc, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	graph, _, moreDiags := c.applyGraph(context.TODO(), plan, config, make(ProviderFunctionMapping))
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	// The wave of a destroy node is the greatest number of other destroy
+	// nodes along any path of dependencies from it, which we calculate
+	// with a memoized depth-first walk.
+	waves := make(map[dag.Vertex]int)
+	var waveOf func(v dag.Vertex) int
+	waveOf = func(v dag.Vertex) int {
+		if wave, ok := waves[v]; ok {
+			return wave
+		}
+		wave := 0
+		for _, dep := range graph.DownEdges(v) {
+			depWave := waveOf(dep)
+			if destroyOrderChange(plan, dep) != nil {
+				depWave++
+			}
+			if depWave > wave {
+				wave = depWave
+			}
+		}
+		waves[v] = wave
+		return wave
+	}
+
+	var ret [][]*plans.ResourceInstanceChangeSrc
+	for _, v := range graph.Vertices() {
+		change := destroyOrderChange(plan, v)
+		if change == nil {
+			continue
+		}
+		wave := waveOf(v)
+		for len(ret) <= wave {
+			ret = append(ret, nil)
+		}
+		ret[wave] = append(ret[wave], change)
+	}
+
+	for _, changes := range ret {
+		sort.Slice(changes, func(i, j int) bool {
+			if !changes[i].Addr.Equal(changes[j].Addr) {
+				return changes[i].Addr.Less(changes[j].Addr)
+			}
+			return changes[i].DeposedKey < changes[j].DeposedKey
+		})
+	}
+	return ret, diags
+}
+
+// destroyOrderChange returns the planned change that the given apply graph
+// node will destroy an object for, or nil if it isn't a node that destroys a
+// managed resource instance object.
+func destroyOrderChange(plan *plans.Plan, v dag.Vertex) *plans.ResourceInstanceChangeSrc {
+	var addr addrs.AbsResourceInstance
+	var deposedKey states.DeposedKey
+	switch n := v.(type) {
+	case *NodeDestroyResourceInstance:
+		addr, deposedKey = n.ResourceInstanceAddr(), n.DeposedKey
+	case *NodeDestroyDeposedResourceInstanceObject:
+		addr, deposedKey = n.ResourceInstanceAddr(), n.DeposedKey
+	default:
+		return nil
+	}
+	if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+		return nil
+	}
+
+	if deposedKey != states.NotDeposed {
+		if change := plan.Changes.ResourceInstanceDeposed(addr, deposedKey); change != nil {
+			return change
+		}
+		// A create_before_destroy replacement destroys the object it
+		// deposes during the apply, whose key is allocated in advance, so
+		// the change is recorded against the current object instead.
+	}
+	return plan.Changes.ResourceInstance(addr)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestContext2DestroyOrder(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_instance" "a" {
+}
+
+resource "test_instance" "b" {
+	value = test_instance.a.id
+}
+
+resource "test_instance" "c" {
+	value = test_instance.b.id
+}
+
+resource "test_instance" "d" {
+	value = test_instance.a.id
+}
+
+resource "test_instance" "e" {
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.PlanResourceChangeFn = testDiffFn
+
+	provider := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	state := states.BuildState(func(s *states.SyncState) {
+		object := func(id string, deps ...string) *states.ResourceInstanceObjectSrc {
+			obj := &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"` + id + `"}`),
+				Status:    states.ObjectReady,
+			}
+			for _, dep := range deps {
+				obj.Dependencies = append(obj.Dependencies, mustConfigResourceAddr(dep))
+			}
+			return obj
+		}
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.a"), object("a"), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.b"), object("b", "test_instance.a"), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.c"), object("c", "test_instance.a", "test_instance.b"), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.d"), object("d", "test_instance.a"), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.e"), object("e"), provider, addrs.NoKey)
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.DestroyMode,
+	})
+	assertNoErrors(t, diags)
+
+	waves, diags := ctx.DestroyOrder(plan, m)
+	assertNoErrors(t, diags)
+
+	var got [][]string
+	for _, wave := range waves {
+		var names []string
+		for _, change := range wave {
+			if change.Action != plans.Delete {
+				t.Errorf("unexpected %s action for %s", change.Action, change.Addr)
+			}
+			names = append(names, change.Addr.String())
+		}
+		got = append(got, names)
+	}
+	want := [][]string{
+		{"test_instance.c", "test_instance.d", "test_instance.e"},
+		{"test_instance.b"},
+		{"test_instance.a"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong destroy order\n%s", diff)
+	}
+}
@@ -8,10 +8,12 @@ package tofu
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tracing"
@@ -19,6 +21,13 @@ import (
 
 type EvalOpts struct {
 	SetVariables InputValues
+
+	// Plan, if set, causes expressions to be evaluated against the planned
+	// new values of the resource instances it changes, rather than against
+	// their values in the given state. The given state should then be the
+	// prior state of the plan, and the variable values are taken from the
+	// plan instead of SetVariables.
+	Plan *plans.Plan
 }
 
 // Eval produces a scope in which expressions can be evaluated for
@@ -58,6 +67,19 @@ func (c *Context) Eval(ctx context.Context, config *configs.Config, state *state
 	var walker *ContextGraphWalker
 
 	variables := opts.SetVariables
+	var changes *plans.Changes
+	var planTimestamp time.Time
+	if opts.Plan != nil {
+		var moreDiags tfdiags.Diagnostics
+		variables, moreDiags = planVariableValues(opts.Plan, config)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		markPlannedObjects(state, opts.Plan.Changes)
+		changes = opts.Plan.Changes
+		planTimestamp = opts.Plan.Timestamp
+	}
 
 	// By the time we get here, we should have values defined for all of
 	// the root module variables, even if some of them are "unknown". It's the
@@ -87,7 +109,9 @@ func (c *Context) Eval(ctx context.Context, config *configs.Config, state *state
 
 	walkOpts := &graphWalkOpts{
 		InputState:              state,
+		Changes:                 changes,
 		Config:                  config,
+		PlanTimeTimestamp:       planTimestamp,
 		ProviderFunctionTracker: providerFunctionTracker,
 	}
 
@@ -110,3 +134,39 @@ func (c *Context) Eval(ctx context.Context, config *configs.Config, state *state
 	evalCtx := walker.EnterPath(moduleAddr)
 	return evalCtx.EvaluationScope(nil, nil, EvalDataForNoInstanceKey), diags
 }
+
+// markPlannedObjects updates the given state so that the current objects of
+// the resource instances created or updated by the given changes have the
+// status states.ObjectPlanned, which makes the evaluator use their planned
+// new values from the changes instead of their values in the state.
+//
+// Instances that the changes delete need no update, because the evaluator
+// already ignores them when it finds their delete action in the changes.
+func markPlannedObjects(state *states.State, changes *plans.Changes) {
+	if changes == nil {
+		return
+	}
+	ss := state.SyncWrapper()
+	for _, rc := range changes.Resources {
+		if rc.DeposedKey != states.NotDeposed {
+			continue
+		}
+		switch rc.Action {
+		case plans.Create, plans.Read, plans.Update, plans.DeleteThenCreate, plans.CreateThenDelete:
+		default:
+			continue
+		}
+		obj := &states.ResourceInstanceObjectSrc{
+			Status: states.ObjectPlanned,
+		}
+		providerKey := addrs.InstanceKey(addrs.NoKey)
+		if is := state.ResourceInstance(rc.Addr); is != nil {
+			if is.Current != nil {
+				obj = is.Current.DeepCopy()
+				obj.Status = states.ObjectPlanned
+			}
+			providerKey = is.ProviderKey
+		}
+		ss.SetResourceInstanceCurrent(rc.Addr, obj, rc.ProviderAddr, providerKey)
+	}
+}
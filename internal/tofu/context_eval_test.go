@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/zclconf/go-cty/cty"
@@ -134,3 +135,77 @@ output "out" {
 	})
 	assertNoErrors(t, diags)
 }
+
+func TestContextEval_plan(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "v" {
+	type = string
+}
+
+resource "test_instance" "a" {
+	value = var.v
+}
+
+resource "test_instance" "b" {
+}
+
+locals {
+	result = test_instance.a.value
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.PlanResourceChangeFn = testDiffFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_instance.a"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"a","value":"old"}`),
+				Status:    states.ObjectReady,
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+			addrs.NoKey,
+		)
+	})
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"v": &InputValue{
+				Value:      cty.StringVal("new"),
+				SourceType: ValueFromCLIArg,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	scope, diags := ctx.Eval(context.Background(), m, plan.PriorState, addrs.RootModuleInstance, &EvalOpts{
+		Plan: plan,
+	})
+	assertNoErrors(t, diags)
+
+	tests := map[string]cty.Value{
+		`var.v`:                 cty.StringVal("new"),
+		`test_instance.a.value`: cty.StringVal("new"),
+		`test_instance.a.id`:    cty.StringVal("a"),
+		`local.result`:          cty.StringVal("new"),
+		`test_instance.b.id`:    cty.UnknownVal(cty.String),
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			expr, _ := hclsyntax.ParseExpression([]byte(input), "<test-input>", hcl.Pos{Line: 1, Column: 1})
+			got, diags := scope.EvalExpr(t.Context(), expr, cty.DynamicPseudoType)
+			assertNoErrors(t, diags)
+			if !got.RawEquals(want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -131,3 +133,111 @@ func providerFunctionParameter(spec providers.FunctionParameterSpec) function.Pa
 		AllowMarked: false,
 	}
 }
+
+// providerFunctionResults memoizes the results of provider function calls
+// during a graph walk.
+//
+// Provider functions must always return the same result for the same
+// arguments, so a configuration that calls a function many times with the
+// same arguments only needs to wait for one round-trip to the provider.
+// Calls with different arguments are not serialized, so they can still run
+// concurrently from different graph nodes.
+//
+// A nil *providerFunctionResults is valid and doesn't memoize anything.
+type providerFunctionResults struct {
+	mu        sync.Mutex
+	results   map[string]*providerFunctionResult
+	functions map[string]*providerFunctionResult
+}
+
+// providerFunctionResult is a memoized response from a provider, which is
+// ready once done is closed.
+type providerFunctionResult struct {
+	done      chan struct{}
+	call      providers.CallFunctionResponse
+	functions providers.GetFunctionsResponse
+}
+
+func newProviderFunctionResults() *providerFunctionResults {
+	return &providerFunctionResults{
+		results:   make(map[string]*providerFunctionResult),
+		functions: make(map[string]*providerFunctionResult),
+	}
+}
+
+// Provider returns a wrapper for the given provider instance that memoizes
+// the results of its functions. The key must uniquely identify the provider
+// instance within the graph walk.
+func (r *providerFunctionResults) Provider(key string, provider providers.Interface) providers.Interface {
+	if r == nil {
+		return provider
+	}
+	return memoizedFunctionsProvider{
+		Interface: provider,
+		key:       key,
+		results:   r,
+	}
+}
+
+// get returns the memoized result for the given key from the given map, and
+// whether the caller is responsible for producing it. If so then the caller
+// must populate the result and then close its done channel, and otherwise
+// the caller must wait for done before using the result.
+func (r *providerFunctionResults) get(m map[string]*providerFunctionResult, key string) (*providerFunctionResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if result, exists := m[key]; exists {
+		return result, false
+	}
+	result := &providerFunctionResult{done: make(chan struct{})}
+	m[key] = result
+	return result, true
+}
+
+// memoizedFunctionsProvider is a provider instance whose function calls are
+// memoized in a providerFunctionResults object.
+type memoizedFunctionsProvider struct {
+	providers.Interface
+
+	key     string
+	results *providerFunctionResults
+}
+
+func (p memoizedFunctionsProvider) GetFunctions(ctx context.Context) providers.GetFunctionsResponse {
+	result, produce := p.results.get(p.results.functions, p.key)
+	if produce {
+		result.functions = p.Interface.GetFunctions(ctx)
+		close(result.done)
+	}
+	<-result.done
+	return result.functions
+}
+
+func (p memoizedFunctionsProvider) CallFunction(ctx context.Context, req providers.CallFunctionRequest) providers.CallFunctionResponse {
+	key, ok := providerFunctionCallKey(p.key, req)
+	if !ok {
+		return p.Interface.CallFunction(ctx, req)
+	}
+	result, produce := p.results.get(p.results.results, key)
+	if produce {
+		result.call = p.Interface.CallFunction(ctx, req)
+		close(result.done)
+	}
+	<-result.done
+	return result.call
+}
+
+// providerFunctionCallKey returns a string that uniquely identifies a call to
+// a function of the provider instance with the given key, or false if the
+// call can't be memoized because its arguments aren't all known.
+func providerFunctionCallKey(providerKey string, req providers.CallFunctionRequest) (string, bool) {
+	args := cty.TupleVal(req.Arguments)
+	if !args.IsWhollyKnown() || args.ContainsMarked() {
+		return "", false
+	}
+	argsJSON, err := ctyjson.Marshal(args, cty.DynamicPseudoType)
+	if err != nil {
+		return "", false
+	}
+	return providerKey + "\x00" + req.Name + "\x00" + string(argsJSON), true
+}
@@ -8,6 +8,8 @@ package tofu
 import (
 	"context"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -844,3 +846,76 @@ variable "value" { }
 		t.Fatalf("Expected function call")
 	}
 }
+
+func TestProviderFunctionResults(t *testing.T) {
+	var calls atomic.Int64
+	mockProvider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{},
+		GetFunctionsResponse: &providers.GetFunctionsResponse{
+			Functions: map[string]providers.FunctionSpec{
+				"echo": {
+					Parameters: []providers.FunctionParameterSpec{{
+						Name:               "input",
+						Type:               cty.String,
+						AllowUnknownValues: true,
+					}},
+					Return: cty.String,
+				},
+			},
+		},
+		CallFunctionFn: func(req providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
+			calls.Add(1)
+			resp.Result = req.Arguments[0]
+			return resp
+		},
+	}
+
+	results := newProviderFunctionResults()
+	pf, _ := addrs.ParseFunction("provider::mockname::echo").AsProviderFunction()
+	call := func(provider providers.Interface, arg cty.Value) cty.Value {
+		fn, diags := evalContextProviderFunction(t.Context(), provider, walkPlan, pf, tfdiags.SourceRange{})
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		got, err := fn.Call([]cty.Value{arg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Identical calls, including concurrent ones, reach the provider only once.
+	provider := results.Provider("provider.mockname", mockProvider)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call(provider, cty.StringVal("a"))
+		}()
+	}
+	wg.Wait()
+	if got, want := calls.Load(), int64(1); got != want {
+		t.Errorf("wrong number of calls for identical arguments %d; want %d", got, want)
+	}
+	if !mockProvider.GetFunctionsCalled {
+		t.Errorf("GetFunctions was not called")
+	}
+
+	// Different arguments and different provider instances are called
+	// separately.
+	if got, want := call(provider, cty.StringVal("b")), cty.StringVal("b"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+	call(results.Provider("provider.mockname.other", mockProvider), cty.StringVal("a"))
+	if got, want := calls.Load(), int64(3); got != want {
+		t.Errorf("wrong number of calls for different arguments %d; want %d", got, want)
+	}
+
+	// Calls with unknown arguments are never memoized.
+	call(provider, cty.UnknownVal(cty.String))
+	call(provider, cty.UnknownVal(cty.String))
+	if got, want := calls.Load(), int64(5); got != want {
+		t.Errorf("wrong number of calls for unknown arguments %d; want %d", got, want)
+	}
+}
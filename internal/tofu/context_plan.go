@@ -22,8 +22,10 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
+	"github.com/opentofu/opentofu/internal/perf"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/refactoring"
 	"github.com/opentofu/opentofu/internal/states"
@@ -92,6 +94,34 @@ type PlanOpts struct {
 	// fully-functional new object.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// CascadeReplace extends ForceReplace to the resource instances whose
+	// resources depend on the resources of the instances in ForceReplace,
+	// directly or indirectly, according to the dependency graph. As with
+	// ForceReplace itself, this affects only instances that would otherwise
+	// have been planned for an update or no action at all.
+	CascadeReplace bool
+
+	// PreviewProvisioners, if set, makes OpenTofu evaluate the provisioners
+	// that will run when each planned change is applied, and record them in
+	// the Provisioners field of the planned changes so that they can be
+	// reviewed before applying.
+	PreviewProvisioners bool
+
+	// PlanCache, if set, makes this an incremental plan: resource instances
+	// whose inputs match those of an instance that had no changes in the
+	// earlier plan that populated the cache are not planned again by their
+	// providers. The cache records the instances that have no changes in
+	// this plan, for use in the next one. It's used only in the normal
+	// planning mode.
+	PlanCache *PlanCache
+
+	// AllowDeferral, if set, makes OpenTofu defer the planning of resources
+	// whose count or for_each arguments won't be known until apply, and of
+	// anything that depends on them, to a later plan instead of returning
+	// an error. The deferred resources are listed in the DeferredResources
+	// field of the resulting plan.
+	AllowDeferral bool
+
 	// ExternalReferences allows the external caller to pass in references to
 	// nodes that should not be pruned even if they are not referenced within
 	// the actual graph.
@@ -227,6 +257,7 @@ func (c *Context) Plan(ctx context.Context, config *configs.Config, prevRunState
 
 The -target and -exclude options are not for routine use, and are provided only for exceptional situations such as recovering from errors or mistakes, or when OpenTofu specifically suggests to use it as part of an error message.`,
 		))
+		diags = diags.Append(unmatchedTargetsDiags(config, prevRunState, opts.Targets))
 	}
 
 	var plan *plans.Plan
@@ -249,6 +280,7 @@ The -target and -exclude options are not for routine use, and are provided only
 
 	// convert the variables into the format expected for the plan
 	varVals := make(map[string]plans.DynamicValue, len(opts.SetVariables))
+	ephemeralVarVals := make(map[string]plans.DynamicValue)
 	for k, iv := range opts.SetVariables {
 		if iv.Value == cty.NilVal {
 			continue // We only record values that the caller actually set
@@ -266,6 +298,10 @@ The -target and -exclude options are not for routine use, and are provided only
 			))
 			continue
 		}
+		if vc := config.Module.Variables[k]; vc != nil && vc.Ephemeral {
+			ephemeralVarVals[k] = dv
+			continue
+		}
 		varVals[k] = dv
 	}
 
@@ -273,6 +309,7 @@ The -target and -exclude options are not for routine use, and are provided only
 	// targets and provider SHAs.
 	if plan != nil {
 		plan.VariableValues = varVals
+		plan.EphemeralVariableValues = ephemeralVarVals
 		plan.TargetAddrs = opts.Targets
 		plan.ExcludeAddrs = opts.Excludes
 	} else if !diags.HasErrors() {
@@ -435,6 +472,7 @@ func (c *Context) destroyPlan(ctx context.Context, config *configs.Config, prevR
 		refreshOpts := *opts
 		refreshOpts.Mode = plans.NormalMode
 		refreshOpts.PreDestroyRefresh = true
+		refreshOpts.PlanCache = nil
 
 		// FIXME: A normal plan is required here to refresh the state, because
 		// the state and configuration may not match during a destroy, and a
@@ -496,6 +534,7 @@ func (c *Context) destroyPlan(ctx context.Context, config *configs.Config, prevR
 
 func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State) ([]refactoring.MoveStatement, refactoring.MoveResults) {
 	explicitMoveStmts := refactoring.FindMoveStatements(config)
+	explicitMoveStmts = refactoring.ExpandMoveStatementWildcards(explicitMoveStmts, prevRunState)
 	implicitMoveStmts := refactoring.ImpliedMoveStatements(config, prevRunState, explicitMoveStmts)
 	var moveStmts []refactoring.MoveStatement
 	if stmtsLen := len(explicitMoveStmts) + len(implicitMoveStmts); stmtsLen > 0 {
@@ -762,6 +801,62 @@ func importResourceWithoutConfigDiags(addressStr string, config *configs.Import)
 	return &diag
 }
 
+// unmatchedTargetsDiags returns a warning for each of the given resource
+// targets that doesn't match any resource in either the configuration or the
+// given state, which is most likely a typo in the -target option. Where
+// possible, the warning suggests a similar resource address.
+func unmatchedTargetsDiags(config *configs.Config, state *states.State, targets []addrs.Targetable) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, target := range targets {
+		var addr addrs.AbsResource
+		switch target := target.(type) {
+		case addrs.AbsResource:
+			addr = target
+		case addrs.AbsResourceInstance:
+			addr = target.ContainingResource()
+		default:
+			continue
+		}
+
+		modCfg := config.DescendentForInstance(addr.Module)
+		if modCfg != nil && modCfg.Module.ResourceByAddr(addr.Resource) != nil {
+			continue
+		}
+		if state != nil && state.Resource(addr) != nil {
+			continue
+		}
+
+		var suggestions []string
+		if modCfg != nil {
+			for _, rc := range modCfg.Module.ManagedResources {
+				suggestions = append(suggestions, rc.Addr().Absolute(addr.Module).String())
+			}
+			for _, rc := range modCfg.Module.DataResources {
+				suggestions = append(suggestions, rc.Addr().Absolute(addr.Module).String())
+			}
+		}
+		if state != nil {
+			for _, ms := range state.Modules {
+				for _, rs := range ms.Resources {
+					suggestions = append(suggestions, rs.Addr.String())
+				}
+			}
+		}
+		sort.Strings(suggestions)
+		suggestion := didyoumean.NameSuggestion(addr.String(), suggestions)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf(" Did you mean %s?", suggestion)
+		}
+
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Resource target matches nothing",
+			fmt.Sprintf("The target address %s does not match any resource in the configuration or the prior state, so it will not select anything.%s", target, suggestion),
+		))
+	}
+	return diags
+}
+
 func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunState *states.State, opts *PlanOpts) (*plans.Plan, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	log.Printf("[DEBUG] Building and walking plan graph for %s", opts.Mode)
@@ -780,7 +875,9 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 	}
 	providerFunctionTracker := make(ProviderFunctionMapping)
 
+	endGraphBuild := perf.StartPhase(ctx, "plan graph build")
 	graph, walkOp, moreDiags := c.planGraph(ctx, config, prevRunState, opts, providerFunctionTracker)
+	endGraphBuild()
 	diags = diags.Append(moreDiags)
 	if diags.HasErrors() {
 		return nil, diags
@@ -791,14 +888,18 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 	// If we get here then we should definitely have a non-nil "graph", which
 	// we can now walk.
 	changes := plans.NewChanges()
+	deferrals := NewDeferrals(opts.AllowDeferral, nil)
+	endGraphWalk := perf.StartPhase(ctx, "plan graph walk")
 	walker, walkDiags := c.walk(ctx, graph, walkOp, &graphWalkOpts{
 		Config:                  config,
 		InputState:              prevRunState,
 		Changes:                 changes,
 		MoveResults:             moveResults,
 		PlanTimeTimestamp:       timestamp,
+		Deferrals:               deferrals,
 		ProviderFunctionTracker: providerFunctionTracker,
 	})
+	endGraphWalk()
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 
@@ -830,6 +931,11 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 		diags = diags.Append(blockedMovesWarningDiag(moveResults))
 	}
 
+	deferredResources := deferrals.Resources()
+	if len(deferredResources) > 0 {
+		diags = diags.Append(deferredResourcesWarningDiag(deferredResources))
+	}
+
 	// If we reach this point with error diagnostics then "changes" is a
 	// representation of the subset of changes we were able to plan before
 	// we encountered errors, which we'll return as part of a non-nil plan
@@ -855,6 +961,7 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 		ExternalReferences: opts.ExternalReferences,
 		Checks:             states.NewCheckResults(walker.Checks),
 		Timestamp:          timestamp,
+		DeferredResources:  deferredResources,
 
 		// Other fields get populated by Context.Plan after we return
 	}
@@ -872,6 +979,9 @@ func (c *Context) planGraph(ctx context.Context, config *configs.Config, prevRun
 			Targets:                 opts.Targets,
 			Excludes:                opts.Excludes,
 			ForceReplace:            opts.ForceReplace,
+			CascadeReplace:          opts.CascadeReplace,
+			PreviewProvisioners:     opts.PreviewProvisioners,
+			PlanCache:               opts.PlanCache,
 			skipRefresh:             opts.SkipRefresh,
 			preDestroyRefresh:       opts.PreDestroyRefresh,
 			Operation:               walkPlan,
@@ -1054,6 +1164,11 @@ func (c *Context) driftedResources(ctx context.Context, config *configs.Config,
 					diags = diags.Append(err)
 					return nil, diags
 				}
+				if modCfg := config.DescendentForInstance(addr.Module); modCfg != nil {
+					if rc := modCfg.Module.ResourceByAddr(addr.Resource.Resource); rc != nil && len(rc.MaskInOutput) != 0 {
+						changeSrc.MaskedPaths = resourceMaskedPaths(rc)
+					}
+				}
 
 				drs = append(drs, changeSrc)
 			}
@@ -1105,6 +1220,22 @@ func blockedMovesWarningDiag(results refactoring.MoveResults) tfdiags.Diagnostic
 	)
 }
 
+func deferredResourcesWarningDiag(deferred []addrs.AbsResource) tfdiags.Diagnostic {
+	var itemsBuf bytes.Buffer
+	for _, addr := range deferred {
+		fmt.Fprintf(&itemsBuf, "\n  - %s", addr)
+	}
+
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Some resources were deferred",
+		fmt.Sprintf(
+			"OpenTofu didn't plan the following resources, because their count or for_each arguments, or their configuration, depend on values that won't be known until apply:%s\n\nAfter applying this plan, run \"tofu plan\" again to plan these resources.",
+			itemsBuf.String(),
+		),
+	)
+}
+
 // referenceAnalyzer returns a globalref.Analyzer object to help with
 // global analysis of references within the configuration that's attached
 // to the receiving context.
@@ -14,12 +14,15 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/zclconf/go-cty-debug/ctydebug"
 	"github.com/zclconf/go-cty/cty"
 
 	// "github.com/opentofu/opentofu/internal/configs"
@@ -27,6 +30,7 @@ import (
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -330,6 +334,67 @@ resource "test_object" "a" {
 	assertNoErrors(t, diags)
 }
 
+func TestContext2Plan_providerConfigDynamicBlock(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  roles = ["first", "second"]
+}
+
+provider "test" {
+  dynamic "assume_role" {
+    for_each = local.roles
+    content {
+      role_arn = assume_role.value
+    }
+  }
+}
+
+resource "test_object" "a" {
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.Provider.Block = &configschema.Block{
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"assume_role": {
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"role_arn": {Type: cty.String, Required: true},
+					},
+				},
+				Nesting: configschema.NestingList,
+			},
+		},
+	}
+
+	var got cty.Value
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+		got = req.Config
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	want := cty.ObjectVal(map[string]cty.Value{
+		"assume_role": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"role_arn": cty.StringVal("first")}),
+			cty.ObjectVal(map[string]cty.Value{"role_arn": cty.StringVal("second")}),
+		}),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong provider configuration\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
 func TestContext2Plan_dataReferencesResourceInModules(t *testing.T) {
 	p := testProvider("test")
 	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
@@ -1390,6 +1455,60 @@ func TestContext2Plan_movedResourceBasic(t *testing.T) {
 	})
 }
 
+func TestContext2Plan_movedResourceWildcard(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				for_each = toset(["x", "y"])
+			}
+
+			moved {
+				from = module.old[*].test_object.a
+				to   = test_object.a[*]
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, key := range []string{"x", "y"} {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(fmt.Sprintf(`module.old[%q].test_object.a`, key)), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{}`),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		}
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for _, key := range []string{"x", "y"} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf(`test_object.a[%q]`, key))
+		prevAddr := mustResourceInstanceAddr(fmt.Sprintf(`module.old[%q].test_object.a`, key))
+		t.Run(addr.String(), func(t *testing.T) {
+			if instPlan := plan.Changes.ResourceInstance(prevAddr); instPlan != nil {
+				t.Fatalf("unexpected plan for %s; should've moved to %s", prevAddr, addr)
+			}
+			instPlan := plan.Changes.ResourceInstance(addr)
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", addr)
+			}
+			if got, want := instPlan.PrevRunAddr, prevAddr; !got.Equal(want) {
+				t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+			}
+			if got, want := instPlan.Action, plans.NoOp; got != want {
+				t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
 func constructProviderSchemaForTesting(attrs map[string]*configschema.Attribute) providers.Schema {
 	return providers.Schema{
 		Block: &configschema.Block{Attributes: attrs},
@@ -2136,6 +2255,11 @@ Note that adding these options may include further additional resource instances
 
 The -target and -exclude options are not for routine use, and are provided only for exceptional situations such as recovering from errors or mistakes, or when OpenTofu specifically suggests to use it as part of an error message.`,
 			),
+			tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Resource target matches nothing",
+				"The target address test_object.unrelated does not match any resource in the configuration or the prior state, so it will not select anything.",
+			),
 			tfdiags.Sourceless(
 				tfdiags.Error,
 				"Moved resource instances excluded by targeting",
@@ -2991,6 +3115,91 @@ output "root" {
 	}
 }
 
+func TestContext2Plan_moduleSensitiveOutputs(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"child/main.tf": `
+output "password" {
+  value     = sensitive("xyz")
+  sensitive = true
+}
+
+output "names" {
+  value     = concat([sensitive("a")], ["b", "c"])
+  sensitive = true
+}
+
+output "host" {
+  value = "db.example.com"
+}`,
+		"main.tf": `
+module "child" {
+  source = "./child"
+
+  sensitive_outputs = ["password"]
+}
+
+output "password" {
+  value     = module.child.password
+  sensitive = true
+}
+
+output "names" {
+  # Not sensitive anymore, so this doesn't need to be declared as sensitive
+  value = module.child.names
+}
+
+output "host" {
+  value     = module.child.host
+  sensitive = true
+}`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for _, name := range []string{"names", "host"} {
+		change := plan.Changes.OutputValue(addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance))
+		if change == nil {
+			t.Fatalf("no change for output %q", name)
+		}
+		val, err := change.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if marks.Contains(val.After, marks.Sensitive) {
+			t.Errorf("output %q value is sensitive; want nonsensitive", name)
+		}
+	}
+
+	// An output listed in sensitive_outputs is sensitive in the calling
+	// module even if the child module doesn't declare it as sensitive.
+	m = testModuleInline(t, map[string]string{
+		"child/main.tf": `
+output "host" {
+  value = "db.example.com"
+}`,
+		"main.tf": `
+module "child" {
+  source = "./child"
+
+  sensitive_outputs = ["host"]
+}
+
+output "host" {
+  value = module.child.host
+}`,
+	})
+	_, diags = ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), "Output refers to sensitive values"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error:\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
 func TestContext2Plan_planDataSourceSensitiveNested(t *testing.T) {
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
@@ -3172,27 +3381,33 @@ func TestContext2Plan_forceReplace(t *testing.T) {
 	})
 }
 
-func TestContext2Plan_forceReplaceIncompleteAddr(t *testing.T) {
-	addr0 := mustResourceInstanceAddr("test_object.a[0]")
-	addr1 := mustResourceInstanceAddr("test_object.a[1]")
-	addrBare := mustResourceInstanceAddr("test_object.a")
+func TestContext2Plan_forceReplaceCascade(t *testing.T) {
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_object.b")
+	addrC := mustResourceInstanceAddr("test_object.c")
+	addrD := mustResourceInstanceAddr("test_object.d")
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
 			resource "test_object" "a" {
-				count = 2
+			}
+			resource "test_object" "b" {
+				depends_on = [test_object.a]
+			}
+			resource "test_object" "c" {
+				depends_on = [test_object.b]
+			}
+			resource "test_object" "d" {
 			}
 		`,
 	})
 
 	state := states.BuildState(func(s *states.SyncState) {
-		s.SetResourceInstanceCurrent(addr0, &states.ResourceInstanceObjectSrc{
-			AttrsJSON: []byte(`{}`),
-			Status:    states.ObjectReady,
-		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
-		s.SetResourceInstanceCurrent(addr1, &states.ResourceInstanceObjectSrc{
-			AttrsJSON: []byte(`{}`),
-			Status:    states.ObjectReady,
-		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		for _, addr := range []addrs.AbsResourceInstance{addrA, addrB, addrC, addrD} {
+			s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{}`),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		}
 	})
 
 	p := simpleMockProvider()
@@ -3205,69 +3420,383 @@ func TestContext2Plan_forceReplaceIncompleteAddr(t *testing.T) {
 	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
 		Mode: plans.NormalMode,
 		ForceReplace: []addrs.AbsResourceInstance{
-			addrBare,
+			addrA,
 		},
+		CascadeReplace: true,
 	})
 	if diags.HasErrors() {
 		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
 	}
-	diagsErr := diags.ErrWithWarnings()
-	if diagsErr == nil {
-		t.Fatalf("no warnings were returned")
-	}
-	if got, want := diagsErr.Error(), "Incompletely-matched force-replace resource instance"; !strings.Contains(got, want) {
-		t.Errorf("missing expected warning\ngot:\n%s\n\nwant substring: %s", got, want)
+
+	for _, tc := range []struct {
+		addr addrs.AbsResourceInstance
+		want plans.Action
+	}{
+		{addrA, plans.DeleteThenCreate},
+		{addrB, plans.DeleteThenCreate},
+		{addrC, plans.DeleteThenCreate},
+		{addrD, plans.NoOp},
+	} {
+		addr, want := tc.addr, tc.want
+		t.Run(addr.String(), func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(addr)
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", addr)
+			}
+
+			if got := instPlan.Action; got != want {
+				t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+			}
+		})
 	}
+}
 
-	t.Run(addr0.String(), func(t *testing.T) {
-		instPlan := plan.Changes.ResourceInstance(addr0)
-		if instPlan == nil {
-			t.Fatalf("no plan for %s at all", addr0)
-		}
+func TestContext2Plan_previewProvisioners(t *testing.T) {
+	addrB := mustResourceInstanceAddr("test_object.b")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				test_string = "new"
+				connection {
+					host     = "a.example.com"
+					user     = "admin"
+					password = "secret"
+				}
+				provisioner "shell" {
+					command = "setup ${self.test_string}"
+				}
+			}
+			resource "test_object" "b" {
+				test_string = "b"
+				provisioner "shell" {
+					when    = destroy
+					command = "teardown ${self.test_string}"
+				}
+				provisioner "shell" {
+					command = "setup ${self.test_string}"
+				}
+			}
+			resource "test_object" "c" {
+				count       = 1
+				test_string = "c"
+				provisioner "shell" {
+					when    = destroy
+					command = "teardown ${self.test_string}"
+				}
+			}
+		`,
+	})
 
-		if got, want := instPlan.Action, plans.NoOp; got != want {
-			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
-		}
-		if got, want := instPlan.ActionReason, plans.ResourceInstanceChangeNoReason; got != want {
-			t.Errorf("wrong action reason\ngot:  %s\nwant: %s", got, want)
+	state := states.BuildState(func(s *states.SyncState) {
+		for addr, attrs := range map[string]string{
+			"test_object.b":    `{"test_string":"b"}`,
+			"test_object.c[0]": `{"test_string":"c"}`,
+			"test_object.c[1]": `{"test_string":"c"}`,
+		} {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(addr), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
 		}
 	})
-	t.Run(addr1.String(), func(t *testing.T) {
-		instPlan := plan.Changes.ResourceInstance(addr1)
-		if instPlan == nil {
-			t.Fatalf("no plan for %s at all", addr1)
-		}
 
-		if got, want := instPlan.Action, plans.NoOp; got != want {
-			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
-		}
-		if got, want := instPlan.ActionReason, plans.ResourceInstanceChangeNoReason; got != want {
-			t.Errorf("wrong action reason\ngot:  %s\nwant: %s", got, want)
-		}
+	p := simpleMockProvider()
+	pr := testProvisioner()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
 	})
-}
 
-// Verify that adding a module instance does force existing module data sources
-// to be deferred
-func TestContext2Plan_noChangeDataSourceAddingModuleInstance(t *testing.T) {
-	m := testModuleInline(t, map[string]string{
-		"main.tf": `
-locals {
-  data = {
-    a = "a"
-    b = "b"
-  }
-}
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode:                plans.NormalMode,
+		ForceReplace:        []addrs.AbsResourceInstance{addrB},
+		PreviewProvisioners: true,
+	})
+	assertNoErrors(t, diags)
 
-module "one" {
-  source   = "./mod"
-  for_each = local.data
-  input = each.value
-}
+	shellConfig := func(command string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"command": cty.StringVal(command),
+			"order":   cty.NullVal(cty.String),
+			"when":    cty.NullVal(cty.String),
+		})
+	}
+	connection := func(host, user string) cty.Value {
+		attrs := map[string]cty.Value{
+			"type":         cty.NullVal(cty.String),
+			"host":         cty.NullVal(cty.String),
+			"port":         cty.NullVal(cty.Number),
+			"user":         cty.NullVal(cty.String),
+			"bastion_host": cty.NullVal(cty.String),
+			"bastion_port": cty.NullVal(cty.Number),
+			"bastion_user": cty.NullVal(cty.String),
+		}
+		if host == "" {
+			return cty.NullVal(cty.ObjectVal(attrs).Type())
+		}
+		attrs["host"] = cty.StringVal(host)
+		attrs["user"] = cty.StringVal(user)
+		return cty.ObjectVal(attrs)
+	}
 
-module "two" {
-  source   = "./mod"
-  for_each = module.one
+	for _, tc := range []struct {
+		addr string
+		want []plans.ProvisionerPreview
+	}{
+		{
+			"test_object.a",
+			[]plans.ProvisionerPreview{
+				{Type: "shell", Config: shellConfig("setup new"), Connection: connection("a.example.com", "admin")},
+			},
+		},
+		{
+			"test_object.b",
+			[]plans.ProvisionerPreview{
+				{Type: "shell", Destroy: true, Config: shellConfig("teardown b"), Connection: connection("", "")},
+				{Type: "shell", Config: shellConfig("setup b"), Connection: connection("", "")},
+			},
+		},
+		{
+			"test_object.c[0]",
+			nil,
+		},
+		{
+			"test_object.c[1]",
+			[]plans.ProvisionerPreview{
+				{Type: "shell", Destroy: true, Config: shellConfig("teardown c"), Connection: connection("", "")},
+			},
+		},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(mustResourceInstanceAddr(tc.addr))
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", tc.addr)
+			}
+			if diff := cmp.Diff(tc.want, instPlan.Provisioners, ctydebug.CmpOptions); diff != "" {
+				t.Errorf("wrong provisioner previews\n%s", diff)
+			}
+		})
+	}
+
+	if pr.ProvisionResourceCalled {
+		t.Errorf("provisioner was run during plan")
+	}
+}
+
+func TestContext2Plan_resourceHookPreviews(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				test_string = "a"
+				lifecycle {
+					hook "post_create" {
+						command = "created ${self.test_string}"
+					}
+				}
+				provisioner "shell" {
+					command = "setup ${self.test_string}"
+				}
+			}
+			resource "test_object" "b" {
+				test_string = "new"
+				lifecycle {
+					hook "post_create" {
+						command = "created ${self.test_string}"
+					}
+					hook "post_update" {
+						command = "updated ${self.test_string}"
+					}
+				}
+			}
+			resource "test_object" "c" {
+				count       = 1
+				test_string = "c"
+				lifecycle {
+					hook "pre_destroy" {
+						command = "destroying ${self.test_string}"
+					}
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for addr, attrs := range map[string]string{
+			"test_object.b":    `{"test_string":"old"}`,
+			"test_object.c[0]": `{"test_string":"c"}`,
+			"test_object.c[1]": `{"test_string":"c"}`,
+		} {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(addr), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		}
+	})
+
+	p := simpleMockProvider()
+	pr := testProvisioner()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"local-exec": testProvisionerFuncFixed(pr),
+			"shell":      testProvisionerFuncFixed(pr),
+		},
+	})
+
+	// Hooks are previewed even when provisioner previews aren't requested.
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	hookConfig := func(command string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"command": cty.StringVal(command),
+			"order":   cty.NullVal(cty.String),
+			"when":    cty.NullVal(cty.String),
+		})
+	}
+	noConnection := previewConnection(cty.NullVal(cty.DynamicPseudoType))
+
+	for _, tc := range []struct {
+		addr string
+		want []plans.ProvisionerPreview
+	}{
+		{
+			"test_object.a",
+			[]plans.ProvisionerPreview{
+				{Type: "local-exec", Hook: "post_create", Config: hookConfig("created a"), Connection: noConnection},
+			},
+		},
+		{
+			"test_object.b",
+			[]plans.ProvisionerPreview{
+				{Type: "local-exec", Hook: "post_update", Config: hookConfig("updated new"), Connection: noConnection},
+			},
+		},
+		{
+			"test_object.c[0]",
+			nil,
+		},
+		{
+			"test_object.c[1]",
+			[]plans.ProvisionerPreview{
+				{Type: "local-exec", Destroy: true, Hook: "pre_destroy", Config: hookConfig("destroying c"), Connection: noConnection},
+			},
+		},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(mustResourceInstanceAddr(tc.addr))
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", tc.addr)
+			}
+			if diff := cmp.Diff(tc.want, instPlan.Provisioners, ctydebug.CmpOptions); diff != "" {
+				t.Errorf("wrong hook previews\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_forceReplaceIncompleteAddr(t *testing.T) {
+	addr0 := mustResourceInstanceAddr("test_object.a[0]")
+	addr1 := mustResourceInstanceAddr("test_object.a[1]")
+	addrBare := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				count = 2
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr0, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+		s.SetResourceInstanceCurrent(addr1, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+		ForceReplace: []addrs.AbsResourceInstance{
+			addrBare,
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+	}
+	diagsErr := diags.ErrWithWarnings()
+	if diagsErr == nil {
+		t.Fatalf("no warnings were returned")
+	}
+	if got, want := diagsErr.Error(), "Incompletely-matched force-replace resource instance"; !strings.Contains(got, want) {
+		t.Errorf("missing expected warning\ngot:\n%s\n\nwant substring: %s", got, want)
+	}
+
+	t.Run(addr0.String(), func(t *testing.T) {
+		instPlan := plan.Changes.ResourceInstance(addr0)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addr0)
+		}
+
+		if got, want := instPlan.Action, plans.NoOp; got != want {
+			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := instPlan.ActionReason, plans.ResourceInstanceChangeNoReason; got != want {
+			t.Errorf("wrong action reason\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run(addr1.String(), func(t *testing.T) {
+		instPlan := plan.Changes.ResourceInstance(addr1)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addr1)
+		}
+
+		if got, want := instPlan.Action, plans.NoOp; got != want {
+			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := instPlan.ActionReason, plans.ResourceInstanceChangeNoReason; got != want {
+			t.Errorf("wrong action reason\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
+// Verify that adding a module instance does force existing module data sources
+// to be deferred
+func TestContext2Plan_noChangeDataSourceAddingModuleInstance(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  data = {
+    a = "a"
+    b = "b"
+  }
+}
+
+module "one" {
+  source   = "./mod"
+  for_each = local.data
+  input = each.value
+}
+
+module "two" {
+  source   = "./mod"
+  for_each = module.one
   input = each.value.output
 }
 `,
@@ -3750,6 +4279,121 @@ resource "test_resource" "a" {
 	})
 }
 
+func TestContext2Plan_preconditionPlannedValues(t *testing.T) {
+	// Preconditions can refer to the planned values of other resources. If
+	// those values are known during planning then the condition is checked
+	// during planning, and otherwise it's checked again during apply once
+	// the values are known.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "vpc_cidr" {
+  type = string
+}
+
+resource "test_resource" "vpc" {
+  value = var.vpc_cidr
+}
+
+resource "test_resource" "subnet" {
+  value = "10.0.1.0/24"
+  lifecycle {
+    precondition {
+      condition     = cidrcontains(test_resource.vpc.value, "10.0.1.0/24")
+      error_message = "Subnet must be inside the VPC."
+    }
+    precondition {
+      condition     = test_resource.vpc.output != "deleted"
+      error_message = "The VPC must not be deleted."
+    }
+  }
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_resource": {
+				Attributes: map[string]*configschema.Attribute{
+					"value": {
+						Type:     cty.String,
+						Required: true,
+					},
+					"output": {
+						Type:     cty.String,
+						Computed: true,
+					},
+				},
+			},
+		},
+	})
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		m := req.ProposedNewState.AsValueMap()
+		m["output"] = cty.UnknownVal(cty.String)
+		resp.PlannedState = cty.ObjectVal(m)
+		return resp
+	}
+	opts := func(cidr string) *PlanOpts {
+		return &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"vpc_cidr": &InputValue{
+					Value:      cty.StringVal(cidr),
+					SourceType: ValueFromCLIArg,
+				},
+			},
+		}
+	}
+	subnetAddr := mustResourceInstanceAddr("test_resource.subnet")
+
+	t.Run("known planned value fails", func(t *testing.T) {
+		ctx := testContext2(t, &ContextOpts{
+			Providers: map[addrs.Provider]providers.Factory{
+				addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			},
+		})
+		_, diags := ctx.Plan(context.Background(), m, states.NewState(), opts("192.168.0.0/16"))
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want errors")
+		}
+		if got, want := diags.Err().Error(), "Subnet must be inside the VPC."; !strings.Contains(got, want) {
+			t.Fatalf("wrong error:\ngot:  %s\nwant: message containing %q", got, want)
+		}
+	})
+
+	t.Run("unknown planned value checked during apply", func(t *testing.T) {
+		ctx := testContext2(t, &ContextOpts{
+			Providers: map[addrs.Provider]providers.Factory{
+				addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			},
+		})
+		plan, diags := ctx.Plan(context.Background(), m, states.NewState(), opts("10.0.0.0/16"))
+		assertNoErrors(t, diags)
+
+		result := plan.Checks.GetObjectResult(subnetAddr)
+		if result == nil {
+			t.Fatalf("no check result for %s", subnetAddr)
+		}
+		if got, want := result.Status, checks.StatusUnknown; got != want {
+			t.Fatalf("wrong check status during planning %s; want %s", got, want)
+		}
+
+		p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+			m := req.PlannedState.AsValueMap()
+			m["output"] = cty.StringVal("deleted")
+			resp.NewState = cty.ObjectVal(m)
+			return resp
+		}
+		_, diags = ctx.Apply(context.Background(), plan, m)
+		if !diags.HasErrors() {
+			t.Fatal("apply succeeded; want errors")
+		}
+		if got, want := diags.Err().Error(), "The VPC must not be deleted."; !strings.Contains(got, want) {
+			t.Fatalf("wrong error:\ngot:  %s\nwant: message containing %q", got, want)
+		}
+	})
+}
+
 func TestContext2Plan_dataSourcePreconditionPostcondition(t *testing.T) {
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
@@ -5071,11 +5715,230 @@ resource "test_object" "a" {
 	}
 }
 
-func TestContext2Plan_importResourceBasic(t *testing.T) {
-	addr := mustResourceInstanceAddr("test_object.a")
-
-	type TestConfiguration struct {
-		Description         string
+func TestContext2Plan_ignoreChangesPatterns(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  tags = {
+    Name                  = "updated"
+    "kubernetes.io/added" = "config"
+  }
+
+  rule {
+    port        = 80
+    description = "changed"
+  }
+  rule {
+    port        = 8443
+    description = "changed"
+  }
+
+  lifecycle {
+    ignore_changes = [
+      tags["kubernetes.io/*"],
+      rule[*].description,
+    ]
+  }
+}
+`})
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"tags": {
+				Type:     cty.Map(cty.String),
+				Optional: true,
+			},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"rule": {
+				Nesting: configschema.NestingList,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"port": {
+							Type:     cty.Number,
+							Required: true,
+						},
+						"description": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	p := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"test_object": {Block: schema},
+			},
+		},
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+
+	state := states.NewState()
+	state.RootModule().SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.a").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"tags":{"Name":"original","kubernetes.io/cluster/main":"owned"},"rule":[{"port":80,"description":"http"},{"port":443,"description":"https"}]}`),
+		},
+		mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		addrs.NoKey,
+	)
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if change == nil {
+		t.Fatal("no change for test_object.a")
+	}
+	if got, want := change.Action, plans.Update; got != want {
+		t.Fatalf("wrong action %s; want %s", got, want)
+	}
+	decoded, err := change.Decode(schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := cty.ObjectVal(map[string]cty.Value{
+		"tags": cty.MapVal(map[string]cty.Value{
+			"Name":                       cty.StringVal("updated"),
+			"kubernetes.io/cluster/main": cty.StringVal("owned"),
+		}),
+		"rule": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"port":        cty.NumberIntVal(80),
+				"description": cty.StringVal("http"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"port":        cty.NumberIntVal(8443),
+				"description": cty.StringVal("https"),
+			}),
+		}),
+	})
+	if diff := cmp.Diff(want, decoded.After, ctydebug.CmpOptions); diff != "" {
+		t.Errorf("wrong planned values\n%s", diff)
+	}
+}
+
+func TestContext2Plan_maskInOutput(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  user_data = "secret"
+  tags = {
+    Name   = "web"
+    secret = "yes"
+  }
+
+  lifecycle {
+    mask_in_output = [user_data, tags["secret"]]
+  }
+}
+
+output "user_data" {
+  value = test_object.a.user_data
+}
+`})
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"user_data": {
+				Type:     cty.String,
+				Optional: true,
+			},
+			"tags": {
+				Type:     cty.Map(cty.String),
+				Optional: true,
+			},
+		},
+	}
+	p := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"test_object": {Block: schema},
+			},
+		},
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	// The output isn't marked as sensitive, so it would fail if the masked
+	// value were treated as a sensitive value.
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if change == nil {
+		t.Fatal("no change for test_object.a")
+	}
+	want := cty.NewPathSet(
+		cty.GetAttrPath("user_data"),
+		cty.GetAttrPath("tags").Index(cty.StringVal("secret")),
+	)
+	if !change.MaskedPaths.Equal(want) {
+		t.Errorf("wrong masked paths\ngot:  %#v\nwant: %#v", change.MaskedPaths.List(), want.List())
+	}
+	if len(change.AfterValMarks) != 0 {
+		t.Errorf("masked values are marked: %#v", change.AfterValMarks)
+	}
+}
+
+func TestContext2Plan_maskInOutputInvalid(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  lifecycle {
+    mask_in_output = [user_data]
+  }
+}
+`})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), `Unsupported attribute: This object has no argument, nested block, or exported attribute named "user_data".`; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_importResourceBasic(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+
+	type TestConfiguration struct {
+		Description         string
 		inlineConfiguration map[string]string
 	}
 	configurations := []TestConfiguration{
@@ -5818,6 +6681,76 @@ import {
 	}
 }
 
+func TestContext2Plan_importIdFromDataSource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+data "test_object" "discovered" {
+}
+
+resource "test_object" "a" {
+  for_each = data.test_object.discovered.test_map
+}
+
+import {
+  for_each = data.test_object.discovered.test_map
+  to       = test_object.a[each.key]
+  id       = "${each.value}/${each.key}"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		state := req.Config.AsValueMap()
+		state["test_map"] = cty.MapVal(map[string]cty.Value{
+			"web-1": cty.StringVal("i-abc"),
+			"web-2": cty.StringVal("i-def"),
+		})
+		return providers.ReadDataSourceResponse{State: cty.ObjectVal(state)}
+	}
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		return providers.ImportResourceStateResponse{
+			ImportedResources: []providers.ImportedResource{
+				{
+					TypeName: "test_object",
+					State: cty.ObjectVal(map[string]cty.Value{
+						"test_string": cty.StringVal(req.ID),
+						"test_number": cty.NullVal(cty.Number),
+						"test_bool":   cty.NullVal(cty.Bool),
+						"test_list":   cty.NullVal(cty.List(cty.String)),
+						"test_map":    cty.NullVal(cty.Map(cty.String)),
+					}),
+				},
+			},
+		}
+	}
+	p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+		return providers.ReadResourceResponse{NewState: req.PriorState}
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for key, wantID := range map[string]string{"web-1": "i-abc/web-1", "web-2": "i-def/web-2"} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_object.a[%q]", key))
+		instPlan := plan.Changes.ResourceInstance(addr)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addr)
+		}
+		if instPlan.Importing == nil {
+			t.Fatalf("%s is not being imported", addr)
+		}
+		if got := instPlan.Importing.ID; got != wantID {
+			t.Errorf("wrong import ID for %s\ngot:  %s\nwant: %s", addr, got, wantID)
+		}
+	}
+}
+
 func TestContext2Plan_importWithInvalidForEach(t *testing.T) {
 	type TestConfiguration struct {
 		Description         string
@@ -5923,7 +6856,7 @@ import {
 		},
 		{
 			Description:   "for_each value is unknown",
-			expectedError: `Invalid import id argument: The import block "id" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.`,
+			expectedError: `Invalid import id argument: The import block "id" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.` + "\n\n" + `To work around this, use the -target option to first apply only the resources that the import ID depends on, and then plan again to import this resource.`,
 			inlineConfiguration: map[string]string{
 				"main.tf": `
 resource "test_object" "reference" {
@@ -8629,3 +9562,718 @@ func featuresBlockTestSchema() *configschema.Block {
 		},
 	}
 }
+
+func TestContext2Plan_enabled(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  monitoring = false
+}
+
+resource "test_object" "disabled" {
+  test_string = "disabled"
+
+  lifecycle {
+    enabled = local.monitoring
+  }
+}
+
+resource "test_object" "enabled" {
+  test_string = "enabled"
+
+  lifecycle {
+    enabled = !local.monitoring
+  }
+}
+
+resource "test_object" "previously_enabled" {
+  lifecycle {
+    enabled = local.monitoring
+  }
+}
+
+module "child" {
+  source = "./child"
+
+  lifecycle {
+    enabled = local.monitoring
+  }
+}
+
+output "disabled" {
+  value = test_object.disabled == null
+}
+
+output "enabled" {
+  value = test_object.enabled.test_string
+}
+
+output "child" {
+  value = module.child == null
+}
+`,
+		"child/main.tf": `
+resource "test_object" "a" {
+}
+
+output "id" {
+  value = test_object.a.test_string
+}
+`,
+	})
+	p := simpleMockProvider()
+
+	provider := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_object.previously_enabled"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"old"}`),
+			Status:    states.ObjectReady,
+		}, provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("module.child.test_object.a"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"old"}`),
+			Status:    states.ObjectReady,
+		}, provider, addrs.NoKey)
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	wantActions := map[string]plans.Action{
+		"test_object.enabled":            plans.Create,
+		"test_object.previously_enabled": plans.Delete,
+		"module.child.test_object.a":     plans.Delete,
+	}
+	gotActions := map[string]plans.Action{}
+	for _, rc := range plan.Changes.Resources {
+		gotActions[rc.Addr.String()] = rc.Action
+	}
+	if diff := cmp.Diff(wantActions, gotActions); diff != "" {
+		t.Errorf("wrong planned actions\n%s", diff)
+	}
+
+	wantOutputs := map[string]cty.Value{
+		"disabled": cty.True,
+		"enabled":  cty.StringVal("enabled"),
+		"child":    cty.True,
+	}
+	for name, want := range wantOutputs {
+		change := plan.Changes.OutputValue(addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance))
+		if change == nil {
+			t.Errorf("no planned change for output %q", name)
+			continue
+		}
+		got, err := change.After.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.RawEquals(want) {
+			t.Errorf("wrong value for output %q\ngot:  %#v\nwant: %#v", name, got, want)
+		}
+	}
+
+	state, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	var gotAddrs []string
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key := range rs.Instances {
+				gotAddrs = append(gotAddrs, rs.Addr.Instance(key).String())
+			}
+		}
+	}
+	if diff := cmp.Diff([]string{"test_object.enabled"}, gotAddrs); diff != "" {
+		t.Errorf("wrong resource instances in state after apply\n%s", diff)
+	}
+}
+
+func TestContext2Plan_enabledUnknown(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  lifecycle {
+    # timestamp() isn't known until apply
+    enabled = timestamp() != ""
+  }
+}
+`,
+	})
+	p := simpleMockProvider()
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want an error for the unknown enabled value")
+	}
+	if got, want := diags.Err().Error(), `The "enabled" value depends on resource attributes that cannot be determined until apply`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, want)
+	}
+}
+
+func TestContext2Plan_variableValidationOtherVariable(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "enable_https" {
+  type    = bool
+  default = false
+}
+
+variable "certificate_arn" {
+  type    = string
+  default = null
+
+  validation {
+    condition     = !var.enable_https || var.certificate_arn != null
+    error_message = "The certificate_arn value is required when enable_https is true."
+  }
+}
+`,
+	})
+
+	tests := map[string]struct {
+		vars    map[string]cty.Value
+		wantErr bool
+	}{
+		"https disabled": {
+			vars: map[string]cty.Value{
+				"enable_https":    cty.False,
+				"certificate_arn": cty.NullVal(cty.String),
+			},
+		},
+		"https enabled with certificate": {
+			vars: map[string]cty.Value{
+				"enable_https":    cty.True,
+				"certificate_arn": cty.StringVal("arn:aws:acm:example"),
+			},
+		},
+		"https enabled without certificate": {
+			vars: map[string]cty.Value{
+				"enable_https":    cty.True,
+				"certificate_arn": cty.NullVal(cty.String),
+			},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext2(t, &ContextOpts{})
+			_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+				Mode:         plans.NormalMode,
+				SetVariables: InputValuesFromCaller(test.vars),
+			})
+			if !test.wantErr {
+				assertNoErrors(t, diags)
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want a validation error")
+			}
+			if got, want := diags.Err().Error(), "The certificate_arn value is required when enable_https is true."; !strings.Contains(got, want) {
+				t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_outputTypeConstraint(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+}
+
+output "out" {
+  value = module.child.settings
+}
+`,
+		"child/main.tf": `
+output "settings" {
+  type = object({
+    name  = string
+    count = number
+    tags  = optional(map(string), {})
+  })
+  value = {
+    name  = "example"
+    count = "2"
+  }
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	outChangeSrc := plan.Changes.OutputValue(addrs.RootModuleInstance.OutputValue("out"))
+	if outChangeSrc == nil {
+		t.Fatalf("no change planned for output value 'out'")
+	}
+	outChange, err := outChangeSrc.Decode()
+	if err != nil {
+		t.Fatalf("failed to decode output value 'out': %s", err)
+	}
+	// The child module's output value should have been converted to its
+	// declared type, including the default for the optional attribute.
+	got := outChange.After
+	want := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("example"),
+		"count": cty.NumberIntVal(2),
+		"tags":  cty.MapValEmpty(cty.String),
+	})
+	if !want.RawEquals(got) {
+		t.Errorf("wrong value for output value 'out'\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_outputTypeConstraintInvalid(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+output "out" {
+  type  = list(string)
+  value = "not a list"
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want an error")
+	}
+	if got, want := diags.Err().Error(), `The value of output "out" is not suitable for its type constraint: list of string required, but have string.`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, want)
+	}
+}
+
+func TestContext2Plan_deferUnknownExpansionKeepsExisting(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "b" {
+  # timestamp() isn't known until apply.
+  count       = length(timestamp())
+  test_string = "b"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr(`test_object.b[0]`), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"b"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+
+	if got, want := len(plan.DeferredResources), 1; got != want {
+		t.Fatalf("wrong number of deferred resources %d; want %d", got, want)
+	}
+	if got, want := plan.DeferredResources[0].String(), "test_object.b"; got != want {
+		t.Errorf("wrong deferred resource %s; want %s", got, want)
+	}
+	// The existing instance must not be planned for destruction just
+	// because the count isn't known yet.
+	if change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.b[0]")); change != nil {
+		t.Errorf("unexpected %s change for test_object.b[0]", change.Action)
+	}
+	if plan.PriorState.ResourceInstance(mustResourceInstanceAddr("test_object.b[0]")) == nil {
+		t.Errorf("test_object.b[0] is missing from the prior state")
+	}
+}
+
+func TestContext2Plan_deferByConfigWholeResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  # timestamp() isn't known until apply.
+  count       = length(timestamp())
+  test_string = "a"
+}
+
+resource "test_object" "b" {
+  count = 2
+  # Only the first instance refers to the deferred resource.
+  test_string = [test_object.a[0].test_string, "b"][count.index]
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+
+	var gotDeferred []string
+	for _, addr := range plan.DeferredResources {
+		gotDeferred = append(gotDeferred, addr.String())
+	}
+	if diff := cmp.Diff([]string{"test_object.a", "test_object.b"}, gotDeferred); diff != "" {
+		t.Errorf("wrong deferred resources\n%s", diff)
+	}
+	// None of the instances of the deferred resource can have changes,
+	// including the one whose own configuration doesn't refer to anything
+	// deferred.
+	for _, rc := range plan.Changes.Resources {
+		t.Errorf("unexpected %s change for %s", rc.Action, rc.Addr)
+	}
+}
+
+func TestContext2Plan_userDefinedFunctions(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+function "label" {
+  params = [name]
+  result = "${module::naming::prefix()}-${name}"
+}
+
+module "naming" {
+  source = "./naming"
+}
+
+resource "test_object" "a" {
+  test_string = local::label("a")
+}
+`,
+		"naming/main.tf": `
+function "prefix" {
+  params = []
+  result = upper(local::base())
+  export = true
+}
+
+function "base" {
+  params = []
+  result = "app"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if change == nil {
+		t.Fatal("no change for test_object.a")
+	}
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block
+	val, err := change.After.Decode(schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := val.GetAttr("test_string"), cty.StringVal("APP-a"); !got.RawEquals(want) {
+		t.Errorf("wrong test_string\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_targetMatchesNothing(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "web" {
+}
+
+resource "test_object" "db" {
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		Targets: []addrs.Targetable{
+			mustResourceInstanceAddr("test_object.db"),
+			mustResourceInstanceAddr("test_object.webb"),
+			mustResourceInstanceAddr("test_object.unrelated"),
+		},
+	})
+	assertNoErrors(t, diags)
+
+	var got []string
+	for _, diag := range diags {
+		if diag.Description().Summary == "Resource target matches nothing" {
+			got = append(got, diag.Description().Detail)
+		}
+	}
+	want := []string{
+		"The target address test_object.webb does not match any resource in the configuration or the prior state, so it will not select anything. Did you mean test_object.web?",
+		"The target address test_object.unrelated does not match any resource in the configuration or the prior state, so it will not select anything.",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong warnings\n%s", diff)
+	}
+}
+
+func TestContext2Plan_incremental(t *testing.T) {
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, name := range []string{"a", "b"} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr("test_object."+name),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"test_string":"` + name + `"}`),
+					Status:    states.ObjectReady,
+				},
+				mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+				addrs.NoKey,
+			)
+		}
+	})
+
+	config := func(b, provider string) map[string]string {
+		return map[string]string{
+			"main.tf": fmt.Sprintf(`
+provider "test" {
+  test_string = %q
+}
+
+resource "test_object" "a" {
+  test_string = "a"
+}
+
+resource "test_object" "b" {
+  test_string = %q
+}
+`, provider, b)}
+	}
+
+	p := simpleMockProvider()
+	var mu sync.Mutex
+	var planned []string
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		planned = append(planned, req.Config.GetAttr("test_string").AsString())
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	// The first plan has no earlier results, so both instances are planned
+	// by the provider and recorded in the cache.
+	cache := NewPlanCache(nil, nil)
+	plan, diags := ctx.Plan(context.Background(), testModuleInline(t, config("b", "p")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	if !plan.Changes.Empty() {
+		t.Fatal("unexpected changes in the first plan")
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, planned, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("wrong instances planned by the provider in the first plan\n%s", diff)
+	}
+	if got := len(cache.Entries()); got != 2 {
+		t.Fatalf("recorded %d entries; want 2", got)
+	}
+
+	// The second plan changes only the configuration of test_object.b, so
+	// only that instance is planned by the provider.
+	planned = nil
+	cache = NewPlanCache(cache.Entries(), nil)
+	plan, diags = ctx.Plan(context.Background(), testModuleInline(t, config("changed", "p")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	if diff := cmp.Diff([]string{"changed"}, planned); diff != "" {
+		t.Errorf("wrong instances planned by the provider in the second plan\n%s", diff)
+	}
+	if got := cache.Hits(); got != 1 {
+		t.Errorf("got %d cache hits; want 1", got)
+	}
+	for addr, want := range map[string]plans.Action{
+		"test_object.a": plans.NoOp,
+		"test_object.b": plans.Update,
+	} {
+		change := plan.Changes.ResourceInstance(mustResourceInstanceAddr(addr))
+		if change == nil || change.Action != want {
+			t.Errorf("wrong change for %s: %#v; want %s", addr, change, want)
+		}
+	}
+
+	// Only the instance without changes is recorded for the next plan.
+	entries := cache.Entries()
+	if _, ok := entries["test_object.a"]; !ok || len(entries) != 1 {
+		t.Errorf("wrong entries recorded by the second plan: %#v", entries)
+	}
+
+	// A different provider configuration can change how the provider plans
+	// the same object, so test_object.a is planned again.
+	planned = nil
+	cache = NewPlanCache(entries, nil)
+	_, diags = ctx.Plan(context.Background(), testModuleInline(t, config("changed", "other")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	if got := cache.Hits(); got != 0 {
+		t.Errorf("got %d cache hits with a changed provider configuration; want 0", got)
+	}
+
+	// So can a different provider package, even with the same
+	// configuration.
+	providers := map[addrs.Provider]string{
+		addrs.NewDefaultProvider("test"): "1.0.0 h1:abc",
+	}
+	cache = NewPlanCache(nil, providers)
+	_, diags = ctx.Plan(context.Background(), testModuleInline(t, config("changed", "other")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	cache = NewPlanCache(cache.Entries(), providers)
+	_, diags = ctx.Plan(context.Background(), testModuleInline(t, config("changed", "other")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	if got := cache.Hits(); got != 1 {
+		t.Errorf("got %d cache hits with the same provider package; want 1", got)
+	}
+	providers[addrs.NewDefaultProvider("test")] = "1.1.0 h1:def"
+	cache = NewPlanCache(cache.Entries(), providers)
+	_, diags = ctx.Plan(context.Background(), testModuleInline(t, config("changed", "other")), state, &PlanOpts{
+		Mode:      plans.NormalMode,
+		PlanCache: cache,
+	})
+	assertNoErrors(t, diags)
+	if got := cache.Hits(); got != 0 {
+		t.Errorf("got %d cache hits with a different provider package; want 0", got)
+	}
+}
+
+func TestContext2Plan_refreshParallelism(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_instance" "a" {
+  count = 4
+}
+
+resource "other_instance" "b" {
+  count = 4
+}
+`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for provider, resource := range map[string]string{"test": "test_instance.a", "other": "other_instance.b"} {
+			for i := 0; i < 4; i++ {
+				s.SetResourceInstanceCurrent(
+					mustResourceInstanceAddr(fmt.Sprintf("%s[%d]", resource, i)),
+					&states.ResourceInstanceObjectSrc{
+						AttrsJSON: []byte(`{"id":"foo"}`),
+						Status:    states.ObjectReady,
+					},
+					mustProviderConfig(fmt.Sprintf(`provider["registry.opentofu.org/hashicorp/%s"]`, provider)),
+					addrs.NoKey,
+				)
+			}
+		}
+	})
+
+	testP := testProvider("test")
+	otherP := testProvider("other")
+
+	// The shared limit of one would refresh the instances one at a time, but
+	// each provider configuration gets its own pool of two instead.
+	hook := &refreshConcurrencyHook{active: map[string]int{}, highest: map[string]int{}}
+	ctx := testContext2(t, &ContextOpts{
+		Hooks:              []Hook{hook},
+		Parallelism:        1,
+		RefreshParallelism: 2,
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"):  testProviderFuncFixed(testP),
+			addrs.NewDefaultProvider("other"): testProviderFuncFixed(otherP),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for _, typeName := range []string{"test_instance", "other_instance"} {
+		if got := hook.highest[typeName]; got > 2 {
+			t.Errorf("refreshed %d %s resources concurrently; want at most 2", got, typeName)
+		}
+	}
+	if got := hook.highestTotal; got < 2 {
+		t.Errorf("refreshed at most %d resources concurrently; want the providers not to share a limit", got)
+	}
+}
+
+// refreshConcurrencyHook records the largest number of instances of each
+// resource type, and overall, that were being refreshed at the same time.
+type refreshConcurrencyHook struct {
+	NilHook
+
+	mu           sync.Mutex
+	active       map[string]int
+	highest      map[string]int
+	activeTotal  int
+	highestTotal int
+}
+
+func (h *refreshConcurrencyHook) PreRefresh(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value) (HookAction, error) {
+	h.mu.Lock()
+	h.active[addr.Resource.Resource.Type]++
+	h.highest[addr.Resource.Resource.Type] = max(h.highest[addr.Resource.Resource.Type], h.active[addr.Resource.Resource.Type])
+	h.activeTotal++
+	h.highestTotal = max(h.highestTotal, h.activeTotal)
+	h.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+	return HookActionContinue, nil
+}
+
+func (h *refreshConcurrencyHook) PostRefresh(addr addrs.AbsResourceInstance, _ states.Generation, _, _ cty.Value) (HookAction, error) {
+	h.mu.Lock()
+	h.active[addr.Resource.Resource.Type]--
+	h.activeTotal--
+	h.mu.Unlock()
+	return HookActionContinue, nil
+}
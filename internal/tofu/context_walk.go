@@ -46,6 +46,10 @@ type graphWalkOpts struct {
 
 	MoveResults refactoring.MoveResults
 
+	// Deferrals tracks the resources whose planning is deferred to a later
+	// plan. It's nil for walks that can't defer anything.
+	Deferrals *Deferrals
+
 	ProviderFunctionTracker ProviderFunctionMapping
 }
 
@@ -153,6 +157,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		InstanceExpander:        instances.NewExpander(),
 		MoveResults:             opts.MoveResults,
 		ImportResolver:          NewImportResolver(),
+		Deferrals:               opts.Deferrals,
 		Operation:               operation,
 		StopContext:             c.runContext,
 		PlanTimestamp:           opts.PlanTimeTimestamp,
@@ -0,0 +1,92 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// Deferrals tracks the resources whose planning is deferred to a later plan
+// during a graph walk, because their count or for_each arguments, or the
+// configuration of their instances, depend on values that won't be known
+// until apply.
+//
+// References to a deferred resource evaluate to an unknown value marked with
+// marks.Deferred, so that anything derived from it can be recognized and
+// deferred too.
+//
+// A nil *Deferrals is valid and never allows deferring anything.
+type Deferrals struct {
+	allowed bool
+
+	mu        sync.Mutex
+	resources addrs.Set[addrs.AbsResource]
+}
+
+// NewDeferrals returns a new Deferrals object. If allowed is true then the
+// graph walk may defer the planning of additional resources, and otherwise
+// only the given resources, which were deferred by an earlier plan, are
+// treated as deferred.
+func NewDeferrals(allowed bool, deferred []addrs.AbsResource) *Deferrals {
+	return &Deferrals{
+		allowed:   allowed,
+		resources: addrs.MakeSet(deferred...),
+	}
+}
+
+// Allowed returns true if the graph walk may defer the planning of resources
+// that aren't already deferred.
+func (d *Deferrals) Allowed() bool {
+	return d != nil && d.allowed
+}
+
+// Defer records that the planning of the given resource is deferred.
+func (d *Deferrals) Defer(addr addrs.AbsResource) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resources.Add(addr)
+}
+
+// IsDeferred returns true if the planning of the given resource is deferred.
+func (d *Deferrals) IsDeferred(addr addrs.AbsResource) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.resources.Has(addr)
+}
+
+// Resources returns the addresses of all of the deferred resources, in a
+// consistent order.
+func (d *Deferrals) Resources() []addrs.AbsResource {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.resources) == 0 {
+		return nil
+	}
+	ret := make([]addrs.AbsResource, 0, len(d.resources))
+	for _, addr := range d.resources {
+		ret = append(ret, addr)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret
+}
+
+// deferredResourceValue is the value that references to a deferred resource
+// evaluate to.
+var deferredResourceValue = cty.DynamicVal.Mark(marks.Deferred)
@@ -80,6 +80,16 @@ type EvalContext interface {
 	// provider configuration does not match the Path() of the EvalContext.
 	ConfigureProvider(context.Context, addrs.AbsProviderConfig, addrs.InstanceKey, cty.Value) tfdiags.Diagnostics
 
+	// ProviderConfigUnknown returns true if the given provider instance was
+	// configured with a configuration that isn't wholly known, which means
+	// that the provider might not be able to plan the resources that use it.
+	ProviderConfigUnknown(addrs.AbsProviderConfig, addrs.InstanceKey) bool
+
+	// ProviderConfigValue returns the configuration that the given provider
+	// instance was configured with, or cty.NilVal if it hasn't been
+	// configured yet.
+	ProviderConfigValue(addrs.AbsProviderConfig, addrs.InstanceKey) cty.Value
+
 	// ProviderInput and SetProviderInput are used to configure providers
 	// from user input.
 	//
@@ -215,6 +225,11 @@ type EvalContext interface {
 	// and have a configuration
 	ImportResolver() *ImportResolver
 
+	// Deferrals returns the object tracking the resources whose planning is
+	// deferred to a later plan. The result may be nil if the current walk
+	// can't defer anything, which the methods of Deferrals handle.
+	Deferrals() *Deferrals
+
 	// WithPath returns a copy of the context with the internal path set to the
 	// path argument.
 	WithPath(path addrs.ModuleInstance) EvalContext
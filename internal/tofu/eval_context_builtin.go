@@ -70,6 +70,11 @@ type BuiltinEvalContext struct {
 	ProviderCache       map[string]map[addrs.InstanceKey]providers.Interface
 	ProviderInputConfig map[string]map[string]cty.Value
 
+	// ProviderConfigValues records the configuration that each provider
+	// instance was configured with, which can include values that aren't
+	// known yet during planning. It's guarded by ProviderLock.
+	ProviderConfigValues map[string]map[addrs.InstanceKey]cty.Value
+
 	ProvisionerLock  *sync.Mutex
 	ProvisionerCache map[string]provisioners.Interface
 
@@ -81,8 +86,13 @@ type BuiltinEvalContext struct {
 	InstanceExpanderValue   *instances.Expander
 	MoveResultsValue        refactoring.MoveResults
 	ImportResolverValue     *ImportResolver
+	DeferralsValue          *Deferrals
 	Encryption              encryption.Encryption
 	ProviderFunctionTracker ProviderFunctionMapping
+
+	// ProviderFunctionResults memoizes the results of provider function
+	// calls across the whole graph walk. It may be nil.
+	ProviderFunctionResults *providerFunctionResults
 }
 
 // BuiltinEvalContext implements EvalContext
@@ -226,6 +236,16 @@ func (c *BuiltinEvalContext) ConfigureProvider(ctx context.Context, addr addrs.A
 		return diags
 	}
 
+	if c.ProviderConfigValues != nil {
+		c.ProviderLock.Lock()
+		providerAddrKey := addr.String()
+		if c.ProviderConfigValues[providerAddrKey] == nil {
+			c.ProviderConfigValues[providerAddrKey] = make(map[addrs.InstanceKey]cty.Value)
+		}
+		c.ProviderConfigValues[providerAddrKey][providerKey] = cfg
+		c.ProviderLock.Unlock()
+	}
+
 	req := providers.ConfigureProviderRequest{
 		TerraformVersion: version.String(),
 		Config:           cfg,
@@ -235,6 +255,22 @@ func (c *BuiltinEvalContext) ConfigureProvider(ctx context.Context, addr addrs.A
 	return resp.Diagnostics
 }
 
+func (c *BuiltinEvalContext) ProviderConfigUnknown(addr addrs.AbsProviderConfig, providerKey addrs.InstanceKey) bool {
+	cfg := c.ProviderConfigValue(addr, providerKey)
+	return cfg != cty.NilVal && !cfg.IsWhollyKnown()
+}
+
+func (c *BuiltinEvalContext) ProviderConfigValue(addr addrs.AbsProviderConfig, providerKey addrs.InstanceKey) cty.Value {
+	c.ProviderLock.Lock()
+	defer c.ProviderLock.Unlock()
+
+	cfg, ok := c.ProviderConfigValues[addr.String()][providerKey]
+	if !ok {
+		return cty.NilVal
+	}
+	return cfg
+}
+
 func (c *BuiltinEvalContext) ProviderInput(_ context.Context, pc addrs.AbsProviderConfig) map[string]cty.Value {
 	c.ProviderLock.Lock()
 	defer c.ProviderLock.Unlock()
@@ -454,7 +490,9 @@ func (c *BuiltinEvalContext) EvaluationScope(self addrs.Referenceable, source ad
 	mc := c.Evaluator.Config.DescendentForInstance(c.PathValue)
 
 	if mc == nil || mc.Module.ProviderRequirements == nil {
-		return c.Evaluator.Scope(data, self, source, nil)
+		scope := c.Evaluator.Scope(data, self, source, nil)
+		scope.UserFunctions = c.Evaluator.UserFunctions(mc)
+		return scope
 	}
 
 	scope := c.Evaluator.Scope(data, self, source, func(ctx context.Context, pf addrs.ProviderFunction, rng tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics) {
@@ -495,9 +533,16 @@ func (c *BuiltinEvalContext) EvaluationScope(self addrs.Referenceable, source ad
 			})
 		}
 
+		resultsKey := providedBy.Provider.String()
+		if providerKey != addrs.NoKey {
+			resultsKey += providerKey.String()
+		}
+		provider = c.ProviderFunctionResults.Provider(resultsKey, provider)
+
 		return evalContextProviderFunction(ctx, provider, c.Evaluator.Operation, pf, rng)
 	})
 	scope.SetActiveExperiments(mc.Module.ActiveExperiments)
+	scope.UserFunctions = c.Evaluator.UserFunctions(mc)
 
 	return scope
 }
@@ -587,6 +632,10 @@ func (c *BuiltinEvalContext) ImportResolver() *ImportResolver {
 	return c.ImportResolverValue
 }
 
+func (c *BuiltinEvalContext) Deferrals() *Deferrals {
+	return c.DeferralsValue
+}
+
 func (c *BuiltinEvalContext) GetEncryption() encryption.Encryption {
 	return c.Encryption
 }
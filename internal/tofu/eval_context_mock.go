@@ -75,6 +75,12 @@ type MockEvalContext struct {
 	ConfigureProviderConfig cty.Value
 	ConfigureProviderDiags  tfdiags.Diagnostics
 
+	ProviderConfigUnknownCalled bool
+	ProviderConfigUnknownResult bool
+
+	ProviderConfigValueCalled bool
+	ProviderConfigValueResult cty.Value
+
 	ProvisionerCalled      bool
 	ProvisionerName        string
 	ProvisionerProvisioner provisioners.Interface
@@ -158,6 +164,9 @@ type MockEvalContext struct {
 	ImportResolverCalled  bool
 	ImportResolverResults *ImportResolver
 
+	DeferralsCalled  bool
+	DeferralsResults *Deferrals
+
 	InstanceExpanderCalled   bool
 	InstanceExpanderExpander *instances.Expander
 }
@@ -221,6 +230,16 @@ func (c *MockEvalContext) ConfigureProvider(_ context.Context, addr addrs.AbsPro
 	return c.ConfigureProviderDiags
 }
 
+func (c *MockEvalContext) ProviderConfigUnknown(addrs.AbsProviderConfig, addrs.InstanceKey) bool {
+	c.ProviderConfigUnknownCalled = true
+	return c.ProviderConfigUnknownResult
+}
+
+func (c *MockEvalContext) ProviderConfigValue(addrs.AbsProviderConfig, addrs.InstanceKey) cty.Value {
+	c.ProviderConfigValueCalled = true
+	return c.ProviderConfigValueResult
+}
+
 func (c *MockEvalContext) ProviderInput(_ context.Context, addr addrs.AbsProviderConfig) map[string]cty.Value {
 	c.ProviderInputCalled = true
 	c.ProviderInputAddr = addr
@@ -411,6 +430,11 @@ func (c *MockEvalContext) ImportResolver() *ImportResolver {
 	return c.ImportResolverResults
 }
 
+func (c *MockEvalContext) Deferrals() *Deferrals {
+	c.DeferralsCalled = true
+	return c.DeferralsResults
+}
+
 func (c *MockEvalContext) InstanceExpander() *instances.Expander {
 	c.InstanceExpanderCalled = true
 	return c.InstanceExpanderExpander
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// validateEphemeralResourceConfig returns error diagnostics for any ephemeral
+// values in the given resource configuration that aren't assigned to
+// write-only attributes, because all of the other attributes of a resource
+// instance are persisted in the plan and state.
+func validateEphemeralResourceConfig(schema *configschema.Block, configVal cty.Value, body hcl.Body, addr string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	_, pvms := configVal.UnmarkDeepWithPaths()
+	for _, pvm := range pvms {
+		if _, ok := pvm.Marks[marks.Ephemeral]; !ok {
+			continue
+		}
+		if schema.IsWriteOnlyPath(pvm.Path) {
+			continue
+		}
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid use of ephemeral value",
+			"Ephemeral values are not persisted in the plan or state, so they can only be assigned to write-only resource arguments.",
+			pvm.Path,
+		))
+	}
+	return diags.InConfigBody(body, addr)
+}
+
+// unmarkEphemeral returns a copy of the given value with any ephemeral marks
+// removed, leaving all other marks intact.
+//
+// Once a resource configuration has been checked by
+// validateEphemeralResourceConfig its ephemeral values can only be in
+// write-only attributes, which are always null in the results from the
+// provider, and so the ephemeral marks don't need to be carried any further.
+func unmarkEphemeral(val cty.Value) cty.Value {
+	return removeMarkDeep(val, marks.Ephemeral)
+}
+
+// removeMarkDeep returns a copy of the given value with the given mark
+// removed from it and from any nested values, leaving all other marks intact.
+func removeMarkDeep(val cty.Value, mark any) cty.Value {
+	unmarked, pvms := val.UnmarkDeepWithPaths()
+	ret := make([]cty.PathValueMarks, 0, len(pvms))
+	for _, pvm := range pvms {
+		delete(pvm.Marks, mark)
+		if len(pvm.Marks) != 0 {
+			ret = append(ret, pvm)
+		}
+	}
+	return unmarked.MarkWithPaths(ret)
+}
@@ -49,3 +49,17 @@ func evaluateCountExpression(ctx context.Context, expr hcl.Expression, evalCtx E
 func evaluateCountExpressionValue(ctx context.Context, expr hcl.Expression, evalCtx EvalContext) (cty.Value, tfdiags.Diagnostics) {
 	return evalchecks.EvaluateCountExpressionValue(expr, evalContextEvaluate(ctx, evalCtx))
 }
+
+func evalContextEvaluateBool(ctx context.Context, evalCtx EvalContext) evalchecks.EvaluateFunc {
+	return func(expr hcl.Expression) (cty.Value, tfdiags.Diagnostics) {
+		return evalCtx.EvaluateExpr(ctx, expr, cty.Bool, nil)
+	}
+}
+
+func evaluateEnabledExpression(ctx context.Context, expr hcl.Expression, evalCtx EvalContext, excludeableAddr addrs.Targetable) (bool, tfdiags.Diagnostics) {
+	return evalchecks.EvaluateEnabledExpression(expr, evalContextEvaluateBool(ctx, evalCtx), excludeableAddr)
+}
+
+func evaluateEnabledExpressionValue(ctx context.Context, expr hcl.Expression, evalCtx EvalContext) (cty.Value, tfdiags.Diagnostics) {
+	return evalchecks.EvaluateEnabledExpressionValue(expr, evalContextEvaluateBool(ctx, evalCtx))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/instances"
+	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/lang/evalchecks"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -49,14 +50,19 @@ func evaluateImportIdExpression(expr hcl.Expression, evalCtx EvalContext, keyDat
 	}
 
 	if !importIdVal.IsKnown() {
+		// We build the evaluation context again only so that the diagnostic
+		// can describe which of the referenced values are unknown.
+		scope := evalCtx.EvaluationScope(nil, nil, keyData)
+		refs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+		hclCtx, _ := scope.EvalContext(context.TODO(), refs)
 		return "", diags.Append(&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Invalid import id argument",
-			Detail:   `The import block "id" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.`, // FIXME and what should I do about that?
-			Subject:  expr.Range().Ptr(),
-			//	Expression:
-			//	EvalContext:
-			Extra: evalchecks.DiagnosticCausedByUnknown(true),
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid import id argument",
+			Detail:      `The import block "id" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.` + "\n\n" + `To work around this, use the -target option to first apply only the resources that the import ID depends on, and then plan again to import this resource.`,
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: hclCtx,
+			Extra:       evalchecks.DiagnosticCausedByUnknown(true),
 		})
 	}
 
@@ -44,7 +44,7 @@ func TestEvaluateImportIdExpression_SensitiveValue(t *testing.T) {
 		{
 			name:    "evaluates_to_unknown",
 			expr:    hcltest.MockExprLiteral(cty.UnknownVal(cty.String)),
-			wantErr: "Invalid import id argument: The import block \"id\" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.", // Adapted the message from your original code
+			wantErr: "Invalid import id argument: The import block \"id\" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.\n\nTo work around this, use the -target option to first apply only the resources that the import ID depends on, and then plan again to import this resource.", // Adapted the message from your original code
 		},
 		{
 			name:    "valid_value",
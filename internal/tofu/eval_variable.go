@@ -244,6 +244,9 @@ func evalVariableValidations(ctx context.Context, addr addrs.AbsInputVariableIns
 		// value, so we need to apply this mark separately.
 		val = val.Mark(marks.Sensitive)
 	}
+	if config.Ephemeral {
+		val = val.Mark(marks.Ephemeral)
+	}
 	for ix, validation := range config.Validations {
 		condRefs, condDiags := lang.ReferencesInExpr(addrs.ParseRef, validation.Condition)
 		diags = diags.Append(condDiags)
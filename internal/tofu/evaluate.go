@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -69,6 +70,56 @@ type Evaluator struct {
 	Changes *plans.ChangesSync
 
 	PlanTimestamp time.Time
+
+	// Deferrals tracks the resources whose planning is deferred to a later
+	// plan, which references evaluate as unknown. It may be nil.
+	Deferrals *Deferrals
+
+	userFunctionsLock sync.Mutex
+	userFunctions     map[*configs.Config]*lang.UserFunctions
+}
+
+// UserFunctions returns the user-defined functions declared in the given
+// module and its descendants, in the form expected by lang.Scope.
+//
+// The result is cached, because it's needed for every scope in the module.
+func (e *Evaluator) UserFunctions(mc *configs.Config) *lang.UserFunctions {
+	if mc == nil || mc.Module == nil {
+		return nil
+	}
+	e.userFunctionsLock.Lock()
+	defer e.userFunctionsLock.Unlock()
+	return e.userFunctionsLocked(mc)
+}
+
+func (e *Evaluator) userFunctionsLocked(mc *configs.Config) *lang.UserFunctions {
+	if ret, ok := e.userFunctions[mc]; ok {
+		return ret
+	}
+
+	ret := &lang.UserFunctions{
+		Funcs:    make(map[string]*lang.UserFunction, len(mc.Module.Functions)),
+		Children: make(map[string]*lang.UserFunctions, len(mc.Children)),
+	}
+	for name, f := range mc.Module.Functions {
+		ret.Funcs[name] = &lang.UserFunction{
+			Description: f.Description,
+			Params:      f.Params,
+			Result:      f.Result,
+			Export:      f.Export,
+		}
+	}
+	for callName, child := range mc.Children {
+		if child.Module != nil {
+			ret.Children[callName] = e.userFunctionsLocked(child)
+		}
+	}
+
+	if e.userFunctions == nil {
+		e.userFunctions = make(map[*configs.Config]*lang.UserFunctions)
+	}
+	e.userFunctions[mc] = ret
+	return ret
 }
 
 // Scope creates an evaluation scope for the given module path and optional
@@ -266,11 +317,16 @@ func (d *evaluationStateData) GetInputVariable(_ context.Context, addr addrs.Inp
 	// being liberal in what it accepts because the subsequent plan walk has
 	// more information available and so can be more conservative.
 	if d.Operation == walkValidate {
-		// Ensure variable sensitivity is captured in the validate walk
+		// Ensure variable sensitivity and ephemerality are captured in the
+		// validate walk
+		val := cty.UnknownVal(config.Type)
 		if config.Sensitive {
-			return cty.UnknownVal(config.Type).Mark(marks.Sensitive), diags
+			val = val.Mark(marks.Sensitive)
 		}
-		return cty.UnknownVal(config.Type), diags
+		if config.Ephemeral {
+			val = val.Mark(marks.Ephemeral)
+		}
+		return val, diags
 	}
 
 	moduleAddrStr := d.ModulePath.String()
@@ -308,6 +364,9 @@ func (d *evaluationStateData) GetInputVariable(_ context.Context, addr addrs.Inp
 	if config.Sensitive {
 		val = val.Mark(marks.Sensitive)
 	}
+	if config.Ephemeral {
+		val = val.Mark(marks.Ephemeral)
+	}
 
 	return val, diags
 }
@@ -353,6 +412,20 @@ func (d *evaluationStateData) GetLocalValue(_ context.Context, addr addrs.LocalV
 	return val, diags
 }
 
+// redeclareOutputSensitivity applies the "sensitive_outputs" argument of the
+// given module call, if it's set, to the value of the named output of the
+// child module: outputs that are listed are marked as sensitive, and any
+// sensitive marks are removed from all of the others.
+func redeclareOutputSensitivity(val cty.Value, name string, call *configs.ModuleCall) cty.Value {
+	if call.SensitiveOutputs == nil {
+		return val
+	}
+	if slices.Contains(call.SensitiveOutputs, name) {
+		return val.Mark(marks.Sensitive)
+	}
+	return removeMarkDeep(val, marks.Sensitive)
+}
+
 func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall, rng tfdiags.SourceRange) (cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	// Output results live in the module that declares them, which is one of
@@ -396,6 +469,8 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 			val = marks.DeprecatedOutput(val, output.Addr, output.Deprecated, parentCfg.IsModuleCallFromRemoteModule(addr.Name))
 		}
 
+		val = redeclareOutputSensitivity(val, output.Addr.OutputValue.Name, callConfig)
+
 		_, callInstance := output.Addr.Module.CallInstance()
 		instance, ok := stateMap[callInstance.Key]
 		if !ok {
@@ -430,8 +505,11 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 	// the structure is based on the configuration, so iterate through all the
 	// defined outputs, and add any instance state or changes we find.
 	for _, cfg := range outputConfigs {
-		// record the output names for validation
+		// record the output names and any declared types for validation
 		unknownMap[cfg.Name] = cty.DynamicPseudoType
+		if cfg.Type != cty.NilType {
+			unknownMap[cfg.Name] = cfg.Type
+		}
 
 		// get all instance output for this path from the state
 		for key, states := range stateMap {
@@ -480,6 +558,8 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 			if cfg.Deprecated != "" {
 				instance[cfg.Name] = marks.DeprecatedOutput(change.After, change.Addr, cfg.Deprecated, parentCfg.IsModuleCallFromRemoteModule(addr.Name))
 			}
+
+			instance[cfg.Name] = redeclareOutputSensitivity(instance[cfg.Name], cfg.Name, callConfig)
 		}
 	}
 
@@ -542,13 +622,17 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 			ret = cty.EmptyObjectVal
 		}
 
+	case callConfig.Enabled != nil && len(moduleInstances) == 0 && len(outputConfigs) != 0:
+		// A disabled module call has no instances, and so its value is null.
+		ret = cty.NullVal(cty.DynamicPseudoType)
+
 	default:
 		val, ok := moduleInstances[addrs.NoKey]
 		if !ok {
 			// create the object if there wasn't one known
 			val = map[string]cty.Value{}
 			for k := range outputConfigs {
-				val[k] = cty.DynamicVal
+				val[k] = cty.UnknownVal(unknownMap[k])
 			}
 		}
 
@@ -673,6 +757,13 @@ func (d *evaluationStateData) GetResource(ctx context.Context, addr addrs.Resour
 		return cty.DynamicVal, diags
 	}
 
+	// A deferred resource won't have any planned instances until a later
+	// plan, so we'll mark its unknown value to let the parts of the
+	// configuration that depend on it know that they must be deferred too.
+	if d.Evaluator.Deferrals.IsDeferred(addr.Absolute(d.ModulePath)) {
+		return deferredResourceValue, diags
+	}
+
 	// Build the provider address from configuration, since we may not have
 	// state available in all cases.
 	// We need to build an abs provider address, but we can use a default
@@ -704,6 +795,10 @@ func (d *evaluationStateData) GetResource(ctx context.Context, addr addrs.Resour
 				return cty.EmptyTupleVal, diags
 			case config.ForEach != nil:
 				return cty.EmptyObjectVal, diags
+			case config.Enabled != nil:
+				// A disabled resource has no instances, and so its value
+				// is null.
+				return cty.NullVal(ty), diags
 			default:
 				// While we can reference an expanded resource with 0
 				// instances, we cannot reference instances that do not exist.
@@ -913,7 +1008,11 @@ func (d *evaluationStateData) GetResource(ctx context.Context, addr addrs.Resour
 
 	default:
 		val, ok := instances[addrs.NoKey]
-		if !ok {
+		switch {
+		case !ok && config.Enabled != nil:
+			// a disabled resource has no instances, and so its value is null
+			val = cty.NullVal(ty)
+		case !ok:
 			// if the instance is missing, insert an unknown value
 			val = cty.UnknownVal(ty)
 		}
@@ -112,7 +112,11 @@ func (d *evaluationStateData) staticValidateReference(ctx context.Context, ref *
 			// but is close enough for our purposes.
 			SrcRange: ref.SourceRange.ToHCL(),
 		}
-		return d.staticValidateModuleCallReference(modCfg, addr.Call.Call, remain, ref.SourceRange)
+		diags := d.staticValidateModuleCallReference(modCfg, addr.Call.Call, remain, ref.SourceRange)
+		if diags.HasErrors() {
+			return diags
+		}
+		return d.staticValidateModuleCallOutputReference(modCfg, addr, ref.SourceRange)
 
 	default:
 		// Anything else we'll just permit through without any static validation
@@ -219,6 +223,22 @@ func (d *evaluationStateData) staticValidateResourceReference(ctx context.Contex
 				suggestion = fmt.Sprintf("\n\nDid you mean the data resource %s?", candidateAddr)
 			}
 		}
+		if suggestion == "" {
+			// Otherwise we'll look for a similarly-named resource of the
+			// same mode, to catch typos in either the type or the name.
+			resources := modCfg.Module.ManagedResources
+			if addr.Mode == addrs.DataResourceMode {
+				resources = modCfg.Module.DataResources
+			}
+			suggestions := make([]string, 0, len(resources))
+			for _, rc := range resources {
+				suggestions = append(suggestions, rc.Addr().String())
+			}
+			sort.Strings(suggestions)
+			if candidate := didyoumean.NameSuggestion(addr.String(), suggestions); candidate != "" {
+				suggestion = fmt.Sprintf(" Did you mean %s?", candidate)
+			}
+		}
 
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -319,6 +339,47 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 	return diags
 }
 
+func (d *evaluationStateData) staticValidateModuleCallOutputReference(modCfg *configs.Config, addr addrs.ModuleCallInstanceOutput, rng tfdiags.SourceRange) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	childCfg := modCfg.Children[addr.Call.Call.Name]
+	if childCfg == nil {
+		// The child module might not have been installed yet, in which case
+		// a different layer will report the problem.
+		return diags
+	}
+
+	// If the module call has "count" or "for_each" set but the reference has
+	// no instance key then the "output name" is actually an instance key,
+	// as in module.foo.bar where module.foo has for_each = {bar = ...}, and
+	// so we can't check it statically.
+	callCfg := modCfg.Module.ModuleCalls[addr.Call.Call.Name]
+	if addr.Call.Key == addrs.NoKey && (callCfg.Count != nil || callCfg.ForEach != nil) {
+		return diags
+	}
+
+	if _, exists := childCfg.Module.Outputs[addr.Name]; !exists {
+		var suggestions []string
+		for name := range childCfg.Module.Outputs {
+			suggestions = append(suggestions, name)
+		}
+		sort.Strings(suggestions)
+		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  `Unsupported attribute`,
+			Detail:   fmt.Sprintf(`This object does not have an attribute named %q.%s`, addr.Name, suggestion),
+			Subject:  rng.ToHCL().Ptr(),
+		})
+	}
+
+	return diags
+}
+
 // moduleConfigDisplayAddr returns a string describing the given module
 // address that is appropriate for returning to users in situations where the
 // root module is possible. Specifically, it returns "the root module" if the
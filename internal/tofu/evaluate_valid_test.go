@@ -46,6 +46,26 @@ func TestStaticValidateReferences(t *testing.T) {
 
 Did you mean the data resource data.beep.boop?`,
 		},
+		{
+			Ref:     "aws_instance.no_cout",
+			WantErr: `Reference to undeclared resource: A managed resource "aws_instance" "no_cout" has not been declared in the root module. Did you mean aws_instance.no_count?`,
+		},
+		{
+			Ref:     "module.child.instance_id",
+			WantErr: ``,
+		},
+		{
+			Ref:     "module.child.instance_di",
+			WantErr: `Unsupported attribute: This object does not have an attribute named "instance_di". Did you mean "instance_id"?`,
+		},
+		{
+			Ref:     "module.children.a",
+			WantErr: ``,
+		},
+		{
+			Ref:     "module.children[\"a\"].nope",
+			WantErr: `Unsupported attribute: This object does not have an attribute named "nope".`,
+		},
 		{
 			Ref:     "aws_instance.no_count[0]",
 			WantErr: `Unexpected resource instance key: Because aws_instance.no_count does not have "count" or "for_each" set, references to it must not include an index key. Remove the bracketed index to refer to the single instance of this resource.`,
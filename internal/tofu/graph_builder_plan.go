@@ -56,6 +56,18 @@ type PlanGraphBuilder struct {
 	// action instead. Create and Delete actions are not affected.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// CascadeReplace extends ForceReplace to the resource instances that
+	// depend on the ones in ForceReplace.
+	CascadeReplace bool
+
+	// PreviewProvisioners makes the resource instance nodes record previews
+	// of the provisioners that will run when their changes are applied.
+	PreviewProvisioners bool
+
+	// PlanCache, if set, lets the resource instance nodes skip planning
+	// instances whose inputs haven't changed since an earlier plan.
+	PlanCache *PlanCache
+
 	// skipRefresh indicates that we should skip refreshing managed resources
 	skipRefresh bool
 
@@ -277,6 +289,9 @@ func (b *PlanGraphBuilder) initPlan() {
 			skipPlanChanges:      b.skipPlanChanges,
 			preDestroyRefresh:    b.preDestroyRefresh,
 			forceReplace:         b.ForceReplace,
+			cascadeReplace:       b.CascadeReplace,
+			previewProvisioners:  b.PreviewProvisioners,
+			planCache:            b.PlanCache,
 		}
 	}
 
@@ -285,6 +300,7 @@ func (b *PlanGraphBuilder) initPlan() {
 			NodeAbstractResourceInstance: a,
 			skipRefresh:                  b.skipRefresh,
 			skipPlanChanges:              b.skipPlanChanges,
+			previewProvisioners:          b.PreviewProvisioners,
 			RemoveStatements:             b.RemoveStatements,
 		}
 	}
@@ -308,6 +324,7 @@ func (b *PlanGraphBuilder) initDestroy() {
 		return &NodePlanDestroyableResourceInstance{
 			NodeAbstractResourceInstance: a,
 			skipRefresh:                  b.skipRefresh,
+			previewProvisioners:          b.PreviewProvisioners,
 		}
 	}
 }
@@ -0,0 +1,327 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// GraphDiagramOpts are the options for [GraphMermaid] and [GraphD2].
+type GraphDiagramOpts struct {
+	// Verbose allows some nodes to decide to only show themselves when the
+	// user has requested the "verbose" graph, as with [dag.DotOpts].
+	Verbose bool
+
+	// Changes, if not nil, are the planned changes used to style each
+	// resource node by the action planned for its instances.
+	Changes *plans.Changes
+}
+
+// diagramActionStyles are the styles of resource nodes by planned action,
+// as fill and stroke colors. When a resource's instances have different
+// actions the node is styled by the first of them in this list.
+var diagramActionStyles = []struct {
+	class        string
+	fill, stroke string
+}{
+	{"replace", "#f3e8ff", "#8250df"},
+	{"delete", "#ffebe9", "#cf222e"},
+	{"forget", "#eaeef2", "#6e7781"},
+	{"update", "#fff8c5", "#bf8700"},
+	{"create", "#dafbe1", "#1a7f37"},
+	{"read", "#ddf4ff", "#0969da"},
+}
+
+func diagramActionClass(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update"
+	case plans.Delete:
+		return "delete"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	case plans.Read:
+		return "read"
+	case plans.Forget:
+		return "forget"
+	default:
+		return ""
+	}
+}
+
+// diagramNode is a vertex of the graph as it appears in a diagram.
+type diagramNode struct {
+	order  int
+	id     string
+	label  string
+	module addrs.Module
+	class  string
+}
+
+// diagramModule is a group of nodes in a diagram, for one module and its
+// child modules.
+type diagramModule struct {
+	id       string
+	addr     addrs.Module
+	nodes    []*diagramNode
+	children []*diagramModule
+}
+
+type diagram struct {
+	root  *diagramModule
+	edges [][2]*diagramNode
+
+	// classes are the action classes used by at least one node.
+	classes map[string]bool
+}
+
+func newDiagram(g *Graph, opts *GraphDiagramOpts) *diagram {
+	if opts == nil {
+		opts = &GraphDiagramOpts{}
+	}
+	dotOpts := &dag.DotOpts{Verbose: opts.Verbose, MaxDepth: -1}
+
+	actions := make(map[string]string)
+	if opts.Changes != nil {
+		rank := make(map[string]int)
+		for i, style := range diagramActionStyles {
+			rank[style.class] = i
+		}
+		for _, rc := range opts.Changes.Resources {
+			class := diagramActionClass(rc.Action)
+			if class == "" {
+				continue
+			}
+			key := rc.Addr.ConfigResource().String()
+			if prev, ok := actions[key]; !ok || rank[class] < rank[prev] {
+				actions[key] = class
+			}
+		}
+	}
+
+	type namedVertex struct {
+		v     dag.Vertex
+		name  string
+		label string
+	}
+	var vertices []namedVertex
+	for _, v := range g.Vertices() {
+		dotter, ok := v.(dag.GraphNodeDotter)
+		if !ok {
+			continue
+		}
+		node := dotter.DotNode(dag.VertexName(v), dotOpts)
+		if node == nil {
+			continue
+		}
+		label := node.Name
+		if l := node.Attrs["label"]; l != "" {
+			label = l
+		}
+		vertices = append(vertices, namedVertex{v, node.Name, label})
+	}
+	sort.SliceStable(vertices, func(i, j int) bool {
+		return vertices[i].name < vertices[j].name
+	})
+
+	d := &diagram{
+		root:    &diagramModule{addr: addrs.RootModule},
+		classes: make(map[string]bool),
+	}
+	modules := map[string]*diagramModule{"": d.root}
+	var moduleFor func(addr addrs.Module) *diagramModule
+	moduleFor = func(addr addrs.Module) *diagramModule {
+		if m, ok := modules[addr.String()]; ok {
+			return m
+		}
+		parent := moduleFor(addr.Parent())
+		m := &diagramModule{
+			id:   fmt.Sprintf("m%d", len(modules)-1),
+			addr: addr,
+		}
+		modules[addr.String()] = m
+		parent.children = append(parent.children, m)
+		return m
+	}
+
+	// Several vertices can represent the same object, such as a resource and
+	// the vertex that expands its instances, so we draw a single node for
+	// all of the vertices with the same label.
+	nodes := make(map[dag.Vertex]*diagramNode)
+	byLabel := make(map[string]*diagramNode)
+	for _, nv := range vertices {
+		n, ok := byLabel[nv.label]
+		if !ok {
+			n = &diagramNode{
+				order: len(byLabel),
+				id:    fmt.Sprintf("n%d", len(byLabel)),
+				label: nv.label,
+			}
+			if mp, ok := nv.v.(GraphNodeModulePath); ok {
+				n.module = mp.ModulePath()
+			}
+			byLabel[nv.label] = n
+			m := moduleFor(n.module)
+			m.nodes = append(m.nodes, n)
+		}
+		if rn, ok := nv.v.(GraphNodeConfigResource); ok && n.class == "" {
+			n.class = actions[rn.ResourceAddr().String()]
+			if n.class != "" {
+				d.classes[n.class] = true
+			}
+		}
+		nodes[nv.v] = n
+	}
+
+	// Dependencies often pass through vertices that aren't drawn, such as
+	// module input variables, so we connect each node to the nearest drawn
+	// nodes it depends on.
+	type edgeKey struct{ source, target *diagramNode }
+	seen := make(map[edgeKey]bool)
+	for _, nv := range vertices {
+		source := nodes[nv.v]
+		visited := make(map[dag.Vertex]bool)
+		var visit func(v dag.Vertex)
+		visit = func(v dag.Vertex) {
+			for _, next := range g.DownEdges(v) {
+				if visited[next] {
+					continue
+				}
+				visited[next] = true
+				target, drawn := nodes[next]
+				if !drawn {
+					visit(next)
+					continue
+				}
+				key := edgeKey{source, target}
+				if target != source && !seen[key] {
+					seen[key] = true
+					d.edges = append(d.edges, [2]*diagramNode{source, target})
+				}
+			}
+		}
+		visit(nv.v)
+	}
+	sort.Slice(d.edges, func(i, j int) bool {
+		a, b := d.edges[i], d.edges[j]
+		if a[0] != b[0] {
+			return a[0].order < b[0].order
+		}
+		return a[1].order < b[1].order
+	})
+
+	return d
+}
+
+// GraphMermaid returns a Mermaid flowchart of the given OpenTofu graph, with
+// the nodes of each module grouped in a subgraph.
+func GraphMermaid(g *Graph, opts *GraphDiagramOpts) string {
+	d := newDiagram(g, opts)
+
+	var buf strings.Builder
+	buf.WriteString("flowchart LR\n")
+	var writeModule func(m *diagramModule, indent string)
+	writeModule = func(m *diagramModule, indent string) {
+		for _, n := range m.nodes {
+			fmt.Fprintf(&buf, "%s%s[%s]\n", indent, n.id, mermaidString(n.label))
+		}
+		for _, child := range m.children {
+			fmt.Fprintf(&buf, "%ssubgraph %s [%s]\n", indent, child.id, mermaidString(child.addr.String()))
+			writeModule(child, indent+"  ")
+			fmt.Fprintf(&buf, "%send\n", indent)
+		}
+	}
+	writeModule(d.root, "  ")
+	for _, e := range d.edges {
+		fmt.Fprintf(&buf, "  %s --> %s\n", e[0].id, e[1].id)
+	}
+
+	for _, style := range diagramActionStyles {
+		if !d.classes[style.class] {
+			continue
+		}
+		fmt.Fprintf(&buf, "  classDef %s fill:%s,stroke:%s\n", style.class, style.fill, style.stroke)
+		var ids []string
+		d.root.walk(func(n *diagramNode) {
+			if n.class == style.class {
+				ids = append(ids, n.id)
+			}
+		})
+		fmt.Fprintf(&buf, "  class %s %s\n", strings.Join(ids, ","), style.class)
+	}
+	return buf.String()
+}
+
+// GraphD2 returns a D2 diagram of the given OpenTofu graph, with the nodes
+// of each module grouped in a container.
+func GraphD2(g *Graph, opts *GraphDiagramOpts) string {
+	d := newDiagram(g, opts)
+
+	var buf strings.Builder
+	buf.WriteString("direction: right\n")
+	if len(d.classes) != 0 {
+		buf.WriteString("classes: {\n")
+		for _, style := range diagramActionStyles {
+			if d.classes[style.class] {
+				fmt.Fprintf(&buf, "  %s: {style: {fill: %q; stroke: %q}}\n", style.class, style.fill, style.stroke)
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	// D2 refers to shapes inside containers by their dotted path from the
+	// top level, so we record the path of each node as we write it.
+	paths := make(map[*diagramNode]string)
+	var writeModule func(m *diagramModule, prefix, indent string)
+	writeModule = func(m *diagramModule, prefix, indent string) {
+		for _, n := range m.nodes {
+			paths[n] = prefix + n.id
+			fmt.Fprintf(&buf, "%s%s: %s", indent, n.id, d2String(n.label))
+			if n.class != "" {
+				fmt.Fprintf(&buf, " {class: %s}", n.class)
+			}
+			buf.WriteString("\n")
+		}
+		for _, child := range m.children {
+			fmt.Fprintf(&buf, "%s%s: %s {\n", indent, child.id, d2String(child.addr.String()))
+			writeModule(child, prefix+child.id+".", indent+"  ")
+			fmt.Fprintf(&buf, "%s}\n", indent)
+		}
+	}
+	writeModule(d.root, "", "")
+	for _, e := range d.edges {
+		fmt.Fprintf(&buf, "%s -> %s\n", paths[e[0]], paths[e[1]])
+	}
+	return buf.String()
+}
+
+func (m *diagramModule) walk(cb func(n *diagramNode)) {
+	for _, n := range m.nodes {
+		cb(n)
+	}
+	for _, child := range m.children {
+		child.walk(cb)
+	}
+}
+
+// mermaidString quotes s as a Mermaid label. Mermaid has no escape sequence
+// for quotes inside a quoted label, so we use its entity code instead.
+func mermaidString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// d2String quotes s as a D2 label.
+func d2String(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
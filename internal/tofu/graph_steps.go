@@ -0,0 +1,148 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// ApplyStepOperation is what a step of an apply graph does to its resource
+// instance object.
+type ApplyStepOperation string
+
+const (
+	// ApplyStepApply creates, updates, or reads the object, including the
+	// creation half of a replacement.
+	ApplyStepApply ApplyStepOperation = "apply"
+
+	// ApplyStepDestroy destroys the object, including the destruction half
+	// of a replacement.
+	ApplyStepDestroy ApplyStepOperation = "destroy"
+
+	// ApplyStepForget removes the object from the state without destroying
+	// it.
+	ApplyStepForget ApplyStepOperation = "forget"
+)
+
+// ApplyStep is a node of an apply graph that changes a single resource
+// instance object, as returned by ApplySteps.
+type ApplyStep struct {
+	Addr       addrs.AbsResourceInstance
+	DeposedKey states.DeposedKey
+	Operation  ApplyStepOperation
+
+	// Provider is the provider configuration that the step uses.
+	Provider addrs.AbsProviderConfig
+
+	// DependsOn are the steps that must complete before this one can start,
+	// either because the graph connects them directly or because they're
+	// connected through nodes that aren't steps, such as local values and
+	// module outputs. Dependencies that are implied by the other
+	// dependencies, because one of those depends on them in turn, might be
+	// left out.
+	DependsOn []*ApplyStep
+}
+
+// ApplySteps returns the steps of the given apply graph, as returned by
+// Context.ApplyGraphForUI, sorted by address. The other nodes of the graph
+// are internal details of how OpenTofu evaluates the configuration, so they
+// only contribute the dependencies between the steps.
+func ApplySteps(g *Graph) []*ApplyStep {
+	steps := make(map[dag.Vertex]*ApplyStep)
+	var ret []*ApplyStep
+	for _, v := range g.Vertices() {
+		if step := applyStepForVertex(v); step != nil {
+			steps[v] = step
+			ret = append(ret, step)
+		}
+	}
+
+	// reachable memoizes the steps that each vertex depends on without
+	// passing through another step, since the nodes in between are often
+	// shared by many steps.
+	reachable := make(map[dag.Vertex][]*ApplyStep)
+	var visit func(v dag.Vertex) []*ApplyStep
+	visit = func(v dag.Vertex) []*ApplyStep {
+		if deps, ok := reachable[v]; ok {
+			return deps
+		}
+		seen := make(map[*ApplyStep]bool)
+		var deps []*ApplyStep
+		add := func(step *ApplyStep) {
+			if !seen[step] {
+				seen[step] = true
+				deps = append(deps, step)
+			}
+		}
+		for _, raw := range g.DownEdges(v) {
+			dep := raw.(dag.Vertex)
+			if step, ok := steps[dep]; ok {
+				add(step)
+				continue
+			}
+			for _, step := range visit(dep) {
+				add(step)
+			}
+		}
+		reachable[v] = deps
+		return deps
+	}
+	for v, step := range steps {
+		step.DependsOn = visit(v)
+		sortApplySteps(step.DependsOn)
+	}
+
+	sortApplySteps(ret)
+	return ret
+}
+
+func applyStepForVertex(v dag.Vertex) *ApplyStep {
+	var abstract *NodeAbstractResourceInstance
+	step := &ApplyStep{}
+	switch n := v.(type) {
+	case *NodeApplyableResourceInstance:
+		abstract = n.NodeAbstractResourceInstance
+		step.Operation = ApplyStepApply
+	case *NodeDestroyResourceInstance:
+		abstract = n.NodeAbstractResourceInstance
+		step.Operation = ApplyStepDestroy
+		step.DeposedKey = n.DeposedKey
+	case *NodeDestroyDeposedResourceInstanceObject:
+		abstract = n.NodeAbstractResourceInstance
+		step.Operation = ApplyStepDestroy
+		step.DeposedKey = n.DeposedKey
+	case *NodeForgetResourceInstance:
+		abstract = n.NodeAbstractResourceInstance
+		step.Operation = ApplyStepForget
+		step.DeposedKey = n.DeposedKey
+	case *NodeForgetDeposedResourceInstanceObject:
+		abstract = n.NodeAbstractResourceInstance
+		step.Operation = ApplyStepForget
+		step.DeposedKey = n.DeposedKey
+	default:
+		return nil
+	}
+	step.Addr = abstract.Addr
+	step.Provider = abstract.ResolvedProvider.ProviderConfig
+	return step
+}
+
+func sortApplySteps(steps []*ApplyStep) {
+	sort.Slice(steps, func(i, j int) bool {
+		a, b := steps[i], steps[j]
+		if !a.Addr.Equal(b.Addr) {
+			return a.Addr.Less(b.Addr)
+		}
+		if a.DeposedKey != b.DeposedKey {
+			return a.DeposedKey < b.DeposedKey
+		}
+		return a.Operation < b.Operation
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestApplySteps(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "new"
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+locals {
+  a = test_object.a.test_string
+}
+
+resource "test_object" "b" {
+  test_string = local.a
+}
+`,
+	})
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.a").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectTainted,
+			AttrsJSON: []byte(`{"test_string":"old"}`),
+		},
+		mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		addrs.NoKey,
+	)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.gone").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"gone"}`),
+		},
+		mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		addrs.NoKey,
+	)
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+	})
+	assertNoErrors(t, diags)
+
+	g, diags := ctx.ApplyGraphForUI(plan, m)
+	assertNoErrors(t, diags)
+
+	// The deposed key of the create_before_destroy replacement is chosen
+	// while building the graph, so we describe the steps without it.
+	describe := func(step *ApplyStep) string {
+		ret := fmt.Sprintf("%s %s", step.Addr, step.Operation)
+		if step.DeposedKey != states.NotDeposed {
+			ret += " (deposed)"
+		}
+		return ret
+	}
+	got := make(map[string][]string)
+	for _, step := range ApplySteps(g) {
+		if step.Provider.String() != `provider["registry.opentofu.org/hashicorp/test"]` {
+			t.Errorf("wrong provider for %s: %s", describe(step), step.Provider)
+		}
+		deps := []string{}
+		for _, dep := range step.DependsOn {
+			deps = append(deps, describe(dep))
+		}
+		got[describe(step)] = deps
+	}
+	want := map[string][]string{
+		"test_object.a apply": {},
+		// A create_before_destroy replacement destroys the old object only
+		// once the objects that depend on it have been updated, which also
+		// implies that the new object has been created.
+		"test_object.a destroy (deposed)": {"test_object.b apply"},
+		// The dependency through the local value is included.
+		"test_object.b apply":      {"test_object.a apply"},
+		"test_object.gone destroy": {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong steps\n%s", diff)
+	}
+}
@@ -39,6 +39,7 @@ type ContextGraphWalker struct {
 	Checks                  *checks.State           // Used for safe concurrent writes of checkable objects and their check results
 	InstanceExpander        *instances.Expander     // Tracks our gradual expansion of module and resource instances
 	ImportResolver          *ImportResolver         // Tracks import targets as they are being resolved
+	Deferrals               *Deferrals              // Tracks resources whose planning is deferred to a later plan
 	MoveResults             refactoring.MoveResults // Read-only record of earlier processing of move statements
 	Operation               walkOperation
 	StopContext             context.Context
@@ -56,11 +57,16 @@ type ContextGraphWalker struct {
 	contextLock sync.Mutex
 	contexts    map[string]*BuiltinEvalContext
 
+	// providerFunctionResults memoizes provider function calls across all
+	// of the evaluation contexts of the walk.
+	providerFunctionResults *providerFunctionResults
+
 	variableValuesLock sync.Mutex
 	variableValues     map[string]map[string]cty.Value
 
-	providerLock  sync.Mutex
-	providerCache map[string]map[addrs.InstanceKey]providers.Interface
+	providerLock         sync.Mutex
+	providerCache        map[string]map[addrs.InstanceKey]providers.Interface
+	providerConfigValues map[string]map[addrs.InstanceKey]cty.Value
 
 	provisionerLock  sync.Mutex
 	provisionerCache map[string]provisioners.Interface
@@ -99,6 +105,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValues:     w.variableValues,
 		VariableValuesLock: &w.variableValuesLock,
 		PlanTimestamp:      w.PlanTimestamp,
+		Deferrals:          w.Deferrals,
 	}
 
 	ctx := &BuiltinEvalContext{
@@ -109,7 +116,9 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		Plugins:                 w.Context.plugins,
 		MoveResultsValue:        w.MoveResults,
 		ImportResolverValue:     w.ImportResolver,
+		DeferralsValue:          w.Deferrals,
 		ProviderCache:           w.providerCache,
+		ProviderConfigValues:    w.providerConfigValues,
 		ProviderInputConfig:     w.Context.providerInputConfig,
 		ProviderLock:            &w.providerLock,
 		ProvisionerCache:        w.provisionerCache,
@@ -124,6 +133,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValuesLock:      &w.variableValuesLock,
 		Encryption:              w.Encryption,
 		ProviderFunctionTracker: w.ProviderFunctionTracker,
+		ProviderFunctionResults: w.providerFunctionResults,
 	}
 
 	return ctx
@@ -131,7 +141,9 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 
 func (w *ContextGraphWalker) init() {
 	w.contexts = make(map[string]*BuiltinEvalContext)
+	w.providerFunctionResults = newProviderFunctionResults()
 	w.providerCache = make(map[string]map[addrs.InstanceKey]providers.Interface)
+	w.providerConfigValues = make(map[string]map[addrs.InstanceKey]cty.Value)
 	w.provisionerCache = make(map[string]provisioners.Interface)
 	w.variableValues = make(map[string]map[string]cty.Value)
 
@@ -144,9 +156,51 @@ func (w *ContextGraphWalker) init() {
 }
 
 func (w *ContextGraphWalker) Execute(ctx context.Context, evalCtx EvalContext, n GraphNodeExecutable) tfdiags.Diagnostics {
+	// If the node belongs to a provider with its own parallelism limit then
+	// we wait for that first, so that nodes queued behind a rate-limited
+	// provider don't hold slots that other providers' nodes could use.
+	if consumer, ok := n.(GraphNodeProviderConsumer); ok {
+		if sem, ok := w.Context.providerSems[consumer.Provider()]; ok {
+			sem.Acquire()
+			defer sem.Release()
+		}
+	}
+
 	// Acquire a lock on the semaphore
-	w.Context.parallelSem.Acquire()
-	defer w.Context.parallelSem.Release()
+	sem := w.refreshSemaphore(n)
+	if sem == nil {
+		sem = w.Context.parallelSem
+	}
+	sem.Acquire()
+	defer sem.Release()
 
 	return n.Execute(ctx, evalCtx, w.Operation)
 }
+
+// refreshSemaphore returns the semaphore of the pool for the provider
+// configuration of the given node if it's a resource node in a plan walk and
+// the context has a refresh parallelism limit, or nil if the node uses the
+// shared pool instead.
+func (w *ContextGraphWalker) refreshSemaphore(n GraphNodeExecutable) Semaphore {
+	if w.Context.refreshParallelism == 0 || w.Operation != walkPlan {
+		return nil
+	}
+	consumer, ok := n.(GraphNodeProviderConsumer)
+	if !ok {
+		return nil
+	}
+	config, ok := consumer.ProvidedBy().ProviderConfig.(addrs.AbsProviderConfig)
+	if !ok {
+		return nil
+	}
+
+	key := config.String()
+	w.Context.refreshSemsLock.Lock()
+	defer w.Context.refreshSemsLock.Unlock()
+	sem, ok := w.Context.refreshSems[key]
+	if !ok {
+		sem = NewSemaphore(w.Context.refreshParallelism)
+		w.Context.refreshSems[key] = sem
+	}
+	return sem
+}
@@ -7,8 +7,10 @@ package tofu
 
 import (
 	"context"
+	"fmt"
 	"log"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -151,27 +153,57 @@ func (n *nodeCheckAssert) Path() addrs.ModuleInstance {
 	return n.addr.Module
 }
 
-func (n *nodeCheckAssert) Execute(ctx context.Context, evalCtx EvalContext, _ walkOperation) tfdiags.Diagnostics {
+func (n *nodeCheckAssert) Execute(ctx context.Context, evalCtx EvalContext, op walkOperation) tfdiags.Diagnostics {
 
 	// We only want to actually execute the checks during specific
 	// operations, such as plan and applies.
 	if n.executeChecks {
+		// Failures of checks with severity "error" fail the apply, but we
+		// still report them only as warnings while planning because the
+		// checked objects might change when the plan is applied.
+		failSeverity := tfdiags.Warning
+		if n.config.Severity == configs.CheckSeverityError && op == walkApply {
+			failSeverity = tfdiags.Error
+		}
+
 		if status := evalCtx.Checks().ObjectCheckStatus(n.addr); status == checks.StatusFail || status == checks.StatusError {
 			// This check is already failing, so we won't try and evaluate it.
 			// This typically means there was an error in a data block within
-			// the check block.
-			return nil
+			// the check block, which has already been reported as a warning.
+			var diags tfdiags.Diagnostics
+			if status == checks.StatusFail && failSeverity == tfdiags.Error {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Check block failed",
+					Detail:   fmt.Sprintf("The data resource in %s could not be read, and the check has severity \"error\".", n.addr),
+					Subject:  n.config.DeclRange.Ptr(),
+				})
+			}
+			return diags
 		}
 
-		return evalCheckRules(
+		diags := evalCheckRules(
 			ctx,
 			addrs.CheckAssertion,
 			n.config.Asserts,
 			evalCtx,
 			n.addr,
 			EvalDataForNoInstanceKey,
-			tfdiags.Warning)
-
+			failSeverity)
+
+		if n.config.Severity == configs.CheckSeverityInfo {
+			// Failures of checks with severity "info" are recorded only in
+			// the check results, so we discard the warnings about them.
+			var filtered tfdiags.Diagnostics
+			for _, diag := range diags {
+				if _, ok := addrs.DiagnosticOriginatesFromCheckRule(diag); ok && diag.Severity() == tfdiags.Warning {
+					continue
+				}
+				filtered = filtered.Append(diag)
+			}
+			diags = filtered
+		}
+		return diags
 	}
 
 	// Otherwise let's still validate the config and references and return
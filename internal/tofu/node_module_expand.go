@@ -55,7 +55,7 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 
 	refs = append(refs, n.DependsOn()...)
 
-	// Expansion only uses the count and for_each expressions, so this
+	// Expansion only uses the count, for_each, and enabled expressions, so this
 	// particular graph node only refers to those.
 	// Individual variable values in the module call definition might also
 	// refer to other objects, but that's handled by
@@ -74,6 +74,10 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 		forEachRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, n.ModuleCall.ForEach)
 		refs = append(refs, forEachRefs...)
 	}
+	if n.ModuleCall.Enabled != nil {
+		enabledRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, n.ModuleCall.Enabled)
+		refs = append(refs, enabledRefs...)
+	}
 
 	for _, passed := range n.ModuleCall.Providers {
 		if passed.InParent.KeyExpression != nil {
@@ -139,6 +143,14 @@ func (n *nodeExpandModule) Execute(ctx context.Context, evalCtx EvalContext, op
 			}
 			expander.SetModuleForEach(module, call, forEach)
 
+		case n.ModuleCall.Enabled != nil:
+			enabled, enDiags := evaluateEnabledExpression(ctx, n.ModuleCall.Enabled, evalCtx, module)
+			diags = diags.Append(enDiags)
+			if diags.HasErrors() {
+				return diags
+			}
+			expander.SetModuleEnabled(module, call, enabled)
+
 		default:
 			expander.SetModuleSingle(module, call)
 		}
@@ -271,6 +283,10 @@ func (n *nodeValidateModule) Execute(ctx context.Context, evalCtx EvalContext, o
 			const tupleNotAllowed = false
 			_, forEachDiags := evaluateForEachExpressionValue(ctx, n.ModuleCall.ForEach, evalCtx, unknownsAllowed, tupleNotAllowed, module)
 			diags = diags.Append(forEachDiags)
+
+		case n.ModuleCall.Enabled != nil:
+			_, enabledDiags := evaluateEnabledExpressionValue(ctx, n.ModuleCall.Enabled, evalCtx)
+			diags = diags.Append(enabledDiags)
 		}
 
 		diags = diags.Append(validateDependsOn(ctx, evalCtx, n.ModuleCall.DependsOn))
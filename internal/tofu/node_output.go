@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -354,6 +355,11 @@ func (n *NodeApplyableOutput) Execute(ctx context.Context, evalCtx EvalContext,
 			var evalDiags tfdiags.Diagnostics
 			val, evalDiags = evalCtx.EvaluateExpr(ctx, n.Config.Expr, cty.DynamicPseudoType, nil)
 			diags = diags.Append(evalDiags)
+			if !evalDiags.HasErrors() {
+				var convDiags tfdiags.Diagnostics
+				val, convDiags = convertOutputValue(n.Config, val)
+				diags = diags.Append(convDiags)
+			}
 
 		// If the module is being overridden and we have a value to use,
 		// we just use it
@@ -388,6 +394,33 @@ If you do intend to export this data, annotate the output value as sensitive by
 				})
 			}
 		}
+
+		// Ephemeral values must never be persisted, and root module outputs
+		// are always persisted in the state, so only child module outputs
+		// can be ephemeral.
+		switch {
+		case n.Addr.Module.IsRoot() && n.Config.Ephemeral:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Ephemeral output not allowed",
+				Detail:   "Root module outputs are persisted in the state, so they can't be ephemeral. Only outputs of child modules can be declared as ephemeral.",
+				Subject:  n.Config.DeclRange.Ptr(),
+			})
+		case !n.Config.Ephemeral && marks.Contains(val, marks.Ephemeral):
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Output refers to ephemeral values",
+				Detail: `Ephemeral values are not persisted in the plan or state, so they can only be assigned to write-only resource arguments, provider configurations, provisioners, and ephemeral outputs of child modules.
+
+If this output belongs to a child module and you intend it to return an ephemeral value, add the following argument:
+    ephemeral = true`,
+				Subject: n.Config.DeclRange.Ptr(),
+			})
+		case n.Config.Ephemeral && val != cty.NilVal:
+			// An ephemeral output is ephemeral as a whole, even if its value
+			// happens not to be derived from any ephemeral values.
+			val = val.Mark(marks.Ephemeral)
+		}
 	}
 
 	// handling the interpolation error
@@ -417,6 +450,18 @@ If you do intend to export this data, annotate the output value as sensitive by
 		}
 		return diags
 	}
+
+	// A root module output that refers to a deferred resource won't have its
+	// final value until a later plan, so it's unknown while planning and
+	// keeps its previous value when applying. Child module outputs retain
+	// the mark so that whatever refers to them is deferred too.
+	if n.Addr.Module.IsRoot() && marks.Contains(val, marks.Deferred) {
+		if !n.Planning {
+			log.Printf("[TRACE] NodeApplyableOutput: not updating %s, because it refers to deferred resources", n.Addr)
+			return diags
+		}
+		val = removeMarkDeep(val, marks.Deferred)
+	}
 	n.setValue(state, changes, val)
 
 	// If we were able to evaluate a new value, we can update that in the
@@ -637,3 +682,33 @@ func (n *NodeApplyableOutput) setValue(state *states.SyncState, changes *plans.C
 
 	state.SetOutputValue(n.Addr, val, n.Config.Sensitive, n.Config.Deprecated)
 }
+
+// convertOutputValue converts the given value to the type constraint declared
+// in the given output configuration, if any, applying any default values for
+// optional object attributes along the way.
+func convertOutputValue(cfg *configs.Output, val cty.Value) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if cfg.ConstraintType == cty.NilType {
+		return val, diags
+	}
+
+	// As with input variables, we don't apply defaults to a top-level null
+	// value, so that an output can still be explicitly null.
+	if cfg.TypeDefaults != nil && !val.IsNull() {
+		val = cfg.TypeDefaults.Apply(val)
+	}
+
+	ret, err := convert.Convert(val, cfg.ConstraintType)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid output value",
+			Detail:   fmt.Sprintf("The value of output %q is not suitable for its type constraint: %s.", cfg.Name, tfdiags.FormatError(err)),
+			Subject:  cfg.Expr.Range().Ptr(),
+		})
+		// We'll return a placeholder unknown value to avoid producing
+		// redundant downstream errors.
+		return cty.UnknownVal(cfg.Type), diags
+	}
+	return ret, diags
+}
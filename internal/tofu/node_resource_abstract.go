@@ -12,6 +12,8 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/communicator/shared"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -186,6 +188,8 @@ func (n *NodeAbstractResource) References() []*addrs.Reference {
 		result = append(result, refs...)
 		refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.ForEach)
 		result = append(result, refs...)
+		refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.Enabled)
+		result = append(result, refs...)
 
 		if c.ProviderConfigRef != nil && c.ProviderConfigRef.KeyExpression != nil {
 			providerRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, c.ProviderConfigRef.KeyExpression)
@@ -225,6 +229,14 @@ func (n *NodeAbstractResource) References() []*addrs.Reference {
 				refs, _ = lang.ReferencesInBlock(addrs.ParseRef, p.Config, schema)
 				result = append(result, refs...)
 			}
+
+			for _, h := range c.Managed.Hooks {
+				if h.Event == configs.ResourceHookPreDestroy {
+					continue
+				}
+				refs, _ = lang.ReferencesInBlock(addrs.ParseRef, h.Config, n.ProvisionerSchemas[configs.ResourceHookProvisioner])
+				result = append(result, refs...)
+			}
 		}
 
 		for _, check := range c.Preconditions {
@@ -428,6 +440,9 @@ func (n *NodeAbstractResource) ProvisionedBy() []string {
 	for i, p := range n.Config.Managed.Provisioners {
 		result[i] = p.Type
 	}
+	if len(n.Config.Managed.Hooks) != 0 {
+		result = append(result, configs.ResourceHookProvisioner)
+	}
 
 	return result
 }
@@ -507,6 +522,11 @@ func (n *NodeAbstractResource) writeResourceState(ctx context.Context, evalCtx E
 
 	switch {
 	case n.Config != nil && n.Config.Count != nil:
+		if n.deferUnknownExpansion(ctx, evalCtx, addr) {
+			expander.SetResourceCount(addr.Module, n.Addr.Resource, 0)
+			return diags
+		}
+
 		count, countDiags := evaluateCountExpression(ctx, n.Config.Count, evalCtx, addr)
 		diags = diags.Append(countDiags)
 		if countDiags.HasErrors() {
@@ -517,6 +537,11 @@ func (n *NodeAbstractResource) writeResourceState(ctx context.Context, evalCtx E
 		expander.SetResourceCount(addr.Module, n.Addr.Resource, count)
 
 	case n.Config != nil && n.Config.ForEach != nil:
+		if n.deferUnknownExpansion(ctx, evalCtx, addr) {
+			expander.SetResourceForEach(addr.Module, n.Addr.Resource, map[string]cty.Value{})
+			return diags
+		}
+
 		forEach, forEachDiags := evaluateForEachExpression(ctx, n.Config.ForEach, evalCtx, addr)
 		diags = diags.Append(forEachDiags)
 		if forEachDiags.HasErrors() {
@@ -528,6 +553,16 @@ func (n *NodeAbstractResource) writeResourceState(ctx context.Context, evalCtx E
 		state.SetResourceProvider(addr, n.ResolvedProvider.ProviderConfig)
 		expander.SetResourceForEach(addr.Module, n.Addr.Resource, forEach)
 
+	case n.Config != nil && n.Config.Enabled != nil:
+		enabled, enabledDiags := evaluateEnabledExpression(ctx, n.Config.Enabled, evalCtx, addr)
+		diags = diags.Append(enabledDiags)
+		if enabledDiags.HasErrors() {
+			return diags
+		}
+
+		state.SetResourceProvider(addr, n.ResolvedProvider.ProviderConfig)
+		expander.SetResourceEnabled(addr.Module, n.Addr.Resource, enabled)
+
 	default:
 		state.SetResourceProvider(addr, n.ResolvedProvider.ProviderConfig)
 		expander.SetResourceSingle(addr.Module, n.Addr.Resource)
@@ -536,6 +571,40 @@ func (n *NodeAbstractResource) writeResourceState(ctx context.Context, evalCtx E
 	return diags
 }
 
+// deferUnknownExpansion returns true if the count or for_each argument of the
+// resource, which must have one of them, isn't known yet and the current walk
+// allows deferring the resource to a later plan, in which case it also
+// records that the resource is deferred.
+//
+// During apply this is true only for resources that the plan deferred,
+// whose arguments might still be unknown because they depend on other
+// deferred resources.
+func (n *NodeAbstractResource) deferUnknownExpansion(ctx context.Context, evalCtx EvalContext, addr addrs.AbsResource) bool {
+	deferrals := evalCtx.Deferrals()
+	if !deferrals.Allowed() && !deferrals.IsDeferred(addr) {
+		return false
+	}
+
+	var val cty.Value
+	switch {
+	case n.Config.Count != nil:
+		val, _ = evaluateCountExpressionValue(ctx, n.Config.Count, evalCtx)
+	case n.Config.ForEach != nil:
+		const unknownsAllowed = true
+		const tupleNotAllowed = false
+		val, _ = evaluateForEachExpressionValue(ctx, n.Config.ForEach, evalCtx, unknownsAllowed, tupleNotAllowed, nil)
+	}
+	// Any errors other than the value being unknown will be reported when
+	// the caller evaluates the expression again.
+	if val == cty.NilVal || val.IsKnown() {
+		return false
+	}
+
+	log.Printf("[TRACE] deferring %s, because its expansion isn't known yet", addr)
+	deferrals.Defer(addr)
+	return true
+}
+
 func isResourceMovedToDifferentType(newAddr, oldAddr addrs.AbsResourceInstance) bool {
 	return newAddr.Resource.Resource.Type != oldAddr.Resource.Resource.Type
 }
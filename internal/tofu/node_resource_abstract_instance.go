@@ -9,7 +9,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -76,6 +79,11 @@ type NodeAbstractResourceInstance struct {
 
 	preDestroyRefresh bool
 
+	// planCache, if set, lets plan skip asking the provider to plan this
+	// instance when its inputs match those of an earlier plan that had no
+	// changes for it.
+	planCache *PlanCache
+
 	// During import we may generate configuration for a resource, which needs
 	// to be stored in the final change.
 	generatedConfigHCL string
@@ -728,6 +736,9 @@ func (n *NodeAbstractResourceInstance) writeChange(ctx context.Context, evalCtx
 	if err != nil {
 		return fmt.Errorf("failed to encode planned changes for %s: %w", n.Addr, err)
 	}
+	if n.Config != nil && len(n.Config.MaskInOutput) != 0 {
+		csrc.MaskedPaths = resourceMaskedPaths(n.Config)
+	}
 
 	changes.AppendResourceInstanceChange(csrc)
 	if deposedKey == states.NotDeposed {
@@ -840,7 +851,9 @@ func (n *NodeAbstractResourceInstance) refresh(ctx context.Context, evalCtx Eval
 	}
 
 	ret := state.DeepCopy()
-	ret.Value = newState
+	// Write-only attributes are never persisted, even if the provider
+	// returns values for them.
+	ret.Value = schema.WriteOnlyAsNull(newState)
 	ret.Private = resp.Private
 
 	// We have no way to exempt provider using the legacy SDK from this check,
@@ -894,7 +907,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		return nil, nil, keyData, diags.Append(err)
 	}
 
-	schema, _ := providerSchema.SchemaForResourceAddr(resource)
+	schema, schemaVersion := providerSchema.SchemaForResourceAddr(resource)
 	if schema == nil {
 		// Should be caught during validation, so we don't bother with a pretty error here
 		diags = diags.Append(fmt.Errorf("provider does not support resource type %q", resource.Type))
@@ -956,6 +969,11 @@ func (n *NodeAbstractResourceInstance) plan(
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateEphemeralResourceConfig(schema, origConfigVal, config.Config, n.Addr.String()))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
+	origConfigVal = unmarkEphemeral(origConfigVal)
 
 	metaConfigVal, metaDiags := n.providerMetas(ctx, evalCtx)
 	diags = diags.Append(metaDiags)
@@ -1033,14 +1051,51 @@ func (n *NodeAbstractResourceInstance) plan(
 		return nil, nil, keyData, diags
 	}
 
-	resp := provider.PlanResourceChange(ctx, providers.PlanResourceChangeRequest{
-		TypeName:         n.Addr.Resource.Resource.Type,
-		Config:           unmarkedConfigVal,
-		PriorState:       unmarkedPriorVal,
-		ProposedNewState: proposedNewVal,
-		PriorPrivate:     priorPrivate,
-		ProviderMeta:     metaConfigVal,
-	})
+	// In an incremental plan, an existing object whose inputs match those of
+	// an earlier plan that had no changes for it is known to have no changes
+	// now, so we don't need to ask the provider again.
+	var fingerprint string
+	if n.planCache != nil && plannedChange == nil && !priorVal.IsNull() && !slices.ContainsFunc(forceReplace, n.Addr.Equal) {
+		fingerprint, _ = n.planCache.fingerprint(
+			n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey,
+			evalCtx.ProviderConfigValue(n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey),
+			resource.Type, schemaVersion, schema,
+			unmarkedConfigVal, unmarkedPriorVal, metaConfigVal, priorPrivate,
+		)
+	}
+
+	// If deferrals are allowed and the provider was configured with values
+	// that won't be known until the apply phase, such as the attributes of a
+	// resource that doesn't exist yet, then it can't reliably plan a new
+	// object yet, so we don't ask it to. Instead we plan an object whose
+	// unset attributes are all unknown, and the provider will plan it
+	// properly during the apply phase, once it's configured with the final
+	// values.
+	deferred := plannedChange == nil && priorVal.IsNull() && evalCtx.Deferrals().Allowed() && evalCtx.ProviderConfigUnknown(n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey)
+
+	var resp providers.PlanResourceChangeResponse
+	if deferred {
+		log.Printf("[TRACE] plan: %s has a provider configuration that isn't fully known yet, so deferring to apply phase", n.Addr)
+		resp = providers.PlanResourceChangeResponse{
+			PlannedState: objchange.PlannedDeferredResourceObject(schema, unmarkedConfigVal),
+		}
+	} else if entry, ok := n.planCache.lookupFingerprint(n.Addr, fingerprint); ok {
+		log.Printf("[TRACE] plan: %s has the same inputs as in the previous incremental plan, so it has no changes", n.Addr)
+		resp = providers.PlanResourceChangeResponse{
+			PlannedState:     unmarkedPriorVal,
+			PlannedPrivate:   priorPrivate,
+			LegacyTypeSystem: entry.LegacyTypeSystem,
+		}
+	} else {
+		resp = provider.PlanResourceChange(ctx, providers.PlanResourceChangeRequest{
+			TypeName:         n.Addr.Resource.Resource.Type,
+			Config:           unmarkedConfigVal,
+			PriorState:       unmarkedPriorVal,
+			ProposedNewState: proposedNewVal,
+			PriorPrivate:     priorPrivate,
+			ProviderMeta:     metaConfigVal,
+		})
+	}
 
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
@@ -1048,8 +1103,6 @@ func (n *NodeAbstractResourceInstance) plan(
 	}
 
 	plannedNewVal := resp.PlannedState
-	// Store an unmarked version of our planned new value because the `plan` now marks properties correctly with the config marks
-	unmarkedPlannedNewVal, _ := plannedNewVal.UnmarkDeep()
 	plannedPrivate := resp.PlannedPrivate
 
 	if plannedNewVal == cty.NilVal {
@@ -1059,6 +1112,12 @@ func (n *NodeAbstractResourceInstance) plan(
 		panic(fmt.Sprintf("PlanResourceChange of %s produced nil value", n.Addr))
 	}
 
+	// Write-only attributes are never persisted, so we discard any values the
+	// provider might have planned for them.
+	plannedNewVal = schema.WriteOnlyAsNull(plannedNewVal)
+	// Store an unmarked version of our planned new value because the `plan` now marks properties correctly with the config marks
+	unmarkedPlannedNewVal, _ := plannedNewVal.UnmarkDeep()
+
 	// We allow the planned new value to disagree with configuration _values_
 	// here, since that allows the provider to do special logic like a
 	// DiffSuppressFunc, but we still require that the provider produces
@@ -1077,7 +1136,9 @@ func (n *NodeAbstractResourceInstance) plan(
 		return nil, nil, keyData, diags
 	}
 
-	if errs := objchange.AssertPlanValid(schema, unmarkedPriorVal, unmarkedConfigVal, unmarkedPlannedNewVal); len(errs) > 0 {
+	// A deferred plan was produced by OpenTofu Core rather than by the
+	// provider, so there's nothing to check here.
+	if errs := objchange.AssertPlanValid(schema, unmarkedPriorVal, unmarkedConfigVal, unmarkedPlannedNewVal); len(errs) > 0 && !deferred {
 		if resp.LegacyTypeSystem {
 			// The shimming of the old type system in the legacy SDK is not precise
 			// enough to pass this consistency check, so we'll give it a pass here,
@@ -1291,7 +1352,7 @@ func (n *NodeAbstractResourceInstance) plan(
 			diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 			return nil, nil, keyData, diags
 		}
-		plannedNewVal = resp.PlannedState
+		plannedNewVal = schema.WriteOnlyAsNull(resp.PlannedState)
 		plannedPrivate = resp.PlannedPrivate
 
 		if len(unmarkedPaths) > 0 {
@@ -1354,6 +1415,13 @@ func (n *NodeAbstractResourceInstance) plan(
 		}
 	}
 
+	if fingerprint != "" && action == plans.NoOp {
+		n.planCache.record(n.Addr, PlanCacheEntry{
+			Fingerprint:      fingerprint,
+			LegacyTypeSystem: resp.LegacyTypeSystem,
+		})
+	}
+
 	// Call post-refresh hook
 	diags = diags.Append(evalCtx.Hook(func(h Hook) (HookAction, error) {
 		return h.PostDiff(n.Addr, states.CurrentGen, action, priorVal, plannedNewVal)
@@ -1404,10 +1472,9 @@ func (n *NodeAbstractResource) processIgnoreChanges(prior, config cty.Value, sch
 		return config, nil
 	}
 
-	ignoreChanges := traversalsToPaths(n.Config.Managed.IgnoreChanges)
 	ignoreAll := n.Config.Managed.IgnoreAllChanges
 
-	if len(ignoreChanges) == 0 && !ignoreAll {
+	if len(n.Config.Managed.IgnoreChanges) == 0 && !ignoreAll {
 		return config, nil
 	}
 
@@ -1441,20 +1508,150 @@ func (n *NodeAbstractResource) processIgnoreChanges(prior, config cty.Value, sch
 		return config, nil
 	}
 
+	var ignoreChanges []cty.Path
+	for _, traversal := range n.Config.Managed.IgnoreChanges {
+		ignoreChanges = append(ignoreChanges, expandIgnoreChangesTraversal(traversal, prior, config)...)
+	}
+
 	ret, diags := processIgnoreChangesIndividual(prior, config, ignoreChanges)
 
 	return ret, diags
 }
 
-// Convert the hcl.Traversal values we get form the configuration to the
-// cty.Path values we need to operate on the cty.Values
-func traversalsToPaths(traversals []hcl.Traversal) []cty.Path {
-	paths := make([]cty.Path, len(traversals))
-	for i, traversal := range traversals {
-		path := traversalToPath(traversal)
-		paths[i] = path
+// expandIgnoreChangesTraversal converts an ignore_changes traversal from the
+// configuration to the cty.Path values we need to operate on the given prior
+// and config values.
+//
+// The traversal can include splat steps, which match every element of a list
+// or map, and map keys containing "*" wildcards, which match any sequence of
+// characters. Each of those is expanded into a path for each matching element
+// of either value, so the result has no wildcards. A path that doesn't apply
+// to one of the values is skipped by processIgnoreChangesIndividual.
+func expandIgnoreChangesTraversal(traversal hcl.Traversal, prior, config cty.Value) []cty.Path {
+	var expand func(path cty.Path, steps hcl.Traversal, vals []cty.Value) []cty.Path
+	expand = func(path cty.Path, steps hcl.Traversal, vals []cty.Value) []cty.Path {
+		if len(steps) == 0 {
+			return []cty.Path{path}
+		}
+
+		var keys []cty.Value
+		switch step := steps[0].(type) {
+		case hcl.TraverseSplat:
+			keys = ignoreChangesElementKeys(vals, func(string) bool { return true })
+		case hcl.TraverseIndex:
+			if pattern, ok := ignoreChangesKeyPattern(step.Key); ok {
+				keys = ignoreChangesElementKeys(vals, func(key string) bool {
+					return ignoreChangesKeyMatch(pattern, key)
+				})
+				break
+			}
+			return expand(path.Copy().Index(step.Key), steps[1:], ignoreChangesStep(vals, cty.IndexStep{Key: step.Key}))
+		default:
+			next := traversalToPath(hcl.Traversal{step})[0]
+			return expand(append(path.Copy(), next), steps[1:], ignoreChangesStep(vals, next))
+		}
+
+		var ret []cty.Path
+		for _, key := range keys {
+			ret = append(ret, expand(path.Copy().Index(key), steps[1:], ignoreChangesStep(vals, cty.IndexStep{Key: key}))...)
+		}
+		return ret
 	}
-	return paths
+	return expand(nil, traversal, []cty.Value{prior, config})
+}
+
+// ignoreChangesStep applies the given step to each of the given values,
+// returning the results for the values it applies to.
+func ignoreChangesStep(vals []cty.Value, step cty.PathStep) []cty.Value {
+	var ret []cty.Value
+	for _, val := range vals {
+		val, _ = val.Unmark()
+		if next, err := step.Apply(val); err == nil {
+			ret = append(ret, next)
+		}
+	}
+	return ret
+}
+
+// ignoreChangesElementKeys returns the keys of the elements of the given
+// lists and maps, in order and without duplicates, including only the map
+// keys that the given function accepts.
+func ignoreChangesElementKeys(vals []cty.Value, match func(string) bool) []cty.Value {
+	var ret []cty.Value
+	seen := make(map[string]bool)
+	for _, val := range vals {
+		val, _ = val.Unmark()
+		if val.IsNull() || !val.IsKnown() {
+			continue
+		}
+		ty := val.Type()
+		if !ty.IsListType() && !ty.IsTupleType() && !ty.IsMapType() {
+			continue
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			key, _ := it.Element()
+			var name string
+			if ty.IsMapType() {
+				name = key.AsString()
+				if !match(name) {
+					continue
+				}
+			} else {
+				name = key.AsBigFloat().String()
+			}
+			if !seen[name] {
+				seen[name] = true
+				ret = append(ret, key)
+			}
+		}
+	}
+	if len(vals) > 1 && len(ret) != 0 && ret[0].Type() == cty.String {
+		// The keys of each map are in order, but a key that's only in a
+		// later map would otherwise come after all of the earlier ones.
+		sort.Slice(ret, func(i, j int) bool {
+			return ret[i].AsString() < ret[j].AsString()
+		})
+	}
+	return ret
+}
+
+// ignoreChangesKeyPattern returns the given index key as a pattern if it's a
+// string containing at least one "*" wildcard.
+func ignoreChangesKeyPattern(key cty.Value) (string, bool) {
+	if key.Type() != cty.String || key.IsNull() || !key.IsKnown() {
+		return "", false
+	}
+	pattern := key.AsString()
+	return pattern, strings.Contains(pattern, "*")
+}
+
+// ignoreChangesKeyMatch returns true if the given map key matches the given
+// pattern, in which each "*" matches any sequence of characters.
+func ignoreChangesKeyMatch(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(key, part)
+		if i < 0 {
+			return false
+		}
+		key = key[i+len(part):]
+	}
+	return len(key) >= len(last) && strings.HasSuffix(key, last)
+}
+
+// resourceMaskedPaths returns the paths given in the "mask_in_output" lifecycle
+// argument of the given resource configuration.
+func resourceMaskedPaths(rc *configs.Resource) cty.PathSet {
+	paths := make([]cty.Path, len(rc.MaskInOutput))
+	for i, traversal := range rc.MaskInOutput {
+		paths[i] = traversalToPath(traversal)
+	}
+	return cty.NewPathSet(paths...)
 }
 
 func traversalToPath(traversal hcl.Traversal) cty.Path {
@@ -1848,6 +2045,11 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx context.Context, evalC
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateEphemeralResourceConfig(schema, configVal, config.Config, n.Addr.String()))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
+	configVal = unmarkEphemeral(configVal)
 
 	check, nested := n.nestedInCheckBlock()
 	if nested {
@@ -1877,11 +2079,17 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx context.Context, evalC
 
 	configKnown := configVal.IsWhollyKnown()
 	depsPending := n.dependenciesHavePendingChanges(evalCtx)
+	deferredByConfig := config.DeferReadUntilApply
+	// When deferrals are allowed, a provider configured with values that
+	// won't be known until the apply phase, such as the attributes of a
+	// resource that doesn't exist yet, can't reliably read anything yet, so
+	// we don't ask it to.
+	providerUnknown := !nested && evalCtx.Deferrals().Allowed() && evalCtx.ProviderConfigUnknown(n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey)
 	// If our configuration contains any unknown values, or we depend on any
-	// unknown values then we must defer the read to the apply phase by
-	// producing a "Read" change for this resource, and a placeholder value for
-	// it in the state.
-	if depsPending || !configKnown {
+	// unknown values, or the configuration or provider asks us to, then we
+	// must defer the read to the apply phase by producing a "Read" change for
+	// this resource, and a placeholder value for it in the state.
+	if depsPending || !configKnown || deferredByConfig || providerUnknown {
 		// We can't plan any changes if we're only refreshing, so the only
 		// value we can set here is whatever was in state previously.
 		if skipPlanChanges {
@@ -1904,36 +2112,16 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx context.Context, evalC
 			// specific.
 			log.Printf("[TRACE] planDataSource: %s configuration is fully known, at least one dependency has changes pending", n.Addr)
 			reason = plans.ResourceInstanceReadBecauseDependencyPending
+		case deferredByConfig:
+			log.Printf("[TRACE] planDataSource: %s has defer_read_until_apply set, so deferring to apply phase", n.Addr)
+			reason = plans.ResourceInstanceReadBecauseDeferredByConfig
+		case providerUnknown:
+			log.Printf("[TRACE] planDataSource: %s provider configuration not fully known yet, so deferring to apply phase", n.Addr)
+			reason = plans.ResourceInstanceReadBecauseConfigUnknown
 		}
 
-		unmarkedConfigVal, configMarkPaths := configVal.UnmarkDeepWithPaths()
-		proposedNewVal := objchange.PlannedDataResourceObject(schema, unmarkedConfigVal)
-		proposedNewVal = proposedNewVal.MarkWithPaths(configMarkPaths)
-
-		// Apply detects that the data source will need to be read by the After
-		// value containing unknowns from PlanDataResourceObject.
-		plannedChange := &plans.ResourceInstanceChange{
-			Addr:         n.Addr,
-			PrevRunAddr:  n.prevRunAddr(evalCtx),
-			ProviderAddr: n.ResolvedProvider.ProviderConfig,
-			Change: plans.Change{
-				Action: plans.Read,
-				Before: priorVal,
-				After:  proposedNewVal,
-			},
-			ActionReason: reason,
-		}
-
-		plannedNewState := &states.ResourceInstanceObject{
-			Value:  proposedNewVal,
-			Status: states.ObjectPlanned,
-		}
-
-		diags = diags.Append(evalCtx.Hook(func(h Hook) (HookAction, error) {
-			return h.PostDiff(n.Addr, states.CurrentGen, plans.Read, priorVal, proposedNewVal)
-		}))
-
-		return plannedChange, plannedNewState, keyData, diags
+		plannedChange, plannedNewState, deferDiags := n.deferDataSourceRead(evalCtx, schema, priorVal, configVal, reason)
+		return plannedChange, plannedNewState, keyData, diags.Append(deferDiags)
 	}
 
 	// We have a complete configuration with no dependencies to wait on, so we
@@ -2014,6 +2202,42 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx context.Context, evalC
 	return plannedChange, plannedNewState, keyData, diags
 }
 
+// deferDataSourceRead produces a "Read" change for the data source, and a
+// placeholder value for it in the state, so that it will be read during the
+// apply phase instead of the plan phase.
+func (n *NodeAbstractResourceInstance) deferDataSourceRead(evalCtx EvalContext, schema *configschema.Block, priorVal, configVal cty.Value, reason plans.ResourceInstanceChangeActionReason) (*plans.ResourceInstanceChange, *states.ResourceInstanceObject, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	unmarkedConfigVal, configMarkPaths := configVal.UnmarkDeepWithPaths()
+	proposedNewVal := objchange.PlannedDataResourceObject(schema, unmarkedConfigVal)
+	proposedNewVal = proposedNewVal.MarkWithPaths(configMarkPaths)
+
+	// Apply detects that the data source will need to be read by the After
+	// value containing unknowns from PlanDataResourceObject.
+	plannedChange := &plans.ResourceInstanceChange{
+		Addr:         n.Addr,
+		PrevRunAddr:  n.prevRunAddr(evalCtx),
+		ProviderAddr: n.ResolvedProvider.ProviderConfig,
+		Change: plans.Change{
+			Action: plans.Read,
+			Before: priorVal,
+			After:  proposedNewVal,
+		},
+		ActionReason: reason,
+	}
+
+	plannedNewState := &states.ResourceInstanceObject{
+		Value:  proposedNewVal,
+		Status: states.ObjectPlanned,
+	}
+
+	diags = diags.Append(evalCtx.Hook(func(h Hook) (HookAction, error) {
+		return h.PostDiff(n.Addr, states.CurrentGen, plans.Read, priorVal, proposedNewVal)
+	}))
+
+	return plannedChange, plannedNewState, diags
+}
+
 // nestedInCheckBlock determines if this resource is nested in a Check config
 // block. If so, this resource will be loaded during both plan and apply
 // operations to make sure the check is always giving the latest information.
@@ -2137,6 +2361,11 @@ func (n *NodeAbstractResourceInstance) applyDataSource(ctx context.Context, eval
 	if configDiags.HasErrors() {
 		return nil, keyData, diags
 	}
+	diags = diags.Append(validateEphemeralResourceConfig(schema, configVal, config.Config, n.Addr.String()))
+	if diags.HasErrors() {
+		return nil, keyData, diags
+	}
+	configVal = unmarkEphemeral(configVal)
 
 	newVal, readDiags := n.readDataSource(ctx, evalCtx, configVal)
 	if check, nested := n.nestedInCheckBlock(); nested {
@@ -2262,6 +2491,43 @@ func filterProvisioners(provisioners []*configs.Provisioner, when configs.Provis
 	return result
 }
 
+// resourceHookProvisioners returns the provisioners that run the lifecycle
+// hooks of the given resource configuration for the given event, in the
+// order the hooks are declared.
+func resourceHookProvisioners(config *configs.Resource, event configs.ResourceHookEvent) []*configs.Provisioner {
+	if config == nil || config.Managed == nil {
+		return nil
+	}
+
+	var result []*configs.Provisioner
+	for _, h := range config.Managed.Hooks {
+		if h.Event == event {
+			result = append(result, h.Provisioner())
+		}
+	}
+	return result
+}
+
+// evalApplyHooks runs the lifecycle hooks of the resource for the given
+// event, with self referring to the given object.
+func (n *NodeAbstractResourceInstance) evalApplyHooks(ctx context.Context, evalCtx EvalContext, state *states.ResourceInstanceObject, event configs.ResourceHookEvent) tfdiags.Diagnostics {
+	if state == nil || state.Status == states.ObjectTainted {
+		return nil
+	}
+
+	provs := resourceHookProvisioners(n.Config, event)
+	if len(provs) == 0 {
+		return nil
+	}
+	log.Printf("[TRACE] evalApplyHooks: running %d %s hooks for %s", len(provs), event, n.Addr)
+
+	when := configs.ProvisionerWhenCreate
+	if event == configs.ResourceHookPreDestroy {
+		when = configs.ProvisionerWhenDestroy
+	}
+	return n.applyProvisioners(ctx, evalCtx, state, when, provs)
+}
+
 // applyProvisioners executes the provisioners for a resource.
 func (n *NodeAbstractResourceInstance) applyProvisioners(ctx context.Context, evalCtx EvalContext, state *states.ResourceInstanceObject, when configs.ProvisionerWhen, provs []*configs.Provisioner) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
@@ -2278,14 +2544,6 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx context.Context, ev
 		evalScope = n.evalProvisionerConfig
 	}
 
-	// If there's a connection block defined directly inside the resource block
-	// then it'll serve as a base connection configuration for all of the
-	// provisioners.
-	var baseConn hcl.Body
-	if n.Config != nil && n.Config.Managed != nil && n.Config.Managed.Connection != nil {
-		baseConn = n.Config.Managed.Connection.Config
-	}
-
 	for _, prov := range provs {
 		log.Printf("[TRACE] applyProvisioners: provisioning %s with %q", n.Addr, prov.Type)
 
@@ -2310,31 +2568,10 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx context.Context, ev
 			return diags
 		}
 
-		// If the provisioner block contains a connection block of its own then
-		// it can override the base connection configuration, if any.
-		var localConn hcl.Body
-		if prov.Connection != nil {
-			localConn = prov.Connection.Config
-		}
-
-		var connBody hcl.Body
-		switch {
-		case baseConn != nil && localConn != nil:
-			// Our standard merging logic applies here, similar to what we do
-			// with _override.tf configuration files: arguments from the
-			// base connection block will be masked by any arguments of the
-			// same name in the local connection block.
-			connBody = configs.MergeBodies(baseConn, localConn)
-		case baseConn != nil:
-			connBody = baseConn
-		case localConn != nil:
-			connBody = localConn
-		}
-
 		// start with an empty connInfo
 		connInfo := cty.NullVal(shared.ConnectionBlockSupersetSchema.ImpliedType())
 
-		if connBody != nil {
+		if connBody := n.provisionerConnectionBody(prov); connBody != nil {
 			var connInfoDiags tfdiags.Diagnostics
 			connInfo, connInfoDiags = evalScope(ctx, evalCtx, connBody, self, shared.ConnectionBlockSupersetSchema)
 			diags = diags.Append(connInfoDiags)
@@ -2421,6 +2658,38 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx context.Context, ev
 	return diags
 }
 
+// provisionerConnectionBody returns the connection configuration for the
+// given provisioner, or nil if there is none.
+func (n *NodeAbstractResourceInstance) provisionerConnectionBody(prov *configs.Provisioner) hcl.Body {
+	// If there's a connection block defined directly inside the resource block
+	// then it'll serve as a base connection configuration for all of the
+	// provisioners.
+	var baseConn hcl.Body
+	if n.Config != nil && n.Config.Managed != nil && n.Config.Managed.Connection != nil {
+		baseConn = n.Config.Managed.Connection.Config
+	}
+
+	// If the provisioner block contains a connection block of its own then
+	// it can override the base connection configuration, if any.
+	var localConn hcl.Body
+	if prov.Connection != nil {
+		localConn = prov.Connection.Config
+	}
+
+	switch {
+	case baseConn != nil && localConn != nil:
+		// Our standard merging logic applies here, similar to what we do
+		// with _override.tf configuration files: arguments from the
+		// base connection block will be masked by any arguments of the
+		// same name in the local connection block.
+		return configs.MergeBodies(baseConn, localConn)
+	case baseConn != nil:
+		return baseConn
+	default:
+		return localConn
+	}
+}
+
 func (n *NodeAbstractResourceInstance) evalProvisionerConfig(ctx context.Context, evalCtx EvalContext, body hcl.Body, self cty.Value, schema *configschema.Block) (cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -2499,6 +2768,11 @@ func (n *NodeAbstractResourceInstance) apply(
 		if configDiags.HasErrors() {
 			return nil, diags
 		}
+		diags = diags.Append(validateEphemeralResourceConfig(schema, configVal, applyConfig.Config, n.Addr.String()))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		configVal = unmarkEphemeral(configVal)
 	}
 
 	if !configVal.IsWhollyKnown() {
@@ -2556,11 +2830,13 @@ func (n *NodeAbstractResourceInstance) apply(
 			Private:             state.Private,
 			Status:              state.Status,
 			Value:               change.After,
+			ApplyDuration:       state.ApplyDuration,
 		}
 		return newState, diags
 	}
 
-	resp := provider.ApplyResourceChange(ctx, providers.ApplyResourceChangeRequest{
+	applyStart := time.Now()
+	resp := n.applyResourceChange(ctx, evalCtx, provider, change.Action, providers.ApplyResourceChangeRequest{
 		TypeName:       n.Addr.Resource.Resource.Type,
 		PriorState:     unmarkedBefore,
 		Config:         unmarkedConfigVal,
@@ -2568,6 +2844,10 @@ func (n *NodeAbstractResourceInstance) apply(
 		PlannedPrivate: change.Private,
 		ProviderMeta:   metaConfigVal,
 	})
+	// We record only whole seconds, which is plenty for estimating how long
+	// future changes might take and avoids churning the state with
+	// differences that nobody could make use of.
+	applyDuration := time.Since(applyStart).Truncate(time.Second)
 
 	applyDiags := resp.Diagnostics
 	if applyConfig != nil {
@@ -2580,7 +2860,7 @@ func (n *NodeAbstractResourceInstance) apply(
 	// failed to fully configure, and so the remaining code must always run
 	// to completion but must be defensive against the new value being
 	// incomplete.
-	newVal := resp.NewState
+	newVal := schema.WriteOnlyAsNull(resp.NewState)
 
 	// If we have paths to mark, mark those on this new value
 	if len(afterPaths) > 0 {
@@ -2765,6 +3045,7 @@ func (n *NodeAbstractResourceInstance) apply(
 			Value:               newVal,
 			Private:             resp.Private,
 			CreateBeforeDestroy: createBeforeDestroy,
+			ApplyDuration:       applyDuration,
 		}
 
 		// if the resource was being deleted, the dependencies are not going to
@@ -2782,6 +3063,7 @@ func (n *NodeAbstractResourceInstance) apply(
 			Value:               newVal,
 			Private:             resp.Private,
 			CreateBeforeDestroy: createBeforeDestroy,
+			ApplyDuration:       applyDuration,
 		}
 		return newState, diags
 
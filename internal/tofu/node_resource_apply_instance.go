@@ -365,6 +365,16 @@ func (n *NodeApplyableResourceInstance) managedResourceExecute(ctx context.Conte
 	// the provisioner errors count as port of the apply error, so we can bundle the diags
 	diags = diags.Append(applyProvisionersDiags)
 
+	// Run lifecycle hooks, unless the change or the provisioners failed
+	if !diags.HasErrors() {
+		switch {
+		case createNew:
+			diags = diags.Append(n.evalApplyHooks(ctx, evalCtx, state, configs.ResourceHookPostCreate))
+		case diffApply.Action == plans.Update:
+			diags = diags.Append(n.evalApplyHooks(ctx, evalCtx, state, configs.ResourceHookPostUpdate))
+		}
+	}
+
 	state = maybeTainted(addr.Absolute(evalCtx.Path()), state, diffApply, diags.Err())
 
 	err = n.writeResourceInstanceState(ctx, evalCtx, state, workingState)
@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// resourceRetryBaseDelay is how long OpenTofu waits before the first retry of
+// a failed change. The delay doubles for each further retry, up to
+// resourceRetryMaxDelay.
+var resourceRetryBaseDelay = 2 * time.Second
+
+const resourceRetryMaxDelay = 30 * time.Second
+
+// applyResourceChange asks the provider to apply the given change, enforcing
+// the "timeouts" and "retry" settings of the resource's lifecycle block, if
+// any.
+func (n *NodeAbstractResourceInstance) applyResourceChange(
+	ctx context.Context,
+	evalCtx EvalContext,
+	provider providers.Interface,
+	action plans.Action,
+	req providers.ApplyResourceChangeRequest,
+) providers.ApplyResourceChangeResponse {
+	var timeout time.Duration
+	var retry *configs.ResourceRetry
+	if n.Config != nil && n.Config.Managed != nil {
+		retry = n.Config.Managed.Retry
+		if timeouts := n.Config.Managed.Timeouts; timeouts != nil {
+			switch action {
+			case plans.Create:
+				timeout = timeouts.Create
+			case plans.Update:
+				timeout = timeouts.Update
+			case plans.Delete:
+				timeout = timeouts.Delete
+			}
+		}
+	}
+
+	operation := resourceOperationName(action)
+	var failures []tfdiags.Diagnostics
+	for attempt := 1; ; attempt++ {
+		resp, timedOut := applyResourceChangeWithTimeout(ctx, provider, req, timeout)
+		if timedOut {
+			// A timed out change is never retried, because we can't tell how
+			// far the provider got before its request was canceled.
+			resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Resource operation timed out",
+				fmt.Sprintf(
+					"The provider did not finish the %s of %s within the %s timeout set in the resource's lifecycle block, so OpenTofu canceled the request.",
+					operation, n.Addr, timeout,
+				),
+			))
+			return resp
+		}
+
+		if !resp.Diagnostics.HasErrors() || retry == nil || attempt >= retry.Attempts || !resourceChangeRetryable(retry, req, resp) {
+			if len(failures) != 0 {
+				resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Resource operation retried",
+					fmt.Sprintf(
+						"The provider failed to apply the %s of %s %d time(s), so OpenTofu retried it as configured in the resource's lifecycle block. The last retried error was: %s",
+						operation, n.Addr, len(failures), failures[len(failures)-1].Err(),
+					),
+				))
+			}
+			return resp
+		}
+		failures = append(failures, resp.Diagnostics)
+
+		delay := resourceRetryBaseDelay << (attempt - 1)
+		if delay > resourceRetryMaxDelay || delay < 0 {
+			delay = resourceRetryMaxDelay
+		}
+		log.Printf("[WARN] %s: the %s failed on attempt %d of %d; retrying in %s: %s", n.Addr, operation, attempt, retry.Attempts, delay, resp.Diagnostics.Err())
+
+		select {
+		case <-time.After(delay):
+		case <-evalCtx.Stopped():
+			// If the operation is being interrupted then we return the
+			// failure we already have, rather than starting over.
+			return resp
+		}
+	}
+}
+
+// applyResourceChangeWithTimeout calls ApplyResourceChange on the given
+// provider, with a context whose deadline is the given timeout from now. If
+// the deadline passes before the provider returns then the second return
+// value is true.
+//
+// Unlike an interrupt, which asks the whole provider to stop, the deadline
+// only cancels this one request, so the provider's other changes carry on.
+func applyResourceChangeWithTimeout(ctx context.Context, provider providers.Interface, req providers.ApplyResourceChangeRequest, timeout time.Duration) (providers.ApplyResourceChangeResponse, bool) {
+	if timeout == 0 {
+		return provider.ApplyResourceChange(ctx, req), false
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp := provider.ApplyResourceChange(callCtx, req)
+	if !resp.Diagnostics.HasErrors() || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return resp, false
+	}
+	log.Printf("[WARN] the provider did not apply the %s change within %s; its request was canceled", req.TypeName, timeout)
+	return resp, true
+}
+
+// resourceChangeRetryable returns true if a failed change described by the
+// given request and response can be retried with the given settings.
+//
+// A change is retried only when the provider reports that it left the object
+// as it was, because otherwise repeating the same request could act on an
+// object that no longer matches its prior state, such as creating a second
+// object after the first one was partially created.
+func resourceChangeRetryable(retry *configs.ResourceRetry, req providers.ApplyResourceChangeRequest, resp providers.ApplyResourceChangeResponse) bool {
+	if resp.NewState != cty.NilVal {
+		eq := resp.NewState.Equals(req.PriorState)
+		if !eq.IsKnown() || eq.False() {
+			return false
+		}
+	}
+
+	for _, diag := range resp.Diagnostics {
+		if diag.Severity() != tfdiags.Error {
+			continue
+		}
+		desc := diag.Description()
+		if retry.Retryable(desc.Summary + ": " + desc.Detail) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceOperationName returns how the operation is described in messages
+// about applying a change with the given action.
+func resourceOperationName(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "creation"
+	case plans.Delete:
+		return "deletion"
+	default:
+		return "update"
+	}
+}
@@ -134,6 +134,11 @@ func (n *NodeDestroyResourceInstance) References() []*addrs.Reference {
 				result = append(result, ReferencesFromConfig(p.Config, schema)...)
 			}
 		}
+		for _, h := range c.Managed.Hooks {
+			if h.Event == configs.ResourceHookPreDestroy {
+				result = append(result, ReferencesFromConfig(h.Config, n.ProvisionerSchemas[configs.ResourceHookProvisioner])...)
+			}
+		}
 
 		return result
 	}
@@ -226,10 +231,13 @@ func (n *NodeDestroyResourceInstance) managedResourceExecute(ctx context.Context
 		return diags
 	}
 
-	// Run destroy provisioners if not tainted
+	// Run pre_destroy hooks and destroy provisioners if not tainted
 	if state.Status != states.ObjectTainted {
-		applyProvisionersDiags := n.evalApplyProvisioners(ctx, evalCtx, state, false, configs.ProvisionerWhenDestroy)
-		diags = diags.Append(applyProvisionersDiags)
+		diags = diags.Append(n.evalApplyHooks(ctx, evalCtx, state, configs.ResourceHookPreDestroy))
+		if !diags.HasErrors() {
+			applyProvisionersDiags := n.evalApplyProvisioners(ctx, evalCtx, state, false, configs.ProvisionerWhenDestroy)
+			diags = diags.Append(applyProvisionersDiags)
+		}
 		// keep the diags separate from the main set until we handle the cleanup
 
 		if diags.HasErrors() {
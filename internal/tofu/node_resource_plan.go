@@ -8,10 +8,12 @@ package tofu
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -42,6 +44,19 @@ type nodeExpandPlannableResource struct {
 	// that this node represents, which the node itself must therefore ignore.
 	forceReplace []addrs.AbsResourceInstance
 
+	// cascadeReplace extends forceReplace to the instances of this resource
+	// if it depends on the resource of any of the instances in forceReplace.
+	cascadeReplace bool
+
+	// previewProvisioners makes the instances of this resource record
+	// previews of the provisioners that will run when their changes are
+	// applied.
+	previewProvisioners bool
+
+	// planCache, if set, lets the instances of this resource skip planning
+	// when their inputs haven't changed since an earlier plan.
+	planCache *PlanCache
+
 	// We attach dependencies to the Resource during refresh, since the
 	// instances are instantiated during DynamicExpand.
 	// FIXME: These would be better off converted to a generic Set data
@@ -139,6 +154,7 @@ func (n *nodeExpandPlannableResource) DynamicExpand(evalCtx EvalContext) (*Graph
 			NodeAbstractResourceInstance: a,
 			skipRefresh:                  n.skipRefresh,
 			skipPlanChanges:              n.skipPlanChanges,
+			previewProvisioners:          n.previewProvisioners,
 		}
 	}
 
@@ -227,6 +243,15 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(ctx context.Contex
 		return diags.ErrWithWarnings()
 	}
 
+	// If writeResourceState deferred the resource because its expansion
+	// isn't known yet then we don't know which of its instances exist,
+	// so we must leave all of them, including any in the prior state,
+	// for a later plan. None of the instances can have been deferred
+	// individually yet, because they're only visited after this.
+	if moduleCtx.Deferrals().IsDeferred(resAddr) {
+		return diags.ErrWithWarnings()
+	}
+
 	// Before we expand our resource into potentially many resource instances,
 	// we'll verify that any mention of this resource in n.forceReplace is
 	// consistent with the repetition mode of the resource. In other words,
@@ -291,6 +316,14 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(ctx context.Contex
 	// actions is in the per-instance function we're about to call, because
 	// we need to evaluate it on a per-instance basis.
 
+	// If the configuration of any of the instances refers to a deferred
+	// resource then the whole resource is deferred, before any of its
+	// instances are planned, so that the plan never has changes for some
+	// instances of a resource that is also deferred.
+	if n.deferredByConfig(ctx, moduleCtx, resAddr, instanceAddrs) {
+		return diags.ErrWithWarnings()
+	}
+
 	for _, addr := range instanceAddrs {
 		// If this resource is participating in the "checks" mechanism then our
 		// caller will need to know all of our expanded instance addresses as
@@ -316,6 +349,44 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(ctx context.Contex
 	return diags.ErrWithWarnings()
 }
 
+// deferredByConfig returns true if the configuration of any of the given
+// instances of the resource refers to a resource whose planning is deferred
+// to a later plan, in which case it also records that this resource is
+// deferred, along with anything that refers to it in turn.
+//
+// The instances of a deferred resource are neither refreshed nor planned, so
+// any objects for them in the prior state are left as they are.
+func (n *nodeExpandPlannableResource) deferredByConfig(ctx context.Context, evalCtx EvalContext, addr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance) bool {
+	deferrals := evalCtx.Deferrals()
+	if !deferrals.Allowed() || n.Config == nil || len(instanceAddrs) == 0 {
+		return false
+	}
+
+	providerSchema, err := evalCtx.ProviderSchema(ctx, n.ResolvedProvider.ProviderConfig)
+	if err != nil {
+		return false
+	}
+	schema, _ := providerSchema.SchemaForResourceAddr(addr.Resource)
+	if schema == nil {
+		return false
+	}
+
+	// Any errors here will be reported when the instances are planned.
+	forEach, _ := evaluateForEachExpression(ctx, n.Config.ForEach, evalCtx, addr)
+	for _, instAddr := range instanceAddrs {
+		keyData := EvalDataForInstanceKey(instAddr.Resource.Key, forEach)
+		configVal, _, configDiags := evalCtx.EvaluateBlock(ctx, n.Config.Config, schema, nil, keyData)
+		if configDiags.HasErrors() || !marks.Contains(configVal, marks.Deferred) {
+			continue
+		}
+
+		log.Printf("[TRACE] deferring %s, because the configuration of %s refers to deferred resources", addr, instAddr)
+		deferrals.Defer(addr)
+		return true
+	}
+	return false
+}
+
 func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx context.Context, evalCtx EvalContext, addr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance) (*Graph, error) {
 	var diags tfdiags.Diagnostics
 
@@ -361,6 +432,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx context.Conte
 		a.Dependencies = n.dependencies
 		a.preDestroyRefresh = n.preDestroyRefresh
 		a.generateConfigPath = n.generateConfigPath
+		a.planCache = n.planCache
 
 		m = &NodePlannableResourceInstance{
 			NodeAbstractResourceInstance: a,
@@ -372,6 +444,8 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx context.Conte
 			skipRefresh:              n.skipRefresh,
 			skipPlanChanges:          n.skipPlanChanges,
 			forceReplace:             n.forceReplace,
+			cascadeReplace:           n.cascadeReplace,
+			previewProvisioners:      n.previewProvisioners,
 		}
 
 		resolvedImportTarget := evalCtx.ImportResolver().GetImport(a.Addr)
@@ -396,6 +470,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx context.Conte
 			NodeAbstractResourceInstance: a,
 			skipRefresh:                  n.skipRefresh,
 			skipPlanChanges:              n.skipPlanChanges,
+			previewProvisioners:          n.previewProvisioners,
 		}
 	}
 
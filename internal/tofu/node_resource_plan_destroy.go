@@ -27,6 +27,10 @@ type NodePlanDestroyableResourceInstance struct {
 
 	// skipRefresh indicates that we should skip refreshing
 	skipRefresh bool
+
+	// previewProvisioners records previews of the destroy-time provisioners
+	// that will run when the planned change is applied.
+	previewProvisioners bool
 }
 
 var (
@@ -127,6 +131,10 @@ func (n *NodePlanDestroyableResourceInstance) managedResourceExecute(ctx context
 		return diags
 	}
 
+	var previewDiags tfdiags.Diagnostics
+	change.Provisioners, previewDiags = n.planProvisionerPreviews(ctx, evalCtx, change, state, n.previewProvisioners)
+	diags = diags.Append(previewDiags)
+
 	diags = diags.Append(n.writeChange(ctx, evalCtx, change, ""))
 	if diags.HasErrors() {
 		return diags
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/hashicorp/hcl/v2"
@@ -50,6 +51,14 @@ type NodePlannableResourceInstance struct {
 	// that this node represents, which the node itself must therefore ignore.
 	forceReplace []addrs.AbsResourceInstance
 
+	// cascadeReplace extends forceReplace to this instance if its resource
+	// depends on the resource of any of the instances in forceReplace.
+	cascadeReplace bool
+
+	// previewProvisioners records previews of the provisioners that will run
+	// when the planned change is applied.
+	previewProvisioners bool
+
 	// replaceTriggeredBy stores references from replace_triggered_by which
 	// triggered this instance to be replaced.
 	replaceTriggeredBy []*addrs.Reference
@@ -324,6 +333,7 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx context.Conte
 		if diags.HasErrors() {
 			return diags
 		}
+		n.replaceCascaded()
 
 		change, instancePlanState, repeatData, planDiags := n.plan(
 			ctx, evalCtx, nil, instanceRefreshState, n.ForceCreateBeforeDestroy, n.forceReplace,
@@ -392,6 +402,11 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx context.Conte
 			return diags
 		}
 
+		diags = diags.Append(n.writeProvisionerPreviews(ctx, evalCtx, change, instanceRefreshState))
+		if diags.HasErrors() {
+			return diags
+		}
+
 		// If this plan resulted in a NoOp, then apply won't have a chance to make
 		// any changes to the stored dependencies. Since this is a NoOp we know
 		// that the stored dependencies will have no effect during apply, and we can
@@ -468,6 +483,38 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx context.Conte
 	return diags
 }
 
+// replaceCascaded checks if this instance needs to be replaced because it
+// depends on an instance that the user asked to replace with cascading. If
+// replacement is required, the instance address is added to forceReplace.
+//
+// The dependencies come from the dependency graph, which tracks them by
+// resource rather than by instance, so replacing any instance of a resource
+// cascades to all of the instances that depend on that resource.
+func (n *NodePlannableResourceInstance) replaceCascaded() {
+	if !n.cascadeReplace {
+		return
+	}
+
+	for _, candidateAddr := range n.forceReplace {
+		if candidateAddr.Equal(n.Addr) {
+			// Already being replaced, either directly or by a trigger.
+			return
+		}
+	}
+	for _, candidateAddr := range n.forceReplace {
+		replaced := candidateAddr.ContainingResource().Config()
+		for _, dep := range n.Dependencies {
+			if dep.Equal(replaced) {
+				// The slice is shared with the other instances, so we must
+				// not append to its backing array.
+				n.forceReplace = append(slices.Clip(n.forceReplace), n.Addr)
+				log.Printf("[DEBUG] Cascading replacement of %s forces replacement of %s", candidateAddr, n.Addr)
+				return
+			}
+		}
+	}
+}
+
 // replaceTriggered checks if this instance needs to be replace due to a change
 // in a replace_triggered_by reference. If replacement is required, the
 // instance address is added to forceReplace
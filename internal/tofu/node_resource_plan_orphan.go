@@ -34,6 +34,10 @@ type NodePlannableResourceInstanceOrphan struct {
 	// for any instances.
 	skipPlanChanges bool
 
+	// previewProvisioners records previews of the destroy-time provisioners
+	// that will run when the planned change is applied.
+	previewProvisioners bool
+
 	// RemoveStatements are resource instance addresses where the user wants to
 	// forget from the state. This set isn't pre-filtered, so
 	// it might contain addresses that have nothing to do with the resource
@@ -211,6 +215,10 @@ func (n *NodePlannableResourceInstanceOrphan) managedResourceExecute(ctx context
 	// sometimes not have a reason.)
 	change.ActionReason = n.deleteActionReason(evalCtx)
 
+	var previewDiags tfdiags.Diagnostics
+	change.Provisioners, previewDiags = n.planProvisionerPreviews(ctx, evalCtx, change, oldState, n.previewProvisioners)
+	diags = diags.Append(previewDiags)
+
 	diags = diags.Append(n.writeChange(ctx, evalCtx, change, ""))
 	if diags.HasErrors() {
 		return diags
@@ -0,0 +1,196 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/communicator/shared"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// provisionerPreviewConnectionAttrs are the connection arguments that are
+// included in provisioner previews. They identify the remote host without
+// including any credentials.
+var provisionerPreviewConnectionAttrs = []string{
+	"type",
+	"host",
+	"port",
+	"user",
+	"bastion_host",
+	"bastion_port",
+	"bastion_user",
+}
+
+// planProvisionerPreviews evaluates the lifecycle hooks that will run when the
+// given planned change is applied, and also the provisioners if
+// includeProvisioners is set, so that the plan can show what they will do.
+//
+// The prior state object is the one that the change was planned from. For a
+// change that creates a new object, this must be called only after the
+// planned change and the planned state have been written, so that references
+// to self refer to the planned new object.
+//
+// Provisioners and hooks are only evaluated during apply, so a configuration
+// error found here doesn't fail the plan: it's returned as a warning and the
+// provisioner or hook is left out of the previews.
+func (n *NodeAbstractResourceInstance) planProvisionerPreviews(ctx context.Context, evalCtx EvalContext, change *plans.ResourceInstanceChange, prior *states.ResourceInstanceObject, includeProvisioners bool) ([]plans.ProvisionerPreview, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var createProvs, destroyProvs []*configs.Provisioner
+	var createHookEvent configs.ResourceHookEvent
+	switch {
+	case change.Action == plans.Create || change.Action.IsReplace():
+		createHookEvent = configs.ResourceHookPostCreate
+		if includeProvisioners {
+			createProvs = filterResourceProvisioners(n.Config, n.removedBlockProvisioners, configs.ProvisionerWhenCreate)
+		}
+	case change.Action == plans.Update:
+		createHookEvent = configs.ResourceHookPostUpdate
+	}
+	createHooks := resourceHookProvisioners(n.Config, createHookEvent)
+
+	var destroyHooks []*configs.Provisioner
+	if (change.Action == plans.Delete || change.Action.IsReplace()) && prior != nil && prior.Status != states.ObjectTainted {
+		if includeProvisioners {
+			destroyProvs = filterResourceProvisioners(n.Config, n.removedBlockProvisioners, configs.ProvisionerWhenDestroy)
+		}
+		if change.Action != plans.CreateThenDelete {
+			// An object that is replaced after its replacement is created
+			// is destroyed as a deposed object, which doesn't run hooks.
+			destroyHooks = resourceHookProvisioners(n.Config, configs.ResourceHookPreDestroy)
+		}
+	}
+	if len(createProvs)+len(destroyProvs)+len(createHooks)+len(destroyHooks) == 0 {
+		return nil, diags
+	}
+	log.Printf("[TRACE] planProvisionerPreviews: previewing %d provisioners and %d hooks for %s", len(createProvs)+len(destroyProvs), len(createHooks)+len(destroyHooks), n.Addr)
+
+	var createPreviews, destroyPreviews []plans.ProvisionerPreview
+	for _, prov := range createProvs {
+		preview, moreDiags := n.previewProvisioner(ctx, evalCtx, prov, cty.NilVal, n.evalProvisionerConfig)
+		diags = diags.Append(moreDiags)
+		if preview != nil {
+			createPreviews = append(createPreviews, *preview)
+		}
+	}
+	for _, prov := range createHooks {
+		preview, moreDiags := n.previewProvisioner(ctx, evalCtx, prov, cty.NilVal, n.evalProvisionerConfig)
+		diags = diags.Append(moreDiags)
+		if preview != nil {
+			preview.Hook = string(createHookEvent)
+			createPreviews = append(createPreviews, *preview)
+		}
+	}
+	for _, prov := range destroyHooks {
+		preview, moreDiags := n.previewProvisioner(ctx, evalCtx, prov, prior.Value, n.evalDestroyProvisionerConfig)
+		diags = diags.Append(moreDiags)
+		if preview != nil {
+			preview.Hook = string(configs.ResourceHookPreDestroy)
+			destroyPreviews = append(destroyPreviews, *preview)
+		}
+	}
+	for _, prov := range destroyProvs {
+		preview, moreDiags := n.previewProvisioner(ctx, evalCtx, prov, prior.Value, n.evalDestroyProvisionerConfig)
+		diags = diags.Append(moreDiags)
+		if preview != nil {
+			destroyPreviews = append(destroyPreviews, *preview)
+		}
+	}
+
+	// The previews are in the order the provisioners will run in, which
+	// depends on whether the new object is created before the current one is
+	// destroyed.
+	if change.Action == plans.CreateThenDelete {
+		return append(createPreviews, destroyPreviews...), diags
+	}
+	return append(destroyPreviews, createPreviews...), diags
+}
+
+func (n *NodeAbstractResourceInstance) previewProvisioner(
+	ctx context.Context,
+	evalCtx EvalContext,
+	prov *configs.Provisioner,
+	self cty.Value,
+	evalScope func(context.Context, EvalContext, hcl.Body, cty.Value, *configschema.Block) (cty.Value, tfdiags.Diagnostics),
+) (*plans.ProvisionerPreview, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	schema, err := evalCtx.ProvisionerSchema(prov.Type)
+	if err != nil {
+		return nil, diags.Append(err)
+	}
+
+	config, evalDiags := evalScope(ctx, evalCtx, prov.Config, self, schema)
+	connection := cty.NullVal(shared.ConnectionBlockSupersetSchema.ImpliedType())
+	if connBody := n.provisionerConnectionBody(prov); connBody != nil && !evalDiags.HasErrors() {
+		var connDiags tfdiags.Diagnostics
+		connection, connDiags = evalScope(ctx, evalCtx, connBody, self, shared.ConnectionBlockSupersetSchema)
+		evalDiags = evalDiags.Append(connDiags)
+	}
+	if evalDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Cannot preview provisioner",
+			fmt.Sprintf("OpenTofu can't show what the %q provisioner of %s will run, because its configuration is invalid:\n\n%s", prov.Type, n.Addr, evalDiags.Err()),
+		))
+		return nil, diags
+	}
+
+	return &plans.ProvisionerPreview{
+		Type:       prov.Type,
+		Destroy:    prov.When == configs.ProvisionerWhenDestroy,
+		Config:     config,
+		Connection: previewConnection(connection),
+	}, diags
+}
+
+// previewConnection returns the subset of the given evaluated connection
+// configuration that identifies the remote host, or a null value if there is
+// no connection configuration.
+func previewConnection(connection cty.Value) cty.Value {
+	attrTypes := make(map[string]cty.Type, len(provisionerPreviewConnectionAttrs))
+	for _, name := range provisionerPreviewConnectionAttrs {
+		attrTypes[name] = shared.ConnectionBlockSupersetSchema.Attributes[name].ImpliedType()
+	}
+	if connection.IsNull() {
+		return cty.NullVal(cty.Object(attrTypes))
+	}
+
+	connection, valMarks := connection.Unmark()
+	attrs := make(map[string]cty.Value, len(provisionerPreviewConnectionAttrs))
+	for _, name := range provisionerPreviewConnectionAttrs {
+		attrs[name] = connection.GetAttr(name)
+	}
+	return cty.ObjectVal(attrs).WithMarks(valMarks)
+}
+
+// writeProvisionerPreviews records previews of the lifecycle hooks, and of the
+// provisioners if they were requested, that will run when the given planned
+// change is applied, by replacing the change that was already written with
+// one that includes them.
+//
+// This must be called after the planned state has been written, because the
+// creation-time provisioners can refer to the planned new object.
+func (n *NodePlannableResourceInstance) writeProvisionerPreviews(ctx context.Context, evalCtx EvalContext, change *plans.ResourceInstanceChange, prior *states.ResourceInstanceObject) tfdiags.Diagnostics {
+	previews, diags := n.planProvisionerPreviews(ctx, evalCtx, change, prior, n.previewProvisioners)
+	if len(previews) == 0 {
+		return diags
+	}
+
+	change.Provisioners = previews
+	diags = diags.Append(n.writeChange(ctx, evalCtx, nil, ""))
+	return diags.Append(n.writeChange(ctx, evalCtx, change, ""))
+}
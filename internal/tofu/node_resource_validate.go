@@ -92,6 +92,14 @@ func (n *NodeValidatableResource) Execute(ctx context.Context, evalCtx EvalConte
 				return diags
 			}
 		}
+
+		// Lifecycle hooks are validated as the provisioners that run them
+		for _, h := range managed.Hooks {
+			diags = diags.Append(n.validateProvisioner(ctx, evalCtx, h.Provisioner()))
+			if diags.HasErrors() {
+				return diags
+			}
+		}
 	}
 	importDiags := n.validateImportIDs(ctx, evalCtx)
 	diags = diags.Append(importDiags)
@@ -153,6 +161,20 @@ func (n *NodeValidatableResource) validateProvisioner(ctx context.Context, evalC
 	return diags
 }
 
+// staticIgnoreChangesTraversal returns a copy of the given ignore_changes
+// traversal with each splat step replaced by an index step for the first
+// element, so that it can be validated against the schema.
+func staticIgnoreChangesTraversal(traversal hcl.Traversal) hcl.Traversal {
+	ret := make(hcl.Traversal, len(traversal))
+	for i, step := range traversal {
+		if splat, ok := step.(hcl.TraverseSplat); ok {
+			step = hcl.TraverseIndex{Key: cty.Zero, SrcRange: splat.SrcRange}
+		}
+		ret[i] = step
+	}
+	return ret
+}
+
 func (n *NodeValidatableResource) evaluateBlock(ctx context.Context, evalCtx EvalContext, body hcl.Body, schema *configschema.Block) (cty.Value, hcl.Body, tfdiags.Diagnostics) {
 	keyData, selfAddr := n.stubRepetitionData(n.Config.Count != nil, n.Config.ForEach != nil)
 
@@ -193,6 +215,12 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 		// Evaluate the for_each expression here so we can expose the diagnostics
 		forEachDiags := validateForEach(ctx, evalCtx, n.Config.ForEach)
 		diags = diags.Append(forEachDiags)
+
+	case n.Config.Enabled != nil:
+		// An unknown value is fine here, but we'll check more thoroughly
+		// during the plan walk.
+		_, enabledDiags := evaluateEnabledExpressionValue(ctx, n.Config.Enabled, evalCtx)
+		diags = diags.Append(enabledDiags)
 	}
 
 	diags = diags.Append(validateDependsOn(ctx, evalCtx, n.Config.DependsOn))
@@ -265,9 +293,17 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateEphemeralResourceConfig(schema, configVal, n.Config.Config, n.Addr.String()))
+		if diags.HasErrors() {
+			return diags
+		}
 
 		if n.Config.Managed != nil { // can be nil only in tests with poorly-configured mocks
 			for _, traversal := range n.Config.Managed.IgnoreChanges {
+				// Splat steps match every element, so for validation they
+				// stand for a single element index.
+				traversal = staticIgnoreChangesTraversal(traversal)
+
 				// validate the ignore_changes traversals apply.
 				moreDiags := schema.StaticValidateTraversal(traversal)
 				diags = diags.Append(moreDiags)
@@ -299,6 +335,10 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 			}
 		}
 
+		for _, traversal := range n.Config.MaskInOutput {
+			diags = diags.Append(schema.StaticValidateTraversal(traversal))
+		}
+
 		// Use unmarked value for validate request
 		unmarkedConfigVal, _ := configVal.UnmarkDeep()
 		req := providers.ValidateResourceConfigRequest{
@@ -339,6 +379,14 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateEphemeralResourceConfig(schema, configVal, n.Config.Config, n.Addr.String()))
+		if diags.HasErrors() {
+			return diags
+		}
+
+		for _, traversal := range n.Config.MaskInOutput {
+			diags = diags.Append(schema.StaticValidateTraversal(traversal))
+		}
 
 		// Use unmarked value for validate request
 		unmarkedConfigVal, _ := configVal.UnmarkDeep()
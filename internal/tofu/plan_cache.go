@@ -0,0 +1,208 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+)
+
+// PlanCache records the inputs of the resource instances that had no changes
+// in an earlier plan, so that an incremental plan can skip asking the
+// provider to plan those instances again when their inputs haven't changed.
+//
+// The inputs of a resource instance are its configuration after
+// ignore_changes is applied, its prior state (after refreshing, unless
+// refreshing is disabled), its provider configuration address, the
+// configuration that the provider instance was configured with, the
+// installed package of its provider, and the schema of its resource type.
+// A PlanCache is safe for concurrent use.
+type PlanCache struct {
+	mu    sync.Mutex
+	prior map[string]PlanCacheEntry
+	next  map[string]PlanCacheEntry
+	hits  int
+
+	// providers identifies the installed package of each provider, by its
+	// address, or is nil if the packages aren't part of the inputs.
+	providers map[addrs.Provider]string
+
+	// schemaHashes caches the hash of each resource type schema, since
+	// the same schema is typically used by many resource instances.
+	schemaHashes map[*configschema.Block]string
+}
+
+// PlanCacheEntry describes the inputs of a resource instance that the
+// provider planned no changes for.
+type PlanCacheEntry struct {
+	// Fingerprint is a hash of the inputs of the resource instance.
+	Fingerprint string
+
+	// LegacyTypeSystem is the LegacyTypeSystem flag of the provider's
+	// response, which OpenTofu uses to decide which checks to apply to the
+	// planned value.
+	LegacyTypeSystem bool
+}
+
+// NewPlanCache returns a PlanCache that uses the given entries, by resource
+// instance address, from an earlier plan. The prior entries can be nil to
+// start an empty cache.
+//
+// The given providers identify the installed package of each provider, such
+// as its locked version and checksums, so that the entries for a provider's
+// resource instances no longer match once a different package is installed.
+// The resource instances of providers that aren't in the map are always
+// planned, unless the map is nil.
+func NewPlanCache(prior map[string]PlanCacheEntry, providers map[addrs.Provider]string) *PlanCache {
+	return &PlanCache{
+		prior:        prior,
+		next:         make(map[string]PlanCacheEntry),
+		providers:    providers,
+		schemaHashes: make(map[*configschema.Block]string),
+	}
+}
+
+// Entries returns the entries recorded by the plan that used the receiver,
+// by resource instance address, which can be given to NewPlanCache for the
+// next incremental plan.
+func (c *PlanCache) Entries() map[string]PlanCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make(map[string]PlanCacheEntry, len(c.next))
+	for k, v := range c.next {
+		ret[k] = v
+	}
+	return ret
+}
+
+// Hits returns the number of resource instances whose planning was skipped
+// because their inputs matched an entry from the earlier plan.
+func (c *PlanCache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// lookupFingerprint returns the entry for the given resource instance from
+// the earlier plan if it has the given fingerprint. The receiver can be nil,
+// and the fingerprint can be empty if there is none, in which case there is
+// never a matching entry.
+func (c *PlanCache) lookupFingerprint(addr addrs.AbsResourceInstance, fingerprint string) (PlanCacheEntry, bool) {
+	if c == nil || fingerprint == "" {
+		return PlanCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.prior[addr.String()]
+	if !ok || entry.Fingerprint != fingerprint {
+		return PlanCacheEntry{}, false
+	}
+	c.hits++
+	return entry, true
+}
+
+func (c *PlanCache) record(addr addrs.AbsResourceInstance, entry PlanCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next[addr.String()] = entry
+}
+
+func (c *PlanCache) schemaHash(schema *configschema.Block) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hash, ok := c.schemaHashes[schema]; ok {
+		return hash, nil
+	}
+	src, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(src)
+	hash := hex.EncodeToString(sum[:])
+	c.schemaHashes[schema] = hash
+	return hash, nil
+}
+
+// fingerprint returns a hash of the inputs of a PlanResourceChange request
+// to the given provider instance, which was configured with providerConfig,
+// or false if the inputs cannot be fingerprinted because they are not
+// wholly known or the provider's package isn't known.
+func (c *PlanCache) fingerprint(provider addrs.AbsProviderConfig, providerKey addrs.InstanceKey, providerConfig cty.Value, typeName string, schemaVersion uint64, schema *configschema.Block, config, prior, meta cty.Value, priorPrivate []byte) (string, bool) {
+	if !config.IsWhollyKnown() || !prior.IsWhollyKnown() || (meta != cty.NilVal && !meta.IsWhollyKnown()) {
+		return "", false
+	}
+	if providerConfig == cty.NilVal || !providerConfig.IsWhollyKnown() {
+		return "", false
+	}
+	var providerPackage string
+	if c.providers != nil {
+		var ok bool
+		providerPackage, ok = c.providers[provider.Provider]
+		if !ok {
+			return "", false
+		}
+	}
+	// The provider configuration can be sensitive, but only its hash is
+	// recorded.
+	unmarkedProviderConfig, _ := providerConfig.UnmarkDeep()
+	providerConfigJSON, err := ctyjson.Marshal(unmarkedProviderConfig, unmarkedProviderConfig.Type())
+	if err != nil {
+		return "", false
+	}
+
+	schemaHash, err := c.schemaHash(schema)
+	if err != nil {
+		return "", false
+	}
+	ty := schema.ImpliedType()
+	configJSON, err := ctyjson.Marshal(config, ty)
+	if err != nil {
+		return "", false
+	}
+	priorJSON, err := ctyjson.Marshal(prior, ty)
+	if err != nil {
+		return "", false
+	}
+	var metaJSON []byte
+	if meta != cty.NilVal {
+		metaJSON, err = ctyjson.Marshal(meta, meta.Type())
+		if err != nil {
+			return "", false
+		}
+	}
+
+	h := sha256.New()
+	// Each part is prefixed with its length so that the boundaries
+	// between them are unambiguous.
+	for _, part := range [][]byte{
+		[]byte(provider.InstanceString(providerKey)),
+		[]byte(providerPackage),
+		providerConfigJSON,
+		[]byte(typeName),
+		[]byte(fmt.Sprint(schemaVersion)),
+		[]byte(schemaHash),
+		configJSON,
+		priorJSON,
+		metaJSON,
+		priorPrivate,
+	} {
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
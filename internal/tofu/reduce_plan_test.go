@@ -8,8 +8,10 @@ package tofu
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty-debug/ctydebug"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -404,6 +406,72 @@ func TestProcessIgnoreChangesIndividual(t *testing.T) {
 				}),
 			}),
 		},
+		"map key pattern": {
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"Name":                        cty.StringVal("web"),
+					"kubernetes.io/cluster/main":  cty.StringVal("owned"),
+					"kubernetes.io/role/elb":      cty.StringVal("1"),
+					"kubernetes.io-not-a-k8s-tag": cty.StringVal("old"),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"Name":                        cty.StringVal("web-new"),
+					"kubernetes.io/role/elb":      cty.StringVal("0"),
+					"kubernetes.io/added":         cty.StringVal("new"),
+					"kubernetes.io-not-a-k8s-tag": cty.StringVal("new"),
+				}),
+			}),
+			[]string{`tags["kubernetes.io/*"]`},
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"Name":                        cty.StringVal("web-new"),
+					"kubernetes.io/cluster/main":  cty.StringVal("owned"),
+					"kubernetes.io/role/elb":      cty.StringVal("1"),
+					"kubernetes.io-not-a-k8s-tag": cty.StringVal("new"),
+				}),
+			}),
+		},
+		"map key pattern in the middle": {
+			cty.ObjectVal(map[string]cty.Value{
+				"settings": cty.MapVal(map[string]cty.Value{
+					"app-a": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("1"),
+						"size":    cty.StringVal("small"),
+					}),
+					"db": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("1"),
+						"size":    cty.StringVal("small"),
+					}),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"settings": cty.MapVal(map[string]cty.Value{
+					"app-a": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("2"),
+						"size":    cty.StringVal("large"),
+					}),
+					"db": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("2"),
+						"size":    cty.StringVal("large"),
+					}),
+				}),
+			}),
+			[]string{`settings["app-*"].version`},
+			cty.ObjectVal(map[string]cty.Value{
+				"settings": cty.MapVal(map[string]cty.Value{
+					"app-a": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("1"),
+						"size":    cty.StringVal("large"),
+					}),
+					"db": cty.ObjectVal(map[string]cty.Value{
+						"version": cty.StringVal("2"),
+						"size":    cty.StringVal("large"),
+					}),
+				}),
+			}),
+		},
 		"marked_map": {
 			cty.ObjectVal(map[string]cty.Value{
 				"map": cty.MapVal(map[string]cty.Value{
@@ -435,7 +503,11 @@ func TestProcessIgnoreChangesIndividual(t *testing.T) {
 				ignore[i] = trav
 			}
 
-			ret, diags := processIgnoreChangesIndividual(test.Old, test.New, traversalsToPaths(ignore))
+			var paths []cty.Path
+			for _, trav := range ignore {
+				paths = append(paths, expandIgnoreChangesTraversal(trav, test.Old, test.New)...)
+			}
+			ret, diags := processIgnoreChangesIndividual(test.Old, test.New, paths)
 			if diags.HasErrors() {
 				t.Fatal(diags.Err())
 			}
@@ -446,3 +518,77 @@ func TestProcessIgnoreChangesIndividual(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandIgnoreChangesTraversal(t *testing.T) {
+	rule := func(port int64, description string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"port":        cty.NumberIntVal(port),
+			"description": cty.StringVal(description),
+		})
+	}
+	prior := cty.ObjectVal(map[string]cty.Value{
+		"rule": cty.ListVal([]cty.Value{rule(80, "http"), rule(443, "https")}),
+		"labels": cty.MapVal(map[string]cty.Value{
+			"a": cty.StringVal("x"),
+		}),
+	})
+	config := cty.ObjectVal(map[string]cty.Value{
+		"rule": cty.ListVal([]cty.Value{rule(8080, "changed"), rule(443, "changed"), rule(22, "ssh")}),
+		"labels": cty.MapVal(map[string]cty.Value{
+			"b": cty.StringVal("y"),
+		}),
+	})
+
+	tests := map[string]struct {
+		traversal hcl.Traversal
+		want      []cty.Path
+	}{
+		"list splat": {
+			hcl.Traversal{hcl.TraverseRoot{Name: "rule"}, hcl.TraverseSplat{}, hcl.TraverseAttr{Name: "description"}},
+			[]cty.Path{
+				cty.GetAttrPath("rule").IndexInt(0).GetAttr("description"),
+				cty.GetAttrPath("rule").IndexInt(1).GetAttr("description"),
+				cty.GetAttrPath("rule").IndexInt(2).GetAttr("description"),
+			},
+		},
+		"map splat": {
+			hcl.Traversal{hcl.TraverseRoot{Name: "labels"}, hcl.TraverseSplat{}},
+			[]cty.Path{
+				cty.GetAttrPath("labels").IndexString("a"),
+				cty.GetAttrPath("labels").IndexString("b"),
+			},
+		},
+		"no wildcards": {
+			hcl.Traversal{hcl.TraverseRoot{Name: "rule"}, hcl.TraverseIndex{Key: cty.NumberIntVal(1)}, hcl.TraverseAttr{Name: "port"}},
+			[]cty.Path{
+				cty.GetAttrPath("rule").IndexInt(1).GetAttr("port"),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := expandIgnoreChangesTraversal(test.traversal, prior, config)
+			if diff := cmp.Diff(test.want, got, ctydebug.CmpOptions); diff != "" {
+				t.Errorf("wrong paths\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		paths := expandIgnoreChangesTraversal(tests["list splat"].traversal, prior, config)
+		got, diags := processIgnoreChangesIndividual(prior, config, paths)
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		want := cty.ObjectVal(map[string]cty.Value{
+			"rule": cty.ListVal([]cty.Value{rule(8080, "http"), rule(443, "https"), rule(22, "ssh")}),
+			"labels": cty.MapVal(map[string]cty.Value{
+				"b": cty.StringVal("y"),
+			}),
+		})
+		if !got.RawEquals(want) {
+			t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+}
@@ -165,6 +165,9 @@ func loadProvisionerSchemas(ctx context.Context, schemas map[string]*configschem
 			for _, pc := range rc.Managed.Provisioners {
 				ensure(pc.Type)
 			}
+			if len(rc.Managed.Hooks) != 0 {
+				ensure(configs.ResourceHookProvisioner)
+			}
 		}
 
 		// Must also visit our child modules, recursively.
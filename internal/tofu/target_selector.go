@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TargetSelector selects resource instances to target based on the attribute
+// values recorded for them in the state, rather than by their addresses.
+//
+// The expression is evaluated separately for each managed resource instance,
+// with the top-level attributes of its current object available as
+// variables, and selects the instance if the result is true.
+type TargetSelector struct {
+	Expr hcl.Expression
+
+	// Source is the selector as written by the user, for use in messages.
+	Source string
+}
+
+// ParseTargetSelector parses the given source code as a target selector
+// expression.
+func ParseTargetSelector(src string) (TargetSelector, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	expr, hclDiags := hclsyntax.ParseExpression([]byte(src), "-target-selector", hcl.Pos{Line: 1, Column: 1})
+	if hclDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			fmt.Sprintf("Invalid target selector %q", src),
+			hclDiags[0].Detail,
+		))
+		return TargetSelector{}, diags
+	}
+	return TargetSelector{Expr: expr, Source: src}, diags
+}
+
+// SelectTargets returns the addresses of the managed resource instances in
+// the given state that are selected by any of the given selectors, to be used
+// as targets for an operation.
+//
+// Each selector must select at least one resource instance, because an
+// operation with no targets at all would otherwise act on everything.
+func SelectTargets(state *states.State, selectors []TargetSelector) ([]addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if len(selectors) == 0 {
+		return nil, diags
+	}
+
+	// We visit the instances in a consistent order so that the evaluation
+	// error we report for a selector doesn't vary between runs.
+	var candidates []addrs.AbsResourceInstance
+	objects := make(map[string]*states.ResourceInstanceObjectSrc)
+	if state != nil {
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+					continue
+				}
+				for key, is := range rs.Instances {
+					addr := rs.Addr.Instance(key)
+					candidates = append(candidates, addr)
+					objects[addr.String()] = is.Current
+				}
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Less(candidates[j])
+	})
+
+	funcs := (&lang.Scope{PureOnly: true}).Functions()
+	matched := make([]int, len(selectors))
+	evalErrs := make([]error, len(selectors))
+	var selected []addrs.AbsResourceInstance
+	for _, addr := range candidates {
+		vars, ok := targetSelectorVariables(objects[addr.String()])
+		if !ok {
+			continue
+		}
+		ctx := &hcl.EvalContext{
+			Variables: vars,
+			Functions: funcs,
+		}
+		match := false
+		for i, selector := range selectors {
+			ok, err := selector.matches(ctx)
+			if err != nil {
+				if evalErrs[i] == nil {
+					evalErrs[i] = err
+				}
+				continue
+			}
+			if ok {
+				matched[i]++
+				match = true
+			}
+		}
+		if match {
+			selected = append(selected, addr)
+		}
+	}
+
+	ret := make([]addrs.Targetable, len(selected))
+	for i, addr := range selected {
+		ret[i] = addr
+	}
+
+	for i, selector := range selectors {
+		if matched[i] > 0 {
+			continue
+		}
+		detail := fmt.Sprintf("The target selector %q doesn't select any resource instances in the current state.", selector.Source)
+		if evalErrs[i] != nil {
+			// A selector that refers to attributes that only some resource
+			// types have can't be evaluated for the others, which is fine,
+			// but if nothing matched then a typing mistake is more likely.
+			detail += fmt.Sprintf(" OpenTofu could not evaluate it for some resource instances: %s.", evalErrs[i])
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No resource instances selected",
+			detail,
+		))
+	}
+	return ret, diags
+}
+
+// matches evaluates the selector in the given context, returning an error if
+// the result isn't a known bool.
+func (s TargetSelector) matches(ctx *hcl.EvalContext) (bool, error) {
+	v, hclDiags := s.Expr.Value(ctx)
+	if hclDiags.HasErrors() {
+		return false, hclDiags
+	}
+	v, err := convert.Convert(v, cty.Bool)
+	if err != nil {
+		return false, fmt.Errorf("the result must be a bool: %w", err)
+	}
+	if v.IsNull() || !v.IsWhollyKnown() {
+		return false, nil
+	}
+	v, _ = v.Unmark()
+	return v.True(), nil
+}
+
+// targetSelectorVariables decodes the attributes of the given object for use
+// as variables when evaluating target selectors, returning false if there are
+// none.
+//
+// We don't have provider schemas when selecting targets, so the types of the
+// attributes are inferred from their JSON encoding.
+func targetSelectorVariables(obj *states.ResourceInstanceObjectSrc) (map[string]cty.Value, bool) {
+	if obj == nil || len(obj.AttrsJSON) == 0 {
+		return nil, false
+	}
+	ty, err := ctyjson.ImpliedType(obj.AttrsJSON)
+	if err != nil || !ty.IsObjectType() {
+		return nil, false
+	}
+	v, err := ctyjson.Unmarshal(obj.AttrsJSON, ty)
+	if err != nil || v.IsNull() {
+		return nil, false
+	}
+	return v.AsValueMap(), true
+}
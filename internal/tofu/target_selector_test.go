@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestSelectTargets(t *testing.T) {
+	provider := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	state := states.BuildState(func(s *states.SyncState) {
+		object := func(attrs string) *states.ResourceInstanceObjectSrc {
+			return &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			}
+		}
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.a"), object(`{"id":"a","tags":{"team":"payments"},"size":1}`), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_instance.b"), object(`{"id":"b","tags":{"team":"search"},"size":3}`), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("module.child.test_instance.c[0]"), object(`{"id":"c","tags":{"team":"payments"},"size":5}`), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_thing.d"), object(`{"id":"d"}`), provider, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("data.test_data_source.e"), object(`{"id":"e","tags":{"team":"payments"}}`), provider, addrs.NoKey)
+	})
+
+	testCases := map[string]struct {
+		selectors []string
+		want      []string
+		wantErr   string
+	}{
+		"map attribute": {
+			selectors: []string{`tags["team"] == "payments"`},
+			want:      []string{"test_instance.a", "module.child.test_instance.c[0]"},
+		},
+		"functions": {
+			selectors: []string{`startswith(id, "b")`},
+			want:      []string{"test_instance.b"},
+		},
+		"any of several selectors": {
+			selectors: []string{`size > 4`, `id == "d"`},
+			want:      []string{"test_thing.d", "module.child.test_instance.c[0]"},
+		},
+		"nothing selected": {
+			selectors: []string{`tags["team"] == "payments"`, `id == "z"`},
+			wantErr:   `The target selector "id == \"z\"" doesn't select any resource instances`,
+		},
+		"unknown attribute": {
+			selectors: []string{`tag["team"] == "payments"`},
+			wantErr:   `There is no variable named "tag"`,
+		},
+		"not a bool": {
+			selectors: []string{`tags`},
+			wantErr:   "the result must be a bool",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var selectors []TargetSelector
+			for _, src := range tc.selectors {
+				selector, diags := ParseTargetSelector(src)
+				assertNoErrors(t, diags)
+				selectors = append(selectors, selector)
+			}
+
+			targets, diags := SelectTargets(state, selectors)
+			if tc.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("expected error, but got none")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+
+			var got []string
+			for _, target := range targets {
+				got = append(got, target.String())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong targets\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+output "instance_id" {
+  value = "i-abc123"
+}
@@ -30,3 +30,12 @@ check "foo" {
     error_message = "check failed"
   }
 }
+
+module "child" {
+  source = "./child"
+}
+
+module "children" {
+  source   = "./child"
+  for_each = toset(["a"])
+}